@@ -35,6 +35,7 @@ export const IdentityContextModel = z.object({
   displayName: z.string().min(1, 'Display name is required'),
   currentPlatform: z.string().optional(),
   conversationType: z.string().optional(),
+  conversationId: z.string().optional(),
   sessionToken: z.string().optional(),
 })
 
@@ -23,11 +23,16 @@ import (
 	"github.com/memohai/memoh/internal/bind"
 	"github.com/memohai/memoh/internal/boot"
 	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/bundle"
 	"github.com/memohai/memoh/internal/channel"
 	"github.com/memohai/memoh/internal/channel/adapters/discord"
+	"github.com/memohai/memoh/internal/channel/adapters/email"
 	"github.com/memohai/memoh/internal/channel/adapters/feishu"
 	"github.com/memohai/memoh/internal/channel/adapters/local"
+	"github.com/memohai/memoh/internal/channel/adapters/matrix"
+	"github.com/memohai/memoh/internal/channel/adapters/slack"
 	"github.com/memohai/memoh/internal/channel/adapters/telegram"
+	"github.com/memohai/memoh/internal/channel/adapters/webhook"
 	"github.com/memohai/memoh/internal/channel/identities"
 	"github.com/memohai/memoh/internal/channel/inbound"
 	"github.com/memohai/memoh/internal/channel/route"
@@ -38,15 +43,18 @@ import (
 	"github.com/memohai/memoh/internal/db"
 	dbsqlc "github.com/memohai/memoh/internal/db/sqlc"
 	"github.com/memohai/memoh/internal/embeddings"
+	"github.com/memohai/memoh/internal/filewatch"
 	"github.com/memohai/memoh/internal/handlers"
 	"github.com/memohai/memoh/internal/healthcheck"
 	channelchecker "github.com/memohai/memoh/internal/healthcheck/checkers/channel"
+	containerchecker "github.com/memohai/memoh/internal/healthcheck/checkers/container"
 	mcpchecker "github.com/memohai/memoh/internal/healthcheck/checkers/mcp"
 	"github.com/memohai/memoh/internal/inbox"
 	"github.com/memohai/memoh/internal/logger"
 	"github.com/memohai/memoh/internal/mcp"
 	mcpcontacts "github.com/memohai/memoh/internal/mcp/providers/contacts"
 	mcpcontainer "github.com/memohai/memoh/internal/mcp/providers/container"
+	mcpfilewatch "github.com/memohai/memoh/internal/mcp/providers/filewatch"
 	mcpinbox "github.com/memohai/memoh/internal/mcp/providers/inbox"
 	mcpmemory "github.com/memohai/memoh/internal/mcp/providers/memory"
 	mcpmessage "github.com/memohai/memoh/internal/mcp/providers/message"
@@ -65,7 +73,7 @@ import (
 	"github.com/memohai/memoh/internal/searchproviders"
 	"github.com/memohai/memoh/internal/server"
 	"github.com/memohai/memoh/internal/settings"
-	"github.com/memohai/memoh/internal/storage/providers/containerfs"
+	"github.com/memohai/memoh/internal/storageproviders"
 	"github.com/memohai/memoh/internal/subagent"
 	"github.com/memohai/memoh/internal/version"
 )
@@ -142,8 +150,9 @@ func runServe() {
 			provideEmbeddingsResolver,
 			provideEmbeddingSetup,
 			provideTextEmbedderForMemory,
-			provideQdrantStore,
+			provideVectorStore,
 			memory.NewBM25Indexer,
+			provideMemoryFS,
 			provideMemoryService,
 
 			// domain services (auto-wired)
@@ -166,6 +175,7 @@ func runServe() {
 			// services requiring provide functions
 			provideRouteService,
 			provideMessageService,
+			provideStorageProvidersService,
 			provideMediaService,
 
 			// channel infrastructure
@@ -180,6 +190,8 @@ func runServe() {
 			provideChatResolver,
 			provideScheduleTriggerer,
 			schedule.NewService,
+			provideFileWatchService,
+			bundle.NewService,
 
 			// containerd handler & tool gateway
 			provideContainerdHandler,
@@ -194,14 +206,20 @@ func runServe() {
 			provideServerHandler(handlers.NewSwaggerHandler),
 			provideServerHandler(handlers.NewProvidersHandler),
 			provideServerHandler(handlers.NewSearchProvidersHandler),
+			provideServerHandler(handlers.NewStorageProvidersHandler),
+			provideServerHandler(handlers.NewImagesHandler),
 			provideServerHandler(handlers.NewModelsHandler),
 			provideServerHandler(handlers.NewSettingsHandler),
 			provideServerHandler(handlers.NewPreauthHandler),
 			provideServerHandler(handlers.NewBindHandler),
 			provideServerHandler(handlers.NewScheduleHandler),
+			provideServerHandler(handlers.NewFileWatchHandler),
 			provideServerHandler(handlers.NewSubagentHandler),
+			provideServerHandler(handlers.NewBundleHandler),
+			provideServerHandler(handlers.NewTemplatesHandler),
 			provideServerHandler(handlers.NewChannelHandler),
 			provideServerHandler(feishu.NewWebhookServerHandler),
+			provideServerHandler(webhook.NewServerHandler),
 			provideServerHandler(provideUsersHandler),
 			provideServerHandler(handlers.NewMCPHandler),
 			provideServerHandler(handlers.NewInboxHandler),
@@ -212,9 +230,12 @@ func runServe() {
 		),
 		fx.Invoke(
 			startMemoryWarmup,
+			startMemoryExpirySweeper,
 			startScheduleService,
 			startChannelManager,
 			startContainerReconciliation,
+			startIdleSuspension,
+			startFileWatcher,
 			startServer,
 		),
 		fx.WithLogger(func(logger *slog.Logger) fxevent.Logger {
@@ -293,6 +314,14 @@ func provideMCPManager(log *slog.Logger, service ctr.Service, cfg config.Config,
 // memory providers
 // ---------------------------------------------------------------------------
 
+// provideMemoryFS mirrors memories into the bot container; nil without an MCP manager.
+func provideMemoryFS(log *slog.Logger, manager *mcp.Manager) *memory.MemoryFS {
+	if manager == nil {
+		return nil
+	}
+	return memory.NewMemoryFS(log, manager, config.DefaultDataMount)
+}
+
 func provideMemoryLLM(modelsService *models.Service, queries *dbsqlc.Queries, log *slog.Logger) memory.LLM {
 	return &lazyLLMClient{
 		modelsService: modelsService,
@@ -332,8 +361,27 @@ func provideEmbeddingSetup(log *slog.Logger, modelsService *models.Service) (emb
 	}, nil
 }
 
-func provideTextEmbedderForMemory(resolver *embeddings.Resolver, setup embeddingSetup, log *slog.Logger) embeddings.Embedder {
-	return buildTextEmbedder(resolver, setup.TextModel, setup.HasEmbeddingModels, log)
+func provideTextEmbedderForMemory(resolver *embeddings.Resolver, setup embeddingSetup, queries *dbsqlc.Queries, log *slog.Logger) embeddings.Embedder {
+	return buildTextEmbedder(resolver, setup.TextModel, setup.HasEmbeddingModels, queries, log)
+}
+
+func provideVectorStore(log *slog.Logger, cfg config.Config, setup embeddingSetup, conn *pgxpool.Pool) (memory.VectorStore, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Memory.Store)) {
+	case "", "qdrant":
+		return provideQdrantStore(log, cfg, setup)
+	case "pgvector":
+		var vectors map[string]int
+		if setup.HasEmbeddingModels {
+			vectors = setup.Vectors
+		}
+		store, err := memory.NewPgVectorStore(log, conn, "", vectors, "sparse_hash", 10*time.Second)
+		if err != nil {
+			return nil, fmt.Errorf("pgvector init: %w", err)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unsupported memory store: %s", cfg.Memory.Store)
+	}
 }
 
 func provideQdrantStore(log *slog.Logger, cfg config.Config, setup embeddingSetup) (*memory.QdrantStore, error) {
@@ -353,8 +401,24 @@ func provideQdrantStore(log *slog.Logger, cfg config.Config, setup embeddingSetu
 	return store, nil
 }
 
-func provideMemoryService(log *slog.Logger, llm memory.LLM, embedder embeddings.Embedder, store *memory.QdrantStore, resolver *embeddings.Resolver, bm25 *memory.BM25Indexer, setup embeddingSetup) *memory.Service {
-	return memory.NewService(log, llm, embedder, store, resolver, bm25, setup.TextModel.ModelID, setup.MultimodalModel.ModelID)
+func provideMemoryService(log *slog.Logger, llm memory.LLM, embedder embeddings.Embedder, store memory.VectorStore, resolver *embeddings.Resolver, bm25 *memory.BM25Indexer, setup embeddingSetup, queries *dbsqlc.Queries, modelsService *models.Service, settingsService *settings.Service, memoryFS *memory.MemoryFS) *memory.Service {
+	svc := memory.NewService(log, llm, embedder, store, resolver, bm25, setup.TextModel.ModelID, setup.MultimodalModel.ModelID)
+	if cached, ok := embedder.(*embeddings.CachedEmbedder); ok {
+		svc.SetQueryEmbedder(cached.Uncached())
+	}
+	svc.SetRevisionStore(memory.NewPgRevisionStore(queries))
+	if memoryFS != nil {
+		svc.SetMemoryFS(memoryFS)
+	}
+	svc.SetRerankResolver(&rerankResolver{
+		modelsService:   modelsService,
+		settingsService: settingsService,
+		queries:         queries,
+		timeout:         10 * time.Second,
+		logger:          log,
+	})
+	svc.SetScopePolicyResolver(&memoryScopeResolver{settingsService: settingsService})
+	return svc
 }
 
 // ---------------------------------------------------------------------------
@@ -373,6 +437,10 @@ func provideScheduleTriggerer(resolver *flow.Resolver) schedule.Triggerer {
 	return flow.NewScheduleGateway(resolver)
 }
 
+func provideFileWatchService(log *slog.Logger, queries *dbsqlc.Queries, manager *mcp.Manager, inboxService *inbox.Service, resolver *flow.Resolver, rc *boot.RuntimeConfig) *filewatch.Service {
+	return filewatch.NewService(log, queries, manager, inboxService, flow.NewFileWatchGateway(resolver), rc)
+}
+
 // ---------------------------------------------------------------------------
 // conversation flow
 // ---------------------------------------------------------------------------
@@ -398,6 +466,16 @@ func provideChannelRegistry(log *slog.Logger, hub *local.RouteHub, mediaService
 	feishuAdapter := feishu.NewFeishuAdapter(log)
 	feishuAdapter.SetAssetOpener(mediaService)
 	registry.MustRegister(feishuAdapter)
+	registry.MustRegister(slack.NewSlackAdapter(log))
+	matrixAdapter := matrix.NewMatrixAdapter(log)
+	matrixAdapter.SetAssetOpener(mediaService)
+	registry.MustRegister(matrixAdapter)
+	emailAdapter := email.NewEmailAdapter(log)
+	emailAdapter.SetAssetOpener(mediaService)
+	registry.MustRegister(emailAdapter)
+	webhookAdapter := webhook.NewWebhookAdapter(log)
+	webhookAdapter.SetAssetOpener(mediaService)
+	registry.MustRegister(webhookAdapter)
 	registry.MustRegister(local.NewCLIAdapter(hub))
 	registry.MustRegister(local.NewWebAdapter(hub))
 	return registry
@@ -418,11 +496,13 @@ func provideChannelRouter(
 	mediaService *media.Service,
 	inboxService *inbox.Service,
 	rc *boot.RuntimeConfig,
+	manager *mcp.Manager,
 ) *inbound.ChannelInboundProcessor {
 	processor := inbound.NewChannelInboundProcessor(log, registry, routeService, msgService, resolver, identityService, botService, policyService, preauthService, bindService, rc.JwtSecret, 5*time.Minute)
 	processor.SetMediaService(mediaService)
 	processor.SetStreamObserver(local.NewRouteHubBroadcaster(hub))
 	processor.SetInboxService(inboxService)
+	processor.SetActivityRecorder(manager)
 	return processor
 }
 
@@ -446,7 +526,7 @@ func provideContainerdHandler(log *slog.Logger, service ctr.Service, manager *mc
 	return handlers.NewContainerdHandler(log, service, manager, cfg.MCP, cfg.Containerd.Namespace, rc.ContainerBackend, botService, accountService, policyService, queries)
 }
 
-func provideToolGatewayService(log *slog.Logger, cfg config.Config, channelManager *channel.Manager, registry *channel.Registry, routeService *route.DBService, scheduleService *schedule.Service, memoryService *memory.Service, chatService *conversation.Service, accountService *accounts.Service, settingsService *settings.Service, searchProviderService *searchproviders.Service, manager *mcp.Manager, containerdHandler *handlers.ContainerdHandler, mcpConnService *mcp.ConnectionService, mediaService *media.Service, inboxService *inbox.Service) *mcp.ToolGatewayService {
+func provideToolGatewayService(log *slog.Logger, cfg config.Config, channelManager *channel.Manager, registry *channel.Registry, routeService *route.DBService, scheduleService *schedule.Service, memoryService *memory.Service, chatService *conversation.Service, accountService *accounts.Service, settingsService *settings.Service, searchProviderService *searchproviders.Service, manager *mcp.Manager, containerdHandler *handlers.ContainerdHandler, mcpConnService *mcp.ConnectionService, mediaService *media.Service, inboxService *inbox.Service, fileWatchService *filewatch.Service) *mcp.ToolGatewayService {
 	var assetResolver mcpmessage.AssetResolver
 	if mediaService != nil {
 		assetResolver = &mediaAssetResolverAdapter{media: mediaService}
@@ -457,16 +537,20 @@ func provideToolGatewayService(log *slog.Logger, cfg config.Config, channelManag
 	memoryExec := mcpmemory.NewExecutor(log, memoryService, chatService, accountService)
 	webExec := mcpweb.NewExecutor(log, settingsService, searchProviderService)
 	inboxExec := mcpinbox.NewExecutor(log, inboxService)
+	fileWatchExec := mcpfilewatch.NewExecutor(log, fileWatchService)
 	fsExec := mcpcontainer.NewExecutor(log, manager, config.DefaultDataMount)
+	fsExec.SetQuotaChecker(manager)
 
 	fedGateway := handlers.NewMCPFederationGateway(log, containerdHandler)
 	fedSource := mcpfederation.NewSource(log, fedGateway, mcpConnService)
 
 	svc := mcp.NewToolGatewayService(
 		log,
-		[]mcp.ToolExecutor{messageExec, contactsExec, scheduleExec, memoryExec, webExec, fsExec, inboxExec},
+		[]mcp.ToolExecutor{messageExec, contactsExec, scheduleExec, memoryExec, webExec, fsExec, inboxExec, fileWatchExec},
 		[]mcp.ToolSource{fedSource},
 	)
+	svc.SetContainerWaker(manager)
+	manager.SetBusyChecker(fsExec)
 	containerdHandler.SetToolGatewayService(svc)
 	return svc
 }
@@ -475,11 +559,12 @@ func provideToolGatewayService(log *slog.Logger, cfg config.Config, channelManag
 // handler providers (interface adaptation / config extraction)
 // ---------------------------------------------------------------------------
 
-func provideMemoryHandler(log *slog.Logger, service *memory.Service, chatService *conversation.Service, accountService *accounts.Service, cfg config.Config, manager *mcp.Manager) *handlers.MemoryHandler {
+func provideMemoryHandler(log *slog.Logger, service *memory.Service, chatService *conversation.Service, accountService *accounts.Service, identityService *identities.Service, memoryFS *memory.MemoryFS, scheduleService *schedule.Service) *handlers.MemoryHandler {
 	h := handlers.NewMemoryHandler(log, service, chatService, accountService)
-	if manager != nil {
-		execWorkDir := config.DefaultDataMount
-		h.SetMemoryFS(memory.NewMemoryFS(log, manager, execWorkDir))
+	h.SetIdentityService(identityService)
+	h.SetCompactionRunner(scheduleService)
+	if memoryFS != nil {
+		h.SetMemoryFS(memoryFS)
 	}
 	return h
 }
@@ -494,20 +579,29 @@ func provideMessageHandler(log *slog.Logger, chatService *conversation.Service,
 	return h
 }
 
-func provideMediaService(log *slog.Logger, cfg config.Config) (*media.Service, error) {
+func provideStorageProvidersService(log *slog.Logger, cfg config.Config, queries *dbsqlc.Queries) (*storageproviders.Service, error) {
 	dataRoot := strings.TrimSpace(cfg.MCP.DataRoot)
 	if dataRoot == "" {
 		dataRoot = config.DefaultDataRoot
 	}
-	provider, err := containerfs.New(dataRoot)
+	svc, err := storageproviders.NewService(log, queries, dataRoot)
 	if err != nil {
 		return nil, fmt.Errorf("init media provider: %w", err)
 	}
-	return media.NewService(log, provider), nil
+	return svc, nil
 }
 
-func provideUsersHandler(log *slog.Logger, accountService *accounts.Service, identityService *identities.Service, botService *bots.Service, routeService *route.DBService, channelStore *channel.Store, channelLifecycle *channel.Lifecycle, channelManager *channel.Manager, registry *channel.Registry) *handlers.UsersHandler {
-	return handlers.NewUsersHandler(log, accountService, identityService, botService, routeService, channelStore, channelLifecycle, channelManager, registry)
+func provideMediaService(log *slog.Logger, storageService *storageproviders.Service) *media.Service {
+	svc := media.NewService(log, storageService.DefaultProvider())
+	svc.SetProviderResolver(storageService)
+	storageService.SetAssetCopier(svc)
+	return svc
+}
+
+func provideUsersHandler(log *slog.Logger, accountService *accounts.Service, identityService *identities.Service, botService *bots.Service, routeService *route.DBService, channelStore *channel.Store, channelLifecycle *channel.Lifecycle, channelManager *channel.Manager, registry *channel.Registry, bundleService *bundle.Service) *handlers.UsersHandler {
+	h := handlers.NewUsersHandler(log, accountService, identityService, botService, routeService, channelStore, channelLifecycle, channelManager, registry)
+	h.SetBotTemplates(bundleService)
+	return h
 }
 
 func provideCLIHandler(channelManager *channel.Manager, channelStore *channel.Store, chatService *conversation.Service, hub *local.RouteHub, botService *bots.Service, accountService *accounts.Service) *handlers.LocalChannelHandler {
@@ -556,7 +650,30 @@ func startMemoryWarmup(lc fx.Lifecycle, memoryService *memory.Service, logger *s
 	})
 }
 
-func startScheduleService(lc fx.Lifecycle, scheduleService *schedule.Service) {
+func startMemoryExpirySweeper(lc fx.Lifecycle, memoryService *memory.Service, cfg config.Config) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			memoryService.StartExpirySweeper(ctx, time.Duration(cfg.Memory.ExpirySweepSeconds)*time.Second)
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+func startScheduleService(lc fx.Lifecycle, scheduleService *schedule.Service, memoryService *memory.Service, memoryFS *memory.MemoryFS, settingsService *settings.Service, manager *mcp.Manager) {
+	// A nil *MemoryFS must not become a non-nil CompactionFS.
+	var compactionFS schedule.CompactionFS
+	if memoryFS != nil {
+		compactionFS = memoryFS
+	}
+	scheduleService.SetMemoryCompactor(memoryService, compactionFS)
+	settingsService.SetCompactionScheduler(scheduleService)
+	scheduleService.SetContainerSnapshotter(manager)
+	settingsService.SetSnapshotScheduler(scheduleService)
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			return scheduleService.Bootstrap(ctx)
@@ -578,6 +695,34 @@ func startChannelManager(lc fx.Lifecycle, channelManager *channel.Manager) {
 	})
 }
 
+func startIdleSuspension(lc fx.Lifecycle, manager *mcp.Manager) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			manager.StartIdleSuspension(ctx)
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+func startFileWatcher(lc fx.Lifecycle, fileWatchService *filewatch.Service, cfg config.Config) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			fileWatchService.Start(ctx, time.Duration(cfg.MCP.FileWatchSeconds)*time.Second)
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
 func startContainerReconciliation(lc fx.Lifecycle, containerdHandler *handlers.ContainerdHandler, _ *mcp.ToolGatewayService) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
@@ -587,7 +732,7 @@ func startContainerReconciliation(lc fx.Lifecycle, containerdHandler *handlers.C
 	})
 }
 
-func startServer(lc fx.Lifecycle, logger *slog.Logger, srv *server.Server, shutdowner fx.Shutdowner, cfg config.Config, queries *dbsqlc.Queries, botService *bots.Service, containerdHandler *handlers.ContainerdHandler, mcpConnService *mcp.ConnectionService, toolGateway *mcp.ToolGatewayService, channelManager *channel.Manager) {
+func startServer(lc fx.Lifecycle, logger *slog.Logger, srv *server.Server, shutdowner fx.Shutdowner, cfg config.Config, queries *dbsqlc.Queries, botService *bots.Service, containerdHandler *handlers.ContainerdHandler, mcpConnService *mcp.ConnectionService, toolGateway *mcp.ToolGatewayService, channelManager *channel.Manager, manager *mcp.Manager) {
 	fmt.Printf("Starting Memoh Agent %s\n", version.GetInfo())
 
 	lc.Append(fx.Hook{
@@ -602,6 +747,9 @@ func startServer(lc fx.Lifecycle, logger *slog.Logger, srv *server.Server, shutd
 			botService.AddRuntimeChecker(healthcheck.NewRuntimeCheckerAdapter(
 				channelchecker.NewChecker(logger, channelManager),
 			))
+			botService.AddRuntimeChecker(healthcheck.NewRuntimeCheckerAdapter(
+				containerchecker.NewChecker(logger, manager),
+			))
 
 			go func() {
 				if err := srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -624,7 +772,7 @@ func startServer(lc fx.Lifecycle, logger *slog.Logger, srv *server.Server, shutd
 // helpers
 // ---------------------------------------------------------------------------
 
-func buildTextEmbedder(resolver *embeddings.Resolver, textModel models.GetResponse, hasModels bool, log *slog.Logger) embeddings.Embedder {
+func buildTextEmbedder(resolver *embeddings.Resolver, textModel models.GetResponse, hasModels bool, queries *dbsqlc.Queries, log *slog.Logger) embeddings.Embedder {
 	if !hasModels {
 		return nil
 	}
@@ -632,11 +780,12 @@ func buildTextEmbedder(resolver *embeddings.Resolver, textModel models.GetRespon
 		log.Warn("No text embedding model configured. Text embedding features will be limited.")
 		return nil
 	}
-	return &embeddings.ResolverTextEmbedder{
+	embedder := &embeddings.ResolverTextEmbedder{
 		Resolver: resolver,
 		ModelID:  textModel.ModelID,
 		Dims:     textModel.Dimensions,
 	}
+	return embeddings.NewCachedEmbedder(log, embedder, embeddings.NewPgCache(queries), textModel.ModelID)
 }
 
 func ensureAdminUser(ctx context.Context, log *slog.Logger, queries *dbsqlc.Queries, cfg config.Config) error {
@@ -742,6 +891,18 @@ func (c *lazyLLMClient) DetectLanguage(ctx context.Context, text string) (string
 	return client.DetectLanguage(ctx, text)
 }
 
+// ModelName reports the memory model selected for the bot in ctx.
+func (c *lazyLLMClient) ModelName(ctx context.Context) string {
+	if c.modelsService == nil || c.queries == nil {
+		return ""
+	}
+	memoryModel, _, err := models.SelectMemoryModelForBot(ctx, c.modelsService, c.queries, memory.BotIDFromContext(ctx))
+	if err != nil {
+		return ""
+	}
+	return memoryModel.ModelID
+}
+
 func (c *lazyLLMClient) resolve(ctx context.Context) (memory.LLM, error) {
 	if c.modelsService == nil || c.queries == nil {
 		return nil, fmt.Errorf("models service not configured")
@@ -760,6 +921,62 @@ func (c *lazyLLMClient) resolve(ctx context.Context) (memory.LLM, error) {
 	return memory.NewLLMClient(c.logger, memoryProvider.BaseUrl, memoryProvider.ApiKey, memoryModel.ModelID, c.timeout)
 }
 
+// rerankResolver selects the memory rerank model from bot settings.
+type rerankResolver struct {
+	modelsService   *models.Service
+	settingsService *settings.Service
+	queries         *dbsqlc.Queries
+	timeout         time.Duration
+	logger          *slog.Logger
+}
+
+func (r *rerankResolver) ResolveReranker(ctx context.Context) (memory.Reranker, int, error) {
+	botID := memory.BotIDFromContext(ctx)
+	if botID == "" {
+		return nil, 0, nil
+	}
+	botSettings, err := r.settingsService.GetBot(ctx, botID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !botSettings.RerankEnabled || botSettings.RerankModelID == "" {
+		return nil, 0, nil
+	}
+	rerankModel, err := r.modelsService.GetByID(ctx, botSettings.RerankModelID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if rerankModel.Type != models.ModelTypeRerank {
+		return nil, 0, fmt.Errorf("model %s is not a rerank model", rerankModel.ModelID)
+	}
+	provider, err := models.FetchProviderByID(ctx, r.queries, rerankModel.LlmProviderID)
+	if err != nil {
+		return nil, 0, err
+	}
+	client, err := memory.NewRerankClient(r.logger, provider.BaseUrl, provider.ApiKey, rerankModel.ModelID, r.timeout)
+	if err != nil {
+		return nil, 0, err
+	}
+	return client, botSettings.RerankTopN, nil
+}
+
+// memoryScopeResolver reads the memory scope policy from bot settings.
+type memoryScopeResolver struct {
+	settingsService *settings.Service
+}
+
+func (r *memoryScopeResolver) ResolveScopePolicy(ctx context.Context) (memory.ScopePolicy, error) {
+	botID := memory.BotIDFromContext(ctx)
+	if botID == "" {
+		return memory.ScopeShared, nil
+	}
+	botSettings, err := r.settingsService.GetBot(ctx, botID)
+	if err != nil {
+		return "", err
+	}
+	return memory.NormalizeScopePolicy(botSettings.MemoryScope), nil
+}
+
 // skillLoaderAdapter bridges handlers.ContainerdHandler to flow.SkillLoader.
 type skillLoaderAdapter struct {
 	handler *handlers.ContainerdHandler
@@ -1,3 +1,8 @@
+DROP TABLE IF EXISTS memory_vectors;
+DROP TABLE IF EXISTS memory_points;
+DROP TABLE IF EXISTS embedding_cache;
+DROP TABLE IF EXISTS memory_compaction_runs;
+DROP TABLE IF EXISTS memory_revisions;
 DROP TABLE IF EXISTS bot_history_message_assets;
 DROP TABLE IF EXISTS media_assets;
 DROP TABLE IF EXISTS bot_storage_bindings;
@@ -90,7 +90,7 @@ CREATE TABLE IF NOT EXISTS models (
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   CONSTRAINT models_provider_model_id_unique UNIQUE (llm_provider_id, model_id),
-  CONSTRAINT models_type_check CHECK (type IN ('chat', 'embedding')),
+  CONSTRAINT models_type_check CHECK (type IN ('chat', 'embedding', 'rerank')),
   CONSTRAINT models_dimensions_check CHECK (type != 'embedding' OR dimensions IS NOT NULL),
   CONSTRAINT models_client_type_check CHECK (client_type IS NULL OR client_type IN ('openai-responses', 'openai-completions', 'anthropic-messages', 'google-generative-ai')),
   CONSTRAINT models_chat_client_type_check CHECK (type != 'chat' OR client_type IS NOT NULL)
@@ -128,12 +128,34 @@ CREATE TABLE IF NOT EXISTS bots (
   memory_model_id UUID REFERENCES models(id) ON DELETE SET NULL,
   embedding_model_id UUID REFERENCES models(id) ON DELETE SET NULL,
   search_provider_id UUID REFERENCES search_providers(id) ON DELETE SET NULL,
+  rerank_enabled BOOLEAN NOT NULL DEFAULT false,
+  rerank_model_id UUID REFERENCES models(id) ON DELETE SET NULL,
+  rerank_top_n INTEGER NOT NULL DEFAULT 20,
+  memory_scope TEXT NOT NULL DEFAULT 'shared',
+  compaction_cron TEXT NOT NULL DEFAULT '',
+  compaction_ratio DOUBLE PRECISION NOT NULL DEFAULT 0.5,
+  compaction_decay_days INTEGER NOT NULL DEFAULT 0,
+  compaction_max_memories INTEGER NOT NULL DEFAULT 0,
+  container_cpu_shares INTEGER NOT NULL DEFAULT 0,
+  container_cpu_limit DOUBLE PRECISION NOT NULL DEFAULT 0,
+  container_memory_mb INTEGER NOT NULL DEFAULT 0,
+  container_pids_limit INTEGER NOT NULL DEFAULT 0,
+  container_disk_quota_mb INTEGER NOT NULL DEFAULT 0,
+  network_mode TEXT NOT NULL DEFAULT 'full',
+  network_allowlist TEXT[] NOT NULL DEFAULT '{}',
+  container_image TEXT NOT NULL DEFAULT '',
+  snapshot_cron TEXT NOT NULL DEFAULT '',
+  snapshot_before_risky BOOLEAN NOT NULL DEFAULT false,
+  snapshot_keep_last INTEGER NOT NULL DEFAULT 0,
+  snapshot_keep_daily INTEGER NOT NULL DEFAULT 0,
   metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   CONSTRAINT bots_type_check CHECK (type IN ('personal', 'public')),
   CONSTRAINT bots_status_check CHECK (status IN ('creating', 'ready', 'deleting')),
-  CONSTRAINT bots_reasoning_effort_check CHECK (reasoning_effort IN ('low', 'medium', 'high'))
+  CONSTRAINT bots_reasoning_effort_check CHECK (reasoning_effort IN ('low', 'medium', 'high')),
+  CONSTRAINT bots_memory_scope_check CHECK (memory_scope IN ('shared', 'per_user', 'per_conversation')),
+  CONSTRAINT bots_network_mode_check CHECK (network_mode IN ('full', 'none', 'allowlist'))
 );
 
 CREATE INDEX IF NOT EXISTS idx_bots_owner_user_id ON bots(owner_user_id);
@@ -170,6 +192,7 @@ CREATE TABLE IF NOT EXISTS bot_channel_configs (
   id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
   bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
   channel_type TEXT NOT NULL,
+  name TEXT NOT NULL DEFAULT '',
   credentials JSONB NOT NULL DEFAULT '{}'::jsonb,
   external_identity TEXT,
   self_identity JSONB NOT NULL DEFAULT '{}'::jsonb,
@@ -179,7 +202,7 @@ CREATE TABLE IF NOT EXISTS bot_channel_configs (
   verified_at TIMESTAMPTZ,
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
-  CONSTRAINT bot_channel_unique UNIQUE (bot_id, channel_type)
+  CONSTRAINT bot_channel_name_unique UNIQUE (bot_id, channel_type, name)
 );
 
 CREATE UNIQUE INDEX IF NOT EXISTS idx_bot_channel_external_identity
@@ -231,8 +254,8 @@ CREATE TABLE IF NOT EXISTS bot_channel_routes (
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
 
-CREATE UNIQUE INDEX IF NOT EXISTS idx_bot_channel_routes_unique
-  ON bot_channel_routes (bot_id, channel_type, external_conversation_id, COALESCE(external_thread_id, ''));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bot_channel_routes_config_unique
+  ON bot_channel_routes (bot_id, channel_type, COALESCE(channel_config_id::text, ''), external_conversation_id, COALESCE(external_thread_id, ''));
 CREATE INDEX IF NOT EXISTS idx_bot_channel_routes_bot ON bot_channel_routes(bot_id);
 
 -- bot_history_messages: unified message history under bot scope.
@@ -408,3 +431,112 @@ CREATE TABLE IF NOT EXISTS bot_inbox (
 
 CREATE INDEX IF NOT EXISTS idx_bot_inbox_bot_unread ON bot_inbox(bot_id, created_at DESC) WHERE is_read = FALSE;
 CREATE INDEX IF NOT EXISTS idx_bot_inbox_bot_created ON bot_inbox(bot_id, created_at DESC);
+
+-- memory_revisions: append-only audit log of memory changes (add/update/delete/compact/restore/expire).
+CREATE TABLE IF NOT EXISTS memory_revisions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  memory_id UUID NOT NULL,
+  action TEXT NOT NULL,
+  previous_text TEXT NOT NULL DEFAULT '',
+  new_text TEXT NOT NULL DEFAULT '',
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  source_messages JSONB NOT NULL DEFAULT '[]'::jsonb,
+  model TEXT NOT NULL DEFAULT '',
+  restored_from UUID,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT memory_revisions_action_check CHECK (action IN ('ADD', 'UPDATE', 'DELETE', 'COMPACT', 'RESTORE', 'EXPIRE'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_revisions_memory ON memory_revisions(memory_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_memory_revisions_bot_created ON memory_revisions(bot_id, created_at DESC);
+
+-- memory_compaction_runs: history of scheduled, manual and dry-run memory compactions.
+CREATE TABLE IF NOT EXISTS memory_compaction_runs (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  trigger TEXT NOT NULL,
+  dry_run BOOLEAN NOT NULL DEFAULT false,
+  status TEXT NOT NULL,
+  ratio DOUBLE PRECISION NOT NULL,
+  decay_days INTEGER NOT NULL DEFAULT 0,
+  before_count INTEGER NOT NULL DEFAULT 0,
+  after_count INTEGER NOT NULL DEFAULT 0,
+  merges JSONB NOT NULL DEFAULT '[]'::jsonb,
+  error TEXT NOT NULL DEFAULT '',
+  started_at TIMESTAMPTZ NOT NULL,
+  finished_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT memory_compaction_runs_trigger_check CHECK (trigger IN ('schedule', 'manual')),
+  CONSTRAINT memory_compaction_runs_status_check CHECK (status IN ('success', 'skipped', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_compaction_runs_bot_started ON memory_compaction_runs(bot_id, started_at DESC);
+
+-- embedding_cache: embeddings keyed by model and content hash, reused across rebuilds and imports.
+CREATE TABLE IF NOT EXISTS embedding_cache (
+  model_id TEXT NOT NULL,
+  content_hash TEXT NOT NULL,
+  dimensions INTEGER NOT NULL,
+  embedding REAL[] NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (model_id, content_hash)
+);
+
+-- bot_templates: admin-managed blueprints (identity files, skills, settings, MCP connections, schedules, subagents) for new bots.
+CREATE TABLE IF NOT EXISTS bot_templates (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  name TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  spec JSONB NOT NULL DEFAULT '{}'::jsonb,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT bot_templates_name_unique UNIQUE (name)
+);
+
+-- bot_file_watches: glob rules over a bot's data directory; matching changes go to the inbox or trigger a chat turn.
+CREATE TABLE IF NOT EXISTS bot_file_watches (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  pattern TEXT NOT NULL,
+  events TEXT[] NOT NULL DEFAULT ARRAY['created', 'modified', 'deleted']::TEXT[],
+  action TEXT NOT NULL DEFAULT 'inbox',
+  command TEXT NOT NULL DEFAULT '',
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT bot_file_watches_action_check CHECK (action IN ('inbox', 'chat'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_bot_file_watches_bot_id ON bot_file_watches(bot_id);
+
+-- memory_points / memory_vectors: pgvector memory store (memory.store = "pgvector"); only created when the vector extension is available.
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'vector') THEN
+    RAISE NOTICE 'vector extension is not available, skipping pgvector memory tables';
+    RETURN;
+  END IF;
+
+  CREATE EXTENSION IF NOT EXISTS vector;
+
+  CREATE TABLE IF NOT EXISTS memory_points (
+    id UUID PRIMARY KEY,
+    payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+    sparse_indices BIGINT[],
+    sparse_values REAL[],
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+  );
+
+  CREATE INDEX IF NOT EXISTS memory_points_bot_id_idx ON memory_points ((payload->>'bot_id'));
+  CREATE INDEX IF NOT EXISTS memory_points_run_id_idx ON memory_points ((payload->>'run_id'));
+  CREATE INDEX IF NOT EXISTS memory_points_sparse_idx ON memory_points USING GIN (sparse_indices);
+
+  CREATE TABLE IF NOT EXISTS memory_vectors (
+    point_id UUID NOT NULL REFERENCES memory_points(id) ON DELETE CASCADE,
+    name TEXT NOT NULL DEFAULT '',
+    embedding vector NOT NULL,
+    PRIMARY KEY (point_id, name)
+  );
+END
+$$;
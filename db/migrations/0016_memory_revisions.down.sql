@@ -0,0 +1,6 @@
+-- 0016_memory_revisions (down)
+-- Remove memory_revisions table.
+
+DROP INDEX IF EXISTS idx_memory_revisions_bot_created;
+DROP INDEX IF EXISTS idx_memory_revisions_memory;
+DROP TABLE IF EXISTS memory_revisions;
@@ -0,0 +1,20 @@
+-- 0016_memory_revisions
+-- Add append-only memory_revisions log recording every change to a memory.
+
+CREATE TABLE IF NOT EXISTS memory_revisions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  memory_id UUID NOT NULL,
+  action TEXT NOT NULL,
+  previous_text TEXT NOT NULL DEFAULT '',
+  new_text TEXT NOT NULL DEFAULT '',
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  source_messages JSONB NOT NULL DEFAULT '[]'::jsonb,
+  model TEXT NOT NULL DEFAULT '',
+  restored_from UUID,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT memory_revisions_action_check CHECK (action IN ('ADD', 'UPDATE', 'DELETE', 'COMPACT', 'RESTORE'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_revisions_memory ON memory_revisions(memory_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_memory_revisions_bot_created ON memory_revisions(bot_id, created_at DESC);
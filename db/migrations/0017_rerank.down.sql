@@ -0,0 +1,10 @@
+-- 0017_rerank (rollback)
+-- Remove per-bot rerank settings and rerank models.
+
+ALTER TABLE bots DROP COLUMN IF EXISTS rerank_top_n;
+ALTER TABLE bots DROP COLUMN IF EXISTS rerank_model_id;
+ALTER TABLE bots DROP COLUMN IF EXISTS rerank_enabled;
+
+DELETE FROM models WHERE type = 'rerank';
+ALTER TABLE models DROP CONSTRAINT IF EXISTS models_type_check;
+ALTER TABLE models ADD CONSTRAINT models_type_check CHECK (type IN ('chat', 'embedding'));
@@ -0,0 +1,9 @@
+-- 0017_rerank
+-- Add the rerank model type and per-bot memory rerank settings.
+
+ALTER TABLE models DROP CONSTRAINT IF EXISTS models_type_check;
+ALTER TABLE models ADD CONSTRAINT models_type_check CHECK (type IN ('chat', 'embedding', 'rerank'));
+
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS rerank_enabled BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS rerank_model_id UUID REFERENCES models(id) ON DELETE SET NULL;
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS rerank_top_n INTEGER NOT NULL DEFAULT 20;
@@ -0,0 +1,5 @@
+-- 0018_memory_scope (rollback)
+-- Remove per-bot memory scope policy.
+
+ALTER TABLE bots DROP CONSTRAINT IF EXISTS bots_memory_scope_check;
+ALTER TABLE bots DROP COLUMN IF EXISTS memory_scope;
@@ -0,0 +1,14 @@
+-- 0018_memory_scope
+-- Add per-bot memory scope policy (shared / per_user / per_conversation).
+
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS memory_scope TEXT NOT NULL DEFAULT 'shared';
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1 FROM pg_constraint WHERE conname = 'bots_memory_scope_check'
+  ) THEN
+    ALTER TABLE bots ADD CONSTRAINT bots_memory_scope_check
+      CHECK (memory_scope IN ('shared', 'per_user', 'per_conversation'));
+  END IF;
+END
+$$;
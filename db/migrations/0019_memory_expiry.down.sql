@@ -0,0 +1,7 @@
+-- 0019_memory_expiry (rollback)
+-- Fold EXPIRE revisions into DELETE and restore the previous action check.
+
+UPDATE memory_revisions SET action = 'DELETE' WHERE action = 'EXPIRE';
+ALTER TABLE memory_revisions DROP CONSTRAINT IF EXISTS memory_revisions_action_check;
+ALTER TABLE memory_revisions ADD CONSTRAINT memory_revisions_action_check
+  CHECK (action IN ('ADD', 'UPDATE', 'DELETE', 'COMPACT', 'RESTORE'));
@@ -0,0 +1,6 @@
+-- 0019_memory_expiry
+-- Allow EXPIRE revisions recorded when the sweeper removes expired memories.
+
+ALTER TABLE memory_revisions DROP CONSTRAINT IF EXISTS memory_revisions_action_check;
+ALTER TABLE memory_revisions ADD CONSTRAINT memory_revisions_action_check
+  CHECK (action IN ('ADD', 'UPDATE', 'DELETE', 'COMPACT', 'RESTORE', 'EXPIRE'));
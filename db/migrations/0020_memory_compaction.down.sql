@@ -0,0 +1,8 @@
+-- 0020_memory_compaction (rollback)
+-- Remove scheduled memory compaction settings and run history.
+
+DROP TABLE IF EXISTS memory_compaction_runs;
+ALTER TABLE bots DROP COLUMN IF EXISTS compaction_max_memories;
+ALTER TABLE bots DROP COLUMN IF EXISTS compaction_decay_days;
+ALTER TABLE bots DROP COLUMN IF EXISTS compaction_ratio;
+ALTER TABLE bots DROP COLUMN IF EXISTS compaction_cron;
@@ -0,0 +1,27 @@
+-- 0020_memory_compaction
+-- Add per-bot scheduled memory compaction settings and compaction run history.
+
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS compaction_cron TEXT NOT NULL DEFAULT '';
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS compaction_ratio DOUBLE PRECISION NOT NULL DEFAULT 0.5;
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS compaction_decay_days INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS compaction_max_memories INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS memory_compaction_runs (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  trigger TEXT NOT NULL,
+  dry_run BOOLEAN NOT NULL DEFAULT false,
+  status TEXT NOT NULL,
+  ratio DOUBLE PRECISION NOT NULL,
+  decay_days INTEGER NOT NULL DEFAULT 0,
+  before_count INTEGER NOT NULL DEFAULT 0,
+  after_count INTEGER NOT NULL DEFAULT 0,
+  merges JSONB NOT NULL DEFAULT '[]'::jsonb,
+  error TEXT NOT NULL DEFAULT '',
+  started_at TIMESTAMPTZ NOT NULL,
+  finished_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT memory_compaction_runs_trigger_check CHECK (trigger IN ('schedule', 'manual')),
+  CONSTRAINT memory_compaction_runs_status_check CHECK (status IN ('success', 'skipped', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_compaction_runs_bot_started ON memory_compaction_runs(bot_id, started_at DESC);
@@ -0,0 +1,4 @@
+-- 0021_embedding_cache (rollback)
+-- Drop the embedding cache.
+
+DROP TABLE IF EXISTS embedding_cache;
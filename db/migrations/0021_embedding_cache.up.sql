@@ -0,0 +1,11 @@
+-- 0021_embedding_cache
+-- Add a content-hash keyed embedding cache so identical text is embedded once per model.
+
+CREATE TABLE IF NOT EXISTS embedding_cache (
+  model_id TEXT NOT NULL,
+  content_hash TEXT NOT NULL,
+  dimensions INTEGER NOT NULL,
+  embedding REAL[] NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (model_id, content_hash)
+);
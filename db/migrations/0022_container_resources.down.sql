@@ -0,0 +1,8 @@
+-- 0022_container_resources (rollback)
+-- Remove per-bot container resource limits.
+
+ALTER TABLE bots DROP COLUMN IF EXISTS container_disk_quota_mb;
+ALTER TABLE bots DROP COLUMN IF EXISTS container_pids_limit;
+ALTER TABLE bots DROP COLUMN IF EXISTS container_memory_mb;
+ALTER TABLE bots DROP COLUMN IF EXISTS container_cpu_limit;
+ALTER TABLE bots DROP COLUMN IF EXISTS container_cpu_shares;
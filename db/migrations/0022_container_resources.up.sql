@@ -0,0 +1,8 @@
+-- 0022_container_resources
+-- Add per-bot container resource limits (CPU, memory, pids, data-dir quota). Zero means unlimited.
+
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS container_cpu_shares INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS container_cpu_limit DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS container_memory_mb INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS container_pids_limit INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS container_disk_quota_mb INTEGER NOT NULL DEFAULT 0;
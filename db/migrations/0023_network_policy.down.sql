@@ -0,0 +1,6 @@
+-- 0023_network_policy (rollback)
+-- Remove per-bot container network policy.
+
+ALTER TABLE bots DROP CONSTRAINT IF EXISTS bots_network_mode_check;
+ALTER TABLE bots DROP COLUMN IF EXISTS network_allowlist;
+ALTER TABLE bots DROP COLUMN IF EXISTS network_mode;
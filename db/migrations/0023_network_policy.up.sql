@@ -0,0 +1,15 @@
+-- 0023_network_policy
+-- Add per-bot container network policy (full / none / allowlist) and its egress allowlist.
+
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS network_mode TEXT NOT NULL DEFAULT 'full';
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS network_allowlist TEXT[] NOT NULL DEFAULT '{}';
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1 FROM pg_constraint WHERE conname = 'bots_network_mode_check'
+  ) THEN
+    ALTER TABLE bots ADD CONSTRAINT bots_network_mode_check
+      CHECK (network_mode IN ('full', 'none', 'allowlist'));
+  END IF;
+END
+$$;
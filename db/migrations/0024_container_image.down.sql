@@ -0,0 +1,4 @@
+-- 0024_container_image (rollback)
+-- Remove per-bot container image.
+
+ALTER TABLE bots DROP COLUMN IF EXISTS container_image;
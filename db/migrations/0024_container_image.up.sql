@@ -0,0 +1,4 @@
+-- 0024_container_image
+-- Add per-bot container image. Empty means the globally configured MCP image.
+
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS container_image TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- 0025_snapshot_policy (rollback)
+-- Remove per-bot container snapshot policy.
+
+ALTER TABLE bots DROP COLUMN IF EXISTS snapshot_keep_daily;
+ALTER TABLE bots DROP COLUMN IF EXISTS snapshot_keep_last;
+ALTER TABLE bots DROP COLUMN IF EXISTS snapshot_before_risky;
+ALTER TABLE bots DROP COLUMN IF EXISTS snapshot_cron;
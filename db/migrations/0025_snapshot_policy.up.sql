@@ -0,0 +1,7 @@
+-- 0025_snapshot_policy
+-- Add per-bot container snapshot policy: cron schedule, snapshots before risky operations and retention.
+
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS snapshot_cron TEXT NOT NULL DEFAULT '';
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS snapshot_before_risky BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS snapshot_keep_last INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS snapshot_keep_daily INTEGER NOT NULL DEFAULT 0;
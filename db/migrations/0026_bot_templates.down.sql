@@ -0,0 +1,4 @@
+-- 0026_bot_templates (rollback)
+-- Remove bot templates.
+
+DROP TABLE IF EXISTS bot_templates;
@@ -0,0 +1,12 @@
+-- 0026_bot_templates
+-- Add admin-managed bot templates used to create preconfigured bots.
+
+CREATE TABLE IF NOT EXISTS bot_templates (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  name TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  spec JSONB NOT NULL DEFAULT '{}'::jsonb,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT bot_templates_name_unique UNIQUE (name)
+);
@@ -0,0 +1,4 @@
+-- 0027_file_watches (rollback)
+-- Remove file watches.
+
+DROP TABLE IF EXISTS bot_file_watches;
@@ -0,0 +1,17 @@
+-- 0027_file_watches
+-- Add per-bot file watches: glob rules over the data directory whose changes are delivered to the inbox or trigger a chat turn.
+
+CREATE TABLE IF NOT EXISTS bot_file_watches (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  pattern TEXT NOT NULL,
+  events TEXT[] NOT NULL DEFAULT ARRAY['created', 'modified', 'deleted']::TEXT[],
+  action TEXT NOT NULL DEFAULT 'inbox',
+  command TEXT NOT NULL DEFAULT '',
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT bot_file_watches_action_check CHECK (action IN ('inbox', 'chat'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_bot_file_watches_bot_id ON bot_file_watches(bot_id);
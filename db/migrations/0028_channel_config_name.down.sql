@@ -0,0 +1,41 @@
+-- 0028_channel_config_name (rollback)
+-- Restore one channel config per bot and channel type.
+
+DO $$
+BEGIN
+  IF EXISTS (
+    SELECT 1
+    FROM bot_channel_configs
+    GROUP BY bot_id, channel_type
+    HAVING COUNT(*) > 1
+  ) THEN
+    RAISE EXCEPTION 'cannot rollback 0028_channel_config_name: bots with several configs of the same channel type exist';
+  END IF;
+END
+$$;
+
+DROP INDEX IF EXISTS idx_bot_channel_routes_config_unique;
+
+-- Keep the most recently used route per external conversation.
+DELETE FROM bot_channel_routes r
+USING bot_channel_routes newer
+WHERE r.bot_id = newer.bot_id
+  AND r.channel_type = newer.channel_type
+  AND r.external_conversation_id = newer.external_conversation_id
+  AND COALESCE(r.external_thread_id, '') = COALESCE(newer.external_thread_id, '')
+  AND (r.updated_at, r.id) < (newer.updated_at, newer.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bot_channel_routes_unique
+  ON bot_channel_routes (bot_id, channel_type, external_conversation_id, COALESCE(external_thread_id, ''));
+
+ALTER TABLE bot_channel_configs DROP CONSTRAINT IF EXISTS bot_channel_name_unique;
+ALTER TABLE bot_channel_configs DROP COLUMN IF EXISTS name;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'bot_channel_unique') THEN
+    ALTER TABLE bot_channel_configs
+      ADD CONSTRAINT bot_channel_unique UNIQUE (bot_id, channel_type);
+  END IF;
+END
+$$;
@@ -0,0 +1,36 @@
+-- 0028_channel_config_name
+-- Allow several named channel configs of the same type per bot and scope routes to the config they arrived through.
+
+ALTER TABLE bot_channel_configs ADD COLUMN IF NOT EXISTS name TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE bot_channel_configs DROP CONSTRAINT IF EXISTS bot_channel_unique;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'bot_channel_name_unique') THEN
+    ALTER TABLE bot_channel_configs
+      ADD CONSTRAINT bot_channel_name_unique UNIQUE (bot_id, channel_type, name);
+  END IF;
+END
+$$;
+
+DROP INDEX IF EXISTS idx_bot_channel_routes_unique;
+
+-- Routes created before configs were named belong to the bot's only config of
+-- that type, which becomes its default.
+UPDATE bot_channel_routes r
+SET channel_config_id = (
+  SELECT c.id
+  FROM bot_channel_configs c
+  WHERE c.bot_id = r.bot_id AND c.channel_type = r.channel_type
+  ORDER BY (c.name = '') DESC, c.created_at ASC
+  LIMIT 1
+)
+WHERE r.channel_config_id IS NULL
+  AND EXISTS (
+    SELECT 1 FROM bot_channel_configs c
+    WHERE c.bot_id = r.bot_id AND c.channel_type = r.channel_type
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bot_channel_routes_config_unique
+  ON bot_channel_routes (bot_id, channel_type, COALESCE(channel_config_id::text, ''), external_conversation_id, COALESCE(external_thread_id, ''));
@@ -0,0 +1,3 @@
+-- 0029_localfs_binding_base_path (rollback)
+-- Nothing to restore: the cleared base paths were never applied.
+SELECT 1;
@@ -0,0 +1,9 @@
+-- 0029_localfs_binding_base_path
+-- Clear base paths on localfs storage bindings; localfs never applied them and now rejects them.
+
+UPDATE bot_storage_bindings b
+SET base_path = '', updated_at = now()
+FROM storage_providers p
+WHERE p.id = b.storage_provider_id
+  AND p.provider = 'localfs'
+  AND b.base_path <> '';
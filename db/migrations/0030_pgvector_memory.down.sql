@@ -0,0 +1,5 @@
+-- 0030_pgvector_memory (rollback)
+-- Remove the pgvector memory store tables; the vector extension is left installed.
+
+DROP TABLE IF EXISTS memory_vectors;
+DROP TABLE IF EXISTS memory_points;
@@ -0,0 +1,35 @@
+-- 0030_pgvector_memory
+-- Create the pgvector memory store tables (memory.store = "pgvector").
+-- Requires a Postgres image that ships the vector extension, e.g. pgvector/pgvector:pg18;
+-- on images without it the tables are skipped and only the Qdrant store is usable.
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'vector') THEN
+    RAISE NOTICE 'vector extension is not available, skipping pgvector memory tables';
+    RETURN;
+  END IF;
+
+  CREATE EXTENSION IF NOT EXISTS vector;
+
+  CREATE TABLE IF NOT EXISTS memory_points (
+    id UUID PRIMARY KEY,
+    payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+    sparse_indices BIGINT[],
+    sparse_values REAL[],
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+  );
+
+  CREATE INDEX IF NOT EXISTS memory_points_bot_id_idx ON memory_points ((payload->>'bot_id'));
+  CREATE INDEX IF NOT EXISTS memory_points_run_id_idx ON memory_points ((payload->>'run_id'));
+  CREATE INDEX IF NOT EXISTS memory_points_sparse_idx ON memory_points USING GIN (sparse_indices);
+
+  CREATE TABLE IF NOT EXISTS memory_vectors (
+    point_id UUID NOT NULL REFERENCES memory_points(id) ON DELETE CASCADE,
+    name TEXT NOT NULL DEFAULT '',
+    embedding vector NOT NULL,
+    PRIMARY KEY (point_id, name)
+  );
+END
+$$;
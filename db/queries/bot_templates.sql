@@ -0,0 +1,25 @@
+-- name: CreateBotTemplate :one
+INSERT INTO bot_templates (name, description, spec)
+VALUES (sqlc.arg(name), sqlc.arg(description), sqlc.arg(spec))
+RETURNING *;
+
+-- name: GetBotTemplateByID :one
+SELECT * FROM bot_templates
+WHERE id = $1;
+
+-- name: ListBotTemplates :many
+SELECT * FROM bot_templates
+ORDER BY name;
+
+-- name: UpdateBotTemplate :one
+UPDATE bot_templates
+SET name = sqlc.arg(name),
+    description = sqlc.arg(description),
+    spec = sqlc.arg(spec),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteBotTemplate :exec
+DELETE FROM bot_templates
+WHERE id = $1;
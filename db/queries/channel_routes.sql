@@ -43,8 +43,14 @@ SELECT
 FROM bot_channel_routes
 WHERE bot_id = $1
   AND channel_type = sqlc.arg(platform)
+  AND (
+    channel_config_id IS NOT DISTINCT FROM sqlc.narg(channel_config_id)::uuid
+    OR channel_config_id IS NULL
+  )
   AND external_conversation_id = sqlc.arg(conversation_id)
   AND COALESCE(external_thread_id, '') = COALESCE(sqlc.narg(thread_id), '')
+-- A route of the requested config wins over one whose config was deleted.
+ORDER BY (channel_config_id IS NULL) ASC
 LIMIT 1;
 
 -- name: GetChatRouteByID :one
@@ -92,6 +98,38 @@ UPDATE bot_channel_routes
 SET metadata = sqlc.arg(metadata), updated_at = now()
 WHERE id = sqlc.arg(id);
 
+-- name: ClaimChatRoute :exec
+-- Attaches a route whose config was deleted to the config it is used through again.
+UPDATE bot_channel_routes
+SET channel_config_id = sqlc.arg(channel_config_id), updated_at = now()
+WHERE id = sqlc.arg(id) AND channel_config_id IS NULL;
+
+-- name: MoveOrphanedChatRouteHistory :exec
+-- Moves the history of config-less routes onto the matching routes of a
+-- config, so deleting that config cannot leave two config-less routes for
+-- the same conversation.
+UPDATE bot_history_messages m
+SET route_id = c.id
+FROM bot_channel_routes c
+JOIN bot_channel_routes o
+  ON o.bot_id = c.bot_id
+  AND o.channel_type = c.channel_type
+  AND o.channel_config_id IS NULL
+  AND o.external_conversation_id = c.external_conversation_id
+  AND COALESCE(o.external_thread_id, '') = COALESCE(c.external_thread_id, '')
+WHERE c.channel_config_id = sqlc.arg(channel_config_id)
+  AND m.route_id = o.id;
+
+-- name: DeleteOrphanedChatRoutes :exec
+DELETE FROM bot_channel_routes o
+USING bot_channel_routes c
+WHERE c.channel_config_id = sqlc.arg(channel_config_id)
+  AND o.bot_id = c.bot_id
+  AND o.channel_type = c.channel_type
+  AND o.channel_config_id IS NULL
+  AND o.external_conversation_id = c.external_conversation_id
+  AND COALESCE(o.external_thread_id, '') = COALESCE(c.external_thread_id, '');
+
 -- name: DeleteChatRoute :exec
 DELETE FROM bot_channel_routes
 WHERE id = $1;
@@ -1,25 +1,38 @@
 -- name: DeleteBotChannelConfig :exec
 DELETE FROM bot_channel_configs
-WHERE bot_id = $1 AND channel_type = $2;
+WHERE bot_id = $1 AND channel_type = $2 AND name = $3;
 
 -- name: GetBotChannelConfig :one
-SELECT id, bot_id, channel_type, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
+SELECT id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
+FROM bot_channel_configs
+WHERE bot_id = $1 AND channel_type = $2 AND name = $3
+LIMIT 1;
+
+-- name: GetDefaultBotChannelConfig :one
+-- Prefers the unnamed config, then the oldest one.
+SELECT id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
 FROM bot_channel_configs
 WHERE bot_id = $1 AND channel_type = $2
+ORDER BY (name = '') DESC, created_at ASC
 LIMIT 1;
 
+-- name: GetBotChannelConfigByID :one
+SELECT id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
+FROM bot_channel_configs
+WHERE id = $1;
+
 -- name: GetBotChannelConfigByExternalIdentity :one
-SELECT id, bot_id, channel_type, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
+SELECT id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
 FROM bot_channel_configs
 WHERE channel_type = $1 AND external_identity = $2
 LIMIT 1;
 
 -- name: UpsertBotChannelConfig :one
 INSERT INTO bot_channel_configs (
-  bot_id, channel_type, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at
+  bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-ON CONFLICT (bot_id, channel_type)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (bot_id, channel_type, name)
 DO UPDATE SET
   credentials = EXCLUDED.credentials,
   external_identity = EXCLUDED.external_identity,
@@ -29,22 +42,28 @@ DO UPDATE SET
   disabled = EXCLUDED.disabled,
   verified_at = EXCLUDED.verified_at,
   updated_at = now()
-RETURNING id, bot_id, channel_type, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at;
+RETURNING id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at;
 
 -- name: UpdateBotChannelConfigDisabled :one
 UPDATE bot_channel_configs
 SET
-  disabled = $3,
+  disabled = $4,
   updated_at = now()
-WHERE bot_id = $1 AND channel_type = $2
-RETURNING id, bot_id, channel_type, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at;
+WHERE bot_id = $1 AND channel_type = $2 AND name = $3
+RETURNING id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at;
 
 -- name: ListBotChannelConfigsByType :many
-SELECT id, bot_id, channel_type, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
+SELECT id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
 FROM bot_channel_configs
 WHERE channel_type = $1
 ORDER BY created_at DESC;
 
+-- name: ListBotChannelConfigsByBot :many
+SELECT id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
+FROM bot_channel_configs
+WHERE bot_id = $1
+ORDER BY channel_type, name;
+
 -- name: GetUserChannelBinding :one
 SELECT id, user_id, channel_type, config, created_at, updated_at
 FROM user_channel_bindings
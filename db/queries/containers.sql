@@ -52,3 +52,22 @@ WHERE bot_id = sqlc.arg(bot_id);
 
 -- name: ListAutoStartContainers :many
 SELECT * FROM containers WHERE auto_start = true ORDER BY updated_at DESC;
+
+-- name: GetBotResourceLimits :one
+SELECT container_cpu_shares, container_cpu_limit, container_memory_mb, container_pids_limit, container_disk_quota_mb
+FROM bots
+WHERE id = $1;
+
+-- name: GetBotNetworkPolicy :one
+SELECT network_mode, network_allowlist
+FROM bots
+WHERE id = $1;
+
+-- name: GetBotContainerImage :one
+SELECT container_image FROM bots WHERE id = $1;
+
+-- name: UpdateBotContainerImage :exec
+UPDATE bots SET container_image = $2, updated_at = now() WHERE id = $1;
+
+-- name: CountContainersByImage :one
+SELECT COUNT(*)::bigint FROM containers WHERE image = $1;
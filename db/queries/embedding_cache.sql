@@ -0,0 +1,13 @@
+-- name: GetEmbeddingCacheEntries :many
+SELECT content_hash, embedding
+FROM embedding_cache
+WHERE model_id = sqlc.arg(model_id)
+  AND content_hash = ANY(sqlc.arg(content_hashes)::text[]);
+
+-- name: UpsertEmbeddingCacheEntry :exec
+INSERT INTO embedding_cache (model_id, content_hash, dimensions, embedding)
+VALUES (sqlc.arg(model_id), sqlc.arg(content_hash), sqlc.arg(dimensions), sqlc.arg(embedding))
+ON CONFLICT (model_id, content_hash) DO UPDATE SET
+  dimensions = EXCLUDED.dimensions,
+  embedding = EXCLUDED.embedding,
+  created_at = now();
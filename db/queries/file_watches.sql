@@ -0,0 +1,36 @@
+-- name: CreateFileWatch :one
+INSERT INTO bot_file_watches (bot_id, pattern, events, action, command, enabled)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, bot_id, pattern, events, action, command, enabled, created_at, updated_at;
+
+-- name: GetFileWatchByID :one
+SELECT id, bot_id, pattern, events, action, command, enabled, created_at, updated_at
+FROM bot_file_watches
+WHERE id = $1;
+
+-- name: ListFileWatchesByBot :many
+SELECT id, bot_id, pattern, events, action, command, enabled, created_at, updated_at
+FROM bot_file_watches
+WHERE bot_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListEnabledFileWatches :many
+SELECT id, bot_id, pattern, events, action, command, enabled, created_at, updated_at
+FROM bot_file_watches
+WHERE enabled = true
+ORDER BY bot_id, created_at;
+
+-- name: UpdateFileWatch :one
+UPDATE bot_file_watches
+SET pattern = $2,
+    events = $3,
+    action = $4,
+    command = $5,
+    enabled = $6,
+    updated_at = now()
+WHERE id = $1
+RETURNING id, bot_id, pattern, events, action, command, enabled, created_at, updated_at;
+
+-- name: DeleteFileWatch :exec
+DELETE FROM bot_file_watches
+WHERE id = $1;
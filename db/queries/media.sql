@@ -12,6 +12,19 @@ SELECT * FROM storage_providers WHERE name = sqlc.arg(name);
 -- name: ListStorageProviders :many
 SELECT * FROM storage_providers ORDER BY created_at DESC;
 
+-- name: UpdateStorageProvider :one
+UPDATE storage_providers
+SET
+  name = sqlc.arg(name),
+  provider = sqlc.arg(provider),
+  config = sqlc.arg(config),
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteStorageProvider :exec
+DELETE FROM storage_providers WHERE id = sqlc.arg(id);
+
 -- name: UpsertBotStorageBinding :one
 INSERT INTO bot_storage_bindings (bot_id, storage_provider_id, base_path)
 VALUES (sqlc.arg(bot_id), sqlc.arg(storage_provider_id), sqlc.arg(base_path))
@@ -24,6 +37,12 @@ RETURNING *;
 -- name: GetBotStorageBinding :one
 SELECT * FROM bot_storage_bindings WHERE bot_id = sqlc.arg(bot_id);
 
+-- name: DeleteBotStorageBinding :exec
+DELETE FROM bot_storage_bindings WHERE bot_id = sqlc.arg(bot_id);
+
+-- name: CountBotStorageBindingsByProvider :one
+SELECT COUNT(*) FROM bot_storage_bindings WHERE storage_provider_id = sqlc.arg(storage_provider_id);
+
 -- name: CreateMessageAsset :one
 INSERT INTO bot_history_message_assets (message_id, role, ordinal, content_hash)
 VALUES (
@@ -51,3 +70,10 @@ ORDER BY message_id, ordinal ASC;
 
 -- name: DeleteMessageAssets :exec
 DELETE FROM bot_history_message_assets WHERE message_id = sqlc.arg(message_id);
+
+-- name: ListBotAssetContentHashes :many
+SELECT DISTINCT a.content_hash
+FROM bot_history_message_assets a
+JOIN bot_history_messages m ON m.id = a.message_id
+WHERE m.bot_id = sqlc.arg(bot_id)
+ORDER BY a.content_hash;
@@ -0,0 +1,32 @@
+-- name: ListBotCompactionSchedules :many
+SELECT id, compaction_cron
+FROM bots
+WHERE compaction_cron <> '';
+
+-- name: GetBotCompactionSettings :one
+SELECT id, compaction_cron, compaction_ratio, compaction_decay_days, compaction_max_memories
+FROM bots
+WHERE id = $1;
+
+-- name: CreateMemoryCompactionRun :one
+INSERT INTO memory_compaction_runs (bot_id, trigger, dry_run, status, ratio, decay_days, before_count, after_count, merges, error, started_at)
+VALUES (
+  sqlc.arg(bot_id),
+  sqlc.arg(trigger),
+  sqlc.arg(dry_run),
+  sqlc.arg(status),
+  sqlc.arg(ratio),
+  sqlc.arg(decay_days),
+  sqlc.arg(before_count),
+  sqlc.arg(after_count),
+  sqlc.arg(merges),
+  sqlc.arg(error),
+  sqlc.arg(started_at)
+)
+RETURNING *;
+
+-- name: ListMemoryCompactionRuns :many
+SELECT * FROM memory_compaction_runs
+WHERE bot_id = sqlc.arg(bot_id)
+ORDER BY started_at DESC, id DESC
+LIMIT sqlc.arg(max_count);
@@ -0,0 +1,26 @@
+-- name: CreateMemoryRevision :one
+INSERT INTO memory_revisions (bot_id, memory_id, action, previous_text, new_text, payload, source_messages, model, restored_from)
+VALUES (
+  sqlc.arg(bot_id),
+  sqlc.arg(memory_id),
+  sqlc.arg(action),
+  sqlc.arg(previous_text),
+  sqlc.arg(new_text),
+  sqlc.arg(payload),
+  sqlc.arg(source_messages),
+  sqlc.arg(model),
+  sqlc.narg(restored_from)
+)
+RETURNING *;
+
+-- name: GetMemoryRevision :one
+SELECT * FROM memory_revisions
+WHERE id = sqlc.arg(id)
+  AND bot_id = sqlc.arg(bot_id);
+
+-- name: ListMemoryRevisions :many
+SELECT * FROM memory_revisions
+WHERE bot_id = sqlc.arg(bot_id)
+  AND memory_id = sqlc.arg(memory_id)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(max_count);
@@ -8,15 +8,35 @@ SELECT
   bots.allow_guest,
   bots.reasoning_enabled,
   bots.reasoning_effort,
+  bots.rerank_enabled,
+  bots.rerank_top_n,
+  bots.memory_scope,
+  bots.compaction_cron,
+  bots.compaction_ratio,
+  bots.compaction_decay_days,
+  bots.compaction_max_memories,
+  bots.container_cpu_shares,
+  bots.container_cpu_limit,
+  bots.container_memory_mb,
+  bots.container_pids_limit,
+  bots.container_disk_quota_mb,
+  bots.network_mode,
+  bots.network_allowlist,
+  bots.snapshot_cron,
+  bots.snapshot_before_risky,
+  bots.snapshot_keep_last,
+  bots.snapshot_keep_daily,
   chat_models.id AS chat_model_id,
   memory_models.id AS memory_model_id,
   embedding_models.id AS embedding_model_id,
-  search_providers.id AS search_provider_id
+  search_providers.id AS search_provider_id,
+  rerank_models.id AS rerank_model_id
 FROM bots
 LEFT JOIN models AS chat_models ON chat_models.id = bots.chat_model_id
 LEFT JOIN models AS memory_models ON memory_models.id = bots.memory_model_id
 LEFT JOIN models AS embedding_models ON embedding_models.id = bots.embedding_model_id
 LEFT JOIN search_providers ON search_providers.id = bots.search_provider_id
+LEFT JOIN models AS rerank_models ON rerank_models.id = bots.rerank_model_id
 WHERE bots.id = $1;
 
 -- name: UpsertBotSettings :one
@@ -33,9 +53,28 @@ WITH updated AS (
       memory_model_id = COALESCE(sqlc.narg(memory_model_id)::uuid, bots.memory_model_id),
       embedding_model_id = COALESCE(sqlc.narg(embedding_model_id)::uuid, bots.embedding_model_id),
       search_provider_id = COALESCE(sqlc.narg(search_provider_id)::uuid, bots.search_provider_id),
+      rerank_enabled = COALESCE(sqlc.narg(rerank_enabled)::boolean, bots.rerank_enabled),
+      rerank_model_id = COALESCE(sqlc.narg(rerank_model_id)::uuid, bots.rerank_model_id),
+      rerank_top_n = COALESCE(sqlc.narg(rerank_top_n)::integer, bots.rerank_top_n),
+      memory_scope = COALESCE(sqlc.narg(memory_scope)::text, bots.memory_scope),
+      compaction_cron = COALESCE(sqlc.narg(compaction_cron)::text, bots.compaction_cron),
+      compaction_ratio = COALESCE(sqlc.narg(compaction_ratio)::double precision, bots.compaction_ratio),
+      compaction_decay_days = COALESCE(sqlc.narg(compaction_decay_days)::integer, bots.compaction_decay_days),
+      compaction_max_memories = COALESCE(sqlc.narg(compaction_max_memories)::integer, bots.compaction_max_memories),
+      container_cpu_shares = COALESCE(sqlc.narg(container_cpu_shares)::integer, bots.container_cpu_shares),
+      container_cpu_limit = COALESCE(sqlc.narg(container_cpu_limit)::double precision, bots.container_cpu_limit),
+      container_memory_mb = COALESCE(sqlc.narg(container_memory_mb)::integer, bots.container_memory_mb),
+      container_pids_limit = COALESCE(sqlc.narg(container_pids_limit)::integer, bots.container_pids_limit),
+      container_disk_quota_mb = COALESCE(sqlc.narg(container_disk_quota_mb)::integer, bots.container_disk_quota_mb),
+      network_mode = COALESCE(sqlc.narg(network_mode)::text, bots.network_mode),
+      network_allowlist = COALESCE(sqlc.narg(network_allowlist)::text[], bots.network_allowlist),
+      snapshot_cron = COALESCE(sqlc.narg(snapshot_cron)::text, bots.snapshot_cron),
+      snapshot_before_risky = COALESCE(sqlc.narg(snapshot_before_risky)::boolean, bots.snapshot_before_risky),
+      snapshot_keep_last = COALESCE(sqlc.narg(snapshot_keep_last)::integer, bots.snapshot_keep_last),
+      snapshot_keep_daily = COALESCE(sqlc.narg(snapshot_keep_daily)::integer, bots.snapshot_keep_daily),
       updated_at = now()
   WHERE bots.id = sqlc.arg(id)
-  RETURNING bots.id, bots.max_context_load_time, bots.max_context_tokens, bots.max_inbox_items, bots.language, bots.allow_guest, bots.reasoning_enabled, bots.reasoning_effort, bots.chat_model_id, bots.memory_model_id, bots.embedding_model_id, bots.search_provider_id
+  RETURNING bots.id, bots.max_context_load_time, bots.max_context_tokens, bots.max_inbox_items, bots.language, bots.allow_guest, bots.reasoning_enabled, bots.reasoning_effort, bots.rerank_enabled, bots.rerank_top_n, bots.memory_scope, bots.compaction_cron, bots.compaction_ratio, bots.compaction_decay_days, bots.compaction_max_memories, bots.container_cpu_shares, bots.container_cpu_limit, bots.container_memory_mb, bots.container_pids_limit, bots.container_disk_quota_mb, bots.network_mode, bots.network_allowlist, bots.snapshot_cron, bots.snapshot_before_risky, bots.snapshot_keep_last, bots.snapshot_keep_daily, bots.chat_model_id, bots.memory_model_id, bots.embedding_model_id, bots.search_provider_id, bots.rerank_model_id
 )
 SELECT
   updated.id AS bot_id,
@@ -46,15 +85,35 @@ SELECT
   updated.allow_guest,
   updated.reasoning_enabled,
   updated.reasoning_effort,
+  updated.rerank_enabled,
+  updated.rerank_top_n,
+  updated.memory_scope,
+  updated.compaction_cron,
+  updated.compaction_ratio,
+  updated.compaction_decay_days,
+  updated.compaction_max_memories,
+  updated.container_cpu_shares,
+  updated.container_cpu_limit,
+  updated.container_memory_mb,
+  updated.container_pids_limit,
+  updated.container_disk_quota_mb,
+  updated.network_mode,
+  updated.network_allowlist,
+  updated.snapshot_cron,
+  updated.snapshot_before_risky,
+  updated.snapshot_keep_last,
+  updated.snapshot_keep_daily,
   chat_models.id AS chat_model_id,
   memory_models.id AS memory_model_id,
   embedding_models.id AS embedding_model_id,
-  search_providers.id AS search_provider_id
+  search_providers.id AS search_provider_id,
+  rerank_models.id AS rerank_model_id
 FROM updated
 LEFT JOIN models AS chat_models ON chat_models.id = updated.chat_model_id
 LEFT JOIN models AS memory_models ON memory_models.id = updated.memory_model_id
 LEFT JOIN models AS embedding_models ON embedding_models.id = updated.embedding_model_id
-LEFT JOIN search_providers ON search_providers.id = updated.search_provider_id;
+LEFT JOIN search_providers ON search_providers.id = updated.search_provider_id
+LEFT JOIN models AS rerank_models ON rerank_models.id = updated.rerank_model_id;
 
 -- name: DeleteSettingsByBotID :exec
 UPDATE bots
@@ -69,5 +128,24 @@ SET max_context_load_time = 1440,
     memory_model_id = NULL,
     embedding_model_id = NULL,
     search_provider_id = NULL,
+    rerank_enabled = false,
+    rerank_model_id = NULL,
+    rerank_top_n = 20,
+    memory_scope = 'shared',
+    compaction_cron = '',
+    compaction_ratio = 0.5,
+    compaction_decay_days = 0,
+    compaction_max_memories = 0,
+    container_cpu_shares = 0,
+    container_cpu_limit = 0,
+    container_memory_mb = 0,
+    container_pids_limit = 0,
+    container_disk_quota_mb = 0,
+    network_mode = 'full',
+    network_allowlist = '{}',
+    snapshot_cron = '',
+    snapshot_before_risky = false,
+    snapshot_keep_last = 0,
+    snapshot_keep_daily = 0,
     updated_at = now()
 WHERE id = $1;
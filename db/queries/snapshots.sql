@@ -61,3 +61,18 @@ FROM snapshots
 WHERE container_id = sqlc.arg(container_id)
   AND runtime_snapshot_name = sqlc.arg(runtime_snapshot_name)
 LIMIT 1;
+
+-- name: ListBotSnapshotSchedules :many
+SELECT id, snapshot_cron
+FROM bots
+WHERE snapshot_cron <> '';
+
+-- name: GetBotSnapshotPolicy :one
+SELECT id, snapshot_cron, snapshot_before_risky, snapshot_keep_last, snapshot_keep_daily
+FROM bots
+WHERE id = $1;
+
+-- name: DeleteSnapshotsByIDs :exec
+DELETE FROM snapshots
+WHERE container_id = sqlc.arg(container_id)
+  AND id = ANY(sqlc.arg(ids)::uuid[]);
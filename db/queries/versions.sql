@@ -29,3 +29,8 @@ FROM container_versions cv
 JOIN snapshots s ON s.id = cv.snapshot_id
 WHERE cv.container_id = sqlc.arg(container_id)
   AND cv.version = sqlc.arg(version);
+
+-- name: DeleteVersionsBySnapshotIDs :exec
+DELETE FROM container_versions
+WHERE container_id = sqlc.arg(container_id)
+  AND snapshot_id = ANY(sqlc.arg(snapshot_ids)::uuid[]);
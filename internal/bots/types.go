@@ -49,6 +49,8 @@ type CreateBotRequest struct {
 	AvatarURL   string         `json:"avatar_url,omitempty"`
 	IsActive    *bool          `json:"is_active,omitempty"`
 	Metadata    map[string]any `json:"metadata,omitempty"`
+	// TemplateID applies an admin-managed bot template after creation.
+	TemplateID string `json:"template_id,omitempty"`
 }
 
 // UpdateBotRequest is the input for updating a bot.
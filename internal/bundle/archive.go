@@ -0,0 +1,222 @@
+package bundle
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	manifestName = "manifest.json"
+	entitiesName = "bot.json"
+	memoriesName = "memories.tar.gz"
+	dataPrefix   = "data/"
+)
+
+// writeBytes adds an in-memory file to the bundle.
+func writeBytes(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// writeDataTree adds the files below root to the bundle under data/. Regular
+// files, directories and symlinks are kept; sockets, devices and pipes are
+// skipped. A missing root yields an empty tree.
+func writeDataTree(tw *tar.Writer, root string) (int, error) {
+	if _, err := os.Stat(root); errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	files := 0
+	err := filepath.WalkDir(root, func(current string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if current == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, current)
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		name := dataPrefix + filepath.ToSlash(rel)
+		switch {
+		case info.IsDir():
+			header, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			header.Name = name + "/"
+			return tw.WriteHeader(header)
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(current)
+			if err != nil {
+				return err
+			}
+			header, err := tar.FileInfoHeader(info, target)
+			if err != nil {
+				return err
+			}
+			header.Name = name
+			return tw.WriteHeader(header)
+		case info.Mode().IsRegular():
+			header, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			header.Name = name
+			if err := writeFileContent(tw, header, current); err != nil {
+				return err
+			}
+			files++
+		}
+		return nil
+	})
+	return files, err
+}
+
+// writeFileContent copies a file into the bundle. A file that shrank after it
+// was stat'ed is padded with zeros, as the header size is already fixed.
+func writeFileContent(tw *tar.Writer, header *tar.Header, filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	written, err := io.CopyN(tw, file, header.Size)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if written < header.Size {
+		_, err = io.CopyN(tw, zeroReader{}, header.Size-written)
+	}
+	return err
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// dataEntryPath returns the slash-separated path of a data/ entry relative to
+// the data directory, or "" for the data/ directory itself. Entries outside
+// data/ or escaping it are rejected.
+func dataEntryPath(name string) (string, error) {
+	if !strings.HasPrefix(name, dataPrefix) {
+		return "", fmt.Errorf("%w: unexpected entry %q", ErrInvalidBundle, name)
+	}
+	rel := strings.TrimPrefix(name, dataPrefix)
+	for _, part := range strings.Split(rel, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("%w: entry %q escapes the data directory", ErrInvalidBundle, name)
+		}
+	}
+	cleaned := strings.TrimPrefix(path.Clean("/"+rel), "/")
+	return cleaned, nil
+}
+
+// extractDataEntry writes one data/ entry below root. Parent directories are
+// created as needed but never through a symlink, so a symlink in the bundle
+// cannot redirect later entries outside root.
+func extractDataEntry(root string, header *tar.Header, r io.Reader) (bool, error) {
+	rel, err := dataEntryPath(header.Name)
+	if err != nil || rel == "" {
+		return false, err
+	}
+	target, err := prepareParent(root, rel)
+	if err != nil {
+		return false, err
+	}
+	mode := header.FileInfo().Mode().Perm()
+	switch header.Typeflag {
+	case tar.TypeDir:
+		if info, err := os.Lstat(target); err == nil {
+			if info.IsDir() {
+				return false, nil
+			}
+			if err := os.Remove(target); err != nil {
+				return false, err
+			}
+		}
+		return false, os.Mkdir(target, mode|0o700)
+	case tar.TypeSymlink:
+		if err := removeExisting(target); err != nil {
+			return false, err
+		}
+		return false, os.Symlink(header.Linkname, target)
+	case tar.TypeReg:
+		if err := removeExisting(target); err != nil {
+			return false, err
+		}
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_EXCL, mode)
+		if err != nil {
+			return false, err
+		}
+		if _, err := io.Copy(file, r); err != nil {
+			_ = file.Close()
+			return false, err
+		}
+		return true, file.Close()
+	default:
+		return false, nil
+	}
+}
+
+func prepareParent(root, rel string) (string, error) {
+	parts := strings.Split(rel, "/")
+	current := root
+	for _, part := range parts[:len(parts)-1] {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			if err := os.Mkdir(current, 0o755); err != nil {
+				return "", err
+			}
+		case err != nil:
+			return "", err
+		case info.Mode()&fs.ModeSymlink != 0:
+			return "", fmt.Errorf("%w: entry %q crosses a symlink", ErrInvalidBundle, rel)
+		case !info.IsDir():
+			return "", fmt.Errorf("%w: entry %q is below a file", ErrInvalidBundle, rel)
+		}
+	}
+	return filepath.Join(current, parts[len(parts)-1]), nil
+}
+
+func removeExisting(target string) error {
+	info, err := os.Lstat(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%w: %s is a directory", ErrInvalidBundle, filepath.Base(target))
+	}
+	return os.Remove(target)
+}
@@ -0,0 +1,173 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/mcp"
+)
+
+func TestDataTreeRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "notes", "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "notes", "a.md"), []byte("hello"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("notes/a.md", filepath.Join(src, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	files, err := writeDataTree(tw, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if files != 1 {
+		t.Fatalf("files = %d, want 1", files)
+	}
+
+	dst := t.TempDir()
+	tr := tar.NewReader(&buf)
+	extracted := 0
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		written, err := extractDataEntry(dst, header, tr)
+		if err != nil {
+			t.Fatalf("extract %s: %v", header.Name, err)
+		}
+		if written {
+			extracted++
+		}
+	}
+	if extracted != 1 {
+		t.Fatalf("extracted = %d, want 1", extracted)
+	}
+	data, err := os.ReadFile(filepath.Join(dst, "notes", "a.md"))
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("a.md = %q, %v", data, err)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "notes", "a.md")); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("a.md mode = %v, %v", info.Mode(), err)
+	}
+	if target, err := os.Readlink(filepath.Join(dst, "link")); err != nil || target != "notes/a.md" {
+		t.Fatalf("link = %q, %v", target, err)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "notes", "empty")); err != nil || !info.IsDir() {
+		t.Fatalf("empty dir missing: %v", err)
+	}
+}
+
+func TestWriteDataTreeMissingRoot(t *testing.T) {
+	tw := tar.NewWriter(io.Discard)
+	files, err := writeDataTree(tw, filepath.Join(t.TempDir(), "missing"))
+	if err != nil || files != 0 {
+		t.Fatalf("got %d, %v; want 0, nil", files, err)
+	}
+}
+
+func TestExtractDataEntryRejectsEscapes(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"data/../evil", "data/a/../../evil", "memories/x", "data/escape/evil"} {
+		header := &tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: 4}
+		if _, err := extractDataEntry(root, header, bytes.NewReader([]byte("evil"))); !errors.Is(err, ErrInvalidBundle) {
+			t.Fatalf("%s: error = %v, want ErrInvalidBundle", name, err)
+		}
+	}
+	if entries, _ := os.ReadDir(outside); len(entries) != 0 {
+		t.Fatalf("files written outside root: %v", entries)
+	}
+}
+
+func TestReadHead(t *testing.T) {
+	build := func(manifest string) *tar.Reader {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		now := time.Now()
+		if err := writeBytes(tw, manifestName, []byte(manifest), now); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeBytes(tw, entitiesName, []byte(`{"bot":{"type":"public","display_name":"helper"}}`), now); err != nil {
+			t.Fatal(err)
+		}
+		if err := tw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return tar.NewReader(&buf)
+	}
+
+	manifest, entities, err := readHead(build(`{"format":"memoh.bot","version":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Version != 1 || entities.Bot.DisplayName != "helper" {
+		t.Fatalf("unexpected head: %+v %+v", manifest, entities.Bot)
+	}
+	for _, bad := range []string{`{"format":"memoh.memory","version":1}`, `{"format":"memoh.bot","version":2}`, `not json`} {
+		if _, _, err := readHead(build(bad)); !errors.Is(err, ErrInvalidBundle) {
+			t.Fatalf("%s: error = %v, want ErrInvalidBundle", bad, err)
+		}
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	entities := Entities{
+		Channels: []ChannelConfig{{ChannelType: "telegram", Credentials: map[string]any{"botToken": "secret"}}},
+		MCPServers: map[string]mcp.MCPServerEntry{
+			"search": {URL: "https://example.com/mcp", Headers: map[string]string{"Authorization": "Bearer secret"}},
+			"local":  {Command: "tool", Env: map[string]string{"API_KEY": "secret"}},
+		},
+	}
+	redactSecrets(&entities)
+	if entities.Channels[0].Credentials != nil {
+		t.Fatalf("credentials kept: %v", entities.Channels[0].Credentials)
+	}
+	if got := entities.MCPServers["search"].Headers; got["Authorization"] != "" || len(got) != 1 {
+		t.Fatalf("headers = %v", got)
+	}
+	if got := entities.MCPServers["local"].Env; got["API_KEY"] != "" || len(got) != 1 {
+		t.Fatalf("env = %v", got)
+	}
+}
+
+func TestPickModel(t *testing.T) {
+	id := func(b byte) pgtype.UUID { return pgtype.UUID{Bytes: [16]byte{b}, Valid: true} }
+	rows := []sqlc.Model{
+		{ID: id(1), ModelID: "gpt-4o", Type: "chat"},
+		{ID: id(2), ModelID: "gpt-4o", Type: "chat"},
+		{ID: id(3), ModelID: "text-embedding-3-small", Type: "embedding"},
+	}
+	if got := pickModel(rows, "embedding"); got != id(3) {
+		t.Fatalf("embedding = %v, want %v", got, id(3))
+	}
+	if got := pickModel(rows, "chat"); got.Valid {
+		t.Fatalf("ambiguous chat models resolved to %v", got)
+	}
+	if got := pickModel(nil, "chat"); got.Valid {
+		t.Fatalf("no rows resolved to %v", got)
+	}
+}
@@ -0,0 +1,140 @@
+package bundle
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/memohai/memoh/internal/bots"
+)
+
+// cloneSkippedDirs are top-level data directories that mirror the bot's
+// memories and therefore belong to its history.
+var cloneSkippedDirs = []string{"memory", "index"}
+
+type CloneOptions struct {
+	OwnerUserID string
+	// DisplayName defaults to the source bot's name with a " (copy)" suffix.
+	DisplayName string
+}
+
+// Clone creates a new bot with the settings, data files, MCP connections,
+// schedules and subagents of an existing one. Conversation history,
+// memories and channel configs, which hold credentials, are not copied.
+func (s *Service) Clone(ctx context.Context, sourceBotID string, opts CloneOptions) (clone bots.Bot, err error) {
+	entities, err := s.collectEntities(ctx, sourceBotID)
+	if err != nil {
+		return bots.Bot{}, err
+	}
+	displayName := strings.TrimSpace(opts.DisplayName)
+	if displayName == "" {
+		displayName = entities.Bot.DisplayName + " (copy)"
+	}
+	isActive := entities.Bot.IsActive
+	clone, err = s.bots.Create(ctx, opts.OwnerUserID, bots.CreateBotRequest{
+		Type:        entities.Bot.Type,
+		DisplayName: displayName,
+		AvatarURL:   entities.Bot.AvatarURL,
+		IsActive:    &isActive,
+		Metadata:    entities.Bot.Metadata,
+	})
+	if err != nil {
+		return bots.Bot{}, fmt.Errorf("create bot: %w", err)
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if deleteErr := s.bots.Delete(context.WithoutCancel(ctx), clone.ID); deleteErr != nil {
+			s.logger.Warn("delete partially cloned bot failed", slog.String("bot_id", clone.ID), slog.Any("error", deleteErr))
+		}
+	}()
+
+	var result ImportResult
+	if s.manager != nil {
+		sourceDir, err := s.manager.DataDir(sourceBotID)
+		if err != nil {
+			return bots.Bot{}, err
+		}
+		targetDir, err := s.manager.DataDir(clone.ID)
+		if err != nil {
+			return bots.Bot{}, err
+		}
+		if result.DataFiles, err = copyDataTree(sourceDir, targetDir); err != nil {
+			return bots.Bot{}, fmt.Errorf("copy data: %w", err)
+		}
+	}
+
+	entities.Channels = nil
+	for i := range entities.Subagents {
+		entities.Subagents[i].Messages = nil
+	}
+	if err := s.applyEntities(ctx, clone.ID, upsertRequest(entities.Settings.Settings), false, entities, &result); err != nil {
+		return bots.Bot{}, err
+	}
+	s.logger.Info("bot cloned",
+		slog.String("source_bot_id", sourceBotID),
+		slog.String("bot_id", clone.ID),
+		slog.Int("data_files", result.DataFiles),
+		slog.Int("mcp_servers", result.MCPServers),
+		slog.Int("schedules", result.Schedules),
+		slog.Int("subagents", result.Subagents),
+	)
+	return clone, nil
+}
+
+// copyDataTree copies the data directory of one bot into another's, leaving
+// out the memory mirror. It streams the tree through the same tar encoding
+// bundles use, so the copy gets the same symlink protections as an import.
+func copyDataTree(source, target string) (int, error) {
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		return 0, err
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(pw)
+		_, err := writeDataTree(tw, source)
+		if err == nil {
+			err = tw.Close()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	defer func() { _ = pr.Close() }()
+
+	tr := tar.NewReader(pr)
+	files := 0
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return files, err
+		}
+		if skipOnClone(header.Name) {
+			continue
+		}
+		written, err := extractDataEntry(target, header, tr)
+		if err != nil {
+			return files, fmt.Errorf("%s: %w", header.Name, err)
+		}
+		if written {
+			files++
+		}
+	}
+}
+
+func skipOnClone(name string) bool {
+	rel := strings.TrimPrefix(name, dataPrefix)
+	for _, dir := range cloneSkippedDirs {
+		if rel == dir || rel == dir+"/" || strings.HasPrefix(rel, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
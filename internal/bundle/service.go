@@ -0,0 +1,765 @@
+// Package bundle exports a bot as a portable tarball and imports it as a new
+// bot, possibly on another host. It also stamps out bots from admin-managed
+// templates and clones existing bots.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/mcp"
+	"github.com/memohai/memoh/internal/memory"
+	"github.com/memohai/memoh/internal/schedule"
+	"github.com/memohai/memoh/internal/settings"
+	"github.com/memohai/memoh/internal/subagent"
+)
+
+const (
+	memoryNamespace = "bot"
+	// maxEntitiesBytes bounds manifest.json and bot.json on import.
+	maxEntitiesBytes = 64 << 20
+	// maxExtractBytes bounds the uncompressed size of an imported bundle.
+	maxExtractBytes = 8 << 30
+)
+
+// ErrInvalidBundle indicates the uploaded bundle cannot be read.
+var ErrInvalidBundle = errors.New("invalid bot bundle")
+
+// Service exports and imports bot bundles.
+type Service struct {
+	queries     *sqlc.Queries
+	bots        *bots.Service
+	settings    *settings.Service
+	channels    *channel.Store
+	lifecycle   *channel.Lifecycle
+	connections *mcp.ConnectionService
+	schedules   *schedule.Service
+	subagents   *subagent.Service
+	memory      *memory.Service
+	memoryFS    *memory.MemoryFS
+	manager     *mcp.Manager
+	logger      *slog.Logger
+}
+
+func NewService(
+	log *slog.Logger,
+	queries *sqlc.Queries,
+	botService *bots.Service,
+	settingsService *settings.Service,
+	channelStore *channel.Store,
+	channelLifecycle *channel.Lifecycle,
+	connectionService *mcp.ConnectionService,
+	scheduleService *schedule.Service,
+	subagentService *subagent.Service,
+	memoryService *memory.Service,
+	memoryFS *memory.MemoryFS,
+	manager *mcp.Manager,
+) *Service {
+	return &Service{
+		queries:     queries,
+		bots:        botService,
+		settings:    settingsService,
+		channels:    channelStore,
+		lifecycle:   channelLifecycle,
+		connections: connectionService,
+		schedules:   scheduleService,
+		subagents:   subagentService,
+		memory:      memoryService,
+		memoryFS:    memoryFS,
+		manager:     manager,
+		logger:      log.With(slog.String("service", "bundle")),
+	}
+}
+
+// Export writes a bundle of the bot to w: its database entities, its
+// bot-shared memories and the tree of its data directory.
+func (s *Service) Export(ctx context.Context, w io.Writer, botID string, opts ExportOptions) (Manifest, error) {
+	entities, err := s.collectEntities(ctx, botID)
+	if err != nil {
+		return Manifest{}, err
+	}
+	if entities.Settings, err = s.exportSettings(ctx, entities.Settings.Settings); err != nil {
+		return Manifest{}, err
+	}
+	manifest := Manifest{
+		Format:          Format,
+		Version:         Version,
+		ExportedAt:      time.Now().UTC().Format(time.RFC3339),
+		BotID:           botID,
+		SecretsRedacted: opts.RedactSecrets,
+		IncludesVectors: opts.IncludeVectors,
+	}
+	if opts.RedactSecrets {
+		redactSecrets(&entities)
+	}
+
+	// The memory archive is spooled to disk; its size must be known before
+	// it is added to the tar.
+	memories, err := os.CreateTemp("", "memoh-bundle-memories-*.tar.gz")
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer func() {
+		_ = memories.Close()
+		_ = os.Remove(memories.Name())
+	}()
+	if s.memory != nil {
+		memoryManifest, err := s.memory.Export(ctx, memories, memory.ExportRequest{
+			BotID:          botID,
+			Filters:        memoryFilters(botID),
+			IncludeVectors: opts.IncludeVectors,
+		})
+		if err != nil {
+			return Manifest{}, fmt.Errorf("export memories: %w", err)
+		}
+		manifest.MemoryCount = memoryManifest.Count
+	}
+	dataDir := ""
+	if s.manager != nil {
+		if dataDir, err = s.manager.DataDir(botID); err != nil {
+			return Manifest{}, err
+		}
+	}
+
+	entitiesData, err := json.MarshalIndent(entities, "", "  ")
+	if err != nil {
+		return Manifest{}, err
+	}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	modTime := time.Now().UTC()
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return Manifest{}, err
+	}
+	if err := writeBytes(tw, manifestName, manifestData, modTime); err != nil {
+		return Manifest{}, err
+	}
+	if err := writeBytes(tw, entitiesName, entitiesData, modTime); err != nil {
+		return Manifest{}, err
+	}
+	if s.memory != nil {
+		info, err := memories.Stat()
+		if err != nil {
+			return Manifest{}, err
+		}
+		if _, err := memories.Seek(0, io.SeekStart); err != nil {
+			return Manifest{}, err
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: memoriesName, Mode: 0o644, Size: info.Size(), ModTime: modTime}); err != nil {
+			return Manifest{}, err
+		}
+		if _, err := io.Copy(tw, memories); err != nil {
+			return Manifest{}, err
+		}
+	}
+	if dataDir != "" {
+		if _, err := writeDataTree(tw, dataDir); err != nil {
+			return Manifest{}, fmt.Errorf("export data: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return Manifest{}, err
+	}
+	if err := gz.Close(); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
+
+// collectEntities reads the bot's database rows. Settings keep this host's
+// model and search provider IDs; Export replaces them with references.
+func (s *Service) collectEntities(ctx context.Context, botID string) (Entities, error) {
+	bot, err := s.bots.Get(ctx, botID)
+	if err != nil {
+		return Entities{}, err
+	}
+	entities := Entities{
+		Bot: Bot{
+			Type:        bot.Type,
+			DisplayName: bot.DisplayName,
+			AvatarURL:   bot.AvatarURL,
+			IsActive:    bot.IsActive,
+			Metadata:    bot.Metadata,
+		},
+		Channels:   []ChannelConfig{},
+		MCPServers: map[string]mcp.MCPServerEntry{},
+		Schedules:  []Schedule{},
+		Subagents:  []Subagent{},
+	}
+
+	botSettings, err := s.settings.GetBot(ctx, botID)
+	if err != nil {
+		return Entities{}, fmt.Errorf("get settings: %w", err)
+	}
+	entities.Settings = Settings{Settings: botSettings}
+
+	channelConfigs, err := s.channels.ListConfigsByBot(ctx, botID)
+	if err != nil {
+		return Entities{}, fmt.Errorf("list channel configs: %w", err)
+	}
+	for _, cfg := range channelConfigs {
+		entities.Channels = append(entities.Channels, ChannelConfig{
+			ChannelType:      cfg.ChannelType.String(),
+			Name:             cfg.Name,
+			Credentials:      cfg.Credentials,
+			ExternalIdentity: cfg.ExternalIdentity,
+			SelfIdentity:     cfg.SelfIdentity,
+			Routing:          cfg.Routing,
+			Disabled:         cfg.Disabled,
+		})
+	}
+
+	servers, err := s.connections.ExportByBot(ctx, botID)
+	if err != nil {
+		return Entities{}, fmt.Errorf("export mcp connections: %w", err)
+	}
+	entities.MCPServers = servers.MCPServers
+
+	schedules, err := s.schedules.List(ctx, botID)
+	if err != nil {
+		return Entities{}, fmt.Errorf("list schedules: %w", err)
+	}
+	for _, item := range schedules {
+		entities.Schedules = append(entities.Schedules, Schedule{
+			Name:        item.Name,
+			Description: item.Description,
+			Pattern:     item.Pattern,
+			MaxCalls:    item.MaxCalls,
+			Command:     item.Command,
+			Enabled:     item.Enabled,
+		})
+	}
+
+	subagents, err := s.subagents.List(ctx, botID)
+	if err != nil {
+		return Entities{}, fmt.Errorf("list subagents: %w", err)
+	}
+	for _, item := range subagents {
+		entities.Subagents = append(entities.Subagents, Subagent{
+			Name:        item.Name,
+			Description: item.Description,
+			Messages:    item.Messages,
+			Metadata:    item.Metadata,
+			Skills:      item.Skills,
+		})
+	}
+	return entities, nil
+}
+
+func (s *Service) exportSettings(ctx context.Context, current settings.Settings) (Settings, error) {
+	out := Settings{Settings: current}
+	var err error
+	if out.ChatModel, err = s.modelRef(ctx, current.ChatModelID); err != nil {
+		return Settings{}, err
+	}
+	if out.MemoryModel, err = s.modelRef(ctx, current.MemoryModelID); err != nil {
+		return Settings{}, err
+	}
+	if out.EmbeddingModel, err = s.modelRef(ctx, current.EmbeddingModelID); err != nil {
+		return Settings{}, err
+	}
+	if out.RerankModel, err = s.modelRef(ctx, current.RerankModelID); err != nil {
+		return Settings{}, err
+	}
+	if id := strings.TrimSpace(current.SearchProviderID); id != "" {
+		pgID, err := db.ParseUUID(id)
+		if err != nil {
+			return Settings{}, err
+		}
+		provider, err := s.queries.GetSearchProviderByID(ctx, pgID)
+		if err != nil {
+			return Settings{}, fmt.Errorf("get search provider: %w", err)
+		}
+		out.SearchProvider = provider.Name
+	}
+	// Host-specific IDs are replaced by the references above.
+	out.ChatModelID = ""
+	out.MemoryModelID = ""
+	out.EmbeddingModelID = ""
+	out.RerankModelID = ""
+	out.SearchProviderID = ""
+	return out, nil
+}
+
+func (s *Service) modelRef(ctx context.Context, id string) (*ModelRef, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, nil
+	}
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return nil, err
+	}
+	model, err := s.queries.GetModelByID(ctx, pgID)
+	if err != nil {
+		return nil, fmt.Errorf("get model: %w", err)
+	}
+	return &ModelRef{ModelID: model.ModelID, Name: model.Name.String, Type: model.Type}, nil
+}
+
+// Import creates a new bot from a bundle. The bot is deleted again when the
+// bundle cannot be applied; parts that depend on this host, like models and
+// channel credentials, are reported as warnings instead.
+func (s *Service) Import(ctx context.Context, r io.Reader, opts ImportOptions) (result ImportResult, err error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	defer func() { _ = gz.Close() }()
+	tr := tar.NewReader(gz)
+
+	manifest, entities, err := readHead(tr)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	result = ImportResult{Manifest: manifest, Warnings: []string{}}
+
+	displayName := strings.TrimSpace(opts.DisplayName)
+	if displayName == "" {
+		displayName = entities.Bot.DisplayName
+	}
+	isActive := entities.Bot.IsActive
+	bot, err := s.bots.Create(ctx, opts.OwnerUserID, bots.CreateBotRequest{
+		Type:        entities.Bot.Type,
+		DisplayName: displayName,
+		AvatarURL:   entities.Bot.AvatarURL,
+		IsActive:    &isActive,
+		Metadata:    entities.Bot.Metadata,
+	})
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("create bot: %w", err)
+	}
+	result.BotID = bot.ID
+	defer func() {
+		if err == nil {
+			return
+		}
+		if deleteErr := s.bots.Delete(context.WithoutCancel(ctx), bot.ID); deleteErr != nil {
+			s.logger.Warn("delete partially imported bot failed", slog.String("bot_id", bot.ID), slog.Any("error", deleteErr))
+		}
+	}()
+
+	memories, err := s.extractRest(tr, bot.ID, &result)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	if memories != "" {
+		defer func() { _ = os.Remove(memories) }()
+		if err := s.importMemories(ctx, memories, bot.ID, opts.EmbeddingEnabled, &result); err != nil {
+			return ImportResult{}, err
+		}
+	}
+	req, warnings, err := s.settingsRequest(ctx, entities.Settings)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	result.Warnings = append(result.Warnings, warnings...)
+	if err := s.applyEntities(ctx, bot.ID, req, manifest.SecretsRedacted, entities, &result); err != nil {
+		return ImportResult{}, err
+	}
+	return result, nil
+}
+
+// readHead reads manifest.json and bot.json, which lead every bundle.
+func readHead(tr *tar.Reader) (Manifest, Entities, error) {
+	var manifest Manifest
+	if err := readJSONEntry(tr, manifestName, &manifest); err != nil {
+		return Manifest{}, Entities{}, err
+	}
+	if manifest.Format != Format {
+		return Manifest{}, Entities{}, fmt.Errorf("%w: unexpected format %q", ErrInvalidBundle, manifest.Format)
+	}
+	if manifest.Version < 1 || manifest.Version > Version {
+		return Manifest{}, Entities{}, fmt.Errorf("%w: unsupported version %d", ErrInvalidBundle, manifest.Version)
+	}
+	var entities Entities
+	if err := readJSONEntry(tr, entitiesName, &entities); err != nil {
+		return Manifest{}, Entities{}, err
+	}
+	return manifest, entities, nil
+}
+
+func readJSONEntry(tr *tar.Reader, name string, v any) error {
+	header, err := tr.Next()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	if header.Name != name {
+		return fmt.Errorf("%w: expected %s, got %q", ErrInvalidBundle, name, header.Name)
+	}
+	data, err := io.ReadAll(io.LimitReader(tr, maxEntitiesBytes+1))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	if len(data) > maxEntitiesBytes {
+		return fmt.Errorf("%w: %s is too large", ErrInvalidBundle, name)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidBundle, name, err)
+	}
+	return nil
+}
+
+// extractRest restores the data tree into the bot's data directory and spools
+// the memory archive to a temporary file, whose path it returns.
+func (s *Service) extractRest(tr *tar.Reader, botID string, result *ImportResult) (string, error) {
+	dataDir := ""
+	if s.manager != nil {
+		var err error
+		if dataDir, err = s.manager.DataDir(botID); err != nil {
+			return "", err
+		}
+		if err := os.MkdirAll(dataDir, 0o755); err != nil {
+			return "", err
+		}
+	}
+	memories := ""
+	budget := int64(maxExtractBytes)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return memories, nil
+		}
+		if err != nil {
+			return memories, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+		}
+		if header.Size > budget {
+			return memories, fmt.Errorf("%w: bundle exceeds %d bytes", ErrInvalidBundle, int64(maxExtractBytes))
+		}
+		budget -= header.Size
+		switch {
+		case header.Name == memoriesName:
+			if memories, err = spool(tr); err != nil {
+				return memories, err
+			}
+		case strings.HasPrefix(header.Name, dataPrefix):
+			if dataDir == "" {
+				continue
+			}
+			written, err := extractDataEntry(dataDir, header, tr)
+			if err != nil {
+				return memories, fmt.Errorf("extract %s: %w", header.Name, err)
+			}
+			if written {
+				result.DataFiles++
+			}
+		default:
+			return memories, fmt.Errorf("%w: unexpected entry %q", ErrInvalidBundle, header.Name)
+		}
+	}
+}
+
+func spool(r io.Reader) (string, error) {
+	file, err := os.CreateTemp("", "memoh-bundle-memories-*.tar.gz")
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+func (s *Service) importMemories(ctx context.Context, archivePath, botID string, embeddingEnabled bool, result *ImportResult) error {
+	if s.memory == nil {
+		result.Warnings = append(result.Warnings, "memories were not imported: memory service not configured")
+		return nil
+	}
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	filters := memoryFilters(botID)
+	imported, err := s.memory.Import(ctx, file, memory.ImportRequest{
+		BotID:            botID,
+		Filters:          filters,
+		EmbeddingEnabled: embeddingEnabled,
+	})
+	if err != nil {
+		return fmt.Errorf("import memories: %w", err)
+	}
+	result.Memories = &imported
+	if s.memoryFS != nil && len(imported.Items) > 0 {
+		// Memories may get new IDs on import, so the mirrored files restored
+		// with the data tree are rebuilt once the container is up.
+		items := imported.Items
+		go func() {
+			bgCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+			defer cancel()
+			if err := s.memoryFS.RebuildFiles(bgCtx, botID, items, filters); err != nil {
+				s.logger.Warn("rebuild imported memory files failed", slog.String("bot_id", botID), slog.Any("error", err))
+			}
+		}()
+	}
+	return nil
+}
+
+// applyEntities stores settings, MCP connections, schedules, subagents and
+// channel configs on a freshly created bot.
+func (s *Service) applyEntities(ctx context.Context, botID string, req settings.UpsertRequest, secretsRedacted bool, entities Entities, result *ImportResult) error {
+	if _, err := s.settings.UpsertBot(ctx, botID, req); err != nil {
+		return fmt.Errorf("apply settings: %w", err)
+	}
+
+	if len(entities.MCPServers) > 0 {
+		connections, err := s.connections.Import(ctx, botID, mcp.ImportRequest{MCPServers: entities.MCPServers})
+		if err != nil {
+			return fmt.Errorf("import mcp connections: %w", err)
+		}
+		result.MCPServers = len(connections)
+		if secretsRedacted {
+			for name, entry := range entities.MCPServers {
+				if len(entry.Env) > 0 || len(entry.Headers) > 0 {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("mcp server %q: env and header values were redacted", name))
+				}
+			}
+		}
+	}
+
+	for _, item := range entities.Schedules {
+		enabled := item.Enabled
+		if _, err := s.schedules.Create(ctx, botID, schedule.CreateRequest{
+			Name:        item.Name,
+			Description: item.Description,
+			Pattern:     item.Pattern,
+			MaxCalls:    schedule.NullableInt{Value: item.MaxCalls, Set: true},
+			Command:     item.Command,
+			Enabled:     &enabled,
+		}); err != nil {
+			return fmt.Errorf("import schedule %q: %w", item.Name, err)
+		}
+		result.Schedules++
+	}
+
+	for _, item := range entities.Subagents {
+		if _, err := s.subagents.Create(ctx, botID, subagent.CreateRequest{
+			Name:        item.Name,
+			Description: item.Description,
+			Messages:    item.Messages,
+			Metadata:    item.Metadata,
+			Skills:      item.Skills,
+		}); err != nil {
+			return fmt.Errorf("import subagent %q: %w", item.Name, err)
+		}
+		result.Subagents++
+	}
+
+	// Channels connect to external services, so they go last and a failure
+	// only skips the channel.
+	for _, item := range entities.Channels {
+		label := item.ChannelType
+		if item.Name != "" {
+			label += " (" + item.Name + ")"
+		}
+		if len(item.Credentials) == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("channel %s: credentials were redacted, configure it again", label))
+			continue
+		}
+		disabled := item.Disabled
+		if _, err := s.lifecycle.UpsertBotChannelConfig(ctx, botID, channel.ChannelType(item.ChannelType), item.Name, channel.UpsertConfigRequest{
+			Credentials:      item.Credentials,
+			ExternalIdentity: item.ExternalIdentity,
+			SelfIdentity:     item.SelfIdentity,
+			Routing:          item.Routing,
+			Disabled:         &disabled,
+		}); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("channel %s: %v", label, err))
+			continue
+		}
+		result.Channels++
+	}
+	return nil
+}
+
+// settingsRequest turns exported settings into an upsert request, re-binding
+// models and the search provider by name. Unknown references are left unset
+// and reported.
+func (s *Service) settingsRequest(ctx context.Context, in Settings) (settings.UpsertRequest, []string, error) {
+	req := upsertRequest(in.Settings)
+	// IDs from another host are meaningless here; only references count.
+	req.ChatModelID = ""
+	req.MemoryModelID = ""
+	req.EmbeddingModelID = ""
+	req.RerankModelID = ""
+	req.SearchProviderID = ""
+	var warnings []string
+	for _, slot := range []struct {
+		name   string
+		ref    *ModelRef
+		target *string
+	}{
+		{"chat model", in.ChatModel, &req.ChatModelID},
+		{"memory model", in.MemoryModel, &req.MemoryModelID},
+		{"embedding model", in.EmbeddingModel, &req.EmbeddingModelID},
+		{"rerank model", in.RerankModel, &req.RerankModelID},
+	} {
+		if slot.ref == nil {
+			continue
+		}
+		id, err := s.resolveModel(ctx, *slot.ref)
+		if err != nil {
+			return settings.UpsertRequest{}, nil, err
+		}
+		if id == "" {
+			warnings = append(warnings, fmt.Sprintf("%s %q not found on this host", slot.name, slot.ref.ModelID))
+			continue
+		}
+		*slot.target = id
+	}
+	if req.RerankModelID == "" && in.RerankModel != nil {
+		disabled := false
+		req.RerankEnabled = &disabled
+	}
+	if name := strings.TrimSpace(in.SearchProvider); name != "" {
+		provider, err := s.queries.GetSearchProviderByName(ctx, name)
+		switch {
+		case err == nil:
+			req.SearchProviderID = provider.ID.String()
+		case errors.Is(err, pgx.ErrNoRows):
+			warnings = append(warnings, fmt.Sprintf("search provider %q not found on this host", name))
+		default:
+			return settings.UpsertRequest{}, nil, err
+		}
+	}
+	return req, warnings, nil
+}
+
+// upsertRequest turns settings into a request that stores all of them,
+// including this host's model and search provider IDs.
+func upsertRequest(current settings.Settings) settings.UpsertRequest {
+	return settings.UpsertRequest{
+		ChatModelID:           current.ChatModelID,
+		MemoryModelID:         current.MemoryModelID,
+		EmbeddingModelID:      current.EmbeddingModelID,
+		SearchProviderID:      current.SearchProviderID,
+		RerankModelID:         current.RerankModelID,
+		MaxContextLoadTime:    &current.MaxContextLoadTime,
+		MaxContextTokens:      &current.MaxContextTokens,
+		MaxInboxItems:         &current.MaxInboxItems,
+		Language:              current.Language,
+		AllowGuest:            &current.AllowGuest,
+		ReasoningEnabled:      &current.ReasoningEnabled,
+		ReasoningEffort:       &current.ReasoningEffort,
+		RerankEnabled:         &current.RerankEnabled,
+		RerankTopN:            &current.RerankTopN,
+		MemoryScope:           &current.MemoryScope,
+		CompactionCron:        &current.CompactionCron,
+		CompactionRatio:       &current.CompactionRatio,
+		CompactionDecayDays:   &current.CompactionDecayDays,
+		CompactionMaxMemories: &current.CompactionMaxMemories,
+		ContainerCPUShares:    &current.ContainerCPUShares,
+		ContainerCPULimit:     &current.ContainerCPULimit,
+		ContainerMemoryMB:     &current.ContainerMemoryMB,
+		ContainerPidsLimit:    &current.ContainerPidsLimit,
+		ContainerDiskQuotaMB:  &current.ContainerDiskQuotaMB,
+		NetworkMode:           &current.NetworkMode,
+		NetworkAllowlist:      current.NetworkAllowlist,
+		SnapshotCron:          &current.SnapshotCron,
+		SnapshotBeforeRisky:   &current.SnapshotBeforeRisky,
+		SnapshotKeepLast:      &current.SnapshotKeepLast,
+		SnapshotKeepDaily:     &current.SnapshotKeepDaily,
+	}
+}
+
+// resolveModel finds the model a reference points to, first by provider
+// model ID and then by display name. It returns "" when nothing matches
+// unambiguously.
+func (s *Service) resolveModel(ctx context.Context, ref ModelRef) (string, error) {
+	if modelID := strings.TrimSpace(ref.ModelID); modelID != "" {
+		rows, err := s.queries.ListModelsByModelID(ctx, modelID)
+		if err != nil {
+			return "", err
+		}
+		if id := pickModel(rows, ref.Type); id.Valid {
+			return id.String(), nil
+		}
+	}
+	if name := strings.TrimSpace(ref.Name); name != "" {
+		rows, err := s.queries.ListModels(ctx)
+		if err != nil {
+			return "", err
+		}
+		var named []sqlc.Model
+		for _, row := range rows {
+			if strings.EqualFold(strings.TrimSpace(row.Name.String), name) {
+				named = append(named, row)
+			}
+		}
+		if id := pickModel(named, ref.Type); id.Valid {
+			return id.String(), nil
+		}
+	}
+	return "", nil
+}
+
+// pickModel returns the single candidate of the given type, if any.
+func pickModel(rows []sqlc.Model, modelType string) pgtype.UUID {
+	var match pgtype.UUID
+	count := 0
+	for _, row := range rows {
+		if modelType != "" && row.Type != modelType {
+			continue
+		}
+		match = row.ID
+		count++
+	}
+	if count != 1 {
+		return pgtype.UUID{}
+	}
+	return match
+}
+
+// redactSecrets drops channel credentials and blanks MCP env and header
+// values, keeping their names so they can be filled in after import.
+func redactSecrets(entities *Entities) {
+	for i := range entities.Channels {
+		entities.Channels[i].Credentials = nil
+	}
+	for name, entry := range entities.MCPServers {
+		entry.Env = blankValues(entry.Env)
+		entry.Headers = blankValues(entry.Headers)
+		entities.MCPServers[name] = entry
+	}
+}
+
+func blankValues(values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return values
+	}
+	out := make(map[string]string, len(values))
+	for key := range values {
+		out[key] = ""
+	}
+	return out
+}
+
+func memoryFilters(botID string) map[string]any {
+	return map[string]any{
+		"namespace": memoryNamespace,
+		"scopeId":   botID,
+	}
+}
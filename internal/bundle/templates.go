@@ -0,0 +1,313 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/mcp"
+	"github.com/memohai/memoh/internal/settings"
+)
+
+var (
+	// ErrTemplateNotFound indicates the requested bot template does not exist.
+	ErrTemplateNotFound = errors.New("bot template not found")
+	// ErrTemplateNameExists indicates another template already uses the name.
+	ErrTemplateNameExists = errors.New("bot template name already exists")
+	// ErrInvalidTemplate indicates the template cannot be stored or applied.
+	ErrInvalidTemplate = errors.New("invalid bot template")
+)
+
+// Template is an admin-managed blueprint for new bots.
+type Template struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Spec        TemplateSpec `json:"spec"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// TemplateSpec is what a template applies to a new bot.
+type TemplateSpec struct {
+	// Files maps paths relative to the data directory, such as IDENTITY.md or
+	// SOUL.md, to their content. They replace the defaults from the MCP
+	// template.
+	Files  map[string]string `json:"files,omitempty"`
+	Skills []TemplateSkill   `json:"skills,omitempty"`
+	// Settings is applied as-is; model fields accept a UUID or a model_id.
+	Settings   settings.UpsertRequest        `json:"settings"`
+	MCPServers map[string]mcp.MCPServerEntry `json:"mcp_servers,omitempty"`
+	Schedules  []Schedule                    `json:"schedules,omitempty"`
+	Subagents  []Subagent                    `json:"subagents,omitempty"`
+}
+
+// TemplateSkill is written to .skills/<name>/SKILL.md. Without content, a
+// stub is generated from the name and description.
+type TemplateSkill struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Content     string `json:"content,omitempty"`
+}
+
+type TemplateRequest struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Spec        TemplateSpec `json:"spec"`
+}
+
+// TemplateResult summarizes what a template applied to a bot.
+type TemplateResult struct {
+	TemplateID string `json:"template_id"`
+	Files      int    `json:"files"`
+	MCPServers int    `json:"mcp_servers"`
+	Schedules  int    `json:"schedules"`
+	Subagents  int    `json:"subagents"`
+}
+
+func (s *Service) CreateTemplate(ctx context.Context, req TemplateRequest) (Template, error) {
+	name, spec, err := normalizeTemplate(req)
+	if err != nil {
+		return Template{}, err
+	}
+	row, err := s.queries.CreateBotTemplate(ctx, sqlc.CreateBotTemplateParams{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		Spec:        spec,
+	})
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return Template{}, ErrTemplateNameExists
+		}
+		return Template{}, fmt.Errorf("create bot template: %w", err)
+	}
+	return toTemplate(row)
+}
+
+func (s *Service) GetTemplate(ctx context.Context, id string) (Template, error) {
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return Template{}, ErrTemplateNotFound
+	}
+	row, err := s.queries.GetBotTemplateByID(ctx, pgID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Template{}, ErrTemplateNotFound
+		}
+		return Template{}, err
+	}
+	return toTemplate(row)
+}
+
+func (s *Service) ListTemplates(ctx context.Context) ([]Template, error) {
+	rows, err := s.queries.ListBotTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Template, 0, len(rows))
+	for _, row := range rows {
+		item, err := toTemplate(row)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (s *Service) UpdateTemplate(ctx context.Context, id string, req TemplateRequest) (Template, error) {
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return Template{}, ErrTemplateNotFound
+	}
+	name, spec, err := normalizeTemplate(req)
+	if err != nil {
+		return Template{}, err
+	}
+	row, err := s.queries.UpdateBotTemplate(ctx, sqlc.UpdateBotTemplateParams{
+		ID:          pgID,
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		Spec:        spec,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return Template{}, ErrTemplateNotFound
+		case db.IsUniqueViolation(err):
+			return Template{}, ErrTemplateNameExists
+		}
+		return Template{}, fmt.Errorf("update bot template: %w", err)
+	}
+	return toTemplate(row)
+}
+
+func (s *Service) DeleteTemplate(ctx context.Context, id string) error {
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return ErrTemplateNotFound
+	}
+	return s.queries.DeleteBotTemplate(ctx, pgID)
+}
+
+// ApplyTemplate writes the template's files and skills into the bot's data
+// directory and stores its settings, MCP connections, schedules and
+// subagents. It is meant for bots that were just created.
+func (s *Service) ApplyTemplate(ctx context.Context, botID, templateID string) (TemplateResult, error) {
+	tmpl, err := s.GetTemplate(ctx, templateID)
+	if err != nil {
+		return TemplateResult{}, err
+	}
+	result := TemplateResult{TemplateID: tmpl.ID}
+	files, err := templateFiles(tmpl.Spec)
+	if err != nil {
+		return TemplateResult{}, err
+	}
+	if s.manager != nil && len(files) > 0 {
+		dataDir, err := s.manager.DataDir(botID)
+		if err != nil {
+			return TemplateResult{}, err
+		}
+		if err := os.MkdirAll(dataDir, 0o755); err != nil {
+			return TemplateResult{}, err
+		}
+		for rel, content := range files {
+			if err := writeTemplateFile(dataDir, rel, content); err != nil {
+				return TemplateResult{}, fmt.Errorf("write %s: %w", rel, err)
+			}
+			result.Files++
+		}
+	}
+
+	var applied ImportResult
+	if err := s.applyEntities(ctx, botID, tmpl.Spec.Settings, false, Entities{
+		MCPServers: tmpl.Spec.MCPServers,
+		Schedules:  tmpl.Spec.Schedules,
+		Subagents:  tmpl.Spec.Subagents,
+	}, &applied); err != nil {
+		return TemplateResult{}, err
+	}
+	result.MCPServers = applied.MCPServers
+	result.Schedules = applied.Schedules
+	result.Subagents = applied.Subagents
+	s.logger.Info("bot template applied", slog.String("bot_id", botID), slog.String("template_id", tmpl.ID))
+	return result, nil
+}
+
+func normalizeTemplate(req TemplateRequest) (string, []byte, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", nil, fmt.Errorf("%w: name is required", ErrInvalidTemplate)
+	}
+	if _, err := templateFiles(req.Spec); err != nil {
+		return "", nil, err
+	}
+	for _, item := range req.Spec.Schedules {
+		if strings.TrimSpace(item.Name) == "" || strings.TrimSpace(item.Pattern) == "" || strings.TrimSpace(item.Command) == "" {
+			return "", nil, fmt.Errorf("%w: schedules need a name, pattern and command", ErrInvalidTemplate)
+		}
+	}
+	for _, item := range req.Spec.Subagents {
+		if strings.TrimSpace(item.Name) == "" {
+			return "", nil, fmt.Errorf("%w: subagents need a name", ErrInvalidTemplate)
+		}
+	}
+	spec, err := json.Marshal(req.Spec)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, spec, nil
+}
+
+// templateFiles returns the files a template writes, keyed by their cleaned
+// path relative to the data directory, with skills rendered to SKILL.md.
+func templateFiles(spec TemplateSpec) (map[string]string, error) {
+	files := make(map[string]string, len(spec.Files)+len(spec.Skills))
+	for name, content := range spec.Files {
+		rel, err := templateFilePath(name)
+		if err != nil {
+			return nil, err
+		}
+		files[rel] = content
+	}
+	for _, skill := range spec.Skills {
+		name := strings.TrimSpace(skill.Name)
+		if name == "" || name == "." || strings.Contains(name, "..") || strings.ContainsAny(name, `/\`) {
+			return nil, fmt.Errorf("%w: invalid skill name %q", ErrInvalidTemplate, skill.Name)
+		}
+		content := strings.TrimSpace(skill.Content)
+		if content == "" {
+			content = skillStub(name, strings.TrimSpace(skill.Description))
+		}
+		files[".skills/"+name+"/SKILL.md"] = content
+	}
+	return files, nil
+}
+
+// templateFilePath validates a template file path and returns it cleaned.
+func templateFilePath(name string) (string, error) {
+	name = strings.TrimSpace(strings.ReplaceAll(name, `\`, "/"))
+	if name == "" || strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("%w: file path %q must be relative to the data directory", ErrInvalidTemplate, name)
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("%w: file path %q escapes the data directory", ErrInvalidTemplate, name)
+		}
+	}
+	rel := path.Clean(name)
+	if rel == "." {
+		return "", fmt.Errorf("%w: file path %q names the data directory", ErrInvalidTemplate, name)
+	}
+	return rel, nil
+}
+
+// writeTemplateFile replaces one file below root, refusing to follow
+// symlinks on the way.
+func writeTemplateFile(root, rel, content string) error {
+	target, err := prepareParent(root, rel)
+	if err != nil {
+		return err
+	}
+	if err := removeExisting(target); err != nil {
+		return err
+	}
+	return os.WriteFile(target, []byte(content), 0o644)
+}
+
+func skillStub(name, description string) string {
+	if description == "" {
+		description = name
+	}
+	return "---\nname: " + name + "\ndescription: " + description + "\n---\n\n# " + name + "\n\n" + description
+}
+
+func toTemplate(row sqlc.BotTemplate) (Template, error) {
+	item := Template{
+		ID:          row.ID.String(),
+		Name:        row.Name,
+		Description: row.Description,
+	}
+	if len(row.Spec) > 0 {
+		if err := json.Unmarshal(row.Spec, &item.Spec); err != nil {
+			return Template{}, fmt.Errorf("decode bot template %s: %w", item.ID, err)
+		}
+	}
+	if row.CreatedAt.Valid {
+		item.CreatedAt = row.CreatedAt.Time
+	}
+	if row.UpdatedAt.Valid {
+		item.UpdatedAt = row.UpdatedAt.Time
+	}
+	return item, nil
+}
@@ -0,0 +1,118 @@
+package bundle
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateFiles(t *testing.T) {
+	files, err := templateFiles(TemplateSpec{
+		Files: map[string]string{"IDENTITY.md": "I am a helper", "./notes//todo.md": "- one"},
+		Skills: []TemplateSkill{
+			{Name: "search", Content: "custom"},
+			{Name: "summarize", Description: "Summarize text"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files["IDENTITY.md"] != "I am a helper" || files["notes/todo.md"] != "- one" {
+		t.Fatalf("files = %v", files)
+	}
+	if files[".skills/search/SKILL.md"] != "custom" {
+		t.Fatalf("search skill = %q", files[".skills/search/SKILL.md"])
+	}
+	if stub := files[".skills/summarize/SKILL.md"]; !strings.Contains(stub, "description: Summarize text") {
+		t.Fatalf("summarize skill = %q", stub)
+	}
+
+	for _, spec := range []TemplateSpec{
+		{Files: map[string]string{"../etc/passwd": "x"}},
+		{Files: map[string]string{"/etc/passwd": "x"}},
+		{Files: map[string]string{"a/../../b": "x"}},
+		{Files: map[string]string{".": "x"}},
+		{Skills: []TemplateSkill{{Name: "../escape"}}},
+		{Skills: []TemplateSkill{{Name: " "}}},
+	} {
+		if _, err := templateFiles(spec); !errors.Is(err, ErrInvalidTemplate) {
+			t.Fatalf("%+v: error = %v, want ErrInvalidTemplate", spec, err)
+		}
+	}
+}
+
+func TestNormalizeTemplate(t *testing.T) {
+	if _, _, err := normalizeTemplate(TemplateRequest{Name: " "}); !errors.Is(err, ErrInvalidTemplate) {
+		t.Fatalf("blank name: error = %v", err)
+	}
+	if _, _, err := normalizeTemplate(TemplateRequest{Name: "daily", Spec: TemplateSpec{Schedules: []Schedule{{Name: "digest"}}}}); !errors.Is(err, ErrInvalidTemplate) {
+		t.Fatalf("incomplete schedule: error = %v", err)
+	}
+	name, spec, err := normalizeTemplate(TemplateRequest{Name: " helper ", Spec: TemplateSpec{Files: map[string]string{"SOUL.md": "calm"}}})
+	if err != nil || name != "helper" || !strings.Contains(string(spec), `"SOUL.md":"calm"`) {
+		t.Fatalf("got %q %s %v", name, spec, err)
+	}
+}
+
+func TestWriteTemplateFileRefusesSymlinks(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeTemplateFile(root, "escape/IDENTITY.md", "x"); err == nil {
+		t.Fatal("expected an error writing through a symlink")
+	}
+	if err := writeTemplateFile(root, "IDENTITY.md", "old"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeTemplateFile(root, "IDENTITY.md", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "IDENTITY.md")); err != nil || string(data) != "new" {
+		t.Fatalf("IDENTITY.md = %q, %v", data, err)
+	}
+	if entries, _ := os.ReadDir(outside); len(entries) != 0 {
+		t.Fatalf("files written outside root: %v", entries)
+	}
+}
+
+func TestCopyDataTreeSkipsMemories(t *testing.T) {
+	source := t.TempDir()
+	for name, content := range map[string]string{
+		"IDENTITY.md":             "me",
+		".skills/search/SKILL.md": "skill",
+		"memory/abc.md":           "remembered",
+		"index/manifest.json":     "{}",
+		"memory-notes/keep.md":    "kept",
+	} {
+		target := filepath.Join(source, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	target := filepath.Join(t.TempDir(), "clone")
+	files, err := copyDataTree(source, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files != 3 {
+		t.Fatalf("files = %d, want 3", files)
+	}
+	for _, name := range []string{"IDENTITY.md", ".skills/search/SKILL.md", "memory-notes/keep.md"} {
+		if _, err := os.Stat(filepath.Join(target, filepath.FromSlash(name))); err != nil {
+			t.Fatalf("%s missing: %v", name, err)
+		}
+	}
+	for _, name := range []string{"memory", "index"} {
+		if _, err := os.Stat(filepath.Join(target, name)); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("%s copied: %v", name, err)
+		}
+	}
+}
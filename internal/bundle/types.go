@@ -0,0 +1,120 @@
+package bundle
+
+import (
+	"github.com/memohai/memoh/internal/mcp"
+	"github.com/memohai/memoh/internal/memory"
+	"github.com/memohai/memoh/internal/settings"
+)
+
+const (
+	// Format identifies bot bundles in the manifest.
+	Format = "memoh.bot"
+	// Version is the current bundle layout version.
+	Version = 1
+)
+
+// Manifest describes a bot bundle. It is the first entry of the gzipped tar,
+// followed by bot.json, memories.tar.gz and the data/ tree.
+type Manifest struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	ExportedAt string `json:"exported_at"`
+	BotID      string `json:"bot_id"`
+	// SecretsRedacted is set when channel credentials and MCP env/header
+	// values were left out of the bundle.
+	SecretsRedacted bool `json:"secrets_redacted"`
+	MemoryCount     int  `json:"memory_count"`
+	IncludesVectors bool `json:"includes_vectors"`
+}
+
+// Entities is the JSON dump of a bot's database rows, stored as bot.json.
+type Entities struct {
+	Bot        Bot                           `json:"bot"`
+	Settings   Settings                      `json:"settings"`
+	Channels   []ChannelConfig               `json:"channels"`
+	MCPServers map[string]mcp.MCPServerEntry `json:"mcp_servers"`
+	Schedules  []Schedule                    `json:"schedules"`
+	Subagents  []Subagent                    `json:"subagents"`
+}
+
+type Bot struct {
+	Type        string         `json:"type"`
+	DisplayName string         `json:"display_name"`
+	AvatarURL   string         `json:"avatar_url,omitempty"`
+	IsActive    bool           `json:"is_active"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+}
+
+// Settings are the bot settings with model and search provider IDs replaced
+// by references that can be resolved on another host.
+type Settings struct {
+	settings.Settings
+	ChatModel      *ModelRef `json:"chat_model,omitempty"`
+	MemoryModel    *ModelRef `json:"memory_model,omitempty"`
+	EmbeddingModel *ModelRef `json:"embedding_model,omitempty"`
+	RerankModel    *ModelRef `json:"rerank_model,omitempty"`
+	SearchProvider string    `json:"search_provider,omitempty"`
+}
+
+// ModelRef identifies a model by its provider model ID and display name.
+type ModelRef struct {
+	ModelID string `json:"model_id"`
+	Name    string `json:"name,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+type ChannelConfig struct {
+	ChannelType string `json:"channel_type"`
+	Name        string `json:"name,omitempty"`
+	// Credentials is omitted when secrets are redacted.
+	Credentials      map[string]any `json:"credentials,omitempty"`
+	ExternalIdentity string         `json:"external_identity,omitempty"`
+	SelfIdentity     map[string]any `json:"self_identity,omitempty"`
+	Routing          map[string]any `json:"routing,omitempty"`
+	Disabled         bool           `json:"disabled"`
+}
+
+type Schedule struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Pattern     string `json:"pattern"`
+	MaxCalls    *int   `json:"max_calls,omitempty"`
+	Command     string `json:"command"`
+	Enabled     bool   `json:"enabled"`
+}
+
+type Subagent struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Messages    []map[string]any `json:"messages,omitempty"`
+	Metadata    map[string]any   `json:"metadata,omitempty"`
+	Skills      []string         `json:"skills,omitempty"`
+}
+
+type ExportOptions struct {
+	RedactSecrets  bool
+	IncludeVectors bool
+}
+
+type ImportOptions struct {
+	OwnerUserID string
+	// DisplayName overrides the display name recorded in the bundle.
+	DisplayName string
+	// EmbeddingEnabled re-embeds memories whose vectors are missing or were
+	// produced by another model.
+	EmbeddingEnabled bool
+}
+
+type ImportResult struct {
+	BotID      string               `json:"bot_id"`
+	Manifest   Manifest             `json:"manifest"`
+	DataFiles  int                  `json:"data_files"`
+	Channels   int                  `json:"channels"`
+	MCPServers int                  `json:"mcp_servers"`
+	Schedules  int                  `json:"schedules"`
+	Subagents  int                  `json:"subagents"`
+	Memories   *memory.ImportResult `json:"memories,omitempty"`
+	// Warnings lists parts of the bundle that could not be applied, such as
+	// models missing on this host or channels whose credentials were redacted.
+	Warnings []string `json:"warnings"`
+}
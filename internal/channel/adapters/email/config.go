@@ -0,0 +1,302 @@
+package email
+
+import (
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// Connection security modes for IMAP and SMTP.
+const (
+	securityTLS      = "tls"
+	securityStartTLS = "starttls"
+	securityNone     = "none"
+)
+
+// Trigger rules deciding which inbound mail starts a chat turn. Mail that
+// does not trigger lands in the bot inbox.
+const (
+	// TriggerAll starts a chat turn for every mail.
+	TriggerAll = "all"
+	// TriggerDirect starts a chat turn for mail addressed to the bot in To
+	// and for replies to mail the bot sent.
+	TriggerDirect = "direct"
+	// TriggerInbox never starts a chat turn.
+	TriggerInbox = "inbox"
+)
+
+const (
+	defaultMailbox      = "INBOX"
+	defaultPollInterval = 60 * time.Second
+	minPollInterval     = 10 * time.Second
+)
+
+// Config holds the mailbox settings extracted from a channel configuration.
+type Config struct {
+	IMAPHost     string
+	IMAPPort     int
+	IMAPSecurity string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPSecurity string
+	Username     string
+	Password     string
+	// Address is the bot's email address, used as From and to recognize
+	// mail addressed to the bot. It defaults to Username.
+	Address      string
+	FromName     string
+	Mailbox      string
+	PollInterval time.Duration
+	Trigger      string
+}
+
+// UserConfig holds the address used to target an email user.
+type UserConfig struct {
+	Address string
+}
+
+func normalizeConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]any{
+		"imapHost":     cfg.IMAPHost,
+		"imapPort":     cfg.IMAPPort,
+		"imapSecurity": cfg.IMAPSecurity,
+		"smtpHost":     cfg.SMTPHost,
+		"smtpPort":     cfg.SMTPPort,
+		"smtpSecurity": cfg.SMTPSecurity,
+		"username":     cfg.Username,
+		"password":     cfg.Password,
+		"address":      cfg.Address,
+		"mailbox":      cfg.Mailbox,
+		"pollInterval": int(cfg.PollInterval / time.Second),
+		"trigger":      cfg.Trigger,
+	}
+	if cfg.FromName != "" {
+		result["fromName"] = cfg.FromName
+	}
+	return result, nil
+}
+
+func normalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"address": cfg.Address}, nil
+}
+
+func resolveTarget(raw map[string]any) (string, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return "", err
+	}
+	return cfg.Address, nil
+}
+
+func matchBinding(raw map[string]any, criteria channel.BindingCriteria) bool {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return false
+	}
+	if value := normalizeAddress(criteria.Attribute("email")); value != "" && value == cfg.Address {
+		return true
+	}
+	return criteria.SubjectID != "" && normalizeAddress(criteria.SubjectID) == cfg.Address
+}
+
+func buildUserConfig(identity channel.Identity) map[string]any {
+	result := map[string]any{}
+	if value := normalizeAddress(identity.Attribute("email")); value != "" {
+		result["address"] = value
+	}
+	return result
+}
+
+func parseConfig(raw map[string]any) (Config, error) {
+	username := strings.TrimSpace(channel.ReadString(raw, "username", "user"))
+	if username == "" {
+		return Config{}, fmt.Errorf("email username is required")
+	}
+	password := channel.ReadString(raw, "password")
+	if password == "" {
+		return Config{}, fmt.Errorf("email password is required")
+	}
+	address := normalizeAddress(channel.ReadString(raw, "address", "email"))
+	if address == "" && strings.Contains(username, "@") {
+		address = normalizeAddress(username)
+	}
+	if address == "" {
+		return Config{}, fmt.Errorf("email address is required when username is not an address")
+	}
+	imapHost := strings.TrimSpace(channel.ReadString(raw, "imapHost", "imap_host"))
+	if imapHost == "" {
+		return Config{}, fmt.Errorf("email imapHost is required")
+	}
+	smtpHost := strings.TrimSpace(channel.ReadString(raw, "smtpHost", "smtp_host"))
+	if smtpHost == "" {
+		return Config{}, fmt.Errorf("email smtpHost is required")
+	}
+	imapSecurity, err := parseSecurity(channel.ReadString(raw, "imapSecurity", "imap_security"), securityTLS)
+	if err != nil {
+		return Config{}, fmt.Errorf("email imapSecurity: %w", err)
+	}
+	smtpSecurity, err := parseSecurity(channel.ReadString(raw, "smtpSecurity", "smtp_security"), securityStartTLS)
+	if err != nil {
+		return Config{}, fmt.Errorf("email smtpSecurity: %w", err)
+	}
+	imapPort, err := parsePort(channel.ReadString(raw, "imapPort", "imap_port"), defaultIMAPPort(imapSecurity))
+	if err != nil {
+		return Config{}, fmt.Errorf("email imapPort: %w", err)
+	}
+	smtpPort, err := parsePort(channel.ReadString(raw, "smtpPort", "smtp_port"), defaultSMTPPort(smtpSecurity))
+	if err != nil {
+		return Config{}, fmt.Errorf("email smtpPort: %w", err)
+	}
+	pollInterval := defaultPollInterval
+	if value := strings.TrimSpace(channel.ReadString(raw, "pollInterval", "poll_interval")); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			return Config{}, fmt.Errorf("email pollInterval must be a positive number of seconds")
+		}
+		pollInterval = max(time.Duration(seconds)*time.Second, minPollInterval)
+	}
+	trigger := strings.ToLower(strings.TrimSpace(channel.ReadString(raw, "trigger")))
+	switch trigger {
+	case "":
+		trigger = TriggerDirect
+	case TriggerAll, TriggerDirect, TriggerInbox:
+	default:
+		return Config{}, fmt.Errorf("email trigger must be one of %s, %s or %s", TriggerAll, TriggerDirect, TriggerInbox)
+	}
+	mailbox := strings.TrimSpace(channel.ReadString(raw, "mailbox"))
+	if mailbox == "" {
+		mailbox = defaultMailbox
+	}
+	return Config{
+		IMAPHost:     imapHost,
+		IMAPPort:     imapPort,
+		IMAPSecurity: imapSecurity,
+		SMTPHost:     smtpHost,
+		SMTPPort:     smtpPort,
+		SMTPSecurity: smtpSecurity,
+		Username:     username,
+		Password:     password,
+		Address:      address,
+		FromName:     strings.TrimSpace(channel.ReadString(raw, "fromName", "from_name")),
+		Mailbox:      mailbox,
+		PollInterval: pollInterval,
+		Trigger:      trigger,
+	}, nil
+}
+
+func parseSecurity(raw, fallback string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	switch value {
+	case "":
+		return fallback, nil
+	case "ssl":
+		return securityTLS, nil
+	case securityTLS, securityStartTLS, securityNone:
+		return value, nil
+	default:
+		return "", fmt.Errorf("must be one of %s, %s or %s", securityTLS, securityStartTLS, securityNone)
+	}
+}
+
+func parsePort(raw string, fallback int) (int, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return fallback, nil
+	}
+	port, err := strconv.Atoi(value)
+	if err != nil || port <= 0 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q", value)
+	}
+	return port, nil
+}
+
+func defaultIMAPPort(security string) int {
+	if security == securityTLS {
+		return 993
+	}
+	return 143
+}
+
+func defaultSMTPPort(security string) int {
+	switch security {
+	case securityTLS:
+		return 465
+	case securityStartTLS:
+		return 587
+	default:
+		return 25
+	}
+}
+
+func parseUserConfig(raw map[string]any) (UserConfig, error) {
+	address := normalizeAddress(channel.ReadString(raw, "address", "email"))
+	if address == "" {
+		return UserConfig{}, fmt.Errorf("email user config requires address")
+	}
+	return UserConfig{Address: address}, nil
+}
+
+// normalizeAddress reduces an address, optionally with a display name or a
+// mailto: prefix, to its lowercase addr-spec. It returns "" when the input
+// is not an address.
+func normalizeAddress(raw string) string {
+	value := strings.TrimSpace(raw)
+	if len(value) >= len("mailto:") && strings.EqualFold(value[:len("mailto:")], "mailto:") {
+		value = value[len("mailto:"):]
+	}
+	if value == "" {
+		return ""
+	}
+	parsed, err := mail.ParseAddress(value)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Address)
+}
+
+// threadSeparator joins an address and a thread root Message-ID in a target,
+// e.g. "alice@example.com:root-id@example.com". Addresses never contain ":".
+const threadSeparator = ":"
+
+// normalizeTarget normalizes the address part of a target and keeps the
+// thread root as is.
+func normalizeTarget(raw string) string {
+	value := strings.TrimSpace(raw)
+	if len(value) >= len("mailto:") && strings.EqualFold(value[:len("mailto:")], "mailto:") {
+		value = value[len("mailto:"):]
+	}
+	address, threadID := splitTarget(value)
+	if normalized := normalizeAddress(address); normalized != "" {
+		address = normalized
+	}
+	return joinTarget(address, threadID)
+}
+
+// splitTarget splits a target into the address and the thread root
+// Message-ID, which is empty when the target has none.
+func splitTarget(target string) (string, string) {
+	target = strings.TrimSpace(target)
+	if idx := strings.Index(target, threadSeparator); idx >= 0 {
+		return strings.TrimSpace(target[:idx]), trimMessageID(target[idx+1:])
+	}
+	return target, ""
+}
+
+func joinTarget(address, threadID string) string {
+	if threadID == "" {
+		return address
+	}
+	return address + threadSeparator + threadID
+}
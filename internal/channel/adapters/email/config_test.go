@@ -0,0 +1,102 @@
+package email
+
+import (
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestParseConfig(t *testing.T) {
+	base := func() map[string]any {
+		return map[string]any{
+			"imapHost": "imap.example.com",
+			"smtpHost": "smtp.example.com",
+			"username": "Bot@Example.com",
+			"password": "secret",
+		}
+	}
+	cfg, err := parseConfig(base())
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if cfg.Address != "bot@example.com" || cfg.IMAPPort != 993 || cfg.IMAPSecurity != securityTLS || cfg.SMTPPort != 587 || cfg.SMTPSecurity != securityStartTLS {
+		t.Fatalf("unexpected defaults: %#v", cfg)
+	}
+	if cfg.Mailbox != "INBOX" || cfg.PollInterval != time.Minute || cfg.Trigger != TriggerDirect {
+		t.Fatalf("unexpected defaults: %#v", cfg)
+	}
+
+	raw := base()
+	raw["smtp_security"] = "ssl"
+	raw["imap_security"] = "none"
+	raw["pollInterval"] = float64(1)
+	raw["trigger"] = "Inbox"
+	cfg, err = parseConfig(raw)
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if cfg.SMTPPort != 465 || cfg.IMAPPort != 143 || cfg.PollInterval != minPollInterval || cfg.Trigger != TriggerInbox {
+		t.Fatalf("unexpected config: %#v", cfg)
+	}
+
+	for name, mutate := range map[string]func(map[string]any){
+		"missing imap host":   func(m map[string]any) { delete(m, "imapHost") },
+		"missing password":    func(m map[string]any) { delete(m, "password") },
+		"username no address": func(m map[string]any) { m["username"] = "bot" },
+		"bad port":            func(m map[string]any) { m["imapPort"] = "99999" },
+		"bad security":        func(m map[string]any) { m["smtpSecurity"] = "maybe" },
+		"bad trigger":         func(m map[string]any) { m["trigger"] = "sometimes" },
+	} {
+		raw := base()
+		mutate(raw)
+		if _, err := parseConfig(raw); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+	raw = base()
+	raw["username"] = "bot"
+	raw["address"] = "Memoh <bot@example.com>"
+	if cfg, err := parseConfig(raw); err != nil || cfg.Address != "bot@example.com" {
+		t.Fatalf("explicit address: %#v %v", cfg, err)
+	}
+}
+
+func TestNormalizeTarget(t *testing.T) {
+	cases := map[string]string{
+		" Alice@Example.com ":                  "alice@example.com",
+		"mailto:alice@example.com":             "alice@example.com",
+		"Alice <alice@example.com>":            "alice@example.com",
+		"alice@example.com:<root@example.com>": "alice@example.com:root@example.com",
+	}
+	for input, want := range cases {
+		if got := normalizeTarget(input); got != want {
+			t.Fatalf("normalizeTarget(%q) = %q, want %q", input, got, want)
+		}
+	}
+	address, thread := splitTarget("alice@example.com:root@example.com")
+	if address != "alice@example.com" || thread != "root@example.com" {
+		t.Fatalf("unexpected split: %q %q", address, thread)
+	}
+}
+
+func TestBindingRoundTrip(t *testing.T) {
+	identity := channel.Identity{
+		SubjectID:  "alice@example.com",
+		Attributes: map[string]string{"email": "Alice@Example.com", "username": "Alice"},
+	}
+	cfg := buildUserConfig(identity)
+	target, err := resolveTarget(cfg)
+	if err != nil || target != "alice@example.com" {
+		t.Fatalf("unexpected target %q err %v", target, err)
+	}
+	if !matchBinding(cfg, channel.BindingCriteria{SubjectID: "alice@example.com"}) {
+		t.Fatal("expected binding to match subject")
+	}
+	if matchBinding(cfg, channel.BindingCriteria{SubjectID: "bob@example.com"}) {
+		t.Fatal("unexpected match for other subject")
+	}
+	if _, err := normalizeUserConfig(map[string]any{"address": "nope"}); err == nil {
+		t.Fatal("expected error for invalid address")
+	}
+}
@@ -0,0 +1,8 @@
+// Package email implements the email channel adapter: IMAP polling for
+// inbound mail and SMTP for replies.
+package email
+
+import "github.com/memohai/memoh/internal/channel"
+
+// Type is the registered ChannelType identifier for email.
+const Type channel.ChannelType = "email"
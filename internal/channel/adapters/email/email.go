@@ -0,0 +1,556 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	attachmentpkg "github.com/memohai/memoh/internal/attachment"
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/media"
+)
+
+// emailTextChunkLimit keeps a reply in a single mail.
+const emailTextChunkLimit = 100000
+
+// maxSubjectLength bounds subjects derived from the first line of a message.
+const maxSubjectLength = 78
+
+// threadCacheTTL is how long the subject and references of a received mail
+// are kept for replies.
+const threadCacheTTL = 7 * 24 * time.Hour
+
+type assetOpener interface {
+	Open(ctx context.Context, botID, contentHash string) (io.ReadCloser, media.Asset, error)
+}
+
+// threadInfo is what a reply needs to know about the mail it answers.
+type threadInfo struct {
+	subject    string
+	references []string
+	seenAt     time.Time
+}
+
+// EmailAdapter implements the email channel: a mailbox polled over IMAP for
+// inbound mail and SMTP submission for replies. Threads follow the
+// Message-ID, In-Reply-To and References headers.
+type EmailAdapter struct {
+	logger  *slog.Logger
+	assets  assetOpener
+	mu      sync.Mutex
+	threads map[string]threadInfo // keyed by address:message_id
+}
+
+// NewEmailAdapter creates an EmailAdapter with the given logger.
+func NewEmailAdapter(log *slog.Logger) *EmailAdapter {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &EmailAdapter{
+		logger:  log.With(slog.String("adapter", "email")),
+		threads: make(map[string]threadInfo),
+	}
+}
+
+// SetAssetOpener injects media asset reader for content_hash attachment delivery.
+func (a *EmailAdapter) SetAssetOpener(opener assetOpener) {
+	a.assets = opener
+}
+
+// Type returns the email channel type.
+func (a *EmailAdapter) Type() channel.ChannelType {
+	return Type
+}
+
+// Descriptor returns the email channel metadata.
+func (a *EmailAdapter) Descriptor() channel.Descriptor {
+	return channel.Descriptor{
+		Type:        Type,
+		DisplayName: "Email",
+		Capabilities: channel.ChannelCapabilities{
+			Text:           true,
+			Reply:          true,
+			Threads:        true,
+			Attachments:    true,
+			Media:          true,
+			BlockStreaming: true,
+		},
+		OutboundPolicy: channel.OutboundPolicy{
+			TextChunkLimit: emailTextChunkLimit,
+		},
+		ConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"imapHost": {
+					Type:     channel.FieldString,
+					Required: true,
+					Title:    "IMAP Host",
+					Example:  "imap.example.com",
+				},
+				"imapPort": {
+					Type:        channel.FieldNumber,
+					Title:       "IMAP Port",
+					Description: "Defaults to 993 with TLS and 143 otherwise",
+				},
+				"imapSecurity": {
+					Type:    channel.FieldEnum,
+					Title:   "IMAP Security",
+					Enum:    []string{securityTLS, securityStartTLS, securityNone},
+					Example: securityTLS,
+				},
+				"smtpHost": {
+					Type:     channel.FieldString,
+					Required: true,
+					Title:    "SMTP Host",
+					Example:  "smtp.example.com",
+				},
+				"smtpPort": {
+					Type:        channel.FieldNumber,
+					Title:       "SMTP Port",
+					Description: "Defaults to 465 with TLS, 587 with STARTTLS and 25 otherwise",
+				},
+				"smtpSecurity": {
+					Type:    channel.FieldEnum,
+					Title:   "SMTP Security",
+					Enum:    []string{securityTLS, securityStartTLS, securityNone},
+					Example: securityStartTLS,
+				},
+				"username": {
+					Type:        channel.FieldString,
+					Required:    true,
+					Title:       "Username",
+					Description: "Login for both IMAP and SMTP",
+				},
+				"password": {
+					Type:        channel.FieldSecret,
+					Required:    true,
+					Title:       "Password",
+					Description: "Password or app password for both IMAP and SMTP",
+				},
+				"address": {
+					Type:        channel.FieldString,
+					Title:       "Address",
+					Description: "Email address of the bot; defaults to the username",
+					Example:     "bot@example.com",
+				},
+				"fromName": {
+					Type:        channel.FieldString,
+					Title:       "From Name",
+					Description: "Display name used in the From header",
+				},
+				"mailbox": {
+					Type:        channel.FieldString,
+					Title:       "Mailbox",
+					Description: "Mailbox polled for new mail",
+					Example:     defaultMailbox,
+				},
+				"pollInterval": {
+					Type:        channel.FieldNumber,
+					Title:       "Poll Interval",
+					Description: "Seconds between mailbox checks (minimum 10)",
+					Example:     int(defaultPollInterval / time.Second),
+				},
+				"trigger": {
+					Type:        channel.FieldEnum,
+					Title:       "Trigger",
+					Description: "Which mail starts a chat turn: all mail, mail sent to the bot's address or replying to it, or none; the rest lands in the inbox",
+					Enum:        []string{TriggerAll, TriggerDirect, TriggerInbox},
+					Example:     TriggerDirect,
+				},
+			},
+		},
+		UserConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"address": {Type: channel.FieldString, Required: true},
+			},
+		},
+		TargetSpec: channel.TargetSpec{
+			Format: "address[:thread_message_id]",
+			Hints: []channel.TargetHint{
+				{Label: "Address", Example: "alice@example.com"},
+				{Label: "Thread", Example: "alice@example.com:CAF1234@mail.example.com"},
+			},
+		},
+	}
+}
+
+// NormalizeConfig validates and normalizes an email channel configuration map.
+func (a *EmailAdapter) NormalizeConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeConfig(raw)
+}
+
+// NormalizeUserConfig validates and normalizes an email user-binding configuration map.
+func (a *EmailAdapter) NormalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeUserConfig(raw)
+}
+
+// NormalizeTarget normalizes an email delivery target string.
+func (a *EmailAdapter) NormalizeTarget(raw string) string {
+	return normalizeTarget(raw)
+}
+
+// ResolveTarget derives a delivery target from an email user-binding configuration.
+func (a *EmailAdapter) ResolveTarget(userConfig map[string]any) (string, error) {
+	return resolveTarget(userConfig)
+}
+
+// MatchBinding reports whether an email user binding matches the given criteria.
+func (a *EmailAdapter) MatchBinding(config map[string]any, criteria channel.BindingCriteria) bool {
+	return matchBinding(config, criteria)
+}
+
+// BuildUserConfig constructs an email user-binding config from an Identity.
+func (a *EmailAdapter) BuildUserConfig(identity channel.Identity) map[string]any {
+	return buildUserConfig(identity)
+}
+
+// rememberThread records the subject and references of a received mail so a
+// reply to it can continue the thread.
+func (a *EmailAdapter) rememberThread(address, messageID string, info threadInfo) {
+	now := time.Now()
+	info.seenAt = now
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for key, existing := range a.threads {
+		if now.Sub(existing.seenAt) > threadCacheTTL {
+			delete(a.threads, key)
+		}
+	}
+	a.threads[address+":"+messageID] = info
+}
+
+func (a *EmailAdapter) lookupThread(address, messageID string) (threadInfo, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	info, ok := a.threads[address+":"+messageID]
+	return info, ok
+}
+
+// Send mails the message to the target address over SMTP. A reply or a
+// thread in the target sets In-Reply-To and References so the mail lands in
+// the recipient's existing thread.
+func (a *EmailAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, msg channel.OutboundMessage) error {
+	emailCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	address, threadID := splitTarget(msg.Target)
+	address = normalizeAddress(address)
+	if address == "" {
+		return fmt.Errorf("email target must be an address")
+	}
+	if threadID == "" && msg.Message.Thread != nil {
+		threadID = trimMessageID(msg.Message.Thread.ID)
+	}
+	text := strings.TrimSpace(msg.Message.PlainText())
+	if text == "" && len(msg.Message.Attachments) == 0 {
+		return fmt.Errorf("message is required")
+	}
+	replyID := threadID
+	if msg.Message.Reply != nil && strings.TrimSpace(msg.Message.Reply.MessageID) != "" {
+		replyID = trimMessageID(msg.Message.Reply.MessageID)
+	}
+	parts := make([]mailPart, 0, len(msg.Message.Attachments))
+	for _, att := range msg.Message.Attachments {
+		part, err := a.readAttachment(ctx, att, cfg.BotID)
+		if err != nil {
+			return err
+		}
+		parts = append(parts, part)
+	}
+	out := outgoingMail{
+		From:        mail.Address{Name: emailCfg.FromName, Address: emailCfg.Address},
+		To:          []string{address},
+		Subject:     a.outboundSubject(emailCfg, msg.Message, replyID, text),
+		MessageID:   newMessageID(emailCfg.Address),
+		InReplyTo:   replyID,
+		References:  a.outboundReferences(emailCfg, threadID, replyID),
+		Date:        time.Now(),
+		Text:        text,
+		Attachments: parts,
+	}
+	data, err := out.bytes()
+	if err != nil {
+		return fmt.Errorf("email build message: %w", err)
+	}
+	if err := sendSMTP(ctx, emailCfg, emailCfg.Address, out.To, data); err != nil {
+		return err
+	}
+	a.logger.Info("mail sent",
+		slog.String("config_id", cfg.ID),
+		slog.String("to", address),
+		slog.String("message_id", out.MessageID),
+		slog.String("in_reply_to", replyID),
+	)
+	return nil
+}
+
+// outboundSubject answers with the subject of the replied mail, and falls
+// back to a "subject" metadata entry or the first line of the text.
+func (a *EmailAdapter) outboundSubject(emailCfg Config, msg channel.Message, replyID, text string) string {
+	if replyID != "" {
+		if info, ok := a.lookupThread(emailCfg.Address, replyID); ok && info.subject != "" {
+			return replySubject(info.subject)
+		}
+	}
+	if msg.Metadata != nil {
+		if subject, ok := msg.Metadata["subject"].(string); ok && strings.TrimSpace(subject) != "" {
+			return strings.TrimSpace(subject)
+		}
+	}
+	line, _, _ := strings.Cut(text, "\n")
+	line = strings.TrimSpace(strings.TrimLeft(line, "#*> "))
+	if runes := []rune(line); len(runes) > maxSubjectLength {
+		line = string(runes[:maxSubjectLength-3]) + "..."
+	}
+	if line == "" {
+		line = "Message from " + emailCfg.Address
+	}
+	if replyID != "" {
+		return replySubject(line)
+	}
+	return line
+}
+
+// outboundReferences continues the References chain of the replied mail, or
+// rebuilds a minimal one from the thread root when it is not cached.
+func (a *EmailAdapter) outboundReferences(emailCfg Config, threadID, replyID string) []string {
+	if replyID == "" {
+		return nil
+	}
+	if info, ok := a.lookupThread(emailCfg.Address, replyID); ok && len(info.references) > 0 {
+		return info.references
+	}
+	if threadID != "" && threadID != replyID {
+		return []string{threadID, replyID}
+	}
+	return []string{replyID}
+}
+
+// readAttachment loads an outbound attachment into memory.
+func (a *EmailAdapter) readAttachment(ctx context.Context, att channel.Attachment, botID string) (mailPart, error) {
+	reader, mime, name, err := a.resolveAttachmentUploadReader(ctx, att, botID)
+	if err != nil {
+		return mailPart{}, err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	data, err := media.ReadAllWithLimit(reader, media.MaxAssetBytes)
+	if err != nil {
+		return mailPart{}, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	if name == "" {
+		name = defaultPartName(mime)
+	}
+	return mailPart{Name: name, Mime: mime, Data: data}, nil
+}
+
+func (a *EmailAdapter) resolveAttachmentUploadReader(ctx context.Context, att channel.Attachment, fallbackBotID string) (io.ReadCloser, string, string, error) {
+	assetID := strings.TrimSpace(att.ContentHash)
+	botID := strings.TrimSpace(fallbackBotID)
+	if botID == "" && att.Metadata != nil {
+		if value, ok := att.Metadata["bot_id"].(string); ok {
+			botID = strings.TrimSpace(value)
+		}
+	}
+	if assetID != "" && botID != "" && a.assets != nil {
+		reader, asset, err := a.assets.Open(ctx, botID, assetID)
+		if err == nil {
+			resolvedMime := strings.TrimSpace(att.Mime)
+			if resolvedMime == "" {
+				resolvedMime = strings.TrimSpace(asset.Mime)
+			}
+			return reader, resolvedMime, strings.TrimSpace(att.Name), nil
+		}
+		a.logger.Debug("email attachment storage open failed",
+			slog.String("bot_id", botID),
+			slog.String("content_hash", assetID),
+			slog.Any("error", err),
+		)
+	}
+
+	rawBase64 := strings.TrimSpace(att.Base64)
+	downloadURL := strings.TrimSpace(att.URL)
+	if rawBase64 == "" && strings.HasPrefix(strings.ToLower(downloadURL), "data:") {
+		rawBase64 = downloadURL
+	}
+	if rawBase64 != "" {
+		decoded, err := attachmentpkg.DecodeBase64(rawBase64, media.MaxAssetBytes)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to decode attachment base64: %w", err)
+		}
+		data, err := media.ReadAllWithLimit(decoded, media.MaxAssetBytes)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to read attachment base64: %w", err)
+		}
+		resolvedMime := strings.TrimSpace(att.Mime)
+		if resolvedMime == "" {
+			resolvedMime = strings.TrimSpace(attachmentpkg.MimeFromDataURL(rawBase64))
+		}
+		return io.NopCloser(bytes.NewReader(data)), resolvedMime, strings.TrimSpace(att.Name), nil
+	}
+
+	if downloadURL == "" {
+		return nil, "", "", fmt.Errorf("attachment reference is required: provide content_hash/base64/url")
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to build download request: %w", err)
+	}
+	httpClient := &http.Client{Timeout: 60 * time.Second}
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to download attachment: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, "", "", fmt.Errorf("failed to download attachment, status: %d", resp.StatusCode)
+	}
+	if resp.ContentLength > media.MaxAssetBytes {
+		_ = resp.Body.Close()
+		return nil, "", "", fmt.Errorf("%w: max %d bytes", media.ErrAssetTooLarge, media.MaxAssetBytes)
+	}
+	resolvedMime := strings.TrimSpace(att.Mime)
+	if resolvedMime == "" {
+		resolvedMime = strings.TrimSpace(resp.Header.Get("Content-Type"))
+		if idx := strings.Index(resolvedMime, ";"); idx >= 0 {
+			resolvedMime = strings.TrimSpace(resolvedMime[:idx])
+		}
+	}
+	return resp.Body, resolvedMime, strings.TrimSpace(att.Name), nil
+}
+
+// OpenStream opens a stream that collects the reply and mails it once the
+// final message arrives.
+func (a *EmailAdapter) OpenStream(ctx context.Context, cfg channel.ChannelConfig, target string, opts channel.StreamOptions) (channel.OutboundStream, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil, fmt.Errorf("email target is required")
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+	reply := opts.Reply
+	if reply == nil && strings.TrimSpace(opts.SourceMessageID) != "" {
+		reply = &channel.ReplyRef{Target: target, MessageID: strings.TrimSpace(opts.SourceMessageID)}
+	}
+	return &emailOutboundStream{
+		adapter: a,
+		cfg:     cfg,
+		target:  target,
+		reply:   reply,
+	}, nil
+}
+
+// DiscoverSelf logs in over IMAP to verify the credentials and returns the
+// configured address as the bot identity.
+func (a *EmailAdapter) DiscoverSelf(ctx context.Context, credentials map[string]any) (map[string]any, string, error) {
+	emailCfg, err := parseConfig(credentials)
+	if err != nil {
+		return nil, "", err
+	}
+	c, err := dialIMAP(ctx, emailCfg)
+	if err != nil {
+		return nil, "", fmt.Errorf("email discover self: %w", err)
+	}
+	defer c.close()
+	if _, err := c.selectMailbox(ctx, emailCfg.Mailbox); err != nil {
+		return nil, "", fmt.Errorf("email discover self: %w", err)
+	}
+	identity := map[string]any{
+		"address": emailCfg.Address,
+	}
+	if emailCfg.FromName != "" {
+		identity["name"] = emailCfg.FromName
+	}
+	return identity, emailCfg.Address, nil
+}
+
+// attachmentKey identifies an inbound attachment as
+// uidvalidity/uid/index within the configured mailbox.
+func attachmentKey(uidValidity, uid uint32, index int) string {
+	return fmt.Sprintf("%d/%d/%d", uidValidity, uid, index)
+}
+
+func parseAttachmentKey(key string) (uint32, uint32, int, error) {
+	fields := strings.Split(strings.TrimSpace(key), "/")
+	if len(fields) != 3 {
+		return 0, 0, 0, fmt.Errorf("invalid email attachment key %q", key)
+	}
+	validity, err1 := strconv.ParseUint(fields[0], 10, 32)
+	uid, err2 := strconv.ParseUint(fields[1], 10, 32)
+	index, err3 := strconv.Atoi(fields[2])
+	if err1 != nil || err2 != nil || err3 != nil || index < 0 {
+		return 0, 0, 0, fmt.Errorf("invalid email attachment key %q", key)
+	}
+	return uint32(validity), uint32(uid), index, nil
+}
+
+// ResolveAttachment fetches the mail an inbound attachment belongs to again
+// and extracts the part. The platform key is uidvalidity/uid/index.
+func (a *EmailAdapter) ResolveAttachment(ctx context.Context, cfg channel.ChannelConfig, attachment channel.Attachment) (channel.AttachmentPayload, error) {
+	emailCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return channel.AttachmentPayload{}, err
+	}
+	if strings.TrimSpace(attachment.PlatformKey) == "" {
+		return channel.AttachmentPayload{}, fmt.Errorf("email attachment requires platform_key")
+	}
+	validity, uid, index, err := parseAttachmentKey(attachment.PlatformKey)
+	if err != nil {
+		return channel.AttachmentPayload{}, err
+	}
+	c, err := dialIMAP(ctx, emailCfg)
+	if err != nil {
+		return channel.AttachmentPayload{}, err
+	}
+	defer c.close()
+	status, err := c.selectMailbox(ctx, emailCfg.Mailbox)
+	if err != nil {
+		return channel.AttachmentPayload{}, err
+	}
+	if status.UIDValidity != validity {
+		return channel.AttachmentPayload{}, fmt.Errorf("email attachment is no longer available: mailbox UIDVALIDITY changed")
+	}
+	raw, err := c.fetchMessage(ctx, uid)
+	if err != nil {
+		return channel.AttachmentPayload{}, fmt.Errorf("download attachment: %w", err)
+	}
+	parsed, err := parseMail(raw)
+	if err != nil {
+		return channel.AttachmentPayload{}, err
+	}
+	if index >= len(parsed.Attachments) {
+		return channel.AttachmentPayload{}, fmt.Errorf("email attachment %d not found in message", index)
+	}
+	part := parsed.Attachments[index]
+	if int64(len(part.Data)) > media.MaxAssetBytes {
+		return channel.AttachmentPayload{}, fmt.Errorf("%w: max %d bytes", media.ErrAssetTooLarge, media.MaxAssetBytes)
+	}
+	mime := strings.TrimSpace(attachment.Mime)
+	if mime == "" {
+		mime = part.Mime
+	}
+	name := strings.TrimSpace(attachment.Name)
+	if name == "" {
+		name = part.Name
+	}
+	return channel.AttachmentPayload{
+		Reader: io.NopCloser(bytes.NewReader(part.Data)),
+		Mime:   mime,
+		Name:   name,
+		Size:   int64(len(part.Data)),
+	}, nil
+}
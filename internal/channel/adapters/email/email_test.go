@@ -0,0 +1,629 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const testUIDValidity = 7
+
+// fakeIMAP serves a single mailbox over plain IMAP.
+type fakeIMAP struct {
+	ln       net.Listener
+	mu       sync.Mutex
+	messages map[uint32]string
+	seen     map[uint32]bool
+}
+
+func newFakeIMAP(t *testing.T) *fakeIMAP {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	f := &fakeIMAP{ln: ln, messages: map[uint32]string{}, seen: map[uint32]bool{}}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { _ = ln.Close() })
+	return f
+}
+
+func (f *fakeIMAP) port() int {
+	return f.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (f *fakeIMAP) add(uid uint32, raw string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages[uid] = strings.ReplaceAll(raw, "\n", "\r\n")
+}
+
+func (f *fakeIMAP) isSeen(uid uint32) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.seen[uid]
+}
+
+func (f *fakeIMAP) uids() []uint32 {
+	uids := make([]uint32, 0, len(f.messages))
+	for uid := range f.messages {
+		uids = append(uids, uid)
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+	return uids
+}
+
+func (f *fakeIMAP) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+	_, _ = io.WriteString(conn, "* OK fake IMAP ready\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		tag, cmd, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		f.mu.Lock()
+		reply := f.handle(tag, cmd)
+		f.mu.Unlock()
+		_, _ = io.WriteString(conn, reply)
+		if strings.HasPrefix(cmd, "LOGOUT") {
+			return
+		}
+	}
+}
+
+func (f *fakeIMAP) handle(tag, cmd string) string {
+	fields := strings.Fields(cmd)
+	switch {
+	case fields[0] == "LOGIN":
+		if cmd != `LOGIN "bot@example.com" "p\"w"` {
+			return tag + " NO [AUTHENTICATIONFAILED] invalid credentials\r\n"
+		}
+		return tag + " OK logged in\r\n"
+	case fields[0] == "SELECT":
+		uids := f.uids()
+		next := uint32(1)
+		if len(uids) > 0 {
+			next = uids[len(uids)-1] + 1
+		}
+		return fmt.Sprintf("* %d EXISTS\r\n* OK [UIDVALIDITY %d] ok\r\n* OK [UIDNEXT %d] ok\r\n%s OK [READ-WRITE] selected\r\n", len(uids), testUIDValidity, next, tag)
+	case fields[0] == "NOOP":
+		return tag + " OK noop\r\n"
+	case fields[0] == "LOGOUT":
+		return "* BYE\r\n" + tag + " OK bye\r\n"
+	case strings.HasPrefix(cmd, "UID SEARCH"):
+		from, _ := strconv.ParseUint(strings.TrimSuffix(fields[3], ":*"), 10, 32)
+		var found []string
+		uids := f.uids()
+		for _, uid := range uids {
+			if uid >= uint32(from) {
+				found = append(found, strconv.Itoa(int(uid)))
+			}
+		}
+		if len(found) == 0 && len(uids) > 0 {
+			// Like real servers, "n:*" always matches the highest UID.
+			found = append(found, strconv.Itoa(int(uids[len(uids)-1])))
+		}
+		return "* SEARCH " + strings.Join(found, " ") + "\r\n" + tag + " OK search\r\n"
+	case strings.HasPrefix(cmd, "UID FETCH"):
+		uid, _ := strconv.ParseUint(fields[2], 10, 32)
+		raw, ok := f.messages[uint32(uid)]
+		if !ok {
+			return tag + " OK fetch\r\n"
+		}
+		return fmt.Sprintf("* 1 FETCH (UID %d BODY[] {%d}\r\n%s)\r\n%s OK fetch\r\n", uid, len(raw), raw, tag)
+	case strings.HasPrefix(cmd, "UID STORE"):
+		uid, _ := strconv.ParseUint(fields[2], 10, 32)
+		f.seen[uint32(uid)] = true
+		return tag + " OK store\r\n"
+	default:
+		return tag + " BAD unknown command\r\n"
+	}
+}
+
+// fakeSMTP accepts mail over plain SMTP with AUTH PLAIN.
+type fakeSMTP struct {
+	ln       net.Listener
+	mu       sync.Mutex
+	messages []smtpMessage
+}
+
+type smtpMessage struct {
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	f := &fakeSMTP{ln: ln}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { _ = ln.Close() })
+	return f
+}
+
+func (f *fakeSMTP) port() int {
+	return f.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (f *fakeSMTP) sent() []smtpMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]smtpMessage(nil), f.messages...)
+}
+
+func (f *fakeSMTP) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+	write := func(s string) { _, _ = io.WriteString(conn, s+"\r\n") }
+	write("220 fake ESMTP")
+	var msg smtpMessage
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		upper := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(upper, "EHLO"):
+			write("250-fake")
+			write("250 AUTH PLAIN")
+		case strings.HasPrefix(upper, "AUTH PLAIN "):
+			decoded, _ := base64.StdEncoding.DecodeString(line[len("AUTH PLAIN "):])
+			msg.auth = string(decoded)
+			write("235 ok")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			msg.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+			write("250 ok")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			msg.to = append(msg.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			write("250 ok")
+		case upper == "DATA":
+			write("354 go ahead")
+			var data strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			msg.data = data.String()
+			f.mu.Lock()
+			f.messages = append(f.messages, msg)
+			f.mu.Unlock()
+			msg = smtpMessage{}
+			write("250 queued")
+		case upper == "QUIT":
+			write("221 bye")
+			return
+		default:
+			write("250 ok")
+		}
+	}
+}
+
+func testConfig(imap *fakeIMAP, smtp *fakeSMTP, trigger string) channel.ChannelConfig {
+	creds := map[string]any{
+		"imapHost":     "127.0.0.1",
+		"imapSecurity": "none",
+		"smtpHost":     "127.0.0.1",
+		"smtpSecurity": "none",
+		"username":     "bot@example.com",
+		"password":     `p"w`,
+		"fromName":     "Memoh",
+		"trigger":      trigger,
+	}
+	if imap != nil {
+		creds["imapPort"] = float64(imap.port())
+	}
+	if smtp != nil {
+		creds["smtpPort"] = float64(smtp.port())
+	}
+	return channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1", Credentials: creds}
+}
+
+const (
+	mailFromAlice = `From: Alice <alice@example.com>
+To: bot@example.com
+Subject: Trip plans
+Message-ID: <m1@example.com>
+Date: Mon, 02 Jan 2026 15:04:05 +0000
+Content-Type: text/plain; charset=utf-8
+
+Can you book the train?
+`
+	mailCcOnly = `From: Bob <bob@example.com>
+To: team@example.com
+Cc: bot@example.com
+Subject: FYI
+Message-ID: <m2@example.com>
+
+Just so you know.
+`
+	mailReplyToBot = `From: Alice <alice@example.com>
+To: Memoh <bot@example.com>
+Subject: Re: Trip plans
+Message-ID: <m3@example.com>
+In-Reply-To: <abc.memoh@example.com>
+References: <m1@example.com> <abc.memoh@example.com>
+Content-Type: multipart/mixed; boundary="b1"
+
+--b1
+Content-Type: text/plain; charset=utf-8
+
+Here is the ticket.
+
+On Mon, Jan 2, 2026 at 3:04 PM Memoh <bot@example.com> wrote:
+> Booked.
+
+--b1
+Content-Type: application/pdf; name="ticket.pdf"
+Content-Disposition: attachment; filename="ticket.pdf"
+Content-Transfer-Encoding: base64
+
+JVBERi0xLjQK
+--b1--
+`
+	mailAutoReply = `From: Carol <carol@example.com>
+To: bot@example.com
+Subject: Out of office
+Message-ID: <m4@example.com>
+Auto-Submitted: auto-replied
+
+I am away.
+`
+)
+
+type inboundCollector struct {
+	mu   sync.Mutex
+	msgs []channel.InboundMessage
+	got  chan struct{}
+}
+
+func newCollector() *inboundCollector {
+	return &inboundCollector{got: make(chan struct{}, 16)}
+}
+
+func (c *inboundCollector) handler(_ context.Context, _ channel.ChannelConfig, msg channel.InboundMessage) error {
+	c.mu.Lock()
+	c.msgs = append(c.msgs, msg)
+	c.mu.Unlock()
+	c.got <- struct{}{}
+	return nil
+}
+
+func (c *inboundCollector) wait(t *testing.T, n int) []channel.InboundMessage {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-c.got:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for inbound message %d", i+1)
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	msgs := append([]channel.InboundMessage(nil), c.msgs...)
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].Message.ID < msgs[j].Message.ID })
+	return msgs
+}
+
+// pollNew opens the mailbox, adds mail after the initial position and polls
+// once, the way Connect's loop does.
+func pollNew(t *testing.T, imap *fakeIMAP, cfg channel.ChannelConfig, adapter *EmailAdapter, collector *inboundCollector, mails map[uint32]string) {
+	t.Helper()
+	emailCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	ctx := context.Background()
+	c, status, err := openMailbox(ctx, emailCfg)
+	if err != nil {
+		t.Fatalf("open mailbox: %v", err)
+	}
+	defer c.close()
+	last := uint32(0)
+	for uid, raw := range mails {
+		imap.add(uid, raw)
+		last = max(last, uid)
+	}
+	state := mailboxState{uidValidity: status.UIDValidity, next: status.UIDNext}
+	if err := adapter.pollOnce(ctx, cfg, emailCfg, c, &state, collector.handler); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if state.next != last+1 {
+		t.Fatalf("unexpected next uid %d", state.next)
+	}
+}
+
+func TestPollMapsThreadsAndTriggers(t *testing.T) {
+	imap := newFakeIMAP(t)
+	imap.add(1, mailCcOnly) // already in the mailbox, never replayed
+	adapter := NewEmailAdapter(nil)
+	cfg := testConfig(imap, nil, "")
+	collector := newCollector()
+
+	pollNew(t, imap, cfg, adapter, collector, map[uint32]string{
+		2: mailFromAlice,
+		3: mailCcOnly,
+		4: mailReplyToBot,
+		5: mailAutoReply,
+	})
+	msgs := collector.wait(t, 4)
+
+	first := msgs[0]
+	if first.Message.ID != "m1@example.com" || first.Sender.SubjectID != "alice@example.com" || first.Sender.DisplayName != "Alice" {
+		t.Fatalf("unexpected first message: %#v", first)
+	}
+	if first.Conversation.Type != conversationType || first.Conversation.Name != "Trip plans" || first.Conversation.ThreadID != "m1@example.com" {
+		t.Fatalf("unexpected conversation: %#v", first.Conversation)
+	}
+	if first.ReplyTarget != "alice@example.com:m1@example.com" || first.Message.Thread.ID != "m1@example.com" {
+		t.Fatalf("unexpected reply target %q", first.ReplyTarget)
+	}
+	if first.Metadata["is_mentioned"] != true || first.Message.Text != "Can you book the train?" {
+		t.Fatalf("direct mail should trigger: %#v", first)
+	}
+
+	if cc := msgs[1]; cc.Message.ID != "m2@example.com" || cc.Metadata["is_mentioned"] != false {
+		t.Fatalf("cc-only mail should land in the inbox: %#v", cc.Metadata)
+	}
+
+	reply := msgs[2]
+	if reply.Conversation.ThreadID != "m1@example.com" || reply.Message.Reply == nil || reply.Message.Reply.MessageID != "abc.memoh@example.com" {
+		t.Fatalf("reply should join the thread: %#v", reply.Message)
+	}
+	if reply.Metadata["is_reply_to_bot"] != true || reply.Message.Text != "Here is the ticket." {
+		t.Fatalf("unexpected reply: %q %#v", reply.Message.Text, reply.Metadata)
+	}
+	if len(reply.Message.Attachments) != 1 {
+		t.Fatalf("expected one attachment, got %#v", reply.Message.Attachments)
+	}
+	att := reply.Message.Attachments[0]
+	if att.PlatformKey != "7/4/0" || att.Name != "ticket.pdf" || att.Mime != "application/pdf" || att.Type != channel.AttachmentFile || att.Size != 9 {
+		t.Fatalf("unexpected attachment: %#v", att)
+	}
+
+	if auto := msgs[3]; auto.Metadata["is_mentioned"] != false {
+		t.Fatalf("auto-replies should never trigger: %#v", auto.Metadata)
+	}
+	for _, uid := range []uint32{2, 3, 4, 5} {
+		if !imap.isSeen(uid) {
+			t.Fatalf("uid %d should be marked seen", uid)
+		}
+	}
+	if imap.isSeen(1) {
+		t.Fatal("mail from before connecting should be left alone")
+	}
+}
+
+func TestTriggerRules(t *testing.T) {
+	tests := []struct {
+		trigger string
+		mail    string
+		want    bool
+	}{
+		{TriggerAll, mailCcOnly, true},
+		{TriggerAll, mailAutoReply, false},
+		{TriggerDirect, mailFromAlice, true},
+		{TriggerDirect, mailCcOnly, false},
+		{TriggerInbox, mailFromAlice, false},
+		{TriggerInbox, mailReplyToBot, false},
+	}
+	for _, tt := range tests {
+		cfg, err := parseConfig(testConfig(nil, nil, tt.trigger).Credentials)
+		if err != nil {
+			t.Fatalf("parse config: %v", err)
+		}
+		parsed, err := parseMail([]byte(tt.mail))
+		if err != nil {
+			t.Fatalf("parse mail: %v", err)
+		}
+		if got := shouldTrigger(cfg, parsed); got != tt.want {
+			t.Fatalf("trigger %q for %q = %v, want %v", tt.trigger, parsed.Subject, got, tt.want)
+		}
+	}
+}
+
+func TestSendReplyContinuesThread(t *testing.T) {
+	imap := newFakeIMAP(t)
+	smtp := newFakeSMTP(t)
+	adapter := NewEmailAdapter(nil)
+	cfg := testConfig(imap, smtp, "")
+	collector := newCollector()
+	pollNew(t, imap, cfg, adapter, collector, map[uint32]string{2: mailReplyToBot})
+	inbound := collector.wait(t, 1)[0]
+
+	err := adapter.Send(context.Background(), cfg, channel.OutboundMessage{
+		Target: inbound.ReplyTarget,
+		Message: channel.Message{
+			Text:  "Thanks, got it.",
+			Reply: &channel.ReplyRef{MessageID: inbound.Message.ID},
+		},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	sent := smtp.sent()
+	if len(sent) != 1 {
+		t.Fatalf("expected one mail, got %d", len(sent))
+	}
+	msg := sent[0]
+	if msg.from != "bot@example.com" || len(msg.to) != 1 || msg.to[0] != "alice@example.com" {
+		t.Fatalf("unexpected envelope: %#v", msg)
+	}
+	if msg.auth != "\x00bot@example.com\x00p\"w" {
+		t.Fatalf("unexpected auth %q", msg.auth)
+	}
+	for _, header := range []string{
+		"From: \"Memoh\" <bot@example.com>\r\n",
+		"Subject: Re: Trip plans\r\n",
+		"In-Reply-To: <m3@example.com>\r\n",
+		"References: <m1@example.com> <abc.memoh@example.com> <m3@example.com>\r\n",
+		"Auto-Submitted: auto-replied\r\n",
+	} {
+		if !strings.Contains(msg.data, header) {
+			t.Fatalf("missing header %q in:\n%s", header, msg.data)
+		}
+	}
+	parsed, err := parseMail([]byte(msg.data))
+	if err != nil {
+		t.Fatalf("parse sent mail: %v", err)
+	}
+	if parsed.Text != "Thanks, got it." || !isOwnMessageID(parsed.MessageID, "bot@example.com") {
+		t.Fatalf("unexpected sent mail: %#v", parsed)
+	}
+}
+
+func TestSendWithoutCachedThread(t *testing.T) {
+	smtp := newFakeSMTP(t)
+	adapter := NewEmailAdapter(nil)
+	cfg := testConfig(nil, smtp, "")
+	err := adapter.Send(context.Background(), cfg, channel.OutboundMessage{
+		Target:  "Alice <alice@example.com>:root@example.com",
+		Message: channel.Message{Text: "Following up\nmore text"},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	data := smtp.sent()[0].data
+	for _, header := range []string{"Subject: Re: Following up\r\n", "In-Reply-To: <root@example.com>\r\n", "References: <root@example.com>\r\n"} {
+		if !strings.Contains(data, header) {
+			t.Fatalf("missing header %q in:\n%s", header, data)
+		}
+	}
+	if err := adapter.Send(context.Background(), cfg, channel.OutboundMessage{Target: "not an address", Message: channel.Message{Text: "x"}}); err == nil {
+		t.Fatal("expected error for invalid target")
+	}
+}
+
+func TestStreamSendsOneMail(t *testing.T) {
+	smtp := newFakeSMTP(t)
+	adapter := NewEmailAdapter(nil)
+	cfg := testConfig(nil, smtp, "")
+	ctx := context.Background()
+	stream, err := adapter.OpenStream(ctx, cfg, "alice@example.com:m1@example.com", channel.StreamOptions{SourceMessageID: "m1@example.com"})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	events := []channel.StreamEvent{
+		{Type: channel.StreamEventStatus, Status: channel.StreamStatusStarted},
+		{Type: channel.StreamEventDelta, Delta: "thinking", Phase: channel.StreamPhaseReasoning},
+		{Type: channel.StreamEventDelta, Delta: "Booked "},
+		{Type: channel.StreamEventAttachment, Attachments: []channel.Attachment{{Type: channel.AttachmentFile, Name: "ticket.txt", Mime: "text/plain", Base64: base64.StdEncoding.EncodeToString([]byte("seat 12A"))}}},
+		{Type: channel.StreamEventDelta, Delta: "the train."},
+		{Type: channel.StreamEventFinal, Final: &channel.StreamFinalizePayload{Message: channel.Message{Text: "Booked the train."}}},
+	}
+	for _, event := range events {
+		if err := stream.Push(ctx, event); err != nil {
+			t.Fatalf("push %s: %v", event.Type, err)
+		}
+	}
+	if err := stream.Close(ctx); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	sent := smtp.sent()
+	if len(sent) != 1 {
+		t.Fatalf("expected one mail, got %d", len(sent))
+	}
+	parsed, err := parseMail([]byte(sent[0].data))
+	if err != nil {
+		t.Fatalf("parse sent mail: %v", err)
+	}
+	if parsed.Text != "Booked the train." || parsed.InReplyTo != "m1@example.com" {
+		t.Fatalf("unexpected mail: %#v", parsed)
+	}
+	if len(parsed.Attachments) != 1 || parsed.Attachments[0].Name != "ticket.txt" || string(parsed.Attachments[0].Data) != "seat 12A" {
+		t.Fatalf("unexpected attachments: %#v", parsed.Attachments)
+	}
+	if err := stream.Push(ctx, channel.StreamEvent{Type: channel.StreamEventDelta, Delta: "x"}); err == nil {
+		t.Fatal("expected error after close")
+	}
+}
+
+func TestResolveAttachment(t *testing.T) {
+	imap := newFakeIMAP(t)
+	imap.add(4, mailReplyToBot)
+	adapter := NewEmailAdapter(nil)
+	cfg := testConfig(imap, nil, "")
+	payload, err := adapter.ResolveAttachment(context.Background(), cfg, channel.Attachment{PlatformKey: "7/4/0"})
+	if err != nil {
+		t.Fatalf("resolve attachment: %v", err)
+	}
+	data, _ := io.ReadAll(payload.Reader)
+	if string(data) != "%PDF-1.4\n" || payload.Name != "ticket.pdf" || payload.Mime != "application/pdf" || payload.Size != 9 {
+		t.Fatalf("unexpected payload: %q %#v", data, payload)
+	}
+	if _, err := adapter.ResolveAttachment(context.Background(), cfg, channel.Attachment{PlatformKey: "8/4/0"}); err == nil {
+		t.Fatal("expected error for stale uidvalidity")
+	}
+	if _, err := adapter.ResolveAttachment(context.Background(), cfg, channel.Attachment{PlatformKey: "7/4/3"}); err == nil {
+		t.Fatal("expected error for missing part")
+	}
+}
+
+func TestConnectAndDiscoverSelf(t *testing.T) {
+	imap := newFakeIMAP(t)
+	adapter := NewEmailAdapter(nil)
+	cfg := testConfig(imap, nil, "")
+	identity, externalID, err := adapter.DiscoverSelf(context.Background(), cfg.Credentials)
+	if err != nil {
+		t.Fatalf("discover self: %v", err)
+	}
+	if externalID != "bot@example.com" || identity["name"] != "Memoh" {
+		t.Fatalf("unexpected identity %q %#v", externalID, identity)
+	}
+	conn, err := adapter.Connect(context.Background(), cfg, newCollector().handler)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := conn.Stop(ctx); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	bad := testConfig(imap, nil, "")
+	bad.Credentials["password"] = "wrong"
+	if _, err := adapter.Connect(context.Background(), bad, newCollector().handler); err == nil {
+		t.Fatal("expected login failure")
+	}
+}
@@ -0,0 +1,339 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	imapDialTimeout    = 30 * time.Second
+	imapCommandTimeout = 2 * time.Minute
+	// imapMaxLiteral bounds a single message fetched over IMAP.
+	imapMaxLiteral = 100 * 1024 * 1024
+)
+
+// imapResponse is an untagged server response. Literals ({n} strings) are
+// cut out of Text and kept in order in Literals.
+type imapResponse struct {
+	Text     string
+	Literals [][]byte
+}
+
+// imapError is a NO or BAD completion of a command.
+type imapError struct {
+	Command string
+	Status  string
+	Text    string
+}
+
+func (e *imapError) Error() string {
+	return fmt.Sprintf("imap %s: %s %s", e.Command, e.Status, e.Text)
+}
+
+// imapClient is a minimal IMAP4rev1 client covering what polling a single
+// mailbox needs: LOGIN, SELECT, UID SEARCH, UID FETCH and UID STORE.
+type imapClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+	tag  int
+}
+
+// dialIMAP connects to the configured server, upgrades the connection when
+// STARTTLS is configured and logs in.
+func dialIMAP(ctx context.Context, cfg Config) (*imapClient, error) {
+	addr := net.JoinHostPort(cfg.IMAPHost, strconv.Itoa(cfg.IMAPPort))
+	dialer := &net.Dialer{Timeout: imapDialTimeout}
+	var conn net.Conn
+	var err error
+	if cfg.IMAPSecurity == securityTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: cfg.IMAPHost}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("imap dial %s: %w", addr, err)
+	}
+	c := &imapClient{conn: conn, r: bufio.NewReader(conn)}
+	if err := c.readGreeting(ctx); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if cfg.IMAPSecurity == securityStartTLS {
+		if _, err := c.command(ctx, "STARTTLS"); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: cfg.IMAPHost})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("imap starttls: %w", err)
+		}
+		c.conn = tlsConn
+		c.r = bufio.NewReader(tlsConn)
+	}
+	if err := c.login(ctx, cfg.Username, cfg.Password); err != nil {
+		_ = c.conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// withDeadline bounds an exchange by ctx and the command timeout.
+func (c *imapClient) withDeadline(ctx context.Context) func() {
+	deadline := time.Now().Add(imapCommandTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = c.conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.conn.SetDeadline(time.Now())
+	})
+	return func() {
+		stop()
+		_ = c.conn.SetDeadline(time.Time{})
+	}
+}
+
+func (c *imapClient) readGreeting(ctx context.Context) error {
+	defer c.withDeadline(ctx)()
+	line, err := c.readLine()
+	if err != nil {
+		return fmt.Errorf("imap greeting: %w", err)
+	}
+	if !strings.HasPrefix(line, "* OK") && !strings.HasPrefix(line, "* PREAUTH") {
+		return fmt.Errorf("imap greeting: %s", line)
+	}
+	return nil
+}
+
+func (c *imapClient) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readResponse reads one response line together with the literals it
+// announces.
+func (c *imapClient) readResponse() (imapResponse, error) {
+	var resp imapResponse
+	var text strings.Builder
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return imapResponse{}, err
+		}
+		size, prefix, ok := literalSize(line)
+		if !ok {
+			text.WriteString(line)
+			resp.Text = text.String()
+			return resp, nil
+		}
+		if size > imapMaxLiteral {
+			return imapResponse{}, fmt.Errorf("imap literal of %d bytes exceeds limit", size)
+		}
+		text.WriteString(prefix)
+		literal := make([]byte, size)
+		if _, err := io.ReadFull(c.r, literal); err != nil {
+			return imapResponse{}, err
+		}
+		resp.Literals = append(resp.Literals, literal)
+	}
+}
+
+// literalSize parses a trailing {n} literal announcement.
+func literalSize(line string) (int, string, bool) {
+	if !strings.HasSuffix(line, "}") {
+		return 0, "", false
+	}
+	start := strings.LastIndex(line, "{")
+	if start < 0 {
+		return 0, "", false
+	}
+	size, err := strconv.Atoi(strings.TrimSuffix(line[start+1:len(line)-1], "+"))
+	if err != nil || size < 0 {
+		return 0, "", false
+	}
+	return size, line[:start], true
+}
+
+// command sends a command and collects untagged responses until its tagged
+// completion. NO and BAD completions are returned as *imapError.
+func (c *imapClient) command(ctx context.Context, format string, args ...any) ([]imapResponse, error) {
+	defer c.withDeadline(ctx)()
+	c.tag++
+	tag := "m" + strconv.Itoa(c.tag)
+	cmd := fmt.Sprintf(format, args...)
+	if _, err := io.WriteString(c.conn, tag+" "+cmd+"\r\n"); err != nil {
+		return nil, fmt.Errorf("imap write: %w", err)
+	}
+	name := strings.Fields(cmd)[0]
+	if name == "UID" {
+		name = strings.Join(strings.Fields(cmd)[:2], " ")
+	}
+	var responses []imapResponse
+	for {
+		resp, err := c.readResponse()
+		if err != nil {
+			return nil, fmt.Errorf("imap %s: %w", name, err)
+		}
+		if strings.HasPrefix(resp.Text, tag+" ") {
+			status, text, _ := strings.Cut(strings.TrimPrefix(resp.Text, tag+" "), " ")
+			if !strings.EqualFold(status, "OK") {
+				return nil, &imapError{Command: name, Status: strings.ToUpper(status), Text: text}
+			}
+			return responses, nil
+		}
+		if strings.HasPrefix(resp.Text, "* ") {
+			responses = append(responses, imapResponse{Text: strings.TrimPrefix(resp.Text, "* "), Literals: resp.Literals})
+		}
+		// Continuation requests are not expected: commands never send
+		// literals.
+	}
+}
+
+func (c *imapClient) login(ctx context.Context, username, password string) error {
+	user, err := quoteIMAP(username)
+	if err != nil {
+		return fmt.Errorf("imap login: username %w", err)
+	}
+	pass, err := quoteIMAP(password)
+	if err != nil {
+		return fmt.Errorf("imap login: password %w", err)
+	}
+	_, err = c.command(ctx, "LOGIN %s %s", user, pass)
+	return err
+}
+
+// mailboxStatus is the state of a selected mailbox.
+type mailboxStatus struct {
+	UIDValidity uint32
+	UIDNext     uint32
+}
+
+// selectMailbox selects a mailbox read-write. When the server does not
+// report UIDNEXT it is derived from the highest UID in the mailbox.
+func (c *imapClient) selectMailbox(ctx context.Context, mailbox string) (mailboxStatus, error) {
+	name, err := quoteIMAP(mailbox)
+	if err != nil {
+		return mailboxStatus{}, fmt.Errorf("imap select: mailbox %w", err)
+	}
+	responses, err := c.command(ctx, "SELECT %s", name)
+	if err != nil {
+		return mailboxStatus{}, err
+	}
+	var status mailboxStatus
+	for _, resp := range responses {
+		if value, ok := responseCode(resp.Text, "UIDVALIDITY"); ok {
+			status.UIDValidity = value
+		}
+		if value, ok := responseCode(resp.Text, "UIDNEXT"); ok {
+			status.UIDNext = value
+		}
+	}
+	if status.UIDNext == 0 {
+		uids, err := c.searchUIDs(ctx, 1)
+		if err != nil {
+			return mailboxStatus{}, err
+		}
+		status.UIDNext = 1
+		for _, uid := range uids {
+			status.UIDNext = max(status.UIDNext, uid+1)
+		}
+	}
+	return status, nil
+}
+
+// responseCode reads a numeric response code such as "OK [UIDNEXT 42]".
+func responseCode(text, code string) (uint32, bool) {
+	marker := "[" + code + " "
+	idx := strings.Index(strings.ToUpper(text), marker)
+	if idx < 0 {
+		return 0, false
+	}
+	rest := text[idx+len(marker):]
+	end := strings.Index(rest, "]")
+	if end < 0 {
+		return 0, false
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(rest[:end]), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint32(value), true
+}
+
+// searchUIDs returns the UIDs of the messages with a UID of at least from.
+func (c *imapClient) searchUIDs(ctx context.Context, from uint32) ([]uint32, error) {
+	responses, err := c.command(ctx, "UID SEARCH UID %d:*", from)
+	if err != nil {
+		return nil, err
+	}
+	var uids []uint32
+	for _, resp := range responses {
+		fields := strings.Fields(resp.Text)
+		if len(fields) == 0 || !strings.EqualFold(fields[0], "SEARCH") {
+			continue
+		}
+		for _, field := range fields[1:] {
+			uid, err := strconv.ParseUint(field, 10, 32)
+			// "n:*" includes the highest UID even when it is below n.
+			if err != nil || uint32(uid) < from {
+				continue
+			}
+			uids = append(uids, uint32(uid))
+		}
+	}
+	return uids, nil
+}
+
+// fetchMessage returns the full raw message without setting \Seen.
+func (c *imapClient) fetchMessage(ctx context.Context, uid uint32) ([]byte, error) {
+	responses, err := c.command(ctx, "UID FETCH %d (BODY.PEEK[])", uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, resp := range responses {
+		if strings.Contains(strings.ToUpper(resp.Text), "FETCH") && len(resp.Literals) > 0 {
+			return resp.Literals[0], nil
+		}
+	}
+	return nil, errMessageNotFound
+}
+
+var errMessageNotFound = errors.New("imap message not found")
+
+func (c *imapClient) markSeen(ctx context.Context, uid uint32) error {
+	_, err := c.command(ctx, `UID STORE %d +FLAGS.SILENT (\Seen)`, uid)
+	return err
+}
+
+func (c *imapClient) noop(ctx context.Context) error {
+	_, err := c.command(ctx, "NOOP")
+	return err
+}
+
+// close logs out and closes the connection.
+func (c *imapClient) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, _ = c.command(ctx, "LOGOUT")
+	_ = c.conn.Close()
+}
+
+// quoteIMAP renders s as an IMAP quoted string.
+func quoteIMAP(s string) (string, error) {
+	if strings.ContainsAny(s, "\r\n\x00") {
+		return "", fmt.Errorf("must not contain line breaks")
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`, nil
+}
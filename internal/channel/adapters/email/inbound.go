@@ -0,0 +1,328 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/adapters/common"
+)
+
+const (
+	pollMinBackoff = 5 * time.Second
+	pollMaxBackoff = 5 * time.Minute
+)
+
+// conversationType marks mail conversations. It is not a direct type, so
+// whether a mail starts a chat turn is decided by the trigger rule through
+// the is_mentioned metadata, and mail from unbound senders is dropped
+// without an auto-reply.
+const conversationType = "email"
+
+// mailboxState tracks the polling position in the mailbox.
+type mailboxState struct {
+	uidValidity uint32
+	next        uint32
+}
+
+// openMailbox logs in and selects the configured mailbox.
+func openMailbox(ctx context.Context, emailCfg Config) (*imapClient, mailboxStatus, error) {
+	c, err := dialIMAP(ctx, emailCfg)
+	if err != nil {
+		return nil, mailboxStatus{}, err
+	}
+	status, err := c.selectMailbox(ctx, emailCfg.Mailbox)
+	if err != nil {
+		c.close()
+		return nil, mailboxStatus{}, err
+	}
+	return c, status, nil
+}
+
+// Connect logs in over IMAP and polls the mailbox for mail that arrives
+// from now on. Fetched mail is marked \Seen.
+func (a *EmailAdapter) Connect(ctx context.Context, cfg channel.ChannelConfig, handler channel.InboundHandler) (channel.Connection, error) {
+	a.logger.Info("start", slog.String("config_id", cfg.ID))
+	emailCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		a.logger.Error("decode config failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		return nil, err
+	}
+	c, status, err := openMailbox(ctx, emailCfg)
+	if err != nil {
+		a.logger.Error("open mailbox failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		return nil, err
+	}
+	connCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.runPoll(connCtx, cfg, emailCfg, c, mailboxState{uidValidity: status.UIDValidity, next: status.UIDNext}, handler)
+	}()
+	stop := func(stopCtx context.Context) error {
+		a.logger.Info("stop", slog.String("config_id", cfg.ID))
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-stopCtx.Done():
+			return stopCtx.Err()
+		}
+	}
+	return channel.NewConnection(cfg, stop), nil
+}
+
+// runPoll checks the mailbox every poll interval until ctx is canceled,
+// reconnecting with backoff when the connection fails.
+func (a *EmailAdapter) runPoll(ctx context.Context, cfg channel.ChannelConfig, emailCfg Config, c *imapClient, state mailboxState, handler channel.InboundHandler) {
+	defer func() {
+		if c != nil {
+			c.close()
+		}
+	}()
+	backoff := pollMinBackoff
+	wait := emailCfg.PollInterval
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		if c == nil {
+			conn, status, err := openMailbox(ctx, emailCfg)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				wait = backoff
+				backoff = min(backoff*2, pollMaxBackoff)
+				a.logger.Warn("reconnect failed", slog.String("config_id", cfg.ID), slog.Duration("retry_in", wait), slog.Any("error", err))
+				continue
+			}
+			c = conn
+			if status.UIDValidity != state.uidValidity {
+				// UIDs from the old mailbox no longer mean anything.
+				a.logger.Warn("mailbox uidvalidity changed", slog.String("config_id", cfg.ID))
+				state = mailboxState{uidValidity: status.UIDValidity, next: status.UIDNext}
+			}
+		}
+		if err := a.pollOnce(ctx, cfg, emailCfg, c, &state, handler); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.close()
+			c = nil
+			wait = backoff
+			backoff = min(backoff*2, pollMaxBackoff)
+			a.logger.Warn("poll failed", slog.String("config_id", cfg.ID), slog.Duration("retry_in", wait), slog.Any("error", err))
+			continue
+		}
+		backoff = pollMinBackoff
+		wait = emailCfg.PollInterval
+	}
+}
+
+// pollOnce fetches the mail that arrived since the last poll and dispatches
+// it to handler.
+func (a *EmailAdapter) pollOnce(ctx context.Context, cfg channel.ChannelConfig, emailCfg Config, c *imapClient, state *mailboxState, handler channel.InboundHandler) error {
+	// NOOP lets the server report mail that arrived since the last command.
+	if err := c.noop(ctx); err != nil {
+		return err
+	}
+	uids, err := c.searchUIDs(ctx, state.next)
+	if err != nil {
+		return err
+	}
+	slices.Sort(uids)
+	for _, uid := range uids {
+		raw, err := c.fetchMessage(ctx, uid)
+		if errors.Is(err, errMessageNotFound) {
+			// Expunged between SEARCH and FETCH.
+			state.next = uid + 1
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := c.markSeen(ctx, uid); err != nil {
+			return err
+		}
+		state.next = uid + 1
+		parsed, err := parseMail(raw)
+		if err != nil {
+			a.logger.Warn("parse mail failed", slog.String("config_id", cfg.ID), slog.Uint64("uid", uint64(uid)), slog.Any("error", err))
+			continue
+		}
+		a.handleMail(ctx, cfg, emailCfg, state.uidValidity, uid, parsed, handler)
+	}
+	return nil
+}
+
+// handleMail converts a parsed mail into an inbound message and dispatches
+// it to handler.
+func (a *EmailAdapter) handleMail(ctx context.Context, cfg channel.ChannelConfig, emailCfg Config, uidValidity, uid uint32, parsed parsedMail, handler channel.InboundHandler) {
+	msg, ok := a.toInboundMessage(cfg, emailCfg, uidValidity, uid, parsed)
+	if !ok {
+		return
+	}
+	a.rememberThread(emailCfg.Address, msg.Message.ID, threadInfo{
+		subject:    parsed.Subject,
+		references: append(slices.Clone(parsed.References), msg.Message.ID),
+	})
+	a.logger.Info(
+		"inbound received",
+		slog.String("config_id", cfg.ID),
+		slog.String("from", msg.Sender.SubjectID),
+		slog.String("thread_id", msg.Conversation.ThreadID),
+		slog.String("subject", common.SummarizeText(parsed.Subject)),
+		slog.Any("triggers", msg.Metadata["is_mentioned"]),
+		slog.Int("attachments", len(msg.Message.Attachments)),
+	)
+	go func() {
+		if err := handler(ctx, cfg, msg); err != nil {
+			a.logger.Error("handle inbound failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+	}()
+}
+
+func (a *EmailAdapter) toInboundMessage(cfg channel.ChannelConfig, emailCfg Config, uidValidity, uid uint32, parsed parsedMail) (channel.InboundMessage, bool) {
+	if parsed.From == nil {
+		return channel.InboundMessage{}, false
+	}
+	from := strings.ToLower(parsed.From.Address)
+	if from == "" || from == emailCfg.Address {
+		return channel.InboundMessage{}, false
+	}
+	text := strings.TrimSpace(parsed.Text)
+	if text == "" && len(parsed.Attachments) == 0 {
+		text = parsed.Subject
+	}
+	if text == "" {
+		return channel.InboundMessage{}, false
+	}
+	messageID := parsed.MessageID
+	if messageID == "" {
+		messageID = fmt.Sprintf("%d.%d@%s", uidValidity, uid, emailCfg.IMAPHost)
+		parsed.MessageID = messageID
+	}
+	replyAddress := from
+	if parsed.ReplyTo != nil && parsed.ReplyTo.Address != "" {
+		replyAddress = strings.ToLower(parsed.ReplyTo.Address)
+	}
+	threadID := parsed.threadRoot()
+	var reply *channel.ReplyRef
+	if parsed.InReplyTo != "" {
+		reply = &channel.ReplyRef{Target: replyAddress, MessageID: parsed.InReplyTo}
+	}
+	triggers := shouldTrigger(emailCfg, parsed)
+	displayName := strings.TrimSpace(parsed.From.Name)
+	if displayName == "" {
+		displayName = from
+	}
+	receivedAt := parsed.Date
+	if receivedAt.IsZero() {
+		receivedAt = time.Now()
+	}
+	attachments := make([]channel.Attachment, 0, len(parsed.Attachments))
+	for _, part := range parsed.Attachments {
+		attachments = append(attachments, channel.Attachment{
+			Type:           attachmentType(part.Mime),
+			PlatformKey:    attachmentKey(uidValidity, uid, part.Index),
+			SourcePlatform: Type.String(),
+			Name:           part.Name,
+			Size:           int64(len(part.Data)),
+			Mime:           part.Mime,
+		})
+	}
+	return channel.InboundMessage{
+		Channel: Type,
+		Message: channel.Message{
+			ID:          messageID,
+			Format:      channel.MessageFormatPlain,
+			Text:        text,
+			Attachments: attachments,
+			Thread:      &channel.ThreadRef{ID: threadID},
+			Reply:       reply,
+		},
+		BotID:       cfg.BotID,
+		ReplyTarget: joinTarget(replyAddress, threadID),
+		Sender: channel.Identity{
+			SubjectID:   from,
+			DisplayName: displayName,
+			Attributes: map[string]string{
+				"email":    from,
+				"username": displayName,
+			},
+		},
+		Conversation: channel.Conversation{
+			ID:       replyAddress,
+			Type:     conversationType,
+			Name:     parsed.Subject,
+			ThreadID: threadID,
+		},
+		ReceivedAt: receivedAt,
+		Source:     "email",
+		Metadata: map[string]any{
+			"is_mentioned":    triggers,
+			"is_reply_to_bot": triggers && isReplyToBot(emailCfg, parsed),
+			"subject":         parsed.Subject,
+		},
+	}, true
+}
+
+// shouldTrigger applies the configured trigger rule. Automated mail never
+// starts a chat turn so the bot does not converse with auto-responders.
+func shouldTrigger(emailCfg Config, parsed parsedMail) bool {
+	if parsed.Automated {
+		return false
+	}
+	switch emailCfg.Trigger {
+	case TriggerAll:
+		return true
+	case TriggerDirect:
+		if isReplyToBot(emailCfg, parsed) {
+			return true
+		}
+		for _, to := range parsed.To {
+			if strings.EqualFold(to.Address, emailCfg.Address) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// isReplyToBot reports whether the mail answers mail the bot sent.
+func isReplyToBot(emailCfg Config, parsed parsedMail) bool {
+	if isOwnMessageID(parsed.InReplyTo, emailCfg.Address) {
+		return true
+	}
+	for _, ref := range parsed.References {
+		if isOwnMessageID(ref, emailCfg.Address) {
+			return true
+		}
+	}
+	return false
+}
+
+func attachmentType(mime string) channel.AttachmentType {
+	switch {
+	case mime == "image/gif":
+		return channel.AttachmentGIF
+	case strings.HasPrefix(mime, "image/"):
+		return channel.AttachmentImage
+	case strings.HasPrefix(mime, "audio/"):
+		return channel.AttachmentAudio
+	case strings.HasPrefix(mime, "video/"):
+		return channel.AttachmentVideo
+	default:
+		return channel.AttachmentFile
+	}
+}
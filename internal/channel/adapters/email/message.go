@@ -0,0 +1,544 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxMIMEDepth bounds multipart nesting while walking a message.
+const maxMIMEDepth = 10
+
+// mailPart is an attachment of a parsed or outgoing message. Index is the
+// attachment's position in the parsed message and identifies it when the
+// message is fetched again.
+type mailPart struct {
+	Index int
+	Name  string
+	Mime  string
+	Data  []byte
+}
+
+// parsedMail holds the fields of an inbound message the adapter uses.
+// Message IDs are stored without angle brackets.
+type parsedMail struct {
+	MessageID   string
+	InReplyTo   string
+	References  []string
+	From        *mail.Address
+	ReplyTo     *mail.Address
+	To          []*mail.Address
+	Cc          []*mail.Address
+	Subject     string
+	Date        time.Time
+	Text        string
+	Attachments []mailPart
+	// Automated is set for auto-replies and bulk or list mail, which never
+	// start a chat turn.
+	Automated bool
+}
+
+// threadRoot returns the Message-ID of the first message of the thread.
+func (m parsedMail) threadRoot() string {
+	if len(m.References) > 0 {
+		return m.References[0]
+	}
+	if m.InReplyTo != "" {
+		return m.InReplyTo
+	}
+	return m.MessageID
+}
+
+var wordDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// charsetReader decodes the charsets that can be converted without tables.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return input, nil
+	case "iso-8859-1", "latin1", "l1":
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		return strings.NewReader(latin1ToUTF8(data)), nil
+	default:
+		return nil, fmt.Errorf("unsupported charset %q", charset)
+	}
+}
+
+func latin1ToUTF8(data []byte) string {
+	var b strings.Builder
+	b.Grow(len(data))
+	for _, c := range data {
+		b.WriteRune(rune(c))
+	}
+	return b.String()
+}
+
+// decodeText converts a text body to UTF-8. Unknown charsets are kept when
+// they happen to be valid UTF-8 and replaced otherwise.
+func decodeText(data []byte, charset string) string {
+	if r, err := charsetReader(charset, bytes.NewReader(data)); err == nil {
+		if decoded, err := io.ReadAll(r); err == nil {
+			data = decoded
+		}
+	}
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	return strings.ToValidUTF8(string(data), "�")
+}
+
+func decodeHeader(value string) string {
+	decoded, err := wordDecoder.DecodeHeader(value)
+	if err != nil {
+		return strings.TrimSpace(value)
+	}
+	return strings.TrimSpace(decoded)
+}
+
+func parseAddressList(header mail.Header, key string) []*mail.Address {
+	value := header.Get(key)
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	parser := mail.AddressParser{WordDecoder: wordDecoder}
+	list, err := parser.ParseList(value)
+	if err != nil {
+		return nil
+	}
+	return list
+}
+
+// parseMessageIDs extracts <id> tokens from a Message-ID, In-Reply-To or
+// References header.
+func parseMessageIDs(value string) []string {
+	var ids []string
+	for {
+		start := strings.Index(value, "<")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(value[start:], ">")
+		if end < 0 {
+			break
+		}
+		if id := strings.TrimSpace(value[start+1 : start+end]); id != "" {
+			ids = append(ids, id)
+		}
+		value = value[start+end+1:]
+	}
+	if len(ids) == 0 {
+		// Some clients omit the angle brackets.
+		for _, field := range strings.Fields(value) {
+			if strings.Contains(field, "@") {
+				ids = append(ids, field)
+			}
+		}
+	}
+	return ids
+}
+
+func trimMessageID(value string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(value), "<"), ">")
+}
+
+// parseMail parses a raw RFC 5322 message, preferring the text/plain body
+// and falling back to text/html converted to text.
+func parseMail(raw []byte) (parsedMail, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return parsedMail{}, fmt.Errorf("parse mail: %w", err)
+	}
+	parsed := parsedMail{
+		Subject: decodeHeader(msg.Header.Get("Subject")),
+		To:      parseAddressList(msg.Header, "To"),
+		Cc:      parseAddressList(msg.Header, "Cc"),
+	}
+	if ids := parseMessageIDs(msg.Header.Get("Message-ID")); len(ids) > 0 {
+		parsed.MessageID = ids[0]
+	}
+	if ids := parseMessageIDs(msg.Header.Get("In-Reply-To")); len(ids) > 0 {
+		parsed.InReplyTo = ids[0]
+	}
+	parsed.References = parseMessageIDs(msg.Header.Get("References"))
+	if from := parseAddressList(msg.Header, "From"); len(from) > 0 {
+		parsed.From = from[0]
+	}
+	if replyTo := parseAddressList(msg.Header, "Reply-To"); len(replyTo) > 0 {
+		parsed.ReplyTo = replyTo[0]
+	}
+	if date, err := msg.Header.Date(); err == nil {
+		parsed.Date = date
+	}
+	parsed.Automated = isAutomated(msg.Header)
+
+	var body bodyParts
+	if err := body.walk(textproto.MIMEHeader(msg.Header), msg.Body, 0); err != nil {
+		return parsedMail{}, err
+	}
+	text := body.plain
+	if strings.TrimSpace(text) == "" {
+		text = htmlToText(body.html)
+	}
+	parsed.Text = stripQuotedReply(normalizeNewlines(text))
+	parsed.Attachments = body.attachments
+	return parsed, nil
+}
+
+// isAutomated reports mail that must not be answered automatically
+// (RFC 3834 and the common Precedence conventions).
+func isAutomated(header mail.Header) bool {
+	if value := strings.ToLower(strings.TrimSpace(header.Get("Auto-Submitted"))); value != "" && value != "no" {
+		return true
+	}
+	switch strings.ToLower(strings.TrimSpace(header.Get("Precedence"))) {
+	case "bulk", "list", "junk", "auto_reply":
+		return true
+	}
+	return header.Get("List-Id") != "" || header.Get("X-Autoreply") != "" || header.Get("X-Autorespond") != ""
+}
+
+// bodyParts collects the text bodies and attachments of a MIME tree.
+type bodyParts struct {
+	plain       string
+	html        string
+	attachments []mailPart
+}
+
+func (b *bodyParts) walk(header textproto.MIMEHeader, body io.Reader, depth int) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	if strings.HasPrefix(mediaType, "multipart/") && depth < maxMIMEDepth {
+		boundary := params["boundary"]
+		if boundary == "" {
+			return fmt.Errorf("parse mail: multipart without boundary")
+		}
+		reader := multipart.NewReader(body, boundary)
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("parse mail: %w", err)
+			}
+			if err := b.walk(part.Header, part, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	data, err := io.ReadAll(transferDecoder(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return fmt.Errorf("parse mail: decode part: %w", err)
+	}
+	disposition, dispParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	name := dispParams["filename"]
+	if name == "" {
+		name = params["name"]
+	}
+	name = decodeHeader(name)
+	isText := mediaType == "text/plain" || mediaType == "text/html"
+	if disposition != "attachment" && name == "" && isText {
+		text := decodeText(data, params["charset"])
+		if mediaType == "text/plain" {
+			b.plain = appendText(b.plain, text)
+		} else {
+			b.html = appendText(b.html, text)
+		}
+		return nil
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if name == "" {
+		name = defaultPartName(mediaType)
+	}
+	b.attachments = append(b.attachments, mailPart{
+		Index: len(b.attachments),
+		Name:  name,
+		Mime:  mediaType,
+		Data:  data,
+	})
+	return nil
+}
+
+func appendText(existing, text string) string {
+	if strings.TrimSpace(existing) == "" {
+		return text
+	}
+	return existing + "\n\n" + text
+}
+
+func transferDecoder(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &base64Cleaner{r: body})
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// base64Cleaner drops the line breaks and whitespace base64 bodies are
+// wrapped with, which encoding/base64 only tolerates as \r and \n.
+type base64Cleaner struct {
+	r io.Reader
+}
+
+func (c *base64Cleaner) Read(p []byte) (int, error) {
+	for {
+		n, err := c.r.Read(p)
+		kept := 0
+		for _, ch := range p[:n] {
+			if ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n' {
+				continue
+			}
+			p[kept] = ch
+			kept++
+		}
+		if kept > 0 || err != nil {
+			return kept, err
+		}
+	}
+}
+
+func defaultPartName(mediaType string) string {
+	switch {
+	case mediaType == "message/rfc822":
+		return "message.eml"
+	case strings.HasPrefix(mediaType, "image/"):
+		return "image"
+	case strings.HasPrefix(mediaType, "audio/"):
+		return "audio"
+	case strings.HasPrefix(mediaType, "video/"):
+		return "video"
+	default:
+		return "attachment"
+	}
+}
+
+func normalizeNewlines(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\r", "\n")
+}
+
+var (
+	htmlDropPattern    = regexp.MustCompile(`(?is)<(script|style|head)\b.*?</(script|style|head)>`)
+	htmlBreakPattern   = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|tr|h[1-6]|blockquote)>`)
+	htmlTagPattern     = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLinesPattern  = regexp.MustCompile(`\n{3,}`)
+	replyHeaderPattern = regexp.MustCompile(`^On .+ wrote:$`)
+)
+
+// htmlToText reduces an HTML body to plain text.
+func htmlToText(body string) string {
+	text := htmlDropPattern.ReplaceAllString(body, "")
+	text = htmlBreakPattern.ReplaceAllString(text, "\n")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	text = html.UnescapeString(normalizeNewlines(text))
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// stripQuotedReply removes the quoted previous message and the signature
+// from a reply so only the new text remains. The text is kept as is when
+// nothing would be left.
+func stripQuotedReply(text string) string {
+	lines := strings.Split(text, "\n")
+	kept := make([]string, 0, len(lines))
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if line == "-- " || trimmed == "-----Original Message-----" || replyHeaderPattern.MatchString(trimmed) {
+			break
+		}
+		// Long "On ... wrote:" headers are often wrapped over two lines.
+		if i+1 < len(lines) && strings.HasPrefix(trimmed, "On ") && replyHeaderPattern.MatchString(trimmed+" "+strings.TrimSpace(lines[i+1])) {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	stripped := strings.TrimSpace(strings.Join(kept, "\n"))
+	if stripped == "" {
+		return strings.TrimSpace(text)
+	}
+	return stripped
+}
+
+// outgoingMail is a message sent by the bot.
+type outgoingMail struct {
+	From        mail.Address
+	To          []string
+	Subject     string
+	MessageID   string
+	InReplyTo   string
+	References  []string
+	Date        time.Time
+	Text        string
+	Attachments []mailPart
+}
+
+// newMessageID returns a Message-ID whose local part ends in ".memoh", which
+// identifies replies to the bot's own mail.
+func newMessageID(address string) string {
+	var buf [12]byte
+	_, _ = rand.Read(buf[:])
+	domain := "localhost"
+	if idx := strings.LastIndex(address, "@"); idx >= 0 && idx < len(address)-1 {
+		domain = address[idx+1:]
+	}
+	return hex.EncodeToString(buf[:]) + messageIDSuffix + "@" + domain
+}
+
+const messageIDSuffix = ".memoh"
+
+// isOwnMessageID reports whether id was generated by newMessageID for address.
+func isOwnMessageID(id, address string) bool {
+	idx := strings.LastIndex(address, "@")
+	if idx < 0 {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(id), messageIDSuffix+strings.ToLower(address[idx:]))
+}
+
+func formatMessageIDs(ids []string) string {
+	formatted := make([]string, 0, len(ids))
+	for _, id := range ids {
+		formatted = append(formatted, "<"+id+">")
+	}
+	return strings.Join(formatted, " ")
+}
+
+// bytes renders the message with CRLF line endings, as text/plain or as
+// multipart/mixed when it has attachments.
+func (m outgoingMail) bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	writeHeader := func(key, value string) {
+		_, _ = w.WriteString(key + ": " + value + "\r\n")
+	}
+	writeHeader("From", m.From.String())
+	writeHeader("To", strings.Join(m.To, ", "))
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	writeHeader("Date", m.Date.Format(time.RFC1123Z))
+	writeHeader("Message-ID", "<"+m.MessageID+">")
+	if m.InReplyTo != "" {
+		writeHeader("In-Reply-To", "<"+m.InReplyTo+">")
+	}
+	if len(m.References) > 0 {
+		writeHeader("References", formatMessageIDs(m.References))
+	}
+	if m.InReplyTo != "" {
+		writeHeader("Auto-Submitted", "auto-replied")
+	} else {
+		writeHeader("Auto-Submitted", "auto-generated")
+	}
+	writeHeader("MIME-Version", "1.0")
+	if len(m.Attachments) == 0 {
+		writeHeader("Content-Type", "text/plain; charset=utf-8")
+		writeHeader("Content-Transfer-Encoding", "quoted-printable")
+		_, _ = w.WriteString("\r\n")
+		if err := writeQuotedPrintable(w, m.Text); err != nil {
+			return nil, err
+		}
+		if err := w.Flush(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	mw := multipart.NewWriter(w)
+	writeHeader("Content-Type", mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": mw.Boundary()}))
+	_, _ = w.WriteString("\r\n")
+	if strings.TrimSpace(m.Text) != "" {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {"text/plain; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(part, m.Text); err != nil {
+			return nil, err
+		}
+	}
+	for _, att := range m.Attachments {
+		contentType := att.Mime
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {contentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": att.Name})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64Lines(part, att.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, text string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(normalizeNewlines(text), "\n", "\r\n"))); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// writeBase64Lines writes data as base64 wrapped at 76 characters.
+func writeBase64Lines(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := min(76, len(encoded))
+		if _, err := io.WriteString(w, encoded[:n]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
+
+// replySubject prefixes subject with "Re: " unless it already is a reply.
+func replySubject(subject string) string {
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		return ""
+	}
+	if len(subject) >= 3 && strings.EqualFold(subject[:3], "re:") {
+		return subject
+	}
+	return "Re: " + subject
+}
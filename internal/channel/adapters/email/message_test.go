@@ -0,0 +1,129 @@
+package email
+
+import (
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMailAlternativeAndEncodings(t *testing.T) {
+	raw := strings.ReplaceAll(`From: =?utf-8?q?Jos=C3=A9?= <Jose@Example.com>
+To: bot@example.com
+Subject: =?utf-8?b?w6l0w6k=?= plans
+Message-ID: <x1@example.com>
+References: <r0@example.com>
+  <r1@example.com>
+In-Reply-To: <r1@example.com>
+Content-Type: multipart/alternative; boundary=alt
+
+--alt
+Content-Type: text/plain; charset=iso-8859-1
+Content-Transfer-Encoding: quoted-printable
+
+Caf=E9 at noon?
+--alt
+Content-Type: text/html; charset=utf-8
+
+<p>ignored</p>
+--alt--
+`, "\n", "\r\n")
+	parsed, err := parseMail([]byte(raw))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if parsed.From.Name != "José" || parsed.From.Address != "Jose@Example.com" {
+		t.Fatalf("unexpected from: %#v", parsed.From)
+	}
+	if parsed.Text != "Café at noon?" {
+		t.Fatalf("unexpected text %q", parsed.Text)
+	}
+	if parsed.MessageID != "x1@example.com" || parsed.InReplyTo != "r1@example.com" || len(parsed.References) != 2 || parsed.threadRoot() != "r0@example.com" {
+		t.Fatalf("unexpected ids: %#v", parsed)
+	}
+	if parsed.Subject != "été plans" {
+		t.Fatalf("unexpected subject %q", parsed.Subject)
+	}
+}
+
+func TestParseMailHTMLOnly(t *testing.T) {
+	raw := "From: a@example.com\r\nContent-Type: text/html; charset=utf-8\r\n\r\n<html><head><style>p{}</style></head><body><p>Hello &amp; welcome</p><div>line<br>two</div></body></html>"
+	parsed, err := parseMail([]byte(raw))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if parsed.Text != "Hello & welcome\nline\ntwo" {
+		t.Fatalf("unexpected text %q", parsed.Text)
+	}
+	if parsed.threadRoot() != "" {
+		t.Fatalf("expected no thread root, got %q", parsed.threadRoot())
+	}
+}
+
+func TestStripQuotedReply(t *testing.T) {
+	tests := map[string]string{
+		"Sounds good.\n\nOn Tue, Jan 3, 2026 at 9:00 AM Bot <bot@example.com> wrote:\n> earlier": "Sounds good.",
+		"Yes\nOn Tue, Jan 3, 2026 at 9:00 AM Bot\n<bot@example.com> wrote:\n> earlier":           "Yes",
+		"Inline\n> quoted\nanswer":                   "Inline\nanswer",
+		"Thanks\n-- \nAlice\nAcme Inc.":              "Thanks",
+		"Top\n-----Original Message-----\nFrom: bot": "Top",
+		"> only quoted":                              "> only quoted",
+	}
+	for input, want := range tests {
+		if got := stripQuotedReply(input); got != want {
+			t.Fatalf("stripQuotedReply(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestOutgoingMailRoundTrip(t *testing.T) {
+	out := outgoingMail{
+		From:        mail.Address{Name: "Mémoh", Address: "bot@example.com"},
+		To:          []string{"alice@example.com"},
+		Subject:     "Re: Café",
+		MessageID:   newMessageID("bot@example.com"),
+		InReplyTo:   "m1@example.com",
+		References:  []string{"m0@example.com", "m1@example.com"},
+		Date:        time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Text:        "Line one with a very long sentence that has to be wrapped by quoted-printable encoding at some point.\nÜmlaut line",
+		Attachments: []mailPart{{Name: "résumé.pdf", Mime: "application/pdf", Data: []byte(strings.Repeat("x", 200))}},
+	}
+	data, err := out.bytes()
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	parsed, err := parseMail(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if parsed.Subject != "Re: Café" || parsed.From.Name != "Mémoh" || !parsed.Automated {
+		t.Fatalf("unexpected headers: %#v", parsed)
+	}
+	if parsed.Text != out.Text {
+		t.Fatalf("unexpected text %q", parsed.Text)
+	}
+	if parsed.InReplyTo != "m1@example.com" || len(parsed.References) != 2 {
+		t.Fatalf("unexpected threading: %#v", parsed)
+	}
+	if len(parsed.Attachments) != 1 || parsed.Attachments[0].Name != "résumé.pdf" || len(parsed.Attachments[0].Data) != 200 {
+		t.Fatalf("unexpected attachments: %#v", parsed.Attachments)
+	}
+	for _, line := range strings.Split(string(data), "\r\n") {
+		if len(line) > 998 {
+			t.Fatalf("line exceeds SMTP limit: %d", len(line))
+		}
+	}
+}
+
+func TestOwnMessageID(t *testing.T) {
+	id := newMessageID("Bot@Example.com")
+	if !strings.HasSuffix(id, ".memoh@Example.com") {
+		t.Fatalf("unexpected message id %q", id)
+	}
+	if !isOwnMessageID(id, "bot@example.com") || isOwnMessageID("abc@example.com", "bot@example.com") || isOwnMessageID(id, "bot@other.org") {
+		t.Fatal("unexpected isOwnMessageID result")
+	}
+	if replySubject("Re: x") != "Re: x" || replySubject("RE: x") != "RE: x" || replySubject("x") != "Re: x" {
+		t.Fatal("unexpected replySubject result")
+	}
+}
@@ -0,0 +1,107 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// emailOutboundStream buffers the reply and sends it as one mail when the
+// final message arrives, with the attachments collected along the way.
+type emailOutboundStream struct {
+	adapter     *EmailAdapter
+	cfg         channel.ChannelConfig
+	target      string
+	reply       *channel.ReplyRef
+	closed      atomic.Bool
+	mu          sync.Mutex
+	buf         strings.Builder
+	attachments []channel.Attachment
+}
+
+func (s *emailOutboundStream) Push(ctx context.Context, event channel.StreamEvent) error {
+	if s == nil || s.adapter == nil {
+		return fmt.Errorf("email stream not configured")
+	}
+	if s.closed.Load() {
+		return fmt.Errorf("email stream is closed")
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	switch event.Type {
+	case channel.StreamEventDelta:
+		if event.Delta == "" || event.Phase == channel.StreamPhaseReasoning {
+			return nil
+		}
+		s.mu.Lock()
+		s.buf.WriteString(event.Delta)
+		s.mu.Unlock()
+		return nil
+	case channel.StreamEventAttachment:
+		s.mu.Lock()
+		s.attachments = append(s.attachments, event.Attachments...)
+		s.mu.Unlock()
+		return nil
+	case channel.StreamEventFinal:
+		s.mu.Lock()
+		text := strings.TrimSpace(s.buf.String())
+		s.mu.Unlock()
+		msg := channel.Message{Format: channel.MessageFormatPlain}
+		if event.Final != nil {
+			msg = event.Final.Message
+		}
+		if text != "" {
+			msg.Text = text
+			msg.Parts = nil
+		}
+		return s.send(ctx, msg)
+	case channel.StreamEventError:
+		errText := strings.TrimSpace(event.Error)
+		if errText == "" {
+			return nil
+		}
+		return s.send(ctx, channel.Message{Format: channel.MessageFormatPlain, Text: "Error: " + errText})
+	default:
+		return nil
+	}
+}
+
+// send mails msg together with the pending attachments and resets the
+// buffer.
+func (s *emailOutboundStream) send(ctx context.Context, msg channel.Message) error {
+	s.mu.Lock()
+	msg.Attachments = append(s.attachments, msg.Attachments...)
+	s.attachments = nil
+	s.buf.Reset()
+	s.mu.Unlock()
+	if strings.TrimSpace(msg.PlainText()) == "" && len(msg.Attachments) == 0 {
+		return nil
+	}
+	if s.reply != nil {
+		msg.Reply = s.reply
+	}
+	return s.adapter.Send(ctx, s.cfg, channel.OutboundMessage{Target: s.target, Message: msg})
+}
+
+// Close sends attachments that were pushed without a final message.
+func (s *emailOutboundStream) Close(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	if s.closed.Swap(true) {
+		return nil
+	}
+	return s.send(ctx, channel.Message{Format: channel.MessageFormatPlain})
+}
@@ -0,0 +1,485 @@
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	clientPrefix     = "/_matrix/client/v3"
+	clientV1Prefix   = "/_matrix/client/v1"
+	mediaPrefix      = "/_matrix/media/v3"
+	apiResponseLimit = 16 << 20
+)
+
+// apiError is an error response from the homeserver.
+type apiError struct {
+	Status     int
+	Code       string
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *apiError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("matrix: %s: %s (status %d)", e.Code, e.Message, e.Status)
+	}
+	return fmt.Sprintf("matrix: %s (status %d)", e.Code, e.Status)
+}
+
+// isAPIError reports whether err is a homeserver error with one of codes.
+func isAPIError(err error, codes ...string) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && (apiErr.Status == http.StatusNotFound || apiErr.Code == "M_NOT_FOUND")
+}
+
+// retryAfter returns the backoff requested by a rate limited call, or zero.
+func retryAfter(err error) time.Duration {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.Code == "M_LIMIT_EXCEEDED" {
+		return apiErr.RetryAfter
+	}
+	return 0
+}
+
+var txnCounter atomic.Uint64
+
+// newTxnID returns a transaction ID unique to this process.
+func newTxnID() string {
+	return "memoh" + strconv.FormatInt(time.Now().UnixNano(), 36) + "." + strconv.FormatUint(txnCounter.Add(1), 36)
+}
+
+// client calls the Matrix client-server API with one access token.
+type client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func newClient(httpClient *http.Client, cfg Config) *client {
+	return &client{baseURL: cfg.HomeserverURL, token: cfg.AccessToken, http: httpClient}
+}
+
+// do sends a JSON request and decodes the JSON response into out, which may
+// be nil. path is relative to the host and must already be escaped.
+func (c *client) do(ctx context.Context, method, path string, query url.Values, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("matrix: encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return fmt.Errorf("matrix: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.send(req, out)
+}
+
+func (c *client) send(req *http.Request, out any) error {
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("matrix: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, apiResponseLimit))
+	if err != nil {
+		return fmt.Errorf("matrix: read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return decodeAPIError(resp.StatusCode, data)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("matrix: parse response: %w", err)
+	}
+	return nil
+}
+
+func decodeAPIError(status int, data []byte) error {
+	var body struct {
+		ErrCode      string `json:"errcode"`
+		Error        string `json:"error"`
+		RetryAfterMs int64  `json:"retry_after_ms"`
+	}
+	_ = json.Unmarshal(data, &body)
+	if body.ErrCode == "" {
+		body.ErrCode = "M_UNKNOWN"
+	}
+	return &apiError{
+		Status:     status,
+		Code:       body.ErrCode,
+		Message:    body.Error,
+		RetryAfter: time.Duration(body.RetryAfterMs) * time.Millisecond,
+	}
+}
+
+// seg escapes a path segment such as a room or event ID.
+func seg(value string) string {
+	return url.PathEscape(value)
+}
+
+// event is a room event as returned by /sync and the room APIs.
+type event struct {
+	Type           string          `json:"type"`
+	EventID        string          `json:"event_id"`
+	Sender         string          `json:"sender"`
+	OriginServerTS int64           `json:"origin_server_ts"`
+	StateKey       *string         `json:"state_key,omitempty"`
+	Content        json.RawMessage `json:"content"`
+}
+
+// messageContent is the content of m.room.message events.
+type messageContent struct {
+	MsgType       string          `json:"msgtype"`
+	Body          string          `json:"body"`
+	Format        string          `json:"format,omitempty"`
+	FormattedBody string          `json:"formatted_body,omitempty"`
+	URL           string          `json:"url,omitempty"`
+	FileName      string          `json:"filename,omitempty"`
+	Info          *fileInfo       `json:"info,omitempty"`
+	RelatesTo     *relatesTo      `json:"m.relates_to,omitempty"`
+	Mentions      *mentions       `json:"m.mentions,omitempty"`
+	NewContent    *messageContent `json:"m.new_content,omitempty"`
+}
+
+type fileInfo struct {
+	MimeType string `json:"mimetype,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+	Width    int    `json:"w,omitempty"`
+	Height   int    `json:"h,omitempty"`
+	Duration int64  `json:"duration,omitempty"`
+}
+
+type relatesTo struct {
+	RelType       string     `json:"rel_type,omitempty"`
+	EventID       string     `json:"event_id,omitempty"`
+	Key           string     `json:"key,omitempty"`
+	IsFallingBack bool       `json:"is_falling_back,omitempty"`
+	InReplyTo     *inReplyTo `json:"m.in_reply_to,omitempty"`
+}
+
+type inReplyTo struct {
+	EventID string `json:"event_id"`
+}
+
+type mentions struct {
+	UserIDs []string `json:"user_ids,omitempty"`
+	Room    bool     `json:"room,omitempty"`
+}
+
+type memberContent struct {
+	Membership  string `json:"membership"`
+	DisplayName string `json:"displayname"`
+	IsDirect    bool   `json:"is_direct"`
+}
+
+type syncRoom struct {
+	Timeline struct {
+		Events []event `json:"events"`
+	} `json:"timeline"`
+}
+
+type syncResponse struct {
+	NextBatch string `json:"next_batch"`
+	Rooms     struct {
+		Join   map[string]syncRoom        `json:"join"`
+		Invite map[string]json.RawMessage `json:"invite"`
+	} `json:"rooms"`
+}
+
+func (c *client) whoami(ctx context.Context) (string, error) {
+	var resp struct {
+		UserID string `json:"user_id"`
+	}
+	if err := c.do(ctx, http.MethodGet, clientPrefix+"/account/whoami", nil, nil, &resp); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(resp.UserID) == "" {
+		return "", fmt.Errorf("matrix whoami: empty user_id")
+	}
+	return resp.UserID, nil
+}
+
+type profile struct {
+	DisplayName string `json:"displayname"`
+	AvatarURL   string `json:"avatar_url"`
+}
+
+func (c *client) profile(ctx context.Context, userID string) (profile, error) {
+	var resp profile
+	err := c.do(ctx, http.MethodGet, clientPrefix+"/profile/"+seg(userID), nil, nil, &resp)
+	return resp, err
+}
+
+// sync long-polls for events after since, waiting up to timeout.
+func (c *client) sync(ctx context.Context, since string, timeout time.Duration, filter string) (syncResponse, error) {
+	query := url.Values{"timeout": {strconv.FormatInt(timeout.Milliseconds(), 10)}}
+	if since != "" {
+		query.Set("since", since)
+	}
+	if filter != "" {
+		query.Set("filter", filter)
+	}
+	var resp syncResponse
+	err := c.do(ctx, http.MethodGet, clientPrefix+"/sync", query, nil, &resp)
+	return resp, err
+}
+
+func (c *client) joinRoom(ctx context.Context, roomIDOrAlias string) (string, error) {
+	var resp struct {
+		RoomID string `json:"room_id"`
+	}
+	err := c.do(ctx, http.MethodPost, clientPrefix+"/join/"+seg(roomIDOrAlias), nil, map[string]any{}, &resp)
+	return resp.RoomID, err
+}
+
+// sendEvent sends a room event and returns its ID.
+func (c *client) sendEvent(ctx context.Context, roomID, eventType string, content any) (string, error) {
+	var resp struct {
+		EventID string `json:"event_id"`
+	}
+	path := clientPrefix + "/rooms/" + seg(roomID) + "/send/" + seg(eventType) + "/" + seg(newTxnID())
+	if err := c.do(ctx, http.MethodPut, path, nil, content, &resp); err != nil {
+		return "", err
+	}
+	return resp.EventID, nil
+}
+
+func (c *client) redact(ctx context.Context, roomID, eventID string) error {
+	path := clientPrefix + "/rooms/" + seg(roomID) + "/redact/" + seg(eventID) + "/" + seg(newTxnID())
+	return c.do(ctx, http.MethodPut, path, nil, map[string]any{}, nil)
+}
+
+func (c *client) setTyping(ctx context.Context, roomID, userID string, typing bool, timeout time.Duration) error {
+	body := map[string]any{"typing": typing}
+	if typing {
+		body["timeout"] = timeout.Milliseconds()
+	}
+	return c.do(ctx, http.MethodPut, clientPrefix+"/rooms/"+seg(roomID)+"/typing/"+seg(userID), nil, body, nil)
+}
+
+func (c *client) getEvent(ctx context.Context, roomID, eventID string) (event, error) {
+	var resp event
+	err := c.do(ctx, http.MethodGet, clientPrefix+"/rooms/"+seg(roomID)+"/event/"+seg(eventID), nil, nil, &resp)
+	return resp, err
+}
+
+// relations lists events relating to eventID with the given relation and
+// event type.
+func (c *client) relations(ctx context.Context, roomID, eventID, relType, eventType string) ([]event, error) {
+	var resp struct {
+		Chunk []event `json:"chunk"`
+	}
+	path := clientV1Prefix + "/rooms/" + seg(roomID) + "/relations/" + seg(eventID) + "/" + seg(relType) + "/" + seg(eventType)
+	err := c.do(ctx, http.MethodGet, path, url.Values{"limit": {"100"}}, nil, &resp)
+	return resp.Chunk, err
+}
+
+func (c *client) joinedRooms(ctx context.Context) ([]string, error) {
+	var resp struct {
+		JoinedRooms []string `json:"joined_rooms"`
+	}
+	err := c.do(ctx, http.MethodGet, clientPrefix+"/joined_rooms", nil, nil, &resp)
+	return resp.JoinedRooms, err
+}
+
+type roomMember struct {
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url"`
+}
+
+func (c *client) joinedMembers(ctx context.Context, roomID string) (map[string]roomMember, error) {
+	var resp struct {
+		Joined map[string]roomMember `json:"joined"`
+	}
+	err := c.do(ctx, http.MethodGet, clientPrefix+"/rooms/"+seg(roomID)+"/joined_members", nil, nil, &resp)
+	return resp.Joined, err
+}
+
+// stateEvent reads the content of a state event; a missing event yields
+// an empty result without error.
+func (c *client) stateEvent(ctx context.Context, roomID, eventType string, out any) error {
+	err := c.do(ctx, http.MethodGet, clientPrefix+"/rooms/"+seg(roomID)+"/state/"+seg(eventType), nil, nil, out)
+	if isNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// accountData reads the user's global account data of eventType; missing
+// data yields an empty result without error.
+func (c *client) accountData(ctx context.Context, userID, eventType string, out any) error {
+	err := c.do(ctx, http.MethodGet, clientPrefix+"/user/"+seg(userID)+"/account_data/"+seg(eventType), nil, nil, out)
+	if isNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (c *client) setAccountData(ctx context.Context, userID, eventType string, content any) error {
+	return c.do(ctx, http.MethodPut, clientPrefix+"/user/"+seg(userID)+"/account_data/"+seg(eventType), nil, content, nil)
+}
+
+func (c *client) resolveAlias(ctx context.Context, alias string) (string, error) {
+	var resp struct {
+		RoomID string `json:"room_id"`
+	}
+	if err := c.do(ctx, http.MethodGet, clientPrefix+"/directory/room/"+seg(alias), nil, nil, &resp); err != nil {
+		return "", err
+	}
+	return resp.RoomID, nil
+}
+
+type directoryUser struct {
+	UserID      string `json:"user_id"`
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url"`
+}
+
+func (c *client) searchUsers(ctx context.Context, term string, limit int) ([]directoryUser, error) {
+	var resp struct {
+		Results []directoryUser `json:"results"`
+	}
+	err := c.do(ctx, http.MethodPost, clientPrefix+"/user_directory/search", nil, map[string]any{
+		"search_term": term,
+		"limit":       limit,
+	}, &resp)
+	return resp.Results, err
+}
+
+// createDirectRoom creates a private room flagged as a direct chat with user.
+func (c *client) createDirectRoom(ctx context.Context, userID string) (string, error) {
+	var resp struct {
+		RoomID string `json:"room_id"`
+	}
+	err := c.do(ctx, http.MethodPost, clientPrefix+"/createRoom", nil, map[string]any{
+		"preset":    "trusted_private_chat",
+		"is_direct": true,
+		"invite":    []string{userID},
+	}, &resp)
+	return resp.RoomID, err
+}
+
+// upload stores media on the homeserver and returns its mxc:// URI.
+func (c *client) upload(ctx context.Context, reader io.Reader, size int64, mime, name string) (string, error) {
+	target := c.baseURL + mediaPrefix + "/upload"
+	if name != "" {
+		target += "?" + url.Values{"filename": {name}}.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, reader)
+	if err != nil {
+		return "", fmt.Errorf("matrix: %w", err)
+	}
+	if size > 0 {
+		req.ContentLength = size
+	}
+	if mime == "" {
+		mime = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", mime)
+	var resp struct {
+		ContentURI string `json:"content_uri"`
+	}
+	if err := c.send(req, &resp); err != nil {
+		return "", err
+	}
+	if resp.ContentURI == "" {
+		return "", fmt.Errorf("matrix upload: empty content_uri")
+	}
+	return resp.ContentURI, nil
+}
+
+// download fetches mxc:// media with the authenticated media API and falls
+// back to the legacy endpoint for homeservers that predate it. The caller
+// closes the response body.
+func (c *client) download(ctx context.Context, mxc string) (*http.Response, error) {
+	serverName, mediaID, ok := parseMXC(mxc)
+	if !ok {
+		return nil, fmt.Errorf("matrix: invalid media uri %q", mxc)
+	}
+	suffix := "/download/" + seg(serverName) + "/" + seg(mediaID)
+	resp, err := c.get(ctx, c.baseURL+clientV1Prefix+"/media"+suffix)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		resp, err = c.get(ctx, c.baseURL+mediaPrefix+suffix)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, decodeAPIError(resp.StatusCode, data)
+	}
+	return resp, nil
+}
+
+func (c *client) get(ctx context.Context, target string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("matrix: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("matrix: %w", err)
+	}
+	return resp, nil
+}
+
+// parseMXC splits mxc://server/media into its server name and media ID.
+func parseMXC(uri string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(uri, "mxc://")
+	if !ok {
+		return "", "", false
+	}
+	serverName, mediaID, ok := strings.Cut(rest, "/")
+	if !ok || serverName == "" || mediaID == "" || strings.Contains(mediaID, "/") {
+		return "", "", false
+	}
+	return serverName, mediaID, true
+}
@@ -0,0 +1,192 @@
+package matrix
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// Config holds the Matrix account credentials extracted from a channel configuration.
+type Config struct {
+	// HomeserverURL is the client-server API base, e.g. https://matrix.example.org.
+	HomeserverURL string
+	AccessToken   string
+	// UserID is the bot's MXID. It is discovered with whoami when empty.
+	UserID string
+}
+
+// UserConfig holds the identifiers used to target a Matrix user or room.
+type UserConfig struct {
+	UserID string
+	RoomID string
+}
+
+func normalizeConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]any{
+		"homeserverUrl": cfg.HomeserverURL,
+		"accessToken":   cfg.AccessToken,
+	}
+	if cfg.UserID != "" {
+		result["userId"] = cfg.UserID
+	}
+	return result, nil
+}
+
+func normalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]any{}
+	if cfg.UserID != "" {
+		result["user_id"] = cfg.UserID
+	}
+	if cfg.RoomID != "" {
+		result["room_id"] = cfg.RoomID
+	}
+	return result, nil
+}
+
+func resolveTarget(raw map[string]any) (string, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return "", err
+	}
+	if cfg.RoomID != "" {
+		return cfg.RoomID, nil
+	}
+	if cfg.UserID != "" {
+		return cfg.UserID, nil
+	}
+	return "", fmt.Errorf("matrix binding is incomplete")
+}
+
+func matchBinding(raw map[string]any, criteria channel.BindingCriteria) bool {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return false
+	}
+	if value := strings.TrimSpace(criteria.Attribute("user_id")); value != "" && value == cfg.UserID {
+		return true
+	}
+	if value := strings.TrimSpace(criteria.Attribute("room_id")); value != "" && value == cfg.RoomID {
+		return true
+	}
+	if criteria.SubjectID != "" {
+		if criteria.SubjectID == cfg.UserID || criteria.SubjectID == cfg.RoomID {
+			return true
+		}
+	}
+	return false
+}
+
+func buildUserConfig(identity channel.Identity) map[string]any {
+	result := map[string]any{}
+	if value := strings.TrimSpace(identity.Attribute("user_id")); value != "" {
+		result["user_id"] = value
+	}
+	if value := strings.TrimSpace(identity.Attribute("room_id")); value != "" {
+		result["room_id"] = value
+	}
+	return result
+}
+
+func parseConfig(raw map[string]any) (Config, error) {
+	homeserver := strings.TrimSpace(channel.ReadString(raw, "homeserverUrl", "homeserver_url", "homeserver"))
+	if homeserver == "" {
+		return Config{}, fmt.Errorf("matrix homeserverUrl is required")
+	}
+	parsed, err := url.Parse(homeserver)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return Config{}, fmt.Errorf("matrix homeserverUrl must be an http(s) URL")
+	}
+	token := strings.TrimSpace(channel.ReadString(raw, "accessToken", "access_token"))
+	if token == "" {
+		return Config{}, fmt.Errorf("matrix accessToken is required")
+	}
+	userID := strings.TrimSpace(channel.ReadString(raw, "userId", "user_id"))
+	if userID != "" && !isUserID(userID) {
+		return Config{}, fmt.Errorf("matrix userId must look like @name:server")
+	}
+	return Config{
+		HomeserverURL: strings.TrimRight(homeserver, "/"),
+		AccessToken:   token,
+		UserID:        userID,
+	}, nil
+}
+
+func parseUserConfig(raw map[string]any) (UserConfig, error) {
+	userID := strings.TrimSpace(channel.ReadString(raw, "userId", "user_id"))
+	roomID := strings.TrimSpace(channel.ReadString(raw, "roomId", "room_id"))
+	if userID == "" && roomID == "" {
+		return UserConfig{}, fmt.Errorf("matrix user config requires user_id or room_id")
+	}
+	return UserConfig{UserID: userID, RoomID: roomID}, nil
+}
+
+// threadSeparator joins a room and a thread root in a target, e.g.
+// "!room:example.org/$root". Room IDs never contain "/$".
+const threadSeparator = "/"
+
+// normalizeTarget trims a target and strips matrix.to links down to the ID
+// or alias they point at.
+func normalizeTarget(raw string) string {
+	value := strings.TrimSpace(raw)
+	for _, prefix := range []string{"https://matrix.to/#/", "matrix.to/#/"} {
+		if strings.HasPrefix(value, prefix) {
+			value = strings.TrimPrefix(value, prefix)
+			if unescaped, err := url.PathUnescape(value); err == nil {
+				value = unescaped
+			}
+			if idx := strings.Index(value, "?"); idx >= 0 {
+				value = value[:idx]
+			}
+		}
+	}
+	return strings.TrimSpace(value)
+}
+
+// splitTarget separates a "room/$thread_root" target into the room (an ID,
+// alias or user) and the thread root event ID.
+func splitTarget(target string) (room, threadID string) {
+	target = normalizeTarget(target)
+	if idx := strings.Index(target, threadSeparator+"$"); idx >= 0 {
+		return target[:idx], target[idx+len(threadSeparator):]
+	}
+	return target, ""
+}
+
+// joinTarget builds the target of a room or, with threadID, of a thread in it.
+func joinTarget(roomID, threadID string) string {
+	if threadID == "" {
+		return roomID
+	}
+	return roomID + threadSeparator + threadID
+}
+
+func isUserID(value string) bool {
+	return strings.HasPrefix(value, "@") && strings.Contains(value, ":")
+}
+
+func isRoomID(value string) bool {
+	return strings.HasPrefix(value, "!") && strings.Contains(value, ":")
+}
+
+func isRoomAlias(value string) bool {
+	return strings.HasPrefix(value, "#") && strings.Contains(value, ":")
+}
+
+// localpart returns the name part of an MXID, e.g. "memoh" for @memoh:example.org.
+func localpart(userID string) string {
+	value := strings.TrimPrefix(userID, "@")
+	if idx := strings.Index(value, ":"); idx >= 0 {
+		return value[:idx]
+	}
+	return value
+}
@@ -0,0 +1,71 @@
+package matrix
+
+import (
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestParseConfig(t *testing.T) {
+	if _, err := parseConfig(map[string]any{"accessToken": "syt"}); err == nil {
+		t.Fatal("expected error without homeserver")
+	}
+	if _, err := parseConfig(map[string]any{"homeserverUrl": "https://matrix.example.org"}); err == nil {
+		t.Fatal("expected error without access token")
+	}
+	if _, err := parseConfig(map[string]any{"homeserverUrl": "matrix.example.org", "accessToken": "syt"}); err == nil {
+		t.Fatal("expected error for url without scheme")
+	}
+	if _, err := parseConfig(map[string]any{"homeserverUrl": "https://matrix.example.org", "accessToken": "syt", "userId": "memoh"}); err == nil {
+		t.Fatal("expected error for malformed user id")
+	}
+	cfg, err := parseConfig(map[string]any{"homeserver_url": " http://localhost:8008/ ", "access_token": "syt", "user_id": "@memoh:localhost"})
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if cfg.HomeserverURL != "http://localhost:8008" || cfg.AccessToken != "syt" || cfg.UserID != "@memoh:localhost" {
+		t.Fatalf("unexpected config: %#v", cfg)
+	}
+}
+
+func TestNormalizeTarget(t *testing.T) {
+	cases := map[string]string{
+		" !room:example.org ":                                   "!room:example.org",
+		"https://matrix.to/#/%23family:example.org":             "#family:example.org",
+		"https://matrix.to/#/@alice:example.org":                "@alice:example.org",
+		"https://matrix.to/#/!room:example.org?via=example.org": "!room:example.org",
+	}
+	for input, want := range cases {
+		if got := normalizeTarget(input); got != want {
+			t.Fatalf("normalizeTarget(%q) = %q, want %q", input, got, want)
+		}
+	}
+	room, thread := splitTarget("!room:example.org/$root")
+	if room != "!room:example.org" || thread != "$root" {
+		t.Fatalf("unexpected split: %q %q", room, thread)
+	}
+	if room, thread = splitTarget("@alice:example.org"); room != "@alice:example.org" || thread != "" {
+		t.Fatalf("unexpected split: %q %q", room, thread)
+	}
+	if joinTarget("!r:x", "") != "!r:x" || joinTarget("!r:x", "$t") != "!r:x/$t" {
+		t.Fatal("unexpected joinTarget result")
+	}
+}
+
+func TestBindingRoundTrip(t *testing.T) {
+	identity := channel.Identity{
+		SubjectID:  "@alice:example.org",
+		Attributes: map[string]string{"user_id": "@alice:example.org", "username": "Alice"},
+	}
+	cfg := buildUserConfig(identity)
+	target, err := resolveTarget(cfg)
+	if err != nil || target != "@alice:example.org" {
+		t.Fatalf("unexpected target %q err %v", target, err)
+	}
+	if !matchBinding(cfg, channel.BindingCriteria{SubjectID: "@alice:example.org"}) {
+		t.Fatal("expected binding to match subject")
+	}
+	if matchBinding(cfg, channel.BindingCriteria{SubjectID: "@bob:example.org"}) {
+		t.Fatal("unexpected match for other subject")
+	}
+}
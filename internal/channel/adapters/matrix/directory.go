@@ -0,0 +1,265 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const (
+	defaultDirectoryPageSize = 20
+	maxDirectoryPageSize     = 200
+)
+
+func directoryLimit(n int) int {
+	if n <= 0 {
+		return defaultDirectoryPageSize
+	}
+	if n > maxDirectoryPageSize {
+		return maxDirectoryPageSize
+	}
+	return n
+}
+
+// ListPeers searches the homeserver user directory. Without a query it lists
+// the members of the rooms the bot has joined.
+func (a *MatrixAdapter) ListPeers(ctx context.Context, cfg channel.ChannelConfig, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	c, matrixCfg, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	selfID, err := a.selfUserID(ctx, c, matrixCfg)
+	if err != nil {
+		return nil, err
+	}
+	limit := directoryLimit(query.Limit)
+	term := strings.TrimSpace(query.Query)
+	entries := make([]channel.DirectoryEntry, 0, limit)
+	if term != "" {
+		users, err := c.searchUsers(ctx, strings.TrimPrefix(term, "@"), limit)
+		if err != nil {
+			return nil, fmt.Errorf("matrix search users: %w", err)
+		}
+		for _, user := range users {
+			if user.UserID == selfID {
+				continue
+			}
+			entries = append(entries, userEntry(user.UserID, user.DisplayName, user.AvatarURL))
+			if len(entries) >= limit {
+				break
+			}
+		}
+		return entries, nil
+	}
+	roomIDs, err := c.joinedRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]struct{}{selfID: {}}
+	for _, roomID := range roomIDs {
+		members, err := c.joinedMembers(ctx, roomID)
+		if err != nil {
+			return nil, err
+		}
+		for _, userID := range sortedMemberIDs(members) {
+			if _, ok := seen[userID]; ok {
+				continue
+			}
+			seen[userID] = struct{}{}
+			member := members[userID]
+			entries = append(entries, userEntry(userID, member.DisplayName, member.AvatarURL))
+			if len(entries) >= limit {
+				return entries, nil
+			}
+		}
+	}
+	return entries, nil
+}
+
+// ListGroups lists the rooms the bot has joined.
+func (a *MatrixAdapter) ListGroups(ctx context.Context, cfg channel.ChannelConfig, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	c, _, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	roomIDs, err := c.joinedRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	limit := directoryLimit(query.Limit)
+	entries := make([]channel.DirectoryEntry, 0, limit)
+	for _, roomID := range roomIDs {
+		entry, err := roomEntry(ctx, c, roomID)
+		if err != nil {
+			return nil, err
+		}
+		if !matchesQuery(entry, query.Query) {
+			continue
+		}
+		entries = append(entries, entry)
+		if len(entries) >= limit {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// ListGroupMembers lists the joined members of a room.
+func (a *MatrixAdapter) ListGroupMembers(ctx context.Context, cfg channel.ChannelConfig, groupID string, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	c, matrixCfg, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	room, _ := splitTarget(groupID)
+	if room == "" || isUserID(room) {
+		return nil, fmt.Errorf("matrix list group members: group id must be a room ID or alias")
+	}
+	roomID, err := a.resolveRoom(ctx, c, matrixCfg, room)
+	if err != nil {
+		return nil, err
+	}
+	members, err := c.joinedMembers(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	limit := directoryLimit(query.Limit)
+	entries := make([]channel.DirectoryEntry, 0, limit)
+	for _, userID := range sortedMemberIDs(members) {
+		member := members[userID]
+		entry := userEntry(userID, member.DisplayName, member.AvatarURL)
+		if !matchesQuery(entry, query.Query) {
+			continue
+		}
+		entries = append(entries, entry)
+		if len(entries) >= limit {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// ResolveEntry resolves a user from an MXID or name, or a room from an ID,
+// alias or name. matrix.to links are accepted as well.
+func (a *MatrixAdapter) ResolveEntry(ctx context.Context, cfg channel.ChannelConfig, input string, kind channel.DirectoryEntryKind) (channel.DirectoryEntry, error) {
+	c, _, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return channel.DirectoryEntry{}, err
+	}
+	value, _ := splitTarget(input)
+	if value == "" {
+		return channel.DirectoryEntry{}, fmt.Errorf("matrix resolve entry: empty input")
+	}
+	switch kind {
+	case channel.DirectoryEntryUser:
+		if isUserID(value) {
+			p, err := c.profile(ctx, value)
+			if err != nil {
+				return channel.DirectoryEntry{}, err
+			}
+			return userEntry(value, p.DisplayName, p.AvatarURL), nil
+		}
+		return a.findEntry(ctx, cfg, value, kind)
+	case channel.DirectoryEntryGroup:
+		roomID := value
+		if isRoomAlias(value) {
+			roomID, err = c.resolveAlias(ctx, value)
+			if err != nil {
+				return channel.DirectoryEntry{}, err
+			}
+		}
+		if isRoomID(roomID) {
+			return roomEntry(ctx, c, roomID)
+		}
+		return a.findEntry(ctx, cfg, value, kind)
+	default:
+		return channel.DirectoryEntry{}, fmt.Errorf("matrix resolve entry: unsupported kind %q", kind)
+	}
+}
+
+// findEntry looks a user or room up by exact name.
+func (a *MatrixAdapter) findEntry(ctx context.Context, cfg channel.ChannelConfig, name string, kind channel.DirectoryEntryKind) (channel.DirectoryEntry, error) {
+	name = strings.TrimLeft(name, "@#")
+	var entries []channel.DirectoryEntry
+	var err error
+	query := channel.DirectoryQuery{Query: name, Limit: maxDirectoryPageSize}
+	if kind == channel.DirectoryEntryUser {
+		entries, err = a.ListPeers(ctx, cfg, query)
+	} else {
+		entries, err = a.ListGroups(ctx, cfg, query)
+	}
+	if err != nil {
+		return channel.DirectoryEntry{}, err
+	}
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Name, name) || strings.EqualFold(localpart(entry.Handle), name) {
+			return entry, nil
+		}
+	}
+	return channel.DirectoryEntry{}, fmt.Errorf("matrix resolve entry: %s %q not found", kind, name)
+}
+
+func userEntry(userID, displayName, avatarURL string) channel.DirectoryEntry {
+	name := strings.TrimSpace(displayName)
+	if name == "" {
+		name = localpart(userID)
+	}
+	return channel.DirectoryEntry{
+		Kind:      channel.DirectoryEntryUser,
+		ID:        userID,
+		Name:      name,
+		Handle:    userID,
+		AvatarURL: strings.TrimSpace(avatarURL),
+		Metadata: map[string]any{
+			"user_id": userID,
+		},
+	}
+}
+
+// roomEntry describes a room by its name and canonical alias state.
+func roomEntry(ctx context.Context, c *client, roomID string) (channel.DirectoryEntry, error) {
+	var name struct {
+		Name string `json:"name"`
+	}
+	if err := c.stateEvent(ctx, roomID, "m.room.name", &name); err != nil {
+		return channel.DirectoryEntry{}, err
+	}
+	var alias struct {
+		Alias string `json:"alias"`
+	}
+	if err := c.stateEvent(ctx, roomID, "m.room.canonical_alias", &alias); err != nil {
+		return channel.DirectoryEntry{}, err
+	}
+	entry := channel.DirectoryEntry{
+		Kind:   channel.DirectoryEntryGroup,
+		ID:     roomID,
+		Name:   strings.TrimSpace(name.Name),
+		Handle: strings.TrimSpace(alias.Alias),
+		Metadata: map[string]any{
+			"room_id": roomID,
+		},
+	}
+	if entry.Name == "" {
+		entry.Name = entry.Handle
+	}
+	return entry, nil
+}
+
+func sortedMemberIDs(members map[string]roomMember) []string {
+	ids := make([]string, 0, len(members))
+	for id := range members {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func matchesQuery(entry channel.DirectoryEntry, query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(entry.Name+" "+entry.Handle+" "+entry.ID), query)
+}
@@ -0,0 +1,66 @@
+package matrix
+
+import (
+	"context"
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestDirectory(t *testing.T) {
+	fake := newFakeHomeserver(t)
+	adapter := NewMatrixAdapter(nil)
+	ctx := context.Background()
+	cfg := fake.config()
+
+	groups, err := adapter.ListGroups(ctx, cfg, channel.DirectoryQuery{Query: "fam"})
+	if err != nil {
+		t.Fatalf("list groups: %v", err)
+	}
+	if len(groups) != 1 || groups[0].ID != testRoomID || groups[0].Name != "Family" || groups[0].Handle != "#family:example.org" {
+		t.Fatalf("unexpected groups: %#v", groups)
+	}
+
+	members, err := adapter.ListGroupMembers(ctx, cfg, "#family:example.org", channel.DirectoryQuery{Query: "ali"})
+	if err != nil {
+		t.Fatalf("list members: %v", err)
+	}
+	if len(members) != 1 || members[0].ID != "@alice:example.org" || members[0].Name != "Alice" {
+		t.Fatalf("unexpected members: %#v", members)
+	}
+
+	peers, err := adapter.ListPeers(ctx, cfg, channel.DirectoryQuery{})
+	if err != nil {
+		t.Fatalf("list peers: %v", err)
+	}
+	if len(peers) != 2 || peers[0].ID != "@alice:example.org" || peers[1].ID != "@bob:example.org" {
+		t.Fatalf("unexpected peers: %#v", peers)
+	}
+	searched, err := adapter.ListPeers(ctx, cfg, channel.DirectoryQuery{Query: "alice"})
+	if err != nil {
+		t.Fatalf("search peers: %v", err)
+	}
+	if len(searched) != 1 || searched[0].Handle != "@alice:example.org" {
+		t.Fatalf("search should skip the bot itself: %#v", searched)
+	}
+
+	user, err := adapter.ResolveEntry(ctx, cfg, "@alice:example.org", channel.DirectoryEntryUser)
+	if err != nil || user.Name != "Alice" || user.Kind != channel.DirectoryEntryUser {
+		t.Fatalf("resolve user: %#v %v", user, err)
+	}
+	byName, err := adapter.ResolveEntry(ctx, cfg, "alice", channel.DirectoryEntryUser)
+	if err != nil || byName.ID != "@alice:example.org" {
+		t.Fatalf("resolve user by name: %#v %v", byName, err)
+	}
+	room, err := adapter.ResolveEntry(ctx, cfg, "https://matrix.to/#/%23family:example.org", channel.DirectoryEntryGroup)
+	if err != nil || room.ID != testRoomID {
+		t.Fatalf("resolve alias: %#v %v", room, err)
+	}
+	room, err = adapter.ResolveEntry(ctx, cfg, "Family", channel.DirectoryEntryGroup)
+	if err != nil || room.ID != testRoomID {
+		t.Fatalf("resolve room by name: %#v %v", room, err)
+	}
+	if _, err := adapter.ResolveEntry(ctx, cfg, "#missing:example.org", channel.DirectoryEntryGroup); err == nil {
+		t.Fatal("expected error for unknown alias")
+	}
+}
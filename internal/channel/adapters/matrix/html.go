@@ -0,0 +1,210 @@
+package matrix
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// htmlFormat is the format of formatted_body in m.room.message events.
+const htmlFormat = "org.matrix.custom.html"
+
+const inlineCodePlaceholder = "\x00IC"
+
+var (
+	reInlineCode  = regexp.MustCompile("`([^`\\n]+?)`")
+	reBold        = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	reItalic      = regexp.MustCompile(`\*([^*\n]+?)\*`)
+	reStrike      = regexp.MustCompile(`~~(.+?)~~`)
+	reLink        = regexp.MustCompile(`\[([^\]]+?)\]\(([^)\s]+?)\)`)
+	reHeading     = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
+	reBullet      = regexp.MustCompile(`^\s*[-+*]\s+(.*)$`)
+	reOrderedItem = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+)
+
+// markdownToHTML converts standard markdown to the HTML subset Matrix
+// clients render in formatted_body.
+//
+// Supported conversions:
+//   - Fenced code blocks → <pre><code class="language-x">
+//   - Inline code, bold, italic, strikethrough and links
+//   - Headings → <h1>..<h6>
+//   - Bulleted and numbered lists → <ul>/<ol>
+//   - Block quotes → <blockquote>
+//   - Paragraphs separated by blank lines, line breaks → <br>
+func markdownToHTML(text string) string {
+	if strings.TrimSpace(text) == "" {
+		return ""
+	}
+	segments := strings.Split(text, "```")
+	if len(segments)%2 == 0 {
+		// Unclosed fence: keep the remainder as normal text.
+		last := len(segments) - 1
+		segments[last-1] += "```" + segments[last]
+		segments = segments[:last]
+	}
+	var buf strings.Builder
+	for i, seg := range segments {
+		if i%2 == 0 {
+			buf.WriteString(renderBlocks(seg))
+			continue
+		}
+		lang, code := splitCodeBlockLang(seg)
+		code = escapeHTML(strings.Trim(code, "\n"))
+		if lang != "" {
+			fmt.Fprintf(&buf, `<pre><code class="language-%s">%s</code></pre>`, escapeHTML(lang), code)
+		} else {
+			buf.WriteString("<pre><code>" + code + "</code></pre>")
+		}
+	}
+	return buf.String()
+}
+
+// splitCodeBlockLang separates a language tag on the opening fence line.
+func splitCodeBlockLang(block string) (string, string) {
+	idx := strings.IndexByte(block, '\n')
+	if idx < 0 {
+		return "", block
+	}
+	firstLine := strings.TrimSpace(block[:idx])
+	if firstLine != "" && !strings.Contains(firstLine, " ") && len(firstLine) <= 20 {
+		return firstLine, block[idx+1:]
+	}
+	return "", block
+}
+
+// renderBlocks renders the lines between code fences.
+func renderBlocks(text string) string {
+	var buf strings.Builder
+	var paragraph, quote []string
+	listTag := ""
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			buf.WriteString("<p>" + strings.Join(paragraph, "<br>") + "</p>")
+			paragraph = nil
+		}
+	}
+	flushQuote := func() {
+		if len(quote) > 0 {
+			buf.WriteString("<blockquote>" + strings.Join(quote, "<br>") + "</blockquote>")
+			quote = nil
+		}
+	}
+	flushList := func() {
+		if listTag != "" {
+			buf.WriteString("</" + listTag + ">")
+			listTag = ""
+		}
+	}
+	openList := func(tag string) {
+		if listTag != tag {
+			flushList()
+			buf.WriteString("<" + tag + ">")
+			listTag = tag
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			flushParagraph()
+			flushQuote()
+			flushList()
+			continue
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			flushParagraph()
+			flushList()
+			quote = append(quote, renderInline(strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))))
+			continue
+		}
+		flushQuote()
+		if m := reHeading.FindStringSubmatch(trimmed); m != nil {
+			flushParagraph()
+			flushList()
+			level := len(m[1])
+			fmt.Fprintf(&buf, "<h%d>%s</h%d>", level, renderInline(m[2]), level)
+			continue
+		}
+		if m := reBullet.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			openList("ul")
+			buf.WriteString("<li>" + renderInline(m[1]) + "</li>")
+			continue
+		}
+		if m := reOrderedItem.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			openList("ol")
+			buf.WriteString("<li>" + renderInline(m[1]) + "</li>")
+			continue
+		}
+		flushList()
+		paragraph = append(paragraph, renderInline(trimmed))
+	}
+	flushParagraph()
+	flushQuote()
+	flushList()
+	return buf.String()
+}
+
+// renderInline converts inline markdown of one line to HTML.
+func renderInline(text string) string {
+	var inlineCodes []string
+	text = reInlineCode.ReplaceAllStringFunc(text, func(match string) string {
+		idx := len(inlineCodes)
+		inlineCodes = append(inlineCodes, reInlineCode.FindStringSubmatch(match)[1])
+		return fmt.Sprintf("%s%d\x00", inlineCodePlaceholder, idx)
+	})
+	text = escapeHTML(text)
+	text = reLink.ReplaceAllString(text, `<a href="$2">$1</a>`)
+	text = reBold.ReplaceAllStringFunc(text, func(match string) string {
+		sub := reBold.FindStringSubmatch(match)
+		inner := sub[1]
+		if inner == "" {
+			inner = sub[2]
+		}
+		return "<strong>" + inner + "</strong>"
+	})
+	text = reItalic.ReplaceAllString(text, "<em>$1</em>")
+	text = reStrike.ReplaceAllString(text, "<del>$1</del>")
+	for i, code := range inlineCodes {
+		placeholder := fmt.Sprintf("%s%d\x00", inlineCodePlaceholder, i)
+		text = strings.Replace(text, placeholder, "<code>"+escapeHTML(code)+"</code>", 1)
+	}
+	return text
+}
+
+func escapeHTML(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(text)
+}
+
+var (
+	reHTMLBreak = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</li>|</h[1-6]>|</blockquote>`)
+	reHTMLTag   = regexp.MustCompile(`<[^>]+>`)
+	reReplyHTML = regexp.MustCompile(`(?is)^\s*<mx-reply>.*?</mx-reply>`)
+)
+
+// stripReplyFallback removes the quoted original that clients prepend to
+// reply bodies: "> <@user> text" lines followed by a blank line.
+func stripReplyFallback(body string) string {
+	if !strings.HasPrefix(body, "> ") {
+		return body
+	}
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "> ") || line == ">" {
+			continue
+		}
+		return strings.TrimLeft(strings.Join(lines[i:], "\n"), "\n")
+	}
+	return ""
+}
+
+// htmlToText renders formatted_body as plain text, dropping the reply
+// fallback, for messages whose body is missing.
+func htmlToText(html string) string {
+	html = reReplyHTML.ReplaceAllString(html, "")
+	html = reHTMLBreak.ReplaceAllString(html, "\n")
+	html = reHTMLTag.ReplaceAllString(html, "")
+	html = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&#39;", "'", "&amp;", "&").Replace(html)
+	return strings.TrimSpace(html)
+}
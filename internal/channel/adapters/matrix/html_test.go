@@ -0,0 +1,64 @@
+package matrix
+
+import "testing"
+
+func TestMarkdownToHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "bold and italic", input: "**bold** and *italic*", want: "<p><strong>bold</strong> and <em>italic</em></p>"},
+		{name: "strikethrough", input: "~~gone~~", want: "<p><del>gone</del></p>"},
+		{name: "link", input: "see [docs](https://example.com/a?b=1&c=2)", want: `<p>see <a href="https://example.com/a?b=1&amp;c=2">docs</a></p>`},
+		{name: "escapes", input: "a < b && c > d", want: "<p>a &lt; b &amp;&amp; c &gt; d</p>"},
+		{name: "heading", input: "## Plan", want: "<h2>Plan</h2>"},
+		{name: "lists", input: "- one\n- two\n\n1. first", want: "<ul><li>one</li><li>two</li></ul><ol><li>first</li></ol>"},
+		{name: "quote", input: "> quoted\n> more", want: "<blockquote>quoted<br>more</blockquote>"},
+		{name: "line breaks and paragraphs", input: "a\nb\n\nc", want: "<p>a<br>b</p><p>c</p>"},
+		{name: "inline code", input: "run `a **b** <c>`", want: "<p>run <code>a **b** &lt;c&gt;</code></p>"},
+		{name: "code block", input: "```go\nx := <y>\n```", want: `<pre><code class="language-go">x := &lt;y&gt;</code></pre>`},
+		{name: "unclosed fence", input: "```go **x**", want: "<p>```go <strong>x</strong></p>"},
+		{name: "empty", input: "  ", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownToHTML(tt.input); got != tt.want {
+				t.Fatalf("markdownToHTML(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripReplyFallback(t *testing.T) {
+	body := "> <@alice:example.org> original\n> second line\n\nthe reply"
+	if got := stripReplyFallback(body); got != "the reply" {
+		t.Fatalf("stripReplyFallback = %q", got)
+	}
+	if got := stripReplyFallback("no fallback"); got != "no fallback" {
+		t.Fatalf("stripReplyFallback changed plain body: %q", got)
+	}
+	html := "<mx-reply><blockquote>old</blockquote></mx-reply><p>new &amp; shiny</p>"
+	if got := htmlToText(html); got != "new & shiny" {
+		t.Fatalf("htmlToText = %q", got)
+	}
+}
+
+func TestIsBotMentioned(t *testing.T) {
+	self := selfInfo{userID: "@memoh:example.org", displayName: "Memoh"}
+	cases := []struct {
+		content messageContent
+		want    bool
+	}{
+		{messageContent{Body: "hi", Mentions: &mentions{UserIDs: []string{"@memoh:example.org"}}}, true},
+		{messageContent{Body: "Memoh", FormattedBody: `<a href="https://matrix.to/#/@memoh:example.org">Memoh</a>`}, true},
+		{messageContent{Body: "ping @memoh:example.org"}, true},
+		{messageContent{Body: "hey memoh, help"}, true},
+		{messageContent{Body: "hello everyone"}, false},
+	}
+	for _, tc := range cases {
+		if got := isBotMentioned(tc.content, self); got != tc.want {
+			t.Fatalf("isBotMentioned(%q) = %v, want %v", tc.content.Body, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package matrix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/adapters/common"
+)
+
+const (
+	syncTimeout     = 30 * time.Second
+	syncMinBackoff  = time.Second
+	syncMaxBackoff  = 30 * time.Second
+	inboundDedupTTL = 10 * time.Minute
+	roomMembersTTL  = 10 * time.Minute
+)
+
+// initialSyncFilter keeps the first sync small: only its next_batch token is
+// used, so history from before the connection is never replayed.
+const initialSyncFilter = `{"room":{"timeline":{"limit":1},"state":{"types":[]},"ephemeral":{"types":[]},"account_data":{"types":[]}},"presence":{"types":[]},"account_data":{"types":[]}}`
+
+// syncFilter limits incremental syncs to the events the adapter handles.
+const syncFilter = `{"room":{"timeline":{"types":["m.room.message"]},"state":{"types":[]},"ephemeral":{"types":[]},"account_data":{"types":[]}},"presence":{"types":[]},"account_data":{"types":[]}}`
+
+type cachedMembers struct {
+	members   map[string]roomMember
+	expiresAt time.Time
+}
+
+// selfInfo identifies the bot account while handling inbound events.
+type selfInfo struct {
+	userID      string
+	displayName string
+}
+
+func decodeContent(ev event, out any) error {
+	if len(ev.Content) == 0 {
+		return fmt.Errorf("matrix event %s has no content", ev.EventID)
+	}
+	return json.Unmarshal(ev.Content, out)
+}
+
+// Connect starts a /sync long-poll loop and forwards room messages to the
+// handler. Invites are accepted automatically.
+func (a *MatrixAdapter) Connect(ctx context.Context, cfg channel.ChannelConfig, handler channel.InboundHandler) (channel.Connection, error) {
+	a.logger.Info("start", slog.String("config_id", cfg.ID))
+	c, matrixCfg, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		a.logger.Error("decode config failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		return nil, err
+	}
+	selfID, err := a.selfUserID(ctx, c, matrixCfg)
+	if err != nil {
+		a.logger.Error("whoami failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		return nil, err
+	}
+	self := selfInfo{userID: selfID}
+	if p, err := c.profile(ctx, selfID); err == nil {
+		self.displayName = strings.TrimSpace(p.DisplayName)
+	}
+	initial, err := c.sync(ctx, "", 0, initialSyncFilter)
+	if err != nil {
+		a.logger.Error("initial sync failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		return nil, err
+	}
+	a.joinInvites(ctx, cfg, c, initial)
+	connCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.runSync(connCtx, cfg, matrixCfg, c, self, initial.NextBatch, handler)
+	}()
+	stop := func(stopCtx context.Context) error {
+		a.logger.Info("stop", slog.String("config_id", cfg.ID))
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-stopCtx.Done():
+			return stopCtx.Err()
+		}
+	}
+	return channel.NewConnection(cfg, stop), nil
+}
+
+// runSync long-polls /sync until ctx is canceled, backing off on errors.
+func (a *MatrixAdapter) runSync(ctx context.Context, cfg channel.ChannelConfig, matrixCfg Config, c *client, self selfInfo, since string, handler channel.InboundHandler) {
+	backoff := syncMinBackoff
+	for ctx.Err() == nil {
+		resp, err := c.sync(ctx, since, syncTimeout, syncFilter)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			delay := retryAfter(err)
+			if delay <= 0 {
+				delay = backoff
+				backoff = min(backoff*2, syncMaxBackoff)
+			}
+			a.logger.Warn("sync failed", slog.String("config_id", cfg.ID), slog.Duration("retry_in", delay), slog.Any("error", err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			continue
+		}
+		backoff = syncMinBackoff
+		if resp.NextBatch != "" {
+			since = resp.NextBatch
+		}
+		a.joinInvites(ctx, cfg, c, resp)
+		for roomID, room := range resp.Rooms.Join {
+			for _, ev := range room.Timeline.Events {
+				a.handleEvent(ctx, cfg, matrixCfg, c, self, roomID, ev, handler)
+			}
+		}
+	}
+}
+
+func (a *MatrixAdapter) joinInvites(ctx context.Context, cfg channel.ChannelConfig, c *client, resp syncResponse) {
+	for roomID := range resp.Rooms.Invite {
+		if _, err := c.joinRoom(ctx, roomID); err != nil {
+			a.logger.Warn("join invited room failed", slog.String("config_id", cfg.ID), slog.String("room_id", roomID), slog.Any("error", err))
+			continue
+		}
+		a.logger.Info("joined invited room", slog.String("config_id", cfg.ID), slog.String("room_id", roomID))
+	}
+}
+
+// handleEvent converts a timeline event into an inbound message and
+// dispatches it to handler.
+func (a *MatrixAdapter) handleEvent(ctx context.Context, cfg channel.ChannelConfig, matrixCfg Config, c *client, self selfInfo, roomID string, ev event, handler channel.InboundHandler) {
+	if ev.Type != "m.room.message" || ev.EventID == "" || ev.Sender == "" || ev.Sender == self.userID {
+		return
+	}
+	var content messageContent
+	if err := decodeContent(ev, &content); err != nil {
+		a.logger.Debug("decode message failed", slog.String("config_id", cfg.ID), slog.String("event_id", ev.EventID), slog.Any("error", err))
+		return
+	}
+	// Edits and notices (which bots send) are not new input.
+	if content.MsgType == "m.notice" || (content.RelatesTo != nil && content.RelatesTo.RelType == "m.replace") {
+		return
+	}
+	if a.isDuplicateInbound(matrixCfg.AccessToken, ev.EventID) {
+		return
+	}
+	msg, ok := a.toInboundMessage(ctx, c, matrixCfg, cfg, self, roomID, ev, content)
+	if !ok {
+		return
+	}
+	a.logger.Info(
+		"inbound received",
+		slog.String("config_id", cfg.ID),
+		slog.String("chat_type", msg.Conversation.Type),
+		slog.String("chat_id", msg.Conversation.ID),
+		slog.String("thread_id", msg.Conversation.ThreadID),
+		slog.String("user_id", ev.Sender),
+		slog.String("text", common.SummarizeText(msg.Message.Text)),
+		slog.Int("attachments", len(msg.Message.Attachments)),
+	)
+	go func() {
+		if err := handler(ctx, cfg, msg); err != nil {
+			a.logger.Error("handle inbound failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+	}()
+}
+
+func (a *MatrixAdapter) toInboundMessage(
+	ctx context.Context,
+	c *client,
+	matrixCfg Config,
+	cfg channel.ChannelConfig,
+	self selfInfo,
+	roomID string,
+	ev event,
+	content messageContent,
+) (channel.InboundMessage, bool) {
+	text, attachments := messageParts(content)
+	if text == "" && len(attachments) == 0 {
+		return channel.InboundMessage{}, false
+	}
+	threadID, replyID := inboundRelations(content)
+	var thread *channel.ThreadRef
+	if threadID != "" {
+		thread = &channel.ThreadRef{ID: threadID}
+	}
+	var reply *channel.ReplyRef
+	isReplyToBot := false
+	if replyID != "" {
+		reply = &channel.ReplyRef{Target: roomID, MessageID: replyID}
+		if parent, err := c.getEvent(ctx, roomID, replyID); err == nil {
+			isReplyToBot = parent.Sender == self.userID
+		}
+	}
+	members := a.lookupRoomMembers(ctx, c, matrixCfg.AccessToken, roomID)
+	chatType := "group"
+	if len(members) == 2 {
+		chatType = "private"
+	}
+	displayName := strings.TrimSpace(members[ev.Sender].DisplayName)
+	if displayName == "" {
+		displayName = localpart(ev.Sender)
+	}
+	return channel.InboundMessage{
+		Channel: Type,
+		Message: channel.Message{
+			ID:          ev.EventID,
+			Format:      channel.MessageFormatPlain,
+			Text:        text,
+			Attachments: attachments,
+			Thread:      thread,
+			Reply:       reply,
+		},
+		BotID:       cfg.BotID,
+		ReplyTarget: joinTarget(roomID, threadID),
+		Sender: channel.Identity{
+			SubjectID:   ev.Sender,
+			DisplayName: displayName,
+			Attributes: map[string]string{
+				"user_id":  ev.Sender,
+				"username": displayName,
+			},
+		},
+		Conversation: channel.Conversation{
+			ID:       roomID,
+			Type:     chatType,
+			ThreadID: threadID,
+		},
+		ReceivedAt: time.UnixMilli(ev.OriginServerTS),
+		Source:     "matrix",
+		Metadata: map[string]any{
+			"is_mentioned":    isBotMentioned(content, self),
+			"is_reply_to_bot": isReplyToBot,
+		},
+	}, true
+}
+
+// messageParts extracts the text and media of a message. Media bodies are
+// the file name unless a separate filename is set, in which case the body
+// is a caption.
+func messageParts(content messageContent) (string, []channel.Attachment) {
+	switch content.MsgType {
+	case "m.image", "m.file", "m.audio", "m.video":
+		if content.URL == "" {
+			return "", nil
+		}
+		name := strings.TrimSpace(content.FileName)
+		caption := ""
+		if name == "" {
+			name = strings.TrimSpace(content.Body)
+		} else if body := strings.TrimSpace(content.Body); body != name {
+			caption = body
+		}
+		att := channel.Attachment{
+			Type:           inboundAttachmentType(content.MsgType),
+			PlatformKey:    content.URL,
+			SourcePlatform: Type.String(),
+			Name:           name,
+			Caption:        caption,
+		}
+		if info := content.Info; info != nil {
+			att.Mime = info.MimeType
+			att.Size = info.Size
+			att.Width = info.Width
+			att.Height = info.Height
+			att.DurationMs = info.Duration
+			if att.Mime == "image/gif" {
+				att.Type = channel.AttachmentGIF
+			}
+		}
+		return caption, []channel.Attachment{att}
+	default:
+		body := content.Body
+		if content.RelatesTo != nil && content.RelatesTo.InReplyTo != nil {
+			body = stripReplyFallback(body)
+		}
+		body = strings.TrimSpace(body)
+		if body == "" && content.Format == htmlFormat {
+			body = htmlToText(content.FormattedBody)
+		}
+		return body, nil
+	}
+}
+
+func inboundAttachmentType(msgType string) channel.AttachmentType {
+	switch msgType {
+	case "m.image":
+		return channel.AttachmentImage
+	case "m.audio":
+		return channel.AttachmentAudio
+	case "m.video":
+		return channel.AttachmentVideo
+	default:
+		return channel.AttachmentFile
+	}
+}
+
+// inboundRelations returns the thread root and the replied event of a
+// message. The reply fallback inside a thread points at the latest thread
+// event, so the thread root is used instead, matching how threads are
+// replied to.
+func inboundRelations(content messageContent) (threadID, replyID string) {
+	rel := content.RelatesTo
+	if rel == nil {
+		return "", ""
+	}
+	if rel.RelType == "m.thread" {
+		threadID = rel.EventID
+		replyID = rel.EventID
+		if rel.InReplyTo != nil && !rel.IsFallingBack && rel.InReplyTo.EventID != "" {
+			replyID = rel.InReplyTo.EventID
+		}
+		return threadID, replyID
+	}
+	if rel.InReplyTo != nil {
+		replyID = rel.InReplyTo.EventID
+	}
+	return "", replyID
+}
+
+// isBotMentioned reports whether a message mentions the bot through
+// m.mentions, a matrix.to pill, its MXID or its display name.
+func isBotMentioned(content messageContent, self selfInfo) bool {
+	if self.userID == "" {
+		return false
+	}
+	if content.Mentions != nil {
+		for _, userID := range content.Mentions.UserIDs {
+			if userID == self.userID {
+				return true
+			}
+		}
+	}
+	if strings.Contains(content.FormattedBody, "matrix.to/#/"+self.userID) {
+		return true
+	}
+	body := strings.ToLower(content.Body)
+	if strings.Contains(body, strings.ToLower(self.userID)) {
+		return true
+	}
+	if name := strings.ToLower(self.displayName); name != "" && strings.Contains(body, name) {
+		return true
+	}
+	return false
+}
+
+// lookupRoomMembers returns the joined members of a room, cached for a while
+// since it is consulted for every message.
+func (a *MatrixAdapter) lookupRoomMembers(ctx context.Context, c *client, token, roomID string) map[string]roomMember {
+	key := token + ":" + roomID
+	now := time.Now()
+	a.mu.Lock()
+	cached, ok := a.roomMembers[key]
+	a.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.members
+	}
+	members, err := c.joinedMembers(ctx, roomID)
+	if err != nil {
+		a.logger.Debug("joined members lookup failed", slog.String("room_id", roomID), slog.Any("error", err))
+		return cached.members
+	}
+	a.mu.Lock()
+	a.roomMembers[key] = cachedMembers{members: members, expiresAt: now.Add(roomMembersTTL)}
+	a.mu.Unlock()
+	return members
+}
+
+func (a *MatrixAdapter) isDuplicateInbound(token, eventID string) bool {
+	key := token + ":" + eventID
+	now := time.Now()
+	expireBefore := now.Add(-inboundDedupTTL)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for k, seenAt := range a.seenEvents {
+		if seenAt.Before(expireBefore) {
+			delete(a.seenEvents, k)
+		}
+	}
+	if _, ok := a.seenEvents[key]; ok {
+		return true
+	}
+	a.seenEvents[key] = now
+	return false
+}
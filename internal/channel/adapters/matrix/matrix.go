@@ -0,0 +1,834 @@
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	attachmentpkg "github.com/memohai/memoh/internal/attachment"
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/media"
+)
+
+// matrixMaxMessageLength keeps bodies well inside the 64 KiB event size
+// limit, which edits fill with both the old and the new content.
+const matrixMaxMessageLength = 10000
+
+// matrixTextChunkLimit is the length messages are split at before sending.
+const matrixTextChunkLimit = 8000
+
+// typingTimeout is how long the typing notification lasts unless renewed.
+const typingTimeout = 30 * time.Second
+
+// processingTypingToken marks a processing status shown as typing.
+const processingTypingToken = "typing"
+
+type assetOpener interface {
+	Open(ctx context.Context, botID, contentHash string) (io.ReadCloser, media.Asset, error)
+}
+
+// MatrixAdapter implements the Matrix channel with the client-server API:
+// /sync long-polling for inbound events and room events for everything else.
+// Encrypted rooms are not supported.
+type MatrixAdapter struct {
+	logger      *slog.Logger
+	httpClient  *http.Client
+	assets      assetOpener
+	mu          sync.Mutex
+	seenEvents  map[string]time.Time     // keyed by token:event_id
+	selfIDs     map[string]string        // keyed by token
+	directRooms map[string]string        // keyed by token:user_id
+	roomMembers map[string]cachedMembers // keyed by token:room_id
+}
+
+// NewMatrixAdapter creates a MatrixAdapter with the given logger.
+func NewMatrixAdapter(log *slog.Logger) *MatrixAdapter {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &MatrixAdapter{
+		logger: log.With(slog.String("adapter", "matrix")),
+		// Longer than the /sync long-poll timeout.
+		httpClient:  &http.Client{Timeout: 90 * time.Second},
+		seenEvents:  make(map[string]time.Time),
+		selfIDs:     make(map[string]string),
+		directRooms: make(map[string]string),
+		roomMembers: make(map[string]cachedMembers),
+	}
+}
+
+// SetAssetOpener injects media asset reader for content_hash attachment delivery.
+func (a *MatrixAdapter) SetAssetOpener(opener assetOpener) {
+	a.assets = opener
+}
+
+func (a *MatrixAdapter) clientFor(credentials map[string]any) (*client, Config, error) {
+	matrixCfg, err := parseConfig(credentials)
+	if err != nil {
+		return nil, Config{}, err
+	}
+	return newClient(a.httpClient, matrixCfg), matrixCfg, nil
+}
+
+// selfUserID returns the configured MXID or looks it up with whoami once per
+// access token.
+func (a *MatrixAdapter) selfUserID(ctx context.Context, c *client, matrixCfg Config) (string, error) {
+	if matrixCfg.UserID != "" {
+		return matrixCfg.UserID, nil
+	}
+	a.mu.Lock()
+	userID, ok := a.selfIDs[matrixCfg.AccessToken]
+	a.mu.Unlock()
+	if ok {
+		return userID, nil
+	}
+	userID, err := c.whoami(ctx)
+	if err != nil {
+		return "", err
+	}
+	a.mu.Lock()
+	a.selfIDs[matrixCfg.AccessToken] = userID
+	a.mu.Unlock()
+	return userID, nil
+}
+
+// Type returns the Matrix channel type.
+func (a *MatrixAdapter) Type() channel.ChannelType {
+	return Type
+}
+
+// Descriptor returns the Matrix channel metadata.
+func (a *MatrixAdapter) Descriptor() channel.Descriptor {
+	return channel.Descriptor{
+		Type:        Type,
+		DisplayName: "Matrix",
+		Capabilities: channel.ChannelCapabilities{
+			Text:        true,
+			Markdown:    true,
+			Reply:       true,
+			Threads:     true,
+			Attachments: true,
+			Media:       true,
+			Streaming:   true,
+			Edit:        true,
+			Unsend:      true,
+			Reactions:   true,
+		},
+		OutboundPolicy: channel.OutboundPolicy{
+			TextChunkLimit: matrixTextChunkLimit,
+		},
+		ConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"homeserverUrl": {
+					Type:        channel.FieldString,
+					Required:    true,
+					Title:       "Homeserver URL",
+					Description: "Client-server API base URL of the homeserver",
+					Example:     "https://matrix.example.org",
+				},
+				"accessToken": {
+					Type:        channel.FieldSecret,
+					Required:    true,
+					Title:       "Access Token",
+					Description: "Access token of the bot account",
+				},
+				"userId": {
+					Type:        channel.FieldString,
+					Title:       "User ID",
+					Description: "MXID of the bot account; discovered with whoami when empty",
+					Example:     "@memoh:example.org",
+				},
+			},
+		},
+		UserConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"user_id": {Type: channel.FieldString},
+				"room_id": {Type: channel.FieldString},
+			},
+		},
+		TargetSpec: channel.TargetSpec{
+			Format: "room_id[/thread_event_id] | #alias:server | @user:server",
+			Hints: []channel.TargetHint{
+				{Label: "Room ID", Example: "!abcdefg:example.org"},
+				{Label: "Thread", Example: "!abcdefg:example.org/$rootevent"},
+				{Label: "Room Alias", Example: "#family:example.org"},
+				{Label: "User ID", Example: "@alice:example.org"},
+			},
+		},
+	}
+}
+
+// NormalizeConfig validates and normalizes a Matrix channel configuration map.
+func (a *MatrixAdapter) NormalizeConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeConfig(raw)
+}
+
+// NormalizeUserConfig validates and normalizes a Matrix user-binding configuration map.
+func (a *MatrixAdapter) NormalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeUserConfig(raw)
+}
+
+// NormalizeTarget normalizes a Matrix delivery target string.
+func (a *MatrixAdapter) NormalizeTarget(raw string) string {
+	return normalizeTarget(raw)
+}
+
+// ResolveTarget derives a delivery target from a Matrix user-binding configuration.
+func (a *MatrixAdapter) ResolveTarget(userConfig map[string]any) (string, error) {
+	return resolveTarget(userConfig)
+}
+
+// MatchBinding reports whether a Matrix user binding matches the given criteria.
+func (a *MatrixAdapter) MatchBinding(config map[string]any, criteria channel.BindingCriteria) bool {
+	return matchBinding(config, criteria)
+}
+
+// BuildUserConfig constructs a Matrix user-binding config from an Identity.
+func (a *MatrixAdapter) BuildUserConfig(identity channel.Identity) map[string]any {
+	return buildUserConfig(identity)
+}
+
+// resolveRoom turns a room ID, room alias or user ID into a room ID. Users
+// are reached in a direct chat, which is created when none exists yet.
+func (a *MatrixAdapter) resolveRoom(ctx context.Context, c *client, matrixCfg Config, room string) (string, error) {
+	room = strings.TrimSpace(room)
+	switch {
+	case room == "":
+		return "", fmt.Errorf("matrix target is required")
+	case isRoomID(room):
+		return room, nil
+	case isRoomAlias(room):
+		roomID, err := c.resolveAlias(ctx, room)
+		if err != nil {
+			return "", fmt.Errorf("matrix resolve alias %s: %w", room, err)
+		}
+		return roomID, nil
+	case isUserID(room):
+		return a.directRoom(ctx, c, matrixCfg, room)
+	default:
+		return "", fmt.Errorf("matrix target %q must be a room ID, room alias or user ID", room)
+	}
+}
+
+// directRoom finds the direct chat with userID through the m.direct account
+// data clients share, creating and recording one when needed.
+func (a *MatrixAdapter) directRoom(ctx context.Context, c *client, matrixCfg Config, userID string) (string, error) {
+	key := matrixCfg.AccessToken + ":" + userID
+	a.mu.Lock()
+	roomID, ok := a.directRooms[key]
+	a.mu.Unlock()
+	if ok {
+		return roomID, nil
+	}
+	selfID, err := a.selfUserID(ctx, c, matrixCfg)
+	if err != nil {
+		return "", err
+	}
+	direct := map[string][]string{}
+	if err := c.accountData(ctx, selfID, "m.direct", &direct); err != nil {
+		return "", fmt.Errorf("matrix read m.direct: %w", err)
+	}
+	if candidates := direct[userID]; len(candidates) > 0 {
+		joined, err := c.joinedRooms(ctx)
+		if err != nil {
+			return "", err
+		}
+		for i := len(candidates) - 1; i >= 0 && roomID == ""; i-- {
+			for _, id := range joined {
+				if id == candidates[i] {
+					roomID = id
+					break
+				}
+			}
+		}
+	}
+	if roomID == "" {
+		roomID, err = c.createDirectRoom(ctx, userID)
+		if err != nil {
+			return "", fmt.Errorf("matrix create direct room: %w", err)
+		}
+		direct[userID] = append(direct[userID], roomID)
+		if err := c.setAccountData(ctx, selfID, "m.direct", direct); err != nil {
+			a.logger.Warn("record direct room failed", slog.String("room_id", roomID), slog.Any("error", err))
+		}
+	}
+	a.mu.Lock()
+	a.directRooms[key] = roomID
+	a.mu.Unlock()
+	return roomID, nil
+}
+
+// relationFor builds the m.relates_to of an outbound message. Messages in a
+// thread carry the thread relation with a reply fallback for clients without
+// thread support; other replies use a plain reply.
+func relationFor(threadID, replyID string) *relatesTo {
+	threadID = strings.TrimSpace(threadID)
+	replyID = strings.TrimSpace(replyID)
+	if threadID != "" {
+		rel := &relatesTo{RelType: "m.thread", EventID: threadID, IsFallingBack: replyID == ""}
+		if replyID == "" {
+			replyID = threadID
+		}
+		rel.InReplyTo = &inReplyTo{EventID: replyID}
+		return rel
+	}
+	if replyID != "" {
+		return &relatesTo{InReplyTo: &inReplyTo{EventID: replyID}}
+	}
+	return nil
+}
+
+// outboundThread returns the thread root for a message: an explicit thread on
+// the message wins over one in the target.
+func outboundThread(target string, msg channel.Message) (string, string) {
+	room, threadID := splitTarget(target)
+	if msg.Thread != nil && strings.TrimSpace(msg.Thread.ID) != "" {
+		threadID = strings.TrimSpace(msg.Thread.ID)
+	}
+	return room, threadID
+}
+
+func replyEventID(reply *channel.ReplyRef) string {
+	if reply == nil {
+		return ""
+	}
+	return strings.TrimSpace(reply.MessageID)
+}
+
+// textContent builds an m.text message, adding an HTML formatted body for
+// markdown.
+func textContent(text string, format channel.MessageFormat) messageContent {
+	text = truncateMatrixText(text)
+	content := messageContent{MsgType: "m.text", Body: text}
+	if format == channel.MessageFormatMarkdown {
+		if formatted := markdownToHTML(text); formatted != "" {
+			content.Format = htmlFormat
+			content.FormattedBody = formatted
+		}
+	}
+	return content
+}
+
+// editContent wraps new content in an m.replace edit of eventID. The outer
+// body is the fallback shown by clients without edit support.
+func editContent(eventID string, content messageContent) messageContent {
+	newContent := content
+	edit := messageContent{
+		MsgType:    content.MsgType,
+		Body:       "* " + content.Body,
+		NewContent: &newContent,
+		RelatesTo:  &relatesTo{RelType: "m.replace", EventID: eventID},
+	}
+	if content.FormattedBody != "" {
+		edit.Format = content.Format
+		edit.FormattedBody = "* " + content.FormattedBody
+	}
+	return edit
+}
+
+func truncateMatrixText(text string) string {
+	runes := []rune(text)
+	if len(runes) <= matrixMaxMessageLength {
+		return text
+	}
+	return string(runes[:matrixMaxMessageLength-3]) + "..."
+}
+
+// Send posts the message text as m.text and uploads each attachment as a
+// media event.
+func (a *MatrixAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, msg channel.OutboundMessage) error {
+	c, matrixCfg, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	room, threadID := outboundThread(msg.Target, msg.Message)
+	roomID, err := a.resolveRoom(ctx, c, matrixCfg, room)
+	if err != nil {
+		return err
+	}
+	text := strings.TrimSpace(msg.Message.PlainText())
+	if text == "" && len(msg.Message.Attachments) == 0 {
+		return fmt.Errorf("message is required")
+	}
+	replyID := replyEventID(msg.Message.Reply)
+	if text != "" {
+		content := textContent(text, msg.Message.Format)
+		content.RelatesTo = relationFor(threadID, replyID)
+		if _, err := c.sendEvent(ctx, roomID, "m.room.message", content); err != nil {
+			return err
+		}
+		replyID = ""
+	}
+	for _, att := range msg.Message.Attachments {
+		if _, err := a.sendAttachment(ctx, c, cfg.BotID, roomID, relationFor(threadID, replyID), att); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendAttachment uploads an attachment, or reuses its mxc:// URI, and sends
+// it as an image, audio, video or file message.
+func (a *MatrixAdapter) sendAttachment(ctx context.Context, c *client, botID, roomID string, rel *relatesTo, att channel.Attachment) (string, error) {
+	mxc := strings.TrimSpace(att.PlatformKey)
+	if !strings.HasPrefix(mxc, "mxc://") {
+		mxc = ""
+	}
+	mime := strings.TrimSpace(att.Mime)
+	name := strings.TrimSpace(att.Name)
+	size := att.Size
+	if mxc == "" {
+		reader, resolvedMime, resolvedName, err := a.resolveAttachmentUploadReader(ctx, att, botID)
+		if err != nil {
+			return "", err
+		}
+		defer func() {
+			_ = reader.Close()
+		}()
+		if mime == "" {
+			mime = resolvedMime
+		}
+		if name == "" {
+			name = resolvedName
+		}
+		data, err := media.ReadAllWithLimit(reader, media.MaxAssetBytes)
+		if err != nil {
+			return "", fmt.Errorf("failed to read attachment: %w", err)
+		}
+		mxc, err = c.upload(ctx, bytes.NewReader(data), int64(len(data)), mime, name)
+		if err != nil {
+			return "", fmt.Errorf("matrix upload attachment: %w", err)
+		}
+		size = int64(len(data))
+	}
+	if name == "" {
+		name = defaultAttachmentName(att.Type)
+	}
+	content := messageContent{
+		MsgType:  mediaMsgType(att.Type, mime),
+		Body:     name,
+		FileName: name,
+		URL:      mxc,
+		Info: &fileInfo{
+			MimeType: mime,
+			Size:     size,
+			Width:    att.Width,
+			Height:   att.Height,
+			Duration: att.DurationMs,
+		},
+		RelatesTo: rel,
+	}
+	if caption := strings.TrimSpace(att.Caption); caption != "" {
+		content.Body = caption
+	}
+	return c.sendEvent(ctx, roomID, "m.room.message", content)
+}
+
+func mediaMsgType(kind channel.AttachmentType, mime string) string {
+	switch kind {
+	case channel.AttachmentImage, channel.AttachmentGIF:
+		return "m.image"
+	case channel.AttachmentAudio, channel.AttachmentVoice:
+		return "m.audio"
+	case channel.AttachmentVideo:
+		return "m.video"
+	case channel.AttachmentFile:
+		return "m.file"
+	}
+	switch {
+	case strings.HasPrefix(mime, "image/"):
+		return "m.image"
+	case strings.HasPrefix(mime, "audio/"):
+		return "m.audio"
+	case strings.HasPrefix(mime, "video/"):
+		return "m.video"
+	default:
+		return "m.file"
+	}
+}
+
+func defaultAttachmentName(kind channel.AttachmentType) string {
+	switch kind {
+	case channel.AttachmentImage, channel.AttachmentGIF:
+		return "image"
+	case channel.AttachmentAudio, channel.AttachmentVoice:
+		return "audio"
+	case channel.AttachmentVideo:
+		return "video"
+	default:
+		return "attachment"
+	}
+}
+
+func (a *MatrixAdapter) resolveAttachmentUploadReader(ctx context.Context, att channel.Attachment, fallbackBotID string) (io.ReadCloser, string, string, error) {
+	assetID := strings.TrimSpace(att.ContentHash)
+	botID := strings.TrimSpace(fallbackBotID)
+	if botID == "" && att.Metadata != nil {
+		if value, ok := att.Metadata["bot_id"].(string); ok {
+			botID = strings.TrimSpace(value)
+		}
+	}
+	if assetID != "" && botID != "" && a.assets != nil {
+		reader, asset, err := a.assets.Open(ctx, botID, assetID)
+		if err == nil {
+			resolvedMime := strings.TrimSpace(att.Mime)
+			if resolvedMime == "" {
+				resolvedMime = strings.TrimSpace(asset.Mime)
+			}
+			return reader, resolvedMime, strings.TrimSpace(att.Name), nil
+		}
+		a.logger.Debug("matrix attachment storage open failed",
+			slog.String("bot_id", botID),
+			slog.String("content_hash", assetID),
+			slog.Any("error", err),
+		)
+	}
+
+	rawBase64 := strings.TrimSpace(att.Base64)
+	downloadURL := strings.TrimSpace(att.URL)
+	if rawBase64 == "" && strings.HasPrefix(strings.ToLower(downloadURL), "data:") {
+		rawBase64 = downloadURL
+	}
+	if rawBase64 != "" {
+		decoded, err := attachmentpkg.DecodeBase64(rawBase64, media.MaxAssetBytes)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to decode attachment base64: %w", err)
+		}
+		data, err := media.ReadAllWithLimit(decoded, media.MaxAssetBytes)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to read attachment base64: %w", err)
+		}
+		resolvedMime := strings.TrimSpace(att.Mime)
+		if resolvedMime == "" {
+			resolvedMime = strings.TrimSpace(attachmentpkg.MimeFromDataURL(rawBase64))
+		}
+		return io.NopCloser(bytes.NewReader(data)), resolvedMime, strings.TrimSpace(att.Name), nil
+	}
+
+	if downloadURL == "" {
+		return nil, "", "", fmt.Errorf("attachment reference is required: provide platform_key/content_hash/base64/url")
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to build download request: %w", err)
+	}
+	httpClient := &http.Client{Timeout: 60 * time.Second}
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to download attachment: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, "", "", fmt.Errorf("failed to download attachment, status: %d", resp.StatusCode)
+	}
+	if resp.ContentLength > media.MaxAssetBytes {
+		_ = resp.Body.Close()
+		return nil, "", "", fmt.Errorf("%w: max %d bytes", media.ErrAssetTooLarge, media.MaxAssetBytes)
+	}
+	resolvedMime := strings.TrimSpace(att.Mime)
+	if resolvedMime == "" {
+		resolvedMime = contentType(resp.Header)
+	}
+	return resp.Body, resolvedMime, strings.TrimSpace(att.Name), nil
+}
+
+func contentType(header http.Header) string {
+	mime := strings.TrimSpace(header.Get("Content-Type"))
+	if idx := strings.Index(mime, ";"); idx >= 0 {
+		mime = strings.TrimSpace(mime[:idx])
+	}
+	return mime
+}
+
+// OpenStream opens a stream that sends one message and keeps it updated with
+// m.replace edits as deltas arrive.
+func (a *MatrixAdapter) OpenStream(ctx context.Context, cfg channel.ChannelConfig, target string, opts channel.StreamOptions) (channel.OutboundStream, error) {
+	c, matrixCfg, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	room, threadID := splitTarget(target)
+	roomID, err := a.resolveRoom(ctx, c, matrixCfg, room)
+	if err != nil {
+		return nil, err
+	}
+	return &matrixOutboundStream{
+		adapter:  a,
+		cfg:      cfg,
+		client:   c,
+		roomID:   roomID,
+		threadID: threadID,
+		replyID:  replyEventID(opts.Reply),
+	}, nil
+}
+
+// Update replaces the content of a message the bot sent with an m.replace edit.
+func (a *MatrixAdapter) Update(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string, msg channel.Message) error {
+	c, matrixCfg, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	room, _ := splitTarget(target)
+	roomID, err := a.resolveRoom(ctx, c, matrixCfg, room)
+	if err != nil {
+		return err
+	}
+	text := strings.TrimSpace(msg.PlainText())
+	if text == "" {
+		return fmt.Errorf("message is required")
+	}
+	_, err = c.sendEvent(ctx, roomID, "m.room.message", editContent(strings.TrimSpace(messageID), textContent(text, msg.Format)))
+	return err
+}
+
+// Unsend redacts a message the bot sent.
+func (a *MatrixAdapter) Unsend(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string) error {
+	c, matrixCfg, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	room, _ := splitTarget(target)
+	roomID, err := a.resolveRoom(ctx, c, matrixCfg, room)
+	if err != nil {
+		return err
+	}
+	return c.redact(ctx, roomID, strings.TrimSpace(messageID))
+}
+
+type reactionContent struct {
+	RelatesTo relatesTo `json:"m.relates_to"`
+}
+
+// React adds an emoji reaction to a message as an m.annotation. Common
+// names such as "thumbsup" or ":eyes:" are converted to their emoji.
+func (a *MatrixAdapter) React(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string, emoji string) error {
+	c, matrixCfg, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	key, err := reactionKey(emoji)
+	if err != nil {
+		return err
+	}
+	room, _ := splitTarget(target)
+	roomID, err := a.resolveRoom(ctx, c, matrixCfg, room)
+	if err != nil {
+		return err
+	}
+	messageID = strings.TrimSpace(messageID)
+	existing, err := a.findOwnReaction(ctx, c, matrixCfg, roomID, messageID, key)
+	if err != nil || existing != "" {
+		return err
+	}
+	_, err = c.sendEvent(ctx, roomID, "m.reaction", reactionContent{
+		RelatesTo: relatesTo{RelType: "m.annotation", EventID: messageID, Key: key},
+	})
+	return err
+}
+
+// Unreact redacts the bot's reaction with emoji on a message.
+func (a *MatrixAdapter) Unreact(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string, emoji string) error {
+	c, matrixCfg, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	key, err := reactionKey(emoji)
+	if err != nil {
+		return err
+	}
+	room, _ := splitTarget(target)
+	roomID, err := a.resolveRoom(ctx, c, matrixCfg, room)
+	if err != nil {
+		return err
+	}
+	reactionID, err := a.findOwnReaction(ctx, c, matrixCfg, roomID, strings.TrimSpace(messageID), key)
+	if err != nil || reactionID == "" {
+		return err
+	}
+	return c.redact(ctx, roomID, reactionID)
+}
+
+// findOwnReaction returns the ID of the bot's reaction with key on eventID.
+func (a *MatrixAdapter) findOwnReaction(ctx context.Context, c *client, matrixCfg Config, roomID, eventID, key string) (string, error) {
+	selfID, err := a.selfUserID(ctx, c, matrixCfg)
+	if err != nil {
+		return "", err
+	}
+	events, err := c.relations(ctx, roomID, eventID, "m.annotation", "m.reaction")
+	if err != nil {
+		return "", fmt.Errorf("matrix list reactions: %w", err)
+	}
+	for _, ev := range events {
+		if ev.Sender != selfID {
+			continue
+		}
+		var content reactionContent
+		if err := decodeContent(ev, &content); err != nil {
+			continue
+		}
+		if content.RelatesTo.Key == key {
+			return ev.EventID, nil
+		}
+	}
+	return "", nil
+}
+
+// emojiByName maps common reaction names to the emoji Matrix uses as keys.
+var emojiByName = map[string]string{
+	"+1":               "👍",
+	"thumbsup":         "👍",
+	"-1":               "👎",
+	"thumbsdown":       "👎",
+	"eyes":             "👀",
+	"white_check_mark": "✅",
+	"check":            "✅",
+	"x":                "❌",
+	"heart":            "❤️",
+	"tada":             "🎉",
+	"joy":              "😂",
+	"smile":            "😄",
+	"pray":             "🙏",
+	"fire":             "🔥",
+	"rocket":           "🚀",
+	"ok_hand":          "👌",
+	"thinking_face":    "🤔",
+	"thinking":         "🤔",
+	"clap":             "👏",
+	"100":              "💯",
+}
+
+// reactionKey converts an emoji or a known emoji name into a reaction key.
+// Other text is used as is, which Matrix allows.
+func reactionKey(emoji string) (string, error) {
+	value := strings.TrimSpace(emoji)
+	if value == "" {
+		return "", fmt.Errorf("matrix reaction emoji is required")
+	}
+	if key, ok := emojiByName[strings.ToLower(strings.Trim(value, ":"))]; ok {
+		return key, nil
+	}
+	return value, nil
+}
+
+// ProcessingStarted shows the bot as typing in the source room.
+func (a *MatrixAdapter) ProcessingStarted(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, info channel.ProcessingStatusInfo) (channel.ProcessingStatusHandle, error) {
+	roomID := processingStatusRoom(msg, info)
+	if roomID == "" {
+		return channel.ProcessingStatusHandle{}, nil
+	}
+	if err := a.setTyping(ctx, cfg, roomID, true); err != nil {
+		return channel.ProcessingStatusHandle{}, err
+	}
+	return channel.ProcessingStatusHandle{Token: processingTypingToken}, nil
+}
+
+// ProcessingCompleted clears the typing notification before output is sent.
+func (a *MatrixAdapter) ProcessingCompleted(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, info channel.ProcessingStatusInfo, handle channel.ProcessingStatusHandle) error {
+	roomID := processingStatusRoom(msg, info)
+	if roomID == "" || handle.Token != processingTypingToken {
+		return nil
+	}
+	return a.setTyping(ctx, cfg, roomID, false)
+}
+
+// ProcessingFailed clears the typing notification when chat processing fails.
+func (a *MatrixAdapter) ProcessingFailed(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, info channel.ProcessingStatusInfo, handle channel.ProcessingStatusHandle, cause error) error {
+	return a.ProcessingCompleted(ctx, cfg, msg, info, handle)
+}
+
+func (a *MatrixAdapter) setTyping(ctx context.Context, cfg channel.ChannelConfig, roomID string, typing bool) error {
+	c, matrixCfg, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	selfID, err := a.selfUserID(ctx, c, matrixCfg)
+	if err != nil {
+		return err
+	}
+	return c.setTyping(ctx, roomID, selfID, typing, typingTimeout)
+}
+
+func processingStatusRoom(msg channel.InboundMessage, info channel.ProcessingStatusInfo) string {
+	roomID := strings.TrimSpace(msg.Conversation.ID)
+	if roomID == "" {
+		roomID, _ = splitTarget(info.ReplyTarget)
+	}
+	if !isRoomID(roomID) {
+		return ""
+	}
+	return roomID
+}
+
+// DiscoverSelf retrieves the bot account behind the access token with whoami
+// and its profile.
+func (a *MatrixAdapter) DiscoverSelf(ctx context.Context, credentials map[string]any) (map[string]any, string, error) {
+	c, _, err := a.clientFor(credentials)
+	if err != nil {
+		return nil, "", err
+	}
+	userID, err := c.whoami(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("matrix discover self: %w", err)
+	}
+	identity := map[string]any{
+		"user_id": userID,
+	}
+	if p, err := c.profile(ctx, userID); err == nil {
+		if name := strings.TrimSpace(p.DisplayName); name != "" {
+			identity["name"] = name
+		}
+		if avatar := strings.TrimSpace(p.AvatarURL); avatar != "" {
+			identity["avatar_url"] = avatar
+		}
+	} else {
+		a.logger.Debug("matrix profile lookup failed", slog.String("user_id", userID), slog.Any("error", err))
+	}
+	return identity, userID, nil
+}
+
+// ResolveAttachment downloads media from the homeserver. The attachment's
+// platform key is its mxc:// URI.
+func (a *MatrixAdapter) ResolveAttachment(ctx context.Context, cfg channel.ChannelConfig, attachment channel.Attachment) (channel.AttachmentPayload, error) {
+	c, _, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return channel.AttachmentPayload{}, err
+	}
+	mxc := strings.TrimSpace(attachment.PlatformKey)
+	if mxc == "" {
+		return channel.AttachmentPayload{}, fmt.Errorf("matrix attachment requires platform_key")
+	}
+	resp, err := c.download(ctx, mxc)
+	if err != nil {
+		return channel.AttachmentPayload{}, fmt.Errorf("download attachment: %w", err)
+	}
+	maxBytes := media.MaxAssetBytes
+	if resp.ContentLength > maxBytes {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return channel.AttachmentPayload{}, fmt.Errorf("%w: max %d bytes", media.ErrAssetTooLarge, maxBytes)
+	}
+	mime := strings.TrimSpace(attachment.Mime)
+	if mime == "" {
+		mime = contentType(resp.Header)
+	}
+	size := attachment.Size
+	if size <= 0 && resp.ContentLength > 0 {
+		size = resp.ContentLength
+	}
+	return channel.AttachmentPayload{
+		Reader: resp.Body,
+		Mime:   mime,
+		Name:   strings.TrimSpace(attachment.Name),
+		Size:   size,
+	}, nil
+}
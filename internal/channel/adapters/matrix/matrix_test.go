@@ -0,0 +1,646 @@
+package matrix
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const (
+	testSelfID = "@memoh:example.org"
+	testRoomID = "!family:example.org"
+)
+
+type fakeRequest struct {
+	method string
+	path   string
+	token  string
+	body   map[string]any
+}
+
+// fakeHomeserver serves the parts of the client-server API the adapter
+// uses. Routes are matched on the unescaped path.
+type fakeHomeserver struct {
+	server    *httptest.Server
+	mu        sync.Mutex
+	requests  []fakeRequest
+	syncs     chan map[string]any
+	eventSeq  int
+	events    map[string]map[string]any
+	relations []map[string]any
+	members   map[string]map[string]any
+	direct    map[string]any
+	media     map[string][]byte
+}
+
+func newFakeHomeserver(t *testing.T) *fakeHomeserver {
+	t.Helper()
+	f := &fakeHomeserver{
+		syncs:  make(chan map[string]any, 8),
+		events: map[string]map[string]any{},
+		members: map[string]map[string]any{
+			testRoomID: {
+				testSelfID:           map[string]any{"display_name": "Memoh"},
+				"@alice:example.org": map[string]any{"display_name": "Alice"},
+				"@bob:example.org":   map[string]any{"display_name": "Bob"},
+			},
+			"!dm:example.org": {
+				testSelfID:           map[string]any{"display_name": "Memoh"},
+				"@alice:example.org": map[string]any{"display_name": "Alice"},
+			},
+		},
+		direct: map[string]any{},
+		media:  map[string][]byte{},
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeHomeserver) config() channel.ChannelConfig {
+	return channel.ChannelConfig{
+		ID:          "cfg-1",
+		BotID:       "bot-1",
+		ChannelType: Type,
+		Credentials: map[string]any{
+			"homeserverUrl": f.server.URL,
+			"accessToken":   "syt_test",
+		},
+	}
+}
+
+func (f *fakeHomeserver) serve(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	var body map[string]any
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}
+	f.mu.Lock()
+	f.requests = append(f.requests, fakeRequest{
+		method: r.Method,
+		path:   path,
+		token:  strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
+		body:   body,
+	})
+	f.mu.Unlock()
+
+	client := strings.TrimPrefix(path, clientPrefix)
+	parts := strings.Split(strings.TrimPrefix(client, "/"), "/")
+	switch {
+	case path == clientPrefix+"/account/whoami":
+		writeJSON(w, map[string]any{"user_id": testSelfID})
+	case strings.HasPrefix(path, clientPrefix+"/profile/"):
+		if parts[1] == testSelfID {
+			writeJSON(w, map[string]any{"displayname": "Memoh", "avatar_url": "mxc://example.org/avatar"})
+			return
+		}
+		writeJSON(w, map[string]any{"displayname": "Alice"})
+	case path == clientPrefix+"/sync":
+		f.serveSync(w, r)
+	case strings.HasPrefix(path, clientPrefix+"/join/"):
+		writeJSON(w, map[string]any{"room_id": parts[1]})
+	case strings.HasPrefix(path, clientPrefix+"/rooms/") && len(parts) >= 3:
+		f.serveRoom(w, parts[1], parts[2:])
+	case strings.HasPrefix(path, clientV1Prefix+"/rooms/"):
+		f.mu.Lock()
+		chunk := f.relations
+		f.mu.Unlock()
+		writeJSON(w, map[string]any{"chunk": chunk})
+	case path == clientPrefix+"/joined_rooms":
+		writeJSON(w, map[string]any{"joined_rooms": []string{testRoomID, "!dm:example.org"}})
+	case strings.HasPrefix(path, clientPrefix+"/directory/room/"):
+		if parts[2] == "#family:example.org" {
+			writeJSON(w, map[string]any{"room_id": testRoomID})
+			return
+		}
+		writeError(w, http.StatusNotFound, "M_NOT_FOUND")
+	case path == clientPrefix+"/user_directory/search":
+		writeJSON(w, map[string]any{"results": []map[string]any{
+			{"user_id": "@alice:example.org", "display_name": "Alice"},
+			{"user_id": testSelfID, "display_name": "Memoh"},
+		}})
+	case strings.HasPrefix(path, clientPrefix+"/user/") && strings.HasSuffix(path, "/account_data/m.direct"):
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if r.Method == http.MethodPut {
+			f.direct = body
+			writeJSON(w, map[string]any{})
+			return
+		}
+		if len(f.direct) == 0 {
+			writeError(w, http.StatusNotFound, "M_NOT_FOUND")
+			return
+		}
+		writeJSON(w, f.direct)
+	case path == clientPrefix+"/createRoom":
+		writeJSON(w, map[string]any{"room_id": "!dm:example.org"})
+	case path == mediaPrefix+"/upload":
+		data, _ := io.ReadAll(r.Body)
+		f.mu.Lock()
+		id := "media" + strconv.Itoa(len(f.media)+1)
+		f.media[id] = data
+		f.requests[len(f.requests)-1].body = map[string]any{
+			"filename":     r.URL.Query().Get("filename"),
+			"content_type": r.Header.Get("Content-Type"),
+			"size":         len(data),
+		}
+		f.mu.Unlock()
+		writeJSON(w, map[string]any{"content_uri": "mxc://example.org/" + id})
+	case strings.HasPrefix(path, clientV1Prefix+"/media/download/"):
+		// Behave like a homeserver without authenticated media.
+		writeError(w, http.StatusNotFound, "M_UNRECOGNIZED")
+	case strings.HasPrefix(path, mediaPrefix+"/download/example.org/"):
+		f.mu.Lock()
+		data, ok := f.media[strings.TrimPrefix(path, mediaPrefix+"/download/example.org/")]
+		f.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusNotFound, "M_NOT_FOUND")
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(data)
+	default:
+		writeError(w, http.StatusNotFound, "M_UNRECOGNIZED")
+	}
+}
+
+func (f *fakeHomeserver) serveSync(w http.ResponseWriter, r *http.Request) {
+	since := r.URL.Query().Get("since")
+	if since == "" {
+		writeJSON(w, map[string]any{"next_batch": "s0"})
+		return
+	}
+	select {
+	case resp := <-f.syncs:
+		writeJSON(w, resp)
+	case <-time.After(50 * time.Millisecond):
+		writeJSON(w, map[string]any{"next_batch": since})
+	case <-r.Context().Done():
+	}
+}
+
+func (f *fakeHomeserver) serveRoom(w http.ResponseWriter, roomID string, rest []string) {
+	switch rest[0] {
+	case "send", "redact":
+		f.mu.Lock()
+		f.eventSeq++
+		eventID := "$ev" + strconv.Itoa(f.eventSeq)
+		f.mu.Unlock()
+		writeJSON(w, map[string]any{"event_id": eventID})
+	case "typing":
+		writeJSON(w, map[string]any{})
+	case "event":
+		f.mu.Lock()
+		ev, ok := f.events[rest[1]]
+		f.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusNotFound, "M_NOT_FOUND")
+			return
+		}
+		writeJSON(w, ev)
+	case "joined_members":
+		f.mu.Lock()
+		joined := f.members[roomID]
+		f.mu.Unlock()
+		writeJSON(w, map[string]any{"joined": joined})
+	case "state":
+		if roomID == testRoomID && rest[1] == "m.room.name" {
+			writeJSON(w, map[string]any{"name": "Family"})
+			return
+		}
+		if roomID == testRoomID && rest[1] == "m.room.canonical_alias" {
+			writeJSON(w, map[string]any{"alias": "#family:example.org"})
+			return
+		}
+		writeError(w, http.StatusNotFound, "M_NOT_FOUND")
+	default:
+		writeError(w, http.StatusNotFound, "M_UNRECOGNIZED")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"errcode": code, "error": "fake"})
+}
+
+// sent returns the requests whose path contains fragment.
+func (f *fakeHomeserver) sent(method, fragment string) []fakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []fakeRequest
+	for _, req := range f.requests {
+		if req.method == method && strings.Contains(req.path, fragment) {
+			out = append(out, req)
+		}
+	}
+	return out
+}
+
+func timelineEvent(eventID, sender string, content map[string]any) map[string]any {
+	return map[string]any{
+		"type":             "m.room.message",
+		"event_id":         eventID,
+		"sender":           sender,
+		"origin_server_ts": 1712345678000,
+		"content":          content,
+	}
+}
+
+func syncWith(batch string, events ...map[string]any) map[string]any {
+	return map[string]any{
+		"next_batch": batch,
+		"rooms": map[string]any{
+			"join": map[string]any{
+				testRoomID: map[string]any{"timeline": map[string]any{"events": events}},
+			},
+		},
+	}
+}
+
+func TestConnectDeliversRoomMessages(t *testing.T) {
+	fake := newFakeHomeserver(t)
+	fake.events["$root"] = timelineEvent("$root", testSelfID, map[string]any{"msgtype": "m.text", "body": "earlier"})
+	adapter := NewMatrixAdapter(nil)
+	received := make(chan channel.InboundMessage, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	conn, err := adapter.Connect(ctx, fake.config(), func(_ context.Context, _ channel.ChannelConfig, msg channel.InboundMessage) error {
+		received <- msg
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() {
+		_ = conn.Stop(context.Background())
+	}()
+
+	threaded := timelineEvent("$m1", "@alice:example.org", map[string]any{
+		"msgtype": "m.text",
+		"body":    "Memoh, what's for dinner?",
+		"m.relates_to": map[string]any{
+			"rel_type":        "m.thread",
+			"event_id":        "$root",
+			"is_falling_back": true,
+			"m.in_reply_to":   map[string]any{"event_id": "$latest"},
+		},
+	})
+	invite := syncWith("s1",
+		threaded,
+		// Own messages, notices and edits are not input.
+		timelineEvent("$m2", testSelfID, map[string]any{"msgtype": "m.text", "body": "mine"}),
+		timelineEvent("$m3", "@bob:example.org", map[string]any{"msgtype": "m.notice", "body": "beep"}),
+		timelineEvent("$m4", "@bob:example.org", map[string]any{
+			"msgtype":       "m.text",
+			"body":          "* fixed",
+			"m.new_content": map[string]any{"msgtype": "m.text", "body": "fixed"},
+			"m.relates_to":  map[string]any{"rel_type": "m.replace", "event_id": "$m1"},
+		}),
+		timelineEvent("$m5", "@bob:example.org", map[string]any{
+			"msgtype": "m.image",
+			"body":    "cat.png",
+			"url":     "mxc://example.org/cat",
+			"info":    map[string]any{"mimetype": "image/png", "size": 42, "w": 10, "h": 20},
+		}),
+	)
+	invite["rooms"].(map[string]any)["invite"] = map[string]any{"!new:example.org": map[string]any{}}
+	fake.syncs <- invite
+	// The same event delivered again must be dropped.
+	fake.syncs <- syncWith("s2", threaded)
+
+	first := waitInbound(t, received)
+	second := waitInbound(t, received)
+	if first.Message.ID != "$m1" {
+		first, second = second, first
+	}
+	if first.Message.Text != "Memoh, what's for dinner?" || first.Sender.DisplayName != "Alice" {
+		t.Fatalf("unexpected message: %#v", first)
+	}
+	if first.Message.Thread == nil || first.Message.Thread.ID != "$root" || first.Conversation.ThreadID != "$root" {
+		t.Fatalf("expected thread ref, got %#v", first.Message.Thread)
+	}
+	if first.ReplyTarget != testRoomID+"/$root" || first.Conversation.Type != "group" {
+		t.Fatalf("unexpected reply target %q type %q", first.ReplyTarget, first.Conversation.Type)
+	}
+	if first.Message.Reply == nil || first.Message.Reply.MessageID != "$root" {
+		t.Fatalf("expected reply to thread root, got %#v", first.Message.Reply)
+	}
+	if first.Metadata["is_mentioned"] != true || first.Metadata["is_reply_to_bot"] != true {
+		t.Fatalf("unexpected metadata: %#v", first.Metadata)
+	}
+	if len(second.Message.Attachments) != 1 {
+		t.Fatalf("expected one attachment, got %#v", second.Message)
+	}
+	att := second.Message.Attachments[0]
+	if att.Type != channel.AttachmentImage || att.PlatformKey != "mxc://example.org/cat" || att.URL != "" || att.Mime != "image/png" || att.Width != 10 {
+		t.Fatalf("unexpected attachment: %#v", att)
+	}
+	if second.Metadata["is_mentioned"] != false {
+		t.Fatalf("image should not be a mention: %#v", second.Metadata)
+	}
+	select {
+	case extra := <-received:
+		t.Fatalf("unexpected extra inbound: %#v", extra)
+	case <-time.After(150 * time.Millisecond):
+	}
+	if joins := fake.sent(http.MethodPost, "/join/!new:example.org"); len(joins) != 1 {
+		t.Fatalf("expected invite to be joined, got %d joins", len(joins))
+	}
+	syncs := fake.sent(http.MethodGet, "/sync")
+	if len(syncs) < 3 || syncs[0].token != "syt_test" {
+		t.Fatalf("unexpected sync requests: %#v", syncs)
+	}
+}
+
+func waitInbound(t *testing.T, ch <-chan channel.InboundMessage) channel.InboundMessage {
+	t.Helper()
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for inbound message")
+		return channel.InboundMessage{}
+	}
+}
+
+func relatesToOf(t *testing.T, body map[string]any) map[string]any {
+	t.Helper()
+	rel, ok := body["m.relates_to"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected m.relates_to in %#v", body)
+	}
+	return rel
+}
+
+func TestSendTextAndMedia(t *testing.T) {
+	fake := newFakeHomeserver(t)
+	adapter := NewMatrixAdapter(nil)
+	ctx := context.Background()
+	png := base64.StdEncoding.EncodeToString([]byte("png-bytes"))
+	err := adapter.Send(ctx, fake.config(), channel.OutboundMessage{
+		Target: testRoomID + "/$root",
+		Message: channel.Message{
+			Text:   "**Pasta** tonight",
+			Format: channel.MessageFormatMarkdown,
+			Attachments: []channel.Attachment{{
+				Type:   channel.AttachmentImage,
+				Base64: "data:image/png;base64," + png,
+				Name:   "menu.png",
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	sends := fake.sent(http.MethodPut, "/send/m.room.message/")
+	if len(sends) != 2 {
+		t.Fatalf("expected text and image events, got %d", len(sends))
+	}
+	text := sends[0].body
+	if text["msgtype"] != "m.text" || text["body"] != "**Pasta** tonight" {
+		t.Fatalf("unexpected text event: %#v", text)
+	}
+	if text["format"] != htmlFormat || text["formatted_body"] != "<p><strong>Pasta</strong> tonight</p>" {
+		t.Fatalf("unexpected formatted body: %#v", text)
+	}
+	rel := relatesToOf(t, text)
+	if rel["rel_type"] != "m.thread" || rel["event_id"] != "$root" || rel["is_falling_back"] != true {
+		t.Fatalf("unexpected thread relation: %#v", rel)
+	}
+	uploads := fake.sent(http.MethodPost, "/upload")
+	if len(uploads) != 1 || uploads[0].body["filename"] != "menu.png" || uploads[0].body["content_type"] != "image/png" || uploads[0].body["size"] != 9 {
+		t.Fatalf("unexpected uploads: %#v", uploads)
+	}
+	image := sends[1].body
+	if image["msgtype"] != "m.image" || image["url"] != "mxc://example.org/media1" || image["body"] != "menu.png" {
+		t.Fatalf("unexpected image event: %#v", image)
+	}
+	if info, _ := image["info"].(map[string]any); info["mimetype"] != "image/png" || info["size"] != float64(9) {
+		t.Fatalf("unexpected image info: %#v", image["info"])
+	}
+	if relatesToOf(t, image)["event_id"] != "$root" {
+		t.Fatalf("image should stay in the thread: %#v", image)
+	}
+
+	err = adapter.Send(ctx, fake.config(), channel.OutboundMessage{
+		Target:  "#family:example.org",
+		Message: channel.Message{Text: "plain", Reply: &channel.ReplyRef{MessageID: "$q"}},
+	})
+	if err != nil {
+		t.Fatalf("send reply: %v", err)
+	}
+	sends = fake.sent(http.MethodPut, "/rooms/"+testRoomID+"/send/m.room.message/")
+	reply := sends[len(sends)-1].body
+	if _, ok := reply["formatted_body"]; ok {
+		t.Fatalf("plain text should not be formatted: %#v", reply)
+	}
+	if inReply, _ := relatesToOf(t, reply)["m.in_reply_to"].(map[string]any); inReply["event_id"] != "$q" {
+		t.Fatalf("unexpected reply relation: %#v", reply)
+	}
+}
+
+func TestSendToUserUsesDirectRoom(t *testing.T) {
+	fake := newFakeHomeserver(t)
+	adapter := NewMatrixAdapter(nil)
+	ctx := context.Background()
+	for range 2 {
+		err := adapter.Send(ctx, fake.config(), channel.OutboundMessage{
+			Target:  "https://matrix.to/#/@alice:example.org",
+			Message: channel.Message{Text: "hi"},
+		})
+		if err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	if created := fake.sent(http.MethodPost, "/createRoom"); len(created) != 1 || created[0].body["is_direct"] != true {
+		t.Fatalf("expected one direct room to be created, got %#v", created)
+	}
+	if sends := fake.sent(http.MethodPut, "/rooms/!dm:example.org/send/"); len(sends) != 2 {
+		t.Fatalf("expected two messages in the direct room, got %d", len(sends))
+	}
+	fake.mu.Lock()
+	direct := fake.direct
+	fake.mu.Unlock()
+	if rooms, _ := direct["@alice:example.org"].([]any); len(rooms) != 1 || rooms[0] != "!dm:example.org" {
+		t.Fatalf("direct room not recorded in m.direct: %#v", direct)
+	}
+
+	// A new adapter finds the room through m.direct.
+	other := NewMatrixAdapter(nil)
+	if err := other.Send(ctx, fake.config(), channel.OutboundMessage{Target: "@alice:example.org", Message: channel.Message{Text: "again"}}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if created := fake.sent(http.MethodPost, "/createRoom"); len(created) != 1 {
+		t.Fatalf("expected the recorded direct room to be reused, got %d creates", len(created))
+	}
+}
+
+func TestStreamSendsThenEdits(t *testing.T) {
+	fake := newFakeHomeserver(t)
+	adapter := NewMatrixAdapter(nil)
+	ctx := context.Background()
+	stream, err := adapter.OpenStream(ctx, fake.config(), testRoomID, channel.StreamOptions{
+		Reply: &channel.ReplyRef{Target: testRoomID, MessageID: "$q"},
+	})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	events := []channel.StreamEvent{
+		{Type: channel.StreamEventDelta, Delta: "Hello"},
+		{Type: channel.StreamEventDelta, Delta: " **world**"},
+		{Type: channel.StreamEventFinal, Final: &channel.StreamFinalizePayload{Message: channel.Message{Text: "Hello **world**", Format: channel.MessageFormatMarkdown}}},
+	}
+	for _, event := range events {
+		if err := stream.Push(ctx, event); err != nil {
+			t.Fatalf("push %s: %v", event.Type, err)
+		}
+	}
+	if err := stream.Close(ctx); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	sends := fake.sent(http.MethodPut, "/send/m.room.message/")
+	if len(sends) != 2 {
+		t.Fatalf("expected the throttled stream to send and edit once, got %d events", len(sends))
+	}
+	first := sends[0].body
+	if first["body"] != "Hello"+matrixStreamPendingSuffix {
+		t.Fatalf("unexpected first event: %#v", first)
+	}
+	if inReply, _ := relatesToOf(t, first)["m.in_reply_to"].(map[string]any); inReply["event_id"] != "$q" {
+		t.Fatalf("first event should reply to the source: %#v", first)
+	}
+	edit := sends[1].body
+	rel := relatesToOf(t, edit)
+	if rel["rel_type"] != "m.replace" || rel["event_id"] != "$ev1" {
+		t.Fatalf("unexpected edit relation: %#v", rel)
+	}
+	newContent, _ := edit["m.new_content"].(map[string]any)
+	if newContent["body"] != "Hello **world**" || newContent["formatted_body"] != "<p>Hello <strong>world</strong></p>" {
+		t.Fatalf("unexpected new content: %#v", newContent)
+	}
+	if edit["body"] != "* Hello **world**" {
+		t.Fatalf("unexpected edit fallback: %#v", edit["body"])
+	}
+}
+
+func TestReactionsAndProcessingStatus(t *testing.T) {
+	fake := newFakeHomeserver(t)
+	adapter := NewMatrixAdapter(nil)
+	ctx := context.Background()
+	cfg := fake.config()
+	if err := adapter.React(ctx, cfg, testRoomID, "$m1", ":eyes:"); err != nil {
+		t.Fatalf("react: %v", err)
+	}
+	reactions := fake.sent(http.MethodPut, "/send/m.reaction/")
+	if len(reactions) != 1 {
+		t.Fatalf("expected one reaction, got %d", len(reactions))
+	}
+	rel := relatesToOf(t, reactions[0].body)
+	if rel["rel_type"] != "m.annotation" || rel["event_id"] != "$m1" || rel["key"] != "👀" {
+		t.Fatalf("unexpected reaction: %#v", rel)
+	}
+
+	fake.mu.Lock()
+	fake.relations = []map[string]any{
+		{"type": "m.reaction", "event_id": "$r0", "sender": "@alice:example.org", "content": map[string]any{"m.relates_to": map[string]any{"rel_type": "m.annotation", "event_id": "$m1", "key": "👀"}}},
+		{"type": "m.reaction", "event_id": "$r1", "sender": testSelfID, "content": map[string]any{"m.relates_to": map[string]any{"rel_type": "m.annotation", "event_id": "$m1", "key": "👀"}}},
+	}
+	fake.mu.Unlock()
+	// Reacting again is a no-op.
+	if err := adapter.React(ctx, cfg, testRoomID, "$m1", "👀"); err != nil {
+		t.Fatalf("react again: %v", err)
+	}
+	if got := len(fake.sent(http.MethodPut, "/send/m.reaction/")); got != 1 {
+		t.Fatalf("expected no duplicate reaction, got %d", got)
+	}
+	if err := adapter.Unreact(ctx, cfg, testRoomID, "$m1", "eyes"); err != nil {
+		t.Fatalf("unreact: %v", err)
+	}
+	if redacts := fake.sent(http.MethodPut, "/redact/$r1/"); len(redacts) != 1 {
+		t.Fatalf("expected own reaction to be redacted, got %#v", fake.sent(http.MethodPut, "/redact/"))
+	}
+
+	msg := channel.InboundMessage{Conversation: channel.Conversation{ID: testRoomID}}
+	info := channel.ProcessingStatusInfo{SourceMessageID: "$m1"}
+	handle, err := adapter.ProcessingStarted(ctx, cfg, msg, info)
+	if err != nil || handle.Token != processingTypingToken {
+		t.Fatalf("processing started: %#v %v", handle, err)
+	}
+	if err := adapter.ProcessingCompleted(ctx, cfg, msg, info, handle); err != nil {
+		t.Fatalf("processing completed: %v", err)
+	}
+	typing := fake.sent(http.MethodPut, "/typing/"+testSelfID)
+	if len(typing) != 2 || typing[0].body["typing"] != true || typing[1].body["typing"] != false {
+		t.Fatalf("unexpected typing requests: %#v", typing)
+	}
+}
+
+func TestUpdateAndUnsend(t *testing.T) {
+	fake := newFakeHomeserver(t)
+	adapter := NewMatrixAdapter(nil)
+	ctx := context.Background()
+	cfg := fake.config()
+	if err := adapter.Update(ctx, cfg, testRoomID+"/$root", "$m1", channel.Message{Text: "edited"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	sends := fake.sent(http.MethodPut, "/send/m.room.message/")
+	if len(sends) != 1 {
+		t.Fatalf("expected one edit, got %d", len(sends))
+	}
+	if rel := relatesToOf(t, sends[0].body); rel["rel_type"] != "m.replace" || rel["event_id"] != "$m1" {
+		t.Fatalf("unexpected edit: %#v", sends[0].body)
+	}
+	if err := adapter.Unsend(ctx, cfg, testRoomID, "$m1"); err != nil {
+		t.Fatalf("unsend: %v", err)
+	}
+	if redacts := fake.sent(http.MethodPut, "/rooms/"+testRoomID+"/redact/$m1/"); len(redacts) != 1 {
+		t.Fatalf("expected one redaction, got %d", len(redacts))
+	}
+	if err := adapter.Update(ctx, cfg, "not-a-room", "$m1", channel.Message{Text: "x"}); err == nil {
+		t.Fatal("expected error for invalid target")
+	}
+}
+
+func TestDiscoverSelfAndResolveAttachment(t *testing.T) {
+	fake := newFakeHomeserver(t)
+	fake.media["cat"] = []byte("meow")
+	adapter := NewMatrixAdapter(nil)
+	ctx := context.Background()
+	identity, externalID, err := adapter.DiscoverSelf(ctx, fake.config().Credentials)
+	if err != nil {
+		t.Fatalf("discover self: %v", err)
+	}
+	if externalID != testSelfID || identity["name"] != "Memoh" || identity["avatar_url"] != "mxc://example.org/avatar" {
+		t.Fatalf("unexpected identity %q %#v", externalID, identity)
+	}
+	payload, err := adapter.ResolveAttachment(ctx, fake.config(), channel.Attachment{PlatformKey: "mxc://example.org/cat", Name: "cat.png"})
+	if err != nil {
+		t.Fatalf("resolve attachment: %v", err)
+	}
+	defer func() {
+		_ = payload.Reader.Close()
+	}()
+	data, _ := io.ReadAll(payload.Reader)
+	if string(data) != "meow" || payload.Mime != "image/png" || payload.Name != "cat.png" {
+		t.Fatalf("unexpected payload %q %#v", data, payload)
+	}
+	if _, err := adapter.ResolveAttachment(ctx, fake.config(), channel.Attachment{PlatformKey: "https://example.org/x"}); err == nil {
+		t.Fatal("expected error for non-mxc platform key")
+	}
+}
@@ -0,0 +1,250 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const matrixStreamEditThrottle = 1000 * time.Millisecond
+const matrixStreamPendingSuffix = " …"
+const matrixFinalEditMaxRetries = 3
+
+// matrixOutboundStream sends the first delta as a message and edits it with
+// m.replace events as more text arrives. A tool call ends the current message
+// so later output starts a new one below the tool activity.
+type matrixOutboundStream struct {
+	adapter      *MatrixAdapter
+	cfg          channel.ChannelConfig
+	client       *client
+	roomID       string
+	threadID     string
+	replyID      string
+	closed       atomic.Bool
+	mu           sync.Mutex
+	buf          strings.Builder
+	eventID      string
+	lastEdited   string
+	lastEditedAt time.Time
+}
+
+// relation returns the thread or reply relation for a new message. Only the
+// first message of a stream replies to the source message.
+func (s *matrixOutboundStream) relation() *relatesTo {
+	rel := relationFor(s.threadID, s.replyID)
+	s.replyID = ""
+	return rel
+}
+
+func (s *matrixOutboundStream) ensureStreamMessage(ctx context.Context, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.eventID != "" {
+		return nil
+	}
+	if strings.TrimSpace(text) == "" {
+		text = "…"
+	} else {
+		text = strings.TrimSpace(text) + matrixStreamPendingSuffix
+	}
+	content := textContent(text, channel.MessageFormatPlain)
+	content.RelatesTo = s.relation()
+	eventID, err := s.client.sendEvent(ctx, s.roomID, "m.room.message", content)
+	if err != nil {
+		return err
+	}
+	s.eventID = eventID
+	s.lastEdited = text
+	s.lastEditedAt = time.Now()
+	return nil
+}
+
+func (s *matrixOutboundStream) editStreamMessage(ctx context.Context, text string) error {
+	s.mu.Lock()
+	eventID := s.eventID
+	lastEdited := s.lastEdited
+	lastEditedAt := s.lastEditedAt
+	s.mu.Unlock()
+	if eventID == "" {
+		return nil
+	}
+	text = strings.TrimSpace(text) + matrixStreamPendingSuffix
+	if text == lastEdited || time.Since(lastEditedAt) < matrixStreamEditThrottle {
+		return nil
+	}
+	content := editContent(eventID, textContent(text, channel.MessageFormatPlain))
+	if _, err := s.client.sendEvent(ctx, s.roomID, "m.room.message", content); err != nil {
+		if isAPIError(err, "M_LIMIT_EXCEEDED") {
+			d := retryAfter(err)
+			if d <= 0 {
+				d = matrixStreamEditThrottle
+			}
+			s.mu.Lock()
+			s.lastEditedAt = time.Now().Add(d)
+			s.mu.Unlock()
+			return nil
+		}
+		return err
+	}
+	s.mu.Lock()
+	s.lastEdited = text
+	s.lastEditedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// editStreamMessageFinal writes the final text, retrying when rate limited so
+// the message never keeps its pending suffix.
+func (s *matrixOutboundStream) editStreamMessageFinal(ctx context.Context, text string, format channel.MessageFormat) error {
+	s.mu.Lock()
+	eventID := s.eventID
+	lastEdited := s.lastEdited
+	s.mu.Unlock()
+	if eventID == "" || text == lastEdited {
+		return nil
+	}
+	content := editContent(eventID, textContent(text, format))
+	for attempt := range matrixFinalEditMaxRetries {
+		_, err := s.client.sendEvent(ctx, s.roomID, "m.room.message", content)
+		if err == nil {
+			s.mu.Lock()
+			s.lastEdited = text
+			s.lastEditedAt = time.Now()
+			s.mu.Unlock()
+			return nil
+		}
+		if !isAPIError(err, "M_LIMIT_EXCEEDED") {
+			return err
+		}
+		d := retryAfter(err)
+		if d <= 0 {
+			d = time.Duration(attempt+1) * time.Second
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
+	}
+	return fmt.Errorf("matrix edit message: rate limited")
+}
+
+func (s *matrixOutboundStream) resetStreamMessage() {
+	s.mu.Lock()
+	s.eventID = ""
+	s.lastEdited = ""
+	s.lastEditedAt = time.Time{}
+	s.buf.Reset()
+	s.mu.Unlock()
+}
+
+func (s *matrixOutboundStream) Push(ctx context.Context, event channel.StreamEvent) error {
+	if s == nil || s.adapter == nil {
+		return fmt.Errorf("matrix stream not configured")
+	}
+	if s.closed.Load() {
+		return fmt.Errorf("matrix stream is closed")
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	switch event.Type {
+	case channel.StreamEventToolCallStart:
+		s.mu.Lock()
+		bufText := strings.TrimSpace(s.buf.String())
+		hasMsg := s.eventID != ""
+		s.mu.Unlock()
+		if hasMsg && bufText != "" {
+			_ = s.editStreamMessageFinal(ctx, bufText, channel.MessageFormatMarkdown)
+		}
+		s.resetStreamMessage()
+		return nil
+	case channel.StreamEventToolCallEnd:
+		s.resetStreamMessage()
+		return nil
+	case channel.StreamEventDelta:
+		if event.Delta == "" || event.Phase == channel.StreamPhaseReasoning {
+			return nil
+		}
+		s.mu.Lock()
+		s.buf.WriteString(event.Delta)
+		content := s.buf.String()
+		s.mu.Unlock()
+		if err := s.ensureStreamMessage(ctx, content); err != nil {
+			return err
+		}
+		return s.editStreamMessage(ctx, content)
+	case channel.StreamEventFinal:
+		s.mu.Lock()
+		finalText := strings.TrimSpace(s.buf.String())
+		s.mu.Unlock()
+		format := channel.MessageFormatMarkdown
+		var attachments []channel.Attachment
+		if event.Final != nil && !event.Final.Message.IsEmpty() {
+			msg := event.Final.Message
+			if finalText == "" {
+				finalText = strings.TrimSpace(msg.PlainText())
+				format = msg.Format
+			}
+			attachments = msg.Attachments
+		}
+		if finalText != "" {
+			if err := s.ensureStreamMessage(ctx, finalText); err != nil {
+				return err
+			}
+			if err := s.editStreamMessageFinal(ctx, finalText, format); err != nil {
+				return err
+			}
+		}
+		return s.sendAttachments(ctx, attachments)
+	case channel.StreamEventError:
+		errText := strings.TrimSpace(event.Error)
+		if errText == "" {
+			return nil
+		}
+		display := "Error: " + errText
+		if err := s.ensureStreamMessage(ctx, display); err != nil {
+			return err
+		}
+		return s.editStreamMessageFinal(ctx, display, channel.MessageFormatPlain)
+	case channel.StreamEventAttachment:
+		return s.sendAttachments(ctx, event.Attachments)
+	default:
+		return nil
+	}
+}
+
+// sendAttachments uploads attachments as separate media messages.
+func (s *matrixOutboundStream) sendAttachments(ctx context.Context, attachments []channel.Attachment) error {
+	for _, att := range attachments {
+		s.mu.Lock()
+		rel := s.relation()
+		s.mu.Unlock()
+		if _, err := s.adapter.sendAttachment(ctx, s.client, s.cfg.BotID, s.roomID, rel, att); err != nil {
+			s.adapter.logger.Warn("stream attachment send failed", slog.String("config_id", s.cfg.ID), slog.Any("error", err))
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *matrixOutboundStream) Close(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	s.closed.Store(true)
+	return nil
+}
@@ -0,0 +1,259 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const apiResponseLimit = 8 << 20
+
+// apiError is a failed Slack Web API call: either a response with ok=false or
+// a rate limited request.
+type apiError struct {
+	Method     string
+	Code       string
+	RetryAfter time.Duration
+}
+
+func (e *apiError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("slack %s: %s (retry after %s)", e.Method, e.Code, e.RetryAfter)
+	}
+	return fmt.Sprintf("slack %s: %s", e.Method, e.Code)
+}
+
+// isAPIError reports whether err is a Slack API error with one of codes.
+func isAPIError(err error, codes ...string) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// retryAfter returns the backoff requested by a rate limited call, or zero.
+func retryAfter(err error) time.Duration {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.Code == "ratelimited" {
+		return apiErr.RetryAfter
+	}
+	return 0
+}
+
+// apiClient calls Slack Web API methods with one token.
+type apiClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func newAPIClient(httpClient *http.Client, baseURL, token string) *apiClient {
+	return &apiClient{baseURL: baseURL, token: token, http: httpClient}
+}
+
+type apiResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+type responseMetadata struct {
+	NextCursor string `json:"next_cursor"`
+}
+
+// call POSTs form params to the method and decodes the response into out,
+// which may be nil.
+func (c *apiClient) call(ctx context.Context, method string, params url.Values, out any) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+method, strings.NewReader(params.Encode()))
+	if err != nil {
+		return fmt.Errorf("slack %s: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack %s: %w", method, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode == http.StatusTooManyRequests {
+		seconds, _ := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Retry-After")))
+		return &apiError{Method: method, Code: "ratelimited", RetryAfter: time.Duration(seconds) * time.Second}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack %s: unexpected status %d", method, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, apiResponseLimit))
+	if err != nil {
+		return fmt.Errorf("slack %s: read response: %w", method, err)
+	}
+	var status apiResponse
+	if err := json.Unmarshal(body, &status); err != nil {
+		return fmt.Errorf("slack %s: parse response: %w", method, err)
+	}
+	if !status.OK {
+		code := status.Error
+		if code == "" {
+			code = "unknown_error"
+		}
+		return &apiError{Method: method, Code: code}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("slack %s: parse response: %w", method, err)
+	}
+	return nil
+}
+
+type slackUser struct {
+	ID       string `json:"id"`
+	TeamID   string `json:"team_id"`
+	Name     string `json:"name"`
+	RealName string `json:"real_name"`
+	Deleted  bool   `json:"deleted"`
+	IsBot    bool   `json:"is_bot"`
+	Profile  struct {
+		DisplayName string `json:"display_name"`
+		RealName    string `json:"real_name"`
+		Image72     string `json:"image_72"`
+	} `json:"profile"`
+}
+
+// displayName picks the name Slack shows for the user.
+func (u slackUser) displayName() string {
+	for _, name := range []string{u.Profile.DisplayName, u.Profile.RealName, u.RealName, u.Name} {
+		if name = strings.TrimSpace(name); name != "" {
+			return name
+		}
+	}
+	return u.ID
+}
+
+type slackConversation struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	IsIM       bool   `json:"is_im"`
+	IsMPIM     bool   `json:"is_mpim"`
+	IsPrivate  bool   `json:"is_private"`
+	IsMember   bool   `json:"is_member"`
+	IsArchived bool   `json:"is_archived"`
+	NumMembers int    `json:"num_members"`
+	Topic      struct {
+		Value string `json:"value"`
+	} `json:"topic"`
+}
+
+type authTestResponse struct {
+	UserID string `json:"user_id"`
+	User   string `json:"user"`
+	BotID  string `json:"bot_id"`
+	TeamID string `json:"team_id"`
+	Team   string `json:"team"`
+	URL    string `json:"url"`
+}
+
+func (c *apiClient) authTest(ctx context.Context) (authTestResponse, error) {
+	var resp authTestResponse
+	err := c.call(ctx, "auth.test", nil, &resp)
+	return resp, err
+}
+
+func (c *apiClient) userInfo(ctx context.Context, userID string) (slackUser, error) {
+	var resp struct {
+		User slackUser `json:"user"`
+	}
+	err := c.call(ctx, "users.info", url.Values{"user": {userID}}, &resp)
+	return resp.User, err
+}
+
+func (c *apiClient) conversationInfo(ctx context.Context, channelID string) (slackConversation, error) {
+	var resp struct {
+		Channel slackConversation `json:"channel"`
+	}
+	err := c.call(ctx, "conversations.info", url.Values{"channel": {channelID}}, &resp)
+	return resp.Channel, err
+}
+
+type postMessageResponse struct {
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+// postMessage sends text to the channel, inside the thread when threadTS is
+// set, and returns the channel and timestamp of the new message.
+func (c *apiClient) postMessage(ctx context.Context, channelID, threadTS, text string) (postMessageResponse, error) {
+	params := url.Values{
+		"channel": {channelID},
+		"text":    {text},
+	}
+	if threadTS != "" {
+		params.Set("thread_ts", threadTS)
+	}
+	var resp postMessageResponse
+	err := c.call(ctx, "chat.postMessage", params, &resp)
+	return resp, err
+}
+
+func (c *apiClient) updateMessage(ctx context.Context, channelID, ts, text string) error {
+	return c.call(ctx, "chat.update", url.Values{
+		"channel": {channelID},
+		"ts":      {ts},
+		"text":    {text},
+	}, nil)
+}
+
+func (c *apiClient) deleteMessage(ctx context.Context, channelID, ts string) error {
+	return c.call(ctx, "chat.delete", url.Values{
+		"channel": {channelID},
+		"ts":      {ts},
+	}, nil)
+}
+
+func (c *apiClient) addReaction(ctx context.Context, channelID, ts, name string) error {
+	return c.call(ctx, "reactions.add", url.Values{
+		"channel":   {channelID},
+		"timestamp": {ts},
+		"name":      {name},
+	}, nil)
+}
+
+func (c *apiClient) removeReaction(ctx context.Context, channelID, ts, name string) error {
+	return c.call(ctx, "reactions.remove", url.Values{
+		"channel":   {channelID},
+		"timestamp": {ts},
+		"name":      {name},
+	}, nil)
+}
+
+// openSocketURL requests a Socket Mode WebSocket URL. The client must use the
+// app-level token.
+func (c *apiClient) openSocketURL(ctx context.Context) (string, error) {
+	var resp struct {
+		URL string `json:"url"`
+	}
+	if err := c.call(ctx, "apps.connections.open", nil, &resp); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(resp.URL) == "" {
+		return "", fmt.Errorf("slack apps.connections.open: empty url")
+	}
+	return resp.URL, nil
+}
@@ -0,0 +1,192 @@
+package slack
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const defaultAPIBaseURL = "https://slack.com/api/"
+
+// Config holds the Slack app credentials extracted from a channel configuration.
+type Config struct {
+	// BotToken is the xoxb- bot token used for the Web API.
+	BotToken string
+	// AppToken is the xapp- app-level token used to open Socket Mode connections.
+	AppToken string
+	// APIBaseURL overrides the Web API endpoint, e.g. for a proxy.
+	APIBaseURL string
+}
+
+// UserConfig holds the identifiers used to target a Slack user or channel.
+type UserConfig struct {
+	UserID    string
+	ChannelID string
+	Username  string
+}
+
+func (c Config) apiBaseURL() string {
+	if c.APIBaseURL == "" {
+		return defaultAPIBaseURL
+	}
+	return c.APIBaseURL
+}
+
+func normalizeConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]any{
+		"botToken": cfg.BotToken,
+		"appToken": cfg.AppToken,
+	}
+	if cfg.APIBaseURL != "" {
+		result["apiBaseUrl"] = cfg.APIBaseURL
+	}
+	return result, nil
+}
+
+func normalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]any{}
+	if cfg.UserID != "" {
+		result["user_id"] = cfg.UserID
+	}
+	if cfg.ChannelID != "" {
+		result["channel_id"] = cfg.ChannelID
+	}
+	if cfg.Username != "" {
+		result["username"] = cfg.Username
+	}
+	return result, nil
+}
+
+func resolveTarget(raw map[string]any) (string, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return "", err
+	}
+	if cfg.ChannelID != "" {
+		return cfg.ChannelID, nil
+	}
+	if cfg.UserID != "" {
+		return cfg.UserID, nil
+	}
+	return "", fmt.Errorf("slack binding is incomplete")
+}
+
+func matchBinding(raw map[string]any, criteria channel.BindingCriteria) bool {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return false
+	}
+	if value := strings.TrimSpace(criteria.Attribute("user_id")); value != "" && value == cfg.UserID {
+		return true
+	}
+	if value := strings.TrimSpace(criteria.Attribute("channel_id")); value != "" && value == cfg.ChannelID {
+		return true
+	}
+	if value := strings.TrimSpace(criteria.Attribute("username")); value != "" && strings.EqualFold(value, cfg.Username) {
+		return true
+	}
+	if criteria.SubjectID != "" {
+		if criteria.SubjectID == cfg.UserID || criteria.SubjectID == cfg.ChannelID {
+			return true
+		}
+	}
+	return false
+}
+
+func buildUserConfig(identity channel.Identity) map[string]any {
+	result := map[string]any{}
+	if value := strings.TrimSpace(identity.Attribute("user_id")); value != "" {
+		result["user_id"] = value
+	}
+	if value := strings.TrimSpace(identity.Attribute("channel_id")); value != "" {
+		result["channel_id"] = value
+	}
+	if value := strings.TrimSpace(identity.Attribute("username")); value != "" {
+		result["username"] = value
+	}
+	return result
+}
+
+func parseConfig(raw map[string]any) (Config, error) {
+	botToken := strings.TrimSpace(channel.ReadString(raw, "botToken", "bot_token"))
+	if botToken == "" {
+		return Config{}, fmt.Errorf("slack botToken is required")
+	}
+	appToken := strings.TrimSpace(channel.ReadString(raw, "appToken", "app_token"))
+	if appToken == "" {
+		return Config{}, fmt.Errorf("slack appToken is required")
+	}
+	baseURL := strings.TrimSpace(channel.ReadString(raw, "apiBaseUrl", "api_base_url"))
+	if baseURL != "" {
+		parsed, err := url.Parse(baseURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return Config{}, fmt.Errorf("slack apiBaseUrl must be an http(s) URL")
+		}
+		if !strings.HasSuffix(baseURL, "/") {
+			baseURL += "/"
+		}
+	}
+	return Config{BotToken: botToken, AppToken: appToken, APIBaseURL: baseURL}, nil
+}
+
+func parseUserConfig(raw map[string]any) (UserConfig, error) {
+	userID := strings.TrimSpace(channel.ReadString(raw, "userId", "user_id"))
+	channelID := strings.TrimSpace(channel.ReadString(raw, "channelId", "channel_id"))
+	username := strings.TrimSpace(channel.ReadString(raw, "username"))
+	if userID == "" && channelID == "" {
+		return UserConfig{}, fmt.Errorf("slack user config requires user_id or channel_id")
+	}
+	return UserConfig{
+		UserID:    userID,
+		ChannelID: channelID,
+		Username:  username,
+	}, nil
+}
+
+// normalizeTarget trims a target and unwraps Slack mention syntax such as
+// <@U123> or <#C123|general>. Thread targets ("C123:1712345678.000100") are
+// kept as is.
+func normalizeTarget(raw string) string {
+	value := strings.TrimSpace(raw)
+	if strings.HasPrefix(value, "<") && strings.HasSuffix(value, ">") {
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+		if idx := strings.Index(value, "|"); idx >= 0 {
+			value = value[:idx]
+		}
+		value = strings.TrimLeft(value, "@#")
+	}
+	return strings.TrimSpace(value)
+}
+
+// splitTarget separates a "channel:thread_ts" target into its channel ID and
+// thread timestamp. Slack IDs never contain a colon.
+func splitTarget(target string) (channelID, threadTS string) {
+	target = normalizeTarget(target)
+	if idx := strings.Index(target, ":"); idx >= 0 {
+		return strings.TrimSpace(target[:idx]), strings.TrimSpace(target[idx+1:])
+	}
+	return target, ""
+}
+
+// joinTarget builds the target of a channel or, with threadTS, of a thread in it.
+func joinTarget(channelID, threadTS string) string {
+	if threadTS == "" {
+		return channelID
+	}
+	return channelID + ":" + threadTS
+}
+
+// isDirectChannel reports whether channelID is a direct message conversation.
+func isDirectChannel(channelID string) bool {
+	return strings.HasPrefix(channelID, "D")
+}
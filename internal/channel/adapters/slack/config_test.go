@@ -0,0 +1,68 @@
+package slack
+
+import (
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestParseConfig(t *testing.T) {
+	if _, err := parseConfig(map[string]any{"botToken": "xoxb-1"}); err == nil {
+		t.Fatal("expected error without app token")
+	}
+	if _, err := parseConfig(map[string]any{"appToken": "xapp-1"}); err == nil {
+		t.Fatal("expected error without bot token")
+	}
+	if _, err := parseConfig(map[string]any{"botToken": "xoxb-1", "appToken": "xapp-1", "apiBaseUrl": "ftp://x"}); err == nil {
+		t.Fatal("expected error for non-http base url")
+	}
+	cfg, err := parseConfig(map[string]any{"bot_token": " xoxb-1 ", "app_token": "xapp-1", "api_base_url": "http://localhost:9000/api"})
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if cfg.BotToken != "xoxb-1" || cfg.AppToken != "xapp-1" || cfg.apiBaseURL() != "http://localhost:9000/api/" {
+		t.Fatalf("unexpected config: %#v", cfg)
+	}
+	if got := (Config{}).apiBaseURL(); got != defaultAPIBaseURL {
+		t.Fatalf("unexpected default base url %q", got)
+	}
+}
+
+func TestNormalizeTarget(t *testing.T) {
+	cases := map[string]string{
+		" C123 ":          "C123",
+		"<@U123>":         "U123",
+		"<#C123|general>": "C123",
+		"C123:1712.0001":  "C123:1712.0001",
+	}
+	for input, want := range cases {
+		if got := normalizeTarget(input); got != want {
+			t.Fatalf("normalizeTarget(%q) = %q, want %q", input, got, want)
+		}
+	}
+	channelID, threadTS := splitTarget("C123:1712.0001")
+	if channelID != "C123" || threadTS != "1712.0001" {
+		t.Fatalf("unexpected split: %q %q", channelID, threadTS)
+	}
+	if joinTarget("C123", "") != "C123" || joinTarget("C123", "1.2") != "C123:1.2" {
+		t.Fatal("unexpected joinTarget result")
+	}
+}
+
+func TestBindingRoundTrip(t *testing.T) {
+	identity := channel.Identity{
+		SubjectID:  "U1",
+		Attributes: map[string]string{"user_id": "U1", "username": "Alice"},
+	}
+	cfg := buildUserConfig(identity)
+	target, err := resolveTarget(cfg)
+	if err != nil || target != "U1" {
+		t.Fatalf("unexpected target %q err %v", target, err)
+	}
+	if !matchBinding(cfg, channel.BindingCriteria{SubjectID: "U1"}) {
+		t.Fatal("expected binding to match subject")
+	}
+	if matchBinding(cfg, channel.BindingCriteria{SubjectID: "U2"}) {
+		t.Fatal("unexpected match for other subject")
+	}
+}
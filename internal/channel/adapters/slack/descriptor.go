@@ -0,0 +1,7 @@
+// Package slack implements the Slack channel adapter.
+package slack
+
+import "github.com/memohai/memoh/internal/channel"
+
+// Type is the registered ChannelType identifier for Slack.
+const Type channel.ChannelType = "slack"
@@ -0,0 +1,296 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const (
+	defaultDirectoryPageSize = 20
+	maxDirectoryPageSize     = 200
+	// maxDirectoryPages bounds the pages scanned while filtering by query.
+	maxDirectoryPages = 5
+)
+
+func directoryLimit(n int) int {
+	if n <= 0 {
+		return defaultDirectoryPageSize
+	}
+	if n > maxDirectoryPageSize {
+		return maxDirectoryPageSize
+	}
+	return n
+}
+
+// ListPeers lists workspace members, skipping deleted users and bots.
+func (a *SlackAdapter) ListPeers(ctx context.Context, cfg channel.ChannelConfig, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	api, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	limit := directoryLimit(query.Limit)
+	entries := make([]channel.DirectoryEntry, 0, limit)
+	err = listPages(ctx, api, "users.list", url.Values{}, func(body *pageBody) (bool, error) {
+		var users []slackUser
+		if err := json.Unmarshal(body.Members, &users); err != nil {
+			return false, err
+		}
+		for _, user := range users {
+			if user.Deleted || user.IsBot || user.ID == "USLACKBOT" {
+				continue
+			}
+			entry := userToEntry(user)
+			if !matchesQuery(entry, query.Query) {
+				continue
+			}
+			entries = append(entries, entry)
+			if len(entries) >= limit {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ListGroups lists public and private channels visible to the bot.
+func (a *SlackAdapter) ListGroups(ctx context.Context, cfg channel.ChannelConfig, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	api, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	limit := directoryLimit(query.Limit)
+	entries := make([]channel.DirectoryEntry, 0, limit)
+	params := url.Values{
+		"types":            {"public_channel,private_channel"},
+		"exclude_archived": {"true"},
+	}
+	err = listPages(ctx, api, "conversations.list", params, func(body *pageBody) (bool, error) {
+		for _, conv := range body.Channels {
+			entry := conversationToEntry(conv)
+			if !matchesQuery(entry, query.Query) {
+				continue
+			}
+			entries = append(entries, entry)
+			if len(entries) >= limit {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ListGroupMembers lists the members of a channel.
+func (a *SlackAdapter) ListGroupMembers(ctx context.Context, cfg channel.ChannelConfig, groupID string, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	api, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	channelID, _ := splitTarget(groupID)
+	if channelID == "" {
+		return nil, fmt.Errorf("slack list group members: empty group id")
+	}
+	limit := directoryLimit(query.Limit)
+	entries := make([]channel.DirectoryEntry, 0, limit)
+	err = listPages(ctx, api, "conversations.members", url.Values{"channel": {channelID}}, func(body *pageBody) (bool, error) {
+		var userIDs []string
+		if err := json.Unmarshal(body.Members, &userIDs); err != nil {
+			return false, err
+		}
+		for _, userID := range userIDs {
+			user, err := api.userInfo(ctx, userID)
+			if err != nil {
+				return false, err
+			}
+			if user.Deleted || user.IsBot {
+				continue
+			}
+			entry := userToEntry(user)
+			if !matchesQuery(entry, query.Query) {
+				continue
+			}
+			entries = append(entries, entry)
+			if len(entries) >= limit {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ResolveEntry resolves a user or channel from an ID, a mention such as
+// <@U123> or <#C123|general>, or a name such as @alice or #general.
+func (a *SlackAdapter) ResolveEntry(ctx context.Context, cfg channel.ChannelConfig, input string, kind channel.DirectoryEntryKind) (channel.DirectoryEntry, error) {
+	api, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return channel.DirectoryEntry{}, err
+	}
+	raw := strings.TrimSpace(input)
+	if raw == "" {
+		return channel.DirectoryEntry{}, fmt.Errorf("slack resolve entry: empty input")
+	}
+	isName := !strings.HasPrefix(raw, "<") && (strings.HasPrefix(raw, "@") || strings.HasPrefix(raw, "#"))
+	value := strings.TrimLeft(normalizeTarget(raw), "@#")
+	switch kind {
+	case channel.DirectoryEntryUser:
+		if !isName && looksLikeID(value, "UW") {
+			user, err := api.userInfo(ctx, value)
+			if err != nil {
+				return channel.DirectoryEntry{}, err
+			}
+			return userToEntry(user), nil
+		}
+		return a.findEntry(ctx, cfg, value, kind)
+	case channel.DirectoryEntryGroup:
+		channelID, _ := splitTarget(value)
+		if !isName && looksLikeID(channelID, "CGD") {
+			conv, err := api.conversationInfo(ctx, channelID)
+			if err != nil {
+				return channel.DirectoryEntry{}, err
+			}
+			return conversationToEntry(conv), nil
+		}
+		return a.findEntry(ctx, cfg, value, kind)
+	default:
+		return channel.DirectoryEntry{}, fmt.Errorf("slack resolve entry: unsupported kind %q", kind)
+	}
+}
+
+// findEntry looks a user or channel up by exact name.
+func (a *SlackAdapter) findEntry(ctx context.Context, cfg channel.ChannelConfig, name string, kind channel.DirectoryEntryKind) (channel.DirectoryEntry, error) {
+	var entries []channel.DirectoryEntry
+	var err error
+	query := channel.DirectoryQuery{Query: name, Limit: maxDirectoryPageSize}
+	if kind == channel.DirectoryEntryUser {
+		entries, err = a.ListPeers(ctx, cfg, query)
+	} else {
+		entries, err = a.ListGroups(ctx, cfg, query)
+	}
+	if err != nil {
+		return channel.DirectoryEntry{}, err
+	}
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Name, name) || strings.EqualFold(strings.TrimLeft(entry.Handle, "@#"), name) {
+			return entry, nil
+		}
+	}
+	return channel.DirectoryEntry{}, fmt.Errorf("slack resolve entry: %s %q not found", kind, name)
+}
+
+// pageBody holds the list fields of the paginated methods used here.
+// Members is a list of users for users.list and of user IDs for
+// conversations.members.
+type pageBody struct {
+	Members          json.RawMessage     `json:"members"`
+	Channels         []slackConversation `json:"channels"`
+	ResponseMetadata responseMetadata    `json:"response_metadata"`
+}
+
+// listPages calls a cursor paginated method until visit returns false or an
+// error, the last page is reached or maxDirectoryPages pages were read.
+func listPages(ctx context.Context, api *apiClient, method string, params url.Values, visit func(*pageBody) (bool, error)) error {
+	params.Set("limit", strconv.Itoa(maxDirectoryPageSize))
+	for range maxDirectoryPages {
+		var body pageBody
+		if err := api.call(ctx, method, params, &body); err != nil {
+			return err
+		}
+		more, err := visit(&body)
+		if err != nil {
+			return fmt.Errorf("slack %s: %w", method, err)
+		}
+		if !more {
+			return nil
+		}
+		cursor := strings.TrimSpace(body.ResponseMetadata.NextCursor)
+		if cursor == "" {
+			return nil
+		}
+		params.Set("cursor", cursor)
+	}
+	return nil
+}
+
+func userToEntry(user slackUser) channel.DirectoryEntry {
+	entry := channel.DirectoryEntry{
+		Kind:      channel.DirectoryEntryUser,
+		ID:        user.ID,
+		Name:      user.displayName(),
+		AvatarURL: strings.TrimSpace(user.Profile.Image72),
+		Metadata: map[string]any{
+			"user_id": user.ID,
+		},
+	}
+	if user.Name != "" {
+		entry.Handle = "@" + user.Name
+	}
+	if user.TeamID != "" {
+		entry.Metadata["team_id"] = user.TeamID
+	}
+	if user.RealName != "" {
+		entry.Metadata["real_name"] = user.RealName
+	}
+	return entry
+}
+
+func conversationToEntry(conv slackConversation) channel.DirectoryEntry {
+	entry := channel.DirectoryEntry{
+		Kind: channel.DirectoryEntryGroup,
+		ID:   conv.ID,
+		Name: conv.Name,
+		Metadata: map[string]any{
+			"channel_id": conv.ID,
+			"is_private": conv.IsPrivate,
+			"is_member":  conv.IsMember,
+		},
+	}
+	if conv.Name != "" {
+		entry.Handle = "#" + conv.Name
+	}
+	if conv.NumMembers > 0 {
+		entry.Metadata["num_members"] = conv.NumMembers
+	}
+	if topic := strings.TrimSpace(conv.Topic.Value); topic != "" {
+		entry.Metadata["topic"] = topic
+	}
+	return entry
+}
+
+func matchesQuery(entry channel.DirectoryEntry, query string) bool {
+	query = strings.ToLower(strings.TrimLeft(strings.TrimSpace(query), "@#"))
+	if query == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(entry.Name+" "+entry.Handle), query)
+}
+
+// looksLikeID reports whether value is an uppercase Slack ID starting with one
+// of the prefixes, e.g. U0123ABCD.
+func looksLikeID(value, prefixes string) bool {
+	if len(value) < 2 || !strings.ContainsRune(prefixes, rune(value[0])) {
+		return false
+	}
+	for _, r := range value {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
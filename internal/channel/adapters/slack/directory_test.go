@@ -0,0 +1,124 @@
+package slack
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestListPeersPaginatesAndFilters(t *testing.T) {
+	fake := newFakeSlack(t)
+	fake.handle("users.list", func(params url.Values) map[string]any {
+		if params.Get("cursor") == "" {
+			return map[string]any{
+				"ok": true,
+				"members": []map[string]any{
+					{"id": "U1", "name": "alice", "profile": map[string]any{"display_name": "Alice"}},
+					{"id": "U2", "name": "robot", "is_bot": true},
+				},
+				"response_metadata": map[string]any{"next_cursor": "page2"},
+			}
+		}
+		return map[string]any{
+			"ok": true,
+			"members": []map[string]any{
+				{"id": "U3", "name": "alina", "real_name": "Alina Smith", "profile": map[string]any{"image_72": "https://example.com/a.png"}},
+				{"id": "U4", "name": "bob", "deleted": true},
+			},
+		}
+	})
+	adapter := NewSlackAdapter(nil)
+	entries, err := adapter.ListPeers(context.Background(), fake.config(), channel.DirectoryQuery{Query: "ali"})
+	if err != nil {
+		t.Fatalf("list peers: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %#v", entries)
+	}
+	if entries[0].ID != "U1" || entries[0].Name != "Alice" || entries[0].Handle != "@alice" {
+		t.Fatalf("unexpected first entry: %#v", entries[0])
+	}
+	if entries[1].ID != "U3" || entries[1].Name != "Alina Smith" || entries[1].AvatarURL == "" {
+		t.Fatalf("unexpected second entry: %#v", entries[1])
+	}
+	if calls := fake.callsTo("users.list"); len(calls) != 2 || calls[1].params.Get("cursor") != "page2" {
+		t.Fatalf("unexpected users.list calls: %#v", calls)
+	}
+}
+
+func TestListGroupMembers(t *testing.T) {
+	fake := newFakeSlack(t)
+	fake.handle("conversations.members", func(params url.Values) map[string]any {
+		if params.Get("channel") != "C1" {
+			return map[string]any{"ok": false, "error": "channel_not_found"}
+		}
+		return map[string]any{"ok": true, "members": []string{"U1", "UBOT"}}
+	})
+	fake.handle("users.info", func(params url.Values) map[string]any {
+		user := map[string]any{"id": params.Get("user"), "name": "alice"}
+		if params.Get("user") == "UBOT" {
+			user["is_bot"] = true
+		}
+		return map[string]any{"ok": true, "user": user}
+	})
+	adapter := NewSlackAdapter(nil)
+	entries, err := adapter.ListGroupMembers(context.Background(), fake.config(), "C1", channel.DirectoryQuery{})
+	if err != nil {
+		t.Fatalf("list members: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != "U1" || entries[0].Kind != channel.DirectoryEntryUser {
+		t.Fatalf("unexpected members: %#v", entries)
+	}
+	if _, err := adapter.ListGroupMembers(context.Background(), fake.config(), "C9", channel.DirectoryQuery{}); err == nil {
+		t.Fatal("expected channel_not_found error")
+	}
+}
+
+func TestResolveEntry(t *testing.T) {
+	fake := newFakeSlack(t)
+	fake.handle("conversations.list", func(url.Values) map[string]any {
+		return map[string]any{
+			"ok": true,
+			"channels": []map[string]any{
+				{"id": "C1", "name": "general", "is_member": true, "num_members": 12},
+				{"id": "C2", "name": "general-chat"},
+			},
+		}
+	})
+	fake.handle("conversations.info", func(params url.Values) map[string]any {
+		return map[string]any{"ok": true, "channel": map[string]any{"id": params.Get("channel"), "name": "ops", "is_private": true}}
+	})
+	fake.handle("users.info", func(params url.Values) map[string]any {
+		return map[string]any{"ok": true, "user": map[string]any{"id": params.Get("user"), "name": "alice"}}
+	})
+	adapter := NewSlackAdapter(nil)
+	ctx := context.Background()
+	cfg := fake.config()
+
+	group, err := adapter.ResolveEntry(ctx, cfg, "#general", channel.DirectoryEntryGroup)
+	if err != nil {
+		t.Fatalf("resolve #general: %v", err)
+	}
+	if group.ID != "C1" || group.Handle != "#general" || group.Metadata["num_members"] != 12 {
+		t.Fatalf("unexpected group: %#v", group)
+	}
+	group, err = adapter.ResolveEntry(ctx, cfg, "<#C7|ops>", channel.DirectoryEntryGroup)
+	if err != nil {
+		t.Fatalf("resolve channel mention: %v", err)
+	}
+	if group.ID != "C7" || group.Metadata["is_private"] != true {
+		t.Fatalf("unexpected group: %#v", group)
+	}
+	user, err := adapter.ResolveEntry(ctx, cfg, "<@U5>", channel.DirectoryEntryUser)
+	if err != nil {
+		t.Fatalf("resolve user mention: %v", err)
+	}
+	if user.ID != "U5" || user.Handle != "@alice" {
+		t.Fatalf("unexpected user: %#v", user)
+	}
+	if _, err := adapter.ResolveEntry(ctx, cfg, "#missing", channel.DirectoryEntryGroup); err == nil {
+		t.Fatal("expected not found error")
+	}
+}
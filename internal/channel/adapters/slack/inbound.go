@@ -0,0 +1,345 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/adapters/common"
+)
+
+const (
+	inboundDedupTTL = time.Minute
+	userNameTTL     = time.Hour
+)
+
+// eventCallback is the payload of an events_api envelope.
+type eventCallback struct {
+	Type    string       `json:"type"`
+	TeamID  string       `json:"team_id"`
+	EventID string       `json:"event_id"`
+	Event   messageEvent `json:"event"`
+}
+
+// messageEvent covers the fields of the message and app_mention events.
+type messageEvent struct {
+	Type         string      `json:"type"`
+	Subtype      string      `json:"subtype"`
+	Channel      string      `json:"channel"`
+	ChannelType  string      `json:"channel_type"`
+	User         string      `json:"user"`
+	BotID        string      `json:"bot_id"`
+	Text         string      `json:"text"`
+	TS           string      `json:"ts"`
+	ThreadTS     string      `json:"thread_ts"`
+	ParentUserID string      `json:"parent_user_id"`
+	Files        []slackFile `json:"files"`
+}
+
+type slackFile struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	Title              string `json:"title"`
+	Mimetype           string `json:"mimetype"`
+	Size               int64  `json:"size"`
+	URLPrivate         string `json:"url_private"`
+	URLPrivateDownload string `json:"url_private_download"`
+	OriginalW          int    `json:"original_w"`
+	OriginalH          int    `json:"original_h"`
+}
+
+type cachedUserName struct {
+	name      string
+	expiresAt time.Time
+}
+
+// handleEvent converts an events_api payload into an inbound message and
+// dispatches it to handler.
+func (a *SlackAdapter) handleEvent(ctx context.Context, cfg channel.ChannelConfig, slackCfg Config, botUserID string, payload json.RawMessage, handler channel.InboundHandler) {
+	var callback eventCallback
+	if err := json.Unmarshal(payload, &callback); err != nil {
+		a.logger.Warn("decode event failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		return
+	}
+	if callback.Type != "event_callback" {
+		return
+	}
+	event := callback.Event
+	if event.Type != "message" && event.Type != "app_mention" {
+		return
+	}
+	if !acceptMessageEvent(event, botUserID) {
+		return
+	}
+	if a.isDuplicateInbound(slackCfg.BotToken, event.Channel+":"+event.TS) {
+		return
+	}
+	api := a.botClient(slackCfg)
+	msg, ok := a.toInboundMessage(ctx, api, slackCfg.BotToken, cfg, botUserID, callback.TeamID, event)
+	if !ok {
+		return
+	}
+	a.logger.Info(
+		"inbound received",
+		slog.String("config_id", cfg.ID),
+		slog.String("chat_type", msg.Conversation.Type),
+		slog.String("chat_id", msg.Conversation.ID),
+		slog.String("thread_id", msg.Conversation.ThreadID),
+		slog.String("user_id", msg.Sender.Attribute("user_id")),
+		slog.String("text", common.SummarizeText(msg.Message.Text)),
+		slog.Int("attachments", len(msg.Message.Attachments)),
+	)
+	go func() {
+		if err := handler(ctx, cfg, msg); err != nil {
+			a.logger.Error("handle inbound failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+	}()
+}
+
+// acceptMessageEvent drops edits, deletions, joins and other system subtypes
+// as well as messages from bots, including this one.
+func acceptMessageEvent(event messageEvent, botUserID string) bool {
+	switch event.Subtype {
+	case "", "file_share", "thread_broadcast":
+	default:
+		return false
+	}
+	if event.BotID != "" || event.User == "" || event.User == botUserID {
+		return false
+	}
+	return event.Channel != "" && event.TS != ""
+}
+
+func (a *SlackAdapter) toInboundMessage(
+	ctx context.Context,
+	api *apiClient,
+	token string,
+	cfg channel.ChannelConfig,
+	botUserID string,
+	teamID string,
+	event messageEvent,
+) (channel.InboundMessage, bool) {
+	text := strings.TrimSpace(plainSlackText(event.Text))
+	attachments := collectAttachments(event.Files)
+	if text == "" && len(attachments) == 0 {
+		return channel.InboundMessage{}, false
+	}
+	chatType := conversationType(event)
+	var thread *channel.ThreadRef
+	var reply *channel.ReplyRef
+	if event.ThreadTS != "" {
+		thread = &channel.ThreadRef{ID: event.ThreadTS}
+		if event.ThreadTS != event.TS {
+			reply = &channel.ReplyRef{Target: event.Channel, MessageID: event.ThreadTS}
+		}
+	}
+	isMentioned := event.Type == "app_mention" || isSlackBotMentioned(event.Text, botUserID)
+	isReplyToBot := botUserID != "" && event.ParentUserID == botUserID
+	displayName := a.lookupUserName(ctx, api, token, event.User)
+	attrs := map[string]string{
+		"user_id": event.User,
+	}
+	if displayName != event.User {
+		attrs["username"] = displayName
+	}
+	if teamID != "" {
+		attrs["team_id"] = teamID
+	}
+	return channel.InboundMessage{
+		Channel: Type,
+		Message: channel.Message{
+			ID:          event.TS,
+			Format:      channel.MessageFormatPlain,
+			Text:        text,
+			Attachments: attachments,
+			Thread:      thread,
+			Reply:       reply,
+		},
+		BotID:       cfg.BotID,
+		ReplyTarget: joinTarget(event.Channel, event.ThreadTS),
+		Sender: channel.Identity{
+			SubjectID:   event.User,
+			DisplayName: displayName,
+			Attributes:  attrs,
+		},
+		Conversation: channel.Conversation{
+			ID:       event.Channel,
+			Type:     chatType,
+			ThreadID: event.ThreadTS,
+		},
+		ReceivedAt: slackTimestamp(event.TS),
+		Source:     "slack",
+		Metadata: map[string]any{
+			"team_id":         teamID,
+			"is_mentioned":    isMentioned,
+			"is_reply_to_bot": isReplyToBot,
+		},
+	}, true
+}
+
+// conversationType maps Slack channel types to the direct/group conversation
+// types the router understands. app_mention events carry no channel_type, so
+// the channel ID prefix is used instead.
+func conversationType(event messageEvent) string {
+	switch event.ChannelType {
+	case "im":
+		return "private"
+	case "channel", "group", "mpim":
+		return "group"
+	}
+	if isDirectChannel(event.Channel) {
+		return "private"
+	}
+	return "group"
+}
+
+// isSlackBotMentioned reports whether text contains <@BOT> or <@BOT|name>.
+func isSlackBotMentioned(text, botUserID string) bool {
+	if botUserID == "" {
+		return false
+	}
+	return strings.Contains(text, "<@"+botUserID+">") || strings.Contains(text, "<@"+botUserID+"|")
+}
+
+// collectAttachments maps shared files to attachments. The private URLs need
+// the bot token, so files are fetched through ResolveAttachment by ID.
+func collectAttachments(files []slackFile) []channel.Attachment {
+	if len(files) == 0 {
+		return nil
+	}
+	attachments := make([]channel.Attachment, 0, len(files))
+	for _, file := range files {
+		if file.ID == "" {
+			continue
+		}
+		downloadURL := file.URLPrivateDownload
+		if downloadURL == "" {
+			downloadURL = file.URLPrivate
+		}
+		name := file.Name
+		if name == "" {
+			name = file.Title
+		}
+		attachments = append(attachments, channel.Attachment{
+			Type:           attachmentType(file.Mimetype),
+			PlatformKey:    file.ID,
+			SourcePlatform: Type.String(),
+			Name:           name,
+			Size:           file.Size,
+			Mime:           file.Mimetype,
+			Width:          file.OriginalW,
+			Height:         file.OriginalH,
+			Metadata: map[string]any{
+				"url_private_download": downloadURL,
+			},
+		})
+	}
+	return attachments
+}
+
+func attachmentType(mime string) channel.AttachmentType {
+	switch {
+	case mime == "image/gif":
+		return channel.AttachmentGIF
+	case strings.HasPrefix(mime, "image/"):
+		return channel.AttachmentImage
+	case strings.HasPrefix(mime, "audio/"):
+		return channel.AttachmentAudio
+	case strings.HasPrefix(mime, "video/"):
+		return channel.AttachmentVideo
+	default:
+		return channel.AttachmentFile
+	}
+}
+
+// slackTimestamp parses a message ts such as "1712345678.000100".
+func slackTimestamp(ts string) time.Time {
+	seconds, frac, _ := strings.Cut(ts, ".")
+	sec, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Now().UTC()
+	}
+	micros, _ := strconv.ParseInt(frac, 10, 64)
+	return time.Unix(sec, micros*int64(time.Microsecond)).UTC()
+}
+
+var reSlackToken = regexp.MustCompile(`<([^<>]+)>`)
+
+// plainSlackText rewrites Slack's escaped message markup into plain text:
+// links become their URL or "label (url)", channel references become #name
+// and special mentions such as <!here> become @here. User mentions are kept
+// as <@U123> so replies can mention them back.
+func plainSlackText(text string) string {
+	text = reSlackToken.ReplaceAllStringFunc(text, func(token string) string {
+		inner := token[1 : len(token)-1]
+		target, label, _ := strings.Cut(inner, "|")
+		switch {
+		case strings.HasPrefix(target, "@"):
+			return "<" + target + ">"
+		case strings.HasPrefix(target, "#"):
+			if label != "" {
+				return "#" + label
+			}
+			return target
+		case strings.HasPrefix(target, "!"):
+			name := strings.TrimPrefix(target, "!")
+			if label != "" {
+				return label
+			}
+			if idx := strings.Index(name, "^"); idx >= 0 {
+				name = name[:idx]
+			}
+			return "@" + name
+		}
+		if label == "" || strings.HasSuffix(target, label) {
+			return target
+		}
+		return label + " (" + target + ")"
+	})
+	return strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&").Replace(text)
+}
+
+func (a *SlackAdapter) isDuplicateInbound(token, key string) bool {
+	now := time.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for seen, at := range a.seenMessages {
+		if now.Sub(at) > inboundDedupTTL {
+			delete(a.seenMessages, seen)
+		}
+	}
+	key = token + ":" + key
+	if _, ok := a.seenMessages[key]; ok {
+		return true
+	}
+	a.seenMessages[key] = now
+	return false
+}
+
+// lookupUserName resolves a user's display name through users.info, caching
+// results per token. It falls back to the user ID.
+func (a *SlackAdapter) lookupUserName(ctx context.Context, api *apiClient, token, userID string) string {
+	key := token + ":" + userID
+	now := time.Now()
+	a.mu.Lock()
+	cached, ok := a.userNames[key]
+	a.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.name
+	}
+	user, err := api.userInfo(ctx, userID)
+	if err != nil {
+		a.logger.Debug("lookup user failed", slog.String("user_id", userID), slog.Any("error", err))
+		return userID
+	}
+	name := user.displayName()
+	a.mu.Lock()
+	a.userNames[key] = cachedUserName{name: name, expiresAt: now.Add(userNameTTL)}
+	a.mu.Unlock()
+	return name
+}
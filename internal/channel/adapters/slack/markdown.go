@@ -0,0 +1,125 @@
+package slack
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	inlineCodePlaceholder = "\x00IC"
+	boldMarker            = "\x00B"
+)
+
+var (
+	reInlineCode = regexp.MustCompile("`([^`\\n]+?)`")
+	reBold       = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	reStrike     = regexp.MustCompile(`~~(.+?)~~`)
+	reLink       = regexp.MustCompile(`\[([^\]]+?)\]\(([^)\s]+?)\)`)
+	reHeading    = regexp.MustCompile(`(?m)^#{1,6}\s+(.+)$`)
+	reListBullet = regexp.MustCompile(`(?m)^(\s*)[-+*]\s`)
+	reItalic     = regexp.MustCompile(`\*([^*\n]+?)\*`)
+	reQuote      = regexp.MustCompile(`(?m)^&gt; ?`)
+)
+
+// markdownToMrkdwn converts standard markdown to Slack mrkdwn.
+//
+// Supported conversions:
+//   - Fenced code blocks keep their fences, without the language tag
+//   - Bold (**text**, __text__) → *text*
+//   - Italic (*text*) → _text_
+//   - Strikethrough (~~text~~) → ~text~
+//   - Links ([text](url)) → <url|text>
+//   - Headings (# text) → bold line
+//   - Unordered lists (- item) → bullet
+//
+// &, < and > are escaped everywhere as Slack requires.
+func markdownToMrkdwn(text string) string {
+	if strings.TrimSpace(text) == "" {
+		return text
+	}
+	segments := strings.Split(text, "```")
+	if len(segments)%2 == 0 {
+		// Unclosed fence: keep the remainder as normal text.
+		last := len(segments) - 1
+		segments[last-1] += "```" + segments[last]
+		segments = segments[:last]
+	}
+	var buf strings.Builder
+	for i, seg := range segments {
+		if i%2 == 0 {
+			buf.WriteString(convertInlineMarkdown(seg))
+			continue
+		}
+		buf.WriteString("```")
+		buf.WriteString(escapeMrkdwn(strings.Trim(stripCodeBlockLang(seg), "\n")))
+		buf.WriteString("```")
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// stripCodeBlockLang drops a language tag on the opening fence line.
+func stripCodeBlockLang(block string) string {
+	idx := strings.IndexByte(block, '\n')
+	if idx < 0 {
+		return block
+	}
+	firstLine := strings.TrimSpace(block[:idx])
+	if firstLine != "" && !strings.Contains(firstLine, " ") && len(firstLine) <= 20 {
+		return block[idx+1:]
+	}
+	return block
+}
+
+func convertInlineMarkdown(text string) string {
+	if strings.TrimSpace(text) == "" {
+		return text
+	}
+
+	// Protect inline code spans from further processing.
+	var inlineCodes []string
+	text = reInlineCode.ReplaceAllStringFunc(text, func(match string) string {
+		idx := len(inlineCodes)
+		inlineCodes = append(inlineCodes, reInlineCode.FindStringSubmatch(match)[1])
+		return fmt.Sprintf("%s%d\x00", inlineCodePlaceholder, idx)
+	})
+
+	text = escapeMrkdwn(text)
+
+	// Block quotes use a literal ">" at the start of a line.
+	text = reQuote.ReplaceAllString(text, "> ")
+
+	text = reLink.ReplaceAllString(text, "<$2|$1>")
+
+	// Bold becomes a marker first so the italic pass does not consume it.
+	text = reBold.ReplaceAllStringFunc(text, func(match string) string {
+		sub := reBold.FindStringSubmatch(match)
+		inner := sub[1]
+		if inner == "" {
+			inner = sub[2]
+		}
+		return boldMarker + inner + boldMarker
+	})
+	text = reHeading.ReplaceAllStringFunc(text, func(match string) string {
+		inner := strings.TrimSpace(reHeading.FindStringSubmatch(match)[1])
+		return boldMarker + strings.ReplaceAll(inner, boldMarker, "") + boldMarker
+	})
+	text = reListBullet.ReplaceAllString(text, "${1}• ")
+	text = reItalic.ReplaceAllString(text, "_${1}_")
+	text = reStrike.ReplaceAllString(text, "~$1~")
+	text = strings.ReplaceAll(text, boldMarker, "*")
+
+	for i, code := range inlineCodes {
+		placeholder := fmt.Sprintf("%s%d\x00", inlineCodePlaceholder, i)
+		text = strings.Replace(text, placeholder, "`"+escapeMrkdwn(code)+"`", 1)
+	}
+	return text
+}
+
+// escapeMrkdwn escapes the characters Slack treats as markup delimiters.
+func escapeMrkdwn(text string) string {
+	text = strings.ReplaceAll(text, "&", "&amp;")
+	text = strings.ReplaceAll(text, "<", "&lt;")
+	text = strings.ReplaceAll(text, ">", "&gt;")
+	return text
+}
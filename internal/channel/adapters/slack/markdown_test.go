@@ -0,0 +1,68 @@
+package slack
+
+import "testing"
+
+func TestMarkdownToMrkdwn(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "bold", input: "this is **bold** text", want: "this is *bold* text"},
+		{name: "italic", input: "this is *italic* text", want: "this is _italic_ text"},
+		{name: "bold and italic", input: "**bold** and *italic*", want: "*bold* and _italic_"},
+		{name: "strikethrough", input: "~~gone~~", want: "~gone~"},
+		{name: "link", input: "see [docs](https://example.com/a?b=1&c=2)", want: "see <https://example.com/a?b=1&amp;c=2|docs>"},
+		{name: "heading", input: "## Plan **now**", want: "*Plan now*"},
+		{name: "bullets", input: "- one\n* two", want: "• one\n• two"},
+		{name: "escapes", input: "a < b && c > d", want: "a &lt; b &amp;&amp; c &gt; d"},
+		{name: "quote", input: "> quoted", want: "> quoted"},
+		{name: "inline code", input: "run `a **b** <c>`", want: "run `a **b** &lt;c&gt;`"},
+		{name: "code block", input: "```go\nx := **y**\n```", want: "```x := **y**```"},
+		{name: "unclosed fence", input: "```go **x**", want: "```go *x*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownToMrkdwn(tt.input); got != tt.want {
+				t.Fatalf("markdownToMrkdwn(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainSlackText(t *testing.T) {
+	tests := map[string]string{
+		"<@U1> hi":                          "<@U1> hi",
+		"<@U1|alice> hi":                    "<@U1> hi",
+		"join <#C1|general>":                "join #general",
+		"<!here> look":                      "@here look",
+		"<https://example.com>":             "https://example.com",
+		"<http://example.com|example.com>":  "http://example.com",
+		"<https://example.com/x|the docs>":  "the docs (https://example.com/x)",
+		"a &lt; b &amp;&amp; c &gt; d":      "a < b && c > d",
+		"<!subteam^S1|@oncall> please help": "@oncall please help",
+	}
+	for input, want := range tests {
+		if got := plainSlackText(input); got != want {
+			t.Fatalf("plainSlackText(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestSlackEmojiName(t *testing.T) {
+	cases := map[string]string{
+		":thumbsup:": "thumbsup",
+		"eyes":       "eyes",
+		"👀":          "eyes",
+		"✅":          "white_check_mark",
+	}
+	for input, want := range cases {
+		got, err := slackEmojiName(input)
+		if err != nil || got != want {
+			t.Fatalf("slackEmojiName(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := slackEmojiName("🦄"); err == nil {
+		t.Fatal("expected error for unknown unicode emoji")
+	}
+}
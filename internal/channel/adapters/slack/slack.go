@@ -0,0 +1,505 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/media"
+)
+
+// slackMaxMessageLength is the longest text chat.postMessage accepts.
+const slackMaxMessageLength = 40000
+
+// slackTextChunkLimit keeps sent messages at the length Slack recommends.
+const slackTextChunkLimit = 4000
+
+// processingReaction marks a message the bot is working on.
+const processingReaction = "eyes"
+
+// SlackAdapter implements the Slack channel using Socket Mode for inbound
+// events and the Web API for everything else.
+type SlackAdapter struct {
+	logger       *slog.Logger
+	httpClient   *http.Client
+	dialer       *websocket.Dialer
+	mu           sync.Mutex
+	seenMessages map[string]time.Time      // keyed by token:channel:ts
+	userNames    map[string]cachedUserName // keyed by token:user_id
+}
+
+// NewSlackAdapter creates a SlackAdapter with the given logger.
+func NewSlackAdapter(log *slog.Logger) *SlackAdapter {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &SlackAdapter{
+		logger:       log.With(slog.String("adapter", "slack")),
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		dialer:       websocket.DefaultDialer,
+		seenMessages: make(map[string]time.Time),
+		userNames:    make(map[string]cachedUserName),
+	}
+}
+
+func (a *SlackAdapter) botClient(cfg Config) *apiClient {
+	return newAPIClient(a.httpClient, cfg.apiBaseURL(), cfg.BotToken)
+}
+
+func (a *SlackAdapter) clientFor(credentials map[string]any) (*apiClient, error) {
+	slackCfg, err := parseConfig(credentials)
+	if err != nil {
+		return nil, err
+	}
+	return a.botClient(slackCfg), nil
+}
+
+// Type returns the Slack channel type.
+func (a *SlackAdapter) Type() channel.ChannelType {
+	return Type
+}
+
+// Descriptor returns the Slack channel metadata.
+func (a *SlackAdapter) Descriptor() channel.Descriptor {
+	return channel.Descriptor{
+		Type:        Type,
+		DisplayName: "Slack",
+		Capabilities: channel.ChannelCapabilities{
+			Text:      true,
+			Markdown:  true,
+			Reply:     true,
+			Threads:   true,
+			Streaming: true,
+			Edit:      true,
+			Unsend:    true,
+			Reactions: true,
+		},
+		OutboundPolicy: channel.OutboundPolicy{
+			TextChunkLimit: slackTextChunkLimit,
+		},
+		ConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"botToken": {
+					Type:        channel.FieldSecret,
+					Required:    true,
+					Title:       "Bot Token",
+					Description: "Bot user OAuth token (xoxb-...)",
+				},
+				"appToken": {
+					Type:        channel.FieldSecret,
+					Required:    true,
+					Title:       "App Token",
+					Description: "App-level token with connections:write for Socket Mode (xapp-...)",
+				},
+				"apiBaseUrl": {
+					Type:        channel.FieldString,
+					Title:       "API Base URL",
+					Description: "Override the Slack Web API endpoint",
+					Example:     defaultAPIBaseURL,
+				},
+			},
+		},
+		UserConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"user_id":    {Type: channel.FieldString},
+				"channel_id": {Type: channel.FieldString},
+				"username":   {Type: channel.FieldString},
+			},
+		},
+		TargetSpec: channel.TargetSpec{
+			Format: "channel_id[:thread_ts] | user_id",
+			Hints: []channel.TargetHint{
+				{Label: "Channel ID", Example: "C0123456789"},
+				{Label: "Thread", Example: "C0123456789:1712345678.000100"},
+				{Label: "User ID", Example: "U0123456789"},
+			},
+		},
+	}
+}
+
+// NormalizeConfig validates and normalizes a Slack channel configuration map.
+func (a *SlackAdapter) NormalizeConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeConfig(raw)
+}
+
+// NormalizeUserConfig validates and normalizes a Slack user-binding configuration map.
+func (a *SlackAdapter) NormalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeUserConfig(raw)
+}
+
+// NormalizeTarget normalizes a Slack delivery target string.
+func (a *SlackAdapter) NormalizeTarget(raw string) string {
+	return normalizeTarget(raw)
+}
+
+// ResolveTarget derives a delivery target from a Slack user-binding configuration.
+func (a *SlackAdapter) ResolveTarget(userConfig map[string]any) (string, error) {
+	return resolveTarget(userConfig)
+}
+
+// MatchBinding reports whether a Slack user binding matches the given criteria.
+func (a *SlackAdapter) MatchBinding(config map[string]any, criteria channel.BindingCriteria) bool {
+	return matchBinding(config, criteria)
+}
+
+// BuildUserConfig constructs a Slack user-binding config from an Identity.
+func (a *SlackAdapter) BuildUserConfig(identity channel.Identity) map[string]any {
+	return buildUserConfig(identity)
+}
+
+// Connect opens a Socket Mode connection and forwards message and app_mention
+// events to the handler.
+func (a *SlackAdapter) Connect(ctx context.Context, cfg channel.ChannelConfig, handler channel.InboundHandler) (channel.Connection, error) {
+	a.logger.Info("start", slog.String("config_id", cfg.ID))
+	slackCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		a.logger.Error("decode config failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		return nil, err
+	}
+	self, err := a.botClient(slackCfg).authTest(ctx)
+	if err != nil {
+		a.logger.Error("auth test failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		return nil, err
+	}
+	conn, err := a.openSocket(ctx, slackCfg)
+	if err != nil {
+		a.logger.Error("open socket failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		return nil, err
+	}
+	connCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.runSocket(connCtx, cfg.ID, slackCfg, conn, func(payload json.RawMessage) {
+			a.handleEvent(connCtx, cfg, slackCfg, self.UserID, payload, handler)
+		})
+	}()
+	stop := func(stopCtx context.Context) error {
+		a.logger.Info("stop", slog.String("config_id", cfg.ID))
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-stopCtx.Done():
+			return stopCtx.Err()
+		}
+	}
+	return channel.NewConnection(cfg, stop), nil
+}
+
+// Send posts an outbound message with chat.postMessage.
+func (a *SlackAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, msg channel.OutboundMessage) error {
+	api, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	channelID, threadTS := resolveOutboundThread(msg.Target, msg.Message)
+	if channelID == "" {
+		return fmt.Errorf("slack target is required")
+	}
+	text := formatSlackText(msg.Message)
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("message is required")
+	}
+	_, err = api.postMessage(ctx, channelID, threadTS, truncateSlackText(text))
+	return err
+}
+
+// resolveOutboundThread returns the channel and thread to post a message to.
+// An explicit thread on the message wins over one in the target. Replies
+// outside direct messages go into the replied message's thread, which is how
+// Slack threads conversations.
+func resolveOutboundThread(target string, msg channel.Message) (string, string) {
+	channelID, threadTS := splitTarget(target)
+	if msg.Thread != nil && strings.TrimSpace(msg.Thread.ID) != "" {
+		threadTS = strings.TrimSpace(msg.Thread.ID)
+	}
+	if threadTS == "" && msg.Reply != nil && !isDirectChannel(channelID) {
+		threadTS = strings.TrimSpace(msg.Reply.MessageID)
+	}
+	return channelID, threadTS
+}
+
+// formatSlackText renders the message text, converting markdown to mrkdwn.
+func formatSlackText(msg channel.Message) string {
+	text := strings.TrimSpace(msg.PlainText())
+	if msg.Format == channel.MessageFormatMarkdown {
+		return markdownToMrkdwn(text)
+	}
+	return text
+}
+
+func truncateSlackText(text string) string {
+	runes := []rune(text)
+	if len(runes) <= slackMaxMessageLength {
+		return text
+	}
+	return string(runes[:slackMaxMessageLength-3]) + "..."
+}
+
+// OpenStream opens a stream that posts one message and keeps it updated with
+// chat.update as deltas arrive.
+func (a *SlackAdapter) OpenStream(ctx context.Context, cfg channel.ChannelConfig, target string, opts channel.StreamOptions) (channel.OutboundStream, error) {
+	api, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	channelID, threadTS := resolveOutboundThread(target, channel.Message{Reply: opts.Reply})
+	if channelID == "" {
+		return nil, fmt.Errorf("slack target is required")
+	}
+	return &slackOutboundStream{
+		adapter:   a,
+		cfg:       cfg,
+		api:       api,
+		channelID: channelID,
+		threadTS:  threadTS,
+	}, nil
+}
+
+// Update replaces the text of a message the bot sent.
+func (a *SlackAdapter) Update(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string, msg channel.Message) error {
+	api, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	channelID, _ := splitTarget(target)
+	text := formatSlackText(msg)
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("message is required")
+	}
+	return api.updateMessage(ctx, channelID, strings.TrimSpace(messageID), truncateSlackText(text))
+}
+
+// Unsend deletes a message the bot sent.
+func (a *SlackAdapter) Unsend(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string) error {
+	api, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	channelID, _ := splitTarget(target)
+	return api.deleteMessage(ctx, channelID, strings.TrimSpace(messageID))
+}
+
+// React adds an emoji reaction to a message. The emoji may be a Slack name
+// such as "thumbsup" or ":thumbsup:" or one of the common unicode emoji.
+func (a *SlackAdapter) React(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string, emoji string) error {
+	api, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	name, err := slackEmojiName(emoji)
+	if err != nil {
+		return err
+	}
+	channelID, _ := splitTarget(target)
+	err = api.addReaction(ctx, channelID, strings.TrimSpace(messageID), name)
+	if isAPIError(err, "already_reacted") {
+		return nil
+	}
+	return err
+}
+
+// Unreact removes the bot's emoji reaction from a message.
+func (a *SlackAdapter) Unreact(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string, emoji string) error {
+	api, err := a.clientFor(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	name, err := slackEmojiName(emoji)
+	if err != nil {
+		return err
+	}
+	channelID, _ := splitTarget(target)
+	err = api.removeReaction(ctx, channelID, strings.TrimSpace(messageID), name)
+	if isAPIError(err, "no_reaction") {
+		return nil
+	}
+	return err
+}
+
+// ProcessingStarted marks the source message with an :eyes: reaction.
+func (a *SlackAdapter) ProcessingStarted(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, info channel.ProcessingStatusInfo) (channel.ProcessingStatusHandle, error) {
+	channelID, messageID := processingStatusTarget(msg, info)
+	if channelID == "" || messageID == "" {
+		return channel.ProcessingStatusHandle{}, nil
+	}
+	if err := a.React(ctx, cfg, channelID, messageID, processingReaction); err != nil {
+		return channel.ProcessingStatusHandle{}, err
+	}
+	return channel.ProcessingStatusHandle{Token: processingReaction}, nil
+}
+
+// ProcessingCompleted removes the processing reaction before output is sent.
+func (a *SlackAdapter) ProcessingCompleted(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, info channel.ProcessingStatusInfo, handle channel.ProcessingStatusHandle) error {
+	channelID, messageID := processingStatusTarget(msg, info)
+	if channelID == "" || messageID == "" || strings.TrimSpace(handle.Token) == "" {
+		return nil
+	}
+	return a.Unreact(ctx, cfg, channelID, messageID, handle.Token)
+}
+
+// ProcessingFailed removes the processing reaction when chat processing fails.
+func (a *SlackAdapter) ProcessingFailed(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, info channel.ProcessingStatusInfo, handle channel.ProcessingStatusHandle, cause error) error {
+	return a.ProcessingCompleted(ctx, cfg, msg, info, handle)
+}
+
+func processingStatusTarget(msg channel.InboundMessage, info channel.ProcessingStatusInfo) (string, string) {
+	channelID := strings.TrimSpace(msg.Conversation.ID)
+	if channelID == "" {
+		channelID, _ = splitTarget(info.ReplyTarget)
+	}
+	return channelID, strings.TrimSpace(info.SourceMessageID)
+}
+
+// DiscoverSelf retrieves the bot user behind the bot token with auth.test.
+func (a *SlackAdapter) DiscoverSelf(ctx context.Context, credentials map[string]any) (map[string]any, string, error) {
+	api, err := a.clientFor(credentials)
+	if err != nil {
+		return nil, "", err
+	}
+	self, err := api.authTest(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("slack discover self: %w", err)
+	}
+	userID := strings.TrimSpace(self.UserID)
+	if userID == "" {
+		return nil, "", fmt.Errorf("slack discover self: empty user_id")
+	}
+	identity := map[string]any{
+		"user_id": userID,
+	}
+	for key, value := range map[string]string{
+		"name":    self.User,
+		"bot_id":  self.BotID,
+		"team_id": self.TeamID,
+		"team":    self.Team,
+	} {
+		if value = strings.TrimSpace(value); value != "" {
+			identity[key] = value
+		}
+	}
+	return identity, userID, nil
+}
+
+// ResolveAttachment downloads a shared file with the bot token. The URL comes
+// from the attachment metadata or, failing that, from files.info.
+func (a *SlackAdapter) ResolveAttachment(ctx context.Context, cfg channel.ChannelConfig, attachment channel.Attachment) (channel.AttachmentPayload, error) {
+	slackCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return channel.AttachmentPayload{}, err
+	}
+	downloadURL := strings.TrimSpace(channel.ReadString(attachment.Metadata, "url_private_download"))
+	if downloadURL == "" {
+		fileID := strings.TrimSpace(attachment.PlatformKey)
+		if fileID == "" {
+			return channel.AttachmentPayload{}, fmt.Errorf("slack attachment requires platform_key")
+		}
+		var resp struct {
+			File slackFile `json:"file"`
+		}
+		if err := a.botClient(slackCfg).call(ctx, "files.info", url.Values{"file": {fileID}}, &resp); err != nil {
+			return channel.AttachmentPayload{}, err
+		}
+		downloadURL = resp.File.URLPrivateDownload
+		if downloadURL == "" {
+			downloadURL = resp.File.URLPrivate
+		}
+		if downloadURL == "" {
+			return channel.AttachmentPayload{}, fmt.Errorf("slack file %s has no download url", fileID)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return channel.AttachmentPayload{}, fmt.Errorf("build download request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+slackCfg.BotToken)
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return channel.AttachmentPayload{}, fmt.Errorf("download attachment: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return channel.AttachmentPayload{}, fmt.Errorf("download attachment status: %d", resp.StatusCode)
+	}
+	maxBytes := media.MaxAssetBytes
+	if resp.ContentLength > maxBytes {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return channel.AttachmentPayload{}, fmt.Errorf("%w: max %d bytes", media.ErrAssetTooLarge, maxBytes)
+	}
+	mime := strings.TrimSpace(attachment.Mime)
+	if mime == "" {
+		mime = strings.TrimSpace(resp.Header.Get("Content-Type"))
+		if idx := strings.Index(mime, ";"); idx >= 0 {
+			mime = strings.TrimSpace(mime[:idx])
+		}
+	}
+	size := attachment.Size
+	if size <= 0 && resp.ContentLength > 0 {
+		size = resp.ContentLength
+	}
+	return channel.AttachmentPayload{
+		Reader: resp.Body,
+		Mime:   mime,
+		Name:   strings.TrimSpace(attachment.Name),
+		Size:   size,
+	}, nil
+}
+
+// unicodeEmojiNames maps common unicode emoji to their Slack names.
+var unicodeEmojiNames = map[string]string{
+	"👍":  "+1",
+	"👎":  "-1",
+	"👀":  "eyes",
+	"✅":  "white_check_mark",
+	"❌":  "x",
+	"❤️": "heart",
+	"❤":  "heart",
+	"🎉":  "tada",
+	"😂":  "joy",
+	"😄":  "smile",
+	"🙏":  "pray",
+	"🔥":  "fire",
+	"🚀":  "rocket",
+	"👌":  "ok_hand",
+	"🤔":  "thinking_face",
+	"👏":  "clap",
+	"💯":  "100",
+}
+
+// slackEmojiName converts an emoji into the reaction name Slack expects.
+func slackEmojiName(emoji string) (string, error) {
+	value := strings.TrimSpace(emoji)
+	if name, ok := unicodeEmojiNames[value]; ok {
+		return name, nil
+	}
+	value = strings.Trim(value, ":")
+	if value == "" {
+		return "", fmt.Errorf("slack reaction emoji is required")
+	}
+	for _, r := range value {
+		if r > 0x7f {
+			return "", fmt.Errorf("slack reaction emoji %q has no known name", emoji)
+		}
+	}
+	return value, nil
+}
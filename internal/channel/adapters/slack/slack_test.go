@@ -0,0 +1,419 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+type fakeCall struct {
+	method string
+	token  string
+	params url.Values
+}
+
+// fakeSlack serves the Web API under /api/ and a Socket Mode endpoint under
+// /socket.
+type fakeSlack struct {
+	server   *httptest.Server
+	mu       sync.Mutex
+	calls    []fakeCall
+	handlers map[string]func(url.Values) map[string]any
+	sockets  chan *websocket.Conn
+}
+
+func newFakeSlack(t *testing.T) *fakeSlack {
+	t.Helper()
+	f := &fakeSlack{
+		handlers: map[string]func(url.Values) map[string]any{},
+		sockets:  make(chan *websocket.Conn, 4),
+	}
+	upgrader := websocket.Upgrader{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		method := strings.TrimPrefix(r.URL.Path, "/api/")
+		f.mu.Lock()
+		f.calls = append(f.calls, fakeCall{
+			method: method,
+			token:  strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
+			params: r.PostForm,
+		})
+		handler := f.handlers[method]
+		f.mu.Unlock()
+		resp := map[string]any{"ok": true}
+		if handler != nil {
+			resp = handler(r.PostForm)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("/socket", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		f.sockets <- conn
+	})
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	f.handle("apps.connections.open", func(url.Values) map[string]any {
+		return map[string]any{"ok": true, "url": "ws" + strings.TrimPrefix(f.server.URL, "http") + "/socket"}
+	})
+	f.handle("auth.test", func(url.Values) map[string]any {
+		return map[string]any{"ok": true, "user_id": "UBOT", "user": "memoh", "bot_id": "BBOT", "team_id": "T1", "team": "Acme"}
+	})
+	return f
+}
+
+func (f *fakeSlack) handle(method string, handler func(url.Values) map[string]any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[method] = handler
+}
+
+func (f *fakeSlack) callsTo(method string) []fakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []fakeCall
+	for _, call := range f.calls {
+		if call.method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func (f *fakeSlack) config() channel.ChannelConfig {
+	return channel.ChannelConfig{
+		ID:          "cfg-1",
+		BotID:       "bot-1",
+		ChannelType: Type,
+		Credentials: map[string]any{
+			"botToken":   "xoxb-test",
+			"appToken":   "xapp-test",
+			"apiBaseUrl": f.server.URL + "/api/",
+		},
+	}
+}
+
+func eventEnvelope(envelopeID string, event map[string]any) map[string]any {
+	return map[string]any{
+		"type":        "events_api",
+		"envelope_id": envelopeID,
+		"payload": map[string]any{
+			"type":     "event_callback",
+			"team_id":  "T1",
+			"event_id": "Ev" + envelopeID,
+			"event":    event,
+		},
+	}
+}
+
+func TestConnectDeliversThreadMention(t *testing.T) {
+	fake := newFakeSlack(t)
+	fake.handle("users.info", func(params url.Values) map[string]any {
+		return map[string]any{"ok": true, "user": map[string]any{"id": params.Get("user"), "name": "alice", "profile": map[string]any{"display_name": "Alice"}}}
+	})
+	adapter := NewSlackAdapter(nil)
+	received := make(chan channel.InboundMessage, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	conn, err := adapter.Connect(ctx, fake.config(), func(_ context.Context, _ channel.ChannelConfig, msg channel.InboundMessage) error {
+		received <- msg
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() {
+		_ = conn.Stop(context.Background())
+	}()
+
+	var ws *websocket.Conn
+	select {
+	case ws = <-fake.sockets:
+	case <-time.After(5 * time.Second):
+		t.Fatal("socket mode connection not opened")
+	}
+	defer func() {
+		_ = ws.Close()
+	}()
+	mention := map[string]any{
+		"type":           "message",
+		"channel":        "C1",
+		"channel_type":   "channel",
+		"user":           "U1",
+		"text":           "<@UBOT> ship it &amp; tell <#C2|ops>",
+		"ts":             "1712345678.000200",
+		"thread_ts":      "1712345678.000100",
+		"parent_user_id": "UBOT",
+	}
+	frames := []map[string]any{
+		{"type": "hello"},
+		eventEnvelope("e1", mention),
+		// The app_mention for the same message must be dropped as a duplicate.
+		eventEnvelope("e2", map[string]any{"type": "app_mention", "channel": "C1", "user": "U1", "text": "<@UBOT> ship it", "ts": "1712345678.000200"}),
+		// Messages from bots are ignored.
+		eventEnvelope("e3", map[string]any{"type": "message", "channel": "C1", "user": "U2", "bot_id": "B2", "text": "beep", "ts": "1712345679.000100"}),
+		eventEnvelope("e4", map[string]any{"type": "message", "channel": "D1", "channel_type": "im", "user": "U1", "text": "hello", "ts": "1712345680.000100"}),
+	}
+	for _, frame := range frames {
+		if err := ws.WriteJSON(frame); err != nil {
+			t.Fatalf("write frame: %v", err)
+		}
+	}
+	acks := map[string]bool{}
+	_ = ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for len(acks) < 4 {
+		var ack socketAck
+		if err := ws.ReadJSON(&ack); err != nil {
+			t.Fatalf("read ack: %v", err)
+		}
+		acks[ack.EnvelopeID] = true
+	}
+
+	first := waitInbound(t, received)
+	second := waitInbound(t, received)
+	if first.Message.ID != "1712345678.000200" {
+		first, second = second, first
+	}
+	if first.Message.Text != "<@UBOT> ship it & tell #ops" {
+		t.Fatalf("unexpected text: %q", first.Message.Text)
+	}
+	if first.Message.Thread == nil || first.Message.Thread.ID != "1712345678.000100" {
+		t.Fatalf("expected thread ref, got %#v", first.Message.Thread)
+	}
+	if first.ReplyTarget != "C1:1712345678.000100" || first.Conversation.ThreadID != "1712345678.000100" {
+		t.Fatalf("unexpected reply target %q thread %q", first.ReplyTarget, first.Conversation.ThreadID)
+	}
+	if first.Conversation.Type != "group" || first.Sender.DisplayName != "Alice" {
+		t.Fatalf("unexpected conversation %q sender %q", first.Conversation.Type, first.Sender.DisplayName)
+	}
+	if first.Metadata["is_mentioned"] != true || first.Metadata["is_reply_to_bot"] != true {
+		t.Fatalf("unexpected metadata: %#v", first.Metadata)
+	}
+	if second.Conversation.Type != "private" || second.ReplyTarget != "D1" || second.Message.Thread != nil {
+		t.Fatalf("unexpected direct message: %#v", second)
+	}
+	if second.Metadata["is_mentioned"] != false {
+		t.Fatalf("direct message should not be a mention: %#v", second.Metadata)
+	}
+	select {
+	case extra := <-received:
+		t.Fatalf("unexpected extra inbound: %#v", extra)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestConnectReconnectsOnDisconnect(t *testing.T) {
+	fake := newFakeSlack(t)
+	adapter := NewSlackAdapter(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	conn, err := adapter.Connect(ctx, fake.config(), func(context.Context, channel.ChannelConfig, channel.InboundMessage) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	ws := <-fake.sockets
+	if err := ws.WriteJSON(map[string]any{"type": "disconnect", "reason": "refresh_requested"}); err != nil {
+		t.Fatalf("write disconnect: %v", err)
+	}
+	select {
+	case next := <-fake.sockets:
+		_ = next.Close()
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a new socket mode connection")
+	}
+	_ = ws.Close()
+	if err := conn.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if calls := fake.callsTo("apps.connections.open"); len(calls) != 2 || calls[0].token != "xapp-test" {
+		t.Fatalf("unexpected apps.connections.open calls: %#v", calls)
+	}
+}
+
+func waitInbound(t *testing.T, ch <-chan channel.InboundMessage) channel.InboundMessage {
+	t.Helper()
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for inbound message")
+		return channel.InboundMessage{}
+	}
+}
+
+func TestSendThreads(t *testing.T) {
+	fake := newFakeSlack(t)
+	adapter := NewSlackAdapter(nil)
+	cfg := fake.config()
+	ctx := context.Background()
+	messages := []channel.OutboundMessage{
+		{Target: "C1:111.1", Message: channel.Message{Text: "in thread"}},
+		{Target: "C1", Message: channel.Message{Text: "reply", Reply: &channel.ReplyRef{MessageID: "222.2"}}},
+		{Target: "D1", Message: channel.Message{Text: "direct", Reply: &channel.ReplyRef{MessageID: "333.3"}}},
+		{Target: "C1", Message: channel.Message{Text: "**bold**", Format: channel.MessageFormatMarkdown, Thread: &channel.ThreadRef{ID: "444.4"}}},
+	}
+	for _, msg := range messages {
+		if err := adapter.Send(ctx, cfg, msg); err != nil {
+			t.Fatalf("send %q: %v", msg.Message.Text, err)
+		}
+	}
+	calls := fake.callsTo("chat.postMessage")
+	if len(calls) != 4 {
+		t.Fatalf("expected 4 posts, got %d", len(calls))
+	}
+	expected := []struct{ channel, thread, text string }{
+		{"C1", "111.1", "in thread"},
+		{"C1", "222.2", "reply"},
+		{"D1", "", "direct"},
+		{"C1", "444.4", "*bold*"},
+	}
+	for i, want := range expected {
+		params := calls[i].params
+		if params.Get("channel") != want.channel || params.Get("thread_ts") != want.thread || params.Get("text") != want.text {
+			t.Fatalf("post %d: unexpected params %v", i, params)
+		}
+		if calls[i].token != "xoxb-test" {
+			t.Fatalf("post %d: unexpected token %q", i, calls[i].token)
+		}
+	}
+}
+
+func TestStreamPostsThenUpdates(t *testing.T) {
+	fake := newFakeSlack(t)
+	fake.handle("chat.postMessage", func(params url.Values) map[string]any {
+		return map[string]any{"ok": true, "channel": params.Get("channel"), "ts": "555.5"}
+	})
+	adapter := NewSlackAdapter(nil)
+	ctx := context.Background()
+	stream, err := adapter.OpenStream(ctx, fake.config(), "C1", channel.StreamOptions{
+		Reply: &channel.ReplyRef{Target: "C1", MessageID: "100.1"},
+	})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	events := []channel.StreamEvent{
+		{Type: channel.StreamEventDelta, Delta: "Hello"},
+		{Type: channel.StreamEventDelta, Delta: " **world**"},
+		{Type: channel.StreamEventFinal, Final: &channel.StreamFinalizePayload{Message: channel.Message{Text: "Hello **world**", Format: channel.MessageFormatMarkdown}}},
+	}
+	for _, event := range events {
+		if err := stream.Push(ctx, event); err != nil {
+			t.Fatalf("push %s: %v", event.Type, err)
+		}
+	}
+	if err := stream.Close(ctx); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	posts := fake.callsTo("chat.postMessage")
+	if len(posts) != 1 {
+		t.Fatalf("expected one post, got %d", len(posts))
+	}
+	if posts[0].params.Get("thread_ts") != "100.1" || posts[0].params.Get("text") != "Hello"+slackStreamPendingSuffix {
+		t.Fatalf("unexpected post params: %v", posts[0].params)
+	}
+	updates := fake.callsTo("chat.update")
+	if len(updates) != 1 {
+		t.Fatalf("expected the throttled stream to update once, got %d", len(updates))
+	}
+	if updates[0].params.Get("ts") != "555.5" || updates[0].params.Get("text") != "Hello *world*" {
+		t.Fatalf("unexpected update params: %v", updates[0].params)
+	}
+}
+
+func TestProcessingStatusReactions(t *testing.T) {
+	fake := newFakeSlack(t)
+	fake.handle("reactions.add", func(url.Values) map[string]any {
+		return map[string]any{"ok": false, "error": "already_reacted"}
+	})
+	adapter := NewSlackAdapter(nil)
+	ctx := context.Background()
+	cfg := fake.config()
+	msg := channel.InboundMessage{Conversation: channel.Conversation{ID: "C1"}}
+	info := channel.ProcessingStatusInfo{SourceMessageID: "100.1", ReplyTarget: "C1:100.0"}
+	handle, err := adapter.ProcessingStarted(ctx, cfg, msg, info)
+	if err != nil {
+		t.Fatalf("processing started: %v", err)
+	}
+	if handle.Token != processingReaction {
+		t.Fatalf("unexpected handle: %#v", handle)
+	}
+	if err := adapter.ProcessingCompleted(ctx, cfg, msg, info, handle); err != nil {
+		t.Fatalf("processing completed: %v", err)
+	}
+	adds := fake.callsTo("reactions.add")
+	removes := fake.callsTo("reactions.remove")
+	if len(adds) != 1 || len(removes) != 1 {
+		t.Fatalf("expected one add and one remove, got %d and %d", len(adds), len(removes))
+	}
+	for _, call := range []fakeCall{adds[0], removes[0]} {
+		if call.params.Get("channel") != "C1" || call.params.Get("timestamp") != "100.1" || call.params.Get("name") != "eyes" {
+			t.Fatalf("unexpected reaction params: %v", call.params)
+		}
+	}
+	if err := adapter.React(ctx, cfg, "C1", "100.1", "👍"); err != nil {
+		t.Fatalf("react: %v", err)
+	}
+	if got := fake.callsTo("reactions.add")[1].params.Get("name"); got != "+1" {
+		t.Fatalf("expected +1 reaction, got %q", got)
+	}
+}
+
+func TestEditAndUnsend(t *testing.T) {
+	fake := newFakeSlack(t)
+	adapter := NewSlackAdapter(nil)
+	ctx := context.Background()
+	cfg := fake.config()
+	if err := adapter.Update(ctx, cfg, "C1:100.0", "100.1", channel.Message{Text: "edited"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := adapter.Unsend(ctx, cfg, "C1", "100.1"); err != nil {
+		t.Fatalf("unsend: %v", err)
+	}
+	update := fake.callsTo("chat.update")
+	if len(update) != 1 || update[0].params.Get("channel") != "C1" || update[0].params.Get("ts") != "100.1" || update[0].params.Get("text") != "edited" {
+		t.Fatalf("unexpected chat.update calls: %#v", update)
+	}
+	del := fake.callsTo("chat.delete")
+	if len(del) != 1 || del[0].params.Get("ts") != "100.1" {
+		t.Fatalf("unexpected chat.delete calls: %#v", del)
+	}
+
+	fake.handle("chat.update", func(url.Values) map[string]any {
+		return map[string]any{"ok": false, "error": "cant_update_message"}
+	})
+	err := adapter.Update(ctx, cfg, "C1", "100.1", channel.Message{Text: "again"})
+	if err == nil || !strings.Contains(err.Error(), "cant_update_message") {
+		t.Fatalf("expected API error, got %v", err)
+	}
+}
+
+func TestDiscoverSelf(t *testing.T) {
+	fake := newFakeSlack(t)
+	adapter := NewSlackAdapter(nil)
+	identity, externalID, err := adapter.DiscoverSelf(context.Background(), fake.config().Credentials)
+	if err != nil {
+		t.Fatalf("discover self: %v", err)
+	}
+	if externalID != "UBOT" {
+		t.Fatalf("unexpected external id %q", externalID)
+	}
+	if identity["bot_id"] != "BBOT" || identity["team_id"] != "T1" || identity["name"] != "memoh" {
+		t.Fatalf("unexpected identity: %#v", identity)
+	}
+}
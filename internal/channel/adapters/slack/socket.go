@@ -0,0 +1,118 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	socketReconnectMin = time.Second
+	socketReconnectMax = 30 * time.Second
+)
+
+// errSocketRefresh signals that Slack asked the client to reconnect, which
+// happens routinely every few hours.
+var errSocketRefresh = errors.New("slack socket mode refresh requested")
+
+// socketEnvelope is a frame received over a Socket Mode connection.
+type socketEnvelope struct {
+	Type       string          `json:"type"`
+	EnvelopeID string          `json:"envelope_id"`
+	Payload    json.RawMessage `json:"payload"`
+	Reason     string          `json:"reason"`
+}
+
+type socketAck struct {
+	EnvelopeID string `json:"envelope_id"`
+}
+
+// openSocket requests a Socket Mode URL with the app token and dials it.
+func (a *SlackAdapter) openSocket(ctx context.Context, cfg Config) (*websocket.Conn, error) {
+	wsURL, err := newAPIClient(a.httpClient, cfg.apiBaseURL(), cfg.AppToken).openSocketURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, resp, err := a.dialer.DialContext(ctx, wsURL, nil)
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("slack socket mode dial: %w", err)
+	}
+	return conn, nil
+}
+
+// runSocket serves conn and reconnects with backoff until ctx is done. Each
+// events_api payload is acknowledged before it is passed to onEvent.
+func (a *SlackAdapter) runSocket(ctx context.Context, configID string, cfg Config, conn *websocket.Conn, onEvent func(json.RawMessage)) {
+	backoff := socketReconnectMin
+	for {
+		err := a.readSocket(ctx, conn, onEvent)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, errSocketRefresh) {
+			a.logger.Info("socket mode reconnect requested", slog.String("config_id", configID))
+		} else {
+			a.logger.Warn("socket mode connection lost", slog.String("config_id", configID), slog.Any("error", err))
+		}
+		for {
+			conn, err = a.openSocket(ctx, cfg)
+			if err == nil {
+				backoff = socketReconnectMin
+				break
+			}
+			a.logger.Error("socket mode reconnect failed", slog.String("config_id", configID), slog.Any("error", err))
+			wait := backoff
+			if d := retryAfter(err); d > wait {
+				wait = d
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+			backoff = min(backoff*2, socketReconnectMax)
+		}
+	}
+}
+
+// readSocket reads frames from conn until it fails, Slack requests a
+// reconnect or ctx is done. The connection is closed on return.
+func (a *SlackAdapter) readSocket(ctx context.Context, conn *websocket.Conn, onEvent func(json.RawMessage)) error {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		_ = conn.Close()
+	}()
+	for {
+		var envelope socketEnvelope
+		if err := conn.ReadJSON(&envelope); err != nil {
+			return err
+		}
+		switch envelope.Type {
+		case "hello":
+			continue
+		case "disconnect":
+			return errSocketRefresh
+		}
+		if envelope.EnvelopeID != "" {
+			if err := conn.WriteJSON(socketAck{EnvelopeID: envelope.EnvelopeID}); err != nil {
+				return err
+			}
+		}
+		if envelope.Type == "events_api" && len(envelope.Payload) > 0 {
+			onEvent(envelope.Payload)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const slackStreamEditThrottle = 1500 * time.Millisecond
+const slackStreamPendingSuffix = " …"
+const slackFinalEditMaxRetries = 3
+
+// slackOutboundStream posts the first delta as a message and edits it with
+// chat.update as more text arrives. A tool call ends the current message so
+// later output starts a new one below the tool activity.
+type slackOutboundStream struct {
+	adapter      *SlackAdapter
+	cfg          channel.ChannelConfig
+	api          *apiClient
+	channelID    string
+	threadTS     string
+	closed       atomic.Bool
+	mu           sync.Mutex
+	buf          strings.Builder
+	streamTS     string
+	lastEdited   string
+	lastEditedAt time.Time
+}
+
+func (s *slackOutboundStream) ensureStreamMessage(ctx context.Context, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.streamTS != "" {
+		return nil
+	}
+	if strings.TrimSpace(text) == "" {
+		text = "…"
+	} else {
+		text = strings.TrimSpace(text) + slackStreamPendingSuffix
+	}
+	resp, err := s.api.postMessage(ctx, s.channelID, s.threadTS, truncateSlackText(text))
+	if err != nil {
+		return err
+	}
+	s.streamTS = resp.TS
+	s.lastEdited = text
+	s.lastEditedAt = time.Now()
+	return nil
+}
+
+func (s *slackOutboundStream) editStreamMessage(ctx context.Context, text string) error {
+	s.mu.Lock()
+	ts := s.streamTS
+	lastEdited := s.lastEdited
+	lastEditedAt := s.lastEditedAt
+	s.mu.Unlock()
+	if ts == "" {
+		return nil
+	}
+	text = strings.TrimSpace(text) + slackStreamPendingSuffix
+	if text == lastEdited || time.Since(lastEditedAt) < slackStreamEditThrottle {
+		return nil
+	}
+	if err := s.api.updateMessage(ctx, s.channelID, ts, truncateSlackText(text)); err != nil {
+		if isAPIError(err, "ratelimited") {
+			d := retryAfter(err)
+			if d <= 0 {
+				d = slackStreamEditThrottle
+			}
+			s.mu.Lock()
+			s.lastEditedAt = time.Now().Add(d)
+			s.mu.Unlock()
+			return nil
+		}
+		return err
+	}
+	s.mu.Lock()
+	s.lastEdited = text
+	s.lastEditedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// editStreamMessageFinal writes the final text, retrying when rate limited so
+// the message never keeps its pending suffix.
+func (s *slackOutboundStream) editStreamMessageFinal(ctx context.Context, text string) error {
+	s.mu.Lock()
+	ts := s.streamTS
+	lastEdited := s.lastEdited
+	s.mu.Unlock()
+	if ts == "" || text == lastEdited {
+		return nil
+	}
+	for attempt := range slackFinalEditMaxRetries {
+		err := s.api.updateMessage(ctx, s.channelID, ts, truncateSlackText(text))
+		if err == nil {
+			s.mu.Lock()
+			s.lastEdited = text
+			s.lastEditedAt = time.Now()
+			s.mu.Unlock()
+			return nil
+		}
+		if !isAPIError(err, "ratelimited") {
+			return err
+		}
+		d := retryAfter(err)
+		if d <= 0 {
+			d = time.Duration(attempt+1) * time.Second
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
+	}
+	return fmt.Errorf("slack chat.update: rate limited")
+}
+
+func (s *slackOutboundStream) resetStreamMessage() {
+	s.mu.Lock()
+	s.streamTS = ""
+	s.lastEdited = ""
+	s.lastEditedAt = time.Time{}
+	s.buf.Reset()
+	s.mu.Unlock()
+}
+
+func (s *slackOutboundStream) Push(ctx context.Context, event channel.StreamEvent) error {
+	if s == nil || s.adapter == nil {
+		return fmt.Errorf("slack stream not configured")
+	}
+	if s.closed.Load() {
+		return fmt.Errorf("slack stream is closed")
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	switch event.Type {
+	case channel.StreamEventToolCallStart:
+		s.mu.Lock()
+		bufText := strings.TrimSpace(s.buf.String())
+		hasMsg := s.streamTS != ""
+		s.mu.Unlock()
+		if hasMsg && bufText != "" {
+			_ = s.editStreamMessageFinal(ctx, markdownToMrkdwn(bufText))
+		}
+		s.resetStreamMessage()
+		return nil
+	case channel.StreamEventToolCallEnd:
+		s.resetStreamMessage()
+		return nil
+	case channel.StreamEventDelta:
+		if event.Delta == "" || event.Phase == channel.StreamPhaseReasoning {
+			return nil
+		}
+		s.mu.Lock()
+		s.buf.WriteString(event.Delta)
+		content := s.buf.String()
+		s.mu.Unlock()
+		if err := s.ensureStreamMessage(ctx, content); err != nil {
+			return err
+		}
+		return s.editStreamMessage(ctx, content)
+	case channel.StreamEventFinal:
+		s.mu.Lock()
+		finalText := strings.TrimSpace(s.buf.String())
+		s.mu.Unlock()
+		format := channel.MessageFormatMarkdown
+		var attachments []channel.Attachment
+		if event.Final != nil && !event.Final.Message.IsEmpty() {
+			msg := event.Final.Message
+			if finalText == "" {
+				finalText = strings.TrimSpace(msg.PlainText())
+				format = msg.Format
+			}
+			attachments = msg.Attachments
+		}
+		if format == channel.MessageFormatMarkdown {
+			finalText = markdownToMrkdwn(finalText)
+		}
+		finalText = appendAttachmentLinks(finalText, attachments)
+		if finalText == "" {
+			return nil
+		}
+		if err := s.ensureStreamMessage(ctx, finalText); err != nil {
+			return err
+		}
+		return s.editStreamMessageFinal(ctx, finalText)
+	case channel.StreamEventError:
+		errText := strings.TrimSpace(event.Error)
+		if errText == "" {
+			return nil
+		}
+		display := "Error: " + errText
+		if err := s.ensureStreamMessage(ctx, display); err != nil {
+			return err
+		}
+		return s.editStreamMessageFinal(ctx, display)
+	case channel.StreamEventAttachment:
+		if len(event.Attachments) == 0 {
+			return nil
+		}
+		text := appendAttachmentLinks("", event.Attachments)
+		if text == "" {
+			s.adapter.logger.Warn("stream attachment has no url", slog.String("config_id", s.cfg.ID))
+			return nil
+		}
+		_, err := s.api.postMessage(ctx, s.channelID, s.threadTS, text)
+		return err
+	default:
+		return nil
+	}
+}
+
+// appendAttachmentLinks lists attachments that have a URL as Slack links;
+// files are not uploaded.
+func appendAttachmentLinks(text string, attachments []channel.Attachment) string {
+	var links []string
+	for _, att := range attachments {
+		link := strings.TrimSpace(att.URL)
+		if link == "" || strings.HasPrefix(link, "data:") {
+			continue
+		}
+		if name := strings.TrimSpace(att.Name); name != "" {
+			link = "<" + link + "|" + escapeMrkdwn(name) + ">"
+		}
+		links = append(links, link)
+	}
+	if len(links) == 0 {
+		return text
+	}
+	if text == "" {
+		return strings.Join(links, "\n")
+	}
+	return text + "\n" + strings.Join(links, "\n")
+}
+
+func (s *slackOutboundStream) Close(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	s.closed.Store(true)
+	return nil
+}
@@ -0,0 +1,221 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// Trigger rules deciding whether an inbound request starts a chat turn.
+const (
+	// TriggerAll starts a chat turn for every request.
+	TriggerAll = "all"
+	// TriggerInbox only stores requests in the bot inbox.
+	TriggerInbox = "inbox"
+)
+
+const defaultSignatureHeader = "X-Memoh-Signature"
+
+// Mapping holds the JSON paths that pick InboundMessage fields out of an
+// inbound request body. Paths are dot-separated keys and array indexes,
+// e.g. "alerts.0.annotations.summary"; an empty path leaves the field unset.
+type Mapping struct {
+	Text             string
+	MessageID        string
+	SenderID         string
+	SenderName       string
+	ConversationID   string
+	ConversationName string
+	ThreadID         string
+	Attachments      string
+}
+
+// defaultMapping matches the native payload shape documented in the
+// descriptor.
+var defaultMapping = Mapping{
+	Text:             "text",
+	MessageID:        "id",
+	SenderID:         "sender.id",
+	SenderName:       "sender.name",
+	ConversationID:   "conversation.id",
+	ConversationName: "conversation.name",
+	ThreadID:         "thread_id",
+	Attachments:      "attachments",
+}
+
+// Config holds the webhook settings extracted from a channel configuration.
+type Config struct {
+	// Secret is the HMAC-SHA256 key inbound requests are verified with and
+	// outbound requests are signed with.
+	Secret          string
+	SignatureHeader string
+	// TimestampHeader, when set, carries a Unix timestamp that is signed
+	// together with the body as "timestamp:body" and bounds replays.
+	TimestampHeader string
+	// OutboundURL receives replies; without it the channel is inbound-only.
+	OutboundURL string
+	Trigger     string
+	Mapping     Mapping
+}
+
+// UserConfig holds the sender ID used to target a webhook user.
+type UserConfig struct {
+	SenderID string
+}
+
+var mappingKeys = []struct {
+	key   string
+	field func(*Mapping) *string
+}{
+	{"textPath", func(m *Mapping) *string { return &m.Text }},
+	{"messageIdPath", func(m *Mapping) *string { return &m.MessageID }},
+	{"senderIdPath", func(m *Mapping) *string { return &m.SenderID }},
+	{"senderNamePath", func(m *Mapping) *string { return &m.SenderName }},
+	{"conversationIdPath", func(m *Mapping) *string { return &m.ConversationID }},
+	{"conversationNamePath", func(m *Mapping) *string { return &m.ConversationName }},
+	{"threadIdPath", func(m *Mapping) *string { return &m.ThreadID }},
+	{"attachmentsPath", func(m *Mapping) *string { return &m.Attachments }},
+}
+
+func normalizeConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]any{
+		"secret":          cfg.Secret,
+		"signatureHeader": cfg.SignatureHeader,
+		"trigger":         cfg.Trigger,
+	}
+	if cfg.TimestampHeader != "" {
+		result["timestampHeader"] = cfg.TimestampHeader
+	}
+	if cfg.OutboundURL != "" {
+		result["outboundUrl"] = cfg.OutboundURL
+	}
+	for _, item := range mappingKeys {
+		result[item.key] = *item.field(&cfg.Mapping)
+	}
+	return result, nil
+}
+
+func normalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"sender_id": cfg.SenderID}, nil
+}
+
+func resolveTarget(raw map[string]any) (string, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return "", err
+	}
+	return cfg.SenderID, nil
+}
+
+func matchBinding(raw map[string]any, criteria channel.BindingCriteria) bool {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return false
+	}
+	if value := criteria.Attribute("sender_id"); value != "" && value == cfg.SenderID {
+		return true
+	}
+	return criteria.SubjectID != "" && criteria.SubjectID == cfg.SenderID
+}
+
+func buildUserConfig(identity channel.Identity) map[string]any {
+	result := map[string]any{}
+	if value := identity.Attribute("sender_id"); value != "" {
+		result["sender_id"] = value
+	}
+	return result
+}
+
+func parseConfig(raw map[string]any) (Config, error) {
+	secret := strings.TrimSpace(channel.ReadString(raw, "secret"))
+	if secret == "" {
+		return Config{}, fmt.Errorf("webhook secret is required")
+	}
+	signatureHeader := http.CanonicalHeaderKey(strings.TrimSpace(channel.ReadString(raw, "signatureHeader", "signature_header")))
+	if signatureHeader == "" {
+		signatureHeader = defaultSignatureHeader
+	}
+	outboundURL := strings.TrimSpace(channel.ReadString(raw, "outboundUrl", "outbound_url"))
+	if outboundURL != "" {
+		parsed, err := url.Parse(outboundURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return Config{}, fmt.Errorf("webhook outboundUrl must be an http(s) URL")
+		}
+	}
+	trigger := strings.ToLower(strings.TrimSpace(channel.ReadString(raw, "trigger")))
+	switch trigger {
+	case "":
+		trigger = TriggerAll
+	case TriggerAll, TriggerInbox:
+	default:
+		return Config{}, fmt.Errorf("webhook trigger must be %s or %s", TriggerAll, TriggerInbox)
+	}
+	mapping := defaultMapping
+	for _, item := range mappingKeys {
+		value, ok := raw[item.key]
+		if !ok {
+			continue
+		}
+		path, ok := value.(string)
+		if !ok {
+			return Config{}, fmt.Errorf("webhook %s must be a string", item.key)
+		}
+		path = normalizePath(path)
+		if _, err := splitPath(path); err != nil {
+			return Config{}, fmt.Errorf("webhook %s: %w", item.key, err)
+		}
+		*item.field(&mapping) = path
+	}
+	return Config{
+		Secret:          secret,
+		SignatureHeader: signatureHeader,
+		TimestampHeader: http.CanonicalHeaderKey(strings.TrimSpace(channel.ReadString(raw, "timestampHeader", "timestamp_header"))),
+		OutboundURL:     outboundURL,
+		Trigger:         trigger,
+		Mapping:         mapping,
+	}, nil
+}
+
+func parseUserConfig(raw map[string]any) (UserConfig, error) {
+	senderID := strings.TrimSpace(channel.ReadString(raw, "senderId", "sender_id"))
+	if senderID == "" {
+		return UserConfig{}, fmt.Errorf("webhook user config requires sender_id")
+	}
+	return UserConfig{SenderID: senderID}, nil
+}
+
+// threadSeparator joins a conversation and a thread in a target, e.g.
+// "alerts#incident-42".
+const threadSeparator = "#"
+
+func normalizeTarget(raw string) string {
+	return strings.TrimSpace(raw)
+}
+
+// splitTarget splits a target into the conversation and the thread, which is
+// empty when the target has none.
+func splitTarget(target string) (string, string) {
+	target = strings.TrimSpace(target)
+	if idx := strings.Index(target, threadSeparator); idx >= 0 {
+		return strings.TrimSpace(target[:idx]), strings.TrimSpace(target[idx+1:])
+	}
+	return target, ""
+}
+
+func joinTarget(conversationID, threadID string) string {
+	if threadID == "" {
+		return conversationID
+	}
+	return conversationID + threadSeparator + threadID
+}
@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestNormalizeConfigDefaults(t *testing.T) {
+	t.Parallel()
+
+	got, err := normalizeConfig(map[string]any{"secret": " s3cret "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["secret"] != "s3cret" || got["signatureHeader"] != defaultSignatureHeader || got["trigger"] != TriggerAll {
+		t.Fatalf("unexpected config: %#v", got)
+	}
+	if got["textPath"] != "text" || got["senderIdPath"] != "sender.id" {
+		t.Fatalf("unexpected default mapping: %#v", got)
+	}
+	if _, ok := got["outboundUrl"]; ok {
+		t.Fatalf("outboundUrl should be omitted: %#v", got)
+	}
+}
+
+func TestNormalizeConfigMapping(t *testing.T) {
+	t.Parallel()
+
+	got, err := normalizeConfig(map[string]any{
+		"secret":           "s",
+		"signature_header": "x-grafana-alerting-signature",
+		"outbound_url":     "https://example.com/hook",
+		"trigger":          "Inbox",
+		"textPath":         "$.alerts[0].annotations.summary",
+		"threadIdPath":     "",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["signatureHeader"] != "X-Grafana-Alerting-Signature" {
+		t.Fatalf("unexpected signature header: %#v", got["signatureHeader"])
+	}
+	if got["outboundUrl"] != "https://example.com/hook" || got["trigger"] != TriggerInbox {
+		t.Fatalf("unexpected config: %#v", got)
+	}
+	if got["textPath"] != "alerts.0.annotations.summary" {
+		t.Fatalf("unexpected text path: %#v", got["textPath"])
+	}
+	if got["threadIdPath"] != "" {
+		t.Fatalf("empty path should disable the field: %#v", got["threadIdPath"])
+	}
+}
+
+func TestNormalizeConfigErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []map[string]any{
+		{},
+		{"secret": "s", "outboundUrl": "ftp://example.com"},
+		{"secret": "s", "outboundUrl": "https://"},
+		{"secret": "s", "trigger": "sometimes"},
+		{"secret": "s", "textPath": "a..b"},
+		{"secret": "s", "textPath": 3},
+	}
+	for _, raw := range cases {
+		if _, err := normalizeConfig(raw); err == nil {
+			t.Fatalf("expected error for %#v", raw)
+		}
+	}
+}
+
+func TestUserConfigAndBinding(t *testing.T) {
+	t.Parallel()
+
+	got, err := normalizeUserConfig(map[string]any{"senderId": " ci "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["sender_id"] != "ci" {
+		t.Fatalf("unexpected user config: %#v", got)
+	}
+	if _, err := normalizeUserConfig(map[string]any{}); err == nil {
+		t.Fatal("expected error for missing sender_id")
+	}
+	target, err := resolveTarget(got)
+	if err != nil || target != "ci" {
+		t.Fatalf("unexpected target: %q %v", target, err)
+	}
+	if !matchBinding(got, channel.BindingCriteria{Attributes: map[string]string{"sender_id": "ci"}}) {
+		t.Fatal("expected attribute match")
+	}
+	if !matchBinding(got, channel.BindingCriteria{SubjectID: "ci"}) {
+		t.Fatal("expected subject match")
+	}
+	if matchBinding(got, channel.BindingCriteria{SubjectID: "other"}) {
+		t.Fatal("unexpected match")
+	}
+	built := buildUserConfig(channel.Identity{SubjectID: "ci", Attributes: map[string]string{"sender_id": "ci"}})
+	if built["sender_id"] != "ci" {
+		t.Fatalf("unexpected built config: %#v", built)
+	}
+}
+
+func TestSplitTarget(t *testing.T) {
+	t.Parallel()
+
+	conversation, thread := splitTarget(" alerts#incident-42 ")
+	if conversation != "alerts" || thread != "incident-42" {
+		t.Fatalf("unexpected split: %q %q", conversation, thread)
+	}
+	conversation, thread = splitTarget("alerts")
+	if conversation != "alerts" || thread != "" {
+		t.Fatalf("unexpected split: %q %q", conversation, thread)
+	}
+	if got := joinTarget("alerts", "incident-42"); got != "alerts#incident-42" {
+		t.Fatalf("unexpected join: %q", got)
+	}
+}
@@ -0,0 +1,8 @@
+// Package webhook implements a generic HTTP channel: signed JSON requests
+// come in on a per-config endpoint and replies are POSTed to a configured URL.
+package webhook
+
+import "github.com/memohai/memoh/internal/channel"
+
+// Type is the registered ChannelType identifier for generic webhooks.
+const Type channel.ChannelType = "webhook"
@@ -0,0 +1,128 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+type configStore interface {
+	ListConfigsByType(ctx context.Context, channelType channel.ChannelType) ([]channel.ChannelConfig, error)
+}
+
+type inboundManager interface {
+	HandleInbound(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage) error
+}
+
+const maxBodyBytes int64 = 1 << 20 // 1 MiB
+
+// Handler receives signed inbound requests for webhook channel configs.
+type Handler struct {
+	logger  *slog.Logger
+	store   configStore
+	manager inboundManager
+	now     func() time.Time
+}
+
+// NewHandler creates the public inbound endpoint for webhook channels.
+func NewHandler(log *slog.Logger, store configStore, manager inboundManager) *Handler {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Handler{
+		logger:  log.With(slog.String("handler", "webhook")),
+		store:   store,
+		manager: manager,
+		now:     time.Now,
+	}
+}
+
+// NewServerHandler is a DI-friendly constructor for fx/dig, using concrete
+// channel types as parameters.
+func NewServerHandler(log *slog.Logger, store *channel.Store, manager *channel.Manager) *Handler {
+	return NewHandler(log, store, manager)
+}
+
+// Register registers the inbound webhook routes.
+func (h *Handler) Register(e *echo.Echo) {
+	e.GET("/channels/webhook/:config_id", h.HandleProbe)
+	e.POST("/channels/webhook/:config_id", h.Handle)
+}
+
+// HandleProbe responds to health/probe requests on the webhook URL.
+func (h *Handler) HandleProbe(c echo.Context) error {
+	return c.String(http.StatusOK, "ok")
+}
+
+// Handle verifies the request signature, maps the JSON body into an inbound
+// message and queues it for the bot.
+func (h *Handler) Handle(c echo.Context) error {
+	if h.store == nil || h.manager == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "webhook dependencies not configured")
+	}
+	configID := strings.TrimSpace(c.Param("config_id"))
+	if configID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "config id is required")
+	}
+	cfg, err := h.findConfigByID(c.Request().Context(), configID)
+	if err != nil {
+		return err
+	}
+	if cfg.Disabled {
+		return echo.NewHTTPError(http.StatusForbidden, "channel config is disabled")
+	}
+	webhookCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(c.Request().Body, maxBodyBytes+1))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("read body: %v", err))
+	}
+	if int64(len(payload)) > maxBodyBytes {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("payload too large: max %d bytes", maxBodyBytes))
+	}
+	now := h.now()
+	if err := verifySignature(webhookCfg, c.Request().Header, payload, now); err != nil {
+		h.logger.Warn("webhook signature rejected", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+	doc, err := decodeBody(payload)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid JSON body: %v", err))
+	}
+	msg, err := toInboundMessage(cfg, webhookCfg, doc, now)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err := h.manager.HandleInbound(context.WithoutCancel(c.Request().Context()), cfg, msg); err != nil {
+		h.logger.Error("webhook inbound failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	}
+	return c.JSON(http.StatusAccepted, map[string]string{
+		"status":     "accepted",
+		"message_id": msg.Message.ID,
+	})
+}
+
+func (h *Handler) findConfigByID(ctx context.Context, configID string) (channel.ChannelConfig, error) {
+	items, err := h.store.ListConfigsByType(ctx, Type)
+	if err != nil {
+		return channel.ChannelConfig{}, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	for _, item := range items {
+		if strings.TrimSpace(item.ID) == configID {
+			return item, nil
+		}
+	}
+	return channel.ChannelConfig{}, echo.NewHTTPError(http.StatusNotFound, "channel config not found")
+}
@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+type fakeWebhookStore struct {
+	configs []channel.ChannelConfig
+	err     error
+}
+
+func (s *fakeWebhookStore) ListConfigsByType(ctx context.Context, channelType channel.ChannelType) ([]channel.ChannelConfig, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.configs, nil
+}
+
+type fakeWebhookManager struct {
+	calls []struct {
+		cfg channel.ChannelConfig
+		msg channel.InboundMessage
+	}
+	err error
+}
+
+func (m *fakeWebhookManager) HandleInbound(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage) error {
+	m.calls = append(m.calls, struct {
+		cfg channel.ChannelConfig
+		msg channel.InboundMessage
+	}{cfg: cfg, msg: msg})
+	return m.err
+}
+
+func newTestHandler(credentials map[string]any, manager *fakeWebhookManager) *Handler {
+	store := &fakeWebhookStore{
+		configs: []channel.ChannelConfig{
+			{ID: "cfg-1", BotID: "bot-1", ChannelType: Type, Credentials: credentials},
+		},
+	}
+	return NewHandler(nil, store, manager)
+}
+
+func serveWebhook(t *testing.T, h *Handler, configID, body string, headers map[string]string) (*httptest.ResponseRecorder, error) {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/channels/webhook/"+configID, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("config_id")
+	c.SetParamValues(configID)
+	return rec, h.Handle(c)
+}
+
+func assertHTTPStatus(t *testing.T, err error, want int) {
+	t.Helper()
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected echo HTTPError with status %d, got %v", want, err)
+	}
+	if httpErr.Code != want {
+		t.Fatalf("unexpected status code: want %d got %d (%v)", want, httpErr.Code, httpErr.Message)
+	}
+}
+
+func TestWebhookHandler_AcceptsSignedRequest(t *testing.T) {
+	t.Parallel()
+
+	manager := &fakeWebhookManager{}
+	h := newTestHandler(map[string]any{"secret": "s3cret"}, manager)
+	body := `{"id":"evt-1","text":"build failed","sender":{"id":"ci","name":"CI"},"conversation":{"id":"builds"}}`
+
+	rec, err := serveWebhook(t, h, "cfg-1", body, map[string]string{
+		defaultSignatureHeader: "sha256=" + sign("s3cret", "", []byte(body)),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusAccepted || !strings.Contains(rec.Body.String(), `"message_id":"evt-1"`) {
+		t.Fatalf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+	if len(manager.calls) != 1 {
+		t.Fatalf("expected one inbound call, got %d", len(manager.calls))
+	}
+	msg := manager.calls[0].msg
+	if msg.Message.Text != "build failed" || msg.Sender.DisplayName != "CI" || msg.ReplyTarget != "builds" {
+		t.Fatalf("unexpected inbound message: %#v", msg)
+	}
+}
+
+func TestWebhookHandler_RejectsBadSignature(t *testing.T) {
+	t.Parallel()
+
+	manager := &fakeWebhookManager{}
+	h := newTestHandler(map[string]any{"secret": "s3cret"}, manager)
+	body := `{"text":"hi"}`
+
+	_, err := serveWebhook(t, h, "cfg-1", body, nil)
+	assertHTTPStatus(t, err, http.StatusUnauthorized)
+	_, err = serveWebhook(t, h, "cfg-1", body, map[string]string{defaultSignatureHeader: sign("other", "", []byte(body))})
+	assertHTTPStatus(t, err, http.StatusUnauthorized)
+	if len(manager.calls) != 0 {
+		t.Fatalf("unexpected inbound calls: %d", len(manager.calls))
+	}
+}
+
+func TestWebhookHandler_TimestampWindow(t *testing.T) {
+	t.Parallel()
+
+	manager := &fakeWebhookManager{}
+	h := newTestHandler(map[string]any{"secret": "s3cret", "timestampHeader": "X-Memoh-Timestamp"}, manager)
+	now := time.Unix(1700000000, 0)
+	h.now = func() time.Time { return now }
+	body := `{"text":"hi"}`
+
+	signed := func(ts time.Time) map[string]string {
+		timestamp := strconv.FormatInt(ts.Unix(), 10)
+		return map[string]string{
+			"X-Memoh-Timestamp":    timestamp,
+			defaultSignatureHeader: sign("s3cret", timestamp, []byte(body)),
+		}
+	}
+	if _, err := serveWebhook(t, h, "cfg-1", body, signed(now.Add(-time.Minute))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err := serveWebhook(t, h, "cfg-1", body, signed(now.Add(-10*time.Minute)))
+	assertHTTPStatus(t, err, http.StatusUnauthorized)
+	if len(manager.calls) != 1 {
+		t.Fatalf("expected one inbound call, got %d", len(manager.calls))
+	}
+}
+
+func TestWebhookHandler_Errors(t *testing.T) {
+	t.Parallel()
+
+	manager := &fakeWebhookManager{}
+	h := newTestHandler(map[string]any{"secret": "s3cret"}, manager)
+	signed := func(body string) map[string]string {
+		return map[string]string{defaultSignatureHeader: sign("s3cret", "", []byte(body))}
+	}
+
+	_, err := serveWebhook(t, h, "missing", `{}`, nil)
+	assertHTTPStatus(t, err, http.StatusNotFound)
+	_, err = serveWebhook(t, h, "cfg-1", `not json`, signed(`not json`))
+	assertHTTPStatus(t, err, http.StatusBadRequest)
+	_, err = serveWebhook(t, h, "cfg-1", `{"other":1}`, signed(`{"other":1}`))
+	assertHTTPStatus(t, err, http.StatusUnprocessableEntity)
+
+	manager.err = errors.New("down")
+	_, err = serveWebhook(t, h, "cfg-1", `{"text":"hi"}`, signed(`{"text":"hi"}`))
+	assertHTTPStatus(t, err, http.StatusServiceUnavailable)
+}
+
+func TestWebhookHandler_DisabledConfig(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeWebhookStore{
+		configs: []channel.ChannelConfig{
+			{ID: "cfg-1", ChannelType: Type, Disabled: true, Credentials: map[string]any{"secret": "s"}},
+		},
+	}
+	h := NewHandler(nil, store, &fakeWebhookManager{})
+	_, err := serveWebhook(t, h, "cfg-1", `{"text":"hi"}`, nil)
+	assertHTTPStatus(t, err, http.StatusForbidden)
+}
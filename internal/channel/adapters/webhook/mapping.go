@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// normalizePath accepts JSONPath-style input ("$.alerts[0].labels") and
+// reduces it to the dot form used internally ("alerts.0.labels").
+func normalizePath(path string) string {
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(path, "$")
+	path = strings.TrimPrefix(path, ".")
+	path = strings.ReplaceAll(path, "[", ".")
+	path = strings.ReplaceAll(path, "]", "")
+	return path
+}
+
+func splitPath(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	segments := strings.Split(path, ".")
+	for _, segment := range segments {
+		if segment == "" {
+			return nil, fmt.Errorf("invalid path %q", path)
+		}
+	}
+	return segments, nil
+}
+
+// lookupPath resolves a dot path in a decoded JSON document. Numeric
+// segments index arrays.
+func lookupPath(doc any, path string) (any, bool) {
+	segments, err := splitPath(path)
+	if err != nil || len(segments) == 0 {
+		return nil, false
+	}
+	current := doc
+	for _, segment := range segments {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, current != nil
+}
+
+// lookupString resolves a path to text. Numbers and booleans are formatted;
+// objects and arrays are rendered as JSON.
+func lookupString(doc any, path string) string {
+	value, ok := lookupPath(doc, path)
+	if !ok {
+		return ""
+	}
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+}
+
+// decodeBody parses a JSON request body, keeping numbers exact so numeric
+// IDs survive.
+func decodeBody(body []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// lookupAttachments maps the array at path to attachments. Elements are
+// either URL strings or objects with url, base64, name, mime and type.
+func lookupAttachments(doc any, path string) []channel.Attachment {
+	value, ok := lookupPath(doc, path)
+	if !ok {
+		return nil
+	}
+	items, ok := value.([]any)
+	if !ok {
+		items = []any{value}
+	}
+	attachments := make([]channel.Attachment, 0, len(items))
+	for _, item := range items {
+		var att channel.Attachment
+		switch v := item.(type) {
+		case string:
+			att.URL = strings.TrimSpace(v)
+		case map[string]any:
+			att.URL = lookupString(v, "url")
+			att.Base64 = lookupString(v, "base64")
+			att.Name = lookupString(v, "name")
+			att.Mime = lookupString(v, "mime")
+			att.Caption = lookupString(v, "caption")
+			att.Type = channel.AttachmentType(lookupString(v, "type"))
+		}
+		if att.URL == "" && att.Base64 == "" {
+			continue
+		}
+		if att.Type == "" {
+			att.Type = attachmentType(att.Mime)
+		}
+		att.SourcePlatform = Type.String()
+		attachments = append(attachments, att)
+	}
+	return attachments
+}
+
+func attachmentType(mime string) channel.AttachmentType {
+	switch {
+	case mime == "image/gif":
+		return channel.AttachmentGIF
+	case strings.HasPrefix(mime, "image/"):
+		return channel.AttachmentImage
+	case strings.HasPrefix(mime, "audio/"):
+		return channel.AttachmentAudio
+	case strings.HasPrefix(mime, "video/"):
+		return channel.AttachmentVideo
+	default:
+		return channel.AttachmentFile
+	}
+}
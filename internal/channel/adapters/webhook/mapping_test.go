@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func mustDecode(t *testing.T, body string) any {
+	t.Helper()
+	doc, err := decodeBody([]byte(body))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	return doc
+}
+
+func TestLookupString(t *testing.T) {
+	t.Parallel()
+
+	doc := mustDecode(t, `{"id":12345678901234567890,"ok":true,"alerts":[{"labels":{"name":" cpu "}}],"obj":{"a":1}}`)
+	cases := map[string]string{
+		"id":                   "12345678901234567890",
+		"ok":                   "true",
+		"alerts.0.labels.name": "cpu",
+		"obj":                  `{"a":1}`,
+		"alerts.1.labels":      "",
+		"missing":              "",
+		"ok.nested":            "",
+	}
+	for path, want := range cases {
+		if got := lookupString(doc, path); got != want {
+			t.Fatalf("path %q: want %q got %q", path, want, got)
+		}
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"$.alerts[0].labels": "alerts.0.labels",
+		".text":              "text",
+		" text ":             "text",
+		"$":                  "",
+	}
+	for input, want := range cases {
+		if got := normalizePath(input); got != want {
+			t.Fatalf("input %q: want %q got %q", input, want, got)
+		}
+	}
+}
+
+func TestLookupAttachments(t *testing.T) {
+	t.Parallel()
+
+	doc := mustDecode(t, `{"attachments":["https://example.com/a.png",{"url":"https://example.com/b.mp3","mime":"audio/mpeg","name":"b.mp3"},{"name":"empty"}]}`)
+	got := lookupAttachments(doc, "attachments")
+	if len(got) != 2 {
+		t.Fatalf("unexpected attachments: %#v", got)
+	}
+	if got[0].URL != "https://example.com/a.png" || got[0].Type != channel.AttachmentFile {
+		t.Fatalf("unexpected first attachment: %#v", got[0])
+	}
+	if got[1].Type != channel.AttachmentAudio || got[1].Name != "b.mp3" || got[1].SourcePlatform != "webhook" {
+		t.Fatalf("unexpected second attachment: %#v", got[1])
+	}
+}
+
+func TestToInboundMessage(t *testing.T) {
+	t.Parallel()
+
+	cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: Type}
+	webhookCfg, err := parseConfig(map[string]any{
+		"secret":               "s",
+		"trigger":              "inbox",
+		"textPath":             "$.alerts[0].annotations.summary",
+		"senderIdPath":         "receiver",
+		"conversationIdPath":   "groupKey",
+		"conversationNamePath": "title",
+		"threadIdPath":         "alerts[0].fingerprint",
+	})
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	doc := mustDecode(t, `{"receiver":"grafana","groupKey":"g1","title":"CPU","alerts":[{"fingerprint":"f1","annotations":{"summary":"CPU high"}}]}`)
+	now := time.Unix(1700000000, 0)
+
+	msg, err := toInboundMessage(cfg, webhookCfg, doc, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.Message.Text != "CPU high" || msg.Message.ID == "" {
+		t.Fatalf("unexpected message: %#v", msg.Message)
+	}
+	if msg.Sender.SubjectID != "grafana" || msg.Sender.DisplayName != "grafana" || msg.Sender.Attribute("sender_id") != "grafana" {
+		t.Fatalf("unexpected sender: %#v", msg.Sender)
+	}
+	if msg.Conversation.ID != "g1" || msg.Conversation.Name != "CPU" || msg.Conversation.Type != conversationType || msg.Conversation.ThreadID != "f1" {
+		t.Fatalf("unexpected conversation: %#v", msg.Conversation)
+	}
+	if msg.ReplyTarget != "g1#f1" || msg.BotID != "bot-1" || !msg.ReceivedAt.Equal(now) {
+		t.Fatalf("unexpected inbound message: %#v", msg)
+	}
+	if msg.Metadata["is_mentioned"] != false {
+		t.Fatalf("inbox trigger should not mention the bot: %#v", msg.Metadata)
+	}
+}
+
+func TestToInboundMessageDefaults(t *testing.T) {
+	t.Parallel()
+
+	webhookCfg, err := parseConfig(map[string]any{"secret": "s"})
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	msg, err := toInboundMessage(channel.ChannelConfig{}, webhookCfg, mustDecode(t, `{"text":"deploy finished","id":7}`), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.Message.ID != "7" || msg.Sender.SubjectID != "webhook" || msg.Conversation.ID != "webhook" || msg.ReplyTarget != "webhook" {
+		t.Fatalf("unexpected defaults: %#v", msg)
+	}
+	if msg.Metadata["is_mentioned"] != true {
+		t.Fatalf("all trigger should mention the bot: %#v", msg.Metadata)
+	}
+
+	if _, err := toInboundMessage(channel.ChannelConfig{}, webhookCfg, mustDecode(t, `{"other":"x"}`), time.Now()); err == nil {
+		t.Fatal("expected error for a body without text")
+	}
+}
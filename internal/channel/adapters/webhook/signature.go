@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxTimestampSkew bounds how old or early a signed timestamp may be.
+const maxTimestampSkew = 5 * time.Minute
+
+const signaturePrefix = "sha256="
+
+var (
+	errMissingSignature = errors.New("missing webhook signature")
+	errInvalidSignature = errors.New("invalid webhook signature")
+	errStaleTimestamp   = errors.New("webhook timestamp is missing or outside the allowed window")
+)
+
+// sign returns the hex HMAC-SHA256 of body, or of "timestamp:body" when a
+// timestamp is given.
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	if timestamp != "" {
+		mac.Write([]byte(timestamp + ":"))
+	}
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifySignature checks the signature header of an inbound request. The
+// header holds the hex digest with or without a "sha256=" prefix.
+func verifySignature(cfg Config, header http.Header, body []byte, now time.Time) error {
+	provided := strings.TrimSpace(header.Get(cfg.SignatureHeader))
+	if provided == "" {
+		return errMissingSignature
+	}
+	if len(provided) >= len(signaturePrefix) && strings.EqualFold(provided[:len(signaturePrefix)], signaturePrefix) {
+		provided = provided[len(signaturePrefix):]
+	}
+	timestamp := ""
+	if cfg.TimestampHeader != "" {
+		timestamp = strings.TrimSpace(header.Get(cfg.TimestampHeader))
+		if !freshTimestamp(timestamp, now) {
+			return errStaleTimestamp
+		}
+	}
+	expected := sign(cfg.Secret, timestamp, body)
+	if !hmac.Equal([]byte(strings.ToLower(provided)), []byte(expected)) {
+		return errInvalidSignature
+	}
+	return nil
+}
+
+// freshTimestamp accepts Unix timestamps in seconds or milliseconds.
+func freshTimestamp(value string, now time.Time) bool {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		return false
+	}
+	ts := time.Unix(n, 0)
+	if n > 1e12 {
+		ts = time.UnixMilli(n)
+	}
+	skew := now.Sub(ts)
+	return skew <= maxTimestampSkew && skew >= -maxTimestampSkew
+}
+
+// signRequest sets the signature, and the timestamp when configured, on an
+// outbound request.
+func signRequest(cfg Config, req *http.Request, body []byte, now time.Time) {
+	timestamp := ""
+	if cfg.TimestampHeader != "" {
+		timestamp = strconv.FormatInt(now.Unix(), 10)
+		req.Header.Set(cfg.TimestampHeader, timestamp)
+	}
+	req.Header.Set(cfg.SignatureHeader, signaturePrefix+sign(cfg.Secret, timestamp, body))
+}
@@ -0,0 +1,415 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/media"
+)
+
+const (
+	outboundTimeout     = 30 * time.Second
+	outboundMaxAttempts = 3
+	outboundRetryDelay  = time.Second
+	// outboundMaxInlineBytes bounds stored media inlined as base64 into an
+	// outbound request.
+	outboundMaxInlineBytes int64 = 10 << 20
+	// webhookTextChunkLimit keeps a reply in a single request.
+	webhookTextChunkLimit = 100000
+)
+
+// conversationType marks webhook conversations. It is not a direct type, so
+// the trigger rule decides through is_mentioned whether a request starts a
+// chat turn, and requests from unbound senders are dropped without a reply.
+const conversationType = "webhook"
+
+type assetOpener interface {
+	Open(ctx context.Context, botID, contentHash string) (io.ReadCloser, media.Asset, error)
+}
+
+// WebhookAdapter implements the generic webhook channel. Inbound requests
+// arrive through Handler; replies are POSTed as JSON to the configured
+// outbound URL.
+type WebhookAdapter struct {
+	logger     *slog.Logger
+	httpClient *http.Client
+	assets     assetOpener
+	retryDelay time.Duration
+}
+
+// NewWebhookAdapter creates a WebhookAdapter with the given logger.
+func NewWebhookAdapter(log *slog.Logger) *WebhookAdapter {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &WebhookAdapter{
+		logger:     log.With(slog.String("adapter", "webhook")),
+		httpClient: &http.Client{Timeout: outboundTimeout},
+		retryDelay: outboundRetryDelay,
+	}
+}
+
+// SetAssetOpener injects media asset reader for content_hash attachment delivery.
+func (a *WebhookAdapter) SetAssetOpener(opener assetOpener) {
+	a.assets = opener
+}
+
+// Type returns the webhook channel type.
+func (a *WebhookAdapter) Type() channel.ChannelType {
+	return Type
+}
+
+// Descriptor returns the webhook channel metadata.
+func (a *WebhookAdapter) Descriptor() channel.Descriptor {
+	return channel.Descriptor{
+		Type:        Type,
+		DisplayName: "Webhook",
+		Capabilities: channel.ChannelCapabilities{
+			Text:           true,
+			Markdown:       true,
+			Reply:          true,
+			Threads:        true,
+			Attachments:    true,
+			Media:          true,
+			BlockStreaming: true,
+		},
+		OutboundPolicy: channel.OutboundPolicy{
+			TextChunkLimit: webhookTextChunkLimit,
+		},
+		ConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"secret": {
+					Type:        channel.FieldSecret,
+					Required:    true,
+					Title:       "Secret",
+					Description: "HMAC-SHA256 key for verifying inbound and signing outbound requests",
+				},
+				"signatureHeader": {
+					Type:        channel.FieldString,
+					Title:       "Signature Header",
+					Description: "Header carrying the hex HMAC-SHA256 of the body, optionally prefixed with sha256=",
+					Example:     defaultSignatureHeader,
+				},
+				"timestampHeader": {
+					Type:        channel.FieldString,
+					Title:       "Timestamp Header",
+					Description: "Optional header with a Unix timestamp; when set the signature covers \"timestamp:body\" and requests older than 5 minutes are rejected",
+					Example:     "X-Memoh-Timestamp",
+				},
+				"outboundUrl": {
+					Type:        channel.FieldString,
+					Title:       "Outbound URL",
+					Description: "URL replies are POSTed to; leave empty for an inbound-only webhook",
+					Example:     "https://example.com/memoh/reply",
+				},
+				"trigger": {
+					Type:        channel.FieldEnum,
+					Title:       "Trigger",
+					Description: "Whether requests start a chat turn or only land in the inbox",
+					Enum:        []string{TriggerAll, TriggerInbox},
+					Example:     TriggerAll,
+				},
+				"textPath":             {Type: channel.FieldString, Title: "Text Path", Description: "JSON path of the message text", Example: defaultMapping.Text},
+				"messageIdPath":        {Type: channel.FieldString, Title: "Message ID Path", Example: defaultMapping.MessageID},
+				"senderIdPath":         {Type: channel.FieldString, Title: "Sender ID Path", Example: defaultMapping.SenderID},
+				"senderNamePath":       {Type: channel.FieldString, Title: "Sender Name Path", Example: defaultMapping.SenderName},
+				"conversationIdPath":   {Type: channel.FieldString, Title: "Conversation ID Path", Description: "Defaults to the sender ID when the path does not resolve", Example: defaultMapping.ConversationID},
+				"conversationNamePath": {Type: channel.FieldString, Title: "Conversation Name Path", Example: defaultMapping.ConversationName},
+				"threadIdPath":         {Type: channel.FieldString, Title: "Thread ID Path", Example: defaultMapping.ThreadID},
+				"attachmentsPath":      {Type: channel.FieldString, Title: "Attachments Path", Description: "JSON path of an array of URLs or {url, base64, name, mime, type} objects", Example: defaultMapping.Attachments},
+			},
+		},
+		UserConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"sender_id": {Type: channel.FieldString, Required: true},
+			},
+		},
+		TargetSpec: channel.TargetSpec{
+			Format: "conversation_id[#thread_id]",
+			Hints: []channel.TargetHint{
+				{Label: "Conversation", Example: "alerts"},
+				{Label: "Thread", Example: "alerts#incident-42"},
+			},
+		},
+	}
+}
+
+// NormalizeConfig validates and normalizes a webhook channel configuration map.
+func (a *WebhookAdapter) NormalizeConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeConfig(raw)
+}
+
+// NormalizeUserConfig validates and normalizes a webhook user-binding configuration map.
+func (a *WebhookAdapter) NormalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeUserConfig(raw)
+}
+
+// NormalizeTarget normalizes a webhook delivery target string.
+func (a *WebhookAdapter) NormalizeTarget(raw string) string {
+	return normalizeTarget(raw)
+}
+
+// ResolveTarget derives a delivery target from a webhook user-binding configuration.
+func (a *WebhookAdapter) ResolveTarget(userConfig map[string]any) (string, error) {
+	return resolveTarget(userConfig)
+}
+
+// MatchBinding reports whether a webhook user binding matches the given criteria.
+func (a *WebhookAdapter) MatchBinding(config map[string]any, criteria channel.BindingCriteria) bool {
+	return matchBinding(config, criteria)
+}
+
+// BuildUserConfig constructs a webhook user-binding config from an Identity.
+func (a *WebhookAdapter) BuildUserConfig(identity channel.Identity) map[string]any {
+	return buildUserConfig(identity)
+}
+
+// Connect validates the configuration. Inbound requests are pushed to
+// Handler, so there is nothing to connect to.
+func (a *WebhookAdapter) Connect(ctx context.Context, cfg channel.ChannelConfig, handler channel.InboundHandler) (channel.Connection, error) {
+	if _, err := parseConfig(cfg.Credentials); err != nil {
+		a.logger.Error("decode config failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		return nil, err
+	}
+	a.logger.Info("webhook endpoint ready", slog.String("config_id", cfg.ID))
+	return channel.NewConnection(cfg, func(context.Context) error { return nil }), nil
+}
+
+// toInboundMessage maps a decoded request body into an InboundMessage.
+func toInboundMessage(cfg channel.ChannelConfig, webhookCfg Config, doc any, now time.Time) (channel.InboundMessage, error) {
+	mapping := webhookCfg.Mapping
+	text := lookupString(doc, mapping.Text)
+	attachments := lookupAttachments(doc, mapping.Attachments)
+	if text == "" && len(attachments) == 0 {
+		return channel.InboundMessage{}, fmt.Errorf("no text at %q and no attachments at %q", mapping.Text, mapping.Attachments)
+	}
+	senderID := lookupString(doc, mapping.SenderID)
+	if senderID == "" {
+		senderID = Type.String()
+	}
+	senderName := lookupString(doc, mapping.SenderName)
+	if senderName == "" {
+		senderName = senderID
+	}
+	conversationID := lookupString(doc, mapping.ConversationID)
+	if conversationID == "" {
+		conversationID = senderID
+	}
+	threadID := lookupString(doc, mapping.ThreadID)
+	messageID := lookupString(doc, mapping.MessageID)
+	if messageID == "" {
+		messageID = newDeliveryID()
+	}
+	var thread *channel.ThreadRef
+	if threadID != "" {
+		thread = &channel.ThreadRef{ID: threadID}
+	}
+	return channel.InboundMessage{
+		Channel: Type,
+		Message: channel.Message{
+			ID:          messageID,
+			Format:      channel.MessageFormatPlain,
+			Text:        text,
+			Attachments: attachments,
+			Thread:      thread,
+		},
+		BotID:       cfg.BotID,
+		ReplyTarget: joinTarget(conversationID, threadID),
+		Sender: channel.Identity{
+			SubjectID:   senderID,
+			DisplayName: senderName,
+			Attributes: map[string]string{
+				"sender_id": senderID,
+				"username":  senderName,
+			},
+		},
+		Conversation: channel.Conversation{
+			ID:       conversationID,
+			Type:     conversationType,
+			Name:     lookupString(doc, mapping.ConversationName),
+			ThreadID: threadID,
+		},
+		ReceivedAt: now,
+		Source:     "webhook",
+		Metadata: map[string]any{
+			"is_mentioned": webhookCfg.Trigger == TriggerAll,
+		},
+	}, nil
+}
+
+// outboundPayload is the JSON body POSTed to the outbound URL.
+type outboundPayload struct {
+	ConfigID string    `json:"config_id"`
+	BotID    string    `json:"bot_id"`
+	SentAt   time.Time `json:"sent_at"`
+	channel.OutboundMessage
+}
+
+// Send POSTs the message to the outbound URL, retrying network errors, 429
+// and 5xx responses. The request is signed like inbound requests and
+// carries a delivery ID that stays the same across retries.
+func (a *WebhookAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, msg channel.OutboundMessage) error {
+	webhookCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	if webhookCfg.OutboundURL == "" {
+		return fmt.Errorf("webhook outboundUrl is not configured")
+	}
+	if msg.Message.IsEmpty() {
+		return fmt.Errorf("message is required")
+	}
+	conversationID, threadID := splitTarget(msg.Target)
+	if conversationID == "" {
+		return fmt.Errorf("webhook target is required")
+	}
+	message := msg.Message
+	if threadID != "" && message.Thread == nil {
+		message.Thread = &channel.ThreadRef{ID: threadID}
+	}
+	message.Attachments, err = a.inlineAttachments(ctx, cfg.BotID, message.Attachments)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(outboundPayload{
+		ConfigID:        cfg.ID,
+		BotID:           cfg.BotID,
+		SentAt:          time.Now().UTC(),
+		OutboundMessage: channel.OutboundMessage{Target: conversationID, Message: message},
+	})
+	if err != nil {
+		return fmt.Errorf("webhook encode message: %w", err)
+	}
+	deliveryID := newDeliveryID()
+	delay := a.retryDelay
+	for attempt := 1; ; attempt++ {
+		retry, err := a.post(ctx, webhookCfg, deliveryID, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= outboundMaxAttempts {
+			return err
+		}
+		a.logger.Warn("webhook delivery failed, retrying",
+			slog.String("config_id", cfg.ID),
+			slog.String("delivery_id", deliveryID),
+			slog.Int("attempt", attempt),
+			slog.Any("error", err),
+		)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth
+// retrying.
+func (a *WebhookAdapter) post(ctx context.Context, webhookCfg Config, deliveryID string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookCfg.OutboundURL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("webhook build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "memoh-webhook")
+	req.Header.Set("X-Memoh-Delivery", deliveryID)
+	signRequest(webhookCfg, req, body, time.Now())
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("webhook post: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook post: unexpected status %d", resp.StatusCode)
+}
+
+// inlineAttachments embeds stored media as base64 data URLs, since the
+// receiving system cannot resolve content hashes.
+func (a *WebhookAdapter) inlineAttachments(ctx context.Context, botID string, attachments []channel.Attachment) ([]channel.Attachment, error) {
+	if len(attachments) == 0 {
+		return attachments, nil
+	}
+	result := make([]channel.Attachment, 0, len(attachments))
+	for _, att := range attachments {
+		contentHash := strings.TrimSpace(att.ContentHash)
+		if contentHash == "" || strings.TrimSpace(att.URL) != "" || strings.TrimSpace(att.Base64) != "" || a.assets == nil || botID == "" {
+			result = append(result, att)
+			continue
+		}
+		reader, asset, err := a.assets.Open(ctx, botID, contentHash)
+		if err != nil {
+			return nil, fmt.Errorf("open attachment %s: %w", contentHash, err)
+		}
+		data, err := media.ReadAllWithLimit(reader, outboundMaxInlineBytes)
+		_ = reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read attachment %s: %w", contentHash, err)
+		}
+		mime := strings.TrimSpace(att.Mime)
+		if mime == "" {
+			mime = strings.TrimSpace(asset.Mime)
+		}
+		if mime == "" {
+			mime = "application/octet-stream"
+		}
+		att.Mime = mime
+		att.Size = int64(len(data))
+		att.Base64 = "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data)
+		result = append(result, att)
+	}
+	return result, nil
+}
+
+// OpenStream opens a stream that collects the reply and delivers it in one
+// request once the final message arrives.
+func (a *WebhookAdapter) OpenStream(ctx context.Context, cfg channel.ChannelConfig, target string, opts channel.StreamOptions) (channel.OutboundStream, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil, fmt.Errorf("webhook target is required")
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+	reply := opts.Reply
+	if reply == nil && strings.TrimSpace(opts.SourceMessageID) != "" {
+		reply = &channel.ReplyRef{Target: target, MessageID: strings.TrimSpace(opts.SourceMessageID)}
+	}
+	return &webhookOutboundStream{
+		adapter: a,
+		cfg:     cfg,
+		target:  target,
+		reply:   reply,
+	}, nil
+}
+
+func newDeliveryID() string {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(buf[:])
+}
@@ -0,0 +1,178 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+type recordedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newOutboundServer(t *testing.T, statuses ...int) (*httptest.Server, func() []recordedRequest) {
+	t.Helper()
+	var (
+		mu       sync.Mutex
+		requests []recordedRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, recordedRequest{header: r.Header.Clone(), body: body})
+		index := len(requests) - 1
+		mu.Unlock()
+		status := http.StatusOK
+		if index < len(statuses) {
+			status = statuses[index]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []recordedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]recordedRequest(nil), requests...)
+	}
+}
+
+func newTestAdapter() *WebhookAdapter {
+	adapter := NewWebhookAdapter(nil)
+	adapter.retryDelay = time.Millisecond
+	return adapter
+}
+
+func TestSendPostsSignedPayload(t *testing.T) {
+	t.Parallel()
+
+	srv, requests := newOutboundServer(t)
+	cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1", Credentials: map[string]any{"secret": "s3cret", "outboundUrl": srv.URL}}
+
+	err := newTestAdapter().Send(context.Background(), cfg, channel.OutboundMessage{
+		Target:  "builds#run-7",
+		Message: channel.Message{Format: channel.MessageFormatMarkdown, Text: "**fixed**"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := requests()
+	if len(got) != 1 {
+		t.Fatalf("expected one request, got %d", len(got))
+	}
+	if sig := got[0].header.Get(defaultSignatureHeader); sig != "sha256="+sign("s3cret", "", got[0].body) {
+		t.Fatalf("unexpected signature %q", sig)
+	}
+	var payload struct {
+		ConfigID string          `json:"config_id"`
+		BotID    string          `json:"bot_id"`
+		Target   string          `json:"target"`
+		Message  channel.Message `json:"message"`
+	}
+	if err := json.Unmarshal(got[0].body, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.ConfigID != "cfg-1" || payload.BotID != "bot-1" || payload.Target != "builds" {
+		t.Fatalf("unexpected payload: %s", got[0].body)
+	}
+	if payload.Message.Text != "**fixed**" || payload.Message.Thread == nil || payload.Message.Thread.ID != "run-7" {
+		t.Fatalf("unexpected message: %s", got[0].body)
+	}
+}
+
+func TestSendRetriesServerErrors(t *testing.T) {
+	t.Parallel()
+
+	srv, requests := newOutboundServer(t, http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK)
+	cfg := channel.ChannelConfig{ID: "cfg-1", Credentials: map[string]any{"secret": "s", "outboundUrl": srv.URL}}
+
+	err := newTestAdapter().Send(context.Background(), cfg, channel.OutboundMessage{Target: "builds", Message: channel.Message{Text: "hi"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := requests()
+	if len(got) != 3 {
+		t.Fatalf("expected three attempts, got %d", len(got))
+	}
+	delivery := got[0].header.Get("X-Memoh-Delivery")
+	if delivery == "" || got[2].header.Get("X-Memoh-Delivery") != delivery {
+		t.Fatalf("delivery id should be stable across retries")
+	}
+}
+
+func TestSendGivesUp(t *testing.T) {
+	t.Parallel()
+
+	srv, requests := newOutboundServer(t, http.StatusBadRequest)
+	cfg := channel.ChannelConfig{Credentials: map[string]any{"secret": "s", "outboundUrl": srv.URL}}
+	adapter := newTestAdapter()
+
+	if err := adapter.Send(context.Background(), cfg, channel.OutboundMessage{Target: "builds", Message: channel.Message{Text: "hi"}}); err == nil {
+		t.Fatal("expected error for a client error response")
+	}
+	if len(requests()) != 1 {
+		t.Fatalf("client errors should not be retried, got %d attempts", len(requests()))
+	}
+
+	srv, requests = newOutboundServer(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK)
+	cfg.Credentials["outboundUrl"] = srv.URL
+	if err := adapter.Send(context.Background(), cfg, channel.OutboundMessage{Target: "builds", Message: channel.Message{Text: "hi"}}); err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if len(requests()) != outboundMaxAttempts {
+		t.Fatalf("expected %d attempts, got %d", outboundMaxAttempts, len(requests()))
+	}
+}
+
+func TestSendRequiresOutboundURL(t *testing.T) {
+	t.Parallel()
+
+	cfg := channel.ChannelConfig{Credentials: map[string]any{"secret": "s"}}
+	if err := newTestAdapter().Send(context.Background(), cfg, channel.OutboundMessage{Target: "builds", Message: channel.Message{Text: "hi"}}); err == nil {
+		t.Fatal("expected error without an outbound URL")
+	}
+}
+
+func TestStreamSendsFinalMessage(t *testing.T) {
+	t.Parallel()
+
+	srv, requests := newOutboundServer(t)
+	cfg := channel.ChannelConfig{Credentials: map[string]any{"secret": "s", "outboundUrl": srv.URL}}
+	ctx := context.Background()
+
+	stream, err := newTestAdapter().OpenStream(ctx, cfg, "builds", channel.StreamOptions{SourceMessageID: "evt-1"})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	for _, delta := range []string{"all ", "green"} {
+		if err := stream.Push(ctx, channel.StreamEvent{Type: channel.StreamEventDelta, Delta: delta}); err != nil {
+			t.Fatalf("push delta: %v", err)
+		}
+	}
+	if err := stream.Push(ctx, channel.StreamEvent{Type: channel.StreamEventFinal, Final: &channel.StreamFinalizePayload{}}); err != nil {
+		t.Fatalf("push final: %v", err)
+	}
+	if err := stream.Close(ctx); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	got := requests()
+	if len(got) != 1 {
+		t.Fatalf("expected one request, got %d", len(got))
+	}
+	var payload struct {
+		Message channel.Message `json:"message"`
+	}
+	if err := json.Unmarshal(got[0].body, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.Message.Text != "all green" || payload.Message.Reply == nil || payload.Message.Reply.MessageID != "evt-1" {
+		t.Fatalf("unexpected payload: %s", got[0].body)
+	}
+}
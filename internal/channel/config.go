@@ -4,8 +4,23 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
+// maxConfigNameLength bounds the name of a channel config.
+const maxConfigNameLength = 64
+
+// NormalizeConfigName trims a channel config name and validates its length.
+// The empty name selects the default config of a channel type.
+func NormalizeConfigName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) > maxConfigNameLength {
+		return "", fmt.Errorf("channel config name must be at most %d characters", maxConfigNameLength)
+	}
+	return name, nil
+}
+
 // DecodeConfigMap unmarshals a JSON byte slice into a string-keyed map.
 func DecodeConfigMap(raw []byte) (map[string]any, error) {
 	if len(raw) == 0 {
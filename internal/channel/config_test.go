@@ -2,6 +2,7 @@ package channel_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/memohai/memoh/internal/channel"
@@ -137,3 +138,21 @@ func TestNormalizeChannelUserConfigRequiresUser(t *testing.T) {
 		t.Fatalf("expected error, got nil")
 	}
 }
+
+func TestNormalizeConfigName(t *testing.T) {
+	t.Parallel()
+
+	name, err := channel.NormalizeConfigName("  work ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "work" {
+		t.Fatalf("unexpected name: %q", name)
+	}
+	if name, err := channel.NormalizeConfigName(""); err != nil || name != "" {
+		t.Fatalf("empty name should select the default config: %q %v", name, err)
+	}
+	if _, err := channel.NormalizeConfigName(strings.Repeat("é", 65)); err == nil {
+		t.Fatal("expected error for a long name")
+	}
+}
@@ -224,40 +224,13 @@ func (m *Manager) EnsureConnection(ctx context.Context, cfg ChannelConfig) error
 	return m.ensureConnection(ctx, cfg)
 }
 
-// RemoveConnection stops and removes connections matching the given bot and channel type.
-func (m *Manager) RemoveConnection(ctx context.Context, botID string, channelType ChannelType) {
-	botID = strings.TrimSpace(botID)
-	if botID == "" {
+// RemoveConnection stops and removes the connection of the given config.
+func (m *Manager) RemoveConnection(ctx context.Context, configID string) {
+	configID = strings.TrimSpace(configID)
+	if configID == "" {
 		return
 	}
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	for id, entry := range m.connections {
-		if entry == nil || entry.config.BotID != botID || entry.config.ChannelType != channelType {
-			continue
-		}
-		if entry.connection != nil {
-			if m.logger != nil {
-				m.logger.Info(
-					"connection remove",
-					slog.String("bot_id", botID),
-					slog.String("channel", channelType.String()),
-					slog.String("config_id", id),
-				)
-			}
-			if err := entry.connection.Stop(ctx); err != nil && !errors.Is(err, ErrStopNotSupported) && m.logger != nil {
-				m.logger.Warn(
-					"connection stop failed",
-					slog.String("bot_id", botID),
-					slog.String("channel", channelType.String()),
-					slog.String("config_id", id),
-					slog.Any("error", err),
-				)
-			}
-		}
-		delete(m.connections, id)
-		delete(m.connectionMeta, id)
-	}
+	_ = m.removeConnection(ctx, configID)
 }
 
 func (m *Manager) removeConnection(ctx context.Context, configID string) error {
@@ -381,6 +354,7 @@ func (m *Manager) setConnectionStatusLocked(cfg ChannelConfig, running bool, che
 	previous, hasPrevious := m.connectionMeta[cfg.ID]
 	status := ConnectionStatus{
 		ConfigID:    cfg.ID,
+		ConfigName:  cfg.Name,
 		BotID:       cfg.BotID,
 		ChannelType: cfg.ChannelType,
 		Running:     running,
@@ -50,6 +50,12 @@ type mediaIngestor interface {
 	IngestContainerFile(ctx context.Context, botID, containerPath string) (media.Asset, error)
 }
 
+// ActivityRecorder records inbound messages as bot activity, which keeps the
+// bot container from being suspended while idle.
+type ActivityRecorder interface {
+	TouchActivity(botID string)
+}
+
 // ChannelInboundProcessor routes channel inbound messages to the chat gateway.
 type ChannelInboundProcessor struct {
 	runner        flow.Runner
@@ -63,6 +69,7 @@ type ChannelInboundProcessor struct {
 	tokenTTL      time.Duration
 	identity      *IdentityResolver
 	observer      channel.StreamObserver
+	activity      ActivityRecorder
 }
 
 // NewChannelInboundProcessor creates a processor with channel identity-based resolution.
@@ -134,6 +141,15 @@ func (p *ChannelInboundProcessor) SetInboxService(service *inbox.Service) {
 	p.inboxService = service
 }
 
+// SetActivityRecorder configures where inbound messages are recorded as bot
+// activity.
+func (p *ChannelInboundProcessor) SetActivityRecorder(recorder ActivityRecorder) {
+	if p == nil {
+		return
+	}
+	p.activity = recorder
+}
+
 // HandleInbound processes an inbound channel message through identity resolution and chat gateway.
 func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, sender channel.StreamReplySender) error {
 	if p.runner == nil {
@@ -159,6 +175,9 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 		}
 		return nil
 	}
+	if p.activity != nil {
+		p.activity.TouchActivity(cfg.BotID)
+	}
 	state, err := p.requireIdentity(ctx, cfg, msg)
 	if err != nil {
 		return err
@@ -9,17 +9,18 @@ import (
 )
 
 // LifecycleStore persists channel configs for lifecycle orchestration.
+// Configs are keyed by bot, channel type and name.
 type LifecycleStore interface {
-	ResolveEffectiveConfig(ctx context.Context, botID string, channelType ChannelType) (ChannelConfig, error)
-	UpsertConfig(ctx context.Context, botID string, channelType ChannelType, req UpsertConfigRequest) (ChannelConfig, error)
-	UpdateConfigDisabled(ctx context.Context, botID string, channelType ChannelType, disabled bool) (ChannelConfig, error)
-	DeleteConfig(ctx context.Context, botID string, channelType ChannelType) error
+	ResolveConfig(ctx context.Context, botID string, channelType ChannelType, name string) (ChannelConfig, error)
+	UpsertConfig(ctx context.Context, botID string, channelType ChannelType, name string, req UpsertConfigRequest) (ChannelConfig, error)
+	UpdateConfigDisabled(ctx context.Context, botID string, channelType ChannelType, name string, disabled bool) (ChannelConfig, error)
+	DeleteConfig(ctx context.Context, botID string, channelType ChannelType, name string) error
 }
 
 // ConnectionController controls runtime channel connections.
 type ConnectionController interface {
 	EnsureConnection(ctx context.Context, cfg ChannelConfig) error
-	RemoveConnection(ctx context.Context, botID string, channelType ChannelType)
+	RemoveConnection(ctx context.Context, configID string)
 }
 
 // ErrEnableChannelFailed indicates that enabling the channel (e.g. EnsureConnection) failed.
@@ -39,13 +40,17 @@ func NewLifecycle(store LifecycleStore, controller ConnectionController) *Lifecy
 	}
 }
 
-// UpsertBotChannelConfig updates config and applies connection lifecycle.
+// UpsertBotChannelConfig updates the named config and applies connection lifecycle.
 // For disabled=true, it stores config and stops any active connection.
 // For disabled=false, it stores config then starts connection; on start failure it rolls back.
-func (s *Lifecycle) UpsertBotChannelConfig(ctx context.Context, botID string, channelType ChannelType, req UpsertConfigRequest) (ChannelConfig, error) {
+func (s *Lifecycle) UpsertBotChannelConfig(ctx context.Context, botID string, channelType ChannelType, name string, req UpsertConfigRequest) (ChannelConfig, error) {
 	if s.store == nil {
 		return ChannelConfig{}, fmt.Errorf("channel lifecycle store not configured")
 	}
+	name, err := NormalizeConfigName(name)
+	if err != nil {
+		return ChannelConfig{}, err
+	}
 	disabled := false
 	if req.Disabled != nil {
 		disabled = *req.Disabled
@@ -54,25 +59,25 @@ func (s *Lifecycle) UpsertBotChannelConfig(ctx context.Context, botID string, ch
 		return ChannelConfig{}, fmt.Errorf("channel connection controller not configured")
 	}
 
-	previous, hadPrevious, err := s.getPreviousConfig(ctx, botID, channelType)
+	previous, hadPrevious, err := s.getPreviousConfig(ctx, botID, channelType, name)
 	if err != nil {
 		return ChannelConfig{}, err
 	}
 
-	updated, err := s.store.UpsertConfig(ctx, botID, channelType, req)
+	updated, err := s.store.UpsertConfig(ctx, botID, channelType, name, req)
 	if err != nil {
 		return ChannelConfig{}, err
 	}
 
 	if disabled {
 		if s.controller != nil {
-			s.controller.RemoveConnection(ctx, botID, channelType)
+			s.controller.RemoveConnection(ctx, updated.ID)
 		}
 		return updated, nil
 	}
 
 	if err := s.controller.EnsureConnection(ctx, updated); err != nil {
-		if rollbackErr := s.rollbackUpsert(ctx, botID, channelType, hadPrevious, previous); rollbackErr != nil {
+		if rollbackErr := s.rollbackUpsert(ctx, botID, channelType, name, updated.ID, hadPrevious, previous); rollbackErr != nil {
 			return ChannelConfig{}, fmt.Errorf("%w (rollback failed: %v): %w", ErrEnableChannelFailed, rollbackErr, err)
 		}
 		return ChannelConfig{}, fmt.Errorf("%w: %w", ErrEnableChannelFailed, err)
@@ -80,22 +85,26 @@ func (s *Lifecycle) UpsertBotChannelConfig(ctx context.Context, botID string, ch
 	return updated, nil
 }
 
-// DeleteBotChannelConfig removes persisted config and stops active runtime connection.
-func (s *Lifecycle) DeleteBotChannelConfig(ctx context.Context, botID string, channelType ChannelType) error {
+// DeleteBotChannelConfig removes the named config and stops its active runtime connection.
+func (s *Lifecycle) DeleteBotChannelConfig(ctx context.Context, botID string, channelType ChannelType, name string) error {
 	if s.store == nil {
 		return fmt.Errorf("channel lifecycle store not configured")
 	}
-	if err := s.store.DeleteConfig(ctx, botID, channelType); err != nil {
+	existing, found, err := s.getPreviousConfig(ctx, botID, channelType, name)
+	if err != nil {
+		return err
+	}
+	if err := s.store.DeleteConfig(ctx, botID, channelType, name); err != nil {
 		return err
 	}
-	if s.controller != nil {
-		s.controller.RemoveConnection(ctx, botID, channelType)
+	if found && s.controller != nil {
+		s.controller.RemoveConnection(ctx, existing.ID)
 	}
 	return nil
 }
 
-// SetBotChannelStatus updates only the disabled status and applies runtime lifecycle.
-func (s *Lifecycle) SetBotChannelStatus(ctx context.Context, botID string, channelType ChannelType, disabled bool) (ChannelConfig, error) {
+// SetBotChannelStatus updates only the disabled status of the named config and applies runtime lifecycle.
+func (s *Lifecycle) SetBotChannelStatus(ctx context.Context, botID string, channelType ChannelType, name string, disabled bool) (ChannelConfig, error) {
 	if s.store == nil {
 		return ChannelConfig{}, fmt.Errorf("channel lifecycle store not configured")
 	}
@@ -103,27 +112,27 @@ func (s *Lifecycle) SetBotChannelStatus(ctx context.Context, botID string, chann
 		return ChannelConfig{}, fmt.Errorf("channel connection controller not configured")
 	}
 
-	updated, err := s.store.UpdateConfigDisabled(ctx, botID, channelType, disabled)
+	updated, err := s.store.UpdateConfigDisabled(ctx, botID, channelType, name, disabled)
 	if err != nil {
 		return ChannelConfig{}, err
 	}
 	if disabled {
-		s.controller.RemoveConnection(ctx, botID, channelType)
+		s.controller.RemoveConnection(ctx, updated.ID)
 		return updated, nil
 	}
 
 	if err := s.controller.EnsureConnection(ctx, updated); err != nil {
-		if _, rollbackErr := s.store.UpdateConfigDisabled(ctx, botID, channelType, true); rollbackErr != nil {
+		if _, rollbackErr := s.store.UpdateConfigDisabled(ctx, botID, channelType, name, true); rollbackErr != nil {
 			return ChannelConfig{}, fmt.Errorf("%w (status rollback failed: %v): %w", ErrEnableChannelFailed, rollbackErr, err)
 		}
-		s.controller.RemoveConnection(ctx, botID, channelType)
+		s.controller.RemoveConnection(ctx, updated.ID)
 		return ChannelConfig{}, fmt.Errorf("%w: %w", ErrEnableChannelFailed, err)
 	}
 	return updated, nil
 }
 
-func (s *Lifecycle) getPreviousConfig(ctx context.Context, botID string, channelType ChannelType) (ChannelConfig, bool, error) {
-	cfg, err := s.store.ResolveEffectiveConfig(ctx, botID, channelType)
+func (s *Lifecycle) getPreviousConfig(ctx context.Context, botID string, channelType ChannelType, name string) (ChannelConfig, bool, error) {
+	cfg, err := s.store.ResolveConfig(ctx, botID, channelType, name)
 	if err == nil {
 		return cfg, true, nil
 	}
@@ -133,19 +142,19 @@ func (s *Lifecycle) getPreviousConfig(ctx context.Context, botID string, channel
 	return ChannelConfig{}, false, err
 }
 
-func (s *Lifecycle) rollbackUpsert(ctx context.Context, botID string, channelType ChannelType, hadPrevious bool, previous ChannelConfig) error {
+func (s *Lifecycle) rollbackUpsert(ctx context.Context, botID string, channelType ChannelType, name, updatedID string, hadPrevious bool, previous ChannelConfig) error {
 	if !hadPrevious {
-		if err := s.store.DeleteConfig(ctx, botID, channelType); err != nil {
+		if err := s.store.DeleteConfig(ctx, botID, channelType, name); err != nil {
 			return err
 		}
 		if s.controller != nil {
-			s.controller.RemoveConnection(ctx, botID, channelType)
+			s.controller.RemoveConnection(ctx, updatedID)
 		}
 		return nil
 	}
 
 	restoreReq := upsertRequestFromConfig(previous)
-	restored, err := s.store.UpsertConfig(ctx, botID, channelType, restoreReq)
+	restored, err := s.store.UpsertConfig(ctx, botID, channelType, name, restoreReq)
 	if err != nil {
 		return err
 	}
@@ -153,7 +162,7 @@ func (s *Lifecycle) rollbackUpsert(ctx context.Context, botID string, channelTyp
 		return nil
 	}
 	if restored.Disabled {
-		s.controller.RemoveConnection(ctx, botID, channelType)
+		s.controller.RemoveConnection(ctx, restored.ID)
 		return nil
 	}
 	return s.controller.EnsureConnection(ctx, restored)
@@ -3,47 +3,48 @@ package channel
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 )
 
 type fakeLifecycleStore struct {
-	resolveFunc func(ctx context.Context, botID string, channelType ChannelType) (ChannelConfig, error)
-	upsertFunc  func(ctx context.Context, botID string, channelType ChannelType, req UpsertConfigRequest) (ChannelConfig, error)
-	statusFunc  func(ctx context.Context, botID string, channelType ChannelType, disabled bool) (ChannelConfig, error)
-	deleteFunc  func(ctx context.Context, botID string, channelType ChannelType) error
+	resolveFunc func(ctx context.Context, botID string, channelType ChannelType, name string) (ChannelConfig, error)
+	upsertFunc  func(ctx context.Context, botID string, channelType ChannelType, name string, req UpsertConfigRequest) (ChannelConfig, error)
+	statusFunc  func(ctx context.Context, botID string, channelType ChannelType, name string, disabled bool) (ChannelConfig, error)
+	deleteFunc  func(ctx context.Context, botID string, channelType ChannelType, name string) error
 }
 
-func (f *fakeLifecycleStore) ResolveEffectiveConfig(ctx context.Context, botID string, channelType ChannelType) (ChannelConfig, error) {
+func (f *fakeLifecycleStore) ResolveConfig(ctx context.Context, botID string, channelType ChannelType, name string) (ChannelConfig, error) {
 	if f.resolveFunc == nil {
 		return ChannelConfig{}, ErrChannelConfigNotFound
 	}
-	return f.resolveFunc(ctx, botID, channelType)
+	return f.resolveFunc(ctx, botID, channelType, name)
 }
 
-func (f *fakeLifecycleStore) UpsertConfig(ctx context.Context, botID string, channelType ChannelType, req UpsertConfigRequest) (ChannelConfig, error) {
+func (f *fakeLifecycleStore) UpsertConfig(ctx context.Context, botID string, channelType ChannelType, name string, req UpsertConfigRequest) (ChannelConfig, error) {
 	if f.upsertFunc == nil {
 		return ChannelConfig{}, nil
 	}
-	return f.upsertFunc(ctx, botID, channelType, req)
+	return f.upsertFunc(ctx, botID, channelType, name, req)
 }
 
-func (f *fakeLifecycleStore) UpdateConfigDisabled(ctx context.Context, botID string, channelType ChannelType, disabled bool) (ChannelConfig, error) {
+func (f *fakeLifecycleStore) UpdateConfigDisabled(ctx context.Context, botID string, channelType ChannelType, name string, disabled bool) (ChannelConfig, error) {
 	if f.statusFunc == nil {
 		return ChannelConfig{}, ErrChannelConfigNotFound
 	}
-	return f.statusFunc(ctx, botID, channelType, disabled)
+	return f.statusFunc(ctx, botID, channelType, name, disabled)
 }
 
-func (f *fakeLifecycleStore) DeleteConfig(ctx context.Context, botID string, channelType ChannelType) error {
+func (f *fakeLifecycleStore) DeleteConfig(ctx context.Context, botID string, channelType ChannelType, name string) error {
 	if f.deleteFunc == nil {
 		return nil
 	}
-	return f.deleteFunc(ctx, botID, channelType)
+	return f.deleteFunc(ctx, botID, channelType, name)
 }
 
 type fakeConnectionController struct {
 	ensureFunc func(ctx context.Context, cfg ChannelConfig) error
-	removeFunc func(ctx context.Context, botID string, channelType ChannelType)
+	removeFunc func(ctx context.Context, configID string)
 }
 
 func (f *fakeConnectionController) EnsureConnection(ctx context.Context, cfg ChannelConfig) error {
@@ -53,11 +54,11 @@ func (f *fakeConnectionController) EnsureConnection(ctx context.Context, cfg Cha
 	return f.ensureFunc(ctx, cfg)
 }
 
-func (f *fakeConnectionController) RemoveConnection(ctx context.Context, botID string, channelType ChannelType) {
+func (f *fakeConnectionController) RemoveConnection(ctx context.Context, configID string) {
 	if f.removeFunc == nil {
 		return
 	}
-	f.removeFunc(ctx, botID, channelType)
+	f.removeFunc(ctx, configID)
 }
 
 func TestLifecycleUpsertDisabledRemovesConnection(t *testing.T) {
@@ -65,19 +66,19 @@ func TestLifecycleUpsertDisabledRemovesConnection(t *testing.T) {
 
 	removeCalled := false
 	store := &fakeLifecycleStore{
-		upsertFunc: func(ctx context.Context, botID string, channelType ChannelType, req UpsertConfigRequest) (ChannelConfig, error) {
+		upsertFunc: func(ctx context.Context, botID string, channelType ChannelType, name string, req UpsertConfigRequest) (ChannelConfig, error) {
 			return ChannelConfig{ID: "cfg-1", BotID: botID, ChannelType: channelType, Disabled: true}, nil
 		},
 	}
 	controller := &fakeConnectionController{
-		removeFunc: func(ctx context.Context, botID string, channelType ChannelType) {
+		removeFunc: func(ctx context.Context, configID string) {
 			removeCalled = true
 		},
 	}
 	service := NewLifecycle(store, controller)
 	disabled := true
 
-	cfg, err := service.UpsertBotChannelConfig(context.Background(), "bot-1", ChannelType("telegram"), UpsertConfigRequest{
+	cfg, err := service.UpsertBotChannelConfig(context.Background(), "bot-1", ChannelType("telegram"), "", UpsertConfigRequest{
 		Credentials: map[string]any{"botToken": "x"},
 		Disabled:    &disabled,
 	})
@@ -112,10 +113,10 @@ func TestLifecycleUpsertEnableFailureRollsBackToPrevious(t *testing.T) {
 	upsertCalls := 0
 	ensureCalls := 0
 	store := &fakeLifecycleStore{
-		resolveFunc: func(ctx context.Context, botID string, channelType ChannelType) (ChannelConfig, error) {
+		resolveFunc: func(ctx context.Context, botID string, channelType ChannelType, name string) (ChannelConfig, error) {
 			return previous, nil
 		},
-		upsertFunc: func(ctx context.Context, botID string, channelType ChannelType, req UpsertConfigRequest) (ChannelConfig, error) {
+		upsertFunc: func(ctx context.Context, botID string, channelType ChannelType, name string, req UpsertConfigRequest) (ChannelConfig, error) {
 			upsertCalls++
 			if upsertCalls == 1 {
 				return newConfig, nil
@@ -138,7 +139,7 @@ func TestLifecycleUpsertEnableFailureRollsBackToPrevious(t *testing.T) {
 	service := NewLifecycle(store, controller)
 	enabled := false
 
-	_, err := service.UpsertBotChannelConfig(context.Background(), "bot-1", ChannelType("telegram"), UpsertConfigRequest{
+	_, err := service.UpsertBotChannelConfig(context.Background(), "bot-1", ChannelType("telegram"), "", UpsertConfigRequest{
 		Credentials: map[string]any{"botToken": "new"},
 		Disabled:    &enabled,
 	})
@@ -158,10 +159,10 @@ func TestLifecycleUpsertEnableFailureWithoutPreviousDeletesNewConfig(t *testing.
 
 	deleteCalls := 0
 	store := &fakeLifecycleStore{
-		resolveFunc: func(ctx context.Context, botID string, channelType ChannelType) (ChannelConfig, error) {
+		resolveFunc: func(ctx context.Context, botID string, channelType ChannelType, name string) (ChannelConfig, error) {
 			return ChannelConfig{}, ErrChannelConfigNotFound
 		},
-		upsertFunc: func(ctx context.Context, botID string, channelType ChannelType, req UpsertConfigRequest) (ChannelConfig, error) {
+		upsertFunc: func(ctx context.Context, botID string, channelType ChannelType, name string, req UpsertConfigRequest) (ChannelConfig, error) {
 			return ChannelConfig{
 				ID:          "cfg-new",
 				BotID:       botID,
@@ -169,7 +170,7 @@ func TestLifecycleUpsertEnableFailureWithoutPreviousDeletesNewConfig(t *testing.
 				Credentials: map[string]any{"botToken": "new"},
 			}, nil
 		},
-		deleteFunc: func(ctx context.Context, botID string, channelType ChannelType) error {
+		deleteFunc: func(ctx context.Context, botID string, channelType ChannelType, name string) error {
 			deleteCalls++
 			return nil
 		},
@@ -182,7 +183,7 @@ func TestLifecycleUpsertEnableFailureWithoutPreviousDeletesNewConfig(t *testing.
 	service := NewLifecycle(store, controller)
 	enabled := false
 
-	_, err := service.UpsertBotChannelConfig(context.Background(), "bot-1", ChannelType("telegram"), UpsertConfigRequest{
+	_, err := service.UpsertBotChannelConfig(context.Background(), "bot-1", ChannelType("telegram"), "", UpsertConfigRequest{
 		Credentials: map[string]any{"botToken": "new"},
 		Disabled:    &enabled,
 	})
@@ -197,24 +198,32 @@ func TestLifecycleUpsertEnableFailureWithoutPreviousDeletesNewConfig(t *testing.
 func TestLifecycleDeleteStopsConnection(t *testing.T) {
 	t.Parallel()
 
-	removeCalled := false
+	removedID := ""
+	deletedName := ""
 	store := &fakeLifecycleStore{
-		deleteFunc: func(ctx context.Context, botID string, channelType ChannelType) error {
+		resolveFunc: func(ctx context.Context, botID string, channelType ChannelType, name string) (ChannelConfig, error) {
+			return ChannelConfig{ID: "cfg-work", BotID: botID, ChannelType: channelType, Name: name}, nil
+		},
+		deleteFunc: func(ctx context.Context, botID string, channelType ChannelType, name string) error {
+			deletedName = name
 			return nil
 		},
 	}
 	controller := &fakeConnectionController{
-		removeFunc: func(ctx context.Context, botID string, channelType ChannelType) {
-			removeCalled = true
+		removeFunc: func(ctx context.Context, configID string) {
+			removedID = configID
 		},
 	}
 	service := NewLifecycle(store, controller)
 
-	if err := service.DeleteBotChannelConfig(context.Background(), "bot-1", ChannelType("telegram")); err != nil {
+	if err := service.DeleteBotChannelConfig(context.Background(), "bot-1", ChannelType("telegram"), "work"); err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if !removeCalled {
-		t.Fatalf("expected remove connection to be called")
+	if deletedName != "work" {
+		t.Fatalf("expected named config to be deleted, got %q", deletedName)
+	}
+	if removedID != "cfg-work" {
+		t.Fatalf("expected connection of cfg-work to be removed, got %q", removedID)
 	}
 }
 
@@ -223,7 +232,7 @@ func TestLifecycleSetBotChannelStatusDisable(t *testing.T) {
 
 	removeCalled := false
 	store := &fakeLifecycleStore{
-		statusFunc: func(ctx context.Context, botID string, channelType ChannelType, disabled bool) (ChannelConfig, error) {
+		statusFunc: func(ctx context.Context, botID string, channelType ChannelType, name string, disabled bool) (ChannelConfig, error) {
 			if !disabled {
 				t.Fatalf("expected disabled=true update")
 			}
@@ -231,13 +240,13 @@ func TestLifecycleSetBotChannelStatusDisable(t *testing.T) {
 		},
 	}
 	controller := &fakeConnectionController{
-		removeFunc: func(ctx context.Context, botID string, channelType ChannelType) {
+		removeFunc: func(ctx context.Context, configID string) {
 			removeCalled = true
 		},
 	}
 	service := NewLifecycle(store, controller)
 
-	cfg, err := service.SetBotChannelStatus(context.Background(), "bot-1", ChannelType("telegram"), true)
+	cfg, err := service.SetBotChannelStatus(context.Background(), "bot-1", ChannelType("telegram"), "", true)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -255,7 +264,7 @@ func TestLifecycleSetBotChannelStatusEnableFailureRollsBack(t *testing.T) {
 	statusCalls := 0
 	removeCalled := false
 	store := &fakeLifecycleStore{
-		statusFunc: func(ctx context.Context, botID string, channelType ChannelType, disabled bool) (ChannelConfig, error) {
+		statusFunc: func(ctx context.Context, botID string, channelType ChannelType, name string, disabled bool) (ChannelConfig, error) {
 			statusCalls++
 			if statusCalls == 1 && disabled {
 				t.Fatalf("first status update should enable config")
@@ -275,13 +284,13 @@ func TestLifecycleSetBotChannelStatusEnableFailureRollsBack(t *testing.T) {
 		ensureFunc: func(ctx context.Context, cfg ChannelConfig) error {
 			return errors.New("start failed")
 		},
-		removeFunc: func(ctx context.Context, botID string, channelType ChannelType) {
+		removeFunc: func(ctx context.Context, configID string) {
 			removeCalled = true
 		},
 	}
 	service := NewLifecycle(store, controller)
 
-	_, err := service.SetBotChannelStatus(context.Background(), "bot-1", ChannelType("telegram"), false)
+	_, err := service.SetBotChannelStatus(context.Background(), "bot-1", ChannelType("telegram"), "", false)
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	}
@@ -292,3 +301,32 @@ func TestLifecycleSetBotChannelStatusEnableFailureRollsBack(t *testing.T) {
 		t.Fatalf("expected remove connection to be called on failed enable")
 	}
 }
+
+func TestLifecycleDeleteMissingConfigKeepsConnections(t *testing.T) {
+	t.Parallel()
+
+	removeCalled := false
+	controller := &fakeConnectionController{
+		removeFunc: func(ctx context.Context, configID string) {
+			removeCalled = true
+		},
+	}
+	service := NewLifecycle(&fakeLifecycleStore{}, controller)
+
+	if err := service.DeleteBotChannelConfig(context.Background(), "bot-1", ChannelType("telegram"), "missing"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if removeCalled {
+		t.Fatalf("expected no connection to be removed")
+	}
+}
+
+func TestLifecycleUpsertRejectsLongName(t *testing.T) {
+	t.Parallel()
+
+	service := NewLifecycle(&fakeLifecycleStore{}, &fakeConnectionController{})
+	_, err := service.UpsertBotChannelConfig(context.Background(), "bot-1", ChannelType("telegram"), strings.Repeat("x", 65), UpsertConfigRequest{})
+	if err == nil {
+		t.Fatalf("expected error for a long config name")
+	}
+}
@@ -19,6 +19,7 @@ type ConfigLister interface {
 // ConfigResolver resolves effective configs and user bindings. Used for outbound sending.
 type ConfigResolver interface {
 	ResolveEffectiveConfig(ctx context.Context, botID string, channelType ChannelType) (ChannelConfig, error)
+	ResolveConfigByID(ctx context.Context, botID, configID string) (ChannelConfig, error)
 	GetChannelIdentityConfig(ctx context.Context, channelIdentityID string, channelType ChannelType) (ChannelIdentityBinding, error)
 }
 
@@ -48,6 +49,7 @@ type ManagerStore interface {
 // ConnectionStatus describes runtime status for one configured channel connection.
 type ConnectionStatus struct {
 	ConfigID    string      `json:"config_id"`
+	ConfigName  string      `json:"config_name,omitempty"`
 	BotID       string      `json:"bot_id"`
 	ChannelType ChannelType `json:"channel_type"`
 	Running     bool        `json:"running"`
@@ -188,6 +190,7 @@ func (m *Manager) Start(ctx context.Context) {
 }
 
 // Send delivers an outbound message to the specified channel, resolving target and config automatically.
+// req.ConfigID picks one of several configs of the channel type; otherwise the default config is used.
 func (m *Manager) Send(ctx context.Context, botID string, channelType ChannelType, req SendRequest) error {
 	if m.service == nil {
 		return fmt.Errorf("channel manager not configured")
@@ -196,7 +199,7 @@ func (m *Manager) Send(ctx context.Context, botID string, channelType ChannelTyp
 	if !ok {
 		return fmt.Errorf("unsupported channel type: %s", channelType)
 	}
-	config, err := m.service.ResolveEffectiveConfig(ctx, botID, channelType)
+	config, err := m.resolveConfig(ctx, botID, channelType, req.ConfigID)
 	if err != nil {
 		return err
 	}
@@ -225,7 +228,7 @@ func (m *Manager) Send(ctx context.Context, botID string, channelType ChannelTyp
 		return fmt.Errorf("message is required")
 	}
 	if m.logger != nil {
-		m.logger.Info("send outbound", slog.String("channel", channelType.String()), slog.String("bot_id", botID))
+		m.logger.Info("send outbound", slog.String("channel", channelType.String()), slog.String("bot_id", botID), slog.String("config_id", config.ID))
 	}
 	policy := m.resolveOutboundPolicy(channelType)
 	outbound, err := buildOutboundMessages(OutboundMessage{
@@ -255,7 +258,7 @@ func (m *Manager) React(ctx context.Context, botID string, channelType ChannelTy
 	if !ok {
 		return fmt.Errorf("channel %s does not support reactions", channelType)
 	}
-	config, err := m.service.ResolveEffectiveConfig(ctx, botID, channelType)
+	config, err := m.resolveConfig(ctx, botID, channelType, req.ConfigID)
 	if err != nil {
 		return err
 	}
@@ -288,6 +291,23 @@ func (m *Manager) React(ctx context.Context, botID string, channelType ChannelTy
 	return reactor.React(ctx, config, target, messageID, emoji)
 }
 
+// resolveConfig returns the config selected by configID, or the default config
+// of the channel type when configID is empty.
+func (m *Manager) resolveConfig(ctx context.Context, botID string, channelType ChannelType, configID string) (ChannelConfig, error) {
+	configID = strings.TrimSpace(configID)
+	if configID == "" || m.registry.IsConfigless(channelType) {
+		return m.service.ResolveEffectiveConfig(ctx, botID, channelType)
+	}
+	config, err := m.service.ResolveConfigByID(ctx, botID, configID)
+	if err != nil {
+		return ChannelConfig{}, err
+	}
+	if config.ChannelType != channelType {
+		return ChannelConfig{}, fmt.Errorf("channel config %s is not a %s config", configID, channelType)
+	}
+	return config, nil
+}
+
 // Shutdown cancels the inbound worker pool and stops all active connections.
 func (m *Manager) Shutdown(ctx context.Context) error {
 	if m.inboundCancel != nil {
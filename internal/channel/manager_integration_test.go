@@ -23,6 +23,17 @@ func (f *fakeConfigStore) ResolveEffectiveConfig(ctx context.Context, botID stri
 	return f.effectiveConfig, nil
 }
 
+func (f *fakeConfigStore) ResolveConfigByID(ctx context.Context, botID, configID string) (ChannelConfig, error) {
+	for _, items := range f.configsByType {
+		for _, item := range items {
+			if item.ID == configID && item.BotID == botID {
+				return item, nil
+			}
+		}
+	}
+	return ChannelConfig{}, ErrChannelConfigNotFound
+}
+
 func (f *fakeConfigStore) GetChannelIdentityConfig(ctx context.Context, channelIdentityID string, channelType ChannelType) (ChannelIdentityBinding, error) {
 	if f.channelIdentityConfig.ID == "" && len(f.channelIdentityConfig.Config) == 0 {
 		return ChannelIdentityBinding{}, fmt.Errorf("channel user config not found")
@@ -77,6 +88,7 @@ type fakeAdapter struct {
 	started     []ChannelConfig
 	connectCtxs []context.Context
 	sent        []OutboundMessage
+	sentConfigs []string
 	stops       int
 }
 
@@ -118,6 +130,7 @@ func (f *fakeAdapter) Connect(ctx context.Context, cfg ChannelConfig, handler In
 func (f *fakeAdapter) Send(ctx context.Context, cfg ChannelConfig, msg OutboundMessage) error {
 	f.mu.Lock()
 	f.sent = append(f.sent, msg)
+	f.sentConfigs = append(f.sentConfigs, cfg.ID)
 	f.mu.Unlock()
 	return nil
 }
@@ -217,6 +230,43 @@ func TestManagerSendUsesBinding(t *testing.T) {
 	}
 }
 
+func TestManagerSendSelectsConfig(t *testing.T) {
+	t.Parallel()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	store := &fakeConfigStore{
+		effectiveConfig: ChannelConfig{ID: "cfg-default", BotID: "bot-1", ChannelType: ChannelType("test")},
+		configsByType: map[ChannelType][]ChannelConfig{
+			ChannelType("test"): {
+				{ID: "cfg-default", BotID: "bot-1", ChannelType: ChannelType("test")},
+				{ID: "cfg-work", BotID: "bot-1", ChannelType: ChannelType("test"), Name: "work"},
+				{ID: "cfg-other", BotID: "bot-2", ChannelType: ChannelType("test")},
+			},
+		},
+	}
+	reg := NewRegistry()
+	adapter := &fakeAdapter{channelType: ChannelType("test")}
+	manager := NewManager(log, reg, store, &fakeInboundProcessorIntegration{})
+	manager.RegisterAdapter(adapter)
+	ctx := context.Background()
+
+	if err := manager.Send(ctx, "bot-1", ChannelType("test"), SendRequest{Target: "alice", Message: Message{Text: "default"}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := manager.Send(ctx, "bot-1", ChannelType("test"), SendRequest{ConfigID: "cfg-work", Target: "alice", Message: Message{Text: "work"}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := manager.Send(ctx, "bot-1", ChannelType("test"), SendRequest{ConfigID: "cfg-other", Target: "alice", Message: Message{Text: "other"}}); err == nil {
+		t.Fatalf("expected error for a config of another bot")
+	}
+
+	adapter.mu.Lock()
+	defer adapter.mu.Unlock()
+	if len(adapter.sentConfigs) != 2 || adapter.sentConfigs[0] != "cfg-default" || adapter.sentConfigs[1] != "cfg-work" {
+		t.Fatalf("unexpected configs used for sending: %v", adapter.sentConfigs)
+	}
+}
+
 func TestManagerReconcileStartsAndStops(t *testing.T) {
 	t.Parallel()
 
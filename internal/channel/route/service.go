@@ -80,17 +80,27 @@ func (s *DBService) Create(ctx context.Context, input CreateInput) (Route, error
 	return toRouteFromCreate(row), nil
 }
 
-// Find finds a route by bot/platform/external-conversation/thread.
-func (s *DBService) Find(ctx context.Context, botID, platform, conversationID, threadID string) (Route, error) {
+// Find finds a route by bot/platform/config/external-conversation/thread.
+// An empty channelConfigID matches routes of configless channels. When the
+// config has no route yet, a route whose config was deleted is returned.
+func (s *DBService) Find(ctx context.Context, botID, platform, channelConfigID, conversationID, threadID string) (Route, error) {
 	pgBotID, err := dbpkg.ParseUUID(botID)
 	if err != nil {
 		return Route{}, err
 	}
+	var pgConfigID pgtype.UUID
+	if strings.TrimSpace(channelConfigID) != "" {
+		pgConfigID, err = dbpkg.ParseUUID(channelConfigID)
+		if err != nil {
+			return Route{}, err
+		}
+	}
 	row, err := s.queries.FindChatRoute(ctx, sqlc.FindChatRouteParams{
-		BotID:          pgBotID,
-		Platform:       platform,
-		ConversationID: conversationID,
-		ThreadID:       toPgText(threadID),
+		BotID:           pgBotID,
+		Platform:        platform,
+		ChannelConfigID: pgConfigID,
+		ConversationID:  conversationID,
+		ThreadID:        toPgText(threadID),
 	})
 	if err != nil {
 		return Route{}, err
@@ -137,6 +147,22 @@ func (s *DBService) Delete(ctx context.Context, routeID string) error {
 	return s.queries.DeleteChatRoute(ctx, pgID)
 }
 
+// claim attaches a route left without a config to channelConfigID.
+func (s *DBService) claim(ctx context.Context, routeID, channelConfigID string) error {
+	pgID, err := dbpkg.ParseUUID(routeID)
+	if err != nil {
+		return err
+	}
+	pgConfigID, err := dbpkg.ParseUUID(channelConfigID)
+	if err != nil {
+		return err
+	}
+	return s.queries.ClaimChatRoute(ctx, sqlc.ClaimChatRouteParams{
+		ID:              pgID,
+		ChannelConfigID: pgConfigID,
+	})
+}
+
 // UpdateReplyTarget updates default reply target.
 func (s *DBService) UpdateReplyTarget(ctx context.Context, routeID, replyTarget string) error {
 	pgID, err := dbpkg.ParseUUID(routeID)
@@ -167,8 +193,13 @@ func (s *DBService) UpdateMetadata(ctx context.Context, routeID string, metadata
 
 // ResolveConversation finds or creates a conversation route for an inbound message.
 func (s *DBService) ResolveConversation(ctx context.Context, input ResolveInput) (ResolveConversationResult, error) {
-	route, err := s.Find(ctx, input.BotID, input.Platform, input.ConversationID, input.ThreadID)
+	route, err := s.Find(ctx, input.BotID, input.Platform, input.ChannelConfigID, input.ConversationID, input.ThreadID)
 	if err == nil {
+		if strings.TrimSpace(route.ChannelConfigID) == "" && strings.TrimSpace(input.ChannelConfigID) != "" {
+			if claimErr := s.claim(ctx, route.ID, input.ChannelConfigID); claimErr != nil && s.logger != nil {
+				s.logger.Warn("claim route for channel config failed", slog.Any("error", claimErr))
+			}
+		}
 		if strings.TrimSpace(input.ChannelIdentityID) != "" && s.conversation != nil {
 			ok, checkErr := s.conversation.IsParticipant(ctx, route.ChatID, input.ChannelIdentityID)
 			if checkErr != nil {
@@ -210,7 +241,7 @@ func (s *DBService) ResolveConversation(ctx context.Context, input ResolveInput)
 
 	var parentConversationID string
 	if kind == conversation.KindThread {
-		parentRoute, parentErr := s.Find(ctx, input.BotID, input.Platform, input.ConversationID, "")
+		parentRoute, parentErr := s.Find(ctx, input.BotID, input.Platform, input.ChannelConfigID, input.ConversationID, "")
 		if parentErr == nil {
 			parentConversationID = parentRoute.ChatID
 		}
@@ -245,7 +276,7 @@ func (s *DBService) ResolveConversation(ctx context.Context, input ResolveInput)
 		// Concurrent insert race: another goroutine created the same route between
 		// our Find and Create calls. Fall back to Find the winning row.
 		if dbpkg.IsUniqueViolation(err) {
-			existing, findErr := s.Find(ctx, input.BotID, input.Platform, input.ConversationID, input.ThreadID)
+			existing, findErr := s.Find(ctx, input.BotID, input.Platform, input.ChannelConfigID, input.ConversationID, input.ThreadID)
 			if findErr == nil {
 				return ResolveConversationResult{ChatID: existing.ChatID, RouteID: existing.ID, Created: false}, nil
 			}
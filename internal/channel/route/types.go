@@ -63,7 +63,7 @@ type Resolver interface {
 type Service interface {
 	Resolver
 	Create(ctx context.Context, input CreateInput) (Route, error)
-	Find(ctx context.Context, botID, platform, conversationID, threadID string) (Route, error)
+	Find(ctx context.Context, botID, platform, channelConfigID, conversationID, threadID string) (Route, error)
 	GetByID(ctx context.Context, routeID string) (Route, error)
 	List(ctx context.Context, chatID string) ([]Route, error)
 	Delete(ctx context.Context, routeID string) error
@@ -15,7 +15,7 @@ import (
 	"github.com/memohai/memoh/internal/db/sqlc"
 )
 
-// ErrChannelConfigNotFound indicates the bot has no persisted config for the channel type.
+// ErrChannelConfigNotFound indicates the bot has no persisted config for the channel type and name.
 var ErrChannelConfigNotFound = errors.New("channel config not found")
 
 // Store provides CRUD operations for channel configurations, user bindings, and sessions.
@@ -32,14 +32,18 @@ func NewStore(queries *sqlc.Queries, registry *Registry) *Store {
 	return &Store{queries: queries, registry: registry}
 }
 
-// UpsertConfig creates or updates a bot's channel configuration.
-func (s *Store) UpsertConfig(ctx context.Context, botID string, channelType ChannelType, req UpsertConfigRequest) (ChannelConfig, error) {
+// UpsertConfig creates or updates the bot's channel configuration with the given name.
+func (s *Store) UpsertConfig(ctx context.Context, botID string, channelType ChannelType, name string, req UpsertConfigRequest) (ChannelConfig, error) {
 	if s.queries == nil {
 		return ChannelConfig{}, fmt.Errorf("channel queries not configured")
 	}
 	if channelType == "" {
 		return ChannelConfig{}, fmt.Errorf("channel type is required")
 	}
+	name, err := NormalizeConfigName(name)
+	if err != nil {
+		return ChannelConfig{}, err
+	}
 	normalized, err := s.registry.NormalizeConfig(channelType, req.Credentials)
 	if err != nil {
 		return ChannelConfig{}, err
@@ -90,6 +94,7 @@ func (s *Store) UpsertConfig(ctx context.Context, botID string, channelType Chan
 	row, err := s.queries.UpsertBotChannelConfig(ctx, sqlc.UpsertBotChannelConfigParams{
 		BotID:       botUUID,
 		ChannelType: channelType.String(),
+		Name:        name,
 		Credentials: credentialsPayload,
 		ExternalIdentity: pgtype.Text{
 			String: externalIdentity,
@@ -107,8 +112,8 @@ func (s *Store) UpsertConfig(ctx context.Context, botID string, channelType Chan
 	return normalizeChannelConfigFromRow(row)
 }
 
-// DeleteConfig removes a bot's channel configuration.
-func (s *Store) DeleteConfig(ctx context.Context, botID string, channelType ChannelType) error {
+// DeleteConfig removes the bot's channel configuration with the given name.
+func (s *Store) DeleteConfig(ctx context.Context, botID string, channelType ChannelType, name string) error {
 	if s.queries == nil {
 		return fmt.Errorf("channel queries not configured")
 	}
@@ -119,14 +124,35 @@ func (s *Store) DeleteConfig(ctx context.Context, botID string, channelType Chan
 	if err != nil {
 		return err
 	}
+	row, err := s.queries.GetBotChannelConfig(ctx, sqlc.GetBotChannelConfigParams{
+		BotID:       botUUID,
+		ChannelType: channelType.String(),
+		Name:        strings.TrimSpace(name),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	// Routes of the config keep their history and lose only the config
+	// reference on delete. Fold in older routes that already lost theirs so
+	// the two do not collide.
+	if err := s.queries.MoveOrphanedChatRouteHistory(ctx, row.ID); err != nil {
+		return fmt.Errorf("move orphaned route history: %w", err)
+	}
+	if err := s.queries.DeleteOrphanedChatRoutes(ctx, row.ID); err != nil {
+		return fmt.Errorf("delete orphaned routes: %w", err)
+	}
 	return s.queries.DeleteBotChannelConfig(ctx, sqlc.DeleteBotChannelConfigParams{
 		BotID:       botUUID,
 		ChannelType: channelType.String(),
+		Name:        strings.TrimSpace(name),
 	})
 }
 
 // UpdateConfigDisabled updates only the disabled flag for a bot channel config and returns latest config.
-func (s *Store) UpdateConfigDisabled(ctx context.Context, botID string, channelType ChannelType, disabled bool) (ChannelConfig, error) {
+func (s *Store) UpdateConfigDisabled(ctx context.Context, botID string, channelType ChannelType, name string, disabled bool) (ChannelConfig, error) {
 	if s.queries == nil {
 		return ChannelConfig{}, fmt.Errorf("channel queries not configured")
 	}
@@ -140,6 +166,7 @@ func (s *Store) UpdateConfigDisabled(ctx context.Context, botID string, channelT
 	row, err := s.queries.UpdateBotChannelConfigDisabled(ctx, sqlc.UpdateBotChannelConfigDisabledParams{
 		BotID:       botUUID,
 		ChannelType: channelType.String(),
+		Name:        strings.TrimSpace(name),
 		Disabled:    disabled,
 	})
 	if err != nil {
@@ -182,7 +209,8 @@ func (s *Store) UpsertChannelIdentityConfig(ctx context.Context, channelIdentity
 	return normalizeChannelIdentityBinding(row)
 }
 
-// ResolveEffectiveConfig returns the active channel configuration for a bot.
+// ResolveEffectiveConfig returns the default channel configuration for a bot:
+// the unnamed config, or the oldest one when all configs are named.
 // For configless channel types, a synthetic config is returned.
 func (s *Store) ResolveEffectiveConfig(ctx context.Context, botID string, channelType ChannelType) (ChannelConfig, error) {
 	if s.queries == nil {
@@ -192,11 +220,30 @@ func (s *Store) ResolveEffectiveConfig(ctx context.Context, botID string, channe
 		return ChannelConfig{}, fmt.Errorf("channel type is required")
 	}
 	if s.registry.IsConfigless(channelType) {
-		return ChannelConfig{
-			ID:          channelType.String() + ":" + strings.TrimSpace(botID),
-			BotID:       strings.TrimSpace(botID),
-			ChannelType: channelType,
-		}, nil
+		return configlessConfig(botID, channelType), nil
+	}
+	botUUID, err := db.ParseUUID(botID)
+	if err != nil {
+		return ChannelConfig{}, err
+	}
+	row, err := s.queries.GetDefaultBotChannelConfig(ctx, sqlc.GetDefaultBotChannelConfigParams{
+		BotID:       botUUID,
+		ChannelType: channelType.String(),
+	})
+	return configFromRowResult(row, err)
+}
+
+// ResolveConfig returns the bot's channel configuration with the given name.
+// For configless channel types, a synthetic config is returned.
+func (s *Store) ResolveConfig(ctx context.Context, botID string, channelType ChannelType, name string) (ChannelConfig, error) {
+	if s.queries == nil {
+		return ChannelConfig{}, fmt.Errorf("channel queries not configured")
+	}
+	if channelType == "" {
+		return ChannelConfig{}, fmt.Errorf("channel type is required")
+	}
+	if s.registry.IsConfigless(channelType) {
+		return configlessConfig(botID, channelType), nil
 	}
 	botUUID, err := db.ParseUUID(botID)
 	if err != nil {
@@ -205,7 +252,41 @@ func (s *Store) ResolveEffectiveConfig(ctx context.Context, botID string, channe
 	row, err := s.queries.GetBotChannelConfig(ctx, sqlc.GetBotChannelConfigParams{
 		BotID:       botUUID,
 		ChannelType: channelType.String(),
+		Name:        strings.TrimSpace(name),
 	})
+	return configFromRowResult(row, err)
+}
+
+// ResolveConfigByID returns the channel configuration with the given ID,
+// provided it belongs to the bot.
+func (s *Store) ResolveConfigByID(ctx context.Context, botID, configID string) (ChannelConfig, error) {
+	if s.queries == nil {
+		return ChannelConfig{}, fmt.Errorf("channel queries not configured")
+	}
+	configUUID, err := db.ParseUUID(configID)
+	if err != nil {
+		return ChannelConfig{}, err
+	}
+	row, err := s.queries.GetBotChannelConfigByID(ctx, configUUID)
+	cfg, err := configFromRowResult(row, err)
+	if err != nil {
+		return ChannelConfig{}, err
+	}
+	if cfg.BotID != strings.TrimSpace(botID) {
+		return ChannelConfig{}, fmt.Errorf("%w", ErrChannelConfigNotFound)
+	}
+	return cfg, nil
+}
+
+func configlessConfig(botID string, channelType ChannelType) ChannelConfig {
+	return ChannelConfig{
+		ID:          channelType.String() + ":" + strings.TrimSpace(botID),
+		BotID:       strings.TrimSpace(botID),
+		ChannelType: channelType,
+	}
+}
+
+func configFromRowResult(row sqlc.BotChannelConfig, err error) (ChannelConfig, error) {
 	if err == nil {
 		return normalizeChannelConfigFromGetRow(row)
 	}
@@ -238,6 +319,30 @@ func (s *Store) ListConfigsByType(ctx context.Context, channelType ChannelType)
 	return items, nil
 }
 
+// ListConfigsByBot returns all persisted channel configurations of a bot.
+func (s *Store) ListConfigsByBot(ctx context.Context, botID string) ([]ChannelConfig, error) {
+	if s.queries == nil {
+		return nil, fmt.Errorf("channel queries not configured")
+	}
+	botUUID, err := db.ParseUUID(botID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.queries.ListBotChannelConfigsByBot(ctx, botUUID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]ChannelConfig, 0, len(rows))
+	for _, row := range rows {
+		item, err := normalizeChannelConfigFromListRow(row)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
 // GetChannelIdentityConfig returns the channel identity's channel binding for the given channel type.
 func (s *Store) GetChannelIdentityConfig(ctx context.Context, channelIdentityID string, channelType ChannelType) (ChannelIdentityBinding, error) {
 	if s.queries == nil {
@@ -313,7 +418,7 @@ func (s *Store) ResolveChannelIdentityBinding(ctx context.Context, channelType C
 
 func normalizeChannelConfigFromRow(row sqlc.BotChannelConfig) (ChannelConfig, error) {
 	return normalizeChannelConfigFields(
-		row.ID, row.BotID, row.ChannelType,
+		row.ID, row.BotID, row.ChannelType, row.Name,
 		row.Credentials, row.ExternalIdentity, row.SelfIdentity, row.Routing,
 		row.Disabled, row.VerifiedAt, row.CreatedAt, row.UpdatedAt,
 	)
@@ -321,7 +426,7 @@ func normalizeChannelConfigFromRow(row sqlc.BotChannelConfig) (ChannelConfig, er
 
 func normalizeChannelConfigFromGetRow(row sqlc.BotChannelConfig) (ChannelConfig, error) {
 	return normalizeChannelConfigFields(
-		row.ID, row.BotID, row.ChannelType,
+		row.ID, row.BotID, row.ChannelType, row.Name,
 		row.Credentials, row.ExternalIdentity, row.SelfIdentity, row.Routing,
 		row.Disabled, row.VerifiedAt, row.CreatedAt, row.UpdatedAt,
 	)
@@ -329,14 +434,14 @@ func normalizeChannelConfigFromGetRow(row sqlc.BotChannelConfig) (ChannelConfig,
 
 func normalizeChannelConfigFromListRow(row sqlc.BotChannelConfig) (ChannelConfig, error) {
 	return normalizeChannelConfigFields(
-		row.ID, row.BotID, row.ChannelType,
+		row.ID, row.BotID, row.ChannelType, row.Name,
 		row.Credentials, row.ExternalIdentity, row.SelfIdentity, row.Routing,
 		row.Disabled, row.VerifiedAt, row.CreatedAt, row.UpdatedAt,
 	)
 }
 
 func normalizeChannelConfigFields(
-	id, botID pgtype.UUID, channelType string,
+	id, botID pgtype.UUID, channelType, name string,
 	credentials []byte, externalIdentity pgtype.Text, selfIdentity, routing []byte,
 	disabled bool, verifiedAt, createdAt, updatedAt pgtype.Timestamptz,
 ) (ChannelConfig, error) {
@@ -364,6 +469,7 @@ func normalizeChannelConfigFields(
 		ID:               id.String(),
 		BotID:            botID.String(),
 		ChannelType:      ChannelType(channelType),
+		Name:             name,
 		Credentials:      credentialsMap,
 		ExternalIdentity: externalIdentityStr,
 		SelfIdentity:     selfIdentityMap,
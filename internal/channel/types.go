@@ -337,10 +337,13 @@ func BindingCriteriaFromIdentity(identity Identity) BindingCriteria {
 
 // ChannelConfig holds the configuration for a bot's channel integration.
 // Disabled: true means the channel is stopped (not connected); false means enabled.
+// Name distinguishes several configs of the same channel type on one bot; the
+// empty name is the default config.
 type ChannelConfig struct {
 	ID               string         `json:"id"`
 	BotID            string         `json:"bot_id"`
 	ChannelType      ChannelType    `json:"channel_type"`
+	Name             string         `json:"name"`
 	Credentials      map[string]any `json:"credentials"`
 	ExternalIdentity string         `json:"external_identity"`
 	SelfIdentity     map[string]any `json:"self_identity"`
@@ -383,7 +386,9 @@ type UpdateChannelStatusRequest struct {
 }
 
 // SendRequest is the input for sending an outbound message through a channel.
+// ConfigID selects the channel config to send through; empty uses the default.
 type SendRequest struct {
+	ConfigID          string  `json:"config_id,omitempty"`
 	Target            string  `json:"target,omitempty"`
 	ChannelIdentityID string  `json:"channel_identity_id,omitempty"`
 	Message           Message `json:"message"`
@@ -391,6 +396,7 @@ type SendRequest struct {
 
 // ReactRequest is the input for adding or removing an emoji reaction on a message.
 type ReactRequest struct {
+	ConfigID  string `json:"config_id,omitempty"`
 	Target    string `json:"target"`
 	MessageID string `json:"message_id"`
 	Emoji     string `json:"emoji"`
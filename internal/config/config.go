@@ -8,23 +8,26 @@ import (
 )
 
 const (
-	DefaultConfigPath       = "config.toml"
-	DefaultHTTPAddr         = ":8080"
-	DefaultNamespace        = "default"
-	DefaultSocketPath       = "/run/containerd/containerd.sock"
-	DefaultMCPImage         = "docker.io/library/memoh-mcp:latest"
-	DefaultDataRoot         = "data"
-	DefaultDataMount        = "/data"
-	DefaultCNIBinaryDir     = "/opt/cni/bin"
-	DefaultCNIConfigDir     = "/etc/cni/net.d"
-	DefaultJWTExpiresIn     = "24h"
-	DefaultPGHost           = "127.0.0.1"
-	DefaultPGPort           = 5432
-	DefaultPGUser           = "postgres"
-	DefaultPGDatabase       = "memoh"
-	DefaultPGSSLMode        = "disable"
-	DefaultQdrantURL        = "http://127.0.0.1:6334"
-	DefaultQdrantCollection = "memory"
+	DefaultConfigPath               = "config.toml"
+	DefaultHTTPAddr                 = ":8080"
+	DefaultNamespace                = "default"
+	DefaultSocketPath               = "/run/containerd/containerd.sock"
+	DefaultMCPImage                 = "docker.io/library/memoh-mcp:latest"
+	DefaultDataRoot                 = "data"
+	DefaultDataMount                = "/data"
+	DefaultCNIBinaryDir             = "/opt/cni/bin"
+	DefaultCNIConfigDir             = "/etc/cni/net.d"
+	DefaultJWTExpiresIn             = "24h"
+	DefaultPGHost                   = "127.0.0.1"
+	DefaultPGPort                   = 5432
+	DefaultPGUser                   = "postgres"
+	DefaultPGDatabase               = "memoh"
+	DefaultPGSSLMode                = "disable"
+	DefaultQdrantURL                = "http://127.0.0.1:6334"
+	DefaultQdrantCollection         = "memory"
+	DefaultMemoryStore              = "qdrant"
+	DefaultMemoryExpirySweepSeconds = 300
+	DefaultFileWatchSeconds         = 5
 )
 
 type Config struct {
@@ -35,6 +38,7 @@ type Config struct {
 	Containerd   ContainerdConfig   `toml:"containerd"`
 	MCP          MCPConfig          `toml:"mcp"`
 	Postgres     PostgresConfig     `toml:"postgres"`
+	Memory       MemoryConfig       `toml:"memory"`
 	Qdrant       QdrantConfig       `toml:"qdrant"`
 	AgentGateway AgentGatewayConfig `toml:"agent_gateway"`
 }
@@ -76,6 +80,14 @@ type MCPConfig struct {
 	DataRoot     string `toml:"data_root"`
 	CNIBinaryDir string `toml:"cni_bin_dir"`
 	CNIConfigDir string `toml:"cni_conf_dir"`
+	// IdleSuspendMinutes suspends bot containers that saw no tool call or
+	// inbound message for this long; 0 keeps them running. IdleSuspendMode is
+	// "pause" (default) or "stop".
+	IdleSuspendMinutes int    `toml:"idle_suspend_minutes"`
+	IdleSuspendMode    string `toml:"idle_suspend_mode"`
+	// FileWatchSeconds is how often bot data directories are scanned for
+	// changes matching file watches; 0 disables file watches.
+	FileWatchSeconds int `toml:"file_watch_seconds"`
 }
 
 type PostgresConfig struct {
@@ -87,6 +99,17 @@ type PostgresConfig struct {
 	SSLMode  string `toml:"sslmode"`
 }
 
+// MemoryConfig selects the vector store backing long-term memory.
+// Store is "qdrant" (default) or "pgvector"; pgvector keeps memories in the
+// [postgres] database, in tables created by migration when the vector
+// extension is available (e.g. the pgvector/pgvector image).
+// ExpirySweepSeconds is how often expired memories are removed; 0 disables
+// the sweeper.
+type MemoryConfig struct {
+	Store              string `toml:"store"`
+	ExpirySweepSeconds int    `toml:"expiry_sweep_seconds"`
+}
+
 type QdrantConfig struct {
 	BaseURL        string `toml:"base_url"`
 	APIKey         string `toml:"api_key"`
@@ -133,10 +156,11 @@ func Load(path string) (Config, error) {
 			Namespace:  DefaultNamespace,
 		},
 		MCP: MCPConfig{
-			Image:        DefaultMCPImage,
-			DataRoot:     DefaultDataRoot,
-			CNIBinaryDir: DefaultCNIBinaryDir,
-			CNIConfigDir: DefaultCNIConfigDir,
+			Image:            DefaultMCPImage,
+			DataRoot:         DefaultDataRoot,
+			CNIBinaryDir:     DefaultCNIBinaryDir,
+			CNIConfigDir:     DefaultCNIConfigDir,
+			FileWatchSeconds: DefaultFileWatchSeconds,
 		},
 		Postgres: PostgresConfig{
 			Host:     DefaultPGHost,
@@ -145,6 +169,10 @@ func Load(path string) (Config, error) {
 			Database: DefaultPGDatabase,
 			SSLMode:  DefaultPGSSLMode,
 		},
+		Memory: MemoryConfig{
+			Store:              DefaultMemoryStore,
+			ExpirySweepSeconds: DefaultMemoryExpirySweepSeconds,
+		},
 		Qdrant: QdrantConfig{
 			BaseURL:    DefaultQdrantURL,
 			Collection: DefaultQdrantCollection,
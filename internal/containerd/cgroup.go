@@ -0,0 +1,170 @@
+package containerd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/containerd/v2/pkg/oci"
+)
+
+// DefaultCPUPeriod is the CFS period used when a CPU quota is set without one.
+const DefaultCPUPeriod uint64 = 100000
+
+// cgroupRoot is where the cgroup hierarchies are mounted on the host.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupV1Unlimited is the smallest value cgroup v1 reports for "no limit".
+const cgroupV1Unlimited = uint64(1) << 62
+
+// resourceSpecOpts converts limits to OCI spec options.
+func resourceSpecOpts(limits ResourceLimits) []oci.SpecOpts {
+	var opts []oci.SpecOpts
+	if limits.CPUShares > 0 {
+		opts = append(opts, oci.WithCPUShares(limits.CPUShares))
+	}
+	if limits.CPUQuota > 0 {
+		period := limits.CPUPeriod
+		if period == 0 {
+			period = DefaultCPUPeriod
+		}
+		opts = append(opts, oci.WithCPUCFS(limits.CPUQuota, period))
+	}
+	if limits.MemoryBytes > 0 {
+		opts = append(opts, oci.WithMemoryLimit(uint64(limits.MemoryBytes)))
+	}
+	if limits.PidsLimit > 0 {
+		opts = append(opts, oci.WithPidsLimit(limits.PidsLimit))
+	}
+	return opts
+}
+
+// clearResourceLimits removes the limits managed by resourceSpecOpts so a
+// limit set to zero is lifted on update.
+func clearResourceLimits(spec *oci.Spec) {
+	if spec.Linux == nil || spec.Linux.Resources == nil {
+		return
+	}
+	resources := spec.Linux.Resources
+	if resources.CPU != nil {
+		resources.CPU.Shares = nil
+		resources.CPU.Quota = nil
+		resources.CPU.Period = nil
+	}
+	if resources.Memory != nil {
+		resources.Memory.Limit = nil
+	}
+	resources.Pids = nil
+}
+
+// readCgroupUsage reads the cgroup usage of the process pid, supporting both
+// the unified (v2) and the legacy (v1) hierarchy.
+func readCgroupUsage(pid uint32) (ResourceUsage, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return ResourceUsage{}, err
+	}
+	return parseCgroupUsage(cgroupRoot, data)
+}
+
+func parseCgroupUsage(root string, procCgroup []byte) (ResourceUsage, error) {
+	paths := parseProcCgroup(procCgroup)
+	if len(paths) == 0 {
+		return ResourceUsage{}, fmt.Errorf("no cgroup found")
+	}
+	if _, ok := paths["memory"]; !ok {
+		if unified, ok := paths[""]; ok {
+			return readCgroupV2Usage(filepath.Join(root, unified)), nil
+		}
+	}
+
+	var usage ResourceUsage
+	if path, ok := paths["memory"]; ok {
+		dir := filepath.Join(root, "memory", path)
+		usage.MemoryBytes = readCgroupUint(filepath.Join(dir, "memory.usage_in_bytes"))
+		if limit := readCgroupUint(filepath.Join(dir, "memory.limit_in_bytes")); limit < cgroupV1Unlimited {
+			usage.MemoryLimit = limit
+		}
+	}
+	if path, ok := paths["pids"]; ok {
+		dir := filepath.Join(root, "pids", path)
+		usage.Pids = readCgroupUint(filepath.Join(dir, "pids.current"))
+		usage.PidsLimit = readCgroupUint(filepath.Join(dir, "pids.max"))
+	}
+	// The cpuacct hierarchy is often co-mounted as "cpu,cpuacct"; paths also
+	// holds each controller on its own, so try every key naming it and keep
+	// the one whose directory exists.
+	for controllers, path := range paths {
+		if !strings.Contains(controllers, "cpuacct") {
+			continue
+		}
+		if nanos := readCgroupUint(filepath.Join(root, controllers, path, "cpuacct.usage")); nanos > 0 {
+			usage.CPUUsageNanos = nanos
+			break
+		}
+	}
+	return usage, nil
+}
+
+func readCgroupV2Usage(dir string) ResourceUsage {
+	usage := ResourceUsage{
+		MemoryBytes: readCgroupUint(filepath.Join(dir, "memory.current")),
+		MemoryLimit: readCgroupUint(filepath.Join(dir, "memory.max")),
+		Pids:        readCgroupUint(filepath.Join(dir, "pids.current")),
+		PidsLimit:   readCgroupUint(filepath.Join(dir, "pids.max")),
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "cpu.stat")); err == nil {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			key, value, ok := strings.Cut(scanner.Text(), " ")
+			if ok && key == "usage_usec" {
+				if usec, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64); err == nil {
+					usage.CPUUsageNanos = usec * 1000
+				}
+				break
+			}
+		}
+	}
+	return usage
+}
+
+// parseProcCgroup maps controller lists to cgroup paths from the contents of
+// /proc/<pid>/cgroup. The unified hierarchy is keyed by "".
+func parseProcCgroup(data []byte) map[string]string {
+	paths := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		controllers, path := parts[1], parts[2]
+		paths[controllers] = path
+		for _, controller := range strings.Split(controllers, ",") {
+			if controller != "" && controller != controllers {
+				if _, ok := paths[controller]; !ok {
+					paths[controller] = path
+				}
+			}
+		}
+	}
+	return paths
+}
+
+// readCgroupUint reads a single-value cgroup file. Missing files and "max"
+// read as zero.
+func readCgroupUint(path string) uint64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,72 @@
+package containerd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func writeCgroupFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseCgroupUsageV2(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "default", "mcp-bot")
+	writeCgroupFile(t, filepath.Join(dir, "memory.current"), "1048576\n")
+	writeCgroupFile(t, filepath.Join(dir, "memory.max"), "max\n")
+	writeCgroupFile(t, filepath.Join(dir, "pids.current"), "7\n")
+	writeCgroupFile(t, filepath.Join(dir, "pids.max"), "64\n")
+	writeCgroupFile(t, filepath.Join(dir, "cpu.stat"), "usage_usec 2500\nuser_usec 2000\n")
+
+	usage, err := parseCgroupUsage(root, []byte("0::/default/mcp-bot\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ResourceUsage{CPUUsageNanos: 2500000, MemoryBytes: 1048576, Pids: 7, PidsLimit: 64}
+	if usage != want {
+		t.Fatalf("got %+v, want %+v", usage, want)
+	}
+}
+
+func TestParseCgroupUsageV1(t *testing.T) {
+	root := t.TempDir()
+	writeCgroupFile(t, filepath.Join(root, "memory", "mcp-bot", "memory.usage_in_bytes"), "2048\n")
+	writeCgroupFile(t, filepath.Join(root, "memory", "mcp-bot", "memory.limit_in_bytes"), "9223372036854771712\n")
+	writeCgroupFile(t, filepath.Join(root, "pids", "mcp-bot", "pids.current"), "3\n")
+	writeCgroupFile(t, filepath.Join(root, "pids", "mcp-bot", "pids.max"), "max\n")
+	writeCgroupFile(t, filepath.Join(root, "cpu,cpuacct", "mcp-bot", "cpuacct.usage"), "123456\n")
+
+	procCgroup := "12:pids:/mcp-bot\n4:cpu,cpuacct:/mcp-bot\n3:memory:/mcp-bot\n0::/\n"
+	usage, err := parseCgroupUsage(root, []byte(procCgroup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ResourceUsage{CPUUsageNanos: 123456, MemoryBytes: 2048, Pids: 3}
+	if usage != want {
+		t.Fatalf("got %+v, want %+v", usage, want)
+	}
+}
+
+func TestClearResourceLimits(t *testing.T) {
+	shares := uint64(512)
+	limit := int64(1 << 20)
+	pids := int64(10)
+	spec := &specs.Spec{Linux: &specs.Linux{Resources: &specs.LinuxResources{
+		CPU:    &specs.LinuxCPU{Shares: &shares},
+		Memory: &specs.LinuxMemory{Limit: &limit},
+		Pids:   &specs.LinuxPids{Limit: &pids},
+	}}}
+	clearResourceLimits(spec)
+	if spec.Linux.Resources.CPU.Shares != nil || spec.Linux.Resources.Memory.Limit != nil || spec.Linux.Resources.Pids != nil {
+		t.Fatalf("limits not cleared: %+v", spec.Linux.Resources)
+	}
+}
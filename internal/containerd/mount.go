@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/containerd/containerd/v2/core/mount"
 )
@@ -106,3 +107,56 @@ func MountSnapshot(ctx context.Context, service Service, snapshotter, key string
 
 	return dir, cleanup, nil
 }
+
+// MountSnapshotView mounts a read-only view of a committed snapshot. The
+// cleanup function unmounts it and removes the view.
+func MountSnapshotView(ctx context.Context, service Service, snapshotter, name string) (string, func() error, error) {
+	if snapshotter == "" || name == "" {
+		return "", nil, ErrInvalidArgument
+	}
+
+	viewKey := fmt.Sprintf("%s-view-%d", name, time.Now().UnixNano())
+	mountInfos, err := service.ViewSnapshot(ctx, snapshotter, viewKey, name)
+	if err != nil {
+		return "", nil, err
+	}
+	removeView := func() error {
+		return service.RemoveSnapshot(context.WithoutCancel(ctx), snapshotter, viewKey)
+	}
+
+	mounts := make([]mount.Mount, len(mountInfos))
+	for i, m := range mountInfos {
+		mounts[i] = mount.Mount{
+			Type:    m.Type,
+			Source:  m.Source,
+			Options: m.Options,
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "memoh-snapshot-view-*")
+	if err != nil {
+		_ = removeView()
+		return "", nil, err
+	}
+
+	if err := mount.All(mounts, dir); err != nil {
+		_ = os.RemoveAll(dir)
+		_ = removeView()
+		return "", nil, err
+	}
+
+	cleanup := func() error {
+		if err := mount.UnmountAll(dir, 0); err != nil {
+			return fmt.Errorf("unmount snapshot view: %w", err)
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("remove snapshot view dir: %w", err)
+		}
+		if err := removeView(); err != nil {
+			return fmt.Errorf("remove snapshot view: %w", err)
+		}
+		return nil
+	}
+
+	return dir, cleanup, nil
+}
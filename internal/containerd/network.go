@@ -3,7 +3,10 @@ package containerd
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/netip"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -11,7 +14,7 @@ import (
 	gocni "github.com/containerd/go-cni"
 )
 
-func setupCNINetwork(ctx context.Context, task client.Task, containerID string, CNIBinDir string, CNIConfDir string) error {
+func setupCNINetwork(ctx context.Context, task client.Task, containerID string, CNIBinDir string, CNIConfDir string, policy NetworkPolicy) error {
 	if task == nil {
 		return ErrInvalidArgument
 	}
@@ -45,7 +48,12 @@ func setupCNINetwork(ctx context.Context, task client.Task, containerID string,
 	if err != nil {
 		return err
 	}
-	if err := cni.Load(gocni.WithLoNetwork, gocni.WithDefaultConf); err != nil {
+	// Offline containers only get a loopback interface.
+	loadOpts := []gocni.Opt{gocni.WithLoNetwork}
+	if policy.Mode != NetworkModeNone {
+		loadOpts = append(loadOpts, gocni.WithDefaultConf)
+	}
+	if err := cni.Load(loadOpts...); err != nil {
 		return err
 	}
 	_, err = cni.Setup(ctx, containerID, netnsPath)
@@ -61,6 +69,16 @@ func setupCNINetwork(ctx context.Context, task client.Task, containerID string,
 			return err
 		}
 	}
+	if policy.Mode == NetworkModeAllowlist {
+		resolvPath := filepath.Join("/proc", fmt.Sprint(pid), "root", "etc", "resolv.conf")
+		if err := applyEgressAllowlist(ctx, netnsPath, resolvPath, policy.Allow); err != nil {
+			// Fail closed: never leave the container with unrestricted egress.
+			if rmErr := cni.Remove(ctx, containerID, netnsPath); rmErr != nil {
+				return fmt.Errorf("apply egress allowlist: %w (remove network: %v)", err, rmErr)
+			}
+			return fmt.Errorf("apply egress allowlist: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -111,3 +129,133 @@ func isDuplicateAllocationError(err error) bool {
 	}
 	return strings.Contains(err.Error(), "duplicate allocation")
 }
+
+// applyEgressAllowlist installs iptables rules in the network namespace so
+// outgoing traffic is dropped unless it is loopback, DNS to a nameserver in the
+// container's resolv.conf, a reply, or bound for an allowlisted destination.
+func applyEgressAllowlist(ctx context.Context, netnsPath, resolvPath string, allow []string) error {
+	data, err := os.ReadFile(resolvPath)
+	if err != nil {
+		return fmt.Errorf("read resolv.conf: %w", err)
+	}
+	nameservers := parseNameservers(data)
+	prefixes := resolveAllowlist(ctx, allow, net.DefaultResolver.LookupNetIP)
+	if err := restoreIPTables(ctx, netnsPath, "iptables-restore", buildEgressRules(prefixes, nameservers, false)); err != nil {
+		return err
+	}
+	if _, err := os.Stat("/proc/sys/net/ipv6"); err != nil {
+		// The kernel has no IPv6, so there is no IPv6 egress to restrict.
+		return nil
+	}
+	if _, err := exec.LookPath("ip6tables-restore"); err != nil {
+		// Without ip6tables the allowlist cannot cover IPv6; turn it off in
+		// the namespace instead of leaving IPv6 egress unrestricted.
+		return disableIPv6(ctx, netnsPath)
+	}
+	return restoreIPTables(ctx, netnsPath, "ip6tables-restore", buildEgressRules(prefixes, nameservers, true))
+}
+
+func disableIPv6(ctx context.Context, netnsPath string) error {
+	cmd := exec.CommandContext(ctx, "nsenter", "--net="+netnsPath, "sysctl", "-w",
+		"net.ipv6.conf.all.disable_ipv6=1", "net.ipv6.conf.default.disable_ipv6=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ip6tables-restore not found and disabling ipv6 failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// parseNameservers returns the nameserver addresses listed in a resolv.conf.
+// Zone suffixes are dropped since iptables matches plain addresses.
+func parseNameservers(data []byte) []netip.Addr {
+	var addrs []netip.Addr
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+		addr, err := netip.ParseAddr(fields[1])
+		if err != nil {
+			continue
+		}
+		addrs = append(addrs, addr.WithZone("").Unmap())
+	}
+	return addrs
+}
+
+func restoreIPTables(ctx context.Context, netnsPath, restoreBin, rules string) error {
+	cmd := exec.CommandContext(ctx, "nsenter", "--net="+netnsPath, restoreBin)
+	cmd.Stdin = strings.NewReader(rules)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", restoreBin, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// resolveAllowlist converts allowlist entries to prefixes. Domains are
+// resolved once, when the container network is set up, and are not refreshed
+// afterwards; restarting the container picks up new addresses. Entries that
+// fail to resolve are skipped and stay blocked.
+func resolveAllowlist(ctx context.Context, allow []string, lookup func(ctx context.Context, network, host string) ([]netip.Addr, error)) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, entry := range allow {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		addrs, err := lookup(ctx, "ip", entry)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+		}
+	}
+	return prefixes
+}
+
+// buildEgressRules renders an iptables-restore ruleset for the IPv4 or IPv6
+// prefixes in allow. DNS is only accepted towards the given nameservers so
+// port 53 cannot be used to reach arbitrary hosts.
+func buildEgressRules(allow []netip.Prefix, nameservers []netip.Addr, v6 bool) string {
+	var b strings.Builder
+	b.WriteString("*filter\n")
+	b.WriteString(":INPUT ACCEPT [0:0]\n")
+	b.WriteString(":FORWARD DROP [0:0]\n")
+	b.WriteString(":OUTPUT DROP [0:0]\n")
+	b.WriteString("-A OUTPUT -o lo -j ACCEPT\n")
+	b.WriteString("-A OUTPUT -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT\n")
+	seenDNS := make(map[netip.Addr]struct{}, len(nameservers))
+	for _, addr := range nameservers {
+		if addr.Is6() != v6 || addr.IsLoopback() {
+			continue
+		}
+		if _, ok := seenDNS[addr]; ok {
+			continue
+		}
+		seenDNS[addr] = struct{}{}
+		fmt.Fprintf(&b, "-A OUTPUT -d %s -p udp --dport 53 -j ACCEPT\n", addr)
+		fmt.Fprintf(&b, "-A OUTPUT -d %s -p tcp --dport 53 -j ACCEPT\n", addr)
+	}
+	seen := make(map[netip.Prefix]struct{}, len(allow))
+	for _, prefix := range allow {
+		if prefix.Addr().Is6() != v6 {
+			continue
+		}
+		if _, ok := seen[prefix]; ok {
+			continue
+		}
+		seen[prefix] = struct{}{}
+		fmt.Fprintf(&b, "-A OUTPUT -d %s -j ACCEPT\n", prefix)
+	}
+	b.WriteString("COMMIT\n")
+	return b.String()
+}
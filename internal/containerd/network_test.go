@@ -0,0 +1,78 @@
+package containerd
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestResolveAllowlist(t *testing.T) {
+	lookup := func(_ context.Context, _, host string) ([]netip.Addr, error) {
+		if host == "api.example.com" {
+			return []netip.Addr{netip.MustParseAddr("::ffff:203.0.113.7"), netip.MustParseAddr("2001:db8::7")}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	got := resolveAllowlist(context.Background(), []string{"10.1.2.3/16", "192.0.2.1", "api.example.com", "missing.example.com", " "}, lookup)
+	want := []string{"10.1.0.0/16", "192.0.2.1/32", "203.0.113.7/32", "2001:db8::7/128"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i, prefix := range got {
+		if prefix.String() != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestBuildEgressRules(t *testing.T) {
+	prefixes := []netip.Prefix{
+		netip.MustParsePrefix("192.0.2.1/32"),
+		netip.MustParsePrefix("192.0.2.1/32"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	}
+
+	nameservers := []netip.Addr{netip.MustParseAddr("192.0.2.53"), netip.MustParseAddr("127.0.0.53")}
+
+	v4 := buildEgressRules(prefixes, nameservers, false)
+	if !strings.Contains(v4, ":OUTPUT DROP") {
+		t.Fatalf("expected default drop policy:\n%s", v4)
+	}
+	if strings.Count(v4, "-d 192.0.2.1/32 -j ACCEPT") != 1 {
+		t.Fatalf("expected one rule for 192.0.2.1:\n%s", v4)
+	}
+	if !strings.Contains(v4, "-d 192.0.2.53 -p udp --dport 53 -j ACCEPT") || !strings.Contains(v4, "-d 192.0.2.53 -p tcp --dport 53 -j ACCEPT") {
+		t.Fatalf("expected DNS rules for the nameserver:\n%s", v4)
+	}
+	if strings.Contains(v4, "127.0.0.53") || strings.Contains(v4, "-A OUTPUT -p udp --dport 53") {
+		t.Fatalf("DNS must only be allowed to listed nameservers:\n%s", v4)
+	}
+	if strings.Contains(v4, "2001:db8::") {
+		t.Fatalf("unexpected IPv6 rule in IPv4 ruleset:\n%s", v4)
+	}
+	if !strings.HasSuffix(v4, "COMMIT\n") {
+		t.Fatalf("ruleset must end with COMMIT:\n%s", v4)
+	}
+
+	v6 := buildEgressRules(prefixes, nameservers, true)
+	if !strings.Contains(v6, "-d 2001:db8::/32 -j ACCEPT") || strings.Contains(v6, "192.0.2") {
+		t.Fatalf("unexpected IPv6 ruleset:\n%s", v6)
+	}
+}
+
+func TestParseNameservers(t *testing.T) {
+	data := []byte("# comment\nnameserver 192.0.2.53\nsearch example.com\nnameserver fe80::1%eth0\nnameserver bogus\n")
+	got := parseNameservers(data)
+	want := []string{"192.0.2.53", "fe80::1"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i, addr := range got {
+		if addr.String() != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
@@ -39,8 +39,22 @@ var (
 type PullImageOptions struct {
 	Unpack      bool
 	Snapshotter string
+	// OnProgress, when set, is called periodically while layers download.
+	// Backends that cannot report progress never call it.
+	OnProgress func(PullProgress)
 }
 
+// PullProgress is the aggregate download state of an image pull.
+type PullProgress struct {
+	// Active is the number of blobs currently downloading.
+	Active     int
+	Downloaded int64
+	Total      int64
+}
+
+// pullProgressInterval is how often pull progress is reported.
+const pullProgressInterval = 500 * time.Millisecond
+
 type DeleteImageOptions struct {
 	Synchronous bool
 }
@@ -97,18 +111,25 @@ type Service interface {
 
 	StartContainer(ctx context.Context, containerID string, opts *StartTaskOptions) error
 	StopContainer(ctx context.Context, containerID string, opts *StopTaskOptions) error
+	PauseContainer(ctx context.Context, containerID string) error
+	ResumeContainer(ctx context.Context, containerID string) error
 	DeleteTask(ctx context.Context, containerID string, opts *DeleteTaskOptions) error
 	GetTaskInfo(ctx context.Context, containerID string) (TaskInfo, error)
 	ListTasks(ctx context.Context, opts *ListTasksOptions) ([]TaskInfo, error)
 	ExecTask(ctx context.Context, containerID string, req ExecTaskRequest) (ExecTaskResult, error)
 	ExecTaskStreaming(ctx context.Context, containerID string, req ExecTaskRequest) (*ExecTaskSession, error)
 
+	UpdateContainerResources(ctx context.Context, containerID string, limits ResourceLimits) error
+	GetContainerResourceUsage(ctx context.Context, containerID string) (ResourceUsage, error)
+
 	SetupNetwork(ctx context.Context, req NetworkSetupRequest) error
 	RemoveNetwork(ctx context.Context, req NetworkSetupRequest) error
 
 	CommitSnapshot(ctx context.Context, snapshotter, name, key string) error
 	ListSnapshots(ctx context.Context, snapshotter string) ([]SnapshotInfo, error)
 	PrepareSnapshot(ctx context.Context, snapshotter, key, parent string) error
+	ViewSnapshot(ctx context.Context, snapshotter, key, parent string) ([]MountInfo, error)
+	RemoveSnapshot(ctx context.Context, snapshotter, key string) error
 	CreateContainerFromSnapshot(ctx context.Context, req CreateContainerRequest) (ContainerInfo, error)
 	SnapshotMounts(ctx context.Context, snapshotter, key string) ([]MountInfo, error)
 }
@@ -145,6 +166,12 @@ func (s *DefaultService) PullImage(ctx context.Context, ref string, opts *PullIm
 		pullOpts = append(pullOpts, containerd.WithPullSnapshotter(opts.Snapshotter))
 	}
 
+	if opts != nil && opts.OnProgress != nil {
+		done := make(chan struct{})
+		defer close(done)
+		go s.reportPullProgress(ctx, done, opts.OnProgress)
+	}
+
 	img, err := s.client.Pull(ctx, ref, pullOpts...)
 	if err != nil {
 		return ImageInfo{}, err
@@ -152,6 +179,31 @@ func (s *DefaultService) PullImage(ctx context.Context, ref string, opts *PullIm
 	return toImageInfo(img), nil
 }
 
+// reportPullProgress polls the content store ingests until done is closed.
+func (s *DefaultService) reportPullProgress(ctx context.Context, done <-chan struct{}, report func(PullProgress)) {
+	ticker := time.NewTicker(pullProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		statuses, err := s.client.ContentStore().ListStatuses(ctx)
+		if err != nil {
+			continue
+		}
+		progress := PullProgress{Active: len(statuses)}
+		for _, status := range statuses {
+			progress.Downloaded += status.Offset
+			progress.Total += status.Total
+		}
+		report(progress)
+	}
+}
+
 func (s *DefaultService) GetImage(ctx context.Context, ref string) (ImageInfo, error) {
 	if ref == "" {
 		return ImageInfo{}, ErrInvalidArgument
@@ -219,6 +271,9 @@ func specOptsFromSpec(spec ContainerSpec) []oci.SpecOpts {
 		}
 		opts = append(opts, oci.WithMounts(mounts))
 	}
+	if spec.Resources != nil {
+		opts = append(opts, resourceSpecOpts(*spec.Resources)...)
+	}
 
 	return opts
 }
@@ -480,6 +535,40 @@ func (s *DefaultService) StartContainer(ctx context.Context, containerID string,
 	return task.Start(ctx)
 }
 
+// UpdateContainerResources replaces the cgroup limits stored in the container
+// spec. The new limits take effect the next time the task is started.
+func (s *DefaultService) UpdateContainerResources(ctx context.Context, containerID string, limits ResourceLimits) error {
+	if containerID == "" {
+		return ErrInvalidArgument
+	}
+
+	ctx = s.withNamespace(ctx)
+	container, err := s.client.LoadContainer(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	spec, err := container.Spec(ctx)
+	if err != nil {
+		return err
+	}
+	clearResourceLimits(spec)
+	for _, opt := range resourceSpecOpts(limits) {
+		if err := opt(ctx, nil, nil, spec); err != nil {
+			return err
+		}
+	}
+	return container.Update(ctx, containerd.UpdateContainerOpts(containerd.WithSpec(spec)))
+}
+
+// GetContainerResourceUsage reads the cgroup usage of the running task.
+func (s *DefaultService) GetContainerResourceUsage(ctx context.Context, containerID string) (ResourceUsage, error) {
+	task, err := s.getTask(ctx, containerID)
+	if err != nil {
+		return ResourceUsage{}, err
+	}
+	return readCgroupUsage(task.Pid())
+}
+
 func (s *DefaultService) getTask(ctx context.Context, containerID string) (containerd.Task, error) {
 	if containerID == "" {
 		return nil, ErrInvalidArgument
@@ -588,6 +677,34 @@ func (s *DefaultService) StopContainer(ctx context.Context, containerID string,
 	}
 }
 
+// PauseContainer freezes the processes of the running task.
+func (s *DefaultService) PauseContainer(ctx context.Context, containerID string) error {
+	if containerID == "" {
+		return ErrInvalidArgument
+	}
+
+	ctx = s.withNamespace(ctx)
+	task, err := s.getTask(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return task.Pause(ctx)
+}
+
+// ResumeContainer thaws a task frozen by PauseContainer.
+func (s *DefaultService) ResumeContainer(ctx context.Context, containerID string) error {
+	if containerID == "" {
+		return ErrInvalidArgument
+	}
+
+	ctx = s.withNamespace(ctx)
+	task, err := s.getTask(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return task.Resume(ctx)
+}
+
 func (s *DefaultService) DeleteTask(ctx context.Context, containerID string, opts *DeleteTaskOptions) error {
 	if containerID == "" {
 		return ErrInvalidArgument
@@ -785,6 +902,9 @@ func (s *DefaultService) ExecTaskStreaming(ctx context.Context, containerID stri
 	}
 
 	closeFn := func() error {
+		if req.Terminal {
+			_ = process.Kill(ctx, syscall.SIGHUP)
+		}
 		_ = stdinW.Close()
 		_ = stdoutR.Close()
 		_ = stderrR.Close()
@@ -801,6 +921,12 @@ func (s *DefaultService) ExecTaskStreaming(ctx context.Context, containerID stri
 		Stderr: stderrR,
 		Wait:   wait,
 		Close:  closeFn,
+		Resize: func(ctx context.Context, width, height uint32) error {
+			if !req.Terminal {
+				return ErrInvalidArgument
+			}
+			return process.Resize(s.withNamespace(ctx), width, height)
+		},
 	}, nil
 }
 
@@ -888,6 +1014,38 @@ func (s *DefaultService) PrepareSnapshot(ctx context.Context, snapshotter, key,
 	return err
 }
 
+// ViewSnapshot creates a read-only view of a committed snapshot and returns
+// its mounts. The view must be removed with RemoveSnapshot.
+func (s *DefaultService) ViewSnapshot(ctx context.Context, snapshotter, key, parent string) ([]MountInfo, error) {
+	if snapshotter == "" || key == "" || parent == "" {
+		return nil, ErrInvalidArgument
+	}
+	ctx = s.withNamespace(ctx)
+	mounts, err := s.client.SnapshotService(snapshotter).View(ctx, key, parent)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]MountInfo, len(mounts))
+	for i, m := range mounts {
+		result[i] = MountInfo{
+			Type:    m.Type,
+			Source:  m.Source,
+			Options: m.Options,
+		}
+	}
+	return result, nil
+}
+
+// RemoveSnapshot removes a snapshot. Snapshots that still have children
+// cannot be removed.
+func (s *DefaultService) RemoveSnapshot(ctx context.Context, snapshotter, key string) error {
+	if snapshotter == "" || key == "" {
+		return ErrInvalidArgument
+	}
+	ctx = s.withNamespace(ctx)
+	return s.client.SnapshotService(snapshotter).Remove(ctx, key)
+}
+
 func (s *DefaultService) CreateContainerFromSnapshot(ctx context.Context, req CreateContainerRequest) (ContainerInfo, error) {
 	if req.ID == "" || req.SnapshotID == "" {
 		return ContainerInfo{}, ErrInvalidArgument
@@ -971,7 +1129,7 @@ func (s *DefaultService) SetupNetwork(ctx context.Context, req NetworkSetupReque
 	if err != nil {
 		return err
 	}
-	return setupCNINetwork(ctx, task, req.ContainerID, req.CNIBinDir, req.CNIConfDir)
+	return setupCNINetwork(ctx, task, req.ContainerID, req.CNIBinDir, req.CNIConfDir, req.Policy)
 }
 
 func (s *DefaultService) RemoveNetwork(ctx context.Context, req NetworkSetupRequest) error {
@@ -296,6 +296,14 @@ func (s *AppleService) StopContainer(ctx context.Context, containerID string, op
 	return nil
 }
 
+func (s *AppleService) PauseContainer(context.Context, string) error {
+	return ErrNotSupported
+}
+
+func (s *AppleService) ResumeContainer(context.Context, string) error {
+	return ErrNotSupported
+}
+
 func (s *AppleService) DeleteTask(context.Context, string, *DeleteTaskOptions) error {
 	return nil
 }
@@ -399,6 +407,17 @@ func (s *AppleService) ExecTaskStreaming(context.Context, string, ExecTaskReques
 	return nil, ErrNotSupported
 }
 
+// ---------------------------------------------------------------------------
+// Resources (not supported on Apple Container)
+// ---------------------------------------------------------------------------
+
+func (s *AppleService) UpdateContainerResources(context.Context, string, ResourceLimits) error {
+	return ErrNotSupported
+}
+func (s *AppleService) GetContainerResourceUsage(context.Context, string) (ResourceUsage, error) {
+	return ResourceUsage{}, ErrNotSupported
+}
+
 // ---------------------------------------------------------------------------
 // Network (no-op — Apple Container handles networking natively)
 // ---------------------------------------------------------------------------
@@ -419,6 +438,12 @@ func (s *AppleService) ListSnapshots(context.Context, string) ([]SnapshotInfo, e
 func (s *AppleService) PrepareSnapshot(context.Context, string, string, string) error {
 	return ErrNotSupported
 }
+func (s *AppleService) ViewSnapshot(context.Context, string, string, string) ([]MountInfo, error) {
+	return nil, ErrNotSupported
+}
+func (s *AppleService) RemoveSnapshot(context.Context, string, string) error {
+	return ErrNotSupported
+}
 func (s *AppleService) CreateContainerFromSnapshot(context.Context, CreateContainerRequest) (ContainerInfo, error) {
 	return ContainerInfo{}, ErrNotSupported
 }
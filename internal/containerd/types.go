@@ -1,6 +1,7 @@
 package containerd
 
 import (
+	"context"
 	"errors"
 	"io"
 	"time"
@@ -86,13 +87,40 @@ type MountSpec struct {
 }
 
 type ContainerSpec struct {
-	Cmd     []string
-	Env     []string
-	WorkDir string
-	User    string
-	Mounts  []MountSpec
-	DNS     []string
-	TTY     bool
+	Cmd       []string
+	Env       []string
+	WorkDir   string
+	User      string
+	Mounts    []MountSpec
+	DNS       []string
+	TTY       bool
+	Resources *ResourceLimits
+}
+
+// ResourceLimits are the cgroup limits of a container. Zero values mean
+// unlimited.
+type ResourceLimits struct {
+	CPUShares uint64
+	// CPUQuota is the CPU time in microseconds the container may use per
+	// CPUPeriod; CPUPeriod defaults to DefaultCPUPeriod.
+	CPUQuota    int64
+	CPUPeriod   uint64
+	MemoryBytes int64
+	PidsLimit   int64
+	// DataQuotaBytes caps the bot data directory. The runtime has no disk
+	// limit; writes through the file manager and the container file tools are
+	// rejected once they would exceed it.
+	DataQuotaBytes int64
+}
+
+// ResourceUsage is the current cgroup usage of a running container. Limits
+// are zero when unlimited.
+type ResourceUsage struct {
+	CPUUsageNanos uint64
+	MemoryBytes   uint64
+	MemoryLimit   uint64
+	Pids          uint64
+	PidsLimit     uint64
 }
 
 type NetworkSetupRequest struct {
@@ -100,6 +128,25 @@ type NetworkSetupRequest struct {
 	PID         uint32
 	CNIBinDir   string
 	CNIConfDir  string
+	// Policy restricts the container network on setup. The zero value gives
+	// full network access.
+	Policy NetworkPolicy
+}
+
+// Container network modes.
+const (
+	NetworkModeFull      = "full"
+	NetworkModeNone      = "none"
+	NetworkModeAllowlist = "allowlist"
+)
+
+// NetworkPolicy is the network access of a container. In allowlist mode only
+// the nameservers from the container's resolv.conf and the listed domains, IPs
+// and CIDRs are reachable; domains are resolved once when the network is set
+// up and are not refreshed until the container restarts.
+type NetworkPolicy struct {
+	Mode  string
+	Allow []string
 }
 
 type ExecTaskRequest struct {
@@ -119,7 +166,10 @@ type ExecTaskSession struct {
 	Stdout io.ReadCloser
 	Stderr io.ReadCloser
 	Wait   func() (ExecTaskResult, error)
-	Close  func() error
+	// Close releases the session. Terminal sessions are hung up first.
+	Close func() error
+	// Resize changes the terminal size of a Terminal session.
+	Resize func(ctx context.Context, width, height uint32) error
 }
 
 type ExecTaskResult struct {
@@ -0,0 +1,35 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/memohai/memoh/internal/conversation"
+	"github.com/memohai/memoh/internal/filewatch"
+)
+
+// FileWatchGateway runs the chat turns of file watches through the chat Resolver.
+type FileWatchGateway struct {
+	resolver *Resolver
+}
+
+// NewFileWatchGateway creates a FileWatchGateway backed by the given Resolver.
+func NewFileWatchGateway(resolver *Resolver) *FileWatchGateway {
+	return &FileWatchGateway{resolver: resolver}
+}
+
+// TriggerFileWatch sends the watch command and the changed files to the bot
+// as a chat turn in its own conversation.
+func (g *FileWatchGateway) TriggerFileWatch(ctx context.Context, botID string, payload filewatch.TriggerPayload, token string) error {
+	if g == nil || g.resolver == nil {
+		return fmt.Errorf("chat resolver not configured")
+	}
+	_, err := g.resolver.Chat(ctx, conversation.ChatRequest{
+		BotID:  botID,
+		ChatID: botID,
+		Query:  filewatch.FormatQuery(payload),
+		UserID: payload.OwnerUserID,
+		Token:  token,
+	})
+	return err
+}
@@ -161,6 +161,7 @@ type gatewayIdentity struct {
 	DisplayName       string `json:"displayName"`
 	CurrentPlatform   string `json:"currentPlatform,omitempty"`
 	ConversationType  string `json:"conversationType,omitempty"`
+	ConversationID    string `json:"conversationId,omitempty"`
 	SessionToken      string `json:"sessionToken,omitempty"`
 }
 
@@ -431,6 +432,7 @@ func (r *Resolver) resolve(ctx context.Context, req conversation.ChatRequest) (r
 			DisplayName:       displayName,
 			CurrentPlatform:   req.CurrentChannel,
 			ConversationType:  strings.TrimSpace(req.ConversationType),
+			ConversationID:    strings.TrimSpace(req.RouteID),
 			SessionToken:      req.ChatToken,
 		},
 		Attachments: attachments,
@@ -1160,6 +1162,7 @@ func (r *Resolver) loadMemoryContextMessage(ctx context.Context, req conversatio
 			"bot_id":    req.BotID,
 		},
 		NoStats: true,
+		Subject: r.memorySubject(ctx, req),
 	})
 	if err != nil {
 		r.logger.Warn("memory search for context failed",
@@ -1272,7 +1275,7 @@ func (r *Resolver) storeRound(ctx context.Context, req conversation.ChatRequest,
 	}
 
 	r.storeMessages(ctx, req, fullRound, usage, roundUsages)
-	go r.storeMemory(context.WithoutCancel(ctx), req.BotID, fullRound)
+	go r.storeMemory(context.WithoutCancel(ctx), req.BotID, r.memorySubject(ctx, req), fullRound)
 	return nil
 }
 
@@ -1526,7 +1529,18 @@ func (r *Resolver) resolveDisplayName(ctx context.Context, req conversation.Chat
 	return "User"
 }
 
-func (r *Resolver) storeMemory(ctx context.Context, botID string, messages []conversation.ModelMessage) {
+// memorySubject identifies the sender and route of req so memories can be
+// tagged and scoped by the bot's memory scope policy.
+func (r *Resolver) memorySubject(ctx context.Context, req conversation.ChatRequest) *memory.Subject {
+	channelIdentityID, userID := r.resolvePersistSenderIDs(ctx, req)
+	return &memory.Subject{
+		ChannelIdentityID: channelIdentityID,
+		UserID:            userID,
+		ConversationID:    strings.TrimSpace(req.RouteID),
+	}
+}
+
+func (r *Resolver) storeMemory(ctx context.Context, botID string, subject *memory.Subject, messages []conversation.ModelMessage) {
 	if r.memoryService == nil {
 		return
 	}
@@ -1548,10 +1562,10 @@ func (r *Resolver) storeMemory(ctx context.Context, botID string, messages []con
 	if len(memMsgs) == 0 {
 		return
 	}
-	r.addMemory(ctx, botID, memMsgs, sharedMemoryNamespace, botID)
+	r.addMemory(ctx, botID, subject, memMsgs, sharedMemoryNamespace, botID)
 }
 
-func (r *Resolver) addMemory(ctx context.Context, botID string, msgs []memory.Message, namespace, scopeID string) {
+func (r *Resolver) addMemory(ctx context.Context, botID string, subject *memory.Subject, msgs []memory.Message, namespace, scopeID string) {
 	filters := map[string]any{
 		"namespace": namespace,
 		"scopeId":   scopeID,
@@ -1561,6 +1575,7 @@ func (r *Resolver) addMemory(ctx context.Context, botID string, msgs []memory.Me
 		Messages: msgs,
 		BotID:    botID,
 		Filters:  filters,
+		Subject:  subject,
 	}); err != nil {
 		r.logger.Warn("store memory failed",
 			slog.String("namespace", namespace),
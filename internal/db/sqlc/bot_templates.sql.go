@@ -0,0 +1,135 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: bot_templates.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createBotTemplate = `-- name: CreateBotTemplate :one
+INSERT INTO bot_templates (name, description, spec)
+VALUES ($1, $2, $3)
+RETURNING id, name, description, spec, created_at, updated_at
+`
+
+type CreateBotTemplateParams struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Spec        []byte `json:"spec"`
+}
+
+func (q *Queries) CreateBotTemplate(ctx context.Context, arg CreateBotTemplateParams) (BotTemplate, error) {
+	row := q.db.QueryRow(ctx, createBotTemplate, arg.Name, arg.Description, arg.Spec)
+	var i BotTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Spec,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteBotTemplate = `-- name: DeleteBotTemplate :exec
+DELETE FROM bot_templates
+WHERE id = $1
+`
+
+func (q *Queries) DeleteBotTemplate(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteBotTemplate, id)
+	return err
+}
+
+const getBotTemplateByID = `-- name: GetBotTemplateByID :one
+SELECT id, name, description, spec, created_at, updated_at FROM bot_templates
+WHERE id = $1
+`
+
+func (q *Queries) GetBotTemplateByID(ctx context.Context, id pgtype.UUID) (BotTemplate, error) {
+	row := q.db.QueryRow(ctx, getBotTemplateByID, id)
+	var i BotTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Spec,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listBotTemplates = `-- name: ListBotTemplates :many
+SELECT id, name, description, spec, created_at, updated_at FROM bot_templates
+ORDER BY name
+`
+
+func (q *Queries) ListBotTemplates(ctx context.Context) ([]BotTemplate, error) {
+	rows, err := q.db.Query(ctx, listBotTemplates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BotTemplate
+	for rows.Next() {
+		var i BotTemplate
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Spec,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateBotTemplate = `-- name: UpdateBotTemplate :one
+UPDATE bot_templates
+SET name = $1,
+    description = $2,
+    spec = $3,
+    updated_at = now()
+WHERE id = $4
+RETURNING id, name, description, spec, created_at, updated_at
+`
+
+type UpdateBotTemplateParams struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Spec        []byte      `json:"spec"`
+	ID          pgtype.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateBotTemplate(ctx context.Context, arg UpdateBotTemplateParams) (BotTemplate, error) {
+	row := q.db.QueryRow(ctx, updateBotTemplate,
+		arg.Name,
+		arg.Description,
+		arg.Spec,
+		arg.ID,
+	)
+	var i BotTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Spec,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -11,6 +11,23 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const claimChatRoute = `-- name: ClaimChatRoute :exec
+UPDATE bot_channel_routes
+SET channel_config_id = $1, updated_at = now()
+WHERE id = $2 AND channel_config_id IS NULL
+`
+
+type ClaimChatRouteParams struct {
+	ChannelConfigID pgtype.UUID `json:"channel_config_id"`
+	ID              pgtype.UUID `json:"id"`
+}
+
+// Attaches a route whose config was deleted to the config it is used through again.
+func (q *Queries) ClaimChatRoute(ctx context.Context, arg ClaimChatRouteParams) error {
+	_, err := q.db.Exec(ctx, claimChatRoute, arg.ChannelConfigID, arg.ID)
+	return err
+}
+
 const createChatRoute = `-- name: CreateChatRoute :one
 INSERT INTO bot_channel_routes (
   bot_id, channel_type, channel_config_id, external_conversation_id, external_thread_id, conversation_type, default_reply_target, metadata
@@ -107,6 +124,22 @@ func (q *Queries) DeleteChatRoute(ctx context.Context, id pgtype.UUID) error {
 	return err
 }
 
+const deleteOrphanedChatRoutes = `-- name: DeleteOrphanedChatRoutes :exec
+DELETE FROM bot_channel_routes o
+USING bot_channel_routes c
+WHERE c.channel_config_id = $1
+  AND o.bot_id = c.bot_id
+  AND o.channel_type = c.channel_type
+  AND o.channel_config_id IS NULL
+  AND o.external_conversation_id = c.external_conversation_id
+  AND COALESCE(o.external_thread_id, '') = COALESCE(c.external_thread_id, '')
+`
+
+func (q *Queries) DeleteOrphanedChatRoutes(ctx context.Context, channelConfigID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteOrphanedChatRoutes, channelConfigID)
+	return err
+}
+
 const findChatRoute = `-- name: FindChatRoute :one
 SELECT
   id,
@@ -124,16 +157,22 @@ SELECT
 FROM bot_channel_routes
 WHERE bot_id = $1
   AND channel_type = $2
-  AND external_conversation_id = $3
-  AND COALESCE(external_thread_id, '') = COALESCE($4, '')
+  AND (
+    channel_config_id IS NOT DISTINCT FROM $3::uuid
+    OR channel_config_id IS NULL
+  )
+  AND external_conversation_id = $4
+  AND COALESCE(external_thread_id, '') = COALESCE($5, '')
+ORDER BY (channel_config_id IS NULL) ASC
 LIMIT 1
 `
 
 type FindChatRouteParams struct {
-	BotID          pgtype.UUID `json:"bot_id"`
-	Platform       string      `json:"platform"`
-	ConversationID string      `json:"conversation_id"`
-	ThreadID       pgtype.Text `json:"thread_id"`
+	BotID           pgtype.UUID `json:"bot_id"`
+	Platform        string      `json:"platform"`
+	ChannelConfigID pgtype.UUID `json:"channel_config_id"`
+	ConversationID  string      `json:"conversation_id"`
+	ThreadID        pgtype.Text `json:"thread_id"`
 }
 
 type FindChatRouteRow struct {
@@ -151,10 +190,12 @@ type FindChatRouteRow struct {
 	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
 }
 
+// A route of the requested config wins over one whose config was deleted.
 func (q *Queries) FindChatRoute(ctx context.Context, arg FindChatRouteParams) (FindChatRouteRow, error) {
 	row := q.db.QueryRow(ctx, findChatRoute,
 		arg.BotID,
 		arg.Platform,
+		arg.ChannelConfigID,
 		arg.ConversationID,
 		arg.ThreadID,
 	)
@@ -296,6 +337,28 @@ func (q *Queries) ListChatRoutes(ctx context.Context, chatID pgtype.UUID) ([]Lis
 	return items, nil
 }
 
+const moveOrphanedChatRouteHistory = `-- name: MoveOrphanedChatRouteHistory :exec
+UPDATE bot_history_messages m
+SET route_id = c.id
+FROM bot_channel_routes c
+JOIN bot_channel_routes o
+  ON o.bot_id = c.bot_id
+  AND o.channel_type = c.channel_type
+  AND o.channel_config_id IS NULL
+  AND o.external_conversation_id = c.external_conversation_id
+  AND COALESCE(o.external_thread_id, '') = COALESCE(c.external_thread_id, '')
+WHERE c.channel_config_id = $1
+  AND m.route_id = o.id
+`
+
+// Moves the history of config-less routes onto the matching routes of a
+// config, so deleting that config cannot leave two config-less routes for
+// the same conversation.
+func (q *Queries) MoveOrphanedChatRouteHistory(ctx context.Context, channelConfigID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, moveOrphanedChatRouteHistory, channelConfigID)
+	return err
+}
+
 const updateChatRouteMetadata = `-- name: UpdateChatRouteMetadata :exec
 UPDATE bot_channel_routes
 SET metadata = $1, updated_at = now()
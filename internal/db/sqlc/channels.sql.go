@@ -13,38 +13,41 @@ import (
 
 const deleteBotChannelConfig = `-- name: DeleteBotChannelConfig :exec
 DELETE FROM bot_channel_configs
-WHERE bot_id = $1 AND channel_type = $2
+WHERE bot_id = $1 AND channel_type = $2 AND name = $3
 `
 
 type DeleteBotChannelConfigParams struct {
 	BotID       pgtype.UUID `json:"bot_id"`
 	ChannelType string      `json:"channel_type"`
+	Name        string      `json:"name"`
 }
 
 func (q *Queries) DeleteBotChannelConfig(ctx context.Context, arg DeleteBotChannelConfigParams) error {
-	_, err := q.db.Exec(ctx, deleteBotChannelConfig, arg.BotID, arg.ChannelType)
+	_, err := q.db.Exec(ctx, deleteBotChannelConfig, arg.BotID, arg.ChannelType, arg.Name)
 	return err
 }
 
 const getBotChannelConfig = `-- name: GetBotChannelConfig :one
-SELECT id, bot_id, channel_type, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
+SELECT id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
 FROM bot_channel_configs
-WHERE bot_id = $1 AND channel_type = $2
+WHERE bot_id = $1 AND channel_type = $2 AND name = $3
 LIMIT 1
 `
 
 type GetBotChannelConfigParams struct {
 	BotID       pgtype.UUID `json:"bot_id"`
 	ChannelType string      `json:"channel_type"`
+	Name        string      `json:"name"`
 }
 
 func (q *Queries) GetBotChannelConfig(ctx context.Context, arg GetBotChannelConfigParams) (BotChannelConfig, error) {
-	row := q.db.QueryRow(ctx, getBotChannelConfig, arg.BotID, arg.ChannelType)
+	row := q.db.QueryRow(ctx, getBotChannelConfig, arg.BotID, arg.ChannelType, arg.Name)
 	var i BotChannelConfig
 	err := row.Scan(
 		&i.ID,
 		&i.BotID,
 		&i.ChannelType,
+		&i.Name,
 		&i.Credentials,
 		&i.ExternalIdentity,
 		&i.SelfIdentity,
@@ -59,7 +62,7 @@ func (q *Queries) GetBotChannelConfig(ctx context.Context, arg GetBotChannelConf
 }
 
 const getBotChannelConfigByExternalIdentity = `-- name: GetBotChannelConfigByExternalIdentity :one
-SELECT id, bot_id, channel_type, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
+SELECT id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
 FROM bot_channel_configs
 WHERE channel_type = $1 AND external_identity = $2
 LIMIT 1
@@ -77,6 +80,69 @@ func (q *Queries) GetBotChannelConfigByExternalIdentity(ctx context.Context, arg
 		&i.ID,
 		&i.BotID,
 		&i.ChannelType,
+		&i.Name,
+		&i.Credentials,
+		&i.ExternalIdentity,
+		&i.SelfIdentity,
+		&i.Routing,
+		&i.Capabilities,
+		&i.Disabled,
+		&i.VerifiedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getBotChannelConfigByID = `-- name: GetBotChannelConfigByID :one
+SELECT id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
+FROM bot_channel_configs
+WHERE id = $1
+`
+
+func (q *Queries) GetBotChannelConfigByID(ctx context.Context, id pgtype.UUID) (BotChannelConfig, error) {
+	row := q.db.QueryRow(ctx, getBotChannelConfigByID, id)
+	var i BotChannelConfig
+	err := row.Scan(
+		&i.ID,
+		&i.BotID,
+		&i.ChannelType,
+		&i.Name,
+		&i.Credentials,
+		&i.ExternalIdentity,
+		&i.SelfIdentity,
+		&i.Routing,
+		&i.Capabilities,
+		&i.Disabled,
+		&i.VerifiedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getDefaultBotChannelConfig = `-- name: GetDefaultBotChannelConfig :one
+SELECT id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
+FROM bot_channel_configs
+WHERE bot_id = $1 AND channel_type = $2
+ORDER BY (name = '') DESC, created_at ASC
+LIMIT 1
+`
+
+type GetDefaultBotChannelConfigParams struct {
+	BotID       pgtype.UUID `json:"bot_id"`
+	ChannelType string      `json:"channel_type"`
+}
+
+// Prefers the unnamed config, then the oldest one.
+func (q *Queries) GetDefaultBotChannelConfig(ctx context.Context, arg GetDefaultBotChannelConfigParams) (BotChannelConfig, error) {
+	row := q.db.QueryRow(ctx, getDefaultBotChannelConfig, arg.BotID, arg.ChannelType)
+	var i BotChannelConfig
+	err := row.Scan(
+		&i.ID,
+		&i.BotID,
+		&i.ChannelType,
+		&i.Name,
 		&i.Credentials,
 		&i.ExternalIdentity,
 		&i.SelfIdentity,
@@ -116,8 +182,49 @@ func (q *Queries) GetUserChannelBinding(ctx context.Context, arg GetUserChannelB
 	return i, err
 }
 
+const listBotChannelConfigsByBot = `-- name: ListBotChannelConfigsByBot :many
+SELECT id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
+FROM bot_channel_configs
+WHERE bot_id = $1
+ORDER BY channel_type, name
+`
+
+func (q *Queries) ListBotChannelConfigsByBot(ctx context.Context, botID pgtype.UUID) ([]BotChannelConfig, error) {
+	rows, err := q.db.Query(ctx, listBotChannelConfigsByBot, botID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BotChannelConfig
+	for rows.Next() {
+		var i BotChannelConfig
+		if err := rows.Scan(
+			&i.ID,
+			&i.BotID,
+			&i.ChannelType,
+			&i.Name,
+			&i.Credentials,
+			&i.ExternalIdentity,
+			&i.SelfIdentity,
+			&i.Routing,
+			&i.Capabilities,
+			&i.Disabled,
+			&i.VerifiedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listBotChannelConfigsByType = `-- name: ListBotChannelConfigsByType :many
-SELECT id, bot_id, channel_type, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
+SELECT id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
 FROM bot_channel_configs
 WHERE channel_type = $1
 ORDER BY created_at DESC
@@ -136,6 +243,7 @@ func (q *Queries) ListBotChannelConfigsByType(ctx context.Context, channelType s
 			&i.ID,
 			&i.BotID,
 			&i.ChannelType,
+			&i.Name,
 			&i.Credentials,
 			&i.ExternalIdentity,
 			&i.SelfIdentity,
@@ -193,25 +301,32 @@ func (q *Queries) ListUserChannelBindingsByPlatform(ctx context.Context, channel
 const updateBotChannelConfigDisabled = `-- name: UpdateBotChannelConfigDisabled :one
 UPDATE bot_channel_configs
 SET
-  disabled = $3,
+  disabled = $4,
   updated_at = now()
-WHERE bot_id = $1 AND channel_type = $2
-RETURNING id, bot_id, channel_type, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
+WHERE bot_id = $1 AND channel_type = $2 AND name = $3
+RETURNING id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
 `
 
 type UpdateBotChannelConfigDisabledParams struct {
 	BotID       pgtype.UUID `json:"bot_id"`
 	ChannelType string      `json:"channel_type"`
+	Name        string      `json:"name"`
 	Disabled    bool        `json:"disabled"`
 }
 
 func (q *Queries) UpdateBotChannelConfigDisabled(ctx context.Context, arg UpdateBotChannelConfigDisabledParams) (BotChannelConfig, error) {
-	row := q.db.QueryRow(ctx, updateBotChannelConfigDisabled, arg.BotID, arg.ChannelType, arg.Disabled)
+	row := q.db.QueryRow(ctx, updateBotChannelConfigDisabled,
+		arg.BotID,
+		arg.ChannelType,
+		arg.Name,
+		arg.Disabled,
+	)
 	var i BotChannelConfig
 	err := row.Scan(
 		&i.ID,
 		&i.BotID,
 		&i.ChannelType,
+		&i.Name,
 		&i.Credentials,
 		&i.ExternalIdentity,
 		&i.SelfIdentity,
@@ -227,10 +342,10 @@ func (q *Queries) UpdateBotChannelConfigDisabled(ctx context.Context, arg Update
 
 const upsertBotChannelConfig = `-- name: UpsertBotChannelConfig :one
 INSERT INTO bot_channel_configs (
-  bot_id, channel_type, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at
+  bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-ON CONFLICT (bot_id, channel_type)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (bot_id, channel_type, name)
 DO UPDATE SET
   credentials = EXCLUDED.credentials,
   external_identity = EXCLUDED.external_identity,
@@ -240,12 +355,13 @@ DO UPDATE SET
   disabled = EXCLUDED.disabled,
   verified_at = EXCLUDED.verified_at,
   updated_at = now()
-RETURNING id, bot_id, channel_type, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
+RETURNING id, bot_id, channel_type, name, credentials, external_identity, self_identity, routing, capabilities, disabled, verified_at, created_at, updated_at
 `
 
 type UpsertBotChannelConfigParams struct {
 	BotID            pgtype.UUID        `json:"bot_id"`
 	ChannelType      string             `json:"channel_type"`
+	Name             string             `json:"name"`
 	Credentials      []byte             `json:"credentials"`
 	ExternalIdentity pgtype.Text        `json:"external_identity"`
 	SelfIdentity     []byte             `json:"self_identity"`
@@ -259,6 +375,7 @@ func (q *Queries) UpsertBotChannelConfig(ctx context.Context, arg UpsertBotChann
 	row := q.db.QueryRow(ctx, upsertBotChannelConfig,
 		arg.BotID,
 		arg.ChannelType,
+		arg.Name,
 		arg.Credentials,
 		arg.ExternalIdentity,
 		arg.SelfIdentity,
@@ -272,6 +389,7 @@ func (q *Queries) UpsertBotChannelConfig(ctx context.Context, arg UpsertBotChann
 		&i.ID,
 		&i.BotID,
 		&i.ChannelType,
+		&i.Name,
 		&i.Credentials,
 		&i.ExternalIdentity,
 		&i.SelfIdentity,
@@ -11,6 +11,17 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countContainersByImage = `-- name: CountContainersByImage :one
+SELECT COUNT(*)::bigint FROM containers WHERE image = $1
+`
+
+func (q *Queries) CountContainersByImage(ctx context.Context, image string) (int64, error) {
+	row := q.db.QueryRow(ctx, countContainersByImage, image)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const deleteContainerByBotID = `-- name: DeleteContainerByBotID :exec
 DELETE FROM containers WHERE bot_id = $1
 `
@@ -20,6 +31,62 @@ func (q *Queries) DeleteContainerByBotID(ctx context.Context, botID pgtype.UUID)
 	return err
 }
 
+const getBotContainerImage = `-- name: GetBotContainerImage :one
+SELECT container_image FROM bots WHERE id = $1
+`
+
+func (q *Queries) GetBotContainerImage(ctx context.Context, id pgtype.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, getBotContainerImage, id)
+	var container_image string
+	err := row.Scan(&container_image)
+	return container_image, err
+}
+
+const getBotNetworkPolicy = `-- name: GetBotNetworkPolicy :one
+SELECT network_mode, network_allowlist
+FROM bots
+WHERE id = $1
+`
+
+type GetBotNetworkPolicyRow struct {
+	NetworkMode      string   `json:"network_mode"`
+	NetworkAllowlist []string `json:"network_allowlist"`
+}
+
+func (q *Queries) GetBotNetworkPolicy(ctx context.Context, id pgtype.UUID) (GetBotNetworkPolicyRow, error) {
+	row := q.db.QueryRow(ctx, getBotNetworkPolicy, id)
+	var i GetBotNetworkPolicyRow
+	err := row.Scan(&i.NetworkMode, &i.NetworkAllowlist)
+	return i, err
+}
+
+const getBotResourceLimits = `-- name: GetBotResourceLimits :one
+SELECT container_cpu_shares, container_cpu_limit, container_memory_mb, container_pids_limit, container_disk_quota_mb
+FROM bots
+WHERE id = $1
+`
+
+type GetBotResourceLimitsRow struct {
+	ContainerCpuShares   int32   `json:"container_cpu_shares"`
+	ContainerCpuLimit    float64 `json:"container_cpu_limit"`
+	ContainerMemoryMb    int32   `json:"container_memory_mb"`
+	ContainerPidsLimit   int32   `json:"container_pids_limit"`
+	ContainerDiskQuotaMb int32   `json:"container_disk_quota_mb"`
+}
+
+func (q *Queries) GetBotResourceLimits(ctx context.Context, id pgtype.UUID) (GetBotResourceLimitsRow, error) {
+	row := q.db.QueryRow(ctx, getBotResourceLimits, id)
+	var i GetBotResourceLimitsRow
+	err := row.Scan(
+		&i.ContainerCpuShares,
+		&i.ContainerCpuLimit,
+		&i.ContainerMemoryMb,
+		&i.ContainerPidsLimit,
+		&i.ContainerDiskQuotaMb,
+	)
+	return i, err
+}
+
 const getContainerByBotID = `-- name: GetContainerByBotID :one
 SELECT id, bot_id, container_id, container_name, image, status, namespace, auto_start, host_path, container_path, created_at, updated_at, last_started_at, last_stopped_at FROM containers WHERE bot_id = $1 ORDER BY updated_at DESC LIMIT 1
 `
@@ -85,6 +152,20 @@ func (q *Queries) ListAutoStartContainers(ctx context.Context) ([]Container, err
 	return items, nil
 }
 
+const updateBotContainerImage = `-- name: UpdateBotContainerImage :exec
+UPDATE bots SET container_image = $2, updated_at = now() WHERE id = $1
+`
+
+type UpdateBotContainerImageParams struct {
+	ID             pgtype.UUID `json:"id"`
+	ContainerImage string      `json:"container_image"`
+}
+
+func (q *Queries) UpdateBotContainerImage(ctx context.Context, arg UpdateBotContainerImageParams) error {
+	_, err := q.db.Exec(ctx, updateBotContainerImage, arg.ID, arg.ContainerImage)
+	return err
+}
+
 const updateContainerStarted = `-- name: UpdateContainerStarted :exec
 UPDATE containers
 SET status = 'running', last_started_at = now(), updated_at = now()
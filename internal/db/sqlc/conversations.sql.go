@@ -590,7 +590,7 @@ WITH updated AS (
   SET display_name = $1,
       updated_at = now()
   WHERE bots.id = $2
-  RETURNING id, owner_user_id, type, display_name, avatar_url, is_active, status, max_context_load_time, max_context_tokens, language, allow_guest, reasoning_enabled, reasoning_effort, max_inbox_items, chat_model_id, memory_model_id, embedding_model_id, search_provider_id, metadata, created_at, updated_at
+  RETURNING id, owner_user_id, type, display_name, avatar_url, is_active, status, max_context_load_time, max_context_tokens, language, allow_guest, reasoning_enabled, reasoning_effort, max_inbox_items, chat_model_id, memory_model_id, embedding_model_id, search_provider_id, rerank_enabled, rerank_model_id, rerank_top_n, memory_scope, compaction_cron, compaction_ratio, compaction_decay_days, compaction_max_memories, container_cpu_shares, container_cpu_limit, container_memory_mb, container_pids_limit, container_disk_quota_mb, network_mode, network_allowlist, container_image, snapshot_cron, snapshot_before_risky, snapshot_keep_last, snapshot_keep_daily, metadata, created_at, updated_at
 )
 SELECT
   updated.id AS id,
@@ -0,0 +1,73 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: embedding_cache.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const getEmbeddingCacheEntries = `-- name: GetEmbeddingCacheEntries :many
+SELECT content_hash, embedding
+FROM embedding_cache
+WHERE model_id = $1
+  AND content_hash = ANY($2::text[])
+`
+
+type GetEmbeddingCacheEntriesParams struct {
+	ModelID       string   `json:"model_id"`
+	ContentHashes []string `json:"content_hashes"`
+}
+
+type GetEmbeddingCacheEntriesRow struct {
+	ContentHash string    `json:"content_hash"`
+	Embedding   []float32 `json:"embedding"`
+}
+
+func (q *Queries) GetEmbeddingCacheEntries(ctx context.Context, arg GetEmbeddingCacheEntriesParams) ([]GetEmbeddingCacheEntriesRow, error) {
+	rows, err := q.db.Query(ctx, getEmbeddingCacheEntries, arg.ModelID, arg.ContentHashes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetEmbeddingCacheEntriesRow
+	for rows.Next() {
+		var i GetEmbeddingCacheEntriesRow
+		if err := rows.Scan(&i.ContentHash, &i.Embedding); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertEmbeddingCacheEntry = `-- name: UpsertEmbeddingCacheEntry :exec
+INSERT INTO embedding_cache (model_id, content_hash, dimensions, embedding)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (model_id, content_hash) DO UPDATE SET
+  dimensions = EXCLUDED.dimensions,
+  embedding = EXCLUDED.embedding,
+  created_at = now()
+`
+
+type UpsertEmbeddingCacheEntryParams struct {
+	ModelID     string    `json:"model_id"`
+	ContentHash string    `json:"content_hash"`
+	Dimensions  int32     `json:"dimensions"`
+	Embedding   []float32 `json:"embedding"`
+}
+
+func (q *Queries) UpsertEmbeddingCacheEntry(ctx context.Context, arg UpsertEmbeddingCacheEntryParams) error {
+	_, err := q.db.Exec(ctx, upsertEmbeddingCacheEntry,
+		arg.ModelID,
+		arg.ContentHash,
+		arg.Dimensions,
+		arg.Embedding,
+	)
+	return err
+}
@@ -0,0 +1,203 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: file_watches.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createFileWatch = `-- name: CreateFileWatch :one
+INSERT INTO bot_file_watches (bot_id, pattern, events, action, command, enabled)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, bot_id, pattern, events, action, command, enabled, created_at, updated_at
+`
+
+type CreateFileWatchParams struct {
+	BotID   pgtype.UUID `json:"bot_id"`
+	Pattern string      `json:"pattern"`
+	Events  []string    `json:"events"`
+	Action  string      `json:"action"`
+	Command string      `json:"command"`
+	Enabled bool        `json:"enabled"`
+}
+
+func (q *Queries) CreateFileWatch(ctx context.Context, arg CreateFileWatchParams) (BotFileWatch, error) {
+	row := q.db.QueryRow(ctx, createFileWatch,
+		arg.BotID,
+		arg.Pattern,
+		arg.Events,
+		arg.Action,
+		arg.Command,
+		arg.Enabled,
+	)
+	var i BotFileWatch
+	err := row.Scan(
+		&i.ID,
+		&i.BotID,
+		&i.Pattern,
+		&i.Events,
+		&i.Action,
+		&i.Command,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteFileWatch = `-- name: DeleteFileWatch :exec
+DELETE FROM bot_file_watches
+WHERE id = $1
+`
+
+func (q *Queries) DeleteFileWatch(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteFileWatch, id)
+	return err
+}
+
+const getFileWatchByID = `-- name: GetFileWatchByID :one
+SELECT id, bot_id, pattern, events, action, command, enabled, created_at, updated_at
+FROM bot_file_watches
+WHERE id = $1
+`
+
+func (q *Queries) GetFileWatchByID(ctx context.Context, id pgtype.UUID) (BotFileWatch, error) {
+	row := q.db.QueryRow(ctx, getFileWatchByID, id)
+	var i BotFileWatch
+	err := row.Scan(
+		&i.ID,
+		&i.BotID,
+		&i.Pattern,
+		&i.Events,
+		&i.Action,
+		&i.Command,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listEnabledFileWatches = `-- name: ListEnabledFileWatches :many
+SELECT id, bot_id, pattern, events, action, command, enabled, created_at, updated_at
+FROM bot_file_watches
+WHERE enabled = true
+ORDER BY bot_id, created_at
+`
+
+func (q *Queries) ListEnabledFileWatches(ctx context.Context) ([]BotFileWatch, error) {
+	rows, err := q.db.Query(ctx, listEnabledFileWatches)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BotFileWatch
+	for rows.Next() {
+		var i BotFileWatch
+		if err := rows.Scan(
+			&i.ID,
+			&i.BotID,
+			&i.Pattern,
+			&i.Events,
+			&i.Action,
+			&i.Command,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFileWatchesByBot = `-- name: ListFileWatchesByBot :many
+SELECT id, bot_id, pattern, events, action, command, enabled, created_at, updated_at
+FROM bot_file_watches
+WHERE bot_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListFileWatchesByBot(ctx context.Context, botID pgtype.UUID) ([]BotFileWatch, error) {
+	rows, err := q.db.Query(ctx, listFileWatchesByBot, botID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BotFileWatch
+	for rows.Next() {
+		var i BotFileWatch
+		if err := rows.Scan(
+			&i.ID,
+			&i.BotID,
+			&i.Pattern,
+			&i.Events,
+			&i.Action,
+			&i.Command,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateFileWatch = `-- name: UpdateFileWatch :one
+UPDATE bot_file_watches
+SET pattern = $2,
+    events = $3,
+    action = $4,
+    command = $5,
+    enabled = $6,
+    updated_at = now()
+WHERE id = $1
+RETURNING id, bot_id, pattern, events, action, command, enabled, created_at, updated_at
+`
+
+type UpdateFileWatchParams struct {
+	ID      pgtype.UUID `json:"id"`
+	Pattern string      `json:"pattern"`
+	Events  []string    `json:"events"`
+	Action  string      `json:"action"`
+	Command string      `json:"command"`
+	Enabled bool        `json:"enabled"`
+}
+
+func (q *Queries) UpdateFileWatch(ctx context.Context, arg UpdateFileWatchParams) (BotFileWatch, error) {
+	row := q.db.QueryRow(ctx, updateFileWatch,
+		arg.ID,
+		arg.Pattern,
+		arg.Events,
+		arg.Action,
+		arg.Command,
+		arg.Enabled,
+	)
+	var i BotFileWatch
+	err := row.Scan(
+		&i.ID,
+		&i.BotID,
+		&i.Pattern,
+		&i.Events,
+		&i.Action,
+		&i.Command,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -11,6 +11,17 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countBotStorageBindingsByProvider = `-- name: CountBotStorageBindingsByProvider :one
+SELECT COUNT(*) FROM bot_storage_bindings WHERE storage_provider_id = $1
+`
+
+func (q *Queries) CountBotStorageBindingsByProvider(ctx context.Context, storageProviderID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countBotStorageBindingsByProvider, storageProviderID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createMessageAsset = `-- name: CreateMessageAsset :one
 INSERT INTO bot_history_message_assets (message_id, role, ordinal, content_hash)
 VALUES (
@@ -77,6 +88,15 @@ func (q *Queries) CreateStorageProvider(ctx context.Context, arg CreateStoragePr
 	return i, err
 }
 
+const deleteBotStorageBinding = `-- name: DeleteBotStorageBinding :exec
+DELETE FROM bot_storage_bindings WHERE bot_id = $1
+`
+
+func (q *Queries) DeleteBotStorageBinding(ctx context.Context, botID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteBotStorageBinding, botID)
+	return err
+}
+
 const deleteMessageAssets = `-- name: DeleteMessageAssets :exec
 DELETE FROM bot_history_message_assets WHERE message_id = $1
 `
@@ -86,6 +106,15 @@ func (q *Queries) DeleteMessageAssets(ctx context.Context, messageID pgtype.UUID
 	return err
 }
 
+const deleteStorageProvider = `-- name: DeleteStorageProvider :exec
+DELETE FROM storage_providers WHERE id = $1
+`
+
+func (q *Queries) DeleteStorageProvider(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteStorageProvider, id)
+	return err
+}
+
 const getBotStorageBinding = `-- name: GetBotStorageBinding :one
 SELECT id, bot_id, storage_provider_id, base_path, created_at, updated_at FROM bot_storage_bindings WHERE bot_id = $1
 `
@@ -140,6 +169,34 @@ func (q *Queries) GetStorageProviderByName(ctx context.Context, name string) (St
 	return i, err
 }
 
+const listBotAssetContentHashes = `-- name: ListBotAssetContentHashes :many
+SELECT DISTINCT a.content_hash
+FROM bot_history_message_assets a
+JOIN bot_history_messages m ON m.id = a.message_id
+WHERE m.bot_id = $1
+ORDER BY a.content_hash
+`
+
+func (q *Queries) ListBotAssetContentHashes(ctx context.Context, botID pgtype.UUID) ([]string, error) {
+	rows, err := q.db.Query(ctx, listBotAssetContentHashes, botID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var content_hash string
+		if err := rows.Scan(&content_hash); err != nil {
+			return nil, err
+		}
+		items = append(items, content_hash)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listMessageAssets = `-- name: ListMessageAssets :many
 SELECT id AS rel_id, message_id, role, ordinal, content_hash
 FROM bot_history_message_assets
@@ -253,6 +310,43 @@ func (q *Queries) ListStorageProviders(ctx context.Context) ([]StorageProvider,
 	return items, nil
 }
 
+const updateStorageProvider = `-- name: UpdateStorageProvider :one
+UPDATE storage_providers
+SET
+  name = $1,
+  provider = $2,
+  config = $3,
+  updated_at = now()
+WHERE id = $4
+RETURNING id, name, provider, config, created_at, updated_at
+`
+
+type UpdateStorageProviderParams struct {
+	Name     string      `json:"name"`
+	Provider string      `json:"provider"`
+	Config   []byte      `json:"config"`
+	ID       pgtype.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateStorageProvider(ctx context.Context, arg UpdateStorageProviderParams) (StorageProvider, error) {
+	row := q.db.QueryRow(ctx, updateStorageProvider,
+		arg.Name,
+		arg.Provider,
+		arg.Config,
+		arg.ID,
+	)
+	var i StorageProvider
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Provider,
+		&i.Config,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const upsertBotStorageBinding = `-- name: UpsertBotStorageBinding :one
 INSERT INTO bot_storage_bindings (bot_id, storage_provider_id, base_path)
 VALUES ($1, $2, $3)
@@ -0,0 +1,181 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: memory_compaction.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createMemoryCompactionRun = `-- name: CreateMemoryCompactionRun :one
+INSERT INTO memory_compaction_runs (bot_id, trigger, dry_run, status, ratio, decay_days, before_count, after_count, merges, error, started_at)
+VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  $8,
+  $9,
+  $10,
+  $11
+)
+RETURNING id, bot_id, trigger, dry_run, status, ratio, decay_days, before_count, after_count, merges, error, started_at, finished_at
+`
+
+type CreateMemoryCompactionRunParams struct {
+	BotID       pgtype.UUID        `json:"bot_id"`
+	Trigger     string             `json:"trigger"`
+	DryRun      bool               `json:"dry_run"`
+	Status      string             `json:"status"`
+	Ratio       float64            `json:"ratio"`
+	DecayDays   int32              `json:"decay_days"`
+	BeforeCount int32              `json:"before_count"`
+	AfterCount  int32              `json:"after_count"`
+	Merges      []byte             `json:"merges"`
+	Error       string             `json:"error"`
+	StartedAt   pgtype.Timestamptz `json:"started_at"`
+}
+
+func (q *Queries) CreateMemoryCompactionRun(ctx context.Context, arg CreateMemoryCompactionRunParams) (MemoryCompactionRun, error) {
+	row := q.db.QueryRow(ctx, createMemoryCompactionRun,
+		arg.BotID,
+		arg.Trigger,
+		arg.DryRun,
+		arg.Status,
+		arg.Ratio,
+		arg.DecayDays,
+		arg.BeforeCount,
+		arg.AfterCount,
+		arg.Merges,
+		arg.Error,
+		arg.StartedAt,
+	)
+	var i MemoryCompactionRun
+	err := row.Scan(
+		&i.ID,
+		&i.BotID,
+		&i.Trigger,
+		&i.DryRun,
+		&i.Status,
+		&i.Ratio,
+		&i.DecayDays,
+		&i.BeforeCount,
+		&i.AfterCount,
+		&i.Merges,
+		&i.Error,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const getBotCompactionSettings = `-- name: GetBotCompactionSettings :one
+SELECT id, compaction_cron, compaction_ratio, compaction_decay_days, compaction_max_memories
+FROM bots
+WHERE id = $1
+`
+
+type GetBotCompactionSettingsRow struct {
+	ID                    pgtype.UUID `json:"id"`
+	CompactionCron        string      `json:"compaction_cron"`
+	CompactionRatio       float64     `json:"compaction_ratio"`
+	CompactionDecayDays   int32       `json:"compaction_decay_days"`
+	CompactionMaxMemories int32       `json:"compaction_max_memories"`
+}
+
+func (q *Queries) GetBotCompactionSettings(ctx context.Context, id pgtype.UUID) (GetBotCompactionSettingsRow, error) {
+	row := q.db.QueryRow(ctx, getBotCompactionSettings, id)
+	var i GetBotCompactionSettingsRow
+	err := row.Scan(
+		&i.ID,
+		&i.CompactionCron,
+		&i.CompactionRatio,
+		&i.CompactionDecayDays,
+		&i.CompactionMaxMemories,
+	)
+	return i, err
+}
+
+const listBotCompactionSchedules = `-- name: ListBotCompactionSchedules :many
+SELECT id, compaction_cron
+FROM bots
+WHERE compaction_cron <> ''
+`
+
+type ListBotCompactionSchedulesRow struct {
+	ID             pgtype.UUID `json:"id"`
+	CompactionCron string      `json:"compaction_cron"`
+}
+
+func (q *Queries) ListBotCompactionSchedules(ctx context.Context) ([]ListBotCompactionSchedulesRow, error) {
+	rows, err := q.db.Query(ctx, listBotCompactionSchedules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBotCompactionSchedulesRow
+	for rows.Next() {
+		var i ListBotCompactionSchedulesRow
+		if err := rows.Scan(&i.ID, &i.CompactionCron); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMemoryCompactionRuns = `-- name: ListMemoryCompactionRuns :many
+SELECT id, bot_id, trigger, dry_run, status, ratio, decay_days, before_count, after_count, merges, error, started_at, finished_at FROM memory_compaction_runs
+WHERE bot_id = $1
+ORDER BY started_at DESC, id DESC
+LIMIT $2
+`
+
+type ListMemoryCompactionRunsParams struct {
+	BotID    pgtype.UUID `json:"bot_id"`
+	MaxCount int32       `json:"max_count"`
+}
+
+func (q *Queries) ListMemoryCompactionRuns(ctx context.Context, arg ListMemoryCompactionRunsParams) ([]MemoryCompactionRun, error) {
+	rows, err := q.db.Query(ctx, listMemoryCompactionRuns, arg.BotID, arg.MaxCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MemoryCompactionRun
+	for rows.Next() {
+		var i MemoryCompactionRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.BotID,
+			&i.Trigger,
+			&i.DryRun,
+			&i.Status,
+			&i.Ratio,
+			&i.DecayDays,
+			&i.BeforeCount,
+			&i.AfterCount,
+			&i.Merges,
+			&i.Error,
+			&i.StartedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,145 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: memory_revisions.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createMemoryRevision = `-- name: CreateMemoryRevision :one
+INSERT INTO memory_revisions (bot_id, memory_id, action, previous_text, new_text, payload, source_messages, model, restored_from)
+VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  $8,
+  $9
+)
+RETURNING id, bot_id, memory_id, action, previous_text, new_text, payload, source_messages, model, restored_from, created_at
+`
+
+type CreateMemoryRevisionParams struct {
+	BotID          pgtype.UUID `json:"bot_id"`
+	MemoryID       pgtype.UUID `json:"memory_id"`
+	Action         string      `json:"action"`
+	PreviousText   string      `json:"previous_text"`
+	NewText        string      `json:"new_text"`
+	Payload        []byte      `json:"payload"`
+	SourceMessages []byte      `json:"source_messages"`
+	Model          string      `json:"model"`
+	RestoredFrom   pgtype.UUID `json:"restored_from"`
+}
+
+func (q *Queries) CreateMemoryRevision(ctx context.Context, arg CreateMemoryRevisionParams) (MemoryRevision, error) {
+	row := q.db.QueryRow(ctx, createMemoryRevision,
+		arg.BotID,
+		arg.MemoryID,
+		arg.Action,
+		arg.PreviousText,
+		arg.NewText,
+		arg.Payload,
+		arg.SourceMessages,
+		arg.Model,
+		arg.RestoredFrom,
+	)
+	var i MemoryRevision
+	err := row.Scan(
+		&i.ID,
+		&i.BotID,
+		&i.MemoryID,
+		&i.Action,
+		&i.PreviousText,
+		&i.NewText,
+		&i.Payload,
+		&i.SourceMessages,
+		&i.Model,
+		&i.RestoredFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getMemoryRevision = `-- name: GetMemoryRevision :one
+SELECT id, bot_id, memory_id, action, previous_text, new_text, payload, source_messages, model, restored_from, created_at FROM memory_revisions
+WHERE id = $1
+  AND bot_id = $2
+`
+
+type GetMemoryRevisionParams struct {
+	ID    pgtype.UUID `json:"id"`
+	BotID pgtype.UUID `json:"bot_id"`
+}
+
+func (q *Queries) GetMemoryRevision(ctx context.Context, arg GetMemoryRevisionParams) (MemoryRevision, error) {
+	row := q.db.QueryRow(ctx, getMemoryRevision, arg.ID, arg.BotID)
+	var i MemoryRevision
+	err := row.Scan(
+		&i.ID,
+		&i.BotID,
+		&i.MemoryID,
+		&i.Action,
+		&i.PreviousText,
+		&i.NewText,
+		&i.Payload,
+		&i.SourceMessages,
+		&i.Model,
+		&i.RestoredFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listMemoryRevisions = `-- name: ListMemoryRevisions :many
+SELECT id, bot_id, memory_id, action, previous_text, new_text, payload, source_messages, model, restored_from, created_at FROM memory_revisions
+WHERE bot_id = $1
+  AND memory_id = $2
+ORDER BY created_at DESC, id DESC
+LIMIT $3
+`
+
+type ListMemoryRevisionsParams struct {
+	BotID    pgtype.UUID `json:"bot_id"`
+	MemoryID pgtype.UUID `json:"memory_id"`
+	MaxCount int32       `json:"max_count"`
+}
+
+func (q *Queries) ListMemoryRevisions(ctx context.Context, arg ListMemoryRevisionsParams) ([]MemoryRevision, error) {
+	rows, err := q.db.Query(ctx, listMemoryRevisions, arg.BotID, arg.MemoryID, arg.MaxCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MemoryRevision
+	for rows.Next() {
+		var i MemoryRevision
+		if err := rows.Scan(
+			&i.ID,
+			&i.BotID,
+			&i.MemoryID,
+			&i.Action,
+			&i.PreviousText,
+			&i.NewText,
+			&i.Payload,
+			&i.SourceMessages,
+			&i.Model,
+			&i.RestoredFrom,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -9,33 +9,54 @@ import (
 )
 
 type Bot struct {
-	ID                 pgtype.UUID        `json:"id"`
-	OwnerUserID        pgtype.UUID        `json:"owner_user_id"`
-	Type               string             `json:"type"`
-	DisplayName        pgtype.Text        `json:"display_name"`
-	AvatarUrl          pgtype.Text        `json:"avatar_url"`
-	IsActive           bool               `json:"is_active"`
-	Status             string             `json:"status"`
-	MaxContextLoadTime int32              `json:"max_context_load_time"`
-	MaxContextTokens   int32              `json:"max_context_tokens"`
-	Language           string             `json:"language"`
-	AllowGuest         bool               `json:"allow_guest"`
-	ReasoningEnabled   bool               `json:"reasoning_enabled"`
-	ReasoningEffort    string             `json:"reasoning_effort"`
-	MaxInboxItems      int32              `json:"max_inbox_items"`
-	ChatModelID        pgtype.UUID        `json:"chat_model_id"`
-	MemoryModelID      pgtype.UUID        `json:"memory_model_id"`
-	EmbeddingModelID   pgtype.UUID        `json:"embedding_model_id"`
-	SearchProviderID   pgtype.UUID        `json:"search_provider_id"`
-	Metadata           []byte             `json:"metadata"`
-	CreatedAt          pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	ID                    pgtype.UUID        `json:"id"`
+	OwnerUserID           pgtype.UUID        `json:"owner_user_id"`
+	Type                  string             `json:"type"`
+	DisplayName           pgtype.Text        `json:"display_name"`
+	AvatarUrl             pgtype.Text        `json:"avatar_url"`
+	IsActive              bool               `json:"is_active"`
+	Status                string             `json:"status"`
+	MaxContextLoadTime    int32              `json:"max_context_load_time"`
+	MaxContextTokens      int32              `json:"max_context_tokens"`
+	Language              string             `json:"language"`
+	AllowGuest            bool               `json:"allow_guest"`
+	ReasoningEnabled      bool               `json:"reasoning_enabled"`
+	ReasoningEffort       string             `json:"reasoning_effort"`
+	MaxInboxItems         int32              `json:"max_inbox_items"`
+	ChatModelID           pgtype.UUID        `json:"chat_model_id"`
+	MemoryModelID         pgtype.UUID        `json:"memory_model_id"`
+	EmbeddingModelID      pgtype.UUID        `json:"embedding_model_id"`
+	SearchProviderID      pgtype.UUID        `json:"search_provider_id"`
+	RerankEnabled         bool               `json:"rerank_enabled"`
+	RerankModelID         pgtype.UUID        `json:"rerank_model_id"`
+	RerankTopN            int32              `json:"rerank_top_n"`
+	MemoryScope           string             `json:"memory_scope"`
+	CompactionCron        string             `json:"compaction_cron"`
+	CompactionRatio       float64            `json:"compaction_ratio"`
+	CompactionDecayDays   int32              `json:"compaction_decay_days"`
+	CompactionMaxMemories int32              `json:"compaction_max_memories"`
+	ContainerCpuShares    int32              `json:"container_cpu_shares"`
+	ContainerCpuLimit     float64            `json:"container_cpu_limit"`
+	ContainerMemoryMb     int32              `json:"container_memory_mb"`
+	ContainerPidsLimit    int32              `json:"container_pids_limit"`
+	ContainerDiskQuotaMb  int32              `json:"container_disk_quota_mb"`
+	NetworkMode           string             `json:"network_mode"`
+	NetworkAllowlist      []string           `json:"network_allowlist"`
+	ContainerImage        string             `json:"container_image"`
+	SnapshotCron          string             `json:"snapshot_cron"`
+	SnapshotBeforeRisky   bool               `json:"snapshot_before_risky"`
+	SnapshotKeepLast      int32              `json:"snapshot_keep_last"`
+	SnapshotKeepDaily     int32              `json:"snapshot_keep_daily"`
+	Metadata              []byte             `json:"metadata"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
 }
 
 type BotChannelConfig struct {
 	ID               pgtype.UUID        `json:"id"`
 	BotID            pgtype.UUID        `json:"bot_id"`
 	ChannelType      string             `json:"channel_type"`
+	Name             string             `json:"name"`
 	Credentials      []byte             `json:"credentials"`
 	ExternalIdentity pgtype.Text        `json:"external_identity"`
 	SelfIdentity     []byte             `json:"self_identity"`
@@ -61,6 +82,18 @@ type BotChannelRoute struct {
 	UpdatedAt              pgtype.Timestamptz `json:"updated_at"`
 }
 
+type BotFileWatch struct {
+	ID        pgtype.UUID        `json:"id"`
+	BotID     pgtype.UUID        `json:"bot_id"`
+	Pattern   string             `json:"pattern"`
+	Events    []string           `json:"events"`
+	Action    string             `json:"action"`
+	Command   string             `json:"command"`
+	Enabled   bool               `json:"enabled"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
 type BotHistoryMessage struct {
 	ID                      pgtype.UUID        `json:"id"`
 	BotID                   pgtype.UUID        `json:"bot_id"`
@@ -122,6 +155,15 @@ type BotStorageBinding struct {
 	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
 }
 
+type BotTemplate struct {
+	ID          pgtype.UUID        `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Spec        []byte             `json:"spec"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
 type ChannelIdentity struct {
 	ID               pgtype.UUID        `json:"id"`
 	UserID           pgtype.UUID        `json:"user_id"`
@@ -170,6 +212,14 @@ type ContainerVersion struct {
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 }
 
+type EmbeddingCache struct {
+	ModelID     string             `json:"model_id"`
+	ContentHash string             `json:"content_hash"`
+	Dimensions  int32              `json:"dimensions"`
+	Embedding   []float32          `json:"embedding"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
 type LifecycleEvent struct {
 	ID          string             `json:"id"`
 	ContainerID string             `json:"container_id"`
@@ -216,6 +266,36 @@ type MediaAsset struct {
 	CreatedAt         pgtype.Timestamptz `json:"created_at"`
 }
 
+type MemoryCompactionRun struct {
+	ID          pgtype.UUID        `json:"id"`
+	BotID       pgtype.UUID        `json:"bot_id"`
+	Trigger     string             `json:"trigger"`
+	DryRun      bool               `json:"dry_run"`
+	Status      string             `json:"status"`
+	Ratio       float64            `json:"ratio"`
+	DecayDays   int32              `json:"decay_days"`
+	BeforeCount int32              `json:"before_count"`
+	AfterCount  int32              `json:"after_count"`
+	Merges      []byte             `json:"merges"`
+	Error       string             `json:"error"`
+	StartedAt   pgtype.Timestamptz `json:"started_at"`
+	FinishedAt  pgtype.Timestamptz `json:"finished_at"`
+}
+
+type MemoryRevision struct {
+	ID             pgtype.UUID        `json:"id"`
+	BotID          pgtype.UUID        `json:"bot_id"`
+	MemoryID       pgtype.UUID        `json:"memory_id"`
+	Action         string             `json:"action"`
+	PreviousText   string             `json:"previous_text"`
+	NewText        string             `json:"new_text"`
+	Payload        []byte             `json:"payload"`
+	SourceMessages []byte             `json:"source_messages"`
+	Model          string             `json:"model"`
+	RestoredFrom   pgtype.UUID        `json:"restored_from"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
 type Model struct {
 	ID                pgtype.UUID        `json:"id"`
 	ModelID           string             `json:"model_id"`
@@ -24,6 +24,25 @@ SET max_context_load_time = 1440,
     memory_model_id = NULL,
     embedding_model_id = NULL,
     search_provider_id = NULL,
+    rerank_enabled = false,
+    rerank_model_id = NULL,
+    rerank_top_n = 20,
+    memory_scope = 'shared',
+    compaction_cron = '',
+    compaction_ratio = 0.5,
+    compaction_decay_days = 0,
+    compaction_max_memories = 0,
+    container_cpu_shares = 0,
+    container_cpu_limit = 0,
+    container_memory_mb = 0,
+    container_pids_limit = 0,
+    container_disk_quota_mb = 0,
+    network_mode = 'full',
+    network_allowlist = '{}',
+    snapshot_cron = '',
+    snapshot_before_risky = false,
+    snapshot_keep_last = 0,
+    snapshot_keep_daily = 0,
     updated_at = now()
 WHERE id = $1
 `
@@ -43,31 +62,70 @@ SELECT
   bots.allow_guest,
   bots.reasoning_enabled,
   bots.reasoning_effort,
+  bots.rerank_enabled,
+  bots.rerank_top_n,
+  bots.memory_scope,
+  bots.compaction_cron,
+  bots.compaction_ratio,
+  bots.compaction_decay_days,
+  bots.compaction_max_memories,
+  bots.container_cpu_shares,
+  bots.container_cpu_limit,
+  bots.container_memory_mb,
+  bots.container_pids_limit,
+  bots.container_disk_quota_mb,
+  bots.network_mode,
+  bots.network_allowlist,
+  bots.snapshot_cron,
+  bots.snapshot_before_risky,
+  bots.snapshot_keep_last,
+  bots.snapshot_keep_daily,
   chat_models.id AS chat_model_id,
   memory_models.id AS memory_model_id,
   embedding_models.id AS embedding_model_id,
-  search_providers.id AS search_provider_id
+  search_providers.id AS search_provider_id,
+  rerank_models.id AS rerank_model_id
 FROM bots
 LEFT JOIN models AS chat_models ON chat_models.id = bots.chat_model_id
 LEFT JOIN models AS memory_models ON memory_models.id = bots.memory_model_id
 LEFT JOIN models AS embedding_models ON embedding_models.id = bots.embedding_model_id
 LEFT JOIN search_providers ON search_providers.id = bots.search_provider_id
+LEFT JOIN models AS rerank_models ON rerank_models.id = bots.rerank_model_id
 WHERE bots.id = $1
 `
 
 type GetSettingsByBotIDRow struct {
-	BotID              pgtype.UUID `json:"bot_id"`
-	MaxContextLoadTime int32       `json:"max_context_load_time"`
-	MaxContextTokens   int32       `json:"max_context_tokens"`
-	MaxInboxItems      int32       `json:"max_inbox_items"`
-	Language           string      `json:"language"`
-	AllowGuest         bool        `json:"allow_guest"`
-	ReasoningEnabled   bool        `json:"reasoning_enabled"`
-	ReasoningEffort    string      `json:"reasoning_effort"`
-	ChatModelID        pgtype.UUID `json:"chat_model_id"`
-	MemoryModelID      pgtype.UUID `json:"memory_model_id"`
-	EmbeddingModelID   pgtype.UUID `json:"embedding_model_id"`
-	SearchProviderID   pgtype.UUID `json:"search_provider_id"`
+	BotID                 pgtype.UUID `json:"bot_id"`
+	MaxContextLoadTime    int32       `json:"max_context_load_time"`
+	MaxContextTokens      int32       `json:"max_context_tokens"`
+	MaxInboxItems         int32       `json:"max_inbox_items"`
+	Language              string      `json:"language"`
+	AllowGuest            bool        `json:"allow_guest"`
+	ReasoningEnabled      bool        `json:"reasoning_enabled"`
+	ReasoningEffort       string      `json:"reasoning_effort"`
+	RerankEnabled         bool        `json:"rerank_enabled"`
+	RerankTopN            int32       `json:"rerank_top_n"`
+	MemoryScope           string      `json:"memory_scope"`
+	CompactionCron        string      `json:"compaction_cron"`
+	CompactionRatio       float64     `json:"compaction_ratio"`
+	CompactionDecayDays   int32       `json:"compaction_decay_days"`
+	CompactionMaxMemories int32       `json:"compaction_max_memories"`
+	ContainerCpuShares    int32       `json:"container_cpu_shares"`
+	ContainerCpuLimit     float64     `json:"container_cpu_limit"`
+	ContainerMemoryMb     int32       `json:"container_memory_mb"`
+	ContainerPidsLimit    int32       `json:"container_pids_limit"`
+	ContainerDiskQuotaMb  int32       `json:"container_disk_quota_mb"`
+	NetworkMode           string      `json:"network_mode"`
+	NetworkAllowlist      []string    `json:"network_allowlist"`
+	SnapshotCron          string      `json:"snapshot_cron"`
+	SnapshotBeforeRisky   bool        `json:"snapshot_before_risky"`
+	SnapshotKeepLast      int32       `json:"snapshot_keep_last"`
+	SnapshotKeepDaily     int32       `json:"snapshot_keep_daily"`
+	ChatModelID           pgtype.UUID `json:"chat_model_id"`
+	MemoryModelID         pgtype.UUID `json:"memory_model_id"`
+	EmbeddingModelID      pgtype.UUID `json:"embedding_model_id"`
+	SearchProviderID      pgtype.UUID `json:"search_provider_id"`
+	RerankModelID         pgtype.UUID `json:"rerank_model_id"`
 }
 
 func (q *Queries) GetSettingsByBotID(ctx context.Context, id pgtype.UUID) (GetSettingsByBotIDRow, error) {
@@ -82,10 +140,29 @@ func (q *Queries) GetSettingsByBotID(ctx context.Context, id pgtype.UUID) (GetSe
 		&i.AllowGuest,
 		&i.ReasoningEnabled,
 		&i.ReasoningEffort,
+		&i.RerankEnabled,
+		&i.RerankTopN,
+		&i.MemoryScope,
+		&i.CompactionCron,
+		&i.CompactionRatio,
+		&i.CompactionDecayDays,
+		&i.CompactionMaxMemories,
+		&i.ContainerCpuShares,
+		&i.ContainerCpuLimit,
+		&i.ContainerMemoryMb,
+		&i.ContainerPidsLimit,
+		&i.ContainerDiskQuotaMb,
+		&i.NetworkMode,
+		&i.NetworkAllowlist,
+		&i.SnapshotCron,
+		&i.SnapshotBeforeRisky,
+		&i.SnapshotKeepLast,
+		&i.SnapshotKeepDaily,
 		&i.ChatModelID,
 		&i.MemoryModelID,
 		&i.EmbeddingModelID,
 		&i.SearchProviderID,
+		&i.RerankModelID,
 	)
 	return i, err
 }
@@ -104,9 +181,28 @@ WITH updated AS (
       memory_model_id = COALESCE($9::uuid, bots.memory_model_id),
       embedding_model_id = COALESCE($10::uuid, bots.embedding_model_id),
       search_provider_id = COALESCE($11::uuid, bots.search_provider_id),
+      rerank_enabled = COALESCE($12::boolean, bots.rerank_enabled),
+      rerank_model_id = COALESCE($13::uuid, bots.rerank_model_id),
+      rerank_top_n = COALESCE($14::integer, bots.rerank_top_n),
+      memory_scope = COALESCE($15::text, bots.memory_scope),
+      compaction_cron = COALESCE($16::text, bots.compaction_cron),
+      compaction_ratio = COALESCE($17::double precision, bots.compaction_ratio),
+      compaction_decay_days = COALESCE($18::integer, bots.compaction_decay_days),
+      compaction_max_memories = COALESCE($19::integer, bots.compaction_max_memories),
+      container_cpu_shares = COALESCE($20::integer, bots.container_cpu_shares),
+      container_cpu_limit = COALESCE($21::double precision, bots.container_cpu_limit),
+      container_memory_mb = COALESCE($22::integer, bots.container_memory_mb),
+      container_pids_limit = COALESCE($23::integer, bots.container_pids_limit),
+      container_disk_quota_mb = COALESCE($24::integer, bots.container_disk_quota_mb),
+      network_mode = COALESCE($25::text, bots.network_mode),
+      network_allowlist = COALESCE($26::text[], bots.network_allowlist),
+      snapshot_cron = COALESCE($27::text, bots.snapshot_cron),
+      snapshot_before_risky = COALESCE($28::boolean, bots.snapshot_before_risky),
+      snapshot_keep_last = COALESCE($29::integer, bots.snapshot_keep_last),
+      snapshot_keep_daily = COALESCE($30::integer, bots.snapshot_keep_daily),
       updated_at = now()
-  WHERE bots.id = $12
-  RETURNING bots.id, bots.max_context_load_time, bots.max_context_tokens, bots.max_inbox_items, bots.language, bots.allow_guest, bots.reasoning_enabled, bots.reasoning_effort, bots.chat_model_id, bots.memory_model_id, bots.embedding_model_id, bots.search_provider_id
+  WHERE bots.id = $31
+  RETURNING bots.id, bots.max_context_load_time, bots.max_context_tokens, bots.max_inbox_items, bots.language, bots.allow_guest, bots.reasoning_enabled, bots.reasoning_effort, bots.rerank_enabled, bots.rerank_top_n, bots.memory_scope, bots.compaction_cron, bots.compaction_ratio, bots.compaction_decay_days, bots.compaction_max_memories, bots.container_cpu_shares, bots.container_cpu_limit, bots.container_memory_mb, bots.container_pids_limit, bots.container_disk_quota_mb, bots.network_mode, bots.network_allowlist, bots.snapshot_cron, bots.snapshot_before_risky, bots.snapshot_keep_last, bots.snapshot_keep_daily, bots.chat_model_id, bots.memory_model_id, bots.embedding_model_id, bots.search_provider_id, bots.rerank_model_id
 )
 SELECT
   updated.id AS bot_id,
@@ -117,45 +213,103 @@ SELECT
   updated.allow_guest,
   updated.reasoning_enabled,
   updated.reasoning_effort,
+  updated.rerank_enabled,
+  updated.rerank_top_n,
+  updated.memory_scope,
+  updated.compaction_cron,
+  updated.compaction_ratio,
+  updated.compaction_decay_days,
+  updated.compaction_max_memories,
+  updated.container_cpu_shares,
+  updated.container_cpu_limit,
+  updated.container_memory_mb,
+  updated.container_pids_limit,
+  updated.container_disk_quota_mb,
+  updated.network_mode,
+  updated.network_allowlist,
+  updated.snapshot_cron,
+  updated.snapshot_before_risky,
+  updated.snapshot_keep_last,
+  updated.snapshot_keep_daily,
   chat_models.id AS chat_model_id,
   memory_models.id AS memory_model_id,
   embedding_models.id AS embedding_model_id,
-  search_providers.id AS search_provider_id
+  search_providers.id AS search_provider_id,
+  rerank_models.id AS rerank_model_id
 FROM updated
 LEFT JOIN models AS chat_models ON chat_models.id = updated.chat_model_id
 LEFT JOIN models AS memory_models ON memory_models.id = updated.memory_model_id
 LEFT JOIN models AS embedding_models ON embedding_models.id = updated.embedding_model_id
 LEFT JOIN search_providers ON search_providers.id = updated.search_provider_id
+LEFT JOIN models AS rerank_models ON rerank_models.id = updated.rerank_model_id
 `
 
 type UpsertBotSettingsParams struct {
-	MaxContextLoadTime int32       `json:"max_context_load_time"`
-	MaxContextTokens   int32       `json:"max_context_tokens"`
-	MaxInboxItems      int32       `json:"max_inbox_items"`
-	Language           string      `json:"language"`
-	AllowGuest         bool        `json:"allow_guest"`
-	ReasoningEnabled   bool        `json:"reasoning_enabled"`
-	ReasoningEffort    string      `json:"reasoning_effort"`
-	ChatModelID        pgtype.UUID `json:"chat_model_id"`
-	MemoryModelID      pgtype.UUID `json:"memory_model_id"`
-	EmbeddingModelID   pgtype.UUID `json:"embedding_model_id"`
-	SearchProviderID   pgtype.UUID `json:"search_provider_id"`
-	ID                 pgtype.UUID `json:"id"`
+	MaxContextLoadTime    int32         `json:"max_context_load_time"`
+	MaxContextTokens      int32         `json:"max_context_tokens"`
+	MaxInboxItems         int32         `json:"max_inbox_items"`
+	Language              string        `json:"language"`
+	AllowGuest            bool          `json:"allow_guest"`
+	ReasoningEnabled      bool          `json:"reasoning_enabled"`
+	ReasoningEffort       string        `json:"reasoning_effort"`
+	ChatModelID           pgtype.UUID   `json:"chat_model_id"`
+	MemoryModelID         pgtype.UUID   `json:"memory_model_id"`
+	EmbeddingModelID      pgtype.UUID   `json:"embedding_model_id"`
+	SearchProviderID      pgtype.UUID   `json:"search_provider_id"`
+	RerankEnabled         pgtype.Bool   `json:"rerank_enabled"`
+	RerankModelID         pgtype.UUID   `json:"rerank_model_id"`
+	RerankTopN            pgtype.Int4   `json:"rerank_top_n"`
+	MemoryScope           pgtype.Text   `json:"memory_scope"`
+	CompactionCron        pgtype.Text   `json:"compaction_cron"`
+	CompactionRatio       pgtype.Float8 `json:"compaction_ratio"`
+	CompactionDecayDays   pgtype.Int4   `json:"compaction_decay_days"`
+	CompactionMaxMemories pgtype.Int4   `json:"compaction_max_memories"`
+	ContainerCpuShares    pgtype.Int4   `json:"container_cpu_shares"`
+	ContainerCpuLimit     pgtype.Float8 `json:"container_cpu_limit"`
+	ContainerMemoryMb     pgtype.Int4   `json:"container_memory_mb"`
+	ContainerPidsLimit    pgtype.Int4   `json:"container_pids_limit"`
+	ContainerDiskQuotaMb  pgtype.Int4   `json:"container_disk_quota_mb"`
+	NetworkMode           pgtype.Text   `json:"network_mode"`
+	NetworkAllowlist      []string      `json:"network_allowlist"`
+	SnapshotCron          pgtype.Text   `json:"snapshot_cron"`
+	SnapshotBeforeRisky   pgtype.Bool   `json:"snapshot_before_risky"`
+	SnapshotKeepLast      pgtype.Int4   `json:"snapshot_keep_last"`
+	SnapshotKeepDaily     pgtype.Int4   `json:"snapshot_keep_daily"`
+	ID                    pgtype.UUID   `json:"id"`
 }
 
 type UpsertBotSettingsRow struct {
-	BotID              pgtype.UUID `json:"bot_id"`
-	MaxContextLoadTime int32       `json:"max_context_load_time"`
-	MaxContextTokens   int32       `json:"max_context_tokens"`
-	MaxInboxItems      int32       `json:"max_inbox_items"`
-	Language           string      `json:"language"`
-	AllowGuest         bool        `json:"allow_guest"`
-	ReasoningEnabled   bool        `json:"reasoning_enabled"`
-	ReasoningEffort    string      `json:"reasoning_effort"`
-	ChatModelID        pgtype.UUID `json:"chat_model_id"`
-	MemoryModelID      pgtype.UUID `json:"memory_model_id"`
-	EmbeddingModelID   pgtype.UUID `json:"embedding_model_id"`
-	SearchProviderID   pgtype.UUID `json:"search_provider_id"`
+	BotID                 pgtype.UUID `json:"bot_id"`
+	MaxContextLoadTime    int32       `json:"max_context_load_time"`
+	MaxContextTokens      int32       `json:"max_context_tokens"`
+	MaxInboxItems         int32       `json:"max_inbox_items"`
+	Language              string      `json:"language"`
+	AllowGuest            bool        `json:"allow_guest"`
+	ReasoningEnabled      bool        `json:"reasoning_enabled"`
+	ReasoningEffort       string      `json:"reasoning_effort"`
+	RerankEnabled         bool        `json:"rerank_enabled"`
+	RerankTopN            int32       `json:"rerank_top_n"`
+	MemoryScope           string      `json:"memory_scope"`
+	CompactionCron        string      `json:"compaction_cron"`
+	CompactionRatio       float64     `json:"compaction_ratio"`
+	CompactionDecayDays   int32       `json:"compaction_decay_days"`
+	CompactionMaxMemories int32       `json:"compaction_max_memories"`
+	ContainerCpuShares    int32       `json:"container_cpu_shares"`
+	ContainerCpuLimit     float64     `json:"container_cpu_limit"`
+	ContainerMemoryMb     int32       `json:"container_memory_mb"`
+	ContainerPidsLimit    int32       `json:"container_pids_limit"`
+	ContainerDiskQuotaMb  int32       `json:"container_disk_quota_mb"`
+	NetworkMode           string      `json:"network_mode"`
+	NetworkAllowlist      []string    `json:"network_allowlist"`
+	SnapshotCron          string      `json:"snapshot_cron"`
+	SnapshotBeforeRisky   bool        `json:"snapshot_before_risky"`
+	SnapshotKeepLast      int32       `json:"snapshot_keep_last"`
+	SnapshotKeepDaily     int32       `json:"snapshot_keep_daily"`
+	ChatModelID           pgtype.UUID `json:"chat_model_id"`
+	MemoryModelID         pgtype.UUID `json:"memory_model_id"`
+	EmbeddingModelID      pgtype.UUID `json:"embedding_model_id"`
+	SearchProviderID      pgtype.UUID `json:"search_provider_id"`
+	RerankModelID         pgtype.UUID `json:"rerank_model_id"`
 }
 
 func (q *Queries) UpsertBotSettings(ctx context.Context, arg UpsertBotSettingsParams) (UpsertBotSettingsRow, error) {
@@ -171,6 +325,25 @@ func (q *Queries) UpsertBotSettings(ctx context.Context, arg UpsertBotSettingsPa
 		arg.MemoryModelID,
 		arg.EmbeddingModelID,
 		arg.SearchProviderID,
+		arg.RerankEnabled,
+		arg.RerankModelID,
+		arg.RerankTopN,
+		arg.MemoryScope,
+		arg.CompactionCron,
+		arg.CompactionRatio,
+		arg.CompactionDecayDays,
+		arg.CompactionMaxMemories,
+		arg.ContainerCpuShares,
+		arg.ContainerCpuLimit,
+		arg.ContainerMemoryMb,
+		arg.ContainerPidsLimit,
+		arg.ContainerDiskQuotaMb,
+		arg.NetworkMode,
+		arg.NetworkAllowlist,
+		arg.SnapshotCron,
+		arg.SnapshotBeforeRisky,
+		arg.SnapshotKeepLast,
+		arg.SnapshotKeepDaily,
 		arg.ID,
 	)
 	var i UpsertBotSettingsRow
@@ -183,10 +356,29 @@ func (q *Queries) UpsertBotSettings(ctx context.Context, arg UpsertBotSettingsPa
 		&i.AllowGuest,
 		&i.ReasoningEnabled,
 		&i.ReasoningEffort,
+		&i.RerankEnabled,
+		&i.RerankTopN,
+		&i.MemoryScope,
+		&i.CompactionCron,
+		&i.CompactionRatio,
+		&i.CompactionDecayDays,
+		&i.CompactionMaxMemories,
+		&i.ContainerCpuShares,
+		&i.ContainerCpuLimit,
+		&i.ContainerMemoryMb,
+		&i.ContainerPidsLimit,
+		&i.ContainerDiskQuotaMb,
+		&i.NetworkMode,
+		&i.NetworkAllowlist,
+		&i.SnapshotCron,
+		&i.SnapshotBeforeRisky,
+		&i.SnapshotKeepLast,
+		&i.SnapshotKeepDaily,
 		&i.ChatModelID,
 		&i.MemoryModelID,
 		&i.EmbeddingModelID,
 		&i.SearchProviderID,
+		&i.RerankModelID,
 	)
 	return i, err
 }
@@ -11,6 +11,49 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const deleteSnapshotsByIDs = `-- name: DeleteSnapshotsByIDs :exec
+DELETE FROM snapshots
+WHERE container_id = $1
+  AND id = ANY($2::uuid[])
+`
+
+type DeleteSnapshotsByIDsParams struct {
+	ContainerID string        `json:"container_id"`
+	Ids         []pgtype.UUID `json:"ids"`
+}
+
+func (q *Queries) DeleteSnapshotsByIDs(ctx context.Context, arg DeleteSnapshotsByIDsParams) error {
+	_, err := q.db.Exec(ctx, deleteSnapshotsByIDs, arg.ContainerID, arg.Ids)
+	return err
+}
+
+const getBotSnapshotPolicy = `-- name: GetBotSnapshotPolicy :one
+SELECT id, snapshot_cron, snapshot_before_risky, snapshot_keep_last, snapshot_keep_daily
+FROM bots
+WHERE id = $1
+`
+
+type GetBotSnapshotPolicyRow struct {
+	ID                  pgtype.UUID `json:"id"`
+	SnapshotCron        string      `json:"snapshot_cron"`
+	SnapshotBeforeRisky bool        `json:"snapshot_before_risky"`
+	SnapshotKeepLast    int32       `json:"snapshot_keep_last"`
+	SnapshotKeepDaily   int32       `json:"snapshot_keep_daily"`
+}
+
+func (q *Queries) GetBotSnapshotPolicy(ctx context.Context, id pgtype.UUID) (GetBotSnapshotPolicyRow, error) {
+	row := q.db.QueryRow(ctx, getBotSnapshotPolicy, id)
+	var i GetBotSnapshotPolicyRow
+	err := row.Scan(
+		&i.ID,
+		&i.SnapshotCron,
+		&i.SnapshotBeforeRisky,
+		&i.SnapshotKeepLast,
+		&i.SnapshotKeepDaily,
+	)
+	return i, err
+}
+
 const getSnapshotByContainerAndRuntimeName = `-- name: GetSnapshotByContainerAndRuntimeName :one
 SELECT
   id,
@@ -46,6 +89,37 @@ func (q *Queries) GetSnapshotByContainerAndRuntimeName(ctx context.Context, arg
 	return i, err
 }
 
+const listBotSnapshotSchedules = `-- name: ListBotSnapshotSchedules :many
+SELECT id, snapshot_cron
+FROM bots
+WHERE snapshot_cron <> ''
+`
+
+type ListBotSnapshotSchedulesRow struct {
+	ID           pgtype.UUID `json:"id"`
+	SnapshotCron string      `json:"snapshot_cron"`
+}
+
+func (q *Queries) ListBotSnapshotSchedules(ctx context.Context) ([]ListBotSnapshotSchedulesRow, error) {
+	rows, err := q.db.Query(ctx, listBotSnapshotSchedules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBotSnapshotSchedulesRow
+	for rows.Next() {
+		var i ListBotSnapshotSchedulesRow
+		if err := rows.Scan(&i.ID, &i.SnapshotCron); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSnapshotsByContainerID = `-- name: ListSnapshotsByContainerID :many
 SELECT
   id,
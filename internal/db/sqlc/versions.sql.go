@@ -11,6 +11,22 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const deleteVersionsBySnapshotIDs = `-- name: DeleteVersionsBySnapshotIDs :exec
+DELETE FROM container_versions
+WHERE container_id = $1
+  AND snapshot_id = ANY($2::uuid[])
+`
+
+type DeleteVersionsBySnapshotIDsParams struct {
+	ContainerID string        `json:"container_id"`
+	SnapshotIds []pgtype.UUID `json:"snapshot_ids"`
+}
+
+func (q *Queries) DeleteVersionsBySnapshotIDs(ctx context.Context, arg DeleteVersionsBySnapshotIDsParams) error {
+	_, err := q.db.Exec(ctx, deleteVersionsBySnapshotIDs, arg.ContainerID, arg.SnapshotIds)
+	return err
+}
+
 const getVersionSnapshotRuntimeName = `-- name: GetVersionSnapshotRuntimeName :one
 SELECT s.runtime_snapshot_name
 FROM container_versions cv
@@ -25,6 +25,10 @@ func (e *ResolverTextEmbedder) Embed(ctx context.Context, input string) ([]float
 	return result.Embedding, nil
 }
 
+func (e *ResolverTextEmbedder) EmbedBatch(ctx context.Context, inputs []string) ([][]float32, error) {
+	return e.Resolver.EmbedBatch(ctx, Request{Model: e.ModelID}, inputs)
+}
+
 func (e *ResolverTextEmbedder) Dimensions() int {
 	return e.Dims
 }
@@ -0,0 +1,155 @@
+package embeddings
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+// Cache stores embeddings keyed by model and content hash.
+type Cache interface {
+	// Get returns the cached vectors of model for the given hashes, keyed by
+	// hash. Missing hashes are absent from the result.
+	Get(ctx context.Context, model string, hashes []string) (map[string][]float32, error)
+	Put(ctx context.Context, model, hash string, vector []float32) error
+}
+
+// ContentHash returns the cache key of text.
+func ContentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// CachedEmbedder wraps an Embedder so identical text is only embedded once
+// per model. Cache failures are logged and fall through to the wrapped
+// embedder.
+type CachedEmbedder struct {
+	inner  Embedder
+	cache  Cache
+	model  string
+	logger *slog.Logger
+}
+
+func NewCachedEmbedder(log *slog.Logger, inner Embedder, cache Cache, model string) *CachedEmbedder {
+	return &CachedEmbedder{
+		inner:  inner,
+		cache:  cache,
+		model:  model,
+		logger: log.With(slog.String("embedder", "cache")),
+	}
+}
+
+// Uncached returns the wrapped embedder, for one-off inputs such as search
+// queries that should not be stored in the cache.
+func (e *CachedEmbedder) Uncached() Embedder {
+	return e.inner
+}
+
+func (e *CachedEmbedder) Dimensions() int {
+	return e.inner.Dimensions()
+}
+
+func (e *CachedEmbedder) Embed(ctx context.Context, input string) ([]float32, error) {
+	vectors, err := e.EmbedBatch(ctx, []string{input})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+func (e *CachedEmbedder) EmbedBatch(ctx context.Context, inputs []string) ([][]float32, error) {
+	if len(inputs) == 0 {
+		return [][]float32{}, nil
+	}
+	hashes := make([]string, len(inputs))
+	for i, input := range inputs {
+		hashes[i] = ContentHash(input)
+	}
+	cached, err := e.cache.Get(ctx, e.model, hashes)
+	if err != nil {
+		e.logger.Warn("embedding cache lookup failed", slog.Any("error", err))
+		cached = map[string][]float32{}
+	}
+
+	// Embed each distinct uncached text once.
+	missing := make([]string, 0, len(inputs))
+	missingHashes := make([]string, 0, len(inputs))
+	seen := make(map[string]struct{}, len(inputs))
+	for i, hash := range hashes {
+		if _, ok := cached[hash]; ok {
+			continue
+		}
+		if _, ok := seen[hash]; ok {
+			continue
+		}
+		seen[hash] = struct{}{}
+		missing = append(missing, inputs[i])
+		missingHashes = append(missingHashes, hash)
+	}
+	if len(missing) > 0 {
+		vectors, err := e.inner.EmbedBatch(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		if len(vectors) != len(missing) {
+			return nil, fmt.Errorf("embedder returned %d vectors for %d inputs", len(vectors), len(missing))
+		}
+		for i, vector := range vectors {
+			cached[missingHashes[i]] = vector
+			if err := e.cache.Put(ctx, e.model, missingHashes[i], vector); err != nil {
+				e.logger.Warn("embedding cache store failed", slog.Any("error", err))
+			}
+		}
+	}
+
+	result := make([][]float32, len(inputs))
+	for i, hash := range hashes {
+		result[i] = cached[hash]
+	}
+	return result, nil
+}
+
+// PgCache stores embeddings in the embedding_cache table.
+type PgCache struct {
+	queries *sqlc.Queries
+}
+
+// NewPgCache creates a Postgres-backed Cache.
+func NewPgCache(queries *sqlc.Queries) *PgCache {
+	return &PgCache{queries: queries}
+}
+
+func (p *PgCache) Get(ctx context.Context, model string, hashes []string) (map[string][]float32, error) {
+	if p.queries == nil {
+		return nil, errors.New("embedding cache queries not configured")
+	}
+	rows, err := p.queries.GetEmbeddingCacheEntries(ctx, sqlc.GetEmbeddingCacheEntriesParams{
+		ModelID:       model,
+		ContentHashes: hashes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	vectors := make(map[string][]float32, len(rows))
+	for _, row := range rows {
+		vectors[row.ContentHash] = row.Embedding
+	}
+	return vectors, nil
+}
+
+func (p *PgCache) Put(ctx context.Context, model, hash string, vector []float32) error {
+	if p.queries == nil {
+		return errors.New("embedding cache queries not configured")
+	}
+	return p.queries.UpsertEmbeddingCacheEntry(ctx, sqlc.UpsertEmbeddingCacheEntryParams{
+		ModelID:     model,
+		ContentHash: hash,
+		Dimensions:  int32(len(vector)), //nolint:gosec // embedding dimensions are small
+		Embedding:   vector,
+	})
+}
@@ -0,0 +1,149 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type countingEmbedder struct {
+	inputs []string
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, input string) ([]float32, error) {
+	vectors, err := e.EmbedBatch(ctx, []string{input})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+func (e *countingEmbedder) EmbedBatch(_ context.Context, inputs []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(inputs))
+	for _, input := range inputs {
+		e.inputs = append(e.inputs, input)
+		vectors = append(vectors, []float32{float32(len(input))})
+	}
+	return vectors, nil
+}
+
+func (e *countingEmbedder) Dimensions() int { return 1 }
+
+type mapCache struct {
+	entries map[string][]float32
+	getErr  error
+}
+
+func (c *mapCache) Get(_ context.Context, model string, hashes []string) (map[string][]float32, error) {
+	if c.getErr != nil {
+		return nil, c.getErr
+	}
+	found := map[string][]float32{}
+	for _, hash := range hashes {
+		if vector, ok := c.entries[model+"/"+hash]; ok {
+			found[hash] = vector
+		}
+	}
+	return found, nil
+}
+
+func (c *mapCache) Put(_ context.Context, model, hash string, vector []float32) error {
+	c.entries[model+"/"+hash] = vector
+	return nil
+}
+
+func TestCachedEmbedder_EmbedBatch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	inner := &countingEmbedder{}
+	cache := &mapCache{entries: map[string][]float32{}}
+	embedder := NewCachedEmbedder(slog.Default(), inner, cache, "model-a")
+
+	vectors, err := embedder.EmbedBatch(ctx, []string{"tea", "coffee", "tea"})
+	if err != nil {
+		t.Fatalf("EmbedBatch failed: %v", err)
+	}
+	if len(vectors) != 3 || vectors[0][0] != 3 || vectors[1][0] != 6 || vectors[2][0] != 3 {
+		t.Fatalf("unexpected vectors: %v", vectors)
+	}
+	if len(inner.inputs) != 2 {
+		t.Fatalf("duplicate text embedded: %v", inner.inputs)
+	}
+
+	if _, err := embedder.Embed(ctx, "coffee"); err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if len(inner.inputs) != 2 {
+		t.Errorf("cached text re-embedded: %v", inner.inputs)
+	}
+
+	other := NewCachedEmbedder(slog.Default(), inner, cache, "model-b")
+	if _, err := other.Embed(ctx, "coffee"); err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if len(inner.inputs) != 3 {
+		t.Errorf("cache shared across models: %v", inner.inputs)
+	}
+}
+
+func TestCachedEmbedder_CacheFailureFallsThrough(t *testing.T) {
+	t.Parallel()
+	inner := &countingEmbedder{}
+	cache := &mapCache{entries: map[string][]float32{}, getErr: errors.New("db down")}
+	embedder := NewCachedEmbedder(slog.Default(), inner, cache, "model-a")
+	vector, err := embedder.Embed(context.Background(), "tea")
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if vector[0] != 3 || len(inner.inputs) != 1 {
+		t.Errorf("expected fallback to inner embedder, got %v (%v)", vector, inner.inputs)
+	}
+}
+
+func TestOpenAIEmbedder_EmbedBatch(t *testing.T) {
+	t.Parallel()
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var req struct {
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		type item struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		}
+		// Answer out of order; the embedder must restore input order.
+		data := make([]item, 0, len(req.Input))
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			data = append(data, item{Index: i, Embedding: []float32{float32(len(req.Input[i]))}})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	defer server.Close()
+
+	embedder, err := NewOpenAIEmbedder(slog.Default(), "key", server.URL, "model-a", 1, time.Second)
+	if err != nil {
+		t.Fatalf("NewOpenAIEmbedder failed: %v", err)
+	}
+	vectors, err := embedder.EmbedBatch(context.Background(), []string{"a", "bb", "ccc"})
+	if err != nil {
+		t.Fatalf("EmbedBatch failed: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+	for i, want := range []float32{1, 2, 3} {
+		if vectors[i][0] != want {
+			t.Errorf("vector %d = %v, want %v", i, vectors[i], want)
+		}
+	}
+}
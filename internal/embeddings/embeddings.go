@@ -8,15 +8,22 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 )
 
 type Embedder interface {
 	Embed(ctx context.Context, input string) ([]float32, error)
+	// EmbedBatch embeds inputs in as few provider calls as possible. The
+	// returned vectors are in the same order as inputs.
+	EmbedBatch(ctx context.Context, inputs []string) ([][]float32, error)
 	Dimensions() int
 }
 
+// openAIMaxBatchSize caps the inputs sent in one embeddings request.
+const openAIMaxBatchSize = 256
+
 type OpenAIEmbedder struct {
 	apiKey  string
 	baseURL string
@@ -27,12 +34,13 @@ type OpenAIEmbedder struct {
 }
 
 type openAIEmbeddingRequest struct {
-	Input string `json:"input"`
+	Input any    `json:"input"`
 	Model string `json:"model"`
 }
 
 type openAIEmbeddingResponse struct {
 	Data []struct {
+		Index     int       `json:"index"`
 		Embedding []float32 `json:"embedding"`
 	} `json:"data"`
 }
@@ -70,17 +78,49 @@ func (e *OpenAIEmbedder) Dimensions() int {
 }
 
 func (e *OpenAIEmbedder) Embed(ctx context.Context, input string) ([]float32, error) {
+	parsed, err := e.request(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed.Data) == 0 {
+		return nil, fmt.Errorf("openai embeddings empty response")
+	}
+	return parsed.Data[0].Embedding, nil
+}
+
+func (e *OpenAIEmbedder) EmbedBatch(ctx context.Context, inputs []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(inputs))
+	for start := 0; start < len(inputs); start += openAIMaxBatchSize {
+		end := min(start+openAIMaxBatchSize, len(inputs))
+		parsed, err := e.request(ctx, inputs[start:end])
+		if err != nil {
+			return nil, err
+		}
+		if len(parsed.Data) != end-start {
+			return nil, fmt.Errorf("openai embeddings returned %d vectors for %d inputs", len(parsed.Data), end-start)
+		}
+		sort.SliceStable(parsed.Data, func(i, j int) bool {
+			return parsed.Data[i].Index < parsed.Data[j].Index
+		})
+		for _, item := range parsed.Data {
+			vectors = append(vectors, item.Embedding)
+		}
+	}
+	return vectors, nil
+}
+
+func (e *OpenAIEmbedder) request(ctx context.Context, input any) (openAIEmbeddingResponse, error) {
 	payload, err := json.Marshal(openAIEmbeddingRequest{
 		Input: input,
 		Model: e.model,
 	})
 	if err != nil {
-		return nil, err
+		return openAIEmbeddingResponse{}, err
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/v1/embeddings", bytes.NewReader(payload))
 	if err != nil {
-		return nil, err
+		return openAIEmbeddingResponse{}, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 	if e.apiKey != "" {
@@ -89,20 +129,17 @@ func (e *OpenAIEmbedder) Embed(ctx context.Context, input string) ([]float32, er
 
 	resp, err := e.http.Do(req)
 	if err != nil {
-		return nil, err
+		return openAIEmbeddingResponse{}, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("openai embeddings error: %s", strings.TrimSpace(string(body)))
+		return openAIEmbeddingResponse{}, fmt.Errorf("openai embeddings error: %s", strings.TrimSpace(string(body)))
 	}
 
 	var parsed openAIEmbeddingResponse
 	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
-		return nil, err
+		return openAIEmbeddingResponse{}, err
 	}
-	if len(parsed.Data) == 0 {
-		return nil, fmt.Errorf("openai embeddings empty response")
-	}
-	return parsed.Data[0].Embedding, nil
+	return parsed, nil
 }
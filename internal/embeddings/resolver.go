@@ -88,36 +88,15 @@ func (r *Resolver) Embed(ctx context.Context, req Request) (Result, error) {
 		return Result{}, errors.New("invalid embeddings type")
 	}
 
-	selected, err := r.selectEmbeddingModel(ctx, req)
-	if err != nil {
-		return Result{}, err
-	}
-	provider, err := r.fetchProvider(ctx, selected.LlmProviderID)
+	req, provider, err := r.resolveModel(ctx, req)
 	if err != nil {
 		return Result{}, err
 	}
 
-	req.Model = selected.ModelID
-	req.Dimensions = selected.Dimensions
-	if selected.ClientType != "" {
-		req.Provider = string(selected.ClientType)
-	}
-	if req.Model == "" {
-		return Result{}, errors.New("embedding model id not configured")
-	}
-	if req.Dimensions <= 0 {
-		return Result{}, errors.New("embedding model dimensions not configured")
-	}
-
-	timeout := r.timeout
-	if timeout <= 0 {
-		timeout = 10 * time.Second
-	}
-
 	// OpenAI-compatible embeddings work for both openai-responses and openai-completions
 	switch req.Type {
 	case TypeText:
-		embedder, err := NewOpenAIEmbedder(r.logger, provider.ApiKey, provider.BaseUrl, req.Model, req.Dimensions, timeout)
+		embedder, err := NewOpenAIEmbedder(r.logger, provider.ApiKey, provider.BaseUrl, req.Model, req.Dimensions, r.requestTimeout())
 		if err != nil {
 			return Result{}, err
 		}
@@ -139,6 +118,66 @@ func (r *Resolver) Embed(ctx context.Context, req Request) (Result, error) {
 	}
 }
 
+// EmbedBatch embeds several texts with the text embedding model selected by
+// req, batching them into as few provider calls as possible. req.Input is
+// ignored; the vectors are returned in the same order as texts.
+func (r *Resolver) EmbedBatch(ctx context.Context, req Request, texts []string) ([][]float32, error) {
+	req.Type = TypeText
+	req.Provider = strings.ToLower(strings.TrimSpace(req.Provider))
+	req.Model = strings.TrimSpace(req.Model)
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+	for _, text := range texts {
+		if strings.TrimSpace(text) == "" {
+			return nil, errors.New("text input is required")
+		}
+	}
+
+	req, provider, err := r.resolveModel(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	embedder, err := NewOpenAIEmbedder(r.logger, provider.ApiKey, provider.BaseUrl, req.Model, req.Dimensions, r.requestTimeout())
+	if err != nil {
+		return nil, err
+	}
+	return embedder.EmbedBatch(ctx, texts)
+}
+
+// resolveModel fills in the model, dimensions and provider type of req from
+// the selected embedding model and returns its LLM provider.
+func (r *Resolver) resolveModel(ctx context.Context, req Request) (Request, sqlc.LlmProvider, error) {
+	selected, err := r.selectEmbeddingModel(ctx, req)
+	if err != nil {
+		return req, sqlc.LlmProvider{}, err
+	}
+	provider, err := r.fetchProvider(ctx, selected.LlmProviderID)
+	if err != nil {
+		return req, sqlc.LlmProvider{}, err
+	}
+
+	req.Model = selected.ModelID
+	req.Dimensions = selected.Dimensions
+	if selected.ClientType != "" {
+		req.Provider = string(selected.ClientType)
+	}
+	if req.Model == "" {
+		return req, sqlc.LlmProvider{}, errors.New("embedding model id not configured")
+	}
+	if req.Dimensions <= 0 {
+		return req, sqlc.LlmProvider{}, errors.New("embedding model dimensions not configured")
+	}
+	return req, provider, nil
+}
+
+func (r *Resolver) requestTimeout() time.Duration {
+	if r.timeout <= 0 {
+		return 10 * time.Second
+	}
+	return r.timeout
+}
+
 func (r *Resolver) selectEmbeddingModel(ctx context.Context, req Request) (models.GetResponse, error) {
 	if r.modelsService == nil {
 		return models.GetResponse{}, errors.New("models service not configured")
@@ -0,0 +1,171 @@
+package filewatch
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/config"
+)
+
+// maxScanFiles bounds the files tracked per bot so a runaway data directory
+// cannot make every poll walk millions of entries.
+const maxScanFiles = 20000
+
+var errTooManyFiles = fmt.Errorf("data directory has more than %d files", maxScanFiles)
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// snapshot maps slash-separated paths relative to the data directory to the
+// state of the regular files found there.
+type snapshot map[string]fileState
+
+// scanDir records the regular files below root. A missing root is empty.
+func scanDir(root string) (snapshot, error) {
+	snap := snapshot{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			// Files may disappear between listing and stat; skip them.
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if len(snap) >= maxScanFiles {
+			return errTooManyFiles
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		snap[filepath.ToSlash(rel)] = fileState{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// diffSnapshots returns the changes from prev to next ordered by path.
+func diffSnapshots(prev, next snapshot) []Event {
+	var events []Event
+	for rel, state := range next {
+		old, ok := prev[rel]
+		switch {
+		case !ok:
+			events = append(events, newEvent(rel, EventCreated, state))
+		case old.size != state.size || !old.modTime.Equal(state.modTime):
+			events = append(events, newEvent(rel, EventModified, state))
+		}
+	}
+	for rel := range prev {
+		if _, ok := next[rel]; !ok {
+			events = append(events, Event{Path: containerPath(rel), Type: EventDeleted})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Path < events[j].Path })
+	return events
+}
+
+func newEvent(rel, eventType string, state fileState) Event {
+	return Event{Path: containerPath(rel), Type: eventType, Size: state.size, ModifiedAt: state.modTime}
+}
+
+func containerPath(rel string) string {
+	return path.Join(config.DefaultDataMount, rel)
+}
+
+// matchEvents returns the events of the watch's types whose path matches its
+// pattern.
+func matchEvents(w Watch, events []Event) []Event {
+	var matched []Event
+	for _, event := range events {
+		if !containsString(w.Events, event.Type) {
+			continue
+		}
+		rel := strings.TrimPrefix(event.Path, config.DefaultDataMount+"/")
+		if matchGlob(w.Pattern, rel) {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+// matchGlob reports whether name matches pattern segment by segment, where a
+// "**" segment matches zero or more directories and the other segments follow
+// path.Match.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// normalizePattern makes pattern relative to the data directory and checks
+// its syntax. A leading data mount such as "/data/" is accepted.
+func normalizePattern(pattern string) (string, error) {
+	pattern = strings.TrimSpace(pattern)
+	pattern = strings.TrimPrefix(pattern, config.DefaultDataMount+"/")
+	pattern = strings.TrimLeft(pattern, "/")
+	if pattern == "" {
+		return "", fmt.Errorf("%w: pattern is required", ErrInvalidWatch)
+	}
+	for _, segment := range strings.Split(pattern, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("%w: pattern %q must be a clean relative path", ErrInvalidWatch, pattern)
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return "", fmt.Errorf("%w: pattern %q: %v", ErrInvalidWatch, pattern, err)
+		}
+	}
+	return pattern, nil
+}
+
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package filewatch
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"uploads/*.csv", "uploads/a.csv", true},
+		{"uploads/*.csv", "uploads/sub/a.csv", false},
+		{"uploads/**/*.csv", "uploads/a.csv", true},
+		{"uploads/**/*.csv", "uploads/x/y/a.csv", true},
+		{"uploads/**", "uploads/x/y/a.txt", true},
+		{"**/report.md", "report.md", true},
+		{"**/report.md", "out/report.md", true},
+		{"*.md", "out/report.md", false},
+		{"out/report.md", "out/report.md", true},
+	}
+	for _, tc := range cases {
+		if got := matchGlob(tc.pattern, tc.name); got != tc.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tc.pattern, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestNormalizeWatch(t *testing.T) {
+	w, err := normalizeWatch(Watch{Pattern: " /data/uploads/*.csv ", Events: []string{"Created", "created"}, Action: " CHAT "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Pattern != "uploads/*.csv" || w.Action != ActionChat || len(w.Events) != 1 || w.Events[0] != EventCreated {
+		t.Fatalf("normalized = %+v", w)
+	}
+	w, err = normalizeWatch(Watch{Pattern: "out/**"})
+	if err != nil || w.Action != ActionInbox || len(w.Events) != 3 {
+		t.Fatalf("defaults = %+v, %v", w, err)
+	}
+	for _, bad := range []Watch{
+		{Pattern: " "},
+		{Pattern: "../etc/*"},
+		{Pattern: "a//b"},
+		{Pattern: "[a"},
+		{Pattern: "a", Action: "email"},
+		{Pattern: "a", Events: []string{"renamed"}},
+	} {
+		if _, err := normalizeWatch(bad); !errors.Is(err, ErrInvalidWatch) {
+			t.Errorf("normalizeWatch(%+v) error = %v, want ErrInvalidWatch", bad, err)
+		}
+	}
+}
+
+func TestScanAndDiff(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		target := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("keep.txt", "same")
+	write("edit.txt", "old")
+	write("gone.txt", "bye")
+	prev, err := scanDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write("edit.txt", "new content")
+	if err := os.Remove(filepath.Join(root, "gone.txt")); err != nil {
+		t.Fatal(err)
+	}
+	write("uploads/new.csv", "a,b")
+	next, err := scanDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := diffSnapshots(prev, next)
+	want := []Event{
+		{Path: "/data/edit.txt", Type: EventModified},
+		{Path: "/data/gone.txt", Type: EventDeleted},
+		{Path: "/data/uploads/new.csv", Type: EventCreated},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("events = %+v", events)
+	}
+	for i := range want {
+		if events[i].Path != want[i].Path || events[i].Type != want[i].Type {
+			t.Fatalf("event %d = %+v, want %+v", i, events[i], want[i])
+		}
+	}
+
+	matched := matchEvents(Watch{Pattern: "uploads/**", Events: []string{EventCreated}}, events)
+	if len(matched) != 1 || matched[0].Size != 3 {
+		t.Fatalf("matched = %+v", matched)
+	}
+
+	missing, err := scanDir(filepath.Join(root, "missing"))
+	if err != nil || len(missing) != 0 {
+		t.Fatalf("missing root = %v, %v", missing, err)
+	}
+}
+
+func TestFormatQuery(t *testing.T) {
+	query := FormatQuery(TriggerPayload{
+		Pattern: "uploads/*",
+		Command: "Summarize new uploads",
+		Events: []Event{
+			{Path: "/data/uploads/a.csv", Type: EventCreated, Size: 12, ModifiedAt: time.Now()},
+			{Path: "/data/uploads/b.csv", Type: EventDeleted},
+		},
+	})
+	want := "Summarize new uploads\n\nFiles matching \"uploads/*\" changed:\n- created /data/uploads/a.csv (12 bytes)\n- deleted /data/uploads/b.csv"
+	if query != want {
+		t.Fatalf("query = %q", query)
+	}
+}
@@ -0,0 +1,424 @@
+// Package filewatch notices changes of files in bot data directories, such as
+// uploads or output of processes in the container, by polling the
+// bind-mounted directory on the host. Bots subscribe with glob watches whose
+// matching changes are delivered as inbox items or trigger a chat turn.
+package filewatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/memohai/memoh/internal/auth"
+	"github.com/memohai/memoh/internal/boot"
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/inbox"
+)
+
+const (
+	triggerTokenTTL = 10 * time.Minute
+	// chatTimeout bounds a chat turn triggered by a watch.
+	chatTimeout = 5 * time.Minute
+)
+
+type Service struct {
+	queries   *sqlc.Queries
+	dataDirs  DataDirResolver
+	inbox     InboxWriter
+	triggerer Triggerer
+	jwtSecret string
+	logger    *slog.Logger
+
+	mu sync.Mutex
+	// snapshots holds the last scan of each bot with enabled watches.
+	snapshots map[string]snapshot
+	// polling marks bots whose previous poll, including chat turns, is still
+	// running.
+	polling map[string]bool
+}
+
+func NewService(log *slog.Logger, queries *sqlc.Queries, dataDirs DataDirResolver, inboxWriter InboxWriter, triggerer Triggerer, runtimeConfig *boot.RuntimeConfig) *Service {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Service{
+		queries:   queries,
+		dataDirs:  dataDirs,
+		inbox:     inboxWriter,
+		triggerer: triggerer,
+		jwtSecret: runtimeConfig.JwtSecret,
+		logger:    log.With(slog.String("service", "filewatch")),
+		snapshots: map[string]snapshot{},
+		polling:   map[string]bool{},
+	}
+}
+
+func (s *Service) Create(ctx context.Context, botID string, req CreateRequest) (Watch, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return Watch{}, err
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	w, err := normalizeWatch(Watch{
+		Pattern: req.Pattern,
+		Events:  req.Events,
+		Action:  req.Action,
+		Command: req.Command,
+		Enabled: enabled,
+	})
+	if err != nil {
+		return Watch{}, err
+	}
+	row, err := s.queries.CreateFileWatch(ctx, sqlc.CreateFileWatchParams{
+		BotID:   pgBotID,
+		Pattern: w.Pattern,
+		Events:  w.Events,
+		Action:  w.Action,
+		Command: w.Command,
+		Enabled: w.Enabled,
+	})
+	if err != nil {
+		return Watch{}, err
+	}
+	return toWatch(row), nil
+}
+
+func (s *Service) Get(ctx context.Context, id string) (Watch, error) {
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return Watch{}, ErrWatchNotFound
+	}
+	row, err := s.queries.GetFileWatchByID(ctx, pgID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Watch{}, ErrWatchNotFound
+		}
+		return Watch{}, err
+	}
+	return toWatch(row), nil
+}
+
+func (s *Service) List(ctx context.Context, botID string) ([]Watch, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.queries.ListFileWatchesByBot(ctx, pgBotID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Watch, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, toWatch(row))
+	}
+	return items, nil
+}
+
+func (s *Service) Update(ctx context.Context, id string, req UpdateRequest) (Watch, error) {
+	w, err := s.Get(ctx, id)
+	if err != nil {
+		return Watch{}, err
+	}
+	if req.Pattern != nil {
+		w.Pattern = *req.Pattern
+	}
+	if req.Events != nil {
+		w.Events = req.Events
+	}
+	if req.Action != nil {
+		w.Action = *req.Action
+	}
+	if req.Command != nil {
+		w.Command = *req.Command
+	}
+	if req.Enabled != nil {
+		w.Enabled = *req.Enabled
+	}
+	if w, err = normalizeWatch(w); err != nil {
+		return Watch{}, err
+	}
+	pgID, err := db.ParseUUID(w.ID)
+	if err != nil {
+		return Watch{}, err
+	}
+	row, err := s.queries.UpdateFileWatch(ctx, sqlc.UpdateFileWatchParams{
+		ID:      pgID,
+		Pattern: w.Pattern,
+		Events:  w.Events,
+		Action:  w.Action,
+		Command: w.Command,
+		Enabled: w.Enabled,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Watch{}, ErrWatchNotFound
+		}
+		return Watch{}, err
+	}
+	return toWatch(row), nil
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return ErrWatchNotFound
+	}
+	return s.queries.DeleteFileWatch(ctx, pgID)
+}
+
+// Start polls the data directories of bots with enabled watches every
+// interval until ctx is done. It does nothing when interval is not positive.
+func (s *Service) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Poll(ctx); err != nil {
+					s.logger.Warn("file watch poll failed", slog.Any("error", err))
+				}
+			}
+		}
+	}()
+}
+
+// Poll scans the data directory of every bot with enabled watches and
+// delivers the changes since the previous scan. The first scan of a bot only
+// records its files. Bots are polled concurrently; a bot whose previous poll
+// is still delivering is skipped.
+func (s *Service) Poll(ctx context.Context) error {
+	rows, err := s.queries.ListEnabledFileWatches(ctx)
+	if err != nil {
+		return err
+	}
+	watches := map[string][]Watch{}
+	for _, row := range rows {
+		w := toWatch(row)
+		watches[w.BotID] = append(watches[w.BotID], w)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Bots without watches are forgotten so a new watch starts from a
+	// fresh scan instead of reporting everything changed in between.
+	for botID := range s.snapshots {
+		if _, ok := watches[botID]; !ok && !s.polling[botID] {
+			delete(s.snapshots, botID)
+		}
+	}
+	for botID, botWatches := range watches {
+		if s.polling[botID] {
+			continue
+		}
+		s.polling[botID] = true
+		go func() {
+			defer func() {
+				s.mu.Lock()
+				delete(s.polling, botID)
+				s.mu.Unlock()
+			}()
+			s.pollBot(ctx, botID, botWatches)
+		}()
+	}
+	return nil
+}
+
+func (s *Service) pollBot(ctx context.Context, botID string, watches []Watch) {
+	next, err := s.scan(botID)
+	if err != nil {
+		s.logger.Warn("file watch scan failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return
+	}
+	s.mu.Lock()
+	prev, seen := s.snapshots[botID]
+	s.snapshots[botID] = next
+	s.mu.Unlock()
+	if !seen {
+		return
+	}
+	events := diffSnapshots(prev, next)
+	if len(events) == 0 {
+		return
+	}
+
+	triggered := false
+	for _, w := range watches {
+		matched := matchEvents(w, events)
+		if len(matched) == 0 {
+			continue
+		}
+		if err := s.deliver(ctx, w, matched); err != nil {
+			s.logger.Warn("file watch delivery failed",
+				slog.String("bot_id", botID),
+				slog.String("watch_id", w.ID),
+				slog.Any("error", err),
+			)
+		}
+		if w.Action == ActionChat {
+			triggered = true
+		}
+	}
+	if !triggered {
+		return
+	}
+	// Files the bot wrote during its chat turns would otherwise trigger the
+	// same watches again; they count as seen. Changes by others during the
+	// turn are not reported either.
+	if after, err := s.scan(botID); err == nil {
+		s.mu.Lock()
+		s.snapshots[botID] = after
+		s.mu.Unlock()
+	}
+}
+
+func (s *Service) scan(botID string) (snapshot, error) {
+	if s.dataDirs == nil {
+		return nil, fmt.Errorf("data directory resolver not configured")
+	}
+	dir, err := s.dataDirs.DataDir(botID)
+	if err != nil {
+		return nil, err
+	}
+	return scanDir(dir)
+}
+
+func (s *Service) deliver(ctx context.Context, w Watch, events []Event) error {
+	switch w.Action {
+	case ActionChat:
+		return s.triggerChat(ctx, w, events)
+	default:
+		if s.inbox == nil {
+			return fmt.Errorf("inbox not configured")
+		}
+		_, err := s.inbox.Create(ctx, inbox.CreateRequest{
+			BotID:  w.BotID,
+			Source: InboxSource,
+			Content: map[string]any{
+				"watch_id": w.ID,
+				"pattern":  w.Pattern,
+				"command":  w.Command,
+				"events":   events,
+			},
+		})
+		return err
+	}
+}
+
+func (s *Service) triggerChat(ctx context.Context, w Watch, events []Event) error {
+	if s.triggerer == nil {
+		return fmt.Errorf("file watch triggerer not configured")
+	}
+	pgBotID, err := db.ParseUUID(w.BotID)
+	if err != nil {
+		return err
+	}
+	bot, err := s.queries.GetBotByID(ctx, pgBotID)
+	if err != nil {
+		return fmt.Errorf("get bot: %w", err)
+	}
+	ownerUserID := bot.OwnerUserID.String()
+	if ownerUserID == "" {
+		return fmt.Errorf("bot owner not found")
+	}
+	if strings.TrimSpace(s.jwtSecret) == "" {
+		return fmt.Errorf("jwt secret not configured")
+	}
+	signed, _, err := auth.GenerateToken(ownerUserID, s.jwtSecret, triggerTokenTTL)
+	if err != nil {
+		return fmt.Errorf("generate trigger token: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, chatTimeout)
+	defer cancel()
+	return s.triggerer.TriggerFileWatch(ctx, w.BotID, TriggerPayload{
+		WatchID:     w.ID,
+		Pattern:     w.Pattern,
+		Command:     w.Command,
+		Events:      events,
+		OwnerUserID: ownerUserID,
+	}, "Bearer "+signed)
+}
+
+// FormatQuery renders the chat query of a triggered watch: the watch command
+// followed by the list of changes.
+func FormatQuery(payload TriggerPayload) string {
+	var b strings.Builder
+	if command := strings.TrimSpace(payload.Command); command != "" {
+		b.WriteString(command)
+		b.WriteString("\n\n")
+	}
+	fmt.Fprintf(&b, "Files matching %q changed:\n", payload.Pattern)
+	for _, event := range payload.Events {
+		fmt.Fprintf(&b, "- %s %s", event.Type, event.Path)
+		if event.Type != EventDeleted {
+			fmt.Fprintf(&b, " (%d bytes)", event.Size)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// normalizeWatch validates w and fills in the default events and action.
+func normalizeWatch(w Watch) (Watch, error) {
+	pattern, err := normalizePattern(w.Pattern)
+	if err != nil {
+		return Watch{}, err
+	}
+	w.Pattern = pattern
+
+	w.Action = strings.ToLower(strings.TrimSpace(w.Action))
+	if w.Action == "" {
+		w.Action = ActionInbox
+	}
+	if w.Action != ActionInbox && w.Action != ActionChat {
+		return Watch{}, fmt.Errorf("%w: action must be %q or %q", ErrInvalidWatch, ActionInbox, ActionChat)
+	}
+	w.Command = strings.TrimSpace(w.Command)
+
+	events := make([]string, 0, 3)
+	for _, event := range w.Events {
+		event = strings.ToLower(strings.TrimSpace(event))
+		switch event {
+		case EventCreated, EventModified, EventDeleted:
+		default:
+			return Watch{}, fmt.Errorf("%w: unknown event %q", ErrInvalidWatch, event)
+		}
+		if !containsString(events, event) {
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		events = []string{EventCreated, EventModified, EventDeleted}
+	}
+	w.Events = events
+	return w, nil
+}
+
+func toWatch(row sqlc.BotFileWatch) Watch {
+	return Watch{
+		ID:        row.ID.String(),
+		BotID:     row.BotID.String(),
+		Pattern:   row.Pattern,
+		Events:    row.Events,
+		Action:    row.Action,
+		Command:   row.Command,
+		Enabled:   row.Enabled,
+		CreatedAt: db.TimeFromPg(row.CreatedAt),
+		UpdatedAt: db.TimeFromPg(row.UpdatedAt),
+	}
+}
@@ -0,0 +1,67 @@
+package filewatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/memohai/memoh/internal/inbox"
+)
+
+type fakeDataDirs struct{ root string }
+
+func (f fakeDataDirs) DataDir(botID string) (string, error) {
+	return filepath.Join(f.root, botID), nil
+}
+
+type fakeInbox struct{ items []inbox.CreateRequest }
+
+func (f *fakeInbox) Create(ctx context.Context, req inbox.CreateRequest) (inbox.Item, error) {
+	f.items = append(f.items, req)
+	return inbox.Item{BotID: req.BotID, Source: req.Source, Content: req.Content}, nil
+}
+
+func TestPollBotDeliversToInbox(t *testing.T) {
+	root := t.TempDir()
+	box := &fakeInbox{}
+	s := &Service{
+		dataDirs:  fakeDataDirs{root: root},
+		inbox:     box,
+		snapshots: map[string]snapshot{},
+		polling:   map[string]bool{},
+	}
+	watches := []Watch{
+		{ID: "w1", BotID: "bot", Pattern: "uploads/*.csv", Events: []string{EventCreated}, Action: ActionInbox},
+		{ID: "w2", BotID: "bot", Pattern: "**/*.md", Events: []string{EventCreated}, Action: ActionInbox},
+	}
+
+	// The first poll only records the files present.
+	s.pollBot(context.Background(), "bot", watches)
+	dir := filepath.Join(root, "bot", "uploads")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "report.csv"), []byte("a,b"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s.pollBot(context.Background(), "bot", watches)
+
+	if len(box.items) != 1 {
+		t.Fatalf("inbox items = %+v", box.items)
+	}
+	item := box.items[0]
+	if item.BotID != "bot" || item.Source != InboxSource || item.Content["watch_id"] != "w1" {
+		t.Fatalf("inbox item = %+v", item)
+	}
+	events, _ := item.Content["events"].([]Event)
+	if len(events) != 1 || events[0].Path != "/data/uploads/report.csv" {
+		t.Fatalf("events = %+v", item.Content["events"])
+	}
+
+	// Nothing changed since the last poll.
+	s.pollBot(context.Background(), "bot", watches)
+	if len(box.items) != 1 {
+		t.Fatalf("unexpected delivery: %+v", box.items)
+	}
+}
@@ -0,0 +1,103 @@
+package filewatch
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/memohai/memoh/internal/inbox"
+)
+
+// Event types a watch can subscribe to.
+const (
+	EventCreated  = "created"
+	EventModified = "modified"
+	EventDeleted  = "deleted"
+)
+
+// Actions taken when a watch matches.
+const (
+	// ActionInbox delivers matching events as an inbox item the bot reads on
+	// its next turn.
+	ActionInbox = "inbox"
+	// ActionChat triggers a chat turn with the watch command and the events.
+	ActionChat = "chat"
+)
+
+// InboxSource is the inbox source of items created by file watches.
+const InboxSource = "file_watch"
+
+var (
+	// ErrWatchNotFound indicates the requested file watch does not exist.
+	ErrWatchNotFound = errors.New("file watch not found")
+	// ErrInvalidWatch indicates the file watch request is malformed.
+	ErrInvalidWatch = errors.New("invalid file watch")
+)
+
+// Watch subscribes a bot to changes of files in its data directory.
+type Watch struct {
+	ID    string `json:"id"`
+	BotID string `json:"bot_id"`
+	// Pattern is a glob relative to the data directory; "**" matches any
+	// number of directories, e.g. "uploads/**/*.csv".
+	Pattern   string    `json:"pattern"`
+	Events    []string  `json:"events"`
+	Action    string    `json:"action"`
+	Command   string    `json:"command"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateRequest struct {
+	Pattern string   `json:"pattern"`
+	Events  []string `json:"events,omitempty"`
+	Action  string   `json:"action,omitempty"`
+	Command string   `json:"command,omitempty"`
+	Enabled *bool    `json:"enabled,omitempty"`
+}
+
+type UpdateRequest struct {
+	Pattern *string  `json:"pattern,omitempty"`
+	Events  []string `json:"events,omitempty"`
+	Action  *string  `json:"action,omitempty"`
+	Command *string  `json:"command,omitempty"`
+	Enabled *bool    `json:"enabled,omitempty"`
+}
+
+type ListResponse struct {
+	Items []Watch `json:"items"`
+}
+
+// Event is a change of a file in a bot's data directory.
+type Event struct {
+	// Path is the file path inside the container.
+	Path       string    `json:"path"`
+	Type       string    `json:"type"`
+	Size       int64     `json:"size,omitempty"`
+	ModifiedAt time.Time `json:"modified_at,omitempty"`
+}
+
+// TriggerPayload describes a chat turn triggered by a file watch.
+type TriggerPayload struct {
+	WatchID     string
+	Pattern     string
+	Command     string
+	Events      []Event
+	OwnerUserID string
+}
+
+// Triggerer runs the chat turn of a file watch with the chat action.
+type Triggerer interface {
+	TriggerFileWatch(ctx context.Context, botID string, payload TriggerPayload, token string) error
+}
+
+// DataDirResolver locates a bot's data directory on the host.
+type DataDirResolver interface {
+	DataDir(botID string) (string, error)
+}
+
+// InboxWriter stores inbox items for file watches with the inbox action.
+type InboxWriter interface {
+	Create(ctx context.Context, req inbox.CreateRequest) (inbox.Item, error)
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/bundle"
+	"github.com/memohai/memoh/internal/memory"
+)
+
+// maxBotBundleBytes caps uploaded bot bundles.
+const maxBotBundleBytes = 4 << 30
+
+// BundleHandler exports bots as portable bundles and imports them as new bots.
+type BundleHandler struct {
+	service        *bundle.Service
+	botService     *bots.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+func NewBundleHandler(log *slog.Logger, service *bundle.Service, botService *bots.Service, accountService *accounts.Service) *BundleHandler {
+	return &BundleHandler{
+		service:        service,
+		botService:     botService,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "bundle")),
+	}
+}
+
+func (h *BundleHandler) Register(e *echo.Echo) {
+	e.POST("/bots/import", h.ImportBot)
+	e.POST("/bots/:id/export", h.ExportBot)
+	e.POST("/bots/:id/clone", h.CloneBot)
+}
+
+type CloneBotRequest struct {
+	// DisplayName defaults to the source bot's name with a " (copy)" suffix.
+	DisplayName string `json:"display_name,omitempty"`
+}
+
+// ExportBot godoc
+// @Summary Export bot bundle
+// @Description Export a bot as a gzipped tar with manifest.json, bot.json (bot, settings, channel configs, MCP connections, schedules and subagents), memories.tar.gz and the data/ tree. Models are referenced by name.
+// @Tags bots
+// @Produce application/gzip
+// @Param id path string true "Bot ID"
+// @Param redact_secrets query bool false "Leave out channel credentials and MCP env/header values"
+// @Param include_vectors query bool false "Include memory vectors and the model that produced them"
+// @Success 200 {file} binary
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{id}/export [post]
+func (h *BundleHandler) ExportBot(c echo.Context) error {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	botID := strings.TrimSpace(c.Param("id"))
+	if botID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := AuthorizeBotAccess(c.Request().Context(), h.botService, h.accountService, channelIdentityID, botID, bots.AccessPolicy{AllowPublicMember: false}); err != nil {
+		return err
+	}
+
+	// The bundle is streamed; errors before the first byte still get a
+	// proper status, later ones can only abort the download.
+	filename := fmt.Sprintf("bot-%s-%s.tar.gz", botID, time.Now().UTC().Format("20060102T150405Z"))
+	c.Response().Header().Set(echo.HeaderContentType, "application/gzip")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	if _, err := h.service.Export(c.Request().Context(), c.Response(), botID, bundle.ExportOptions{
+		RedactSecrets:  strings.EqualFold(c.QueryParam("redact_secrets"), "true"),
+		IncludeVectors: strings.EqualFold(c.QueryParam("include_vectors"), "true"),
+	}); err != nil {
+		if c.Response().Committed {
+			h.logger.Error("bot export aborted", slog.String("bot_id", botID), slog.Any("error", err))
+			return nil
+		}
+		c.Response().Header().Del(echo.HeaderContentDisposition)
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return nil
+}
+
+// ImportBot godoc
+// @Summary Import bot bundle
+// @Description Create a new bot owned by the current user from a bundle produced by the export endpoint. Models and the search provider are re-bound by name; anything that cannot be applied is listed in warnings.
+// @Tags bots
+// @Accept application/gzip
+// @Accept multipart/form-data
+// @Produce json
+// @Param display_name query string false "Display name of the new bot (defaults to the exported one)"
+// @Param embedding_enabled query bool false "Re-embed memories whose vectors are missing or from another model"
+// @Param file formData file false "Bot bundle (multipart upload); otherwise send the bundle as the request body"
+// @Success 201 {object} bundle.ImportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/import [post]
+func (h *BundleHandler) ImportBot(c echo.Context) error {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, maxBotBundleBytes)
+	var archive io.Reader = body
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		c.Request().Body = body
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "file is required")
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		defer func() { _ = file.Close() }()
+		archive = file
+	}
+
+	result, err := h.service.Import(c.Request().Context(), archive, bundle.ImportOptions{
+		OwnerUserID:      channelIdentityID,
+		DisplayName:      c.QueryParam("display_name"),
+		EmbeddingEnabled: strings.EqualFold(c.QueryParam("embedding_enabled"), "true"),
+	})
+	if err != nil {
+		if errors.Is(err, bundle.ErrInvalidBundle) || errors.Is(err, memory.ErrInvalidArchive) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	h.logger.Info("bot imported", slog.String("bot_id", result.BotID), slog.Int("warnings", len(result.Warnings)))
+	return c.JSON(http.StatusCreated, result)
+}
+
+// CloneBot godoc
+// @Summary Clone bot
+// @Description Create a new bot owned by the current user with the settings, data files, MCP connections, schedules and subagents of an existing bot. Conversation history, memories and channel configs are not copied.
+// @Tags bots
+// @Param id path string true "Bot ID"
+// @Param payload body CloneBotRequest false "Clone payload"
+// @Success 201 {object} bots.Bot
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{id}/clone [post]
+func (h *BundleHandler) CloneBot(c echo.Context) error {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	botID := strings.TrimSpace(c.Param("id"))
+	if botID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := AuthorizeBotAccess(c.Request().Context(), h.botService, h.accountService, channelIdentityID, botID, bots.AccessPolicy{AllowPublicMember: false}); err != nil {
+		return err
+	}
+	var req CloneBotRequest
+	if c.Request().ContentLength != 0 {
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+	clone, err := h.service.Clone(c.Request().Context(), botID, bundle.CloneOptions{
+		OwnerUserID: channelIdentityID,
+		DisplayName: req.DisplayName,
+	})
+	if err != nil {
+		if errors.Is(err, bundle.ErrInvalidBundle) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusCreated, clone)
+}
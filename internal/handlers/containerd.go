@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -70,6 +71,16 @@ type GetContainerResponse struct {
 	UpdatedAt     time.Time `json:"updated_at"`
 }
 
+type SetContainerImageRequest struct {
+	// Image is the image reference; empty restores the default MCP image.
+	Image string `json:"image"`
+}
+
+type SetContainerImageResponse struct {
+	ContainerID string `json:"container_id"`
+	Image       string `json:"image"`
+}
+
 type CreateSnapshotRequest struct {
 	SnapshotName string `json:"snapshot_name"`
 }
@@ -100,6 +111,26 @@ type ListSnapshotsResponse struct {
 	Snapshots   []SnapshotInfo `json:"snapshots"`
 }
 
+type PruneSnapshotsResponse struct {
+	ContainerID string   `json:"container_id"`
+	Versions    []int    `json:"versions"`
+	Removed     []string `json:"removed"`
+	Retained    []string `json:"retained"`
+}
+
+type VersionFileChange struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+}
+
+type VersionDiffResponse struct {
+	ContainerID string              `json:"container_id"`
+	From        int                 `json:"from"`
+	To          int                 `json:"to"`
+	Changes     []VersionFileChange `json:"changes"`
+	Truncated   bool                `json:"truncated"`
+}
+
 func NewContainerdHandler(log *slog.Logger, service ctr.Service, manager *mcp.Manager, cfg config.MCPConfig, namespace string, containerBackend string, botService *bots.Service, accountService *accounts.Service, policyService *policy.Service, queries *dbsqlc.Queries) *ContainerdHandler {
 	return &ContainerdHandler{
 		service:          service,
@@ -124,8 +155,11 @@ func (h *ContainerdHandler) Register(e *echo.Echo) {
 	group.DELETE("", h.DeleteContainer)
 	group.POST("/start", h.StartContainer)
 	group.POST("/stop", h.StopContainer)
+	group.PUT("/image", h.SetContainerImage)
 	group.POST("/snapshots", h.CreateSnapshot)
 	group.GET("/snapshots", h.ListSnapshots)
+	group.POST("/snapshots/prune", h.PruneSnapshots)
+	group.GET("/versions/diff", h.DiffVersions)
 	group.GET("/skills", h.ListSkills)
 	group.POST("/skills", h.UpsertSkills)
 	group.DELETE("/skills", h.DeleteSkills)
@@ -139,6 +173,7 @@ func (h *ContainerdHandler) Register(e *echo.Echo) {
 	group.POST("/fs/mkdir", h.FSMkdir)
 	group.POST("/fs/delete", h.FSDelete)
 	group.POST("/fs/rename", h.FSRename)
+	group.GET("/terminal", h.ContainerTerminal)
 	root := e.Group("/bots/:bot_id")
 	root.POST("/mcp-stdio", h.CreateMCPStdio)
 	root.POST("/mcp-stdio/:connection_id", h.HandleMCPStdio)
@@ -166,13 +201,13 @@ func (h *ContainerdHandler) CreateContainer(c echo.Context) error {
 	}
 	containerID := mcp.ContainerPrefix + botID
 
-	image := h.mcpImageRef()
+	ctx := c.Request().Context()
+	image := h.botImageRef(ctx, botID)
 	snapshotter := strings.TrimSpace(req.Snapshotter)
 	if snapshotter == "" {
 		snapshotter = h.cfg.Snapshotter
 	}
 
-	ctx := c.Request().Context()
 	dataRoot := strings.TrimSpace(h.cfg.DataRoot)
 	if dataRoot == "" {
 		dataRoot = config.DefaultDataRoot
@@ -233,6 +268,7 @@ func (h *ContainerdHandler) CreateContainer(c echo.Context) error {
 		}
 	}
 
+	h.applyResourceLimits(ctx, botID)
 	started := false
 	if err := h.service.StartContainer(ctx, containerID, &ctr.StartTaskOptions{
 		UseStdio: false,
@@ -242,6 +278,7 @@ func (h *ContainerdHandler) CreateContainer(c echo.Context) error {
 			ContainerID: containerID,
 			CNIBinDir:   h.cfg.CNIBinaryDir,
 			CNIConfDir:  h.cfg.CNIConfigDir,
+			Policy:      h.networkPolicy(ctx, botID),
 		}); netErr != nil {
 			h.logger.Warn("mcp container network setup failed, task kept running",
 				slog.String("container_id", containerID),
@@ -275,6 +312,12 @@ func (h *ContainerdHandler) CreateContainer(c echo.Context) error {
 // running. If the container is missing (e.g. after a VM restart) it is recreated via
 // SetupBotContainer. This prevents permanent desync between DB and containerd state.
 func (h *ContainerdHandler) ensureContainerAndTask(ctx context.Context, containerID, botID string) error {
+	if h.manager != nil {
+		// A paused task would otherwise be replaced by a fresh one.
+		if err := h.manager.Wake(ctx, botID); err != nil {
+			return err
+		}
+	}
 	_, err := h.service.GetContainer(ctx, containerID)
 	if err != nil {
 		if !errdefs.IsNotFound(err) {
@@ -299,6 +342,7 @@ func (h *ContainerdHandler) ensureContainerAndTask(ctx context.Context, containe
 				ContainerID: containerID,
 				CNIBinDir:   h.cfg.CNIBinaryDir,
 				CNIConfDir:  h.cfg.CNIConfigDir,
+				Policy:      h.networkPolicy(ctx, botID),
 			}); netErr != nil {
 				h.logger.Warn("network re-setup failed for running task",
 					slog.String("container_id", containerID), slog.Any("error", netErr))
@@ -310,6 +354,7 @@ func (h *ContainerdHandler) ensureContainerAndTask(ctx context.Context, containe
 		}
 	}
 
+	h.applyResourceLimits(ctx, botID)
 	if err := h.service.StartContainer(ctx, containerID, &ctr.StartTaskOptions{
 		UseStdio: false,
 	}); err != nil {
@@ -319,6 +364,7 @@ func (h *ContainerdHandler) ensureContainerAndTask(ctx context.Context, containe
 		ContainerID: containerID,
 		CNIBinDir:   h.cfg.CNIBinaryDir,
 		CNIConfDir:  h.cfg.CNIConfigDir,
+		Policy:      h.networkPolicy(ctx, botID),
 	}); netErr != nil {
 		h.logger.Warn("network setup failed, task kept running",
 			slog.String("container_id", containerID), slog.Any("error", netErr))
@@ -326,6 +372,34 @@ func (h *ContainerdHandler) ensureContainerAndTask(ctx context.Context, containe
 	return nil
 }
 
+// applyResourceLimits writes the bot's configured cgroup limits into the
+// container spec before its task starts. Failures are logged so a bad limit
+// never blocks the container from starting.
+func (h *ContainerdHandler) applyResourceLimits(ctx context.Context, botID string) {
+	if h.manager == nil {
+		return
+	}
+	if err := h.manager.ApplyResourceLimits(ctx, botID); err != nil {
+		h.logger.Warn("apply container resource limits failed",
+			slog.String("bot_id", botID), slog.Any("error", err))
+	}
+}
+
+// networkPolicy returns the bot's container network policy. When it cannot be
+// read the container is kept offline rather than given full access.
+func (h *ContainerdHandler) networkPolicy(ctx context.Context, botID string) ctr.NetworkPolicy {
+	if h.manager == nil {
+		return ctr.NetworkPolicy{}
+	}
+	policy, err := h.manager.NetworkPolicy(ctx, botID)
+	if err != nil {
+		h.logger.Warn("load container network policy failed, keeping container offline",
+			slog.String("bot_id", botID), slog.Any("error", err))
+		return ctr.NetworkPolicy{Mode: ctr.NetworkModeNone}
+	}
+	return policy
+}
+
 // botContainerID resolves container_id for a bot from the database.
 func (h *ContainerdHandler) botContainerID(ctx context.Context, botID string) (string, error) {
 	if h.queries != nil {
@@ -518,6 +592,99 @@ func (h *ContainerdHandler) StopContainer(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]bool{"stopped": true})
 }
 
+// SetContainerImage godoc
+// @Summary Switch the bot container image
+// @Description Recreate the bot container from another image. The data directory is kept and mounted into the new container. Images that are not present locally can only be pulled by admins.
+// @Tags containerd
+// @Param bot_id path string true "Bot ID"
+// @Param payload body SetContainerImageRequest true "Container image payload"
+// @Success 200 {object} SetContainerImageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Router /bots/{bot_id}/container/image [put]
+func (h *ContainerdHandler) SetContainerImage(c echo.Context) error {
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	if h.manager == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "container manager not configured")
+	}
+	var req SetContainerImageRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	ctx := c.Request().Context()
+	image := h.manager.DefaultImageRef()
+	if strings.TrimSpace(req.Image) != "" {
+		image, err = mcp.NormalizeImageRef(req.Image)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	if _, err := h.service.GetImage(ctx, image); err != nil {
+		if !errdefs.IsNotFound(err) {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		channelIdentityID, err := h.requireChannelIdentityID(c)
+		if err != nil {
+			return err
+		}
+		isAdmin, err := h.accountService.IsAdmin(ctx, channelIdentityID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		if !isAdmin {
+			return echo.NewHTTPError(http.StatusForbidden, "image is not available; an admin must pull it first")
+		}
+		if _, err := h.manager.PullImage(ctx, image, nil); err != nil {
+			return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+		}
+	}
+
+	// The data directory lives on the host, so recreating the container keeps
+	// /data intact.
+	previous := h.manager.ImageRef(ctx, botID)
+	if err := h.manager.SnapshotBeforeOperation(ctx, botID, "image"); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if err := h.CleanupBotContainer(ctx, botID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if err := h.manager.SetImageRef(ctx, botID, image); err != nil {
+		if restoreErr := h.restoreContainerImage(ctx, botID, previous); restoreErr != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("%v; restore previous image: %v", err, restoreErr))
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if err := h.SetupBotContainer(ctx, botID); err != nil {
+		if restoreErr := h.restoreContainerImage(ctx, botID, previous); restoreErr != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("setup container with %s: %v; restore previous image: %v", image, err, restoreErr))
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("setup container with %s: %v; previous image %s restored", image, err, previous))
+	}
+	return c.JSON(http.StatusOK, SetContainerImageResponse{
+		ContainerID: mcp.ContainerPrefix + botID,
+		Image:       image,
+	})
+}
+
+// restoreContainerImage puts the bot back on its previous image after a
+// failed image change, so it is not left without a container.
+func (h *ContainerdHandler) restoreContainerImage(ctx context.Context, botID, previous string) error {
+	ctx = context.WithoutCancel(ctx)
+	if err := h.CleanupBotContainer(ctx, botID); err != nil {
+		h.logger.Warn("restore image: cleanup failed", slog.String("bot_id", botID), slog.Any("error", err))
+	}
+	if err := h.manager.SetImageRef(ctx, botID, previous); err != nil {
+		return err
+	}
+	return h.SetupBotContainer(ctx, botID)
+}
+
 // CreateSnapshot godoc
 // @Summary Create container snapshot for bot
 // @Tags containerd
@@ -746,6 +913,97 @@ func snapshotLineage(root string, all []ctr.SnapshotInfo) ([]ctr.SnapshotInfo, b
 	return lineage, true
 }
 
+// PruneSnapshots godoc
+// @Summary Apply the snapshot retention policy of a bot
+// @Description Deletes versions outside the keep-last/keep-daily policy and removes unreferenced container snapshots.
+// @Tags containerd
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} PruneSnapshotsResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 501 {object} ErrorResponse "Snapshots currently not supported on this backend"
+// @Router /bots/{bot_id}/container/snapshots/prune [post]
+func (h *ContainerdHandler) PruneSnapshots(c echo.Context) error {
+	if h.containerBackend == "apple" {
+		return echo.NewHTTPError(http.StatusNotImplemented, "snapshots currently not supported on Apple Container backend")
+	}
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	if h.manager == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "snapshot manager not configured")
+	}
+	result, err := h.manager.PruneSnapshots(c.Request().Context(), botID)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return echo.NewHTTPError(http.StatusNotFound, "container not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, PruneSnapshotsResponse{
+		ContainerID: mcp.ContainerPrefix + botID,
+		Versions:    result.Versions,
+		Removed:     result.Removed,
+		Retained:    result.Retained,
+	})
+}
+
+// DiffVersions godoc
+// @Summary List files changed between two container versions
+// @Description Compares the root filesystems of two versions. The bot data directory is not versioned and never shows up.
+// @Tags containerd
+// @Param bot_id path string true "Bot ID"
+// @Param from query int true "Base version"
+// @Param to query int true "Target version"
+// @Success 200 {object} VersionDiffResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 501 {object} ErrorResponse "Snapshots currently not supported on this backend"
+// @Router /bots/{bot_id}/container/versions/diff [get]
+func (h *ContainerdHandler) DiffVersions(c echo.Context) error {
+	if h.containerBackend == "apple" {
+		return echo.NewHTTPError(http.StatusNotImplemented, "snapshots currently not supported on Apple Container backend")
+	}
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	if h.manager == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "snapshot manager not configured")
+	}
+	from, err := strconv.Atoi(strings.TrimSpace(c.QueryParam("from")))
+	if err != nil || from <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "from must be a positive version number")
+	}
+	to, err := strconv.Atoi(strings.TrimSpace(c.QueryParam("to")))
+	if err != nil || to <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "to must be a positive version number")
+	}
+	diff, err := h.manager.DiffVersions(c.Request().Context(), botID, from, to)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "version not found")
+		}
+		if errdefs.IsNotFound(err) {
+			return echo.NewHTTPError(http.StatusNotFound, "container not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	changes := make([]VersionFileChange, 0, len(diff.Changes))
+	for _, change := range diff.Changes {
+		changes = append(changes, VersionFileChange{Path: change.Path, Kind: change.Kind})
+	}
+	return c.JSON(http.StatusOK, VersionDiffResponse{
+		ContainerID: mcp.ContainerPrefix + botID,
+		From:        diff.From,
+		To:          diff.To,
+		Changes:     changes,
+		Truncated:   diff.Truncated,
+	})
+}
+
 // ---------- auth helpers ----------
 
 func (h *ContainerdHandler) mcpImageRef() string {
@@ -755,6 +1013,14 @@ func (h *ContainerdHandler) mcpImageRef() string {
 	return config.DefaultMCPImage
 }
 
+// botImageRef returns the image the bot's container should run.
+func (h *ContainerdHandler) botImageRef(ctx context.Context, botID string) string {
+	if h.manager == nil {
+		return h.mcpImageRef()
+	}
+	return h.manager.ImageRef(ctx, botID)
+}
+
 // requireBotAccess extracts bot_id from path, validates user auth, and authorizes bot access.
 func (h *ContainerdHandler) requireBotAccess(c echo.Context) (string, error) {
 	channelIdentityID, err := h.requireChannelIdentityID(c)
@@ -801,7 +1067,7 @@ func (h *ContainerdHandler) requireBotAccessWithGuest(c echo.Context) (string, e
 func (h *ContainerdHandler) SetupBotContainer(ctx context.Context, botID string) error {
 	containerID := mcp.ContainerPrefix + botID
 
-	image := h.mcpImageRef()
+	image := h.botImageRef(ctx, botID)
 	snapshotter := strings.TrimSpace(h.cfg.Snapshotter)
 
 	dataRoot := strings.TrimSpace(h.cfg.DataRoot)
@@ -865,6 +1131,7 @@ func (h *ContainerdHandler) SetupBotContainer(ctx context.Context, botID string)
 		}
 	}
 
+	h.applyResourceLimits(ctx, botID)
 	if err := h.service.StartContainer(ctx, containerID, &ctr.StartTaskOptions{
 		UseStdio: false,
 	}); err == nil {
@@ -872,6 +1139,7 @@ func (h *ContainerdHandler) SetupBotContainer(ctx context.Context, botID string)
 			ContainerID: containerID,
 			CNIBinDir:   h.cfg.CNIBinaryDir,
 			CNIConfDir:  h.cfg.CNIConfigDir,
+			Policy:      h.networkPolicy(ctx, botID),
 		}); netErr != nil {
 			h.logger.Warn("setup bot container: network setup failed, task kept running",
 				slog.String("bot_id", botID),
@@ -1033,6 +1301,7 @@ func (h *ContainerdHandler) ReconcileContainers(ctx context.Context) {
 				ContainerID: containerID,
 				CNIBinDir:   h.cfg.CNIBinaryDir,
 				CNIConfDir:  h.cfg.CNIConfigDir,
+				Policy:      h.networkPolicy(ctx, botID),
 			}); netErr != nil {
 				h.logger.Warn("reconcile: network re-setup failed for running task",
 					slog.String("bot_id", botID),
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
@@ -438,6 +439,7 @@ func (h *ContainerdHandler) FSDownload(c echo.Context) error {
 // @Failure 400 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 507 {object} ErrorResponse "Data directory quota exceeded"
 // @Router /bots/{bot_id}/container/fs/write [post]
 func (h *ContainerdHandler) FSWrite(c echo.Context) error {
 	botID, err := h.requireBotAccess(c)
@@ -460,6 +462,10 @@ func (h *ContainerdHandler) FSWrite(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusForbidden, "write operations are only allowed within the data directory")
 	}
 
+	if err := h.checkDataQuota(c.Request().Context(), botID, pc.hostPath, int64(len(req.Content))); err != nil {
+		return err
+	}
+
 	dir := filepath.Dir(pc.hostPath)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
@@ -482,6 +488,7 @@ func (h *ContainerdHandler) FSWrite(c echo.Context) error {
 // @Failure 400 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 507 {object} ErrorResponse "Data directory quota exceeded"
 // @Router /bots/{bot_id}/container/fs/upload [post]
 func (h *ContainerdHandler) FSUpload(c echo.Context) error {
 	botID, err := h.requireBotAccess(c)
@@ -506,6 +513,10 @@ func (h *ContainerdHandler) FSUpload(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusForbidden, "upload operations are only allowed within the data directory")
 	}
 
+	if err := h.checkDataQuota(c.Request().Context(), botID, pc.hostPath, file.Size); err != nil {
+		return err
+	}
+
 	src, err := file.Open()
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
@@ -681,6 +692,26 @@ func (h *ContainerdHandler) FSRename(c echo.Context) error {
 
 // ---------- helpers ----------
 
+// checkDataQuota rejects a write of size bytes to hostPath when it would
+// grow the bot data directory past its quota. The current size of the file
+// being replaced is credited back.
+func (h *ContainerdHandler) checkDataQuota(ctx context.Context, botID, hostPath string, size int64) error {
+	if h.manager == nil {
+		return nil
+	}
+	delta := size
+	if info, err := os.Stat(hostPath); err == nil && info.Mode().IsRegular() {
+		delta -= info.Size()
+	}
+	if err := h.manager.CheckDataQuota(ctx, botID, delta); err != nil {
+		if errors.Is(err, mcp.ErrDataQuotaExceeded) {
+			return echo.NewHTTPError(http.StatusInsufficientStorage, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return nil
+}
+
 func osFileInfoToFS(containerPath string, info os.FileInfo) FSFileInfo {
 	return FSFileInfo{
 		Name:    info.Name(),
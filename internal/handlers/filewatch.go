@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/filewatch"
+)
+
+// FileWatchHandler manages the file watches of a bot's data directory.
+type FileWatchHandler struct {
+	service        *filewatch.Service
+	botService     *bots.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+func NewFileWatchHandler(log *slog.Logger, service *filewatch.Service, botService *bots.Service, accountService *accounts.Service) *FileWatchHandler {
+	return &FileWatchHandler{
+		service:        service,
+		botService:     botService,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "filewatch")),
+	}
+}
+
+func (h *FileWatchHandler) Register(e *echo.Echo) {
+	group := e.Group("/bots/:bot_id/file-watches")
+	group.POST("", h.Create)
+	group.GET("", h.List)
+	group.GET("/:id", h.Get)
+	group.PUT("/:id", h.Update)
+	group.DELETE("/:id", h.Delete)
+}
+
+// Create godoc
+// @Summary Create file watch
+// @Description Watch files in the bot data directory matching a glob; changes are delivered to the bot inbox or trigger a chat turn
+// @Tags file-watches
+// @Param bot_id path string true "Bot ID"
+// @Param payload body filewatch.CreateRequest true "File watch payload"
+// @Success 201 {object} filewatch.Watch
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/file-watches [post]
+func (h *FileWatchHandler) Create(c echo.Context) error {
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	var req filewatch.CreateRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	item, err := h.service.Create(c.Request().Context(), botID, req)
+	if err != nil {
+		return fileWatchError(err)
+	}
+	return c.JSON(http.StatusCreated, item)
+}
+
+// List godoc
+// @Summary List file watches
+// @Tags file-watches
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} filewatch.ListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/file-watches [get]
+func (h *FileWatchHandler) List(c echo.Context) error {
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.service.List(c.Request().Context(), botID)
+	if err != nil {
+		return fileWatchError(err)
+	}
+	return c.JSON(http.StatusOK, filewatch.ListResponse{Items: items})
+}
+
+// Get godoc
+// @Summary Get file watch
+// @Tags file-watches
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "File watch ID"
+// @Success 200 {object} filewatch.Watch
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/file-watches/{id} [get]
+func (h *FileWatchHandler) Get(c echo.Context) error {
+	item, err := h.requireWatch(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// Update godoc
+// @Summary Update file watch
+// @Tags file-watches
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "File watch ID"
+// @Param payload body filewatch.UpdateRequest true "File watch payload"
+// @Success 200 {object} filewatch.Watch
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/file-watches/{id} [put]
+func (h *FileWatchHandler) Update(c echo.Context) error {
+	item, err := h.requireWatch(c)
+	if err != nil {
+		return err
+	}
+	var req filewatch.UpdateRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	updated, err := h.service.Update(c.Request().Context(), item.ID, req)
+	if err != nil {
+		return fileWatchError(err)
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+// Delete godoc
+// @Summary Delete file watch
+// @Tags file-watches
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "File watch ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/file-watches/{id} [delete]
+func (h *FileWatchHandler) Delete(c echo.Context) error {
+	item, err := h.requireWatch(c)
+	if err != nil {
+		return err
+	}
+	if err := h.service.Delete(c.Request().Context(), item.ID); err != nil {
+		return fileWatchError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// requireBotAccess authorizes the caller for the bot in the path and returns
+// its ID.
+func (h *FileWatchHandler) requireBotAccess(c echo.Context) (string, error) {
+	userID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return "", err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := h.authorizeBotAccess(c.Request().Context(), userID, botID); err != nil {
+		return "", err
+	}
+	return botID, nil
+}
+
+// requireWatch loads the watch in the path after checking it belongs to the
+// bot the caller may access.
+func (h *FileWatchHandler) requireWatch(c echo.Context) (filewatch.Watch, error) {
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return filewatch.Watch{}, err
+	}
+	item, err := h.service.Get(c.Request().Context(), strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		return filewatch.Watch{}, fileWatchError(err)
+	}
+	if item.BotID != botID {
+		return filewatch.Watch{}, echo.NewHTTPError(http.StatusForbidden, "bot mismatch")
+	}
+	return item, nil
+}
+
+func (h *FileWatchHandler) authorizeBotAccess(ctx context.Context, userID, botID string) (bots.Bot, error) {
+	return AuthorizeBotAccess(ctx, h.botService, h.accountService, userID, botID, bots.AccessPolicy{AllowPublicMember: false})
+}
+
+func fileWatchError(err error) error {
+	switch {
+	case errors.Is(err, filewatch.ErrWatchNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, filewatch.ErrInvalidWatch):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
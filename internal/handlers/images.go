@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/containerd/errdefs"
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	ctr "github.com/memohai/memoh/internal/containerd"
+	dbsqlc "github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/mcp"
+)
+
+// ImagesHandler manages the container images available to bots.
+type ImagesHandler struct {
+	service        ctr.Service
+	manager        *mcp.Manager
+	accountService *accounts.Service
+	queries        *dbsqlc.Queries
+	logger         *slog.Logger
+}
+
+type ImageInfo struct {
+	Name    string   `json:"name"`
+	ID      string   `json:"id"`
+	Tags    []string `json:"tags,omitempty"`
+	Default bool     `json:"default"`
+}
+
+type ListImagesResponse struct {
+	Items []ImageInfo `json:"items"`
+}
+
+type PullImageRequest struct {
+	Ref string `json:"ref"`
+	// Stream reports pull progress as server-sent events instead of a single
+	// JSON response.
+	Stream bool `json:"stream,omitempty"`
+}
+
+// PullImageEvent is one server-sent event of a streamed image pull.
+type PullImageEvent struct {
+	Status     string     `json:"status"`
+	Active     int        `json:"active,omitempty"`
+	Downloaded int64      `json:"downloaded,omitempty"`
+	Total      int64      `json:"total,omitempty"`
+	Image      *ImageInfo `json:"image,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+const (
+	pullEventProgress = "progress"
+	pullEventDone     = "done"
+	pullEventError    = "error"
+)
+
+func NewImagesHandler(log *slog.Logger, service ctr.Service, manager *mcp.Manager, accountService *accounts.Service, queries *dbsqlc.Queries) *ImagesHandler {
+	return &ImagesHandler{
+		service:        service,
+		manager:        manager,
+		accountService: accountService,
+		queries:        queries,
+		logger:         log.With(slog.String("handler", "images")),
+	}
+}
+
+func (h *ImagesHandler) Register(e *echo.Echo) {
+	group := e.Group("/images")
+	group.GET("", h.List)
+	group.POST("/pull", h.Pull)
+	group.DELETE("", h.Delete)
+}
+
+// List godoc
+// @Summary List container images
+// @Description List the images available to bot containers (admin only)
+// @Tags images
+// @Success 200 {object} ListImagesResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /images [get]
+func (h *ImagesHandler) List(c echo.Context) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	images, err := h.service.ListImages(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	items := make([]ImageInfo, 0, len(images))
+	for _, image := range images {
+		items = append(items, h.toImageInfo(image))
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return c.JSON(http.StatusOK, ListImagesResponse{Items: items})
+}
+
+// Pull godoc
+// @Summary Pull a container image
+// @Description Pull an image with retries (admin only). When the registry is unreachable but the image is already present locally, the local copy is returned. With stream=true, progress is sent as server-sent events.
+// @Tags images
+// @Param payload body PullImageRequest true "Pull image payload"
+// @Success 200 {object} ImageInfo
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Router /images/pull [post]
+func (h *ImagesHandler) Pull(c echo.Context) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	var req PullImageRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	ref, err := mcp.NormalizeImageRef(req.Ref)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	ctx := c.Request().Context()
+	if !req.Stream {
+		image, err := h.manager.PullImage(ctx, ref, nil)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+		}
+		return c.JSON(http.StatusOK, h.toImageInfo(image))
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-cache")
+	c.Response().Header().Set(echo.HeaderConnection, "keep-alive")
+	c.Response().WriteHeader(http.StatusOK)
+	flusher, ok := c.Response().Writer.(http.Flusher)
+	if !ok {
+		return echo.NewHTTPError(http.StatusInternalServerError, "streaming not supported")
+	}
+	writer := bufio.NewWriter(c.Response().Writer)
+	events := make(chan PullImageEvent, 16)
+	go func() {
+		defer close(events)
+		image, err := h.manager.PullImage(ctx, ref, func(progress ctr.PullProgress) {
+			select {
+			case events <- PullImageEvent{
+				Status:     pullEventProgress,
+				Active:     progress.Active,
+				Downloaded: progress.Downloaded,
+				Total:      progress.Total,
+			}:
+			default:
+				// Drop progress updates the client is too slow to read.
+			}
+		})
+		if err != nil {
+			events <- PullImageEvent{Status: pullEventError, Error: err.Error()}
+			return
+		}
+		info := h.toImageInfo(image)
+		events <- PullImageEvent{Status: pullEventDone, Image: &info}
+	}()
+	for event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			continue
+		}
+		if _, err := fmt.Fprintf(writer, "data: %s\n\n", data); err != nil {
+			continue // client disconnected; keep draining until the pull stops
+		}
+		writer.Flush()
+		flusher.Flush()
+	}
+	return nil
+}
+
+// Delete godoc
+// @Summary Delete a container image
+// @Description Delete an image that no bot container uses (admin only)
+// @Tags images
+// @Param ref query string true "Image reference"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /images [delete]
+func (h *ImagesHandler) Delete(c echo.Context) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	ref, err := mcp.NormalizeImageRef(c.QueryParam("ref"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if ref == h.manager.DefaultImageRef() {
+		return echo.NewHTTPError(http.StatusConflict, "the default image cannot be deleted")
+	}
+	ctx := c.Request().Context()
+	if h.queries != nil {
+		count, err := h.queries.CountContainersByImage(ctx, ref)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		if count > 0 {
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("image is used by %d bot container(s)", count))
+		}
+	}
+	if err := h.service.DeleteImage(ctx, ref, &ctr.DeleteImageOptions{Synchronous: true}); err != nil {
+		if errdefs.IsNotFound(err) {
+			return echo.NewHTTPError(http.StatusNotFound, "image not found")
+		}
+		if errors.Is(err, ctr.ErrNotSupported) {
+			return echo.NewHTTPError(http.StatusNotImplemented, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *ImagesHandler) toImageInfo(image ctr.ImageInfo) ImageInfo {
+	return ImageInfo{
+		Name:    image.Name,
+		ID:      image.ID,
+		Tags:    image.Tags,
+		Default: strings.TrimSpace(image.Name) == h.manager.DefaultImageRef(),
+	}
+}
+
+func (h *ImagesHandler) requireAdmin(c echo.Context) error {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	isAdmin, err := h.accountService.IsAdmin(c.Request().Context(), channelIdentityID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if !isAdmin {
+		return echo.NewHTTPError(http.StatusForbidden, "admin role required")
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ const (
 	headerSessionToken      = "X-Memoh-Session-Token"
 	headerCurrentPlatform   = "X-Memoh-Current-Platform"
 	headerReplyTarget       = "X-Memoh-Reply-Target"
+	headerConversationID    = "X-Memoh-Conversation-Id"
 )
 
 func (h *ContainerdHandler) SetToolGatewayService(service *mcpgw.ToolGatewayService) {
@@ -237,5 +238,6 @@ func (h *ContainerdHandler) buildToolSessionContext(c echo.Context, botID string
 		SessionToken:      strings.TrimSpace(c.Request().Header.Get(headerSessionToken)),
 		CurrentPlatform:   strings.TrimSpace(c.Request().Header.Get(headerCurrentPlatform)),
 		ReplyTarget:       strings.TrimSpace(c.Request().Header.Get(headerReplyTarget)),
+		ConversationID:    strings.TrimSpace(c.Request().Header.Get(headerConversationID)),
 	}
 }
@@ -1,18 +1,25 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/channel/identities"
 	"github.com/memohai/memoh/internal/conversation"
 	"github.com/memohai/memoh/internal/memory"
+	"github.com/memohai/memoh/internal/schedule"
 )
 
 // MemoryHandler handles memory CRUD operations scoped by conversation.
@@ -20,7 +27,9 @@ type MemoryHandler struct {
 	service        *memory.Service
 	chatService    *conversation.Service
 	accountService *accounts.Service
+	identities     *identities.Service
 	memoryFS       *memory.MemoryFS
+	compaction     *schedule.Service
 	logger         *slog.Logger
 }
 
@@ -33,6 +42,7 @@ type memoryAddPayload struct {
 	Filters          map[string]any   `json:"filters,omitempty"`
 	Infer            *bool            `json:"infer,omitempty"`
 	EmbeddingEnabled *bool            `json:"embedding_enabled,omitempty"`
+	ExpiresAt        *time.Time       `json:"expires_at,omitempty"`
 }
 
 type memorySearchPayload struct {
@@ -52,6 +62,7 @@ type memoryDeletePayload struct {
 type memoryCompactPayload struct {
 	Ratio     float64 `json:"ratio"`
 	DecayDays *int    `json:"decay_days,omitempty"`
+	DryRun    bool    `json:"dry_run,omitempty"`
 }
 
 // namespaceScope holds namespace + scopeId for a single memory scope.
@@ -62,6 +73,9 @@ type namespaceScope struct {
 
 const sharedMemoryNamespace = "bot"
 
+// maxMemoryArchiveBytes caps uploaded memory archives.
+const maxMemoryArchiveBytes = 256 << 20
+
 // NewMemoryHandler creates a MemoryHandler.
 func NewMemoryHandler(log *slog.Logger, service *memory.Service, chatService *conversation.Service, accountService *accounts.Service) *MemoryHandler {
 	return &MemoryHandler{
@@ -77,17 +91,37 @@ func (h *MemoryHandler) SetMemoryFS(fs *memory.MemoryFS) {
 	h.memoryFS = fs
 }
 
+// SetIdentityService enables matching linked channel identities in the
+// personal memory endpoints.
+func (h *MemoryHandler) SetIdentityService(service *identities.Service) {
+	h.identities = service
+}
+
+// SetCompactionRunner routes manual compactions through the schedule service
+// so they are recorded in the bot's compaction run history.
+func (h *MemoryHandler) SetCompactionRunner(service *schedule.Service) {
+	h.compaction = service
+}
+
 // Register registers chat-level memory routes.
 func (h *MemoryHandler) Register(e *echo.Echo) {
 	chatGroup := e.Group("/bots/:bot_id/memory")
 	chatGroup.POST("", h.ChatAdd)
 	chatGroup.POST("/search", h.ChatSearch)
 	chatGroup.POST("/compact", h.ChatCompact)
+	chatGroup.GET("/compaction/runs", h.ChatListCompactionRuns)
 	chatGroup.POST("/rebuild", h.ChatRebuild)
+	chatGroup.GET("/export", h.ChatExport)
+	chatGroup.POST("/import", h.ChatImport)
 	chatGroup.GET("", h.ChatGetAll)
 	chatGroup.GET("/usage", h.ChatUsage)
+	chatGroup.GET("/me", h.ChatListMine)
+	chatGroup.DELETE("/me", h.ChatDeleteMine)
+	chatGroup.DELETE("/me/:memory_id", h.ChatDeleteMineOne)
 	chatGroup.DELETE("", h.ChatDelete)
 	chatGroup.DELETE("/:memory_id", h.ChatDeleteOne)
+	chatGroup.GET("/:memory_id/revisions", h.ChatListRevisions)
+	chatGroup.POST("/:memory_id/revisions/:revision_id/restore", h.ChatRestoreRevision)
 }
 
 func (h *MemoryHandler) checkService() error {
@@ -101,7 +135,7 @@ func (h *MemoryHandler) checkService() error {
 
 // ChatAdd godoc
 // @Summary Add memory
-// @Description Add memory into the bot-shared namespace
+// @Description Add memory into the bot-shared namespace. Memories with expires_at are removed automatically once it passes.
 // @Tags memory
 // @Accept json
 // @Produce json
@@ -138,6 +172,9 @@ func (h *MemoryHandler) ChatAdd(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	if payload.ExpiresAt != nil && !payload.ExpiresAt.After(time.Now()) {
+		return echo.NewHTTPError(http.StatusBadRequest, "expires_at must be in the future")
+	}
 
 	// Resolve bot scope for shared memory.
 	scopeID, botID, err := h.resolveWriteScope(c.Request().Context(), containerID)
@@ -155,6 +192,7 @@ func (h *MemoryHandler) ChatAdd(c echo.Context) error {
 		Filters:          filters,
 		Infer:            payload.Infer,
 		EmbeddingEnabled: payload.EmbeddingEnabled,
+		ExpiresAt:        payload.ExpiresAt,
 	}
 	resp, err := h.service.Add(c.Request().Context(), req)
 	if err != nil {
@@ -439,11 +477,13 @@ func (h *MemoryHandler) ChatDeleteOne(c echo.Context) error {
 // @Description - 0.3 = aggressive compression, heavily consolidate, keep ~30%
 // @Description
 // @Description **decay_days** (optional): enable time decay — memories older than N days are treated as low priority and more likely to be merged/dropped.
+// @Description
+// @Description **dry_run** (optional): only preview which memories would merge; nothing is changed.
 // @Tags memory
 // @Accept json
 // @Produce json
 // @Param bot_id path string true "Bot ID"
-// @Param payload body memoryCompactPayload true "ratio (0,1] required; decay_days optional"
+// @Param payload body memoryCompactPayload true "ratio (0,1] required; decay_days and dry_run optional"
 // @Success 200 {object} memory.CompactResult
 // @Failure 400 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
@@ -489,7 +529,26 @@ func (h *MemoryHandler) ChatCompact(c echo.Context) error {
 
 	// Compact the first (primary) scope.
 	scope := scopes[0]
+	if h.compaction != nil {
+		_, result, err := h.compaction.RunCompaction(c.Request().Context(), scope.ScopeID, schedule.CompactionTriggerManual, schedule.CompactionRequest{
+			Ratio:     &ratio,
+			DecayDays: &decayDays,
+			DryRun:    payload.DryRun,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		return c.JSON(http.StatusOK, result)
+	}
+
 	filters := buildNamespaceFilters(scope.Namespace, scope.ScopeID, nil)
+	if payload.DryRun {
+		result, err := h.service.PreviewCompact(c.Request().Context(), filters, ratio, decayDays)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		return c.JSON(http.StatusOK, result)
+	}
 	result, err := h.service.Compact(c.Request().Context(), filters, ratio, decayDays)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
@@ -505,6 +564,53 @@ func (h *MemoryHandler) ChatCompact(c echo.Context) error {
 	return c.JSON(http.StatusOK, result)
 }
 
+// ChatListCompactionRuns godoc
+// @Summary List memory compaction runs
+// @Description List the scheduled and manual memory compactions of a bot, newest first, including the merges each run made or previewed
+// @Tags memory
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param limit query int false "Maximum number of runs (default 20)"
+// @Success 200 {object} schedule.CompactionRunListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /bots/{bot_id}/memory/compaction/runs [get]
+func (h *MemoryHandler) ChatListCompactionRuns(c echo.Context) error {
+	if h.compaction == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "memory compaction not available")
+	}
+	channelIdentityID, err := h.requireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	containerID, err := h.resolveBotContainerID(c)
+	if err != nil {
+		return err
+	}
+	if err := h.requireChatParticipant(c.Request().Context(), containerID, channelIdentityID); err != nil {
+		return err
+	}
+	_, botID, err := h.resolveWriteScope(c.Request().Context(), containerID)
+	if err != nil {
+		return err
+	}
+
+	limit := 0
+	if raw := strings.TrimSpace(c.QueryParam("limit")); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid limit")
+		}
+	}
+	items, err := h.compaction.ListCompactionRuns(c.Request().Context(), botID, limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, schedule.CompactionRunListResponse{Items: items})
+}
+
 // ChatUsage godoc
 // @Summary Get memory usage
 // @Description Query the estimated storage usage of current memories
@@ -649,8 +755,419 @@ func (h *MemoryHandler) ChatRebuild(c echo.Context) error {
 	})
 }
 
+// ChatExport godoc
+// @Summary Export memories
+// @Description Export bot-shared memories as a versioned archive (gzipped tar with manifest.json and memories.jsonl)
+// @Tags memory
+// @Produce application/gzip
+// @Param bot_id path string true "Bot ID"
+// @Param include_vectors query bool false "Include dense vectors and the model that produced them"
+// @Success 200 {file} binary
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /bots/{bot_id}/memory/export [get]
+func (h *MemoryHandler) ChatExport(c echo.Context) error {
+	if err := h.checkService(); err != nil {
+		return err
+	}
+	channelIdentityID, err := h.requireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	containerID, err := h.resolveBotContainerID(c)
+	if err != nil {
+		return err
+	}
+	if err := h.requireChatParticipant(c.Request().Context(), containerID, channelIdentityID); err != nil {
+		return err
+	}
+	scopeID, botID, err := h.resolveWriteScope(c.Request().Context(), containerID)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if _, err := h.service.Export(c.Request().Context(), &buf, memory.ExportRequest{
+		BotID:          botID,
+		Filters:        buildNamespaceFilters(sharedMemoryNamespace, scopeID, nil),
+		IncludeVectors: strings.EqualFold(c.QueryParam("include_vectors"), "true"),
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	filename := fmt.Sprintf("memory-%s-%s.tar.gz", botID, time.Now().UTC().Format("20060102T150405Z"))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Blob(http.StatusOK, "application/gzip", buf.Bytes())
+}
+
+// ChatImport godoc
+// @Summary Import memories
+// @Description Import a memory archive produced by the export endpoint into the bot-shared namespace. When an embedding model is configured, vectors that are missing or from a different model are re-embedded unless embedding_enabled=false.
+// @Tags memory
+// @Accept application/gzip
+// @Accept multipart/form-data
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param embedding_enabled query bool false "Re-embed memories whose vectors are missing or from another model (default true; false keeps archived vectors only)"
+// @Param file formData file false "Memory archive (multipart upload); otherwise send the archive as the request body"
+// @Success 200 {object} memory.ImportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /bots/{bot_id}/memory/import [post]
+func (h *MemoryHandler) ChatImport(c echo.Context) error {
+	if err := h.checkService(); err != nil {
+		return err
+	}
+	channelIdentityID, err := h.requireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	containerID, err := h.resolveBotContainerID(c)
+	if err != nil {
+		return err
+	}
+	if err := h.requireChatParticipant(c.Request().Context(), containerID, channelIdentityID); err != nil {
+		return err
+	}
+	scopeID, botID, err := h.resolveWriteScope(c.Request().Context(), containerID)
+	if err != nil {
+		return err
+	}
+
+	body := http.MaxBytesReader(c.Response(), c.Request().Body, maxMemoryArchiveBytes)
+	var archive io.Reader = body
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		c.Request().Body = body
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "file is required")
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		defer func() { _ = file.Close() }()
+		archive = file
+	}
+
+	filters := buildNamespaceFilters(sharedMemoryNamespace, scopeID, nil)
+	// Re-embedding is on by default; the service skips it without an embedder.
+	result, err := h.service.Import(c.Request().Context(), archive, memory.ImportRequest{
+		BotID:            botID,
+		Filters:          filters,
+		EmbeddingEnabled: !strings.EqualFold(c.QueryParam("embedding_enabled"), "false"),
+	})
+	if err != nil {
+		if errors.Is(err, memory.ErrInvalidArchive) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if h.memoryFS != nil && len(result.Items) > 0 {
+		items := result.Items
+		go func() {
+			bgCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+			defer cancel()
+			if err := h.memoryFS.PersistMemories(bgCtx, botID, items, filters); err != nil {
+				h.logger.Warn("async memory import persist failed", slog.Any("error", err))
+			}
+		}()
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// ChatListRevisions godoc
+// @Summary List memory revisions
+// @Description List the audit history of a memory (ADD/UPDATE/DELETE/COMPACT/RESTORE), newest first
+// @Tags memory
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param memory_id path string true "Memory ID"
+// @Param limit query int false "Maximum number of revisions (default 50)"
+// @Success 200 {object} memory.RevisionListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /bots/{bot_id}/memory/{memory_id}/revisions [get]
+func (h *MemoryHandler) ChatListRevisions(c echo.Context) error {
+	if err := h.checkService(); err != nil {
+		return err
+	}
+	channelIdentityID, err := h.requireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	containerID, err := h.resolveBotContainerID(c)
+	if err != nil {
+		return err
+	}
+	if err := h.requireChatParticipant(c.Request().Context(), containerID, channelIdentityID); err != nil {
+		return err
+	}
+	_, botID, err := h.resolveWriteScope(c.Request().Context(), containerID)
+	if err != nil {
+		return err
+	}
+
+	memoryID := strings.TrimSpace(c.Param("memory_id"))
+	if memoryID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "memory_id is required")
+	}
+	limit := 0
+	if raw := strings.TrimSpace(c.QueryParam("limit")); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid limit")
+		}
+	}
+	items, err := h.service.ListRevisions(c.Request().Context(), botID, memoryID, limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, memory.RevisionListResponse{Items: items})
+}
+
+// ChatRestoreRevision godoc
+// @Summary Restore memory revision
+// @Description Restore a memory to the text and payload captured by a revision. Deleted memories are re-created under the same ID.
+// @Tags memory
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param memory_id path string true "Memory ID"
+// @Param revision_id path string true "Revision ID"
+// @Param embedding_enabled query bool false "Re-embed the restored memory"
+// @Success 200 {object} memory.MemoryItem
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /bots/{bot_id}/memory/{memory_id}/revisions/{revision_id}/restore [post]
+func (h *MemoryHandler) ChatRestoreRevision(c echo.Context) error {
+	if err := h.checkService(); err != nil {
+		return err
+	}
+	channelIdentityID, err := h.requireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	containerID, err := h.resolveBotContainerID(c)
+	if err != nil {
+		return err
+	}
+	if err := h.requireChatParticipant(c.Request().Context(), containerID, channelIdentityID); err != nil {
+		return err
+	}
+	scopeID, botID, err := h.resolveWriteScope(c.Request().Context(), containerID)
+	if err != nil {
+		return err
+	}
+
+	memoryID := strings.TrimSpace(c.Param("memory_id"))
+	revisionID := strings.TrimSpace(c.Param("revision_id"))
+	if memoryID == "" || revisionID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "memory_id and revision_id are required")
+	}
+	item, err := h.service.RestoreRevision(c.Request().Context(), memory.RestoreRequest{
+		BotID:            botID,
+		MemoryID:         memoryID,
+		RevisionID:       revisionID,
+		EmbeddingEnabled: strings.EqualFold(c.QueryParam("embedding_enabled"), "true"),
+	})
+	if err != nil {
+		if errors.Is(err, memory.ErrRevisionNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if h.memoryFS != nil {
+		filters := buildNamespaceFilters(sharedMemoryNamespace, scopeID, nil)
+		if err := h.memoryFS.PersistMemories(c.Request().Context(), botID, []memory.MemoryItem{item}, filters); err != nil {
+			h.logger.Warn("restore memory fs persist failed", slog.Any("error", err))
+		}
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// ChatListMine godoc
+// @Summary List my memories
+// @Description List the memories the bot has learned from the caller, matched by user ID and every linked channel identity
+// @Tags memory
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} memory.SearchResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /bots/{bot_id}/memory/me [get]
+func (h *MemoryHandler) ChatListMine(c echo.Context) error {
+	if err := h.checkService(); err != nil {
+		return err
+	}
+	userID, err := h.requireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	botID, err := h.resolveBotContainerID(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.listCallerMemories(c.Request().Context(), botID, userID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, memory.SearchResponse{Results: items})
+}
+
+// ChatDeleteMine godoc
+// @Summary Delete my memories
+// @Description Delete every memory the bot has learned from the caller
+// @Tags memory
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} memory.DeleteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /bots/{bot_id}/memory/me [delete]
+func (h *MemoryHandler) ChatDeleteMine(c echo.Context) error {
+	if err := h.checkService(); err != nil {
+		return err
+	}
+	userID, err := h.requireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	botID, err := h.resolveBotContainerID(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.listCallerMemories(c.Request().Context(), botID, userID)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return c.JSON(http.StatusOK, memory.DeleteResponse{Message: "No memories to delete."})
+	}
+	memoryIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		memoryIDs = append(memoryIDs, item.ID)
+	}
+	resp, err := h.service.DeleteBatch(c.Request().Context(), memoryIDs)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if h.memoryFS != nil {
+		if err := h.memoryFS.RemoveMemories(c.Request().Context(), botID, memoryIDs); err != nil {
+			h.logger.Warn("delete my memories fs remove failed", slog.Any("error", err))
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ChatDeleteMineOne godoc
+// @Summary Delete one of my memories
+// @Description Delete a single memory the bot has learned from the caller
+// @Tags memory
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param memory_id path string true "Memory ID"
+// @Success 200 {object} memory.DeleteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /bots/{bot_id}/memory/me/{memory_id} [delete]
+func (h *MemoryHandler) ChatDeleteMineOne(c echo.Context) error {
+	if err := h.checkService(); err != nil {
+		return err
+	}
+	userID, err := h.requireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	botID, err := h.resolveBotContainerID(c)
+	if err != nil {
+		return err
+	}
+	memoryID := strings.TrimSpace(c.Param("memory_id"))
+	if memoryID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "memory_id is required")
+	}
+	items, err := h.listCallerMemories(c.Request().Context(), botID, userID)
+	if err != nil {
+		return err
+	}
+	owned := false
+	for _, item := range items {
+		if item.ID == memoryID {
+			owned = true
+			break
+		}
+	}
+	if !owned {
+		return echo.NewHTTPError(http.StatusNotFound, "memory not found")
+	}
+	resp, err := h.service.Delete(c.Request().Context(), memoryID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if h.memoryFS != nil {
+		if err := h.memoryFS.RemoveMemories(c.Request().Context(), botID, []string{memoryID}); err != nil {
+			h.logger.Warn("delete my memory fs remove failed", slog.Any("error", err))
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
 // --- helpers ---
 
+// callerSubjectFilters returns one payload filter per identity of the caller:
+// the user ID and every channel identity linked to it.
+func (h *MemoryHandler) callerSubjectFilters(ctx context.Context, userID string) ([]map[string]any, error) {
+	filters := []map[string]any{{memory.SubjectUserKey: userID}}
+	if h.identities == nil {
+		return filters, nil
+	}
+	linked, err := h.identities.ListUserChannelIdentities(ctx, userID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	for _, identity := range linked {
+		if id := strings.TrimSpace(identity.ID); id != "" {
+			filters = append(filters, map[string]any{memory.SubjectChannelIdentityKey: id})
+		}
+	}
+	return filters, nil
+}
+
+// listCallerMemories returns the bot memories tagged with any identity of the caller.
+func (h *MemoryHandler) listCallerMemories(ctx context.Context, botID, userID string) ([]memory.MemoryItem, error) {
+	subjects, err := h.callerSubjectFilters(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var items []memory.MemoryItem
+	for _, subject := range subjects {
+		found, err := h.service.ListAll(ctx, buildNamespaceFilters(sharedMemoryNamespace, botID, subject))
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		items = append(items, found...)
+	}
+	items = deduplicateMemoryItems(items)
+	if items == nil {
+		items = []memory.MemoryItem{}
+	}
+	return items, nil
+}
+
 // resolveEnabledScopes returns the bot-shared namespace scope for the conversation.
 func (h *MemoryHandler) resolveEnabledScopes(ctx context.Context, chatID string) ([]namespaceScope, error) {
 	if h.chatService == nil {
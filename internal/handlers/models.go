@@ -66,7 +66,7 @@ func (h *ModelsHandler) Create(c echo.Context) error {
 // @Summary List all models
 // @Description Get a list of all configured models, optionally filtered by type or client type
 // @Tags models
-// @Param type query string false "Model type (chat, embedding)"
+// @Param type query string false "Model type (chat, embedding, rerank)"
 // @Param client_type query string false "Client type (openai-responses, openai-completions, anthropic-messages, google-generative-ai)"
 // @Success 200 {array} models.GetResponse
 // @Failure 400 {object} ErrorResponse
@@ -284,7 +284,7 @@ func (h *ModelsHandler) DeleteByModelID(c echo.Context) error {
 // @Summary Get model count
 // @Description Get the total count of models, optionally filtered by type
 // @Tags models
-// @Param type query string false "Model type (chat, embedding)"
+// @Param type query string false "Model type (chat, embedding, rerank)"
 // @Success 200 {object} models.CountResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -96,7 +96,7 @@ func (h *SettingsHandler) Upsert(c echo.Context) error {
 		if errors.Is(err, settings.ErrPersonalBotGuestAccessUnsupported) {
 			return echo.NewHTTPError(http.StatusBadRequest, "personal bot does not support guest access")
 		}
-		if errors.Is(err, settings.ErrInvalidModelRef) {
+		if errors.Is(err, settings.ErrInvalidModelRef) || errors.Is(err, settings.ErrInvalidCompaction) || errors.Is(err, settings.ErrInvalidResourceLimits) || errors.Is(err, settings.ErrInvalidNetworkPolicy) || errors.Is(err, settings.ErrInvalidSnapshotPolicy) {
 			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 		}
 		if errors.Is(err, settings.ErrModelIDAmbiguous) {
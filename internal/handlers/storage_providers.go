@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/storageproviders"
+)
+
+type StorageProvidersHandler struct {
+	service        *storageproviders.Service
+	botService     *bots.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+func NewStorageProvidersHandler(log *slog.Logger, service *storageproviders.Service, botService *bots.Service, accountService *accounts.Service) *StorageProvidersHandler {
+	return &StorageProvidersHandler{
+		service:        service,
+		botService:     botService,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "storage_providers")),
+	}
+}
+
+func (h *StorageProvidersHandler) Register(e *echo.Echo) {
+	group := e.Group("/storage-providers")
+	group.GET("/meta", h.ListMeta)
+	group.POST("", h.Create)
+	group.GET("", h.List)
+	group.GET("/:id", h.Get)
+	group.PUT("/:id", h.Update)
+	group.DELETE("/:id", h.Delete)
+
+	botGroup := e.Group("/bots/:bot_id/storage")
+	botGroup.GET("", h.GetBinding)
+	botGroup.PUT("", h.UpsertBinding)
+	botGroup.DELETE("", h.DeleteBinding)
+	botGroup.POST("/migrate", h.StartMigration)
+	botGroup.GET("/migrate", h.GetMigration)
+}
+
+// ListMeta godoc
+// @Summary List storage provider metadata
+// @Description List available storage provider types and config schemas
+// @Tags storage-providers
+// @Success 200 {array} storageproviders.ProviderMeta
+// @Failure 403 {object} ErrorResponse
+// @Router /storage-providers/meta [get]
+func (h *StorageProvidersHandler) ListMeta(c echo.Context) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, h.service.ListMeta(c.Request().Context()))
+}
+
+// Create godoc
+// @Summary Create a storage provider
+// @Description Create a storage provider configuration (admin only)
+// @Tags storage-providers
+// @Accept json
+// @Produce json
+// @Param request body storageproviders.CreateRequest true "Storage provider configuration"
+// @Success 201 {object} storageproviders.GetResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /storage-providers [post]
+func (h *StorageProvidersHandler) Create(c echo.Context) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	var req storageproviders.CreateRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "name is required")
+	}
+	if strings.TrimSpace(string(req.Provider)) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "provider is required")
+	}
+	resp, err := h.service.Create(c.Request().Context(), req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// List godoc
+// @Summary List storage providers
+// @Description List configured storage providers (admin only)
+// @Tags storage-providers
+// @Produce json
+// @Success 200 {array} storageproviders.GetResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /storage-providers [get]
+func (h *StorageProvidersHandler) List(c echo.Context) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	items, err := h.service.List(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
+// Get godoc
+// @Summary Get a storage provider
+// @Description Get storage provider by ID (admin only)
+// @Tags storage-providers
+// @Produce json
+// @Param id path string true "Provider ID"
+// @Success 200 {object} storageproviders.GetResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /storage-providers/{id} [get]
+func (h *StorageProvidersHandler) Get(c echo.Context) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "id is required")
+	}
+	resp, err := h.service.Get(c.Request().Context(), id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Update godoc
+// @Summary Update a storage provider
+// @Description Update storage provider by ID (admin only)
+// @Tags storage-providers
+// @Accept json
+// @Produce json
+// @Param id path string true "Provider ID"
+// @Param request body storageproviders.UpdateRequest true "Updated configuration"
+// @Success 200 {object} storageproviders.GetResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /storage-providers/{id} [put]
+func (h *StorageProvidersHandler) Update(c echo.Context) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "id is required")
+	}
+	var req storageproviders.UpdateRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	resp, err := h.service.Update(c.Request().Context(), id, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Delete godoc
+// @Summary Delete a storage provider
+// @Description Delete storage provider by ID (admin only)
+// @Tags storage-providers
+// @Param id path string true "Provider ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /storage-providers/{id} [delete]
+func (h *StorageProvidersHandler) Delete(c echo.Context) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "id is required")
+	}
+	if err := h.service.Delete(c.Request().Context(), id); err != nil {
+		if errors.Is(err, storageproviders.ErrProviderInUse) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetBinding godoc
+// @Summary Get bot storage binding
+// @Description Get the storage provider bound to a bot
+// @Tags storage-providers
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} storageproviders.BindingResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /bots/{bot_id}/storage [get]
+func (h *StorageProvidersHandler) GetBinding(c echo.Context) error {
+	botID, err := h.authorizeBot(c)
+	if err != nil {
+		return err
+	}
+	resp, err := h.service.GetBinding(c.Request().Context(), botID)
+	if err != nil {
+		if errors.Is(err, storageproviders.ErrBindingNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// UpsertBinding godoc
+// @Summary Bind bot storage
+// @Description Bind a bot to a storage provider. Existing assets are not moved; use the migrate endpoint for that.
+// @Tags storage-providers
+// @Accept json
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param request body storageproviders.BindingRequest true "Binding"
+// @Success 200 {object} storageproviders.BindingResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /bots/{bot_id}/storage [put]
+func (h *StorageProvidersHandler) UpsertBinding(c echo.Context) error {
+	botID, err := h.authorizeBot(c)
+	if err != nil {
+		return err
+	}
+	var req storageproviders.BindingRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if strings.TrimSpace(req.StorageProviderID) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "storage_provider_id is required")
+	}
+	resp, err := h.service.UpsertBinding(c.Request().Context(), botID, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DeleteBinding godoc
+// @Summary Remove bot storage binding
+// @Description Remove the bot storage binding so the bot uses the default provider
+// @Tags storage-providers
+// @Param bot_id path string true "Bot ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/storage [delete]
+func (h *StorageProvidersHandler) DeleteBinding(c echo.Context) error {
+	botID, err := h.authorizeBot(c)
+	if err != nil {
+		return err
+	}
+	if err := h.service.DeleteBinding(c.Request().Context(), botID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// StartMigration godoc
+// @Summary Migrate bot storage
+// @Description Copy a bot's media assets to another storage provider in the background, then switch the binding
+// @Tags storage-providers
+// @Accept json
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Param request body storageproviders.MigrateRequest true "Migration target"
+// @Success 202 {object} storageproviders.MigrationStatus
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /bots/{bot_id}/storage/migrate [post]
+func (h *StorageProvidersHandler) StartMigration(c echo.Context) error {
+	botID, err := h.authorizeBot(c)
+	if err != nil {
+		return err
+	}
+	var req storageproviders.MigrateRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	status, err := h.service.StartMigration(c.Request().Context(), botID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, storageproviders.ErrMigrationRunning), errors.Is(err, storageproviders.ErrSameTarget):
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+	return c.JSON(http.StatusAccepted, status)
+}
+
+// GetMigration godoc
+// @Summary Get bot storage migration status
+// @Description Get progress of the latest storage migration for a bot
+// @Tags storage-providers
+// @Produce json
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} storageproviders.MigrationStatus
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /bots/{bot_id}/storage/migrate [get]
+func (h *StorageProvidersHandler) GetMigration(c echo.Context) error {
+	botID, err := h.authorizeBot(c)
+	if err != nil {
+		return err
+	}
+	status, ok := h.service.GetMigration(botID)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "no storage migration for bot")
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+func (h *StorageProvidersHandler) requireAdmin(c echo.Context) error {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	isAdmin, err := h.accountService.IsAdmin(c.Request().Context(), channelIdentityID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if !isAdmin {
+		return echo.NewHTTPError(http.StatusForbidden, "admin role required")
+	}
+	return nil
+}
+
+func (h *StorageProvidersHandler) authorizeBot(c echo.Context) (string, error) {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return "", err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "bot_id is required")
+	}
+	if _, err := AuthorizeBotAccess(c.Request().Context(), h.botService, h.accountService, channelIdentityID, botID, bots.AccessPolicy{AllowPublicMember: false}); err != nil {
+		return "", err
+	}
+	return botID, nil
+}
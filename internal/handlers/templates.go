@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bundle"
+)
+
+// TemplatesHandler manages the bot templates new bots can be created from.
+type TemplatesHandler struct {
+	service        *bundle.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+type ListBotTemplatesResponse struct {
+	Items []bundle.Template `json:"items"`
+}
+
+func NewTemplatesHandler(log *slog.Logger, service *bundle.Service, accountService *accounts.Service) *TemplatesHandler {
+	return &TemplatesHandler{
+		service:        service,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "templates")),
+	}
+}
+
+func (h *TemplatesHandler) Register(e *echo.Echo) {
+	group := e.Group("/bot-templates")
+	group.GET("", h.List)
+	group.POST("", h.Create)
+	group.GET("/:id", h.Get)
+	group.PUT("/:id", h.Update)
+	group.DELETE("/:id", h.Delete)
+}
+
+// List godoc
+// @Summary List bot templates
+// @Description List the templates bots can be created from
+// @Tags bot-templates
+// @Success 200 {object} ListBotTemplatesResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bot-templates [get]
+func (h *TemplatesHandler) List(c echo.Context) error {
+	if _, err := RequireChannelIdentityID(c); err != nil {
+		return err
+	}
+	items, err := h.service.ListTemplates(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, ListBotTemplatesResponse{Items: items})
+}
+
+// Get godoc
+// @Summary Get bot template
+// @Tags bot-templates
+// @Param id path string true "Template ID"
+// @Success 200 {object} bundle.Template
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bot-templates/{id} [get]
+func (h *TemplatesHandler) Get(c echo.Context) error {
+	if _, err := RequireChannelIdentityID(c); err != nil {
+		return err
+	}
+	item, err := h.service.GetTemplate(c.Request().Context(), strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		return templateError(err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// Create godoc
+// @Summary Create bot template
+// @Description Create a bot template with identity files, skills, settings, MCP connections, schedules and subagents (admin only)
+// @Tags bot-templates
+// @Param payload body bundle.TemplateRequest true "Template payload"
+// @Success 201 {object} bundle.Template
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bot-templates [post]
+func (h *TemplatesHandler) Create(c echo.Context) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	var req bundle.TemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	item, err := h.service.CreateTemplate(c.Request().Context(), req)
+	if err != nil {
+		return templateError(err)
+	}
+	return c.JSON(http.StatusCreated, item)
+}
+
+// Update godoc
+// @Summary Update bot template
+// @Description Replace a bot template (admin only). Bots created from it are not changed.
+// @Tags bot-templates
+// @Param id path string true "Template ID"
+// @Param payload body bundle.TemplateRequest true "Template payload"
+// @Success 200 {object} bundle.Template
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bot-templates/{id} [put]
+func (h *TemplatesHandler) Update(c echo.Context) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	var req bundle.TemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	item, err := h.service.UpdateTemplate(c.Request().Context(), strings.TrimSpace(c.Param("id")), req)
+	if err != nil {
+		return templateError(err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// Delete godoc
+// @Summary Delete bot template
+// @Description Delete a bot template (admin only). Bots created from it are kept.
+// @Tags bot-templates
+// @Param id path string true "Template ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bot-templates/{id} [delete]
+func (h *TemplatesHandler) Delete(c echo.Context) error {
+	if err := h.requireAdmin(c); err != nil {
+		return err
+	}
+	if err := h.service.DeleteTemplate(c.Request().Context(), strings.TrimSpace(c.Param("id"))); err != nil {
+		return templateError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *TemplatesHandler) requireAdmin(c echo.Context) error {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	isAdmin, err := h.accountService.IsAdmin(c.Request().Context(), channelIdentityID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if !isAdmin {
+		return echo.NewHTTPError(http.StatusForbidden, "admin role required")
+	}
+	return nil
+}
+
+func templateError(err error) error {
+	switch {
+	case errors.Is(err, bundle.ErrTemplateNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, bundle.ErrTemplateNameExists):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, bundle.ErrInvalidTemplate):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
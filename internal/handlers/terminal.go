@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/config"
+	ctr "github.com/memohai/memoh/internal/containerd"
+)
+
+const (
+	terminalMessageInput  = "input"
+	terminalMessageResize = "resize"
+	terminalMessageExit   = "exit"
+
+	terminalWriteTimeout = 10 * time.Second
+	terminalPongWait     = 60 * time.Second
+	terminalPingPeriod   = terminalPongWait / 2
+	terminalReadLimit    = 1 << 20
+	terminalBufferSize   = 32 * 1024
+)
+
+// terminalShellArgs starts a login shell, preferring bash when the image has it.
+var terminalShellArgs = []string{"/bin/sh", "-c", "if command -v bash >/dev/null 2>&1; then exec bash -l; fi; exec sh -l"}
+
+var terminalUpgrader = websocket.Upgrader{
+	ReadBufferSize:  terminalBufferSize,
+	WriteBufferSize: terminalBufferSize,
+	// Requests are authenticated by bearer token rather than cookies, so a
+	// cross-origin upgrade carries no ambient credentials.
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// TerminalMessage is a JSON control message of a terminal session. Clients send
+// input (also accepted as binary frames) and resize messages; the server sends
+// terminal output as binary frames and an exit message when the shell ends.
+type TerminalMessage struct {
+	Type     string  `json:"type"`
+	Data     string  `json:"data,omitempty"`
+	Cols     uint32  `json:"cols,omitempty"`
+	Rows     uint32  `json:"rows,omitempty"`
+	ExitCode *uint32 `json:"exit_code,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// ContainerTerminal godoc
+// @Summary Open an interactive terminal
+// @Description Upgrades to a WebSocket attached to a login shell (PTY) in the bot container. Browsers pass the access token as the token query parameter. Output is sent as binary frames; input is accepted as binary frames or {"type":"input","data":...} text frames, and {"type":"resize","cols":...,"rows":...} resizes the terminal.
+// @Tags containerd
+// @Param bot_id path string true "Bot ID"
+// @Param cols query int false "Initial terminal width"
+// @Param rows query int false "Initial terminal height"
+// @Success 101
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 501 {object} ErrorResponse
+// @Router /bots/{bot_id}/container/terminal [get]
+func (h *ContainerdHandler) ContainerTerminal(c echo.Context) error {
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	if !websocket.IsWebSocketUpgrade(c.Request()) {
+		return echo.NewHTTPError(http.StatusBadRequest, "websocket upgrade required")
+	}
+	cols, rows := parseTerminalSize(c.QueryParam("cols"), c.QueryParam("rows"))
+
+	ctx := c.Request().Context()
+	containerID, err := h.botContainerID(ctx, botID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "container not found for bot")
+	}
+	if err := h.validateMCPContainer(ctx, containerID, botID); err != nil {
+		return err
+	}
+	if h.manager != nil {
+		// The shell must not be frozen by idle suspension while it is open.
+		release := h.manager.HoldAwake(botID)
+		defer release()
+		if err := h.manager.SnapshotBeforeOperation(ctx, botID, "terminal"); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
+	if err := h.ensureContainerAndTask(ctx, containerID, botID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	// The shell outlives individual request-scoped calls; it is hung up when
+	// the socket closes.
+	sessionCtx := context.WithoutCancel(ctx)
+	session, err := h.service.ExecTaskStreaming(sessionCtx, containerID, ctr.ExecTaskRequest{
+		Args:     terminalShellArgs,
+		Env:      []string{"TERM=xterm-256color"},
+		WorkDir:  config.DefaultDataMount,
+		Terminal: true,
+		FIFODir:  h.mcpFIFODir(),
+	})
+	if err != nil {
+		if errors.Is(err, ctr.ErrNotSupported) {
+			return echo.NewHTTPError(http.StatusNotImplemented, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	conn, err := terminalUpgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		// The upgrader has already written an error response.
+		_ = session.Close()
+		return nil
+	}
+	h.logger.Info("terminal session opened", slog.String("bot_id", botID), slog.String("container_id", containerID))
+	serveTerminal(sessionCtx, conn, session, cols, rows, h.logger)
+	h.logger.Info("terminal session closed", slog.String("bot_id", botID), slog.String("container_id", containerID))
+	return nil
+}
+
+// serveTerminal pipes a terminal exec session through a WebSocket until either
+// side closes.
+func serveTerminal(ctx context.Context, conn *websocket.Conn, session *ctr.ExecTaskSession, cols, rows uint32, logger *slog.Logger) {
+	defer func() { _ = conn.Close() }()
+
+	var writeMu sync.Mutex
+	write := func(messageType int, data []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_ = conn.SetWriteDeadline(time.Now().Add(terminalWriteTimeout))
+		return conn.WriteMessage(messageType, data)
+	}
+	resize := func(cols, rows uint32) {
+		if cols == 0 || rows == 0 || session.Resize == nil {
+			return
+		}
+		if err := session.Resize(ctx, cols, rows); err != nil {
+			logger.Warn("terminal resize failed", slog.Any("error", err))
+		}
+	}
+	resize(cols, rows)
+
+	outputDone := make(chan struct{})
+	go func() {
+		defer close(outputDone)
+		buf := make([]byte, terminalBufferSize)
+		for {
+			n, err := session.Stdout.Read(buf)
+			if n > 0 {
+				if write(websocket.BinaryMessage, buf[:n]) != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		result, err := session.Wait()
+		<-outputDone
+		msg := TerminalMessage{Type: terminalMessageExit}
+		if err != nil {
+			msg.Error = err.Error()
+		} else {
+			msg.ExitCode = &result.ExitCode
+		}
+		if data, err := json.Marshal(msg); err == nil {
+			_ = write(websocket.TextMessage, data)
+		}
+		_ = write(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		// Unblock the read loop below.
+		_ = conn.Close()
+	}()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(terminalPingPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if write(websocket.PingMessage, nil) != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	conn.SetReadLimit(terminalReadLimit)
+	_ = conn.SetReadDeadline(time.Now().Add(terminalPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(terminalPongWait))
+	})
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(terminalPongWait))
+		switch messageType {
+		case websocket.BinaryMessage:
+			_, _ = session.Stdin.Write(data)
+		case websocket.TextMessage:
+			var msg TerminalMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				continue
+			}
+			switch msg.Type {
+			case terminalMessageInput:
+				_, _ = session.Stdin.Write([]byte(msg.Data))
+			case terminalMessageResize:
+				resize(msg.Cols, msg.Rows)
+			}
+		}
+	}
+	_ = session.Close()
+	select {
+	case <-exited:
+	case <-time.After(terminalWriteTimeout):
+		logger.Warn("terminal shell did not exit after hangup")
+	}
+}
+
+// parseTerminalSize parses the initial terminal size; invalid values are ignored.
+func parseTerminalSize(rawCols, rawRows string) (uint32, uint32) {
+	cols, err := strconv.ParseUint(strings.TrimSpace(rawCols), 10, 16)
+	if err != nil {
+		return 0, 0
+	}
+	rows, err := strconv.ParseUint(strings.TrimSpace(rawRows), 10, 16)
+	if err != nil {
+		return 0, 0
+	}
+	return uint32(cols), uint32(rows)
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	ctr "github.com/memohai/memoh/internal/containerd"
+)
+
+// fakeTerminal echoes stdin to stdout and exits with code 3 on "exit".
+type fakeTerminal struct {
+	mu      sync.Mutex
+	resizes [][2]uint32
+}
+
+func (f *fakeTerminal) session() *ctr.ExecTaskSession {
+	stdinR, stdinW := io.Pipe()
+	stdoutR, stdoutW := io.Pipe()
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		defer func() { _ = stdoutW.Close() }()
+		buf := make([]byte, 1024)
+		for {
+			n, err := stdinR.Read(buf)
+			if err != nil {
+				return
+			}
+			if strings.TrimSpace(string(buf[:n])) == "exit" {
+				return
+			}
+			if _, err := stdoutW.Write(buf[:n]); err != nil {
+				return
+			}
+		}
+	}()
+	return &ctr.ExecTaskSession{
+		Stdin:  stdinW,
+		Stdout: stdoutR,
+		Wait: func() (ctr.ExecTaskResult, error) {
+			<-exited
+			return ctr.ExecTaskResult{ExitCode: 3}, nil
+		},
+		Close: func() error {
+			_ = stdinR.Close()
+			return stdinW.Close()
+		},
+		Resize: func(_ context.Context, width, height uint32) error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.resizes = append(f.resizes, [2]uint32{width, height})
+			return nil
+		},
+	}
+}
+
+func TestServeTerminal(t *testing.T) {
+	fake := &fakeTerminal{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := terminalUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		serveTerminal(context.Background(), conn, fake.session(), 80, 24, slog.Default())
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	if err := conn.WriteMessage(websocket.BinaryMessage, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	messageType, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if messageType != websocket.BinaryMessage || string(data) != "hello" {
+		t.Fatalf("got %d %q, want binary hello", messageType, data)
+	}
+
+	if err := conn.WriteJSON(TerminalMessage{Type: terminalMessageResize, Cols: 120, Rows: 40}); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.WriteJSON(TerminalMessage{Type: terminalMessageInput, Data: "exit"}); err != nil {
+		t.Fatal(err)
+	}
+	messageType, data, err = conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg TerminalMessage
+	if messageType != websocket.TextMessage || json.Unmarshal(data, &msg) != nil {
+		t.Fatalf("expected exit message, got %d %q", messageType, data)
+	}
+	if msg.Type != terminalMessageExit || msg.ExitCode == nil || *msg.ExitCode != 3 {
+		t.Fatalf("unexpected exit message: %s", data)
+	}
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Fatalf("expected normal close, got %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	want := [][2]uint32{{80, 24}, {120, 40}}
+	if len(fake.resizes) != len(want) || fake.resizes[0] != want[0] || fake.resizes[1] != want[1] {
+		t.Fatalf("resizes = %v, want %v", fake.resizes, want)
+	}
+}
+
+func TestParseTerminalSize(t *testing.T) {
+	if cols, rows := parseTerminalSize("120", " 40 "); cols != 120 || rows != 40 {
+		t.Fatalf("got %dx%d, want 120x40", cols, rows)
+	}
+	if cols, rows := parseTerminalSize("120", ""); cols != 0 || rows != 0 {
+		t.Fatalf("got %dx%d, want 0x0", cols, rows)
+	}
+	if cols, rows := parseTerminalSize("100000", "40"); cols != 0 || rows != 0 {
+		t.Fatalf("got %dx%d, want 0x0 for out of range width", cols, rows)
+	}
+}
@@ -13,10 +13,12 @@ import (
 	"github.com/memohai/memoh/internal/accounts"
 	"github.com/memohai/memoh/internal/auth"
 	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/bundle"
 	"github.com/memohai/memoh/internal/channel"
 	"github.com/memohai/memoh/internal/channel/identities"
 	"github.com/memohai/memoh/internal/channel/route"
 	"github.com/memohai/memoh/internal/identity"
+	"github.com/memohai/memoh/internal/settings"
 )
 
 // UsersHandler manages user/account CRUD and bot operations via REST API.
@@ -29,6 +31,7 @@ type UsersHandler struct {
 	channelLifecycle       *channel.Lifecycle
 	channelManager         *channel.Manager
 	registry               *channel.Registry
+	templates              *bundle.Service
 	logger                 *slog.Logger
 }
 
@@ -37,6 +40,11 @@ type listMyIdentitiesResponse struct {
 	Items  []identities.ChannelIdentity `json:"items"`
 }
 
+// ListBotChannelConfigsResponse lists every channel config of a bot.
+type ListBotChannelConfigsResponse struct {
+	Items []channel.ChannelConfig `json:"items"`
+}
+
 // NewUsersHandler creates a UsersHandler with channel identity support.
 func NewUsersHandler(log *slog.Logger, service *accounts.Service, channelIdentityService *identities.Service, botService *bots.Service, routeService route.Service, channelStore *channel.Store, channelLifecycle *channel.Lifecycle, channelManager *channel.Manager, registry *channel.Registry) *UsersHandler {
 	if log == nil {
@@ -55,6 +63,11 @@ func NewUsersHandler(log *slog.Logger, service *accounts.Service, channelIdentit
 	}
 }
 
+// SetBotTemplates enables creating bots from templates.
+func (h *UsersHandler) SetBotTemplates(service *bundle.Service) {
+	h.templates = service
+}
+
 func (h *UsersHandler) Register(e *echo.Echo) {
 	userGroup := e.Group("/users")
 	userGroup.GET("/me", h.GetMe)
@@ -78,6 +91,7 @@ func (h *UsersHandler) Register(e *echo.Echo) {
 	botGroup.GET("/:id/members", h.ListBotMembers)
 	botGroup.PUT("/:id/members", h.UpsertBotMember)
 	botGroup.DELETE("/:id/members/:user_id", h.DeleteBotMember)
+	botGroup.GET("/:id/channels", h.ListBotChannelConfigs)
 	botGroup.GET("/:id/channel/:platform", h.GetBotChannelConfig)
 	botGroup.PUT("/:id/channel/:platform", h.UpsertBotChannelConfig)
 	botGroup.PATCH("/:id/channel/:platform/status", h.UpdateBotChannelStatus)
@@ -380,7 +394,7 @@ func (h *UsersHandler) CreateUser(c echo.Context) error {
 
 // CreateBot godoc
 // @Summary Create bot user
-// @Description Create a bot user owned by current user (or admin-specified owner)
+// @Description Create a bot user owned by current user (or admin-specified owner). With template_id, the bot template's files, skills, settings, MCP connections, schedules and subagents are applied; the bot is removed again if that fails.
 // @Tags bots
 // @Param payload body bots.CreateBotRequest true "Bot payload"
 // @Success 201 {object} bots.Bot
@@ -413,6 +427,18 @@ func (h *UsersHandler) CreateBot(c echo.Context) error {
 		ownerID = raw
 		ownerFromToken = false
 	}
+	templateID := strings.TrimSpace(req.TemplateID)
+	if templateID != "" {
+		if h.templates == nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "bot templates not configured")
+		}
+		if _, err := h.templates.GetTemplate(c.Request().Context(), templateID); err != nil {
+			if errors.Is(err, bundle.ErrTemplateNotFound) {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
 	if ownerFromToken {
 		if _, err := h.service.Get(c.Request().Context(), ownerID); err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
@@ -446,9 +472,36 @@ func (h *UsersHandler) CreateBot(c echo.Context) error {
 		}
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	if templateID != "" {
+		if _, err := h.templates.ApplyTemplate(c.Request().Context(), resp.ID, templateID); err != nil {
+			if deleteErr := h.botService.Delete(context.WithoutCancel(c.Request().Context()), resp.ID); deleteErr != nil {
+				h.logger.Warn("delete bot after failed template failed", slog.String("bot_id", resp.ID), slog.Any("error", deleteErr))
+			}
+			return templateApplyError(err)
+		}
+	}
 	return c.JSON(http.StatusCreated, resp)
 }
 
+// templateApplyError maps errors from applying a bot template, most of which
+// come from the template content rather than the server.
+func templateApplyError(err error) error {
+	switch {
+	case errors.Is(err, bundle.ErrTemplateNotFound),
+		errors.Is(err, bundle.ErrInvalidTemplate),
+		errors.Is(err, bundle.ErrInvalidBundle),
+		errors.Is(err, settings.ErrInvalidModelRef),
+		errors.Is(err, settings.ErrModelIDAmbiguous),
+		errors.Is(err, settings.ErrPersonalBotGuestAccessUnsupported),
+		errors.Is(err, settings.ErrInvalidCompaction),
+		errors.Is(err, settings.ErrInvalidResourceLimits),
+		errors.Is(err, settings.ErrInvalidNetworkPolicy),
+		errors.Is(err, settings.ErrInvalidSnapshotPolicy):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
+
 // ListBots godoc
 // @Summary List bots
 // @Description List bots accessible to current user (admin can specify owner_id)
@@ -764,12 +817,45 @@ func (h *UsersHandler) DeleteBotMember(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// ListBotChannelConfigs godoc
+// @Summary List bot channel configs
+// @Description List all channel configurations of a bot, including named configs of the same platform
+// @Tags bots
+// @Param id path string true "Bot ID"
+// @Success 200 {object} ListBotChannelConfigsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{id}/channels [get]
+func (h *UsersHandler) ListBotChannelConfigs(c echo.Context) error {
+	channelIdentityID, err := h.requireChannelIdentityID(c)
+	if err != nil {
+		return err
+	}
+	botID := strings.TrimSpace(c.Param("id"))
+	if botID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := h.authorizeBotAccess(c.Request().Context(), channelIdentityID, botID); err != nil {
+		return err
+	}
+	if h.channelStore == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "channel store not configured")
+	}
+	items, err := h.channelStore.ListConfigsByBot(c.Request().Context(), botID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, ListBotChannelConfigsResponse{Items: items})
+}
+
 // GetBotChannelConfig godoc
 // @Summary Get bot channel config
 // @Description Get bot channel configuration
 // @Tags bots
 // @Param id path string true "Bot ID"
 // @Param platform path string true "Channel platform"
+// @Param name query string false "Config name; empty selects the default config"
 // @Success 200 {object} channel.ChannelConfig
 // @Failure 400 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
@@ -792,10 +878,14 @@ func (h *UsersHandler) GetBotChannelConfig(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
+	name, err := channelConfigName(c)
+	if err != nil {
+		return err
+	}
 	if h.channelStore == nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "channel store not configured")
 	}
-	resp, err := h.channelStore.ResolveEffectiveConfig(c.Request().Context(), botID, channelType)
+	resp, err := h.channelStore.ResolveConfig(c.Request().Context(), botID, channelType, name)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			return echo.NewHTTPError(http.StatusNotFound, err.Error())
@@ -811,6 +901,7 @@ func (h *UsersHandler) GetBotChannelConfig(c echo.Context) error {
 // @Tags bots
 // @Param id path string true "Bot ID"
 // @Param platform path string true "Channel platform"
+// @Param name query string false "Config name; empty selects the default config"
 // @Param payload body channel.UpsertConfigRequest true "Channel config payload"
 // @Success 200 {object} channel.ChannelConfig
 // @Failure 400 {object} ErrorResponse
@@ -834,6 +925,10 @@ func (h *UsersHandler) UpsertBotChannelConfig(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
+	name, err := channelConfigName(c)
+	if err != nil {
+		return err
+	}
 	var req channel.UpsertConfigRequest
 	if err := c.Bind(&req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -844,7 +939,7 @@ func (h *UsersHandler) UpsertBotChannelConfig(c echo.Context) error {
 	if h.channelLifecycle == nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "channel lifecycle not configured")
 	}
-	resp, err := h.channelLifecycle.UpsertBotChannelConfig(c.Request().Context(), botID, channelType, req)
+	resp, err := h.channelLifecycle.UpsertBotChannelConfig(c.Request().Context(), botID, channelType, name, req)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if errors.Is(err, channel.ErrEnableChannelFailed) {
@@ -861,6 +956,7 @@ func (h *UsersHandler) UpsertBotChannelConfig(c echo.Context) error {
 // @Tags bots
 // @Param id path string true "Bot ID"
 // @Param platform path string true "Channel platform"
+// @Param name query string false "Config name; empty selects the default config"
 // @Param payload body channel.UpdateChannelStatusRequest true "Channel status payload"
 // @Success 200 {object} channel.ChannelConfig
 // @Failure 400 {object} ErrorResponse
@@ -884,6 +980,10 @@ func (h *UsersHandler) UpdateBotChannelStatus(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
+	name, err := channelConfigName(c)
+	if err != nil {
+		return err
+	}
 	var req channel.UpdateChannelStatusRequest
 	if err := c.Bind(&req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -891,7 +991,7 @@ func (h *UsersHandler) UpdateBotChannelStatus(c echo.Context) error {
 	if h.channelLifecycle == nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "channel lifecycle not configured")
 	}
-	resp, err := h.channelLifecycle.SetBotChannelStatus(c.Request().Context(), botID, channelType, req.Disabled)
+	resp, err := h.channelLifecycle.SetBotChannelStatus(c.Request().Context(), botID, channelType, name, req.Disabled)
 	if err != nil {
 		if errors.Is(err, channel.ErrChannelConfigNotFound) {
 			return echo.NewHTTPError(http.StatusNotFound, err.Error())
@@ -911,6 +1011,7 @@ func (h *UsersHandler) UpdateBotChannelStatus(c echo.Context) error {
 // @Tags bots
 // @Param id path string true "Bot ID"
 // @Param platform path string true "Channel platform"
+// @Param name query string false "Config name; empty selects the default config"
 // @Success 204 "No Content"
 // @Failure 400 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
@@ -932,10 +1033,14 @@ func (h *UsersHandler) DeleteBotChannelConfig(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
+	name, err := channelConfigName(c)
+	if err != nil {
+		return err
+	}
 	if h.channelLifecycle == nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "channel lifecycle not configured")
 	}
-	if err := h.channelLifecycle.DeleteBotChannelConfig(c.Request().Context(), botID, channelType); err != nil {
+	if err := h.channelLifecycle.DeleteBotChannelConfig(c.Request().Context(), botID, channelType, name); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 	return c.NoContent(http.StatusNoContent)
@@ -1034,14 +1139,24 @@ func (h *UsersHandler) SendBotMessageSession(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "message is required")
 	}
 	if err := h.channelManager.Send(c.Request().Context(), botID, channelType, channel.SendRequest{
-		Target:  route.ReplyTarget,
-		Message: req.Message,
+		ConfigID: route.ChannelConfigID,
+		Target:   route.ReplyTarget,
+		Message:  req.Message,
 	}); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// channelConfigName reads the optional config name query parameter.
+func channelConfigName(c echo.Context) (string, error) {
+	name, err := channel.NormalizeConfigName(c.QueryParam("name"))
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return name, nil
+}
+
 func (h *UsersHandler) authorizeBotAccess(ctx context.Context, channelIdentityID, botID string) (bots.Bot, error) {
 	return AuthorizeBotAccess(ctx, h.botService, h.service, channelIdentityID, botID, bots.AccessPolicy{AllowPublicMember: false})
 }
@@ -88,7 +88,7 @@ func (c *Checker) ListChecks(ctx context.Context, botID string) []healthcheck.Ch
 			channelType = "unknown"
 		}
 		checkID := buildCheckID(status.ConfigID, idx)
-		subtitle := buildSubtitle(channelType, status.ConfigID)
+		subtitle := buildSubtitle(channelType, status.ConfigName, status.ConfigID)
 		item := healthcheck.CheckResult{
 			ID:       checkID,
 			Type:     checkTypeChannelConnection,
@@ -102,6 +102,9 @@ func (c *Checker) ListChecks(ctx context.Context, botID string) []healthcheck.Ch
 				"running":      status.Running,
 			},
 		}
+		if status.ConfigName != "" {
+			item.Metadata["config_name"] = status.ConfigName
+		}
 		if status.UpdatedAt.Unix() > 0 {
 			item.Metadata["updated_at"] = status.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z")
 		}
@@ -125,7 +128,10 @@ func buildCheckID(configID string, idx int) string {
 	return fmt.Sprintf("%s.unknown_%d", checkTypeChannelConnection, idx+1)
 }
 
-func buildSubtitle(channelType, configID string) string {
+func buildSubtitle(channelType, configName, configID string) string {
+	if configName = strings.TrimSpace(configName); configName != "" {
+		return channelType + " (" + configName + ")"
+	}
 	configID = strings.TrimSpace(configID)
 	if configID == "" {
 		return channelType
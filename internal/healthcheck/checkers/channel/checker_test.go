@@ -30,6 +30,7 @@ func TestCheckerListChecks(t *testing.T) {
 		items: []channel.ConnectionStatus{
 			{
 				ConfigID:    "cfg-1",
+				ConfigName:  "work",
 				BotID:       "bot-1",
 				ChannelType: channel.ChannelType("telegram"),
 				Running:     true,
@@ -59,6 +60,9 @@ func TestCheckerListChecks(t *testing.T) {
 			if item.Status != "ok" {
 				t.Fatalf("expected ok for cfg-1, got %s", item.Status)
 			}
+			if item.Subtitle != "telegram (work)" {
+				t.Fatalf("expected config name in subtitle, got %s", item.Subtitle)
+			}
 		}
 		if item.ID == "channel.connection.cfg-2" {
 			errFound = true
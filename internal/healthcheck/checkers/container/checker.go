@@ -0,0 +1,171 @@
+package containerchecker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/healthcheck"
+	"github.com/memohai/memoh/internal/mcp"
+)
+
+const (
+	checkTypeContainerResources = "container.resources"
+	titleKeyContainerResources  = "bots.checks.titles.containerResources"
+
+	// warnRatio is the share of a limit above which usage is reported as a
+	// warning.
+	warnRatio = 0.9
+)
+
+// ResourceObserver reads bot container resource usage.
+type ResourceObserver interface {
+	ResourceReport(ctx context.Context, botID string) (mcp.ResourceReport, error)
+}
+
+// Checker evaluates container resource usage checks.
+type Checker struct {
+	logger   *slog.Logger
+	observer ResourceObserver
+}
+
+// NewChecker creates a container resource health checker.
+func NewChecker(log *slog.Logger, observer ResourceObserver) *Checker {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Checker{
+		logger:   log.With(slog.String("checker", "healthcheck_container")),
+		observer: observer,
+	}
+}
+
+// ListChecks reports the container resource usage of a bot against its
+// configured limits.
+func (c *Checker) ListChecks(ctx context.Context, botID string) []healthcheck.CheckResult {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	botID = strings.TrimSpace(botID)
+	if botID == "" {
+		return []healthcheck.CheckResult{}
+	}
+	item := healthcheck.CheckResult{
+		ID:       checkTypeContainerResources,
+		Type:     checkTypeContainerResources,
+		TitleKey: titleKeyContainerResources,
+	}
+	if c.observer == nil {
+		item.Status = healthcheck.StatusWarn
+		item.Summary = "Container checker service is not available."
+		item.Detail = "resource observer is nil"
+		return []healthcheck.CheckResult{item}
+	}
+
+	report, err := c.observer.ResourceReport(ctx, botID)
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Warn(
+				"container healthcheck read usage failed",
+				slog.String("bot_id", botID),
+				slog.Any("error", err),
+			)
+		}
+		item.Status = healthcheck.StatusError
+		item.Summary = "Failed to read container resource usage."
+		item.Detail = err.Error()
+		return []healthcheck.CheckResult{item}
+	}
+
+	memoryLimit := report.Usage.MemoryLimit
+	if memoryLimit == 0 && report.Limits.MemoryBytes > 0 {
+		memoryLimit = uint64(report.Limits.MemoryBytes)
+	}
+	pidsLimit := report.Usage.PidsLimit
+	if pidsLimit == 0 && report.Limits.PidsLimit > 0 {
+		pidsLimit = uint64(report.Limits.PidsLimit)
+	}
+	item.Metadata = map[string]any{
+		"running":          report.Running,
+		"cpu_usage_ns":     report.Usage.CPUUsageNanos,
+		"cpu_shares":       report.Limits.CPUShares,
+		"cpu_quota":        report.Limits.CPUQuota,
+		"cpu_period":       report.Limits.CPUPeriod,
+		"memory_bytes":     report.Usage.MemoryBytes,
+		"memory_limit":     memoryLimit,
+		"pids":             report.Usage.Pids,
+		"pids_limit":       pidsLimit,
+		"data_bytes":       report.DataBytes,
+		"data_quota_bytes": report.Limits.DataQuotaBytes,
+	}
+	if report.Suspended != "" {
+		item.Metadata["suspended"] = report.Suspended
+	}
+	if !report.LastActivity.IsZero() {
+		item.Metadata["last_activity"] = report.LastActivity.UTC().Format(time.RFC3339)
+	}
+
+	var (
+		errs  []string
+		warns []string
+	)
+	if quota := report.Limits.DataQuotaBytes; quota > 0 {
+		switch {
+		case report.DataBytes > quota:
+			errs = append(errs, fmt.Sprintf("data directory uses %s of its %s quota", formatBytes(uint64(report.DataBytes)), formatBytes(uint64(quota))))
+		case nearLimit(uint64(report.DataBytes), uint64(quota)):
+			warns = append(warns, fmt.Sprintf("data directory uses %s of its %s quota", formatBytes(uint64(report.DataBytes)), formatBytes(uint64(quota))))
+		}
+	}
+	if report.Running {
+		if nearLimit(report.Usage.MemoryBytes, memoryLimit) {
+			warns = append(warns, fmt.Sprintf("memory uses %s of its %s limit", formatBytes(report.Usage.MemoryBytes), formatBytes(memoryLimit)))
+		}
+		if nearLimit(report.Usage.Pids, pidsLimit) {
+			warns = append(warns, fmt.Sprintf("%d of %d processes in use", report.Usage.Pids, pidsLimit))
+		}
+	}
+
+	switch {
+	case len(errs) > 0:
+		item.Status = healthcheck.StatusError
+		item.Summary = "Container exceeds its resource limits."
+		item.Detail = strings.Join(append(errs, warns...), "; ")
+	case len(warns) > 0:
+		item.Status = healthcheck.StatusWarn
+		item.Summary = "Container is close to its resource limits."
+		item.Detail = strings.Join(warns, "; ")
+	case report.Suspended == mcp.IdleSuspendPause:
+		item.Status = healthcheck.StatusOK
+		item.Summary = "Container is paused while idle and resumes on demand."
+	case report.Suspended == mcp.IdleSuspendStop:
+		item.Status = healthcheck.StatusOK
+		item.Summary = "Container is stopped while idle and restarts on demand."
+	case !report.Running:
+		item.Status = healthcheck.StatusUnknown
+		item.Summary = "Container is not running."
+	default:
+		item.Status = healthcheck.StatusOK
+		item.Summary = "Container resource usage is within limits."
+	}
+	return []healthcheck.CheckResult{item}
+}
+
+func nearLimit(used, limit uint64) bool {
+	return limit > 0 && float64(used) >= float64(limit)*warnRatio
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,126 @@
+package containerchecker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	ctr "github.com/memohai/memoh/internal/containerd"
+	"github.com/memohai/memoh/internal/mcp"
+)
+
+type fakeResourceObserver struct {
+	report mcp.ResourceReport
+	err    error
+}
+
+func (f *fakeResourceObserver) ResourceReport(ctx context.Context, botID string) (mcp.ResourceReport, error) {
+	if f.err != nil {
+		return mcp.ResourceReport{}, f.err
+	}
+	return f.report, nil
+}
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestCheckerWithinLimits(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(newTestLogger(), &fakeResourceObserver{
+		report: mcp.ResourceReport{
+			Running: true,
+			Limits:  ctr.ResourceLimits{MemoryBytes: 512 << 20, PidsLimit: 100},
+			Usage:   ctr.ResourceUsage{MemoryBytes: 100 << 20, MemoryLimit: 512 << 20, Pids: 10, PidsLimit: 100},
+		},
+	})
+
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 1 {
+		t.Fatalf("expected 1 check, got %d", len(items))
+	}
+	if items[0].Status != "ok" {
+		t.Fatalf("expected ok status, got %s", items[0].Status)
+	}
+	if items[0].Metadata["memory_limit"] != uint64(512<<20) {
+		t.Fatalf("unexpected memory limit metadata: %v", items[0].Metadata["memory_limit"])
+	}
+}
+
+func TestCheckerNearMemoryLimit(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(newTestLogger(), &fakeResourceObserver{
+		report: mcp.ResourceReport{
+			Running: true,
+			Limits:  ctr.ResourceLimits{MemoryBytes: 100 << 20},
+			Usage:   ctr.ResourceUsage{MemoryBytes: 95 << 20},
+		},
+	})
+
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 1 || items[0].Status != "warn" {
+		t.Fatalf("expected one warn check, got %+v", items)
+	}
+}
+
+func TestCheckerDataQuotaExceeded(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(newTestLogger(), &fakeResourceObserver{
+		report: mcp.ResourceReport{
+			Limits:    ctr.ResourceLimits{DataQuotaBytes: 10 << 20},
+			DataBytes: 11 << 20,
+		},
+	})
+
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 1 || items[0].Status != "error" {
+		t.Fatalf("expected one error check, got %+v", items)
+	}
+}
+
+func TestCheckerNotRunning(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(newTestLogger(), &fakeResourceObserver{})
+
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 1 || items[0].Status != "unknown" {
+		t.Fatalf("expected one unknown check, got %+v", items)
+	}
+}
+
+func TestCheckerSuspended(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(newTestLogger(), &fakeResourceObserver{report: mcp.ResourceReport{
+		Running:   true,
+		Suspended: mcp.IdleSuspendPause,
+	}})
+
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 1 || items[0].Status != "ok" {
+		t.Fatalf("expected one ok check, got %+v", items)
+	}
+	if items[0].Metadata["suspended"] != mcp.IdleSuspendPause {
+		t.Fatalf("expected suspended metadata, got %+v", items[0].Metadata)
+	}
+}
+
+func TestCheckerObserverError(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(newTestLogger(), &fakeResourceObserver{err: errors.New("boom")})
+
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 1 || items[0].Status != "error" {
+		t.Fatalf("expected one error check, got %+v", items)
+	}
+	if items[0].Detail != "boom" {
+		t.Fatalf("unexpected detail: %s", items[0].Detail)
+	}
+}
@@ -0,0 +1,314 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+
+	ctr "github.com/memohai/memoh/internal/containerd"
+)
+
+const (
+	// IdleSuspendPause freezes an idle container; its processes keep their
+	// memory and continue where they left off.
+	IdleSuspendPause = "pause"
+	// IdleSuspendStop stops an idle container's task, freeing its memory.
+	IdleSuspendStop = "stop"
+
+	idleCheckInterval = time.Minute
+)
+
+// BusyChecker reports whether a bot has work in its container that must not
+// be suspended, such as background jobs.
+type BusyChecker interface {
+	Busy(ctx context.Context, botID string) (bool, error)
+}
+
+// idleTracker records bot activity for idle suspension. It is guarded by
+// Manager.idleMu.
+type idleTracker struct {
+	// activity is the last tool call, inbound message or exec per bot.
+	activity map[string]time.Time
+	// holds counts open sessions, like terminals, that keep a bot awake.
+	holds map[string]int
+	// suspended maps bots suspended for inactivity to how they were
+	// suspended.
+	suspended map[string]string
+}
+
+func newIdleTracker() idleTracker {
+	return idleTracker{
+		activity:  map[string]time.Time{},
+		holds:     map[string]int{},
+		suspended: map[string]string{},
+	}
+}
+
+// candidates returns the bots idle for at least timeout. Bots seen for the
+// first time start their idle window now.
+func (t *idleTracker) candidates(botIDs []string, now time.Time, timeout time.Duration) []string {
+	var idle []string
+	for _, botID := range botIDs {
+		last, ok := t.activity[botID]
+		if !ok {
+			t.activity[botID] = now
+			continue
+		}
+		if t.holds[botID] > 0 || t.suspended[botID] != "" {
+			continue
+		}
+		if now.Sub(last) >= timeout {
+			idle = append(idle, botID)
+		}
+	}
+	return idle
+}
+
+// IdleTimeout returns how long a bot container may stay idle before it is
+// suspended, or 0 when idle suspension is disabled.
+func (m *Manager) IdleTimeout() time.Duration {
+	if m.cfg.IdleSuspendMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(m.cfg.IdleSuspendMinutes) * time.Minute
+}
+
+func (m *Manager) idleSuspendMode() string {
+	if strings.EqualFold(strings.TrimSpace(m.cfg.IdleSuspendMode), IdleSuspendStop) {
+		return IdleSuspendStop
+	}
+	return IdleSuspendPause
+}
+
+// SetBusyChecker registers a check that keeps busy bots from being suspended.
+func (m *Manager) SetBusyChecker(checker BusyChecker) {
+	m.busyChecker = checker
+}
+
+// TouchActivity restarts the idle window of a bot.
+func (m *Manager) TouchActivity(botID string) {
+	botID = strings.TrimSpace(botID)
+	if botID == "" {
+		return
+	}
+	m.idleMu.Lock()
+	m.idle.activity[botID] = time.Now()
+	m.idleMu.Unlock()
+}
+
+// HoldAwake keeps a bot from being suspended until the returned release
+// function is called.
+func (m *Manager) HoldAwake(botID string) func() {
+	m.idleMu.Lock()
+	m.idle.holds[botID]++
+	m.idleMu.Unlock()
+	return func() {
+		m.idleMu.Lock()
+		defer m.idleMu.Unlock()
+		if m.idle.holds[botID]--; m.idle.holds[botID] <= 0 {
+			delete(m.idle.holds, botID)
+		}
+		m.idle.activity[botID] = time.Now()
+	}
+}
+
+func (m *Manager) forgetIdle(botID string) {
+	m.idleMu.Lock()
+	defer m.idleMu.Unlock()
+	delete(m.idle.activity, botID)
+	delete(m.idle.holds, botID)
+	delete(m.idle.suspended, botID)
+}
+
+// IdleState reports how a bot is suspended ("" when it is not) and when it
+// was last active.
+func (m *Manager) IdleState(botID string) (string, time.Time) {
+	m.idleMu.Lock()
+	defer m.idleMu.Unlock()
+	return m.idle.suspended[botID], m.idle.activity[botID]
+}
+
+// Wake resumes a container suspended for inactivity. It is a no-op for
+// containers that were not suspended.
+func (m *Manager) Wake(ctx context.Context, botID string) error {
+	if mode, _ := m.IdleState(botID); mode == "" {
+		return nil
+	}
+	unlock := m.lockContainer(m.containerID(botID))
+	defer unlock()
+
+	// Another caller may have woken the container while we waited.
+	mode, _ := m.IdleState(botID)
+	if mode == "" {
+		return nil
+	}
+	if err := m.resumeTask(ctx, botID); err != nil {
+		return fmt.Errorf("wake container: %w", err)
+	}
+	m.idleMu.Lock()
+	delete(m.idle.suspended, botID)
+	m.idle.activity[botID] = time.Now()
+	m.idleMu.Unlock()
+	m.logger.Info("idle container woken", slog.String("bot_id", botID), slog.String("mode", mode))
+	return nil
+}
+
+// resumeTask brings the task back to running whatever state it is in, so a
+// container that was paused and then restarted elsewhere still wakes.
+func (m *Manager) resumeTask(ctx context.Context, botID string) error {
+	containerID := m.containerID(botID)
+	info, err := m.service.GetTaskInfo(ctx, containerID)
+	switch {
+	case err == nil && info.Status == ctr.TaskStatusRunning:
+		return nil
+	case err == nil && info.Status == ctr.TaskStatusPaused:
+		return m.service.ResumeContainer(ctx, containerID)
+	case err == nil:
+		if err := m.service.DeleteTask(ctx, containerID, &ctr.DeleteTaskOptions{Force: true}); err != nil {
+			m.logger.Warn("cleanup: delete task failed", slog.String("container_id", containerID), slog.Any("error", err))
+		}
+	case !errdefs.IsNotFound(err):
+		return err
+	}
+	return m.Start(ctx, botID)
+}
+
+// StartIdleSuspension suspends idle bot containers until ctx is done. It
+// does nothing when idle suspension is disabled.
+func (m *Manager) StartIdleSuspension(ctx context.Context) {
+	timeout := m.IdleTimeout()
+	if timeout <= 0 {
+		return
+	}
+	interval := min(idleCheckInterval, timeout)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := m.SuspendIdle(ctx, now); err != nil {
+					m.logger.Warn("idle suspension failed", slog.Any("error", err))
+				}
+			}
+		}
+	}()
+}
+
+// SuspendIdle suspends the running containers of bots idle for longer than
+// the idle timeout.
+func (m *Manager) SuspendIdle(ctx context.Context, now time.Time) error {
+	timeout := m.IdleTimeout()
+	if timeout <= 0 {
+		return nil
+	}
+	botIDs, err := m.ListBots(ctx)
+	if err != nil {
+		return err
+	}
+	m.idleMu.Lock()
+	idle := m.idle.candidates(botIDs, now, timeout)
+	m.idleMu.Unlock()
+	for _, botID := range idle {
+		if err := m.suspendIfIdle(ctx, botID, now.Add(-timeout)); err != nil {
+			m.logger.Warn("suspend idle container failed", slog.String("bot_id", botID), slog.Any("error", err))
+		}
+	}
+	return nil
+}
+
+// suspendIfIdle suspends a bot container unless it became active after
+// cutoff, is held awake, is not running or reports background work.
+func (m *Manager) suspendIfIdle(ctx context.Context, botID string, cutoff time.Time) error {
+	containerID := m.containerID(botID)
+	info, err := m.service.GetTaskInfo(ctx, containerID)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if info.Status != ctr.TaskStatusRunning {
+		return nil
+	}
+	if m.busyChecker != nil {
+		busy, err := m.busyChecker.Busy(ctx, botID)
+		if err != nil {
+			return err
+		}
+		if busy {
+			return nil
+		}
+	}
+
+	unlock := m.lockContainer(containerID)
+	defer unlock()
+	mode := m.idleSuspendMode()
+	// Marking the bot first makes callers arriving from here on wait in Wake
+	// for the container lock instead of using a container being suspended.
+	if !m.markSuspended(botID, cutoff, mode) {
+		return nil
+	}
+	if err := m.suspendTask(ctx, botID, &mode); err != nil {
+		m.idleMu.Lock()
+		delete(m.idle.suspended, botID)
+		m.idleMu.Unlock()
+		return err
+	}
+	m.idleMu.Lock()
+	m.idle.suspended[botID] = mode
+	m.idleMu.Unlock()
+	m.logger.Info("idle container suspended", slog.String("bot_id", botID), slog.String("mode", mode))
+	return nil
+}
+
+// markSuspended records the bot as suspended if it is still idle.
+func (m *Manager) markSuspended(botID string, cutoff time.Time, mode string) bool {
+	m.idleMu.Lock()
+	defer m.idleMu.Unlock()
+	if m.idle.holds[botID] > 0 || m.idle.suspended[botID] != "" || m.idle.activity[botID].After(cutoff) {
+		return false
+	}
+	m.idle.suspended[botID] = mode
+	return true
+}
+
+// suspendTask pauses or stops a bot's task. Backends that cannot pause fall
+// back to stopping, which is reflected in mode.
+func (m *Manager) suspendTask(ctx context.Context, botID string, mode *string) error {
+	if *mode == IdleSuspendPause {
+		err := m.service.PauseContainer(ctx, m.containerID(botID))
+		if !errors.Is(err, ctr.ErrNotSupported) {
+			return err
+		}
+		*mode = IdleSuspendStop
+	}
+	return m.stopTask(ctx, botID)
+}
+
+// stopTask stops and removes a bot's task together with its network, which
+// Start sets up again.
+func (m *Manager) stopTask(ctx context.Context, botID string) error {
+	containerID := m.containerID(botID)
+	if err := m.service.RemoveNetwork(ctx, ctr.NetworkSetupRequest{
+		ContainerID: containerID,
+		CNIBinDir:   m.cfg.CNIBinaryDir,
+		CNIConfDir:  m.cfg.CNIConfigDir,
+	}); err != nil {
+		m.logger.Warn("cleanup: remove network failed", slog.String("container_id", containerID), slog.Any("error", err))
+	}
+	if err := m.safeStopTask(ctx, containerID); err != nil {
+		return err
+	}
+	if err := m.service.DeleteTask(ctx, containerID, &ctr.DeleteTaskOptions{Force: true}); err != nil && !errdefs.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdleTrackerCandidates(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	timeout := 30 * time.Minute
+	tracker := newIdleTracker()
+	tracker.activity["idle"] = now.Add(-time.Hour)
+	tracker.activity["recent"] = now.Add(-time.Minute)
+	tracker.activity["held"] = now.Add(-time.Hour)
+	tracker.holds["held"] = 1
+	tracker.activity["suspended"] = now.Add(-time.Hour)
+	tracker.suspended["suspended"] = IdleSuspendPause
+
+	got := tracker.candidates([]string{"idle", "recent", "held", "suspended", "new"}, now, timeout)
+	if len(got) != 1 || got[0] != "idle" {
+		t.Fatalf("candidates = %v, want [idle]", got)
+	}
+	if !tracker.activity["new"].Equal(now) {
+		t.Fatalf("new bot activity = %v, want %v", tracker.activity["new"], now)
+	}
+	if got := tracker.candidates([]string{"new"}, now.Add(timeout), timeout); len(got) != 1 {
+		t.Fatalf("new bot not idle after timeout: %v", got)
+	}
+}
+
+func TestMarkSuspended(t *testing.T) {
+	m := &Manager{idle: newIdleTracker()}
+	cutoff := time.Now().Add(-time.Minute)
+	m.idle.activity["bot"] = cutoff.Add(-time.Second)
+
+	release := m.HoldAwake("bot")
+	if m.markSuspended("bot", cutoff, IdleSuspendPause) {
+		t.Fatal("held bot was marked suspended")
+	}
+	release()
+	if m.markSuspended("bot", cutoff, IdleSuspendPause) {
+		t.Fatal("releasing a hold must count as activity")
+	}
+
+	m.idle.activity["bot"] = cutoff.Add(-time.Second)
+	if !m.markSuspended("bot", cutoff, IdleSuspendStop) {
+		t.Fatal("idle bot was not marked suspended")
+	}
+	if mode, _ := m.IdleState("bot"); mode != IdleSuspendStop {
+		t.Fatalf("mode = %q, want %q", mode, IdleSuspendStop)
+	}
+	if m.markSuspended("bot", cutoff, IdleSuspendStop) {
+		t.Fatal("bot was marked suspended twice")
+	}
+}
+
+func TestIdleSuspendMode(t *testing.T) {
+	m := &Manager{}
+	if m.IdleTimeout() != 0 || m.idleSuspendMode() != IdleSuspendPause {
+		t.Fatalf("defaults: timeout %v, mode %q", m.IdleTimeout(), m.idleSuspendMode())
+	}
+	m.cfg.IdleSuspendMinutes = 15
+	m.cfg.IdleSuspendMode = " Stop "
+	if m.IdleTimeout() != 15*time.Minute || m.idleSuspendMode() != IdleSuspendStop {
+		t.Fatalf("configured: timeout %v, mode %q", m.IdleTimeout(), m.idleSuspendMode())
+	}
+}
@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+
+	ctr "github.com/memohai/memoh/internal/containerd"
+	"github.com/memohai/memoh/internal/db"
+	dbsqlc "github.com/memohai/memoh/internal/db/sqlc"
+)
+
+const (
+	pullAttempts     = 3
+	pullRetryBackoff = 2 * time.Second
+)
+
+// ErrInvalidImageRef is returned for image references that cannot be parsed.
+var ErrInvalidImageRef = errors.New("invalid image reference")
+
+// NormalizeImageRef validates an image reference and returns it in its
+// canonical form, e.g. "python:3.12" becomes "docker.io/library/python:3.12".
+func NormalizeImageRef(ref string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(strings.TrimSpace(ref))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidImageRef, err)
+	}
+	return reference.TagNameOnly(named).String(), nil
+}
+
+// DefaultImageRef returns the globally configured MCP image in canonical form.
+func (m *Manager) DefaultImageRef() string {
+	if ref, err := NormalizeImageRef(m.imageRef()); err == nil {
+		return ref
+	}
+	return m.imageRef()
+}
+
+// ImageRef returns the image of a bot's container, falling back to the global
+// MCP image when the bot has none configured.
+func (m *Manager) ImageRef(ctx context.Context, botID string) string {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return m.imageRef()
+	}
+	image, err := m.queries.GetBotContainerImage(ctx, pgBotID)
+	if err != nil || strings.TrimSpace(image) == "" {
+		return m.imageRef()
+	}
+	return image
+}
+
+// SetImageRef stores the image of a bot's container. An empty image restores
+// the global default. The running container is not touched.
+func (m *Manager) SetImageRef(ctx context.Context, botID, image string) error {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return err
+	}
+	if image == m.DefaultImageRef() {
+		image = ""
+	}
+	return m.queries.UpdateBotContainerImage(ctx, dbsqlc.UpdateBotContainerImageParams{
+		ID:             pgBotID,
+		ContainerImage: image,
+	})
+}
+
+// PullImage pulls an image, retrying transient failures. When every attempt
+// fails but the image is already present locally (e.g. the host is offline),
+// the local image is returned instead.
+func (m *Manager) PullImage(ctx context.Context, ref string, onProgress func(ctr.PullProgress)) (ctr.ImageInfo, error) {
+	var lastErr error
+	for attempt := 1; attempt <= pullAttempts; attempt++ {
+		info, err := m.service.PullImage(ctx, ref, &ctr.PullImageOptions{
+			Unpack:      true,
+			Snapshotter: m.cfg.Snapshotter,
+			OnProgress:  onProgress,
+		})
+		if err == nil {
+			return info, nil
+		}
+		lastErr = err
+		m.logger.Warn("pull image failed",
+			slog.String("image", ref),
+			slog.Int("attempt", attempt),
+			slog.Any("error", err),
+		)
+		if attempt == pullAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctr.ImageInfo{}, ctx.Err()
+		case <-time.After(time.Duration(attempt) * pullRetryBackoff):
+		}
+	}
+	if info, err := m.service.GetImage(ctx, ref); err == nil {
+		m.logger.Warn("pull image failed, using local copy", slog.String("image", ref), slog.Any("error", lastErr))
+		return info, nil
+	}
+	return ctr.ImageInfo{}, lastErr
+}
@@ -0,0 +1,29 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeImageRef(t *testing.T) {
+	cases := map[string]string{
+		"python:3.12":                    "docker.io/library/python:3.12",
+		" ghcr.io/memohai/mcp ":          "ghcr.io/memohai/mcp:latest",
+		"registry.local:5000/tools/ds:1": "registry.local:5000/tools/ds:1",
+	}
+	for input, want := range cases {
+		got, err := NormalizeImageRef(input)
+		if err != nil {
+			t.Fatalf("NormalizeImageRef(%q): %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("NormalizeImageRef(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	for _, input := range []string{"", "Invalid Ref", "library/Python"} {
+		if _, err := NormalizeImageRef(input); !errors.Is(err, ErrInvalidImageRef) {
+			t.Fatalf("NormalizeImageRef(%q) error = %v, want ErrInvalidImageRef", input, err)
+		}
+	}
+}
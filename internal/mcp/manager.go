@@ -55,6 +55,9 @@ type Manager struct {
 	logger          *slog.Logger
 	containerLockMu sync.Mutex
 	containerLocks  map[string]*sync.Mutex
+	idleMu          sync.Mutex
+	idle            idleTracker
+	busyChecker     BusyChecker
 }
 
 func NewManager(log *slog.Logger, service ctr.Service, cfg config.MCPConfig, namespace string, conn *pgxpool.Pool) *Manager {
@@ -69,6 +72,7 @@ func NewManager(log *slog.Logger, service ctr.Service, cfg config.MCPConfig, nam
 		queries:        dbsqlc.New(conn),
 		logger:         log.With(slog.String("component", "mcp")),
 		containerLocks: make(map[string]*sync.Mutex),
+		idle:           newIdleTracker(),
 		containerID: func(botID string) string {
 			return ContainerPrefix + botID
 		},
@@ -89,12 +93,7 @@ func (m *Manager) lockContainer(containerID string) func() {
 }
 
 func (m *Manager) Init(ctx context.Context) error {
-	image := m.imageRef()
-
-	_, err := m.service.PullImage(ctx, image, &ctr.PullImageOptions{
-		Unpack:      true,
-		Snapshotter: m.cfg.Snapshotter,
-	})
+	_, err := m.PullImage(ctx, m.imageRef(), nil)
 	return err
 }
 
@@ -110,7 +109,7 @@ func (m *Manager) EnsureBot(ctx context.Context, botID string) error {
 	}
 
 	dataMount := m.dataMount()
-	image := m.imageRef()
+	image := m.ImageRef(ctx, botID)
 	resolvPath, err := ctr.ResolveConfSource(dataDir)
 	if err != nil {
 		return err
@@ -133,6 +132,11 @@ func (m *Manager) EnsureBot(ctx context.Context, botID string) error {
 	tzMounts, tzEnv := ctr.TimezoneSpec()
 	mounts = append(mounts, tzMounts...)
 
+	limits, err := m.ResourceLimits(ctx, botID)
+	if err != nil {
+		return err
+	}
+
 	_, err = m.service.CreateContainer(ctx, ctr.CreateContainerRequest{
 		ID:          m.containerID(botID),
 		ImageRef:    image,
@@ -141,8 +145,9 @@ func (m *Manager) EnsureBot(ctx context.Context, botID string) error {
 			BotLabelKey: botID,
 		},
 		Spec: ctr.ContainerSpec{
-			Mounts: mounts,
-			Env:    tzEnv,
+			Mounts:    mounts,
+			Env:       tzEnv,
+			Resources: &limits,
 		},
 	})
 	if err == nil {
@@ -178,6 +183,13 @@ func (m *Manager) Start(ctx context.Context, botID string) error {
 	if err := m.EnsureBot(ctx, botID); err != nil {
 		return err
 	}
+	if err := m.ApplyResourceLimits(ctx, botID); err != nil {
+		return err
+	}
+	policy, err := m.NetworkPolicy(ctx, botID)
+	if err != nil {
+		return err
+	}
 
 	if err := m.service.StartContainer(ctx, m.containerID(botID), &ctr.StartTaskOptions{
 		UseStdio: false,
@@ -188,6 +200,7 @@ func (m *Manager) Start(ctx context.Context, botID string) error {
 		ContainerID: m.containerID(botID),
 		CNIBinDir:   m.cfg.CNIBinaryDir,
 		CNIConfDir:  m.cfg.CNIConfigDir,
+		Policy:      policy,
 	}); err != nil {
 		if stopErr := m.service.StopContainer(ctx, m.containerID(botID), &ctr.StopTaskOptions{Force: true}); stopErr != nil {
 			m.logger.Warn("cleanup: stop task failed", slog.String("container_id", m.containerID(botID)), slog.Any("error", stopErr))
@@ -211,6 +224,7 @@ func (m *Manager) Delete(ctx context.Context, botID string) error {
 	if err := validateBotID(botID); err != nil {
 		return err
 	}
+	m.forgetIdle(botID)
 
 	if err := m.service.RemoveNetwork(ctx, ctr.NetworkSetupRequest{
 		ContainerID: m.containerID(botID),
@@ -238,6 +252,11 @@ func (m *Manager) Exec(ctx context.Context, req ExecRequest) (*ExecResult, error
 		return nil, fmt.Errorf("db is not configured")
 	}
 
+	if err := m.Wake(ctx, req.BotID); err != nil {
+		return nil, err
+	}
+	m.TouchActivity(req.BotID)
+
 	startedAt := time.Now()
 	if _, err := m.CreateVersion(ctx, req.BotID); err != nil {
 		return nil, err
@@ -270,6 +289,8 @@ func (m *Manager) Exec(ctx context.Context, req ExecRequest) (*ExecResult, error
 // ExecWithCapture runs a command in the bot container and returns stdout, stderr and exit code.
 // Use this when the caller needs command output (e.g. MCP exec tool).
 // The container must already be running; use Start(botID) or the container/start API to start it.
+// A container suspended for inactivity is woken, but the call does not count as
+// activity, so housekeeping execs do not keep a bot awake.
 func (m *Manager) ExecWithCapture(ctx context.Context, req ExecRequest) (*ExecWithCaptureResult, error) {
 	if err := validateBotID(req.BotID); err != nil {
 		return nil, err
@@ -280,6 +301,9 @@ func (m *Manager) ExecWithCapture(ctx context.Context, req ExecRequest) (*ExecWi
 	if m.queries == nil {
 		return nil, fmt.Errorf("db is not configured")
 	}
+	if err := m.Wake(ctx, req.BotID); err != nil {
+		return nil, err
+	}
 	return m.execWithCaptureContainerd(ctx, req)
 }
 
@@ -0,0 +1,22 @@
+package mcp
+
+import (
+	"context"
+
+	ctr "github.com/memohai/memoh/internal/containerd"
+	"github.com/memohai/memoh/internal/db"
+)
+
+// NetworkPolicy returns the container network policy configured in the bot
+// settings.
+func (m *Manager) NetworkPolicy(ctx context.Context, botID string) (ctr.NetworkPolicy, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return ctr.NetworkPolicy{}, err
+	}
+	row, err := m.queries.GetBotNetworkPolicy(ctx, pgBotID)
+	if err != nil {
+		return ctr.NetworkPolicy{}, err
+	}
+	return ctr.NetworkPolicy{Mode: row.NetworkMode, Allow: row.NetworkAllowlist}, nil
+}
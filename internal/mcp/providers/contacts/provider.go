@@ -84,6 +84,9 @@ func (p *Executor) CallTool(ctx context.Context, session mcpgw.ToolSessionContex
 			"conversation_id":   r.ConversationID,
 			"last_active":       r.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 		}
+		if r.ChannelConfigID != "" {
+			entry["config_id"] = r.ChannelConfigID
+		}
 		if len(r.Metadata) > 0 {
 			if v, ok := r.Metadata["conversation_name"].(string); ok && v != "" {
 				entry["display_name"] = v
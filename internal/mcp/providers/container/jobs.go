@@ -0,0 +1,313 @@
+package container
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	mcpgw "github.com/memohai/memoh/internal/mcp"
+)
+
+// Background jobs live in the container under jobsRoot/<id>/:
+//
+//	run.sh       the user command
+//	wrap.sh      runs run.sh, capturing output and exit code
+//	pid          pid of the wrapper (a session leader when setsid exists)
+//	output       combined stdout and stderr
+//	exit_code    written when the command exits
+//	started_at   unix seconds
+//	killed       present once job_kill was called
+const (
+	jobsRoot = "/tmp/memoh-jobs"
+
+	JobStateRunning = "running"
+	JobStateExited  = "exited"
+	JobStateKilled  = "killed"
+	// JobStateLost means the job neither runs nor recorded an exit code, e.g.
+	// after a container restart.
+	JobStateLost = "lost"
+
+	defaultJobOutputLimit = 16 * 1024
+	maxJobOutputLimit     = 64 * 1024
+)
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+
+	jobIDPattern = regexp.MustCompile(`^[0-9a-f]{16}$`)
+)
+
+// JobInfo describes a background job.
+type JobInfo struct {
+	ID         string
+	PID        int
+	State      string
+	ExitCode   *int
+	StartedAt  time.Time
+	OutputSize int64
+	Command    string
+}
+
+// JobOutput is a byte range of a job's combined output.
+type JobOutput struct {
+	Output     string
+	Offset     int64
+	NextOffset int64
+	Size       int64
+}
+
+// jobInfoFunc prints one job as "id|pid|state|exit_code|started_at|size|command_b64".
+const jobInfoFunc = `job_info() {
+  d="$1"; id=${d##*/}
+  pid=$(cat "$d/pid" 2>/dev/null)
+  code=
+  if [ -f "$d/exit_code" ]; then state=exited; code=$(cat "$d/exit_code")
+  elif [ -n "$pid" ] && kill -0 "$pid" 2>/dev/null && [ "$(sed 's/.*) //' "/proc/$pid/stat" 2>/dev/null | cut -c1)" != Z ]; then state=running
+  else state=lost; fi
+  if [ -f "$d/killed" ] && [ "$state" != running ]; then state=killed; fi
+  size=$(wc -c < "$d/output" 2>/dev/null || echo 0)
+  started=$(cat "$d/started_at" 2>/dev/null)
+  cmd=$(base64 < "$d/run.sh" 2>/dev/null | tr -d '\n')
+  echo "$id|$pid|$state|$code|$started|$size|$cmd"
+}
+`
+
+func newJobID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func jobDir(id string) (string, error) {
+	id = strings.TrimSpace(id)
+	if !jobIDPattern.MatchString(id) {
+		return "", fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return jobsRoot + "/" + id, nil
+}
+
+func runJobScript(ctx context.Context, runner ExecRunner, botID, script string) (string, error) {
+	result, err := runner.ExecWithCapture(ctx, mcpgw.ExecRequest{
+		BotID:   botID,
+		Command: []string{shellCommandName, shellCommandFlag, script},
+	})
+	if err != nil {
+		return "", err
+	}
+	if result.ExitCode != 0 {
+		msg := strings.TrimSpace(result.Stderr)
+		if strings.Contains(msg, "no such job") {
+			return "", ErrJobNotFound
+		}
+		if msg == "" {
+			msg = fmt.Sprintf("job script exited with code %d", result.ExitCode)
+		}
+		return "", errors.New(msg)
+	}
+	return result.Stdout, nil
+}
+
+func requireJobScript(dir string) string {
+	return fmt.Sprintf("[ -d %s ] || { echo 'no such job' >&2; exit 1; }\n", dir)
+}
+
+// ExecStartJob starts command in the background inside the container and
+// returns as soon as it is launched.
+func ExecStartJob(ctx context.Context, runner ExecRunner, botID, workDir, command string) (JobInfo, error) {
+	id, err := newJobID()
+	if err != nil {
+		return JobInfo{}, err
+	}
+	dir, _ := jobDir(id)
+	wrapper := fmt.Sprintf("sh %[1]s/run.sh > %[1]s/output 2>&1 < /dev/null\necho $? > %[1]s/exit_code.tmp && mv %[1]s/exit_code.tmp %[1]s/exit_code\n", dir)
+	if workDir != "" {
+		wrapper = "cd " + ShellQuote(workDir) + " || exit 1\n" + wrapper
+	}
+	script := fmt.Sprintf(`mkdir -p %[1]s && cd %[1]s || exit 1
+echo %[2]s | base64 -d > run.sh && echo %[3]s | base64 -d > wrap.sh && date +%%s > started_at && : > output || exit 1
+if command -v setsid >/dev/null 2>&1; then setsid sh wrap.sh > /dev/null 2>&1 < /dev/null &
+else nohup sh wrap.sh > /dev/null 2>&1 < /dev/null &
+fi
+echo $! > pid
+%[4]sjob_info %[1]s
+`, dir, ShellQuote(base64.StdEncoding.EncodeToString([]byte(command))), ShellQuote(base64.StdEncoding.EncodeToString([]byte(wrapper))), jobInfoFunc)
+	out, err := runJobScript(ctx, runner, botID, script)
+	if err != nil {
+		return JobInfo{}, err
+	}
+	jobs := parseJobInfoOutput(out)
+	if len(jobs) == 0 {
+		return JobInfo{}, fmt.Errorf("start job: unexpected output %q", strings.TrimSpace(out))
+	}
+	return jobs[0], nil
+}
+
+// ExecJobStatus returns the state of a background job.
+func ExecJobStatus(ctx context.Context, runner ExecRunner, botID, jobID string) (JobInfo, error) {
+	dir, err := jobDir(jobID)
+	if err != nil {
+		return JobInfo{}, err
+	}
+	out, err := runJobScript(ctx, runner, botID, requireJobScript(dir)+jobInfoFunc+"job_info "+dir+"\n")
+	if err != nil {
+		return JobInfo{}, err
+	}
+	jobs := parseJobInfoOutput(out)
+	if len(jobs) == 0 {
+		return JobInfo{}, ErrJobNotFound
+	}
+	return jobs[0], nil
+}
+
+// ExecListJobs returns all background jobs of the container, oldest first.
+func ExecListJobs(ctx context.Context, runner ExecRunner, botID string) ([]JobInfo, error) {
+	script := jobInfoFunc + fmt.Sprintf(`for d in %s/*; do [ -d "$d" ] && job_info "$d"; done; true
+`, jobsRoot)
+	out, err := runJobScript(ctx, runner, botID, script)
+	if err != nil {
+		return nil, err
+	}
+	jobs := parseJobInfoOutput(out)
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].StartedAt.Before(jobs[j].StartedAt) })
+	return jobs, nil
+}
+
+// Busy reports whether the bot has running background jobs, which idle
+// suspension must not freeze.
+func (p *Executor) Busy(ctx context.Context, botID string) (bool, error) {
+	jobs, err := ExecListJobs(ctx, p.execRunner, botID)
+	if err != nil {
+		return false, err
+	}
+	for _, job := range jobs {
+		if job.State == JobStateRunning {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ExecJobOutput reads up to limit bytes of a job's output starting at offset.
+// A negative offset reads the last limit bytes.
+func ExecJobOutput(ctx context.Context, runner ExecRunner, botID, jobID string, offset int64, limit int) (JobOutput, error) {
+	dir, err := jobDir(jobID)
+	if err != nil {
+		return JobOutput{}, err
+	}
+	if limit <= 0 {
+		limit = defaultJobOutputLimit
+	}
+	limit = min(limit, maxJobOutputLimit)
+	start := strconv.FormatInt(offset, 10)
+	if offset < 0 {
+		start = fmt.Sprintf("$(( size > %[1]d ? size - %[1]d : 0 ))", limit)
+	}
+	script := requireJobScript(dir) + fmt.Sprintf(`size=$(wc -c < %[1]s/output)
+off=%[2]s
+echo "$size $off"
+tail -c +$((off + 1)) %[1]s/output | head -c %[3]d
+`, dir, start, limit)
+	out, err := runJobScript(ctx, runner, botID, script)
+	if err != nil {
+		return JobOutput{}, err
+	}
+	return parseJobOutput(out)
+}
+
+// ExecKillJob sends signal (e.g. TERM or KILL) to a background job and its
+// process group.
+func ExecKillJob(ctx context.Context, runner ExecRunner, botID, jobID, signal string) error {
+	dir, err := jobDir(jobID)
+	if err != nil {
+		return err
+	}
+	signal = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(signal)), "SIG")
+	switch signal {
+	case "":
+		signal = "TERM"
+	case "TERM", "KILL", "INT", "HUP":
+	default:
+		return fmt.Errorf("unsupported signal: %s", signal)
+	}
+	script := requireJobScript(dir) + fmt.Sprintf(`[ -f %[1]s/exit_code ] && exit 0
+pid=$(cat %[1]s/pid) || exit 1
+touch %[1]s/killed
+kill -%[2]s -"$pid" 2>/dev/null || kill -%[2]s "$pid" 2>/dev/null || true
+`, dir, signal)
+	_, err = runJobScript(ctx, runner, botID, script)
+	return err
+}
+
+func parseJobInfoOutput(output string) []JobInfo {
+	var jobs []JobInfo
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), "|", 7)
+		if len(parts) != 7 || !jobIDPattern.MatchString(parts[0]) {
+			continue
+		}
+		job := JobInfo{ID: parts[0], State: parts[2]}
+		job.PID, _ = strconv.Atoi(parts[1])
+		if code, err := strconv.Atoi(parts[3]); err == nil {
+			job.ExitCode = &code
+		}
+		if started, err := strconv.ParseInt(parts[4], 10, 64); err == nil {
+			job.StartedAt = time.Unix(started, 0).UTC()
+		}
+		job.OutputSize, _ = strconv.ParseInt(parts[5], 10, 64)
+		if command, err := base64.StdEncoding.DecodeString(parts[6]); err == nil {
+			job.Command = string(command)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+func parseJobOutput(output string) (JobOutput, error) {
+	header, body, _ := strings.Cut(output, "\n")
+	fields := strings.Fields(header)
+	if len(fields) != 2 {
+		return JobOutput{}, fmt.Errorf("read job output: unexpected header %q", header)
+	}
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return JobOutput{}, fmt.Errorf("read job output: %w", err)
+	}
+	offset, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return JobOutput{}, fmt.Errorf("read job output: %w", err)
+	}
+	offset = min(offset, size)
+	return JobOutput{
+		Output:     strings.ToValidUTF8(body, "�"),
+		Offset:     offset,
+		NextOffset: offset + int64(len(body)),
+		Size:       size,
+	}, nil
+}
+
+func jobInfoMap(job JobInfo) map[string]any {
+	result := map[string]any{
+		"job_id":      job.ID,
+		"pid":         job.PID,
+		"state":       job.State,
+		"command":     job.Command,
+		"output_size": job.OutputSize,
+	}
+	if !job.StartedAt.IsZero() {
+		result["started_at"] = job.StartedAt.Format(time.RFC3339)
+	}
+	if job.ExitCode != nil {
+		result["exit_code"] = *job.ExitCode
+	}
+	return result
+}
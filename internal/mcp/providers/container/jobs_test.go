@@ -0,0 +1,131 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	mcpgw "github.com/memohai/memoh/internal/mcp"
+)
+
+func TestParseJobInfoOutput(t *testing.T) {
+	output := "0123456789abcdef|42|exited|3|1700000000|128|ZWNobyBoaQ==\n" +
+		"fedcba9876543210|43|running||1700000100|0|c2xlZXAgMTA=\n" +
+		"garbage line\n"
+	jobs := parseJobInfoOutput(output)
+	if len(jobs) != 2 {
+		t.Fatalf("got %d jobs, want 2", len(jobs))
+	}
+	first := jobs[0]
+	if first.ID != "0123456789abcdef" || first.PID != 42 || first.State != JobStateExited {
+		t.Errorf("unexpected job: %+v", first)
+	}
+	if first.ExitCode == nil || *first.ExitCode != 3 {
+		t.Errorf("exit code = %v, want 3", first.ExitCode)
+	}
+	if first.OutputSize != 128 || first.Command != "echo hi" || first.StartedAt.Unix() != 1700000000 {
+		t.Errorf("unexpected job: %+v", first)
+	}
+	if jobs[1].State != JobStateRunning || jobs[1].ExitCode != nil {
+		t.Errorf("unexpected job: %+v", jobs[1])
+	}
+}
+
+func TestParseJobOutput(t *testing.T) {
+	out, err := parseJobOutput("30 20\nine 3\nerr\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Output != "ine 3\nerr\n" || out.Offset != 20 || out.NextOffset != 30 || out.Size != 30 {
+		t.Errorf("unexpected output: %+v", out)
+	}
+
+	// Offsets past the end are clamped to the output size.
+	out, err = parseJobOutput("10 50\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Offset != 10 || out.NextOffset != 10 || out.Output != "" {
+		t.Errorf("unexpected output: %+v", out)
+	}
+
+	if _, err := parseJobOutput("oops"); err == nil {
+		t.Error("expected error for malformed header")
+	}
+}
+
+func TestExecJobStatus_InvalidID(t *testing.T) {
+	runner := &fakeExecRunner{result: &mcpgw.ExecWithCaptureResult{}}
+	_, err := ExecJobStatus(context.Background(), runner, "bot1", "../../etc")
+	if !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("err = %v, want ErrJobNotFound", err)
+	}
+	if len(runner.lastReq.Command) != 0 {
+		t.Error("invalid job id must not reach the container")
+	}
+}
+
+func TestExecJobStatus_Missing(t *testing.T) {
+	runner := &fakeExecRunner{result: &mcpgw.ExecWithCaptureResult{Stderr: "no such job\n", ExitCode: 1}}
+	_, err := ExecJobStatus(context.Background(), runner, "bot1", "0123456789abcdef")
+	if !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("err = %v, want ErrJobNotFound", err)
+	}
+}
+
+func TestExecKillJob_Signal(t *testing.T) {
+	runner := &fakeExecRunner{result: &mcpgw.ExecWithCaptureResult{}}
+	ctx := context.Background()
+	if err := ExecKillJob(ctx, runner, "bot1", "0123456789abcdef", "sigkill"); err != nil {
+		t.Fatal(err)
+	}
+	if script := runner.lastReq.Command[2]; !strings.Contains(script, `kill -KILL -"$pid"`) {
+		t.Errorf("script does not kill the process group:\n%s", script)
+	}
+	if err := ExecKillJob(ctx, runner, "bot1", "0123456789abcdef", "STOP; rm -rf /"); err == nil {
+		t.Error("expected error for unsupported signal")
+	}
+}
+
+func TestExecutor_CallTool_ExecBackground(t *testing.T) {
+	runner := &fakeExecRunner{}
+	runner.handler = func(req mcpgw.ExecRequest) (*mcpgw.ExecWithCaptureResult, error) {
+		script := req.Command[2]
+		if !strings.Contains(script, "setsid sh wrap.sh") {
+			t.Errorf("job is not started in its own session:\n%s", script)
+		}
+		return &mcpgw.ExecWithCaptureResult{
+			Stdout: "0123456789abcdef|42|running||1700000000|0|bWFrZQ==\n",
+		}, nil
+	}
+	exec := NewExecutor(nil, runner, "/data")
+	session := mcpgw.ToolSessionContext{BotID: "bot1"}
+	result, err := exec.CallTool(context.Background(), session, toolExec, map[string]any{"command": "make", "background": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mcpgw.PayloadError(result); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := result["structuredContent"].(map[string]any)
+	if content["job_id"] != "0123456789abcdef" || content["state"] != JobStateRunning || content["command"] != "make" {
+		t.Errorf("unexpected result: %v", content)
+	}
+}
+
+func TestExecutor_Busy(t *testing.T) {
+	runner := &fakeExecRunner{result: &mcpgw.ExecWithCaptureResult{
+		Stdout: "0123456789abcdef|42|exited|0|1700000000|0|bWFrZQ==\n",
+	}}
+	exec := NewExecutor(nil, runner, "/data")
+	busy, err := exec.Busy(context.Background(), "bot1")
+	if err != nil || busy {
+		t.Fatalf("busy = %v, %v; want false", busy, err)
+	}
+	runner.result.Stdout += "fedcba9876543210|43|running||1700000100|0|c2xlZXAgMTA=\n"
+	busy, err = exec.Busy(context.Background(), "bot1")
+	if err != nil || !busy {
+		t.Fatalf("busy = %v, %v; want true", busy, err)
+	}
+}
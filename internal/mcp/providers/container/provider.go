@@ -16,6 +16,11 @@ const (
 	toolEdit  = "edit"
 	toolExec  = "exec"
 
+	toolJobStatus = "job_status"
+	toolJobOutput = "job_output"
+	toolJobKill   = "job_kill"
+	toolJobList   = "job_list"
+
 	defaultExecWorkDir = "/data"
 	shellCommandName   = "/bin/sh"
 	shellCommandFlag   = "-c"
@@ -26,11 +31,17 @@ type ExecRunner interface {
 	ExecWithCapture(ctx context.Context, req mcpgw.ExecRequest) (*mcpgw.ExecWithCaptureResult, error)
 }
 
-// Executor provides filesystem and exec tools (read, write, list, edit, exec) that
-// operate inside the bot container via ExecRunner. All I/O goes through the container
+// QuotaChecker rejects writes that would grow the bot data directory past its
+// quota. It is implemented by mcp.Manager.
+type QuotaChecker interface {
+	CheckDataQuota(ctx context.Context, botID string, delta int64) error
+}
+
+// Executor provides filesystem, exec and background job tools that operate inside the bot container via ExecRunner. All I/O goes through the container
 // sandbox — no direct host filesystem access.
 type Executor struct {
 	execRunner  ExecRunner
+	quota       QuotaChecker
 	execWorkDir string
 	logger      *slog.Logger
 }
@@ -53,7 +64,21 @@ func NewExecutor(log *slog.Logger, execRunner ExecRunner, execWorkDir string) *E
 	}
 }
 
-// ListTools returns read, write, list, edit, and exec tool descriptors.
+// SetQuotaChecker enables data quota checks for the write and edit tools.
+func (p *Executor) SetQuotaChecker(checker QuotaChecker) {
+	p.quota = checker
+}
+
+// checkQuota reports whether a write growing the data directory by delta
+// bytes is allowed.
+func (p *Executor) checkQuota(ctx context.Context, botID string, delta int64) error {
+	if p.quota == nil {
+		return nil
+	}
+	return p.quota.CheckDataQuota(ctx, botID, delta)
+}
+
+// ListTools returns read, write, list, edit, exec and job tool descriptors.
 func (p *Executor) ListTools(ctx context.Context, session mcpgw.ToolSessionContext) ([]mcpgw.ToolDescriptor, error) {
 	wd := p.execWorkDir
 	if wd == "" {
@@ -110,7 +135,7 @@ func (p *Executor) ListTools(ctx context.Context, session mcpgw.ToolSessionConte
 		},
 		{
 			Name:        toolExec,
-			Description: fmt.Sprintf("Execute a command in the bot container. Runs in the bot's data directory (%s) by default.", wd),
+			Description: fmt.Sprintf("Execute a command in the bot container. Runs in the bot's data directory (%s) by default. Set background to start long-running commands (builds, servers) as a job and poll it with job_status and job_output.", wd),
 			InputSchema: map[string]any{
 				"type": "object",
 				"properties": map[string]any{
@@ -122,10 +147,58 @@ func (p *Executor) ListTools(ctx context.Context, session mcpgw.ToolSessionConte
 						"type":        "string",
 						"description": fmt.Sprintf("Working directory inside the container (default: %s)", wd),
 					},
+					"background": map[string]any{
+						"type":        "boolean",
+						"description": "Run the command as a background job and return its job_id immediately",
+					},
 				},
 				"required": []string{"command"},
 			},
 		},
+		{
+			Name:        toolJobStatus,
+			Description: "Get the state (running, exited, killed or lost) and exit code of a background job.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"job_id": map[string]any{"type": "string", "description": "job id returned by exec"},
+				},
+				"required": []string{"job_id"},
+			},
+		},
+		{
+			Name:        toolJobOutput,
+			Description: "Read the combined stdout and stderr of a background job. Without offset the tail is returned; pass next_offset back as offset to follow new output.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"job_id": map[string]any{"type": "string", "description": "job id returned by exec"},
+					"offset": map[string]any{"type": "integer", "description": "byte offset to read from (default: tail)"},
+					"limit":  map[string]any{"type": "integer", "description": fmt.Sprintf("max bytes to return (default: %d, max: %d)", defaultJobOutputLimit, maxJobOutputLimit)},
+				},
+				"required": []string{"job_id"},
+			},
+		},
+		{
+			Name:        toolJobKill,
+			Description: "Stop a background job and its child processes.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"job_id": map[string]any{"type": "string", "description": "job id returned by exec"},
+					"signal": map[string]any{"type": "string", "description": "TERM (default), INT, HUP or KILL"},
+				},
+				"required": []string{"job_id"},
+			},
+		},
+		{
+			Name:        toolJobList,
+			Description: "List background jobs in the bot container.",
+			InputSchema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{},
+			},
+		},
 	}, nil
 }
 
@@ -176,6 +249,11 @@ func (p *Executor) CallTool(ctx context.Context, session mcpgw.ToolSessionContex
 		if filePath == "" {
 			return mcpgw.BuildToolErrorResult("path is required"), nil
 		}
+		// The size of a replaced file is not known here, so the full
+		// content counts against the quota.
+		if err := p.checkQuota(ctx, botID, int64(len(content))); err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		}
 		if err := ExecWrite(ctx, p.execRunner, botID, p.execWorkDir, filePath, content); err != nil {
 			return mcpgw.BuildToolErrorResult(err.Error()), nil
 		}
@@ -220,6 +298,9 @@ func (p *Executor) CallTool(ctx context.Context, session mcpgw.ToolSessionContex
 		if err != nil {
 			return mcpgw.BuildToolErrorResult(err.Error()), nil
 		}
+		if err := p.checkQuota(ctx, botID, int64(len(updated)-len(raw))); err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		}
 		// Step 3: write back via exec
 		if err := ExecWrite(ctx, p.execRunner, botID, p.execWorkDir, filePath, updated); err != nil {
 			return mcpgw.BuildToolErrorResult(err.Error()), nil
@@ -235,6 +316,14 @@ func (p *Executor) CallTool(ctx context.Context, session mcpgw.ToolSessionContex
 		if workDir == "" {
 			workDir = p.execWorkDir
 		}
+		if background, _, _ := mcpgw.BoolArg(arguments, "background"); background {
+			job, err := ExecStartJob(ctx, p.execRunner, botID, workDir, command)
+			if err != nil {
+				p.logger.Warn("start job failed", slog.String("bot_id", botID), slog.String("command", command), slog.Any("error", err))
+				return mcpgw.BuildToolErrorResult(err.Error()), nil
+			}
+			return mcpgw.BuildToolSuccessResult(jobInfoMap(job)), nil
+		}
 		wrappedCmd := command
 		if workDir != "" {
 			wrappedCmd = "cd " + ShellQuote(workDir) + " && " + command
@@ -260,6 +349,59 @@ func (p *Executor) CallTool(ctx context.Context, session mcpgw.ToolSessionContex
 			"exit_code": result.ExitCode,
 		}), nil
 
+	case toolJobStatus:
+		job, err := ExecJobStatus(ctx, p.execRunner, botID, mcpgw.StringArg(arguments, "job_id"))
+		if err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		}
+		return mcpgw.BuildToolSuccessResult(jobInfoMap(job)), nil
+
+	case toolJobOutput:
+		jobID := mcpgw.StringArg(arguments, "job_id")
+		offset, hasOffset, err := mcpgw.IntArg(arguments, "offset")
+		if err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		}
+		if !hasOffset || offset < 0 {
+			offset = -1
+		}
+		limit, _, err := mcpgw.IntArg(arguments, "limit")
+		if err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		}
+		out, err := ExecJobOutput(ctx, p.execRunner, botID, jobID, int64(offset), limit)
+		if err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		}
+		job, err := ExecJobStatus(ctx, p.execRunner, botID, jobID)
+		if err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		}
+		result := jobInfoMap(job)
+		result["output"] = out.Output
+		result["offset"] = out.Offset
+		result["next_offset"] = out.NextOffset
+		result["output_size"] = out.Size
+		return mcpgw.BuildToolSuccessResult(result), nil
+
+	case toolJobKill:
+		jobID := mcpgw.StringArg(arguments, "job_id")
+		if err := ExecKillJob(ctx, p.execRunner, botID, jobID, mcpgw.StringArg(arguments, "signal")); err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		}
+		return mcpgw.BuildToolSuccessResult(map[string]any{"ok": true, "job_id": jobID}), nil
+
+	case toolJobList:
+		jobs, err := ExecListJobs(ctx, p.execRunner, botID)
+		if err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		}
+		items := make([]map[string]any, len(jobs))
+		for i, job := range jobs {
+			items[i] = jobInfoMap(job)
+		}
+		return mcpgw.BuildToolSuccessResult(map[string]any{"jobs": items}), nil
+
 	default:
 		return nil, mcpgw.ErrToolNotFound
 	}
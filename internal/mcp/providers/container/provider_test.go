@@ -38,7 +38,10 @@ func TestExecutor_ListTools(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	want := map[string]bool{"read": true, "write": true, "list": true, "edit": true, "exec": true}
+	want := map[string]bool{
+		"read": true, "write": true, "list": true, "edit": true, "exec": true,
+		"job_status": true, "job_output": true, "job_kill": true, "job_list": true,
+	}
 	if len(tools) != len(want) {
 		t.Errorf("got %d tools, want %d", len(tools), len(want))
 	}
@@ -100,6 +103,41 @@ func TestExecutor_CallTool_Write(t *testing.T) {
 	}
 }
 
+type fakeQuotaChecker struct {
+	limit int64
+	delta int64
+}
+
+func (f *fakeQuotaChecker) CheckDataQuota(_ context.Context, _ string, delta int64) error {
+	f.delta = delta
+	if delta > f.limit {
+		return fmt.Errorf("%w: over quota", mcpgw.ErrDataQuotaExceeded)
+	}
+	return nil
+}
+
+func TestExecutor_CallTool_WriteOverQuota(t *testing.T) {
+	runner := &fakeExecRunner{result: &mcpgw.ExecWithCaptureResult{}}
+	exec := NewExecutor(nil, runner, "/data")
+	quota := &fakeQuotaChecker{limit: 4}
+	exec.SetQuotaChecker(quota)
+	session := mcpgw.ToolSessionContext{BotID: "bot1"}
+
+	result, err := exec.CallTool(context.Background(), session, "write", map[string]any{"path": "big.txt", "content": "too large"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isErr, _ := result["isError"].(bool); !isErr {
+		t.Fatalf("expected quota error, got %v", result)
+	}
+	if quota.delta != int64(len("too large")) {
+		t.Errorf("delta = %d, want %d", quota.delta, len("too large"))
+	}
+	if len(runner.lastReq.Command) != 0 {
+		t.Errorf("write must not run, got %q", runner.lastReq.Command)
+	}
+}
+
 func TestExecutor_CallTool_List(t *testing.T) {
 	runner := &fakeExecRunner{
 		result: &mcpgw.ExecWithCaptureResult{
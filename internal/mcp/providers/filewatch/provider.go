@@ -0,0 +1,234 @@
+package filewatch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/memohai/memoh/internal/filewatch"
+	mcpgw "github.com/memohai/memoh/internal/mcp"
+)
+
+const (
+	toolWatchList   = "list_file_watches"
+	toolWatchCreate = "create_file_watch"
+	toolWatchUpdate = "update_file_watch"
+	toolWatchDelete = "delete_file_watch"
+)
+
+type Watcher interface {
+	List(ctx context.Context, botID string) ([]filewatch.Watch, error)
+	Get(ctx context.Context, id string) (filewatch.Watch, error)
+	Create(ctx context.Context, botID string, req filewatch.CreateRequest) (filewatch.Watch, error)
+	Update(ctx context.Context, id string, req filewatch.UpdateRequest) (filewatch.Watch, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type Executor struct {
+	service Watcher
+	logger  *slog.Logger
+}
+
+func NewExecutor(log *slog.Logger, service Watcher) *Executor {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Executor{
+		service: service,
+		logger:  log.With(slog.String("provider", "filewatch_tool")),
+	}
+}
+
+func (p *Executor) ListTools(ctx context.Context, session mcpgw.ToolSessionContext) ([]mcpgw.ToolDescriptor, error) {
+	if p.service == nil {
+		return []mcpgw.ToolDescriptor{}, nil
+	}
+	watchProperties := func() map[string]any {
+		return map[string]any{
+			"pattern": map[string]any{
+				"type":        "string",
+				"description": "Glob relative to /data, ** matches any directories, e.g. uploads/**/*.csv",
+			},
+			"events": map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string", "enum": []string{filewatch.EventCreated, filewatch.EventModified, filewatch.EventDeleted}},
+				"description": "Changes to report, all by default",
+			},
+			"action": map[string]any{
+				"type":        "string",
+				"enum":        []string{filewatch.ActionInbox, filewatch.ActionChat},
+				"description": "inbox (default) delivers changes to your inbox, chat starts a turn with the command right away",
+			},
+			"command": map[string]any{"type": "string", "description": "What to do with the changed files"},
+			"enabled": map[string]any{"type": "boolean"},
+		}
+	}
+	updateProperties := watchProperties()
+	updateProperties["id"] = map[string]any{"type": "string", "description": "File watch ID"}
+	return []mcpgw.ToolDescriptor{
+		{
+			Name:        toolWatchList,
+			Description: "List file watches for current bot",
+			InputSchema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{},
+			},
+		},
+		{
+			Name:        toolWatchCreate,
+			Description: "Watch files in /data for changes, e.g. user uploads or output of background jobs",
+			InputSchema: map[string]any{
+				"type":       "object",
+				"properties": watchProperties(),
+				"required":   []string{"pattern"},
+			},
+		},
+		{
+			Name:        toolWatchUpdate,
+			Description: "Update an existing file watch",
+			InputSchema: map[string]any{
+				"type":       "object",
+				"properties": updateProperties,
+				"required":   []string{"id"},
+			},
+		},
+		{
+			Name:        toolWatchDelete,
+			Description: "Delete a file watch by id",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"id": map[string]any{"type": "string", "description": "File watch ID"},
+				},
+				"required": []string{"id"},
+			},
+		},
+	}, nil
+}
+
+func (p *Executor) CallTool(ctx context.Context, session mcpgw.ToolSessionContext, toolName string, arguments map[string]any) (map[string]any, error) {
+	if p.service == nil {
+		return mcpgw.BuildToolErrorResult("file watch service not available"), nil
+	}
+	botID := strings.TrimSpace(session.BotID)
+	if botID == "" {
+		return mcpgw.BuildToolErrorResult("bot_id is required"), nil
+	}
+
+	switch toolName {
+	case toolWatchList:
+		items, err := p.service.List(ctx, botID)
+		if err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		}
+		return mcpgw.BuildToolSuccessResult(map[string]any{
+			"items": items,
+		}), nil
+	case toolWatchCreate:
+		req := filewatch.CreateRequest{
+			Pattern: mcpgw.StringArg(arguments, "pattern"),
+			Action:  mcpgw.StringArg(arguments, "action"),
+			Command: mcpgw.StringArg(arguments, "command"),
+		}
+		if req.Pattern == "" {
+			return mcpgw.BuildToolErrorResult("pattern is required"), nil
+		}
+		events, err := stringsArg(arguments, "events")
+		if err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		}
+		req.Events = events
+		if enabled, ok, err := mcpgw.BoolArg(arguments, "enabled"); err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		} else if ok {
+			req.Enabled = &enabled
+		}
+		item, err := p.service.Create(ctx, botID, req)
+		if err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		}
+		return mcpgw.BuildToolSuccessResult(item), nil
+	case toolWatchUpdate:
+		id := mcpgw.StringArg(arguments, "id")
+		if id == "" {
+			return mcpgw.BuildToolErrorResult("id is required"), nil
+		}
+		if result := p.checkOwner(ctx, botID, id); result != nil {
+			return result, nil
+		}
+		req := filewatch.UpdateRequest{}
+		if value := mcpgw.StringArg(arguments, "pattern"); value != "" {
+			req.Pattern = &value
+		}
+		if value := mcpgw.StringArg(arguments, "action"); value != "" {
+			req.Action = &value
+		}
+		if _, ok := arguments["command"]; ok {
+			value := mcpgw.StringArg(arguments, "command")
+			req.Command = &value
+		}
+		events, err := stringsArg(arguments, "events")
+		if err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		}
+		req.Events = events
+		if enabled, ok, err := mcpgw.BoolArg(arguments, "enabled"); err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		} else if ok {
+			req.Enabled = &enabled
+		}
+		item, err := p.service.Update(ctx, id, req)
+		if err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		}
+		return mcpgw.BuildToolSuccessResult(item), nil
+	case toolWatchDelete:
+		id := mcpgw.StringArg(arguments, "id")
+		if id == "" {
+			return mcpgw.BuildToolErrorResult("id is required"), nil
+		}
+		if result := p.checkOwner(ctx, botID, id); result != nil {
+			return result, nil
+		}
+		if err := p.service.Delete(ctx, id); err != nil {
+			return mcpgw.BuildToolErrorResult(err.Error()), nil
+		}
+		return mcpgw.BuildToolSuccessResult(map[string]any{"success": true}), nil
+	default:
+		return nil, mcpgw.ErrToolNotFound
+	}
+}
+
+// checkOwner returns an error result unless the watch belongs to botID.
+func (p *Executor) checkOwner(ctx context.Context, botID, id string) map[string]any {
+	item, err := p.service.Get(ctx, id)
+	if err != nil {
+		return mcpgw.BuildToolErrorResult(err.Error())
+	}
+	if item.BotID != botID {
+		return mcpgw.BuildToolErrorResult("bot mismatch")
+	}
+	return nil
+}
+
+// stringsArg reads an optional array of strings; a missing key yields nil.
+func stringsArg(arguments map[string]any, key string) ([]string, error) {
+	raw, ok := arguments[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array of strings", key)
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		value, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an array of strings", key)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,105 @@
+package filewatch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/memohai/memoh/internal/filewatch"
+	mcpgw "github.com/memohai/memoh/internal/mcp"
+)
+
+type fakeWatcher struct {
+	get       filewatch.Watch
+	created   filewatch.CreateRequest
+	updated   filewatch.UpdateRequest
+	deletedID string
+}
+
+func (f *fakeWatcher) List(ctx context.Context, botID string) ([]filewatch.Watch, error) {
+	return []filewatch.Watch{f.get}, nil
+}
+
+func (f *fakeWatcher) Get(ctx context.Context, id string) (filewatch.Watch, error) {
+	return f.get, nil
+}
+
+func (f *fakeWatcher) Create(ctx context.Context, botID string, req filewatch.CreateRequest) (filewatch.Watch, error) {
+	f.created = req
+	return filewatch.Watch{ID: "w1", BotID: botID, Pattern: req.Pattern}, nil
+}
+
+func (f *fakeWatcher) Update(ctx context.Context, id string, req filewatch.UpdateRequest) (filewatch.Watch, error) {
+	f.updated = req
+	return f.get, nil
+}
+
+func (f *fakeWatcher) Delete(ctx context.Context, id string) error {
+	f.deletedID = id
+	return nil
+}
+
+func TestExecutor_ListTools_NilService(t *testing.T) {
+	tools, err := NewExecutor(nil, nil).ListTools(context.Background(), mcpgw.ToolSessionContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools) != 0 {
+		t.Errorf("expected 0 tools when service nil, got %d", len(tools))
+	}
+}
+
+func TestExecutor_CallTool_Create(t *testing.T) {
+	svc := &fakeWatcher{}
+	exec := NewExecutor(nil, svc)
+	session := mcpgw.ToolSessionContext{BotID: "bot1"}
+	result, err := exec.CallTool(context.Background(), session, toolWatchCreate, map[string]any{
+		"pattern": "uploads/*.csv",
+		"events":  []any{"created"},
+		"action":  "chat",
+		"command": "Summarize it",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mcpgw.PayloadError(result); err != nil {
+		t.Fatal(err)
+	}
+	if svc.created.Pattern != "uploads/*.csv" || svc.created.Action != "chat" || len(svc.created.Events) != 1 {
+		t.Fatalf("create request = %+v", svc.created)
+	}
+
+	result, err = exec.CallTool(context.Background(), session, toolWatchCreate, map[string]any{
+		"pattern": "a", "events": "created",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isErr, _ := result["isError"].(bool); !isErr {
+		t.Error("expected error when events is not an array")
+	}
+}
+
+func TestExecutor_CallTool_BotMismatch(t *testing.T) {
+	svc := &fakeWatcher{get: filewatch.Watch{ID: "w1", BotID: "other-bot"}}
+	exec := NewExecutor(nil, svc)
+	session := mcpgw.ToolSessionContext{BotID: "bot1"}
+	for _, tool := range []string{toolWatchUpdate, toolWatchDelete} {
+		result, err := exec.CallTool(context.Background(), session, tool, map[string]any{"id": "w1", "enabled": false})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if isErr, _ := result["isError"].(bool); !isErr {
+			t.Errorf("%s: expected error when bot mismatch", tool)
+		}
+	}
+	if svc.deletedID != "" || svc.updated.Enabled != nil {
+		t.Fatal("watch of another bot was changed")
+	}
+}
+
+func TestExecutor_CallTool_NotFound(t *testing.T) {
+	_, err := NewExecutor(nil, &fakeWatcher{}).CallTool(context.Background(), mcpgw.ToolSessionContext{BotID: "bot1"}, "other_tool", nil)
+	if err != mcpgw.ErrToolNotFound {
+		t.Errorf("expected ErrToolNotFound, got %v", err)
+	}
+}
@@ -137,6 +137,10 @@ func (p *Executor) CallTool(ctx context.Context, session mcpgw.ToolSessionContex
 			"bot_id":    botID,
 		},
 		NoStats: true,
+		Subject: &mem.Subject{
+			ChannelIdentityID: channelIdentityID,
+			ConversationID:    strings.TrimSpace(session.ConversationID),
+		},
 	})
 	if err != nil {
 		p.logger.Warn("memory search namespace failed", slog.String("namespace", sharedMemoryNamespace), slog.Any("error", err))
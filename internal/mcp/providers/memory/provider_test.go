@@ -11,11 +11,13 @@ import (
 )
 
 type fakeSearcher struct {
-	resp memory.SearchResponse
-	err  error
+	resp    memory.SearchResponse
+	err     error
+	lastReq memory.SearchRequest
 }
 
 func (f *fakeSearcher) Search(ctx context.Context, req memory.SearchRequest) (memory.SearchResponse, error) {
+	f.lastReq = req
 	if f.err != nil {
 		return memory.SearchResponse{}, f.err
 	}
@@ -144,7 +146,7 @@ func TestExecutor_CallTool_Success_BotScope(t *testing.T) {
 	accessor := &fakeChatAccessor{}
 	exec := NewExecutor(nil, searcher, accessor, nil)
 	ctx := context.Background()
-	session := mcpgw.ToolSessionContext{BotID: "bot1", ChatID: "bot1"}
+	session := mcpgw.ToolSessionContext{BotID: "bot1", ChatID: "bot1", ChannelIdentityID: "ci1", ConversationID: "route1"}
 	result, err := exec.CallTool(ctx, session, toolSearchMemory, map[string]any{"query": "test"})
 	if err != nil {
 		t.Fatal(err)
@@ -152,6 +154,9 @@ func TestExecutor_CallTool_Success_BotScope(t *testing.T) {
 	if err := mcpgw.PayloadError(result); err != nil {
 		t.Fatal(err)
 	}
+	if subject := searcher.lastReq.Subject; subject == nil || subject.ChannelIdentityID != "ci1" || subject.ConversationID != "route1" {
+		t.Errorf("search subject = %+v, want channel identity ci1 and conversation route1", subject)
+	}
 	content, _ := result["structuredContent"].(map[string]any)
 	if content == nil {
 		t.Fatal("no structuredContent")
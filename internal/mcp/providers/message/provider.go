@@ -93,6 +93,10 @@ func (p *Executor) ListTools(ctx context.Context, session mcpgw.ToolSessionConte
 						"type":        "string",
 						"description": "Channel target (chat/group/thread ID). Use get_contacts to find available targets.",
 					},
+					"config_id": map[string]any{
+						"type":        "string",
+						"description": "Channel config to send through when the bot has several accounts on the platform. Use the config_id returned by get_contacts; defaults to the platform's default config.",
+					},
 					"text": map[string]any{
 						"type":        "string",
 						"description": "Message text shortcut when message object is omitted",
@@ -134,6 +138,10 @@ func (p *Executor) ListTools(ctx context.Context, session mcpgw.ToolSessionConte
 						"type":        "string",
 						"description": "Channel target (chat/group ID). Defaults to current session reply target.",
 					},
+					"config_id": map[string]any{
+						"type":        "string",
+						"description": "Channel config the message belongs to when the bot has several accounts on the platform",
+					},
 					"message_id": map[string]any{
 						"type":        "string",
 						"description": "The message ID to react to",
@@ -216,8 +224,9 @@ func (p *Executor) callSend(ctx context.Context, session mcpgw.ToolSessionContex
 	}
 
 	sendReq := channel.SendRequest{
-		Target:  target,
-		Message: outboundMessage,
+		ConfigID: mcpgw.FirstStringArg(arguments, "config_id"),
+		Target:   target,
+		Message:  outboundMessage,
 	}
 	if err := p.sender.Send(ctx, botID, channelType, sendReq); err != nil {
 		p.logger.Warn("send failed", slog.Any("error", err), slog.String("bot_id", botID), slog.String("platform", string(channelType)))
@@ -267,6 +276,7 @@ func (p *Executor) callReact(ctx context.Context, session mcpgw.ToolSessionConte
 	remove, _, _ := mcpgw.BoolArg(arguments, "remove")
 
 	reactReq := channel.ReactRequest{
+		ConfigID:  mcpgw.FirstStringArg(arguments, "config_id"),
 		Target:    target,
 		MessageID: messageID,
 		Emoji:     emoji,
@@ -292,6 +292,26 @@ func TestExecutor_CallTool_NoReplyTo(t *testing.T) {
 	}
 }
 
+func TestExecutor_CallTool_ConfigID(t *testing.T) {
+	sender := &fakeSender{}
+	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
+	exec := NewExecutor(nil, sender, nil, resolver, nil)
+	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "telegram", ReplyTarget: "123"}
+	result, err := exec.CallTool(context.Background(), session, toolSend, map[string]any{
+		"text":      "hello",
+		"config_id": "cfg-work",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mcpgw.PayloadError(result); err != nil {
+		t.Fatal(err)
+	}
+	if sender.lastReq.ConfigID != "cfg-work" {
+		t.Errorf("ConfigID = %q, want %q", sender.lastReq.ConfigID, "cfg-work")
+	}
+}
+
 // --- react tests ---
 
 func TestExecutor_React_NilReactor(t *testing.T) {
@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/containerd/errdefs"
+
+	ctr "github.com/memohai/memoh/internal/containerd"
+	"github.com/memohai/memoh/internal/db"
+)
+
+// ErrDataQuotaExceeded is returned when a write would grow the bot data
+// directory past its configured quota.
+var ErrDataQuotaExceeded = errors.New("data directory quota exceeded")
+
+// ResourceReport is the resource usage of a bot container against its limits.
+type ResourceReport struct {
+	Limits ctr.ResourceLimits
+	// Running is false when the container has no running task; Usage is then
+	// zero.
+	Running bool
+	Usage   ctr.ResourceUsage
+	// DataBytes is the size of the bot data directory.
+	DataBytes int64
+	// Suspended is how the container was suspended for inactivity, "pause"
+	// or "stop", or empty when it was not.
+	Suspended    string
+	LastActivity time.Time
+}
+
+// ResourceLimits returns the container limits configured in the bot settings.
+func (m *Manager) ResourceLimits(ctx context.Context, botID string) (ctr.ResourceLimits, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return ctr.ResourceLimits{}, err
+	}
+	row, err := m.queries.GetBotResourceLimits(ctx, pgBotID)
+	if err != nil {
+		return ctr.ResourceLimits{}, err
+	}
+	limits := ctr.ResourceLimits{
+		CPUShares:      uint64(max(row.ContainerCpuShares, 0)),
+		MemoryBytes:    int64(row.ContainerMemoryMb) << 20,
+		PidsLimit:      int64(row.ContainerPidsLimit),
+		DataQuotaBytes: int64(row.ContainerDiskQuotaMb) << 20,
+	}
+	if row.ContainerCpuLimit > 0 {
+		limits.CPUPeriod = ctr.DefaultCPUPeriod
+		limits.CPUQuota = int64(row.ContainerCpuLimit * float64(ctr.DefaultCPUPeriod))
+	}
+	return limits, nil
+}
+
+// ApplyResourceLimits writes the bot's configured limits into its container
+// spec so they take effect on the next task start. Backends without cgroup
+// support are skipped.
+func (m *Manager) ApplyResourceLimits(ctx context.Context, botID string) error {
+	limits, err := m.ResourceLimits(ctx, botID)
+	if err != nil {
+		return err
+	}
+	err = m.service.UpdateContainerResources(ctx, m.containerID(botID), limits)
+	if errors.Is(err, ctr.ErrNotSupported) {
+		m.logger.Debug("container resource limits not supported", slog.String("bot_id", botID))
+		return nil
+	}
+	return err
+}
+
+// ResourceReport returns the current usage of a bot container and data
+// directory together with the configured limits.
+func (m *Manager) ResourceReport(ctx context.Context, botID string) (ResourceReport, error) {
+	limits, err := m.ResourceLimits(ctx, botID)
+	if err != nil {
+		return ResourceReport{}, err
+	}
+	report := ResourceReport{Limits: limits}
+	report.Suspended, report.LastActivity = m.IdleState(botID)
+
+	usage, err := m.service.GetContainerResourceUsage(ctx, m.containerID(botID))
+	switch {
+	case err == nil:
+		report.Running = true
+		report.Usage = usage
+	case errdefs.IsNotFound(err), errors.Is(err, ctr.ErrNotSupported):
+	default:
+		return ResourceReport{}, err
+	}
+
+	dataDir, err := m.DataDir(botID)
+	if err != nil {
+		return ResourceReport{}, err
+	}
+	report.DataBytes, err = dirSize(dataDir)
+	if err != nil {
+		return ResourceReport{}, err
+	}
+	return report, nil
+}
+
+// CheckDataQuota returns ErrDataQuotaExceeded when growing the bot data
+// directory by delta bytes would exceed its quota. A zero quota is unlimited.
+func (m *Manager) CheckDataQuota(ctx context.Context, botID string, delta int64) error {
+	limits, err := m.ResourceLimits(ctx, botID)
+	if err != nil {
+		return err
+	}
+	if limits.DataQuotaBytes <= 0 {
+		return nil
+	}
+	dataDir, err := m.DataDir(botID)
+	if err != nil {
+		return err
+	}
+	used, err := dirSize(dataDir)
+	if err != nil {
+		return err
+	}
+	return checkQuota(used, delta, limits.DataQuotaBytes)
+}
+
+func checkQuota(used, delta, quota int64) error {
+	if delta > 0 && used+delta > quota {
+		return fmt.Errorf("%w: %d of %d bytes used, write needs %d more", ErrDataQuotaExceeded, used, quota, delta)
+	}
+	return nil
+}
+
+func dirSize(root string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(root, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
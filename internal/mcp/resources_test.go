@@ -0,0 +1,25 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckQuota(t *testing.T) {
+	tests := []struct {
+		name    string
+		used    int64
+		delta   int64
+		wantErr bool
+	}{
+		{name: "fits", used: 60, delta: 40},
+		{name: "over", used: 60, delta: 41, wantErr: true},
+		{name: "shrinking while over", used: 200, delta: -10},
+	}
+	for _, tt := range tests {
+		err := checkQuota(tt.used, tt.delta, 100)
+		if tt.wantErr != errors.Is(err, ErrDataQuotaExceeded) {
+			t.Errorf("%s: checkQuota() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
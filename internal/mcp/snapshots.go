@@ -0,0 +1,461 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containerd/continuity/fs"
+	"github.com/containerd/errdefs"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	ctr "github.com/memohai/memoh/internal/containerd"
+	"github.com/memohai/memoh/internal/db"
+	dbsqlc "github.com/memohai/memoh/internal/db/sqlc"
+)
+
+// maxDiffChanges caps the number of changes returned by DiffVersions.
+const maxDiffChanges = 5000
+
+var errDiffLimit = errors.New("diff change limit reached")
+
+// SnapshotPolicy is a bot's automatic snapshot and retention configuration.
+type SnapshotPolicy struct {
+	Cron        string
+	BeforeRisky bool
+	// KeepLast and KeepDaily bound retention; both zero keeps every snapshot.
+	KeepLast  int
+	KeepDaily int
+}
+
+// PruneResult reports a snapshot garbage collection.
+type PruneResult struct {
+	// Versions lists the versions whose records were deleted.
+	Versions []int
+	// Removed lists the runtime snapshots removed from the snapshotter.
+	Removed []string
+	// Retained lists pruned snapshots kept in the snapshotter because newer
+	// snapshots are still layered on top of them.
+	Retained []string
+}
+
+// FileChange is a path that differs between two container versions. Kind is
+// add, modify or delete.
+type FileChange struct {
+	Path string
+	Kind string
+}
+
+// VersionDiff lists the files changed between two container versions.
+type VersionDiff struct {
+	From      int
+	To        int
+	Changes   []FileChange
+	Truncated bool
+}
+
+type snapshotRecord struct {
+	ID        pgtype.UUID
+	Name      string
+	Version   int
+	CreatedAt time.Time
+}
+
+// SnapshotPolicy returns the snapshot policy from the bot settings.
+func (m *Manager) SnapshotPolicy(ctx context.Context, botID string) (SnapshotPolicy, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return SnapshotPolicy{}, err
+	}
+	row, err := m.queries.GetBotSnapshotPolicy(ctx, pgBotID)
+	if err != nil {
+		return SnapshotPolicy{}, err
+	}
+	return SnapshotPolicy{
+		Cron:        strings.TrimSpace(row.SnapshotCron),
+		BeforeRisky: row.SnapshotBeforeRisky,
+		KeepLast:    int(max(row.SnapshotKeepLast, 0)),
+		KeepDaily:   int(max(row.SnapshotKeepDaily, 0)),
+	}, nil
+}
+
+// TakeSnapshot records the container rootfs as a new version. The task is
+// stopped for the commit and restarted afterwards if it was running.
+func (m *Manager) TakeSnapshot(ctx context.Context, botID, source string) (*VersionInfo, error) {
+	if err := validateBotID(botID); err != nil {
+		return nil, err
+	}
+	wasRunning := false
+	if task, err := m.service.GetTaskInfo(ctx, m.containerID(botID)); err == nil {
+		wasRunning = task.Status == ctr.TaskStatusRunning
+	}
+	version, err := m.createVersion(ctx, botID, source)
+	if err != nil {
+		return nil, err
+	}
+	if wasRunning {
+		if err := m.Start(ctx, botID); err != nil {
+			return version, fmt.Errorf("restart after snapshot: %w", err)
+		}
+	}
+	return version, nil
+}
+
+// SnapshotBeforeOperation takes a snapshot ahead of a risky operation when the
+// bot's policy asks for it. Bots without a container are skipped.
+func (m *Manager) SnapshotBeforeOperation(ctx context.Context, botID, operation string) error {
+	return m.snapshotBeforeOperation(ctx, botID, operation, true)
+}
+
+func (m *Manager) snapshotBeforeOperation(ctx context.Context, botID, operation string, restart bool) error {
+	if m.queries == nil {
+		return nil
+	}
+	policy, err := m.SnapshotPolicy(ctx, botID)
+	if err != nil || !policy.BeforeRisky {
+		return err
+	}
+	containerID := m.containerID(botID)
+	if _, err := m.service.GetContainer(ctx, containerID); err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	var version *VersionInfo
+	if restart {
+		version, err = m.TakeSnapshot(ctx, botID, SnapshotSourcePreOperation)
+	} else {
+		version, err = m.createVersion(ctx, botID, SnapshotSourcePreOperation)
+	}
+	if err != nil {
+		return fmt.Errorf("snapshot before %s: %w", operation, err)
+	}
+	return m.insertEvent(ctx, containerID, "snapshot_before_operation", map[string]any{
+		"operation":     operation,
+		"snapshot_name": version.SnapshotName,
+		"version":       version.Version,
+	})
+}
+
+// RunScheduledSnapshot snapshots a bot container and applies the retention
+// policy. Bots without a container are skipped.
+func (m *Manager) RunScheduledSnapshot(ctx context.Context, botID string) error {
+	if _, err := m.service.GetContainer(ctx, m.containerID(botID)); err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if _, err := m.TakeSnapshot(ctx, botID, SnapshotSourceSchedule); err != nil {
+		return err
+	}
+	_, err := m.PruneSnapshots(ctx, botID)
+	return err
+}
+
+// PruneSnapshots deletes the versions outside the bot's retention policy and
+// garbage collects runtime snapshots of the container that are no longer
+// referenced. Snapshots with children cannot be removed from the snapshotter;
+// they are collected by a later run once nothing is layered on top of them.
+func (m *Manager) PruneSnapshots(ctx context.Context, botID string) (PruneResult, error) {
+	if m.db == nil || m.queries == nil {
+		return PruneResult{}, fmt.Errorf("db is not configured")
+	}
+	if err := validateBotID(botID); err != nil {
+		return PruneResult{}, err
+	}
+	policy, err := m.SnapshotPolicy(ctx, botID)
+	if err != nil {
+		return PruneResult{}, err
+	}
+
+	containerID := m.containerID(botID)
+	unlock := m.lockContainer(containerID)
+	defer unlock()
+
+	info, err := m.service.GetContainer(ctx, containerID)
+	if err != nil {
+		return PruneResult{}, err
+	}
+	rows, err := m.queries.ListSnapshotsWithVersionByContainerID(ctx, containerID)
+	if err != nil {
+		return PruneResult{}, err
+	}
+	records := make([]snapshotRecord, 0, len(rows))
+	for _, row := range rows {
+		record := snapshotRecord{
+			ID:        row.ID,
+			Name:      strings.TrimSpace(row.RuntimeSnapshotName),
+			CreatedAt: db.TimeFromPg(row.CreatedAt),
+		}
+		if row.Version.Valid {
+			record.Version = int(row.Version.Int32)
+		}
+		records = append(records, record)
+	}
+
+	result := PruneResult{Versions: []int{}, Removed: []string{}, Retained: []string{}}
+	pruned := selectSnapshotsToPrune(records, policy.KeepLast, policy.KeepDaily)
+	if len(pruned) > 0 {
+		if err := m.deleteSnapshotRecords(ctx, containerID, pruned); err != nil {
+			return PruneResult{}, err
+		}
+	}
+	managed := make(map[string]struct{}, len(records))
+	prunedNames := make(map[string]struct{}, len(pruned))
+	for _, record := range pruned {
+		prunedNames[record.Name] = struct{}{}
+		if record.Version > 0 {
+			result.Versions = append(result.Versions, record.Version)
+		}
+	}
+	for _, record := range records {
+		if _, ok := prunedNames[record.Name]; !ok {
+			managed[record.Name] = struct{}{}
+		}
+	}
+
+	removed, err := m.collectSnapshots(ctx, info, managed, prunedNames)
+	if err != nil {
+		return PruneResult{}, err
+	}
+	removedSet := make(map[string]struct{}, len(removed))
+	for _, name := range removed {
+		removedSet[name] = struct{}{}
+	}
+	result.Removed = removed
+	for _, record := range pruned {
+		if _, ok := removedSet[record.Name]; !ok {
+			result.Retained = append(result.Retained, record.Name)
+		}
+	}
+	sort.Ints(result.Versions)
+
+	if len(result.Versions) > 0 || len(result.Removed) > 0 {
+		if err := m.insertEvent(ctx, containerID, "snapshot_prune", map[string]any{
+			"versions": result.Versions,
+			"removed":  result.Removed,
+			"retained": result.Retained,
+		}); err != nil {
+			return PruneResult{}, err
+		}
+	}
+	return result, nil
+}
+
+func (m *Manager) deleteSnapshotRecords(ctx context.Context, containerID string, records []snapshotRecord) error {
+	ids := make([]pgtype.UUID, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	qtx := m.queries.WithTx(tx)
+	if err := qtx.DeleteVersionsBySnapshotIDs(ctx, dbsqlc.DeleteVersionsBySnapshotIDsParams{
+		ContainerID: containerID,
+		SnapshotIds: ids,
+	}); err != nil {
+		return err
+	}
+	if err := qtx.DeleteSnapshotsByIDs(ctx, dbsqlc.DeleteSnapshotsByIDsParams{
+		ContainerID: containerID,
+		Ids:         ids,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// collectSnapshots removes the container's runtime snapshots that are neither
+// managed nor part of the active rootfs chain: pruned versions and leftovers of
+// earlier versions, rollbacks and diff views. Leaves go first, so a chain of
+// unreferenced snapshots is removed in one pass.
+func (m *Manager) collectSnapshots(ctx context.Context, info ctr.ContainerInfo, managed, pruned map[string]struct{}) ([]string, error) {
+	all, err := m.service.ListSnapshots(ctx, info.Snapshotter)
+	if err != nil {
+		return nil, err
+	}
+	candidates := collectableSnapshots(all, info.ID+"-", info.SnapshotKey, managed, pruned)
+	removed := []string{}
+	for len(candidates) > 0 {
+		children := make(map[string]int, len(all))
+		for _, snapshot := range all {
+			if snapshot.Parent != "" {
+				children[snapshot.Parent]++
+			}
+		}
+		progress := false
+		remaining := candidates[:0]
+		for _, name := range candidates {
+			if children[name] > 0 {
+				remaining = append(remaining, name)
+				continue
+			}
+			if err := m.service.RemoveSnapshot(ctx, info.Snapshotter, name); err != nil && !errdefs.IsNotFound(err) {
+				m.logger.Warn("remove snapshot failed", slog.String("snapshot", name), slog.Any("error", err))
+				continue
+			}
+			removed = append(removed, name)
+			all = withoutSnapshot(all, name)
+			progress = true
+		}
+		candidates = remaining
+		if !progress {
+			break
+		}
+	}
+	return removed, nil
+}
+
+// collectableSnapshots returns the snapshots that may be garbage collected:
+// pruned ones, plus unmanaged snapshots carrying the container prefix. The
+// active snapshot and its ancestors are never collected.
+func collectableSnapshots(all []ctr.SnapshotInfo, prefix, activeKey string, managed, pruned map[string]struct{}) []string {
+	byName := make(map[string]ctr.SnapshotInfo, len(all))
+	for _, snapshot := range all {
+		byName[snapshot.Name] = snapshot
+	}
+	inUse := map[string]struct{}{}
+	for name := activeKey; name != ""; {
+		if _, seen := inUse[name]; seen {
+			break
+		}
+		inUse[name] = struct{}{}
+		name = byName[name].Parent
+	}
+	var candidates []string
+	for _, snapshot := range all {
+		name := snapshot.Name
+		if _, ok := inUse[name]; ok {
+			continue
+		}
+		if _, ok := managed[name]; ok {
+			continue
+		}
+		_, isPruned := pruned[name]
+		if isPruned || strings.HasPrefix(name, prefix) {
+			candidates = append(candidates, name)
+		}
+	}
+	sort.Strings(candidates)
+	return candidates
+}
+
+func withoutSnapshot(all []ctr.SnapshotInfo, name string) []ctr.SnapshotInfo {
+	out := all[:0]
+	for _, snapshot := range all {
+		if snapshot.Name != name {
+			out = append(out, snapshot)
+		}
+	}
+	return out
+}
+
+// selectSnapshotsToPrune returns the records outside the retention policy:
+// the newest keepLast records and the newest record of each of the last
+// keepDaily days (UTC) are kept. With both limits zero nothing is pruned.
+func selectSnapshotsToPrune(records []snapshotRecord, keepLast, keepDaily int) []snapshotRecord {
+	if keepLast <= 0 && keepDaily <= 0 {
+		return nil
+	}
+	sorted := append([]snapshotRecord(nil), records...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.After(sorted[j].CreatedAt) })
+
+	days := map[string]struct{}{}
+	var pruned []snapshotRecord
+	for i, record := range sorted {
+		keep := i < keepLast
+		day := record.CreatedAt.UTC().Format(time.DateOnly)
+		if _, seen := days[day]; !seen && len(days) < keepDaily {
+			days[day] = struct{}{}
+			keep = true
+		}
+		if !keep {
+			pruned = append(pruned, record)
+		}
+	}
+	return pruned
+}
+
+// DiffVersions lists the rootfs files changed between two versions of a bot
+// container. The data directory is a bind mount and is not part of versions.
+func (m *Manager) DiffVersions(ctx context.Context, botID string, from, to int) (VersionDiff, error) {
+	if m.db == nil || m.queries == nil {
+		return VersionDiff{}, fmt.Errorf("db is not configured")
+	}
+	fromName, err := m.VersionSnapshotName(ctx, botID, from)
+	if err != nil {
+		return VersionDiff{}, fmt.Errorf("version %d: %w", from, err)
+	}
+	toName, err := m.VersionSnapshotName(ctx, botID, to)
+	if err != nil {
+		return VersionDiff{}, fmt.Errorf("version %d: %w", to, err)
+	}
+
+	containerID := m.containerID(botID)
+	// Prune must not collect the views while they are mounted.
+	unlock := m.lockContainer(containerID)
+	defer unlock()
+
+	info, err := m.service.GetContainer(ctx, containerID)
+	if err != nil {
+		return VersionDiff{}, err
+	}
+	fromDir, cleanupFrom, err := ctr.MountSnapshotView(ctx, m.service, info.Snapshotter, fromName)
+	if err != nil {
+		return VersionDiff{}, err
+	}
+	defer m.cleanupView(cleanupFrom)
+	toDir, cleanupTo, err := ctr.MountSnapshotView(ctx, m.service, info.Snapshotter, toName)
+	if err != nil {
+		return VersionDiff{}, err
+	}
+	defer m.cleanupView(cleanupTo)
+
+	changes, truncated, err := diffDirs(ctx, fromDir, toDir, maxDiffChanges)
+	if err != nil {
+		return VersionDiff{}, err
+	}
+	return VersionDiff{From: from, To: to, Changes: changes, Truncated: truncated}, nil
+}
+
+func (m *Manager) cleanupView(cleanup func() error) {
+	if err := cleanup(); err != nil {
+		m.logger.Warn("cleanup snapshot view failed", slog.Any("error", err))
+	}
+}
+
+// diffDirs lists the paths that differ between two directory trees, stopping
+// after limit changes.
+func diffDirs(ctx context.Context, a, b string, limit int) ([]FileChange, bool, error) {
+	changes := []FileChange{}
+	err := fs.Changes(ctx, a, b, func(kind fs.ChangeKind, path string, _ os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if kind == fs.ChangeKindUnmodified {
+			return nil
+		}
+		if len(changes) >= limit {
+			return errDiffLimit
+		}
+		changes = append(changes, FileChange{Path: path, Kind: kind.String()})
+		return nil
+	})
+	if errors.Is(err, errDiffLimit) {
+		return changes, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return changes, false, nil
+}
@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	ctr "github.com/memohai/memoh/internal/containerd"
+)
+
+func TestSelectSnapshotsToPrune(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	records := []snapshotRecord{
+		{Name: "v1", Version: 1, CreatedAt: day.Add(-48*time.Hour + time.Hour)},
+		{Name: "v2", Version: 2, CreatedAt: day.Add(-48*time.Hour + 2*time.Hour)},
+		{Name: "v3", Version: 3, CreatedAt: day.Add(-24*time.Hour + time.Hour)},
+		{Name: "v4", Version: 4, CreatedAt: day.Add(time.Hour)},
+		{Name: "v5", Version: 5, CreatedAt: day.Add(2 * time.Hour)},
+		{Name: "v6", Version: 6, CreatedAt: day.Add(3 * time.Hour)},
+	}
+	cases := []struct {
+		name      string
+		keepLast  int
+		keepDaily int
+		want      []int
+	}{
+		{name: "no policy keeps everything"},
+		{name: "keep last", keepLast: 2, want: []int{1, 2, 3, 4}},
+		{name: "keep daily", keepDaily: 2, want: []int{1, 2, 4, 5}},
+		{name: "keep last and daily", keepLast: 2, keepDaily: 3, want: []int{1, 4}},
+		{name: "limits above count", keepLast: 10, keepDaily: 10},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []int
+			for _, record := range selectSnapshotsToPrune(records, tc.keepLast, tc.keepDaily) {
+				got = append(got, record.Version)
+			}
+			sort.Ints(got)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("pruned versions = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCollectableSnapshots(t *testing.T) {
+	all := []ctr.SnapshotInfo{
+		{Name: "base"},
+		{Name: "mcp-bot-v1", Parent: "base"},
+		{Name: "mcp-bot-v2", Parent: "mcp-bot-v1"},
+		{Name: "mcp-bot-rollback-1", Parent: "mcp-bot-v2"},
+		{Name: "mcp-bot-v3", Parent: "mcp-bot-v2"},
+		{Name: "mcp-bot-view-1", Parent: "mcp-bot-v1"},
+		{Name: "mcp-bot", Parent: "mcp-bot-v3"},
+		{Name: "mcp-other-v1", Parent: "base"},
+	}
+	managed := map[string]struct{}{"mcp-bot-v3": {}}
+	pruned := map[string]struct{}{"mcp-bot-v1": {}, "mcp-bot-v2": {}}
+
+	got := collectableSnapshots(all, "mcp-bot-", "mcp-bot", managed, pruned)
+	want := []string{"mcp-bot-rollback-1", "mcp-bot-view-1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("collectable = %v, want %v", got, want)
+	}
+}
+
+func TestDiffDirs(t *testing.T) {
+	a := t.TempDir()
+	b := t.TempDir()
+	write := func(dir, name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(a, "same.txt", "same")
+	write(b, "same.txt", "same")
+	write(a, "edited.txt", "before")
+	write(b, "edited.txt", "after the edit")
+	write(a, "removed.txt", "gone")
+	write(b, "etc/added.conf", "new")
+	// Copy the unchanged file's mtime so it compares as unmodified.
+	info, err := os.Stat(filepath.Join(a, "same.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(filepath.Join(b, "same.txt"), info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, truncated, err := diffDirs(context.Background(), a, b, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if truncated {
+		t.Fatal("unexpected truncation")
+	}
+	got := map[string]string{}
+	for _, change := range changes {
+		got[change.Path] = change.Kind
+	}
+	want := map[string]string{
+		"/edited.txt":     "modify",
+		"/etc":            "add",
+		"/etc/added.conf": "add",
+		"/removed.txt":    "delete",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("changes = %v, want %v", got, want)
+	}
+
+	changes, truncated, err = diffDirs(context.Background(), a, b, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !truncated || len(changes) != 2 {
+		t.Fatalf("got %d changes truncated=%v, want 2 truncated", len(changes), truncated)
+	}
+}
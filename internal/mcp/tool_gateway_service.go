@@ -28,6 +28,15 @@ type ToolGatewayService struct {
 
 	mu    sync.Mutex
 	cache map[string]cachedToolRegistry
+
+	waker ContainerWaker
+}
+
+// ContainerWaker wakes bot containers suspended for inactivity and records
+// tool calls as activity.
+type ContainerWaker interface {
+	Wake(ctx context.Context, botID string) error
+	TouchActivity(botID string)
 }
 
 func NewToolGatewayService(log *slog.Logger, executors []ToolExecutor, sources []ToolSource) *ToolGatewayService {
@@ -55,6 +64,11 @@ func NewToolGatewayService(log *slog.Logger, executors []ToolExecutor, sources [
 	}
 }
 
+// SetContainerWaker makes tool listing and calls wake suspended containers.
+func (s *ToolGatewayService) SetContainerWaker(waker ContainerWaker) {
+	s.waker = waker
+}
+
 func (s *ToolGatewayService) InitializeResult() map[string]any {
 	return map[string]any{
 		"protocolVersion": "2025-06-18",
@@ -71,6 +85,12 @@ func (s *ToolGatewayService) InitializeResult() map[string]any {
 }
 
 func (s *ToolGatewayService) ListTools(ctx context.Context, session ToolSessionContext) ([]ToolDescriptor, error) {
+	if s.waker != nil {
+		// Container-backed sources list nothing while suspended.
+		if err := s.waker.Wake(ctx, session.BotID); err != nil {
+			s.logger.Warn("wake container for tool listing failed", slog.String("bot_id", session.BotID), slog.Any("error", err))
+		}
+	}
 	registry, err := s.getRegistry(ctx, session, false)
 	if err != nil {
 		return nil, err
@@ -83,6 +103,12 @@ func (s *ToolGatewayService) CallTool(ctx context.Context, session ToolSessionCo
 	if toolName == "" {
 		return nil, fmt.Errorf("tool name is required")
 	}
+	if s.waker != nil {
+		s.waker.TouchActivity(session.BotID)
+		if err := s.waker.Wake(ctx, session.BotID); err != nil {
+			return BuildToolErrorResult(err.Error()), nil
+		}
+	}
 
 	registry, err := s.getRegistry(ctx, session, false)
 	if err != nil {
@@ -124,3 +124,44 @@ func TestToolGatewayServiceCallToolProviderError(t *testing.T) {
 		t.Fatalf("expected isError=true for provider failure")
 	}
 }
+
+type gatewayTestWaker struct {
+	woken   []string
+	touched []string
+	err     error
+}
+
+func (w *gatewayTestWaker) Wake(ctx context.Context, botID string) error {
+	w.woken = append(w.woken, botID)
+	return w.err
+}
+
+func (w *gatewayTestWaker) TouchActivity(botID string) {
+	w.touched = append(w.touched, botID)
+}
+
+func TestToolGatewayServiceCallToolWakesContainer(t *testing.T) {
+	provider := &gatewayTestProvider{
+		tools:      []ToolDescriptor{{Name: "echo_tool", InputSchema: map[string]any{"type": "object"}}},
+		callResult: map[string]map[string]any{"echo_tool": {"ok": true}},
+	}
+	service := NewToolGatewayService(slog.Default(), []ToolExecutor{provider}, nil)
+	waker := &gatewayTestWaker{}
+	service.SetContainerWaker(waker)
+
+	if _, err := service.CallTool(context.Background(), ToolSessionContext{BotID: "bot-1"}, ToolCallPayload{Name: "echo_tool"}); err != nil {
+		t.Fatalf("call tool should not fail: %v", err)
+	}
+	if len(waker.woken) != 1 || len(waker.touched) != 1 || waker.touched[0] != "bot-1" {
+		t.Fatalf("expected one wake and activity for bot-1, got %v %v", waker.woken, waker.touched)
+	}
+
+	waker.err = errors.New("resume failed")
+	result, err := service.CallTool(context.Background(), ToolSessionContext{BotID: "bot-1"}, ToolCallPayload{Name: "echo_tool"})
+	if err != nil {
+		t.Fatalf("call tool should not fail: %v", err)
+	}
+	if isErr, _ := result["isError"].(bool); !isErr {
+		t.Fatalf("expected tool error result when the container cannot be woken, got %v", result)
+	}
+}
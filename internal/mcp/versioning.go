@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -19,9 +20,11 @@ import (
 )
 
 const (
-	SnapshotSourceManual   = "manual"
-	SnapshotSourcePreExec  = "pre_exec"
-	SnapshotSourceRollback = "rollback"
+	SnapshotSourceManual       = "manual"
+	SnapshotSourcePreExec      = "pre_exec"
+	SnapshotSourceRollback     = "rollback"
+	SnapshotSourceSchedule     = "schedule"
+	SnapshotSourcePreOperation = "pre_operation"
 )
 
 type VersionInfo struct {
@@ -99,6 +102,12 @@ func (m *Manager) CreateSnapshot(ctx context.Context, botID, snapshotName, sourc
 }
 
 func (m *Manager) CreateVersion(ctx context.Context, botID string) (*VersionInfo, error) {
+	return m.createVersion(ctx, botID, SnapshotSourcePreExec)
+}
+
+// createVersion stops the container task, commits its rootfs as a new version
+// and recreates the container on top of it. The task is not restarted.
+func (m *Manager) createVersion(ctx context.Context, botID, source string) (*VersionInfo, error) {
 	if m.db == nil || m.queries == nil {
 		return nil, fmt.Errorf("db is not configured")
 	}
@@ -160,7 +169,7 @@ func (m *Manager) CreateVersion(ctx context.Context, botID string) (*VersionInfo
 		versionSnapshotName,
 		info.SnapshotKey,
 		info.Snapshotter,
-		SnapshotSourcePreExec,
+		source,
 	)
 	if err != nil {
 		return nil, err
@@ -221,9 +230,6 @@ func (m *Manager) RollbackVersion(ctx context.Context, botID string, version int
 	}
 
 	containerID := m.containerID(botID)
-	unlock := m.lockContainer(containerID)
-	defer unlock()
-
 	snapshotName, err := m.queries.GetVersionSnapshotRuntimeName(ctx, dbsqlc.GetVersionSnapshotRuntimeNameParams{
 		ContainerID: containerID,
 		Version:     int32(version),
@@ -231,6 +237,13 @@ func (m *Manager) RollbackVersion(ctx context.Context, botID string, version int
 	if err != nil {
 		return err
 	}
+	// Keep the state being rolled away from, so the rollback can be undone.
+	if err := m.snapshotBeforeOperation(ctx, botID, "rollback", false); err != nil {
+		return err
+	}
+
+	unlock := m.lockContainer(containerID)
+	defer unlock()
 
 	info, err := m.service.GetContainer(ctx, containerID)
 	if err != nil {
@@ -322,6 +335,12 @@ func (m *Manager) buildVersionSpec(botID string) (ctr.ContainerSpec, error) {
 }
 
 func (m *Manager) safeStopTask(ctx context.Context, containerID string) error {
+	// A frozen task may not act on the stop signal until it is thawed.
+	if info, err := m.service.GetTaskInfo(ctx, containerID); err == nil && info.Status == ctr.TaskStatusPaused {
+		if err := m.service.ResumeContainer(ctx, containerID); err != nil {
+			m.logger.Warn("resume paused task before stop failed", slog.String("container_id", containerID), slog.Any("error", err))
+		}
+	}
 	err := m.service.StopContainer(ctx, containerID, &ctr.StopTaskOptions{
 		Timeout: 10 * time.Second,
 		Force:   true,
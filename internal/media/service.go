@@ -14,10 +14,17 @@ import (
 	"github.com/memohai/memoh/internal/storage"
 )
 
+// ProviderResolver selects the storage provider bound to a bot.
+// A nil provider with a nil error means the bot uses the default provider.
+type ProviderResolver interface {
+	ResolveProvider(ctx context.Context, botID string) (storage.Provider, error)
+}
+
 // Service provides content-addressed media asset persistence.
 // All metadata is derived from the filesystem — no database, no sidecar files.
 type Service struct {
 	provider storage.Provider
+	resolver ProviderResolver
 	logger   *slog.Logger
 }
 
@@ -32,18 +39,25 @@ func NewService(log *slog.Logger, provider storage.Provider) *Service {
 	}
 }
 
+// SetProviderResolver enables per-bot storage provider selection.
+// Bots without a binding keep using the default provider.
+func (s *Service) SetProviderResolver(resolver ProviderResolver) {
+	s.resolver = resolver
+}
+
 // Ingest persists a new media asset. It hashes the content, deduplicates by
 // checking the filesystem, and stores the bytes. Returns a derived Asset.
 func (s *Service) Ingest(ctx context.Context, input IngestInput) (Asset, error) {
-	if s.provider == nil {
-		return Asset{}, ErrProviderUnavailable
-	}
 	if strings.TrimSpace(input.BotID) == "" {
 		return Asset{}, fmt.Errorf("bot id is required")
 	}
 	if input.Reader == nil {
 		return Asset{}, fmt.Errorf("reader is required")
 	}
+	provider, err := s.providerFor(ctx, input.BotID)
+	if err != nil {
+		return Asset{}, err
+	}
 
 	maxBytes := input.MaxBytes
 	if maxBytes <= 0 {
@@ -65,8 +79,9 @@ func (s *Service) Ingest(ctx context.Context, input IngestInput) (Asset, error)
 	storageKey := path.Join(contentHash[:2], contentHash+ext)
 	routingKey := path.Join(input.BotID, storageKey)
 
-	// Filesystem dedup: if the file already exists, skip write.
-	if _, openErr := s.provider.Open(ctx, routingKey); openErr == nil {
+	// Storage dedup: if the object already exists, skip write.
+	if rc, openErr := provider.Open(ctx, routingKey); openErr == nil {
+		_ = rc.Close()
 		return Asset{
 			ContentHash: contentHash,
 			BotID:       input.BotID,
@@ -83,7 +98,7 @@ func (s *Service) Ingest(ctx context.Context, input IngestInput) (Asset, error)
 	defer func() {
 		_ = tempFile.Close()
 	}()
-	if err := s.provider.Put(ctx, routingKey, tempFile); err != nil {
+	if err := provider.Put(ctx, routingKey, tempFile); err != nil {
 		return Asset{}, fmt.Errorf("store media: %w", err)
 	}
 
@@ -98,24 +113,26 @@ func (s *Service) Ingest(ctx context.Context, input IngestInput) (Asset, error)
 
 // Resolve finds an asset by content hash (no stream open). Used to fill mime/storage_key when DB has none.
 func (s *Service) Resolve(ctx context.Context, botID, contentHash string) (Asset, error) {
-	if s.provider == nil {
-		return Asset{}, ErrProviderUnavailable
+	provider, err := s.providerFor(ctx, botID)
+	if err != nil {
+		return Asset{}, err
 	}
-	return s.resolveByContentHash(ctx, botID, contentHash)
+	return resolveByContentHash(ctx, provider, botID, contentHash)
 }
 
 // Open returns a reader for the media asset identified by content hash.
 // It locates the file by scanning extensions under the hash prefix and derives MIME from the extension.
 func (s *Service) Open(ctx context.Context, botID, contentHash string) (io.ReadCloser, Asset, error) {
-	if s.provider == nil {
-		return nil, Asset{}, ErrProviderUnavailable
+	provider, err := s.providerFor(ctx, botID)
+	if err != nil {
+		return nil, Asset{}, err
 	}
-	asset, err := s.resolveByContentHash(ctx, botID, contentHash)
+	asset, err := resolveByContentHash(ctx, provider, botID, contentHash)
 	if err != nil {
 		return nil, Asset{}, err
 	}
 	routingKey := path.Join(botID, asset.StorageKey)
-	reader, err := s.provider.Open(ctx, routingKey)
+	reader, err := provider.Open(ctx, routingKey)
 	if err != nil {
 		return nil, Asset{}, fmt.Errorf("open storage: %w", err)
 	}
@@ -124,11 +141,12 @@ func (s *Service) Open(ctx context.Context, botID, contentHash string) (io.ReadC
 
 // GetByStorageKey returns an asset derived from a known storage key.
 func (s *Service) GetByStorageKey(ctx context.Context, botID, storageKey string) (Asset, error) {
-	if s.provider == nil {
-		return Asset{}, ErrProviderUnavailable
+	provider, err := s.providerFor(ctx, botID)
+	if err != nil {
+		return Asset{}, err
 	}
 	routingKey := path.Join(botID, storageKey)
-	rc, err := s.provider.Open(ctx, routingKey)
+	rc, err := provider.Open(ctx, routingKey)
 	if err != nil {
 		return Asset{}, ErrAssetNotFound
 	}
@@ -138,15 +156,19 @@ func (s *Service) GetByStorageKey(ctx context.Context, botID, storageKey string)
 
 // AccessPath returns a consumer-accessible reference for a persisted asset.
 func (s *Service) AccessPath(asset Asset) string {
-	if s.provider == nil {
+	provider, err := s.providerFor(context.Background(), asset.BotID)
+	if err != nil {
+		s.logger.Warn("resolve storage provider for access path failed", slog.String("bot_id", asset.BotID), slog.Any("error", err))
 		return ""
 	}
 	routingKey := path.Join(asset.BotID, asset.StorageKey)
-	return s.provider.AccessPath(routingKey)
+	return provider.AccessPath(routingKey)
 }
 
 // IngestContainerFile reads an arbitrary file from a bot's /data/ directory
-// and ingests it into the media store. The provider must implement ContainerFileOpener.
+// and ingests it into the media store. Container files always live on the
+// host bind mount, so the default provider must implement ContainerFileOpener;
+// the bytes are then stored in the bot's bound provider.
 func (s *Service) IngestContainerFile(ctx context.Context, botID, containerPath string) (Asset, error) {
 	if s.provider == nil {
 		return Asset{}, ErrProviderUnavailable
@@ -165,27 +187,62 @@ func (s *Service) IngestContainerFile(ctx context.Context, botID, containerPath
 	return s.Ingest(ctx, IngestInput{BotID: botID, Mime: mime, Reader: f, OriginalExt: ext})
 }
 
-// resolveByContentHash scans hash-prefix directory by extension to find the file.
-// It first tries known extensions (fast path), then falls back to a directory
-// listing if the provider supports it, so arbitrary file types are found.
-func (s *Service) resolveByContentHash(ctx context.Context, botID, contentHash string) (Asset, error) {
-	if strings.TrimSpace(contentHash) == "" || len(contentHash) < 2 {
-		return Asset{}, ErrAssetNotFound
+// CopyAsset copies the asset identified by content hash from one provider to
+// another, keeping its storage key. It reports false when the destination
+// already holds the object.
+func (s *Service) CopyAsset(ctx context.Context, botID, contentHash string, from, to storage.Provider) (Asset, bool, error) {
+	if from == nil || to == nil {
+		return Asset{}, false, ErrProviderUnavailable
+	}
+	asset, err := resolveByContentHash(ctx, from, botID, contentHash)
+	if err != nil {
+		return Asset{}, false, err
 	}
-	prefix := contentHash[:2]
+	routingKey := path.Join(botID, asset.StorageKey)
+	if rc, openErr := to.Open(ctx, routingKey); openErr == nil {
+		_ = rc.Close()
+		return asset, false, nil
+	}
+	src, err := from.Open(ctx, routingKey)
+	if err != nil {
+		return Asset{}, false, fmt.Errorf("open source: %w", err)
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+	if err := to.Put(ctx, routingKey, src); err != nil {
+		return Asset{}, false, fmt.Errorf("store media: %w", err)
+	}
+	return asset, true, nil
+}
 
-	for _, ext := range knownExtensions {
-		storageKey := path.Join(prefix, contentHash+ext)
-		routingKey := path.Join(botID, storageKey)
-		rc, err := s.provider.Open(ctx, routingKey)
+// providerFor returns the storage provider bound to botID, or the default.
+func (s *Service) providerFor(ctx context.Context, botID string) (storage.Provider, error) {
+	if s.resolver != nil {
+		provider, err := s.resolver.ResolveProvider(ctx, botID)
 		if err != nil {
-			continue
+			return nil, fmt.Errorf("resolve storage provider: %w", err)
+		}
+		if provider != nil {
+			return provider, nil
 		}
-		_ = rc.Close()
-		return deriveAssetFromKey(botID, storageKey), nil
 	}
+	if s.provider == nil {
+		return nil, ErrProviderUnavailable
+	}
+	return s.provider, nil
+}
 
-	if lister, ok := s.provider.(storage.PrefixLister); ok {
+// resolveByContentHash locates the stored file for a content hash. Providers
+// that can list keys are asked once for the hash prefix (a single round trip
+// for object stores); otherwise known extensions are probed one by one.
+func resolveByContentHash(ctx context.Context, provider storage.Provider, botID, contentHash string) (Asset, error) {
+	if strings.TrimSpace(contentHash) == "" || len(contentHash) < 2 {
+		return Asset{}, ErrAssetNotFound
+	}
+	prefix := contentHash[:2]
+
+	if lister, ok := provider.(storage.PrefixLister); ok {
 		keyPrefix := path.Join(botID, prefix, contentHash)
 		keys, err := lister.ListPrefix(ctx, keyPrefix)
 		if err == nil {
@@ -198,6 +255,17 @@ func (s *Service) resolveByContentHash(ctx context.Context, botID, contentHash s
 		}
 	}
 
+	for _, ext := range knownExtensions {
+		storageKey := path.Join(prefix, contentHash+ext)
+		routingKey := path.Join(botID, storageKey)
+		rc, err := provider.Open(ctx, routingKey)
+		if err != nil {
+			continue
+		}
+		_ = rc.Close()
+		return deriveAssetFromKey(botID, storageKey), nil
+	}
+
 	return Asset{}, ErrAssetNotFound
 }
 
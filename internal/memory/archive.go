@@ -0,0 +1,442 @@
+package memory
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ArchiveFormat identifies memory archives in the manifest.
+	ArchiveFormat = "memoh.memory"
+	// ArchiveVersion is the current archive layout version.
+	ArchiveVersion = 1
+
+	archiveManifestName = "manifest.json"
+	archiveRecordsName  = "memories.jsonl"
+	archiveScrollBatch  = 256
+	// maxArchiveRecordBytes bounds a single JSONL line on import.
+	maxArchiveRecordBytes = 16 << 20
+	maxImportErrors       = 20
+)
+
+// ErrInvalidArchive indicates the uploaded archive cannot be read.
+var ErrInvalidArchive = errors.New("invalid memory archive")
+
+// ArchiveManifest describes a memory archive. It is written as manifest.json
+// next to memories.jsonl inside a gzipped tar.
+type ArchiveManifest struct {
+	Format          string `json:"format"`
+	Version         int    `json:"version"`
+	ExportedAt      string `json:"exported_at"`
+	BotID           string `json:"bot_id,omitempty"`
+	Count           int    `json:"count"`
+	IncludesVectors bool   `json:"includes_vectors"`
+	// EmbeddingModels lists the model IDs that produced the exported vectors,
+	// with their dimensions.
+	EmbeddingModels map[string]int `json:"embedding_models,omitempty"`
+}
+
+// ArchiveRecord is one line of memories.jsonl.
+type ArchiveRecord struct {
+	ID      string         `json:"id"`
+	Memory  string         `json:"memory"`
+	Payload map[string]any `json:"payload"`
+	// Vector and VectorModel are set only when vectors are included.
+	Vector      []float32 `json:"vector,omitempty"`
+	VectorModel string    `json:"vector_model,omitempty"`
+}
+
+type ExportRequest struct {
+	BotID          string
+	Filters        map[string]any
+	IncludeVectors bool
+}
+
+type ImportRequest struct {
+	BotID string
+	// Filters are the target scope written onto every imported memory,
+	// replacing the scope recorded in the archive.
+	Filters map[string]any
+	// EmbeddingEnabled re-embeds memories whose archived vector is missing or
+	// was produced by a different model than the current text model. It has
+	// no effect when no embedder is configured.
+	EmbeddingEnabled bool
+}
+
+type ImportResult struct {
+	Manifest   ArchiveManifest `json:"manifest"`
+	Total      int             `json:"total"`
+	Imported   int             `json:"imported"`
+	Reembedded int             `json:"reembedded"`
+	Reused     int             `json:"reused_vectors"`
+	Skipped    int             `json:"skipped"`
+	Errors     []string        `json:"errors,omitempty"`
+	// Items are the imported memories, for callers that mirror them elsewhere.
+	Items []MemoryItem `json:"-"`
+}
+
+// Export writes all memories matching req.Filters to w as a memory archive.
+func (s *Service) Export(ctx context.Context, w io.Writer, req ExportRequest) (ArchiveManifest, error) {
+	if s.store == nil {
+		return ArchiveManifest{}, fmt.Errorf("vector store not configured")
+	}
+	if len(req.Filters) == 0 {
+		return ArchiveManifest{}, fmt.Errorf("export requires filters")
+	}
+	manifest := ArchiveManifest{
+		Format:          ArchiveFormat,
+		Version:         ArchiveVersion,
+		ExportedAt:      time.Now().UTC().Format(time.RFC3339),
+		BotID:           req.BotID,
+		IncludesVectors: req.IncludeVectors,
+	}
+
+	var records bytes.Buffer
+	enc := json.NewEncoder(&records)
+	offset := ""
+	for {
+		var (
+			points []vectorPoint
+			next   string
+			err    error
+		)
+		if req.IncludeVectors {
+			points, next, err = s.store.ScrollVectors(ctx, archiveScrollBatch, req.Filters, offset)
+		} else {
+			points, next, err = s.store.Scroll(ctx, archiveScrollBatch, req.Filters, offset)
+		}
+		if err != nil {
+			return ArchiveManifest{}, err
+		}
+		for _, point := range points {
+			record := ArchiveRecord{
+				ID:      point.ID,
+				Memory:  fmt.Sprint(point.Payload["data"]),
+				Payload: point.Payload,
+			}
+			if req.IncludeVectors && len(point.Vector) > 0 {
+				record.Vector = point.Vector
+				record.VectorModel = s.vectorModel(point.VectorName)
+				if record.VectorModel != "" {
+					if manifest.EmbeddingModels == nil {
+						manifest.EmbeddingModels = map[string]int{}
+					}
+					manifest.EmbeddingModels[record.VectorModel] = len(point.Vector)
+				}
+			}
+			if err := enc.Encode(record); err != nil {
+				return ArchiveManifest{}, err
+			}
+			manifest.Count++
+		}
+		if next == "" || len(points) == 0 {
+			break
+		}
+		offset = next
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return ArchiveManifest{}, err
+	}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	modTime := time.Now().UTC()
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{
+		{archiveManifestName, manifestData},
+		{archiveRecordsName, records.Bytes()},
+	} {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    file.name,
+			Mode:    0o644,
+			Size:    int64(len(file.data)),
+			ModTime: modTime,
+		}); err != nil {
+			return ArchiveManifest{}, err
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			return ArchiveManifest{}, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return ArchiveManifest{}, err
+	}
+	if err := gz.Close(); err != nil {
+		return ArchiveManifest{}, err
+	}
+	return manifest, nil
+}
+
+// Import reads a memory archive and upserts its memories into the scope given
+// by req.Filters. Sparse vectors are always recomputed; dense vectors are
+// reused when they come from the current text model, otherwise re-embedded
+// if embedding is enabled. Archived IDs are kept unless they belong to
+// another bot, in which case a new ID is assigned.
+func (s *Service) Import(ctx context.Context, r io.Reader, req ImportRequest) (ImportResult, error) {
+	if s.store == nil {
+		return ImportResult{}, fmt.Errorf("vector store not configured")
+	}
+	if s.bm25 == nil {
+		return ImportResult{}, fmt.Errorf("bm25 indexer not configured")
+	}
+	if len(req.Filters) == 0 {
+		return ImportResult{}, fmt.Errorf("import requires filters")
+	}
+	manifest, records, err := readArchive(r)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	vectors := s.embedImportRecords(ctx, records, req)
+	result := ImportResult{Manifest: manifest, Total: len(records)}
+	for _, record := range records {
+		item, reembedded, reused, err := s.importRecord(ctx, record, req, vectors)
+		if err != nil {
+			result.Skipped++
+			if len(result.Errors) < maxImportErrors {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", record.ID, err))
+			}
+			s.logger.Warn("memory import record failed", slog.String("id", record.ID), slog.Any("error", err))
+			continue
+		}
+		result.Imported++
+		if reembedded {
+			result.Reembedded++
+		}
+		if reused {
+			result.Reused++
+		}
+		result.Items = append(result.Items, item)
+	}
+	return result, nil
+}
+
+// embedImportRecords embeds, in one batch, the text of every record whose
+// archived vector cannot be reused. It returns the vectors keyed by text, or
+// nil when the batch fails so records fall back to embedding one by one.
+func (s *Service) embedImportRecords(ctx context.Context, records []ArchiveRecord, req ImportRequest) map[string][]float32 {
+	if !req.EmbeddingEnabled || s.embedder == nil {
+		return nil
+	}
+	texts := make([]string, 0, len(records))
+	seen := make(map[string]struct{}, len(records))
+	for _, record := range records {
+		text := strings.TrimSpace(record.Memory)
+		if text == "" || s.reusableVector(record) {
+			continue
+		}
+		if _, ok := seen[text]; ok {
+			continue
+		}
+		seen[text] = struct{}{}
+		texts = append(texts, text)
+	}
+	if len(texts) == 0 {
+		return nil
+	}
+	embedded, err := s.embedder.EmbedBatch(ctx, texts)
+	if err != nil || len(embedded) != len(texts) {
+		s.logger.Warn("memory import batch embed failed", slog.Int("count", len(texts)), slog.Any("error", err))
+		return nil
+	}
+	vectors := make(map[string][]float32, len(texts))
+	for i, text := range texts {
+		vectors[text] = embedded[i]
+	}
+	return vectors
+}
+
+// reusableVector reports whether the archived dense vector comes from the
+// current text model.
+func (s *Service) reusableVector(record ArchiveRecord) bool {
+	currentModel := strings.TrimSpace(s.defaultTextModelID)
+	return len(record.Vector) > 0 && currentModel != "" && record.VectorModel == currentModel
+}
+
+func (s *Service) importRecord(ctx context.Context, record ArchiveRecord, req ImportRequest, vectors map[string][]float32) (MemoryItem, bool, bool, error) {
+	text := strings.TrimSpace(record.Memory)
+	if text == "" {
+		return MemoryItem{}, false, false, fmt.Errorf("empty memory")
+	}
+	id, err := s.importID(ctx, record.ID, req.BotID)
+	if err != nil {
+		return MemoryItem{}, false, false, err
+	}
+
+	payload := make(map[string]any, len(record.Payload)+len(req.Filters)+1)
+	for key, value := range record.Payload {
+		payload[key] = value
+	}
+	payload["data"] = text
+	payload["hash"] = hashMemory(text)
+	if _, ok := payload["created_at"].(string); !ok {
+		payload["created_at"] = time.Now().UTC().Format(time.RFC3339)
+	}
+	if req.BotID != "" {
+		payload["bot_id"] = req.BotID
+	}
+	applyFiltersToPayload(payload, req.Filters)
+
+	lang, _ := payload["lang"].(string)
+	if strings.TrimSpace(lang) == "" {
+		lang, err = s.detectLanguage(ctx, text)
+		if err != nil {
+			return MemoryItem{}, false, false, err
+		}
+		payload["lang"] = lang
+	}
+	termFreq, docLen, err := s.bm25.TermFrequencies(lang, text)
+	if err != nil {
+		return MemoryItem{}, false, false, err
+	}
+	sparseIndices, sparseValues := s.bm25.AddDocument(lang, termFreq, docLen)
+	point := vectorPoint{
+		ID:               id,
+		SparseIndices:    sparseIndices,
+		SparseValues:     sparseValues,
+		SparseVectorName: s.store.SparseVectorName(),
+		Payload:          payload,
+	}
+
+	reembedded, reused := false, false
+	switch {
+	case s.reusableVector(record):
+		point.Vector = record.Vector
+		point.VectorName = s.vectorNameForText()
+		reused = true
+	case req.EmbeddingEnabled && s.embedder != nil:
+		vector, ok := vectors[text]
+		if !ok {
+			vector, err = s.embedder.Embed(ctx, text)
+			if err != nil {
+				return MemoryItem{}, false, false, fmt.Errorf("re-embed: %w", err)
+			}
+		}
+		point.Vector = vector
+		point.VectorName = s.vectorNameForText()
+		reembedded = true
+	}
+	if err := s.store.Upsert(ctx, []vectorPoint{point}); err != nil {
+		return MemoryItem{}, false, false, err
+	}
+	s.recordRevision(ctx, Revision{
+		BotID:    req.BotID,
+		MemoryID: id,
+		Action:   RevisionAdd,
+		NewText:  text,
+		Payload:  payload,
+	})
+	return payloadToMemoryItem(id, payload), reembedded, reused, nil
+}
+
+// importID keeps the archived ID unless it is invalid or already used by a
+// memory of another bot.
+func (s *Service) importID(ctx context.Context, id, botID string) (string, error) {
+	if _, err := uuid.Parse(strings.TrimSpace(id)); err != nil {
+		return uuid.NewString(), nil
+	}
+	existing, err := s.store.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if existing == nil {
+		return id, nil
+	}
+	if owner, _ := existing.Payload["bot_id"].(string); owner == botID {
+		return id, nil
+	}
+	return uuid.NewString(), nil
+}
+
+// vectorModel maps a stored vector name to the model that produced it.
+// Unnamed vectors always come from the default text model.
+func (s *Service) vectorModel(vectorName string) string {
+	if strings.TrimSpace(vectorName) != "" {
+		return vectorName
+	}
+	return strings.TrimSpace(s.defaultTextModelID)
+}
+
+func readArchive(r io.Reader) (ArchiveManifest, []ArchiveRecord, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return ArchiveManifest{}, nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	var (
+		manifest     ArchiveManifest
+		haveManifest bool
+		records      []ArchiveRecord
+	)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return ArchiveManifest{}, nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		switch strings.TrimPrefix(header.Name, "./") {
+		case archiveManifestName:
+			if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+				return ArchiveManifest{}, nil, fmt.Errorf("%w: manifest: %v", ErrInvalidArchive, err)
+			}
+			haveManifest = true
+		case archiveRecordsName:
+			records, err = readArchiveRecords(tr)
+			if err != nil {
+				return ArchiveManifest{}, nil, err
+			}
+		}
+	}
+	if !haveManifest {
+		return ArchiveManifest{}, nil, fmt.Errorf("%w: missing %s", ErrInvalidArchive, archiveManifestName)
+	}
+	if manifest.Format != ArchiveFormat {
+		return ArchiveManifest{}, nil, fmt.Errorf("%w: unknown format %q", ErrInvalidArchive, manifest.Format)
+	}
+	if manifest.Version < 1 || manifest.Version > ArchiveVersion {
+		return ArchiveManifest{}, nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidArchive, manifest.Version)
+	}
+	return manifest, records, nil
+}
+
+func readArchiveRecords(r io.Reader) ([]ArchiveRecord, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxArchiveRecordBytes)
+	var records []ArchiveRecord
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		var record ArchiveRecord
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return nil, fmt.Errorf("%w: %s line %d: %v", ErrInvalidArchive, archiveRecordsName, line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	return records, nil
+}
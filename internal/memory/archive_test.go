@@ -0,0 +1,325 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"testing"
+)
+
+// memStore is an in-memory VectorStore used to exercise Service logic.
+type memStore struct {
+	named  bool
+	points map[string]vectorPoint
+}
+
+func newMemStore(named bool) *memStore {
+	return &memStore{named: named, points: map[string]vectorPoint{}}
+}
+
+func (m *memStore) matches(point vectorPoint, filters map[string]any) bool {
+	for key, value := range filters {
+		if expiry, ok := value.(expiryFilter); ok {
+			if isExpired(point.Payload, expiry.at) != expiry.expired {
+				return false
+			}
+			continue
+		}
+		if fmt.Sprint(point.Payload[key]) != fmt.Sprint(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *memStore) sortedIDs() []string {
+	ids := make([]string, 0, len(m.points))
+	for id := range m.points {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (m *memStore) Upsert(_ context.Context, points []vectorPoint) error {
+	for _, point := range points {
+		m.points[point.ID] = point
+	}
+	return nil
+}
+
+func (m *memStore) Search(context.Context, []float32, int, map[string]any, string) ([]vectorPoint, []float64, error) {
+	return nil, nil, nil
+}
+
+func (m *memStore) SearchSparse(context.Context, []uint32, []float32, int, map[string]any, bool) ([]vectorPoint, []float64, error) {
+	return nil, nil, nil
+}
+
+func (m *memStore) SearchBySources(context.Context, []float32, int, map[string]any, []string, string) (map[string][]vectorPoint, map[string][]float64, error) {
+	return nil, nil, nil
+}
+
+func (m *memStore) SearchSparseBySources(context.Context, []uint32, []float32, int, map[string]any, []string, bool) (map[string][]vectorPoint, map[string][]float64, error) {
+	return nil, nil, nil
+}
+
+func (m *memStore) Get(_ context.Context, id string) (*vectorPoint, error) {
+	point, ok := m.points[id]
+	if !ok {
+		return nil, nil
+	}
+	return &point, nil
+}
+
+func (m *memStore) Delete(_ context.Context, id string) error {
+	delete(m.points, id)
+	return nil
+}
+
+func (m *memStore) DeleteBatch(_ context.Context, ids []string) error {
+	for _, id := range ids {
+		delete(m.points, id)
+	}
+	return nil
+}
+
+func (m *memStore) DeleteAll(_ context.Context, filters map[string]any) error {
+	for id, point := range m.points {
+		if m.matches(point, filters) {
+			delete(m.points, id)
+		}
+	}
+	return nil
+}
+
+// List applies the same default limit of 100 as the real stores.
+func (m *memStore) List(_ context.Context, limit int, filters map[string]any, _ bool) ([]vectorPoint, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	var out []vectorPoint
+	for _, id := range m.sortedIDs() {
+		if len(out) == limit {
+			break
+		}
+		if m.matches(m.points[id], filters) {
+			out = append(out, m.points[id])
+		}
+	}
+	return out, nil
+}
+
+func (m *memStore) Scroll(ctx context.Context, limit int, filters map[string]any, offset string) ([]vectorPoint, string, error) {
+	points, next, err := m.ScrollVectors(ctx, limit, filters, offset)
+	for i := range points {
+		points[i].Vector, points[i].VectorName = nil, ""
+	}
+	return points, next, err
+}
+
+func (m *memStore) ScrollVectors(_ context.Context, limit int, filters map[string]any, offset string) ([]vectorPoint, string, error) {
+	var out []vectorPoint
+	for _, id := range m.sortedIDs() {
+		if id < offset || !m.matches(m.points[id], filters) {
+			continue
+		}
+		if len(out) == limit {
+			return out, id, nil
+		}
+		out = append(out, m.points[id])
+	}
+	return out, "", nil
+}
+
+func (m *memStore) Count(_ context.Context, filters map[string]any) (uint64, error) {
+	var n uint64
+	for _, point := range m.points {
+		if m.matches(point, filters) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *memStore) UsesNamedVectors() bool   { return m.named }
+func (m *memStore) SparseVectorName() string { return sparseHashVectorName }
+
+type stubEmbedder struct {
+	calls   int
+	batches int
+}
+
+func (e *stubEmbedder) Embed(context.Context, string) ([]float32, error) {
+	e.calls++
+	return []float32{9, 9, 9}, nil
+}
+
+func (e *stubEmbedder) EmbedBatch(_ context.Context, inputs []string) ([][]float32, error) {
+	e.batches++
+	vectors := make([][]float32, 0, len(inputs))
+	for range inputs {
+		e.calls++
+		vectors = append(vectors, []float32{9, 9, 9})
+	}
+	return vectors, nil
+}
+
+func (e *stubEmbedder) Dimensions() int { return 3 }
+
+func newArchiveTestService(store VectorStore, embedder *stubEmbedder, textModel string) *Service {
+	svc := &Service{
+		llm:                &MockLLM{DetectLanguageFunc: func(context.Context, string) (string, error) { return "en", nil }},
+		store:              store,
+		bm25:               NewBM25Indexer(nil),
+		logger:             slog.Default(),
+		defaultTextModelID: textModel,
+	}
+	// Avoid storing a typed nil, which the service would treat as configured.
+	if embedder != nil {
+		svc.embedder = embedder
+	}
+	return svc
+}
+
+func TestService_ExportImport_RoundTrip(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	source := newMemStore(true)
+	for i, text := range []string{"User likes Go", "User lives in Berlin", "Other bot memory"} {
+		scope := "bot-a"
+		if i == 2 {
+			scope = "bot-b"
+		}
+		_ = source.Upsert(ctx, []vectorPoint{{
+			ID:         fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", i+1),
+			Vector:     []float32{float32(i), 1, 2},
+			VectorName: "model-a",
+			Payload: map[string]any{
+				"data": text, "lang": "en", "bot_id": scope,
+				"namespace": "bot", "scopeId": scope, "created_at": "2026-01-01T00:00:00Z",
+			},
+		}})
+	}
+	exporter := newArchiveTestService(source, nil, "model-a")
+
+	var archive bytes.Buffer
+	manifest, err := exporter.Export(ctx, &archive, ExportRequest{
+		BotID:          "bot-a",
+		Filters:        map[string]any{"namespace": "bot", "scopeId": "bot-a"},
+		IncludeVectors: true,
+	})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if manifest.Count != 2 || manifest.EmbeddingModels["model-a"] != 3 {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+	raw := archive.Bytes()
+
+	t.Run("same model reuses vectors", func(t *testing.T) {
+		t.Parallel()
+		target := newMemStore(true)
+		embedder := &stubEmbedder{}
+		svc := newArchiveTestService(target, embedder, "model-a")
+		result, err := svc.Import(ctx, bytes.NewReader(raw), ImportRequest{
+			BotID:            "bot-c",
+			Filters:          map[string]any{"namespace": "bot", "scopeId": "bot-c"},
+			EmbeddingEnabled: true,
+		})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if result.Imported != 2 || result.Reused != 2 || embedder.calls != 0 {
+			t.Fatalf("unexpected result: %+v (embed calls %d)", result, embedder.calls)
+		}
+		point := target.points["00000000-0000-0000-0000-000000000001"]
+		if point.Payload["scopeId"] != "bot-c" || point.Payload["bot_id"] != "bot-c" {
+			t.Errorf("scope not rewritten: %v", point.Payload)
+		}
+		if point.VectorName != "model-a" || len(point.SparseIndices) == 0 {
+			t.Errorf("expected dense and sparse vectors, got %+v", point)
+		}
+	})
+
+	t.Run("different model re-embeds", func(t *testing.T) {
+		t.Parallel()
+		target := newMemStore(true)
+		embedder := &stubEmbedder{}
+		svc := newArchiveTestService(target, embedder, "model-b")
+		result, err := svc.Import(ctx, bytes.NewReader(raw), ImportRequest{
+			BotID:            "bot-a",
+			Filters:          map[string]any{"namespace": "bot", "scopeId": "bot-a"},
+			EmbeddingEnabled: true,
+		})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if result.Reembedded != 2 || embedder.calls != 2 || embedder.batches != 1 {
+			t.Fatalf("expected batched re-embedding, got %+v (embed calls %d, batches %d)", result, embedder.calls, embedder.batches)
+		}
+		if got := target.points["00000000-0000-0000-0000-000000000002"]; got.VectorName != "model-b" || got.Vector[0] != 9 {
+			t.Errorf("vector not re-embedded: %+v", got)
+		}
+	})
+
+	t.Run("embedding enabled without an embedder keeps sparse only", func(t *testing.T) {
+		t.Parallel()
+		target := newMemStore(true)
+		svc := newArchiveTestService(target, nil, "model-b")
+		result, err := svc.Import(ctx, bytes.NewReader(raw), ImportRequest{
+			BotID:            "bot-a",
+			Filters:          map[string]any{"namespace": "bot", "scopeId": "bot-a"},
+			EmbeddingEnabled: true,
+		})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if result.Imported != 2 || result.Reembedded != 0 {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+		if got := target.points["00000000-0000-0000-0000-000000000001"]; len(got.Vector) != 0 || len(got.SparseIndices) == 0 {
+			t.Errorf("expected a sparse-only point, got %+v", got)
+		}
+	})
+
+	t.Run("ids owned by another bot are reassigned", func(t *testing.T) {
+		t.Parallel()
+		svc := newArchiveTestService(source.clone(), nil, "model-a")
+		result, err := svc.Import(ctx, bytes.NewReader(raw), ImportRequest{
+			BotID:   "bot-b",
+			Filters: map[string]any{"namespace": "bot", "scopeId": "bot-b"},
+		})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		for _, item := range result.Items {
+			if item.ID == "00000000-0000-0000-0000-000000000001" || item.ID == "00000000-0000-0000-0000-000000000002" {
+				t.Errorf("import overwrote a memory of bot-a: %s", item.ID)
+			}
+		}
+	})
+}
+
+func (m *memStore) clone() *memStore {
+	out := newMemStore(m.named)
+	for id, point := range m.points {
+		out.points[id] = point
+	}
+	return out
+}
+
+func TestService_Import_InvalidArchive(t *testing.T) {
+	t.Parallel()
+	svc := newArchiveTestService(newMemStore(false), nil, "")
+	_, err := svc.Import(context.Background(), bytes.NewReader([]byte("not an archive")), ImportRequest{
+		Filters: map[string]any{"scopeId": "bot-a"},
+	})
+	if !errors.Is(err, ErrInvalidArchive) {
+		t.Fatalf("Import error = %v, want ErrInvalidArchive", err)
+	}
+}
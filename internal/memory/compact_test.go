@@ -0,0 +1,204 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestService_PreviewCompact(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := newMemStore(false)
+	svc := newArchiveTestService(store, nil, "")
+	filters := map[string]any{"bot_id": "bot-a", "namespace": "bot", "scopeId": "bot-a"}
+	tea, err := svc.applyAdd(ctx, "Likes green tea", filters, nil, false)
+	if err != nil {
+		t.Fatalf("applyAdd failed: %v", err)
+	}
+	matcha, err := svc.applyAdd(ctx, "Drinks matcha every morning", filters, nil, false)
+	if err != nil {
+		t.Fatalf("applyAdd failed: %v", err)
+	}
+	svc.llm = &MockLLM{
+		CompactFunc: func(_ context.Context, req CompactRequest) (CompactResponse, error) {
+			return CompactResponse{
+				Facts:   []string{"Drinks green tea and matcha daily"},
+				Sources: [][]string{{tea.ID, matcha.ID, "unknown-id"}},
+			}, nil
+		},
+	}
+
+	result, err := svc.PreviewCompact(ctx, filters, 0.5, 0)
+	if err != nil {
+		t.Fatalf("PreviewCompact failed: %v", err)
+	}
+	if !result.DryRun || result.BeforeCount != 2 || result.AfterCount != 1 {
+		t.Errorf("unexpected preview: %+v", result)
+	}
+	if len(result.Merges) != 1 || len(result.Merges[0].Sources) != 2 {
+		t.Fatalf("unexpected merges: %+v", result.Merges)
+	}
+	if result.Merges[0].Sources[0].Memory != "Likes green tea" {
+		t.Errorf("merge source = %+v", result.Merges[0].Sources[0])
+	}
+	for _, id := range []string{tea.ID, matcha.ID} {
+		if _, ok := store.points[id]; !ok {
+			t.Errorf("preview removed memory %s", id)
+		}
+	}
+	if len(store.points) != 2 {
+		t.Errorf("preview changed the store: %d points", len(store.points))
+	}
+}
+
+func TestService_CompactSeesAllMemories(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := newMemStore(false)
+	svc := newArchiveTestService(store, nil, "")
+	filters := map[string]any{"bot_id": "bot-a", "namespace": "bot", "scopeId": "bot-a"}
+	const total = 150
+	points := make([]vectorPoint, 0, total)
+	for i := 0; i < total; i++ {
+		payload := map[string]any{"data": fmt.Sprintf("fact %d", i)}
+		applyFiltersToPayload(payload, filters)
+		points = append(points, vectorPoint{ID: fmt.Sprintf("00000000-0000-0000-0000-%012d", i), Payload: payload})
+	}
+	if err := store.Upsert(ctx, points); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := svc.Count(ctx, filters)
+	if err != nil || count != total {
+		t.Fatalf("Count = %d, %v; want %d", count, err, total)
+	}
+	usage, err := svc.Usage(ctx, filters)
+	if err != nil || usage.Count != total {
+		t.Fatalf("Usage count = %d, %v; want %d", usage.Count, err, total)
+	}
+	var seen int
+	svc.llm = &MockLLM{
+		CompactFunc: func(_ context.Context, req CompactRequest) (CompactResponse, error) {
+			seen = len(req.Memories)
+			return CompactResponse{Facts: []string{"merged"}}, nil
+		},
+	}
+	result, err := svc.PreviewCompact(ctx, filters, 0.5, 0)
+	if err != nil {
+		t.Fatalf("PreviewCompact failed: %v", err)
+	}
+	if seen != total || result.BeforeCount != total {
+		t.Fatalf("compaction saw %d memories (before %d), want %d", seen, result.BeforeCount, total)
+	}
+}
+
+func TestService_CompactKeepsSubjectTags(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := newMemStore(false)
+	svc := newArchiveTestService(store, nil, "")
+	filters := map[string]any{"bot_id": "bot-a", "namespace": "bot", "scopeId": "bot-a"}
+	alice := &Subject{ChannelIdentityID: "ci-alice", ConversationID: "route-1"}
+	bob := &Subject{ChannelIdentityID: "ci-bob", ConversationID: "route-2"}
+	for _, add := range []struct {
+		text    string
+		subject *Subject
+	}{
+		{"Alice likes tea", alice},
+		{"Alice drinks matcha", alice},
+		{"Bob likes coffee", bob},
+		{"Bob drinks espresso", bob},
+		{"Bot is called Memo", nil},
+	} {
+		if _, err := svc.applyAdd(ctx, add.text, mergeFilters(filters, add.subject.payload()), nil, false); err != nil {
+			t.Fatalf("applyAdd failed: %v", err)
+		}
+	}
+	var calls int
+	svc.llm = &MockLLM{
+		DetectLanguageFunc: func(context.Context, string) (string, error) { return "en", nil },
+		CompactFunc: func(_ context.Context, req CompactRequest) (CompactResponse, error) {
+			calls++
+			merged := ""
+			for _, m := range req.Memories {
+				merged += m.Memory + "; "
+			}
+			return CompactResponse{Facts: []string{merged}}, nil
+		},
+	}
+
+	result, err := svc.Compact(ctx, filters, 0.5, 0)
+	if err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	if calls != 2 || result.BeforeCount != 5 || result.AfterCount != 3 || len(store.points) != 3 {
+		t.Fatalf("unexpected compaction: %d llm calls, %+v, %d stored", calls, result, len(store.points))
+	}
+	for _, point := range store.points {
+		data := fmt.Sprint(point.Payload["data"])
+		switch {
+		case strings.HasPrefix(data, "Alice"):
+			if point.Payload[SubjectChannelIdentityKey] != "ci-alice" || point.Payload[SubjectConversationKey] != "route-1" || strings.Contains(data, "Bob") {
+				t.Errorf("alice memory lost its subject or was mixed: %v", point.Payload)
+			}
+		case strings.HasPrefix(data, "Bob"):
+			if point.Payload[SubjectChannelIdentityKey] != "ci-bob" || point.Payload[SubjectConversationKey] != "route-2" || strings.Contains(data, "Alice") {
+				t.Errorf("bob memory lost its subject or was mixed: %v", point.Payload)
+			}
+		default:
+			if _, ok := point.Payload[SubjectChannelIdentityKey]; ok {
+				t.Errorf("bot-wide memory gained a subject: %v", point.Payload)
+			}
+		}
+	}
+}
+
+func TestService_CompactKeepsLatestExpiry(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := newMemStore(false)
+	svc := newArchiveTestService(store, nil, "")
+	filters := map[string]any{"bot_id": "bot-a", "namespace": "bot", "scopeId": "bot-a"}
+	ids := map[string]string{}
+	for _, add := range []struct{ text, expiresAt string }{
+		{"Travels to Japan", "2030-05-01T00:00:00Z"},
+		{"Flies to Tokyo", "2030-05-10T00:00:00Z"},
+		{"Likes sushi", ""},
+		{"Visits Kyoto", "2030-05-03T00:00:00Z"},
+	} {
+		item, err := svc.applyAdd(ctx, add.text, withExpiry(filters, add.expiresAt), nil, false)
+		if err != nil {
+			t.Fatalf("applyAdd failed: %v", err)
+		}
+		ids[add.text] = item.ID
+	}
+	svc.llm = &MockLLM{
+		DetectLanguageFunc: func(context.Context, string) (string, error) { return "en", nil },
+		CompactFunc: func(context.Context, CompactRequest) (CompactResponse, error) {
+			return CompactResponse{
+				Facts: []string{"Is travelling in Japan", "Likes Japanese food"},
+				Sources: [][]string{
+					{ids["Travels to Japan"], ids["Flies to Tokyo"]},
+					{ids["Likes sushi"], ids["Visits Kyoto"]},
+				},
+			}, nil
+		},
+	}
+
+	if _, err := svc.Compact(ctx, filters, 0.5, 0); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	want := map[string]string{"Is travelling in Japan": "2030-05-10T00:00:00Z", "Likes Japanese food": ""}
+	if len(store.points) != len(want) {
+		t.Fatalf("stored %d memories, want %d", len(store.points), len(want))
+	}
+	for _, point := range store.points {
+		data := fmt.Sprint(point.Payload["data"])
+		got, _ := point.Payload[expiresAtKey].(string)
+		if got != want[data] {
+			t.Errorf("%q expires_at = %q, want %q", data, got, want[data])
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// expiresAtKey is the payload key holding the RFC 3339 time after which a
+// memory no longer holds and is removed by the expiry sweeper.
+const expiresAtKey = "expires_at"
+
+const expirySweepBatchSize = 200
+
+// parseExpiry parses an expiry given as RFC 3339 or as a plain date. A plain
+// date (YYYY-MM-DD) expires at the end of that day, UTC.
+func parseExpiry(raw string) (time.Time, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t.UTC(), true
+	}
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		return t.AddDate(0, 0, 1).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+func formatExpiry(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// withExpiry returns filters extended with the expires_at payload tag.
+func withExpiry(filters map[string]any, expiresAt string) map[string]any {
+	if expiresAt == "" {
+		return filters
+	}
+	return mergeFilters(filters, map[string]any{expiresAtKey: expiresAt})
+}
+
+// factExpiry returns the expiry for fact: the request-level expiry when set,
+// otherwise the one the extractor attached to the fact.
+func factExpiry(requested string, expirations map[string]string, fact string) string {
+	if requested != "" || len(expirations) == 0 {
+		return requested
+	}
+	fact = strings.TrimSpace(fact)
+	for text, raw := range expirations {
+		if !strings.EqualFold(strings.TrimSpace(text), fact) {
+			continue
+		}
+		if t, ok := parseExpiry(raw); ok {
+			return formatExpiry(t)
+		}
+	}
+	return ""
+}
+
+// isExpired reports whether the payload carries an expires_at at or before now.
+func isExpired(payload map[string]any, now time.Time) bool {
+	raw, ok := payload[expiresAtKey].(string)
+	if !ok {
+		return false
+	}
+	t, ok := parseExpiry(raw)
+	return ok && !t.After(now)
+}
+
+// expiryFilter is a filter value for expiresAtKey that the vector stores turn
+// into a store-side time comparison. With expired set it matches memories
+// whose expiry is at or before at; otherwise it matches memories without an
+// expiry or expiring after at. Like isExpired, unparseable expiries never
+// count as expired.
+type expiryFilter struct {
+	at      time.Time
+	expired bool
+}
+
+func expiredAt(now time.Time) expiryFilter {
+	return expiryFilter{at: now.UTC(), expired: true}
+}
+
+func unexpiredAt(now time.Time) expiryFilter {
+	return expiryFilter{at: now.UTC()}
+}
+
+// SetMemoryFS lets the expiry sweeper keep the bot filesystem mirror in sync.
+func (s *Service) SetMemoryFS(fs *MemoryFS) {
+	s.memoryFS = fs
+}
+
+// SweepExpired deletes every memory whose expires_at is at or before now from
+// the vector store, the BM25 index and the bot filesystem, recording an EXPIRE
+// revision for each. It returns the number of memories removed.
+func (s *Service) SweepExpired(ctx context.Context, now time.Time) (int, error) {
+	if s.store == nil {
+		return 0, fmt.Errorf("vector store not configured")
+	}
+	expired := map[string][]vectorPoint{}
+	filters := map[string]any{expiresAtKey: expiredAt(now)}
+	offset := ""
+	for {
+		points, next, err := s.store.Scroll(ctx, expirySweepBatchSize, filters, offset)
+		if err != nil {
+			return 0, err
+		}
+		for _, point := range points {
+			botID := resolveBotID("", point.Payload)
+			expired[botID] = append(expired[botID], point)
+		}
+		if next == "" || len(points) == 0 {
+			break
+		}
+		offset = next
+	}
+
+	removed := 0
+	for botID, points := range expired {
+		botCtx := withRevisionSource(WithBotID(ctx, botID), revisionSource{action: RevisionExpire})
+		ids := make([]string, 0, len(points))
+		for _, point := range points {
+			ids = append(ids, point.ID)
+		}
+		if err := s.store.DeleteBatch(botCtx, ids); err != nil {
+			return removed, err
+		}
+		for _, point := range points {
+			s.removeFromIndex(botCtx, point.Payload)
+		}
+		s.recordDeletions(botCtx, points)
+		if s.memoryFS != nil && botID != "" {
+			if err := s.memoryFS.RemoveMemories(botCtx, botID, ids); err != nil {
+				s.logger.Warn("expired memory fs remove failed", slog.String("bot_id", botID), slog.Any("error", err))
+			}
+		}
+		removed += len(ids)
+	}
+	return removed, nil
+}
+
+// StartExpirySweeper runs SweepExpired every interval until ctx is done.
+func (s *Service) StartExpirySweeper(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				removed, err := s.SweepExpired(ctx, now)
+				if err != nil {
+					s.logger.Warn("memory expiry sweep failed", slog.Any("error", err))
+					continue
+				}
+				if removed > 0 {
+					s.logger.Info("expired memories removed", slog.Int("count", removed))
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseExpiry(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		raw  string
+		want string
+		ok   bool
+	}{
+		{raw: "2026-05-12", want: "2026-05-13T00:00:00Z", ok: true},
+		{raw: "2026-05-12T08:30:00+02:00", want: "2026-05-12T06:30:00Z", ok: true},
+		{raw: "next week"},
+		{raw: ""},
+	}
+	for _, tt := range tests {
+		got, ok := parseExpiry(tt.raw)
+		if ok != tt.ok {
+			t.Errorf("parseExpiry(%q) ok = %v, want %v", tt.raw, ok, tt.ok)
+			continue
+		}
+		if ok && formatExpiry(got) != tt.want {
+			t.Errorf("parseExpiry(%q) = %s, want %s", tt.raw, formatExpiry(got), tt.want)
+		}
+	}
+}
+
+func TestFactExpiry(t *testing.T) {
+	t.Parallel()
+	expirations := map[string]string{"Is travelling in Japan": "2026-05-12", "Broken": "soon"}
+	if got := factExpiry("", expirations, "is travelling in japan"); got != "2026-05-13T00:00:00Z" {
+		t.Errorf("extractor expiry = %q", got)
+	}
+	if got := factExpiry("2030-01-01T00:00:00Z", expirations, "Is travelling in Japan"); got != "2030-01-01T00:00:00Z" {
+		t.Errorf("requested expiry should win, got %q", got)
+	}
+	if got := factExpiry("", expirations, "Broken"); got != "" {
+		t.Errorf("unparseable expiry = %q, want empty", got)
+	}
+	if got := factExpiry("", expirations, "Likes tea"); got != "" {
+		t.Errorf("permanent fact expiry = %q, want empty", got)
+	}
+}
+
+func TestService_Add_Expiry(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("request expiry tags raw memories", func(t *testing.T) {
+		t.Parallel()
+		store := newMemStore(false)
+		svc := newArchiveTestService(store, nil, "")
+		infer := false
+		expiresAt := time.Now().Add(48 * time.Hour)
+		resp, err := svc.Add(ctx, AddRequest{Message: "Out of office", BotID: "bot-a", Infer: &infer, ExpiresAt: &expiresAt})
+		if err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if len(resp.Results) != 1 || resp.Results[0].ExpiresAt != formatExpiry(expiresAt) {
+			t.Fatalf("memory not tagged with expiry: %+v", resp.Results)
+		}
+	})
+
+	t.Run("past expiry is rejected", func(t *testing.T) {
+		t.Parallel()
+		svc := newArchiveTestService(newMemStore(false), nil, "")
+		past := time.Now().Add(-time.Hour)
+		if _, err := svc.Add(ctx, AddRequest{Message: "Old news", BotID: "bot-a", ExpiresAt: &past}); err == nil {
+			t.Fatal("expected error for past expires_at")
+		}
+	})
+
+	t.Run("extractor expirations apply per fact", func(t *testing.T) {
+		t.Parallel()
+		svc := newArchiveTestService(newMemStore(false), nil, "")
+		svc.llm = &MockLLM{
+			ExtractFunc: func(context.Context, ExtractRequest) (ExtractResponse, error) {
+				return ExtractResponse{
+					Facts:       []string{"Is travelling in Japan", "Likes sushi"},
+					Expirations: map[string]string{"Is travelling in Japan": "2099-05-12"},
+				}, nil
+			},
+			DecideFunc: func(_ context.Context, req DecideRequest) (DecideResponse, error) {
+				actions := make([]DecisionAction, 0, len(req.Facts))
+				for _, fact := range req.Facts {
+					actions = append(actions, DecisionAction{Event: "ADD", Text: fact})
+				}
+				return DecideResponse{Actions: actions}, nil
+			},
+			DetectLanguageFunc: func(context.Context, string) (string, error) { return "en", nil },
+		}
+		resp, err := svc.Add(ctx, AddRequest{Message: "I'm in Japan until Sunday, loving the sushi", BotID: "bot-a"})
+		if err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		got := map[string]string{}
+		for _, item := range resp.Results {
+			got[item.Memory] = item.ExpiresAt
+		}
+		if got["Is travelling in Japan"] != "2099-05-13T00:00:00Z" || got["Likes sushi"] != "" {
+			t.Errorf("unexpected expiries: %v", got)
+		}
+	})
+}
+
+func TestService_SweepExpired(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := newMemStore(false)
+	revisions := &memRevisions{}
+	svc := newArchiveTestService(store, nil, "")
+	svc.SetRevisionStore(revisions)
+
+	now := time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+	filters := map[string]any{"bot_id": revisionTestBotID, "namespace": "bot", "scopeId": revisionTestBotID}
+	expired, err := svc.applyAdd(ctx, "Is travelling in Japan", withExpiry(filters, "2026-05-13T00:00:00Z"), nil, false)
+	if err != nil {
+		t.Fatalf("applyAdd failed: %v", err)
+	}
+	upcoming, err := svc.applyAdd(ctx, "Has a dentist appointment", withExpiry(filters, "2026-06-01T00:00:00Z"), nil, false)
+	if err != nil {
+		t.Fatalf("applyAdd failed: %v", err)
+	}
+	permanent, err := svc.applyAdd(ctx, "Likes sushi", filters, nil, false)
+	if err != nil {
+		t.Fatalf("applyAdd failed: %v", err)
+	}
+
+	removed, err := svc.SweepExpired(ctx, now)
+	if err != nil {
+		t.Fatalf("SweepExpired failed: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("removed %d memories, want 1", removed)
+	}
+	if _, ok := store.points[expired.ID]; ok {
+		t.Error("expired memory still stored")
+	}
+	for _, id := range []string{upcoming.ID, permanent.ID} {
+		if _, ok := store.points[id]; !ok {
+			t.Errorf("memory %s should not be swept", id)
+		}
+	}
+	history, err := svc.ListRevisions(ctx, revisionTestBotID, expired.ID, 0)
+	if err != nil {
+		t.Fatalf("ListRevisions failed: %v", err)
+	}
+	if len(history) == 0 || history[0].Action != RevisionExpire || history[0].PreviousText != "Is travelling in Japan" {
+		t.Errorf("expiry not recorded: %+v", history)
+	}
+}
+
+func TestService_ExpiredMemoriesHiddenBeforeSweep(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := newMemStore(false)
+	svc := newArchiveTestService(store, nil, "")
+
+	filters := map[string]any{"bot_id": "bot-a"}
+	expired, err := svc.applyAdd(ctx, "Is travelling in Japan", withExpiry(filters, "2020-01-01T00:00:00Z"), nil, false)
+	if err != nil {
+		t.Fatalf("applyAdd failed: %v", err)
+	}
+	kept, err := svc.applyAdd(ctx, "Likes sushi", filters, nil, false)
+	if err != nil {
+		t.Fatalf("applyAdd failed: %v", err)
+	}
+
+	resp, err := svc.GetAll(ctx, GetAllRequest{BotID: "bot-a", NoStats: true})
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].ID != kept.ID {
+		t.Fatalf("GetAll = %+v, want only %s", resp.Results, kept.ID)
+	}
+	if _, ok := store.points[expired.ID]; !ok {
+		t.Fatal("expired memory should stay stored until swept")
+	}
+
+	capture := &filterCaptureStore{memStore: store}
+	svc = newArchiveTestService(capture, nil, "")
+	if _, err := svc.Search(ctx, SearchRequest{Query: "japan", BotID: "bot-a", NoStats: true}); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if filter, ok := capture.lastFilters[expiresAtKey].(expiryFilter); !ok || filter.expired {
+		t.Fatalf("Search filters = %+v, want an unexpired filter on %s", capture.lastFilters, expiresAtKey)
+	}
+}
@@ -47,6 +47,11 @@ func NewLLMClient(log *slog.Logger, baseURL, apiKey, model string, timeout time.
 	}, nil
 }
 
+// ModelName returns the chat model this client calls.
+func (c *LLMClient) ModelName(context.Context) string {
+	return c.model
+}
+
 func (c *LLMClient) Extract(ctx context.Context, req ExtractRequest) (ExtractResponse, error) {
 	if len(req.Messages) == 0 {
 		return ExtractResponse{}, fmt.Errorf("messages is required")
@@ -0,0 +1,588 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var pgIdentifierPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,40}$`)
+
+// PgVectorStore keeps memory points in Postgres. Payloads are stored as JSONB,
+// dense vectors in a pgvector side table (one row per named vector) and the
+// BM25 sparse vector as parallel index/value arrays scored by dot product,
+// which matches the Qdrant sparse query semantics.
+//
+// Dense vectors use a dimensionless vector column so several embedding models
+// can share the table; queries are exact scans, which is fine for the small
+// deployments this store targets.
+type PgVectorStore struct {
+	pool             *pgxpool.Pool
+	pointsTable      string
+	vectorsTable     string
+	logger           *slog.Logger
+	timeout          time.Duration
+	usesNamedVectors bool
+	sparseVectorName string
+}
+
+// NewPgVectorStore opens the <table>_points and <table>_vectors tables; an
+// empty table selects the migration-managed memory_points and memory_vectors.
+func NewPgVectorStore(log *slog.Logger, pool *pgxpool.Pool, table string, vectors map[string]int, sparseVectorName string, timeout time.Duration) (*PgVectorStore, error) {
+	if pool == nil {
+		return nil, fmt.Errorf("postgres pool is required")
+	}
+	table = strings.TrimSpace(table)
+	if table == "" {
+		table = "memory"
+	}
+	if !pgIdentifierPattern.MatchString(table) {
+		return nil, fmt.Errorf("invalid pgvector table name: %q", table)
+	}
+	if strings.TrimSpace(sparseVectorName) == "" {
+		sparseVectorName = sparseHashVectorName
+	}
+	store := &PgVectorStore{
+		pool:             pool,
+		pointsTable:      table + "_points",
+		vectorsTable:     table + "_vectors",
+		logger:           log.With(slog.String("store", "pgvector")),
+		timeout:          timeoutOrDefault(timeout),
+		usesNamedVectors: len(vectors) > 0,
+		sparseVectorName: strings.TrimSpace(sparseVectorName),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+	if err := store.checkSchema(ctx); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *PgVectorStore) UsesNamedVectors() bool {
+	return s.usesNamedVectors
+}
+
+func (s *PgVectorStore) SparseVectorName() string {
+	return s.sparseVectorName
+}
+
+// checkSchema verifies that the store tables exist. They are created by
+// migration 0030_pgvector_memory rather than at startup, because creating the
+// vector extension needs a Postgres image that ships it and, usually,
+// superuser rights.
+func (s *PgVectorStore) checkSchema(ctx context.Context) error {
+	var points, vectors *string
+	if err := s.pool.QueryRow(ctx, `SELECT to_regclass($1)::text, to_regclass($2)::text`, s.pointsTable, s.vectorsTable).Scan(&points, &vectors); err != nil {
+		return fmt.Errorf("pgvector schema: %w", err)
+	}
+	if points == nil || vectors == nil {
+		return fmt.Errorf("pgvector schema: tables %s and %s not found; run migrations against a Postgres image with the vector extension (e.g. pgvector/pgvector:pg18), or if they already ran without it, re-apply them with `migrate force 29` and `migrate up`", s.pointsTable, s.vectorsTable)
+	}
+	return nil
+}
+
+func (s *PgVectorStore) Upsert(ctx context.Context, points []vectorPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	pointsTable := pgx.Identifier{s.pointsTable}.Sanitize()
+	vectorsTable := pgx.Identifier{s.vectorsTable}.Sanitize()
+	for _, point := range points {
+		vectorName := ""
+		if s.usesNamedVectors {
+			vectorName = strings.TrimSpace(point.VectorName)
+		}
+		hasDense := len(point.Vector) > 0 && (!s.usesNamedVectors || vectorName != "")
+		hasSparse := len(point.SparseIndices) > 0 && len(point.SparseValues) > 0
+		if !hasDense && !hasSparse {
+			return fmt.Errorf("no vector data provided for point %s", point.ID)
+		}
+		payload, err := json.Marshal(point.Payload)
+		if err != nil {
+			return err
+		}
+		var sparseIndices []int64
+		var sparseValues []float32
+		if hasSparse {
+			sparseIndices = make([]int64, len(point.SparseIndices))
+			for i, idx := range point.SparseIndices {
+				sparseIndices[i] = int64(idx)
+			}
+			sparseValues = point.SparseValues
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO `+pointsTable+` (id, payload, sparse_indices, sparse_values)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (id) DO UPDATE SET
+  payload = EXCLUDED.payload,
+  sparse_indices = EXCLUDED.sparse_indices,
+  sparse_values = EXCLUDED.sparse_values,
+  updated_at = now()`, point.ID, payload, sparseIndices, sparseValues); err != nil {
+			return err
+		}
+		// Upsert replaces all vectors of a point, as in Qdrant.
+		if _, err := tx.Exec(ctx, `DELETE FROM `+vectorsTable+` WHERE point_id = $1`, point.ID); err != nil {
+			return err
+		}
+		if hasDense {
+			if _, err := tx.Exec(ctx, `INSERT INTO `+vectorsTable+` (point_id, name, embedding) VALUES ($1, $2, $3::vector)`,
+				point.ID, vectorName, formatPgVector(point.Vector)); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *PgVectorStore) Search(ctx context.Context, vector []float32, limit int, filters map[string]any, vectorName string) ([]vectorPoint, []float64, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if len(vector) == 0 {
+		return nil, nil, nil
+	}
+	if !s.usesNamedVectors {
+		vectorName = ""
+	}
+	q := &pgQuery{}
+	vectorArg := q.arg(formatPgVector(vector))
+	nameArg := q.arg(vectorName)
+	dimsArg := q.arg(len(vector))
+	where := buildPgFilter(q, filters)
+	sql := `SELECT p.id::text, p.payload, 1 - (v.embedding <=> ` + vectorArg + `::vector) AS score
+FROM ` + pgx.Identifier{s.pointsTable}.Sanitize() + ` p
+JOIN ` + pgx.Identifier{s.vectorsTable}.Sanitize() + ` v ON v.point_id = p.id
+WHERE v.name = ` + nameArg + ` AND vector_dims(v.embedding) = ` + dimsArg + where + `
+ORDER BY v.embedding <=> ` + vectorArg + `::vector
+LIMIT ` + q.arg(limit)
+	return s.queryScored(ctx, sql, q.args, false)
+}
+
+func (s *PgVectorStore) SearchSparse(ctx context.Context, indices []uint32, values []float32, limit int, filters map[string]any, withSparseVectors bool) ([]vectorPoint, []float64, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if len(indices) == 0 || len(values) == 0 {
+		return nil, nil, nil
+	}
+	queryIndices := make([]int64, len(indices))
+	for i, idx := range indices {
+		queryIndices[i] = int64(idx)
+	}
+	q := &pgQuery{}
+	indicesArg := q.arg(queryIndices)
+	valuesArg := q.arg(values)
+	where := buildPgFilter(q, filters)
+	sql := `SELECT p.id::text, p.payload, SUM(qv.val * dv.val)::float8 AS score` + sparseColumns(withSparseVectors) + `
+FROM ` + pgx.Identifier{s.pointsTable}.Sanitize() + ` p
+CROSS JOIN LATERAL unnest(p.sparse_indices, p.sparse_values) AS dv(idx, val)
+JOIN unnest(` + indicesArg + `::bigint[], ` + valuesArg + `::real[]) AS qv(idx, val) ON qv.idx = dv.idx
+WHERE p.sparse_indices && ` + indicesArg + `::bigint[]` + where + `
+GROUP BY p.id
+ORDER BY score DESC
+LIMIT ` + q.arg(limit)
+	return s.queryScored(ctx, sql, q.args, withSparseVectors)
+}
+
+func (s *PgVectorStore) SearchBySources(ctx context.Context, vector []float32, limit int, filters map[string]any, sources []string, vectorName string) (map[string][]vectorPoint, map[string][]float64, error) {
+	pointsBySource := make(map[string][]vectorPoint, len(sources))
+	scoresBySource := make(map[string][]float64, len(sources))
+	for _, source := range sources {
+		merged := cloneFilters(filters)
+		if source != "" {
+			merged["source"] = source
+		}
+		points, scores, err := s.Search(ctx, vector, limit, merged, vectorName)
+		if err != nil {
+			return nil, nil, err
+		}
+		pointsBySource[source] = points
+		scoresBySource[source] = scores
+	}
+	return pointsBySource, scoresBySource, nil
+}
+
+func (s *PgVectorStore) SearchSparseBySources(ctx context.Context, indices []uint32, values []float32, limit int, filters map[string]any, sources []string, withSparseVectors bool) (map[string][]vectorPoint, map[string][]float64, error) {
+	pointsBySource := make(map[string][]vectorPoint, len(sources))
+	scoresBySource := make(map[string][]float64, len(sources))
+	for _, source := range sources {
+		merged := cloneFilters(filters)
+		if source != "" {
+			merged["source"] = source
+		}
+		points, scores, err := s.SearchSparse(ctx, indices, values, limit, merged, withSparseVectors)
+		if err != nil {
+			return nil, nil, err
+		}
+		pointsBySource[source] = points
+		scoresBySource[source] = scores
+	}
+	return pointsBySource, scoresBySource, nil
+}
+
+func (s *PgVectorStore) Get(ctx context.Context, id string) (*vectorPoint, error) {
+	row := s.pool.QueryRow(ctx, `SELECT id::text, payload FROM `+pgx.Identifier{s.pointsTable}.Sanitize()+` WHERE id = $1`, id)
+	var (
+		pointID string
+		payload []byte
+	)
+	if err := row.Scan(&pointID, &payload); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	point := vectorPoint{ID: pointID}
+	if err := unmarshalPgPayload(payload, &point); err != nil {
+		return nil, err
+	}
+	return &point, nil
+}
+
+func (s *PgVectorStore) Delete(ctx context.Context, id string) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM `+pgx.Identifier{s.pointsTable}.Sanitize()+` WHERE id = $1`, id)
+	return err
+}
+
+func (s *PgVectorStore) DeleteBatch(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := s.pool.Exec(ctx, `DELETE FROM `+pgx.Identifier{s.pointsTable}.Sanitize()+` WHERE id = ANY($1::uuid[])`, ids)
+	return err
+}
+
+func (s *PgVectorStore) DeleteAll(ctx context.Context, filters map[string]any) error {
+	q := &pgQuery{}
+	where := buildPgFilter(q, filters)
+	if where == "" {
+		return fmt.Errorf("delete all requires filters")
+	}
+	_, err := s.pool.Exec(ctx, `DELETE FROM `+pgx.Identifier{s.pointsTable}.Sanitize()+` p WHERE TRUE`+where, q.args...)
+	return err
+}
+
+func (s *PgVectorStore) List(ctx context.Context, limit int, filters map[string]any, withSparseVectors bool) ([]vectorPoint, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	q := &pgQuery{}
+	where := buildPgFilter(q, filters)
+	sql := `SELECT p.id::text, p.payload` + sparseColumns(withSparseVectors) + `
+FROM ` + pgx.Identifier{s.pointsTable}.Sanitize() + ` p
+WHERE TRUE` + where + `
+ORDER BY p.id
+LIMIT ` + q.arg(limit)
+	points, _, err := s.queryPoints(ctx, sql, q.args, false, withSparseVectors)
+	return points, err
+}
+
+func (s *PgVectorStore) Scroll(ctx context.Context, limit int, filters map[string]any, offset string) ([]vectorPoint, string, error) {
+	return s.scroll(ctx, limit, filters, offset, false)
+}
+
+func (s *PgVectorStore) ScrollVectors(ctx context.Context, limit int, filters map[string]any, offset string) ([]vectorPoint, string, error) {
+	return s.scroll(ctx, limit, filters, offset, true)
+}
+
+func (s *PgVectorStore) scroll(ctx context.Context, limit int, filters map[string]any, offset string, withVectors bool) ([]vectorPoint, string, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	q := &pgQuery{}
+	where := buildPgFilter(q, filters)
+	if offset != "" {
+		where += ` AND p.id >= ` + q.arg(offset) + `::uuid`
+	}
+	columns, join := "", ""
+	if withVectors {
+		columns = ", v.name, v.embedding::text"
+		join = `
+LEFT JOIN LATERAL (
+  SELECT name, embedding FROM ` + pgx.Identifier{s.vectorsTable}.Sanitize() + ` WHERE point_id = p.id ORDER BY name LIMIT 1
+) v ON TRUE`
+	}
+	// Fetch one extra row to find the next offset, as Qdrant does.
+	sql := `SELECT p.id::text, p.payload` + columns + `
+FROM ` + pgx.Identifier{s.pointsTable}.Sanitize() + ` p` + join + `
+WHERE TRUE` + where + `
+ORDER BY p.id
+LIMIT ` + q.arg(limit+1)
+	rows, err := s.pool.Query(ctx, sql, q.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+	var points []vectorPoint
+	for rows.Next() {
+		var (
+			point      vectorPoint
+			payload    []byte
+			vectorName *string
+			vectorText *string
+		)
+		dest := []any{&point.ID, &payload}
+		if withVectors {
+			dest = append(dest, &vectorName, &vectorText)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, "", err
+		}
+		if err := unmarshalPgPayload(payload, &point); err != nil {
+			return nil, "", err
+		}
+		if vectorText != nil {
+			vector, err := parsePgVector(*vectorText)
+			if err != nil {
+				return nil, "", fmt.Errorf("decode vector for point %s: %w", point.ID, err)
+			}
+			point.Vector = vector
+			if vectorName != nil {
+				point.VectorName = *vectorName
+			}
+		}
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+	if len(points) > limit {
+		return points[:limit], points[limit].ID, nil
+	}
+	return points, "", nil
+}
+
+func (s *PgVectorStore) Count(ctx context.Context, filters map[string]any) (uint64, error) {
+	q := &pgQuery{}
+	where := buildPgFilter(q, filters)
+	var count int64
+	if err := s.pool.QueryRow(ctx, `SELECT count(*) FROM `+pgx.Identifier{s.pointsTable}.Sanitize()+` p WHERE TRUE`+where, q.args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return uint64(count), nil
+}
+
+func (s *PgVectorStore) queryScored(ctx context.Context, sql string, args []any, withSparseVectors bool) ([]vectorPoint, []float64, error) {
+	return s.queryPoints(ctx, sql, args, true, withSparseVectors)
+}
+
+// queryPoints scans rows of (id, payload[, score][, sparse_indices, sparse_values]).
+func (s *PgVectorStore) queryPoints(ctx context.Context, sql string, args []any, withScore, withSparseVectors bool) ([]vectorPoint, []float64, error) {
+	rows, err := s.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var (
+		points []vectorPoint
+		scores []float64
+	)
+	for rows.Next() {
+		var (
+			point         vectorPoint
+			payload       []byte
+			score         float64
+			sparseIndices []int64
+			sparseValues  []float32
+		)
+		dest := []any{&point.ID, &payload}
+		if withScore {
+			dest = append(dest, &score)
+		}
+		if withSparseVectors {
+			dest = append(dest, &sparseIndices, &sparseValues)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, nil, err
+		}
+		if err := unmarshalPgPayload(payload, &point); err != nil {
+			return nil, nil, err
+		}
+		if withSparseVectors && len(sparseIndices) > 0 {
+			point.SparseIndices = make([]uint32, len(sparseIndices))
+			for i, idx := range sparseIndices {
+				point.SparseIndices[i] = uint32(idx)
+			}
+			point.SparseValues = sparseValues
+			point.SparseVectorName = s.sparseVectorName
+		}
+		points = append(points, point)
+		if withScore {
+			scores = append(scores, score)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return points, scores, nil
+}
+
+func sparseColumns(withSparseVectors bool) string {
+	if !withSparseVectors {
+		return ""
+	}
+	return ", p.sparse_indices, p.sparse_values"
+}
+
+func unmarshalPgPayload(raw []byte, point *vectorPoint) error {
+	point.Payload = map[string]any{}
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, &point.Payload); err != nil {
+		return fmt.Errorf("decode payload for point %s: %w", point.ID, err)
+	}
+	return nil
+}
+
+// formatPgVector renders a vector in pgvector's text input format.
+func formatPgVector(vector []float32) string {
+	var b strings.Builder
+	b.Grow(len(vector)*10 + 2)
+	b.WriteByte('[')
+	for i, v := range vector {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// parsePgVector parses pgvector's text output format.
+func parsePgVector(text string) ([]float32, error) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "[") || !strings.HasSuffix(text, "]") {
+		return nil, fmt.Errorf("invalid vector literal")
+	}
+	body := strings.TrimSpace(text[1 : len(text)-1])
+	if body == "" {
+		return []float32{}, nil
+	}
+	parts := strings.Split(body, ",")
+	vector := make([]float32, len(parts))
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return nil, err
+		}
+		vector[i] = float32(v)
+	}
+	return vector, nil
+}
+
+// pgQuery accumulates positional arguments for a dynamically built query.
+type pgQuery struct {
+	args []any
+}
+
+func (q *pgQuery) arg(value any) string {
+	q.args = append(q.args, value)
+	return "$" + strconv.Itoa(len(q.args))
+}
+
+// buildPgFilter translates payload filters into " AND ..." clauses against
+// the points table aliased as p. It mirrors buildQdrantCondition: strings,
+// bools and integers match exactly, floats and {gte,gt,lte,lt} maps become
+// numeric ranges, anything else is matched by its string form.
+// pgIndexedKeys are payload keys with an expression index in migration
+// 0030_pgvector_memory. Equality on them is written with the literal key so the
+// planner can match the index; a parameterized path cannot.
+var pgIndexedKeys = map[string]string{
+	"bot_id": "p.payload->>'bot_id'",
+	"run_id": "p.payload->>'run_id'",
+}
+
+func buildPgFilter(q *pgQuery, filters map[string]any) string {
+	if len(filters) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		if expr, ok := pgIndexedKeys[key]; ok {
+			if value, ok := filters[key].(string); ok {
+				b.WriteString(" AND " + expr + " = " + q.arg(value))
+				continue
+			}
+		}
+		path := q.arg(strings.Split(key, ".")) + "::text[]"
+		switch typed := filters[key].(type) {
+		case string:
+			b.WriteString(" AND p.payload #>> " + path + " = " + q.arg(typed))
+		case bool:
+			b.WriteString(" AND p.payload #> " + path + " = to_jsonb(" + q.arg(typed) + "::boolean)")
+		case int:
+			b.WriteString(" AND p.payload #> " + path + " = to_jsonb(" + q.arg(int64(typed)) + "::bigint)")
+		case int64:
+			b.WriteString(" AND p.payload #> " + path + " = to_jsonb(" + q.arg(typed) + "::bigint)")
+		case float32, float64:
+			v, _ := toFloat(typed)
+			b.WriteString(" AND " + pgNumeric(path) + " = " + q.arg(v))
+		case expiryFilter:
+			// Expiries are stored by formatExpiry, so byte order is time order.
+			expired := "(p.payload #>> " + path + ") COLLATE \"C\" <= " + q.arg(formatExpiry(typed.at))
+			if typed.expired {
+				b.WriteString(" AND " + expired)
+			} else {
+				b.WriteString(" AND NOT COALESCE(" + expired + ", FALSE)")
+			}
+		case map[string]any:
+			clause := buildPgRange(q, path, typed)
+			if clause == "" {
+				b.WriteString(" AND p.payload #>> " + path + " = " + q.arg(fmt.Sprint(typed)))
+				continue
+			}
+			b.WriteString(clause)
+		default:
+			b.WriteString(" AND p.payload #>> " + path + " = " + q.arg(fmt.Sprint(typed)))
+		}
+	}
+	return b.String()
+}
+
+func buildPgRange(q *pgQuery, path string, ops map[string]any) string {
+	var b strings.Builder
+	for _, op := range []string{"gte", "gt", "lte", "lt"} {
+		raw, ok := ops[op]
+		if !ok {
+			continue
+		}
+		val, ok := toFloat(raw)
+		if !ok {
+			continue
+		}
+		sqlOp := map[string]string{"gte": ">=", "gt": ">", "lte": "<=", "lt": "<"}[op]
+		b.WriteString(" AND " + pgNumeric(path) + " " + sqlOp + " " + q.arg(val))
+	}
+	return b.String()
+}
+
+// pgNumeric extracts a payload number, yielding NULL for non-numeric values
+// so that comparisons fail instead of raising cast errors.
+func pgNumeric(path string) string {
+	return "(CASE WHEN jsonb_typeof(p.payload #> " + path + ") = 'number' THEN (p.payload #>> " + path + ")::float8 END)"
+}
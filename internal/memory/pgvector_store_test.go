@@ -0,0 +1,303 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestBuildPgFilter(t *testing.T) {
+	t.Parallel()
+
+	q := &pgQuery{}
+	where := buildPgFilter(q, map[string]any{
+		"bot_id":         "b1",
+		"metadata.score": map[string]any{"gte": 0.5, "lt": 1},
+		"pinned":         true,
+	})
+	want := " AND p.payload->>'bot_id' = $1" +
+		" AND (CASE WHEN jsonb_typeof(p.payload #> $2::text[]) = 'number' THEN (p.payload #>> $2::text[])::float8 END) >= $3" +
+		" AND (CASE WHEN jsonb_typeof(p.payload #> $2::text[]) = 'number' THEN (p.payload #>> $2::text[])::float8 END) < $4" +
+		" AND p.payload #> $5::text[] = to_jsonb($6::boolean)"
+	if where != want {
+		t.Fatalf("where =\n%s\nwant\n%s", where, want)
+	}
+	wantArgs := []any{"b1", []string{"metadata", "score"}, 0.5, 1.0, []string{"pinned"}, true}
+	if !reflect.DeepEqual(q.args, wantArgs) {
+		t.Fatalf("args = %#v, want %#v", q.args, wantArgs)
+	}
+
+	if got := buildPgFilter(&pgQuery{}, nil); got != "" {
+		t.Fatalf("empty filters should produce no clause, got %q", got)
+	}
+}
+
+func TestBuildPgFilter_Expiry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+	q := &pgQuery{}
+	if got, want := buildPgFilter(q, map[string]any{expiresAtKey: expiredAt(now)}),
+		` AND (p.payload #>> $1::text[]) COLLATE "C" <= $2`; got != want {
+		t.Fatalf("expired where =\n%s\nwant\n%s", got, want)
+	}
+	if !reflect.DeepEqual(q.args, []any{[]string{expiresAtKey}, "2026-05-20T12:00:00Z"}) {
+		t.Fatalf("args = %#v", q.args)
+	}
+	if got, want := buildPgFilter(&pgQuery{}, map[string]any{expiresAtKey: unexpiredAt(now)}),
+		` AND NOT COALESCE((p.payload #>> $1::text[]) COLLATE "C" <= $2, FALSE)`; got != want {
+		t.Fatalf("unexpired where =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFormatPgVector(t *testing.T) {
+	t.Parallel()
+
+	if got := formatPgVector([]float32{0.25, -1, 3.5e-7}); got != "[0.25,-1,0.00000035]" {
+		t.Fatalf("formatPgVector = %q", got)
+	}
+	if got := formatPgVector(nil); got != "[]" {
+		t.Fatalf("formatPgVector(nil) = %q", got)
+	}
+}
+
+// newPgVectorTestStore opens a PgVectorStore on throwaway tables shaped like
+// those of migration 0030_pgvector_memory. It needs TEST_POSTGRES_DSN pointing
+// at a database with the vector extension available.
+func newPgVectorTestStore(t *testing.T) *PgVectorStore {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("skip integration test: TEST_POSTGRES_DSN is not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Skipf("skip integration test: cannot connect to database: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	if err := pool.Ping(ctx); err != nil {
+		t.Skipf("skip integration test: database ping failed: %v", err)
+	}
+	if _, err := pool.Exec(ctx, `CREATE EXTENSION IF NOT EXISTS vector`); err != nil {
+		t.Skipf("skip integration test: vector extension unavailable: %v", err)
+	}
+
+	table := fmt.Sprintf("memtest_%d", time.Now().UnixNano())
+	points := pgx.Identifier{table + "_points"}.Sanitize()
+	vectors := pgx.Identifier{table + "_vectors"}.Sanitize()
+	for _, stmt := range []string{
+		`CREATE TABLE ` + points + ` (
+  id UUID PRIMARY KEY,
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  sparse_indices BIGINT[],
+  sparse_values REAL[],
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`,
+		`CREATE TABLE ` + vectors + ` (
+  point_id UUID NOT NULL REFERENCES ` + points + `(id) ON DELETE CASCADE,
+  name TEXT NOT NULL DEFAULT '',
+  embedding vector NOT NULL,
+  PRIMARY KEY (point_id, name)
+)`,
+	} {
+		if _, err := pool.Exec(ctx, stmt); err != nil {
+			t.Fatalf("create test table: %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(context.Background(), `DROP TABLE IF EXISTS `+vectors+`, `+points)
+	})
+
+	store, err := NewPgVectorStore(slog.Default(), pool, table, nil, "", 10*time.Second)
+	if err != nil {
+		t.Fatalf("NewPgVectorStore: %v", err)
+	}
+	return store
+}
+
+func pgTestID(i int) string {
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", i)
+}
+
+func TestPgVectorStore_MissingTables(t *testing.T) {
+	store := newPgVectorTestStore(t)
+
+	if _, err := NewPgVectorStore(slog.Default(), store.pool, "memtest_missing", nil, "", 10*time.Second); err == nil {
+		t.Fatal("expected an error when the tables do not exist")
+	}
+}
+
+func TestPgVectorStore_AddAndSearch(t *testing.T) {
+	store := newPgVectorTestStore(t)
+	ctx := context.Background()
+
+	svc := newArchiveTestService(store, nil, "")
+	infer := false
+	added, err := svc.Add(ctx, AddRequest{
+		Message: "User likes the Go programming language",
+		BotID:   "bot-a",
+		Infer:   &infer,
+	})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if len(added.Results) != 1 {
+		t.Fatalf("Add returned %d results, want 1", len(added.Results))
+	}
+	got, err := store.Get(ctx, added.Results[0].ID)
+	if err != nil || got == nil {
+		t.Fatalf("Get after Add = %v, %v", got, err)
+	}
+	if got.Payload["bot_id"] != "bot-a" {
+		t.Fatalf("stored bot_id = %v, want bot-a", got.Payload["bot_id"])
+	}
+
+	resp, err := svc.Search(ctx, SearchRequest{Query: "Go programming", BotID: "bot-a"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].ID != added.Results[0].ID {
+		t.Fatalf("Search results = %+v, want the added memory", resp.Results)
+	}
+	resp, err = svc.Search(ctx, SearchRequest{Query: "Go programming", BotID: "bot-b"})
+	if err != nil {
+		t.Fatalf("Search other bot: %v", err)
+	}
+	if len(resp.Results) != 0 {
+		t.Fatalf("Search leaked memories across bots: %+v", resp.Results)
+	}
+}
+
+func TestPgVectorStore_SearchDense(t *testing.T) {
+	store := newPgVectorTestStore(t)
+	ctx := context.Background()
+
+	if err := store.Upsert(ctx, []vectorPoint{
+		{ID: pgTestID(1), Vector: []float32{1, 0, 0}, Payload: map[string]any{"bot_id": "bot-a", "data": "x"}},
+		{ID: pgTestID(2), Vector: []float32{0, 1, 0}, Payload: map[string]any{"bot_id": "bot-a", "data": "y"}},
+		{ID: pgTestID(3), Vector: []float32{1, 0, 0}, Payload: map[string]any{"bot_id": "bot-b", "data": "z"}},
+		{ID: pgTestID(4), Vector: []float32{1, 0}, Payload: map[string]any{"bot_id": "bot-a", "data": "other model"}},
+	}); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	points, scores, err := store.Search(ctx, []float32{0.9, 0.1, 0}, 10, map[string]any{"bot_id": "bot-a"}, "")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(points) != 2 || points[0].ID != pgTestID(1) || points[1].ID != pgTestID(2) {
+		t.Fatalf("Search order = %+v, want points 1 then 2", points)
+	}
+	if len(scores) != 2 || scores[0] <= scores[1] {
+		t.Fatalf("scores = %v, want descending", scores)
+	}
+
+	points, _, err = store.Search(ctx, []float32{0.9, 0.1, 0}, 1, map[string]any{"bot_id": "bot-a"}, "")
+	if err != nil {
+		t.Fatalf("Search limit: %v", err)
+	}
+	if len(points) != 1 {
+		t.Fatalf("Search limit 1 returned %d points", len(points))
+	}
+}
+
+func TestPgVectorStore_Scroll(t *testing.T) {
+	store := newPgVectorTestStore(t)
+	ctx := context.Background()
+
+	var batch []vectorPoint
+	for i := 1; i <= 5; i++ {
+		batch = append(batch, vectorPoint{
+			ID:      pgTestID(i),
+			Vector:  []float32{float32(i), 1},
+			Payload: map[string]any{"bot_id": "bot-a"},
+		})
+	}
+	batch = append(batch, vectorPoint{ID: pgTestID(9), Vector: []float32{1, 1}, Payload: map[string]any{"bot_id": "bot-b"}})
+	if err := store.Upsert(ctx, batch); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	var seen []string
+	offset := ""
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("scroll did not terminate")
+		}
+		points, next, err := store.ScrollVectors(ctx, 2, map[string]any{"bot_id": "bot-a"}, offset)
+		if err != nil {
+			t.Fatalf("ScrollVectors: %v", err)
+		}
+		for _, point := range points {
+			if len(point.Vector) != 2 {
+				t.Fatalf("point %s vector = %v, want 2 dimensions", point.ID, point.Vector)
+			}
+			seen = append(seen, point.ID)
+		}
+		if next == "" {
+			break
+		}
+		offset = next
+	}
+	want := []string{pgTestID(1), pgTestID(2), pgTestID(3), pgTestID(4), pgTestID(5)}
+	if !reflect.DeepEqual(seen, want) {
+		t.Fatalf("scrolled ids = %v, want %v", seen, want)
+	}
+
+	count, err := store.Count(ctx, map[string]any{"bot_id": "bot-a"})
+	if err != nil || count != 5 {
+		t.Fatalf("Count = %d, %v; want 5", count, err)
+	}
+}
+
+func TestPgVectorStore_Delete(t *testing.T) {
+	store := newPgVectorTestStore(t)
+	ctx := context.Background()
+
+	var batch []vectorPoint
+	for i := 1; i <= 4; i++ {
+		batch = append(batch, vectorPoint{ID: pgTestID(i), Vector: []float32{1, 1}, Payload: map[string]any{"bot_id": "bot-a"}})
+	}
+	batch = append(batch, vectorPoint{ID: pgTestID(5), Vector: []float32{1, 1}, Payload: map[string]any{"bot_id": "bot-b"}})
+	if err := store.Upsert(ctx, batch); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	if err := store.Delete(ctx, pgTestID(1)); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if got, err := store.Get(ctx, pgTestID(1)); err != nil || got != nil {
+		t.Fatalf("Get after Delete = %v, %v; want nil", got, err)
+	}
+	var vectors int
+	if err := store.pool.QueryRow(ctx, `SELECT count(*) FROM `+pgx.Identifier{store.vectorsTable}.Sanitize()+` WHERE point_id = $1`, pgTestID(1)).Scan(&vectors); err != nil || vectors != 0 {
+		t.Fatalf("vectors left after Delete = %d, %v", vectors, err)
+	}
+
+	if err := store.DeleteBatch(ctx, []string{pgTestID(2), pgTestID(3)}); err != nil {
+		t.Fatalf("DeleteBatch: %v", err)
+	}
+	if count, err := store.Count(ctx, map[string]any{"bot_id": "bot-a"}); err != nil || count != 1 {
+		t.Fatalf("Count after DeleteBatch = %d, %v; want 1", count, err)
+	}
+
+	if err := store.DeleteAll(ctx, nil); err == nil {
+		t.Fatal("DeleteAll without filters should fail")
+	}
+	if err := store.DeleteAll(ctx, map[string]any{"bot_id": "bot-a"}); err != nil {
+		t.Fatalf("DeleteAll: %v", err)
+	}
+	if count, err := store.Count(ctx, nil); err != nil || count != 1 {
+		t.Fatalf("Count after DeleteAll = %d, %v; want only bot-b left", count, err)
+	}
+}
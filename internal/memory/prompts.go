@@ -44,7 +44,10 @@ Output: {"facts" : ["Name is John", "Is a Software engineer"]}
 Input: Me favourite movies are Inception and Interstellar.
 Output: {"facts" : ["Favourite movies are Inception and Interstellar"]}
 
-Return the facts and preferences in a JSON format as shown above. You MUST return a valid JSON object with a 'facts' key containing an array of strings.
+Input: I'm travelling in Japan until Sunday, so reply slowly. (Today is 2024-05-08.)
+Output: {"facts" : ["Is travelling in Japan"], "expirations" : {"Is travelling in Japan": "2024-05-12"}}
+
+Return the facts and preferences in a JSON format as shown above. You MUST return a valid JSON object with a 'facts' key containing an array of strings, and an optional 'expirations' object.
 
 Remember the following:
 - Today's date is %s.
@@ -58,6 +61,7 @@ Remember the following:
 - DO NOT ADD ANY ADDITIONAL TEXT OR CODEBLOCK IN THE JSON FIELDS WHICH MAKE IT INVALID SUCH AS "%s" OR "%s".
 - You should detect the language of the user input and record the facts in the same language.
 - For basic factual statements, break them down into individual facts if they contain multiple pieces of information.
+- If a fact only holds for a limited time (a trip, a temporary situation, a deadline), add it to the "expirations" object, mapping the exact fact string to the last date (YYYY-MM-DD) on which it holds. Omit facts that do not expire.
 
 Following is a conversation between the user and the assistant. You have to extract the relevant facts and preferences about the user, if any, from the conversation and return them in the JSON format as shown above.
 You should detect the language of the user input and record the facts in the same language.
@@ -126,7 +130,7 @@ Guidelines:
 4. Each output fact should be a single, self-contained statement.
 5. Target approximately %d output facts (but use fewer if the information naturally consolidates to less, and never produce more than the input count).
 6. Keep the same language as the original memories. Do not translate.
-7. Return a JSON object with a single key "facts" containing an array of strings.
+7. Return a JSON object with a key "facts" containing an array of strings, and a key "sources" containing, for each fact in the same order, the array of input memory ids merged into it.
 8. DO NOT RETURN ANYTHING ELSE OTHER THAN THE JSON FORMAT.
 9. DO NOT ADD ANY ADDITIONAL TEXT OR CODEBLOCK IN THE JSON FIELDS WHICH MAKE IT INVALID SUCH AS "%s" OR "%s".%s
 
@@ -135,7 +139,7 @@ Input memories:
 [{"id":"1","text":"User likes dark mode","created_at":"2026-01-01"},{"id":"2","text":"User prefers dark theme for all apps","created_at":"2026-02-10"},{"id":"3","text":"User is a software engineer","created_at":"2026-01-15"},{"id":"4","text":"User works as a developer","created_at":"2026-02-01"}]
 Target: 2
 
-Output: {"facts": ["User prefers dark theme for all apps", "User is a software engineer"]}
+Output: {"facts": ["User prefers dark theme for all apps", "User is a software engineer"], "sources": [["1", "2"], ["3", "4"]]}
 `, targetCount, "```json", "```", decayInstruction)
 
 	userPrompt := fmt.Sprintf("Consolidate the following memories into approximately %d concise facts:\n\n%s", targetCount, toJSON(memories))
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/qdrant/go-client/qdrant"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
@@ -33,7 +35,7 @@ type QdrantStore struct {
 	usesSparseVectors bool
 }
 
-type qdrantPoint struct {
+type vectorPoint struct {
 	ID               string         `json:"id"`
 	Vector           []float32      `json:"vector"`
 	VectorName       string         `json:"vector_name,omitempty"`
@@ -140,7 +142,17 @@ func NewQdrantStoreWithVectors(log *slog.Logger, baseURL, apiKey, collection str
 	return store, nil
 }
 
-func (s *QdrantStore) Upsert(ctx context.Context, points []qdrantPoint) error {
+// UsesNamedVectors reports whether dense vectors are keyed by embedding model.
+func (s *QdrantStore) UsesNamedVectors() bool {
+	return s.usesNamedVectors
+}
+
+// SparseVectorName returns the name of the BM25 sparse vector.
+func (s *QdrantStore) SparseVectorName() string {
+	return s.sparseVectorName
+}
+
+func (s *QdrantStore) Upsert(ctx context.Context, points []vectorPoint) error {
 	if len(points) == 0 {
 		return nil
 	}
@@ -191,7 +203,7 @@ func (s *QdrantStore) Upsert(ctx context.Context, points []qdrantPoint) error {
 	return err
 }
 
-func (s *QdrantStore) Search(ctx context.Context, vector []float32, limit int, filters map[string]any, vectorName string) ([]qdrantPoint, []float64, error) {
+func (s *QdrantStore) Search(ctx context.Context, vector []float32, limit int, filters map[string]any, vectorName string) ([]vectorPoint, []float64, error) {
 	if limit <= 0 {
 		limit = 10
 	}
@@ -212,10 +224,10 @@ func (s *QdrantStore) Search(ctx context.Context, vector []float32, limit int, f
 		return nil, nil, err
 	}
 
-	points := make([]qdrantPoint, 0, len(results))
+	points := make([]vectorPoint, 0, len(results))
 	scores := make([]float64, 0, len(results))
 	for _, scored := range results {
-		points = append(points, qdrantPoint{
+		points = append(points, vectorPoint{
 			ID:      pointIDToString(scored.GetId()),
 			Payload: valueMapToInterface(scored.GetPayload()),
 		})
@@ -224,7 +236,7 @@ func (s *QdrantStore) Search(ctx context.Context, vector []float32, limit int, f
 	return points, scores, nil
 }
 
-func (s *QdrantStore) SearchSparse(ctx context.Context, indices []uint32, values []float32, limit int, filters map[string]any, withSparseVectors bool) ([]qdrantPoint, []float64, error) {
+func (s *QdrantStore) SearchSparse(ctx context.Context, indices []uint32, values []float32, limit int, filters map[string]any, withSparseVectors bool) ([]vectorPoint, []float64, error) {
 	if limit <= 0 {
 		limit = 10
 	}
@@ -251,10 +263,10 @@ func (s *QdrantStore) SearchSparse(ctx context.Context, indices []uint32, values
 	if err != nil {
 		return nil, nil, err
 	}
-	points := make([]qdrantPoint, 0, len(results))
+	points := make([]vectorPoint, 0, len(results))
 	scores := make([]float64, 0, len(results))
 	for _, scored := range results {
-		p := qdrantPoint{
+		p := vectorPoint{
 			ID:      pointIDToString(scored.GetId()),
 			Payload: valueMapToInterface(scored.GetPayload()),
 		}
@@ -267,8 +279,8 @@ func (s *QdrantStore) SearchSparse(ctx context.Context, indices []uint32, values
 	return points, scores, nil
 }
 
-func (s *QdrantStore) SearchBySources(ctx context.Context, vector []float32, limit int, filters map[string]any, sources []string, vectorName string) (map[string][]qdrantPoint, map[string][]float64, error) {
-	pointsBySource := make(map[string][]qdrantPoint, len(sources))
+func (s *QdrantStore) SearchBySources(ctx context.Context, vector []float32, limit int, filters map[string]any, sources []string, vectorName string) (map[string][]vectorPoint, map[string][]float64, error) {
+	pointsBySource := make(map[string][]vectorPoint, len(sources))
 	scoresBySource := make(map[string][]float64, len(sources))
 	if len(sources) == 0 {
 		return pointsBySource, scoresBySource, nil
@@ -288,8 +300,8 @@ func (s *QdrantStore) SearchBySources(ctx context.Context, vector []float32, lim
 	return pointsBySource, scoresBySource, nil
 }
 
-func (s *QdrantStore) SearchSparseBySources(ctx context.Context, indices []uint32, values []float32, limit int, filters map[string]any, sources []string, withSparseVectors bool) (map[string][]qdrantPoint, map[string][]float64, error) {
-	pointsBySource := make(map[string][]qdrantPoint, len(sources))
+func (s *QdrantStore) SearchSparseBySources(ctx context.Context, indices []uint32, values []float32, limit int, filters map[string]any, sources []string, withSparseVectors bool) (map[string][]vectorPoint, map[string][]float64, error) {
+	pointsBySource := make(map[string][]vectorPoint, len(sources))
 	scoresBySource := make(map[string][]float64, len(sources))
 	if len(sources) == 0 {
 		return pointsBySource, scoresBySource, nil
@@ -309,7 +321,7 @@ func (s *QdrantStore) SearchSparseBySources(ctx context.Context, indices []uint3
 	return pointsBySource, scoresBySource, nil
 }
 
-func (s *QdrantStore) Get(ctx context.Context, id string) (*qdrantPoint, error) {
+func (s *QdrantStore) Get(ctx context.Context, id string) (*vectorPoint, error) {
 	result, err := s.client.Get(ctx, &qdrant.GetPoints{
 		CollectionName: s.collection,
 		Ids:            []*qdrant.PointId{qdrant.NewIDUUID(id)},
@@ -322,7 +334,7 @@ func (s *QdrantStore) Get(ctx context.Context, id string) (*qdrantPoint, error)
 		return nil, nil
 	}
 	point := result[0]
-	return &qdrantPoint{
+	return &vectorPoint{
 		ID:      pointIDToString(point.GetId()),
 		Payload: valueMapToInterface(point.GetPayload()),
 	}, nil
@@ -353,7 +365,7 @@ func (s *QdrantStore) DeleteBatch(ctx context.Context, ids []string) error {
 	return err
 }
 
-func (s *QdrantStore) List(ctx context.Context, limit int, filters map[string]any, withSparseVectors bool) ([]qdrantPoint, error) {
+func (s *QdrantStore) List(ctx context.Context, limit int, filters map[string]any, withSparseVectors bool) ([]vectorPoint, error) {
 	if limit <= 0 {
 		limit = 100
 	}
@@ -372,9 +384,9 @@ func (s *QdrantStore) List(ctx context.Context, limit int, filters map[string]an
 		return nil, err
 	}
 
-	result := make([]qdrantPoint, 0, len(points))
+	result := make([]vectorPoint, 0, len(points))
 	for _, point := range points {
-		p := qdrantPoint{
+		p := vectorPoint{
 			ID:      pointIDToString(point.GetId()),
 			Payload: valueMapToInterface(point.GetPayload()),
 		}
@@ -386,29 +398,90 @@ func (s *QdrantStore) List(ctx context.Context, limit int, filters map[string]an
 	return result, nil
 }
 
-func (s *QdrantStore) Scroll(ctx context.Context, limit int, filters map[string]any, offset *qdrant.PointId) ([]qdrantPoint, *qdrant.PointId, error) {
+func (s *QdrantStore) Scroll(ctx context.Context, limit int, filters map[string]any, offset string) ([]vectorPoint, string, error) {
+	return s.scroll(ctx, limit, filters, offset, false)
+}
+
+// ScrollVectors is Scroll that also returns each point's dense vector.
+func (s *QdrantStore) ScrollVectors(ctx context.Context, limit int, filters map[string]any, offset string) ([]vectorPoint, string, error) {
+	return s.scroll(ctx, limit, filters, offset, true)
+}
+
+func (s *QdrantStore) scroll(ctx context.Context, limit int, filters map[string]any, offset string, withVectors bool) ([]vectorPoint, string, error) {
 	if limit <= 0 {
 		limit = 100
 	}
 	filter := buildQdrantFilter(filters)
-	points, nextOffset, err := s.client.ScrollAndOffset(ctx, &qdrant.ScrollPoints{
+	var offsetID *qdrant.PointId
+	if offset != "" {
+		offsetID = qdrant.NewIDUUID(offset)
+	}
+	scroll := &qdrant.ScrollPoints{
 		CollectionName: s.collection,
 		Limit:          qdrant.PtrOf(uint32(limit)),
 		Filter:         filter,
-		Offset:         offset,
+		Offset:         offsetID,
 		WithPayload:    qdrant.NewWithPayload(true),
-	})
+	}
+	if withVectors {
+		scroll.WithVectors = qdrant.NewWithVectors(true)
+	}
+	points, nextOffset, err := s.client.ScrollAndOffset(ctx, scroll)
 	if err != nil {
-		return nil, nil, err
+		return nil, "", err
 	}
-	result := make([]qdrantPoint, 0, len(points))
+	result := make([]vectorPoint, 0, len(points))
 	for _, point := range points {
-		result = append(result, qdrantPoint{
+		p := vectorPoint{
 			ID:      pointIDToString(point.GetId()),
 			Payload: valueMapToInterface(point.GetPayload()),
-		})
+		}
+		if withVectors {
+			p.VectorName, p.Vector = s.extractDenseVector(point.GetVectors())
+		}
+		result = append(result, p)
+	}
+	return result, pointIDToString(nextOffset), nil
+}
+
+// extractDenseVector returns the first dense vector of a point (by name order)
+// skipping the sparse BM25 vectors.
+func (s *QdrantStore) extractDenseVector(vectors *qdrant.VectorsOutput) (string, []float32) {
+	if vectors == nil {
+		return "", nil
+	}
+	if vecOut := vectors.GetVector(); vecOut != nil {
+		return "", denseData(vecOut)
+	}
+	named := vectors.GetVectors().GetVectors()
+	names := make([]string, 0, len(named))
+	for name := range named {
+		if name == s.sparseVectorName || name == sparseVocabVectorName {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if data := denseData(named[name]); len(data) > 0 {
+			return name, data
+		}
 	}
-	return result, nextOffset, nil
+	return "", nil
+}
+
+func denseData(vecOut *qdrant.VectorOutput) []float32 {
+	if vecOut == nil || vecOut.GetSparse() != nil {
+		return nil
+	}
+	if dense := vecOut.GetDense(); dense != nil {
+		return dense.GetData()
+	}
+	// Deprecated flat field used by older Qdrant servers.
+	if vecOut.GetIndices() == nil {
+		return vecOut.GetData()
+	}
+	return nil
 }
 
 // extractSparseVector extracts sparse indices and values from a VectorsOutput.
@@ -581,13 +654,21 @@ func (s *QdrantStore) ensurePayloadIndexes(ctx context.Context) error {
 	if s.client == nil {
 		return nil
 	}
-	fields := []string{"bot_id", "run_id"}
+	// expires_at is indexed as a datetime so expiry filters avoid full scans.
+	fields := []struct {
+		name      string
+		fieldType qdrant.FieldType
+	}{
+		{name: "bot_id", fieldType: qdrant.FieldType_FieldTypeKeyword},
+		{name: "run_id", fieldType: qdrant.FieldType_FieldTypeKeyword},
+		{name: expiresAtKey, fieldType: qdrant.FieldType_FieldTypeDatetime},
+	}
 	wait := true
 	for _, field := range fields {
 		_, err := s.client.CreateFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
 			CollectionName: s.collection,
-			FieldName:      field,
-			FieldType:      qdrant.FieldType_FieldTypeKeyword.Enum(),
+			FieldName:      field.name,
+			FieldType:      field.fieldType.Enum(),
 			Wait:           &wait,
 		})
 		if err == nil {
@@ -697,6 +778,12 @@ func buildQdrantCondition(key string, value any) *qdrant.Condition {
 		return qdrant.NewRange(key, &qdrant.Range{Gte: &v, Lte: &v})
 	case float64:
 		return qdrant.NewRange(key, &qdrant.Range{Gte: &typed, Lte: &typed})
+	case expiryFilter:
+		expired := qdrant.NewDatetimeRange(key, &qdrant.DatetimeRange{Lte: timestamppb.New(typed.at)})
+		if typed.expired {
+			return expired
+		}
+		return qdrant.NewFilterAsCondition(&qdrant.Filter{MustNot: []*qdrant.Condition{expired}})
 	case map[string]any:
 		rangeValue := &qdrant.Range{}
 		for _, op := range []string{"gte", "gt", "lte", "lt"} {
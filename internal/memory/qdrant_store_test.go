@@ -1,6 +1,9 @@
 package memory
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestBuildQdrantFilter(t *testing.T) {
 	t.Parallel()
@@ -16,3 +19,18 @@ func TestBuildQdrantFilter(t *testing.T) {
 		t.Fatalf("expected two conditions, got %d", len(filter.Must))
 	}
 }
+
+func TestBuildQdrantCondition_Expiry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+	expired := buildQdrantCondition(expiresAtKey, expiredAt(now)).GetField()
+	if expired == nil || expired.GetKey() != expiresAtKey || !expired.GetDatetimeRange().GetLte().AsTime().Equal(now) {
+		t.Fatalf("expired condition = %+v, want a datetime range lte now", expired)
+	}
+
+	mustNot := buildQdrantCondition(expiresAtKey, unexpiredAt(now)).GetFilter().GetMustNot()
+	if len(mustNot) != 1 || !mustNot[0].GetField().GetDatetimeRange().GetLte().AsTime().Equal(now) {
+		t.Fatalf("unexpired condition must_not = %+v, want the expired range", mustNot)
+	}
+}
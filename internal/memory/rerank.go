@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RerankResult scores one document passed to Reranker.Rerank.
+type RerankResult struct {
+	Index int     `json:"index"`
+	Score float64 `json:"relevance_score"`
+}
+
+// Reranker scores documents by relevance to a query, e.g. with a cross-encoder.
+type Reranker interface {
+	Rerank(ctx context.Context, query string, documents []string) ([]RerankResult, error)
+}
+
+// RerankResolver selects the reranker for the bot carried in ctx. It returns a
+// nil Reranker when the bot has reranking disabled, along with the number of
+// fused candidates to rerank.
+type RerankResolver interface {
+	ResolveReranker(ctx context.Context) (Reranker, int, error)
+}
+
+// SetRerankResolver enables the optional rerank stage of Search.
+func (s *Service) SetRerankResolver(resolver RerankResolver) {
+	s.rerankers = resolver
+}
+
+// resolveReranker returns the bot's reranker and candidate count, or nil when
+// reranking is unavailable.
+func (s *Service) resolveReranker(ctx context.Context) (Reranker, int) {
+	if s.rerankers == nil {
+		return nil, 0
+	}
+	reranker, topN, err := s.rerankers.ResolveReranker(ctx)
+	if err != nil {
+		s.logger.Warn("resolve reranker failed", slog.Any("error", err))
+		return nil, 0
+	}
+	if reranker == nil || topN <= 0 {
+		return nil, 0
+	}
+	return reranker, topN
+}
+
+// rerankItems reorders items by reranker relevance. The retrieval score is kept
+// in RetrievalScore and Score becomes the rerank score so callers sorting by
+// Score keep the reranked order. On failure the fused order is returned.
+func (s *Service) rerankItems(ctx context.Context, reranker Reranker, query string, items []MemoryItem) []MemoryItem {
+	if len(items) == 0 {
+		return items
+	}
+	documents := make([]string, len(items))
+	for i, item := range items {
+		documents[i] = item.Memory
+	}
+	results, err := reranker.Rerank(ctx, query, documents)
+	if err != nil {
+		s.logger.Warn("memory rerank failed, using fused order", slog.Any("error", err))
+		return items
+	}
+	reranked := make([]MemoryItem, 0, len(results))
+	seen := make(map[int]struct{}, len(results))
+	for _, result := range results {
+		if result.Index < 0 || result.Index >= len(items) {
+			continue
+		}
+		if _, ok := seen[result.Index]; ok {
+			continue
+		}
+		seen[result.Index] = struct{}{}
+		item := items[result.Index]
+		score := result.Score
+		item.RetrievalScore = item.Score
+		item.RerankScore = &score
+		item.Score = score
+		reranked = append(reranked, item)
+	}
+	if len(reranked) == 0 {
+		return items
+	}
+	sort.SliceStable(reranked, func(i, j int) bool {
+		return reranked[i].Score > reranked[j].Score
+	})
+	return reranked
+}
+
+// RerankClient calls a Cohere/Jina-style `POST /rerank` endpoint.
+type RerankClient struct {
+	baseURL string
+	apiKey  string
+	model   string
+	logger  *slog.Logger
+	http    *http.Client
+}
+
+type rerankRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	TopN      int      `json:"top_n,omitempty"`
+}
+
+type rerankResponse struct {
+	Results []RerankResult `json:"results"`
+}
+
+func NewRerankClient(log *slog.Logger, baseURL, apiKey, model string, timeout time.Duration) (*RerankClient, error) {
+	if strings.TrimSpace(baseURL) == "" {
+		return nil, fmt.Errorf("rerank client: base url is required")
+	}
+	if strings.TrimSpace(model) == "" {
+		return nil, fmt.Errorf("rerank client: model is required")
+	}
+	if log == nil {
+		log = slog.Default()
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &RerankClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		logger:  log.With(slog.String("client", "rerank")),
+		http: &http.Client{
+			Timeout: timeout,
+		},
+	}, nil
+}
+
+func (c *RerankClient) Rerank(ctx context.Context, query string, documents []string) ([]RerankResult, error) {
+	if len(documents) == 0 {
+		return nil, nil
+	}
+	payload, err := json.Marshal(rerankRequest{
+		Model:     c.model,
+		Query:     query,
+		Documents: documents,
+		TopN:      len(documents),
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/rerank", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("rerank error: %s", strings.TrimSpace(string(body)))
+	}
+
+	var parsed rerankResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+	return parsed.Results, nil
+}
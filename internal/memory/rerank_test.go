@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRerankClient(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rerank" || r.Header.Get("Authorization") != "Bearer test-key" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var req rerankRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model != "rerank-v3.5" || len(req.Documents) != 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":[{"index":1,"relevance_score":0.9},{"index":0,"relevance_score":0.1}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewRerankClient(nil, server.URL+"/", "test-key", "rerank-v3.5", 0)
+	if err != nil {
+		t.Fatalf("new rerank client: %v", err)
+	}
+	results, err := client.Rerank(context.Background(), "coffee", []string{"tea", "coffee"})
+	if err != nil {
+		t.Fatalf("rerank: %v", err)
+	}
+	if len(results) != 2 || results[0].Index != 1 || results[0].Score != 0.9 {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+}
+
+// sparseHitStore returns fixed sparse search hits, truncated to the limit.
+type sparseHitStore struct {
+	*memStore
+	hits      []vectorPoint
+	lastLimit int
+}
+
+func (s *sparseHitStore) SearchSparse(_ context.Context, _ []uint32, _ []float32, limit int, _ map[string]any, _ bool) ([]vectorPoint, []float64, error) {
+	s.lastLimit = limit
+	hits := s.hits
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	scores := make([]float64, len(hits))
+	for i := range hits {
+		scores[i] = float64(len(hits) - i)
+	}
+	return hits, scores, nil
+}
+
+type stubReranker struct {
+	err error
+}
+
+func (r *stubReranker) Rerank(_ context.Context, _ string, documents []string) ([]RerankResult, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	// Reverse the fused order.
+	out := make([]RerankResult, len(documents))
+	for i := range documents {
+		out[i] = RerankResult{Index: i, Score: float64(i)}
+	}
+	return out, nil
+}
+
+type stubRerankResolver struct {
+	reranker Reranker
+	topN     int
+}
+
+func (r *stubRerankResolver) ResolveReranker(context.Context) (Reranker, int, error) {
+	return r.reranker, r.topN, nil
+}
+
+func TestService_Search_Rerank(t *testing.T) {
+	t.Parallel()
+	hits := make([]vectorPoint, 0, 5)
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		hits = append(hits, vectorPoint{ID: id, Payload: map[string]any{"data": "memory " + id}})
+	}
+
+	tests := []struct {
+		name      string
+		reranker  Reranker
+		topN      int
+		wantIDs   []string
+		wantLimit int
+		reranked  bool
+	}{
+		{name: "disabled", wantIDs: []string{"a", "b"}, wantLimit: 2},
+		{name: "reorders top n", reranker: &stubReranker{}, topN: 4, wantIDs: []string{"d", "c"}, wantLimit: 4, reranked: true},
+		{name: "falls back on error", reranker: &stubReranker{err: errors.New("boom")}, topN: 4, wantIDs: []string{"a", "b"}, wantLimit: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			store := &sparseHitStore{memStore: newMemStore(false), hits: hits}
+			svc := newArchiveTestService(store, nil, "")
+			if tt.reranker != nil {
+				svc.SetRerankResolver(&stubRerankResolver{reranker: tt.reranker, topN: tt.topN})
+			}
+			resp, err := svc.Search(context.Background(), SearchRequest{Query: "memory", BotID: "bot-a", Limit: 2, NoStats: true})
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if store.lastLimit != tt.wantLimit {
+				t.Errorf("candidate limit = %d, want %d", store.lastLimit, tt.wantLimit)
+			}
+			if len(resp.Results) != len(tt.wantIDs) {
+				t.Fatalf("got %d results, want %d", len(resp.Results), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				item := resp.Results[i]
+				if item.ID != id {
+					t.Errorf("result %d = %s, want %s", i, item.ID, id)
+				}
+				if tt.reranked && (item.RerankScore == nil || item.Score != *item.RerankScore || item.RetrievalScore == 0) {
+					t.Errorf("result %d missing rerank scores: %+v", i, item)
+				}
+				if !tt.reranked && item.RerankScore != nil {
+					t.Errorf("result %d unexpectedly reranked: %+v", i, item)
+				}
+			}
+		})
+	}
+}
+
+func TestService_Search_RerankDefaultLimit(t *testing.T) {
+	t.Parallel()
+	hits := make([]vectorPoint, 0, 20)
+	for i := range 20 {
+		id := fmt.Sprintf("m%02d", i)
+		hits = append(hits, vectorPoint{ID: id, Payload: map[string]any{"data": "memory " + id}})
+	}
+	store := &sparseHitStore{memStore: newMemStore(false), hits: hits}
+	svc := newArchiveTestService(store, nil, "")
+	svc.SetRerankResolver(&stubRerankResolver{reranker: &stubReranker{}, topN: 15})
+
+	resp, err := svc.Search(context.Background(), SearchRequest{Query: "memory", BotID: "bot-a", NoStats: true})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if store.lastLimit != 15 {
+		t.Errorf("candidate limit = %d, want 15", store.lastLimit)
+	}
+	if len(resp.Results) != defaultSearchLimit {
+		t.Fatalf("got %d results, want the default limit %d", len(resp.Results), defaultSearchLimit)
+	}
+	if resp.Results[0].ID != "m14" {
+		t.Errorf("first result = %s, want the reranked m14", resp.Results[0].ID)
+	}
+}
@@ -0,0 +1,446 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+// RevisionAction identifies the kind of change recorded by a Revision.
+type RevisionAction string
+
+const (
+	RevisionAdd     RevisionAction = "ADD"
+	RevisionUpdate  RevisionAction = "UPDATE"
+	RevisionDelete  RevisionAction = "DELETE"
+	RevisionCompact RevisionAction = "COMPACT"
+	RevisionRestore RevisionAction = "RESTORE"
+	RevisionExpire  RevisionAction = "EXPIRE"
+)
+
+const defaultRevisionListLimit = 50
+
+// ErrRevisionNotFound is returned when a revision does not exist for the bot.
+var ErrRevisionNotFound = errors.New("memory revision not found")
+
+// Revision is one entry of the append-only memory history. Payload is the
+// point payload after the change (before it, for deletions) and is what a
+// restore brings back.
+type Revision struct {
+	ID             string         `json:"id"`
+	BotID          string         `json:"bot_id"`
+	MemoryID       string         `json:"memory_id"`
+	Action         RevisionAction `json:"action"`
+	PreviousText   string         `json:"previous_text,omitempty"`
+	NewText        string         `json:"new_text,omitempty"`
+	Payload        map[string]any `json:"payload,omitempty"`
+	SourceMessages []Message      `json:"source_messages,omitempty"`
+	Model          string         `json:"model,omitempty"`
+	RestoredFrom   string         `json:"restored_from,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// snapshotText returns the memory text as it stood in this revision.
+func (r Revision) snapshotText() string {
+	if strings.TrimSpace(r.NewText) != "" {
+		return r.NewText
+	}
+	return r.PreviousText
+}
+
+// RevisionListResponse lists the revisions of one memory, newest first.
+type RevisionListResponse struct {
+	Items []Revision `json:"items"`
+}
+
+// RevisionStore persists memory revisions.
+type RevisionStore interface {
+	Create(ctx context.Context, rev Revision) (Revision, error)
+	// Get returns ErrRevisionNotFound when the revision does not belong to botID.
+	Get(ctx context.Context, botID, revisionID string) (Revision, error)
+	List(ctx context.Context, botID, memoryID string, limit int) ([]Revision, error)
+}
+
+// ModelNamer is optionally implemented by an LLM to report the model that
+// serves requests for the bot carried in ctx.
+type ModelNamer interface {
+	ModelName(ctx context.Context) string
+}
+
+// RestoreRequest restores a memory to the state captured by a revision.
+type RestoreRequest struct {
+	BotID            string `json:"bot_id"`
+	MemoryID         string `json:"memory_id"`
+	RevisionID       string `json:"revision_id"`
+	EmbeddingEnabled bool   `json:"embedding_enabled,omitempty"`
+}
+
+// PgRevisionStore stores revisions in the memory_revisions table.
+type PgRevisionStore struct {
+	queries *sqlc.Queries
+}
+
+// NewPgRevisionStore creates a Postgres-backed RevisionStore.
+func NewPgRevisionStore(queries *sqlc.Queries) *PgRevisionStore {
+	return &PgRevisionStore{queries: queries}
+}
+
+func (p *PgRevisionStore) Create(ctx context.Context, rev Revision) (Revision, error) {
+	botUUID, err := db.ParseUUID(rev.BotID)
+	if err != nil {
+		return Revision{}, err
+	}
+	memoryUUID, err := db.ParseUUID(rev.MemoryID)
+	if err != nil {
+		return Revision{}, err
+	}
+	var restoredFrom pgtype.UUID
+	if rev.RestoredFrom != "" {
+		if restoredFrom, err = db.ParseUUID(rev.RestoredFrom); err != nil {
+			return Revision{}, err
+		}
+	}
+	payload, err := json.Marshal(nonNilMap(rev.Payload))
+	if err != nil {
+		return Revision{}, err
+	}
+	sources := rev.SourceMessages
+	if sources == nil {
+		sources = []Message{}
+	}
+	sourceMessages, err := json.Marshal(sources)
+	if err != nil {
+		return Revision{}, err
+	}
+	row, err := p.queries.CreateMemoryRevision(ctx, sqlc.CreateMemoryRevisionParams{
+		BotID:          botUUID,
+		MemoryID:       memoryUUID,
+		Action:         string(rev.Action),
+		PreviousText:   rev.PreviousText,
+		NewText:        rev.NewText,
+		Payload:        payload,
+		SourceMessages: sourceMessages,
+		Model:          rev.Model,
+		RestoredFrom:   restoredFrom,
+	})
+	if err != nil {
+		return Revision{}, err
+	}
+	return rowToRevision(row), nil
+}
+
+func (p *PgRevisionStore) Get(ctx context.Context, botID, revisionID string) (Revision, error) {
+	botUUID, err := db.ParseUUID(botID)
+	if err != nil {
+		return Revision{}, err
+	}
+	revisionUUID, err := db.ParseUUID(revisionID)
+	if err != nil {
+		return Revision{}, ErrRevisionNotFound
+	}
+	row, err := p.queries.GetMemoryRevision(ctx, sqlc.GetMemoryRevisionParams{
+		ID:    revisionUUID,
+		BotID: botUUID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Revision{}, ErrRevisionNotFound
+		}
+		return Revision{}, err
+	}
+	return rowToRevision(row), nil
+}
+
+func (p *PgRevisionStore) List(ctx context.Context, botID, memoryID string, limit int) ([]Revision, error) {
+	botUUID, err := db.ParseUUID(botID)
+	if err != nil {
+		return nil, err
+	}
+	memoryUUID, err := db.ParseUUID(memoryID)
+	if err != nil {
+		// Memory IDs are always UUIDs, so nothing can have been recorded.
+		return []Revision{}, nil
+	}
+	if limit <= 0 {
+		limit = defaultRevisionListLimit
+	}
+	rows, err := p.queries.ListMemoryRevisions(ctx, sqlc.ListMemoryRevisionsParams{
+		BotID:    botUUID,
+		MemoryID: memoryUUID,
+		MaxCount: int32(limit), //nolint:gosec // limit is caller-bounded
+	})
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]Revision, 0, len(rows))
+	for _, row := range rows {
+		revisions = append(revisions, rowToRevision(row))
+	}
+	return revisions, nil
+}
+
+func rowToRevision(row sqlc.MemoryRevision) Revision {
+	var payload map[string]any
+	if len(row.Payload) > 0 {
+		_ = json.Unmarshal(row.Payload, &payload)
+	}
+	var sources []Message
+	if len(row.SourceMessages) > 0 {
+		_ = json.Unmarshal(row.SourceMessages, &sources)
+	}
+	return Revision{
+		ID:             pgUUIDToString(row.ID),
+		BotID:          pgUUIDToString(row.BotID),
+		MemoryID:       pgUUIDToString(row.MemoryID),
+		Action:         RevisionAction(row.Action),
+		PreviousText:   row.PreviousText,
+		NewText:        row.NewText,
+		Payload:        payload,
+		SourceMessages: sources,
+		Model:          row.Model,
+		RestoredFrom:   pgUUIDToString(row.RestoredFrom),
+		CreatedAt:      db.TimeFromPg(row.CreatedAt),
+	}
+}
+
+func pgUUIDToString(id pgtype.UUID) string {
+	if !id.Valid {
+		return ""
+	}
+	return uuid.UUID(id.Bytes).String()
+}
+
+func nonNilMap(m map[string]any) map[string]any {
+	if m == nil {
+		return map[string]any{}
+	}
+	return m
+}
+
+// revisionSource carries what caused a batch of memory changes so that the
+// apply* helpers can attribute the revisions they record.
+type revisionSource struct {
+	action   RevisionAction
+	messages []Message
+	model    string
+}
+
+const memoryRevisionSourceContextKey contextKey = "memory_revision_source"
+
+func withRevisionSource(ctx context.Context, source revisionSource) context.Context {
+	return context.WithValue(ctx, memoryRevisionSourceContextKey, source)
+}
+
+func revisionSourceFromContext(ctx context.Context) revisionSource {
+	source, _ := ctx.Value(memoryRevisionSourceContextKey).(revisionSource)
+	return source
+}
+
+// SetRevisionStore enables the memory revision log.
+func (s *Service) SetRevisionStore(store RevisionStore) {
+	s.revisions = store
+}
+
+// modelName reports the LLM model used for the bot in ctx, if known.
+func (s *Service) modelName(ctx context.Context) string {
+	if namer, ok := s.llm.(ModelNamer); ok {
+		return namer.ModelName(ctx)
+	}
+	return ""
+}
+
+// recordRevision appends rev to the revision log. The action, source messages
+// and model from the context's revisionSource take precedence. Failures are
+// logged and never fail the memory operation itself.
+func (s *Service) recordRevision(ctx context.Context, rev Revision) {
+	if s.revisions == nil {
+		return
+	}
+	source := revisionSourceFromContext(ctx)
+	if source.action != "" {
+		rev.Action = source.action
+	}
+	if rev.SourceMessages == nil {
+		rev.SourceMessages = source.messages
+	}
+	if rev.Model == "" {
+		rev.Model = source.model
+	}
+	if rev.BotID == "" {
+		rev.BotID = resolveBotID(BotIDFromContext(ctx), rev.Payload)
+	}
+	if _, err := uuid.Parse(rev.BotID); err != nil {
+		s.logger.Debug("skip memory revision without bot", slog.String("memory_id", rev.MemoryID))
+		return
+	}
+	rev.Payload = clonePayload(rev.Payload)
+	if _, err := s.revisions.Create(ctx, rev); err != nil {
+		s.logger.Warn("record memory revision failed",
+			slog.String("memory_id", rev.MemoryID),
+			slog.String("action", string(rev.Action)),
+			slog.Any("error", err))
+	}
+}
+
+// recordDeletions records a DELETE revision for each point about to be removed.
+func (s *Service) recordDeletions(ctx context.Context, points []vectorPoint) {
+	for _, point := range points {
+		s.recordRevision(ctx, Revision{
+			MemoryID:     point.ID,
+			Action:       RevisionDelete,
+			PreviousText: fmt.Sprint(point.Payload["data"]),
+			Payload:      point.Payload,
+		})
+	}
+}
+
+// ListRevisions returns the revisions of a memory, newest first.
+func (s *Service) ListRevisions(ctx context.Context, botID, memoryID string, limit int) ([]Revision, error) {
+	if s.revisions == nil {
+		return nil, fmt.Errorf("memory revision log not configured")
+	}
+	if strings.TrimSpace(memoryID) == "" {
+		return nil, fmt.Errorf("memory_id is required")
+	}
+	return s.revisions.List(ctx, botID, memoryID, limit)
+}
+
+// RestoreRevision brings a memory back to the text and payload captured by a
+// revision, re-creating it if it has since been deleted.
+func (s *Service) RestoreRevision(ctx context.Context, req RestoreRequest) (MemoryItem, error) {
+	if s.revisions == nil {
+		return MemoryItem{}, fmt.Errorf("memory revision log not configured")
+	}
+	if s.store == nil {
+		return MemoryItem{}, fmt.Errorf("vector store not configured")
+	}
+	if s.bm25 == nil {
+		return MemoryItem{}, fmt.Errorf("bm25 indexer not configured")
+	}
+	rev, err := s.revisions.Get(ctx, req.BotID, req.RevisionID)
+	if err != nil {
+		return MemoryItem{}, err
+	}
+	if req.MemoryID != "" && rev.MemoryID != req.MemoryID {
+		return MemoryItem{}, ErrRevisionNotFound
+	}
+	text := rev.snapshotText()
+	if strings.TrimSpace(text) == "" {
+		return MemoryItem{}, fmt.Errorf("revision has no memory text to restore")
+	}
+	ctx = WithBotID(ctx, rev.BotID)
+
+	existing, err := s.store.Get(ctx, rev.MemoryID)
+	if err != nil {
+		return MemoryItem{}, err
+	}
+	previousText := ""
+	if existing != nil {
+		previousText = fmt.Sprint(existing.Payload["data"])
+		s.removeFromIndex(ctx, existing.Payload)
+	}
+
+	lang, err := s.detectLanguage(ctx, text)
+	if err != nil {
+		return MemoryItem{}, err
+	}
+	termFreq, docLen, err := s.bm25.TermFrequencies(lang, text)
+	if err != nil {
+		return MemoryItem{}, err
+	}
+	sparseIndices, sparseValues := s.bm25.AddDocument(lang, termFreq, docLen)
+
+	payload := clonePayload(rev.Payload)
+	if payload == nil {
+		payload = map[string]any{"bot_id": rev.BotID}
+	}
+	payload["data"] = text
+	payload["hash"] = hashMemory(text)
+	payload["lang"] = lang
+	payload["updated_at"] = time.Now().UTC().Format(time.RFC3339)
+	if isExpired(payload, time.Now()) {
+		// Restoring an expired memory keeps it instead of letting the sweeper drop it again.
+		delete(payload, expiresAtKey)
+	}
+	if _, ok := payload["created_at"]; !ok {
+		payload["created_at"] = payload["updated_at"]
+	}
+	point := vectorPoint{
+		ID:               rev.MemoryID,
+		SparseIndices:    sparseIndices,
+		SparseValues:     sparseValues,
+		SparseVectorName: s.store.SparseVectorName(),
+		Payload:          payload,
+	}
+	if req.EmbeddingEnabled {
+		if s.embedder == nil {
+			return MemoryItem{}, fmt.Errorf("embedder not configured")
+		}
+		vector, err := s.embedder.Embed(ctx, text)
+		if err != nil {
+			return MemoryItem{}, err
+		}
+		point.Vector = vector
+		point.VectorName = s.vectorNameForText()
+	}
+	if err := s.store.Upsert(ctx, []vectorPoint{point}); err != nil {
+		return MemoryItem{}, err
+	}
+	s.recordRevision(ctx, Revision{
+		BotID:        rev.BotID,
+		MemoryID:     rev.MemoryID,
+		Action:       RevisionRestore,
+		PreviousText: previousText,
+		NewText:      text,
+		Payload:      payload,
+		RestoredFrom: rev.ID,
+	})
+	return payloadToMemoryItem(rev.MemoryID, payload), nil
+}
+
+// removeFromIndex drops a stored memory's terms from the BM25 statistics.
+func (s *Service) removeFromIndex(ctx context.Context, payload map[string]any) {
+	if s.bm25 == nil {
+		return
+	}
+	text := fmt.Sprint(payload["data"])
+	lang := fmt.Sprint(payload["lang"])
+	if lang == "" && strings.TrimSpace(text) != "" {
+		var err error
+		lang, err = s.detectLanguage(ctx, text)
+		if err != nil {
+			s.logger.Warn("detect language failed for old text", slog.Any("error", err))
+		}
+	}
+	if strings.TrimSpace(text) == "" || strings.TrimSpace(lang) == "" {
+		return
+	}
+	freq, docLen, err := s.bm25.TermFrequencies(lang, text)
+	if err != nil {
+		s.logger.Warn("bm25 term frequencies failed", slog.String("lang", lang), slog.Any("error", err))
+		return
+	}
+	s.bm25.RemoveDocument(lang, freq, docLen)
+}
+
+func clonePayload(payload map[string]any) map[string]any {
+	if payload == nil {
+		return nil
+	}
+	out := make(map[string]any, len(payload))
+	for k, v := range payload {
+		out[k] = v
+	}
+	return out
+}
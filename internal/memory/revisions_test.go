@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// memRevisions is an in-memory RevisionStore.
+type memRevisions struct {
+	items []Revision
+}
+
+func (m *memRevisions) Create(_ context.Context, rev Revision) (Revision, error) {
+	rev.ID = fmt.Sprintf("00000000-0000-0000-0000-%012d", len(m.items)+1)
+	rev.CreatedAt = time.Now()
+	m.items = append(m.items, rev)
+	return rev, nil
+}
+
+func (m *memRevisions) Get(_ context.Context, botID, revisionID string) (Revision, error) {
+	for _, rev := range m.items {
+		if rev.ID == revisionID && rev.BotID == botID {
+			return rev, nil
+		}
+	}
+	return Revision{}, ErrRevisionNotFound
+}
+
+func (m *memRevisions) List(_ context.Context, botID, memoryID string, _ int) ([]Revision, error) {
+	var out []Revision
+	for i := len(m.items) - 1; i >= 0; i-- {
+		if m.items[i].BotID == botID && m.items[i].MemoryID == memoryID {
+			out = append(out, m.items[i])
+		}
+	}
+	return out, nil
+}
+
+const revisionTestBotID = "11111111-1111-1111-1111-111111111111"
+
+func TestService_Revisions_UpdateDeleteRestore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := newMemStore(false)
+	revisions := &memRevisions{}
+	svc := newArchiveTestService(store, nil, "")
+	svc.SetRevisionStore(revisions)
+
+	filters := map[string]any{"bot_id": revisionTestBotID, "namespace": "bot", "scopeId": revisionTestBotID}
+	added, err := svc.applyAdd(ctx, "User likes tea", filters, nil, false)
+	if err != nil {
+		t.Fatalf("applyAdd failed: %v", err)
+	}
+	if _, err := svc.Update(ctx, UpdateRequest{MemoryID: added.ID, Memory: "User likes coffee"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, err := svc.Delete(ctx, added.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	history, err := svc.ListRevisions(ctx, revisionTestBotID, added.ID, 0)
+	if err != nil {
+		t.Fatalf("ListRevisions failed: %v", err)
+	}
+	want := []RevisionAction{RevisionDelete, RevisionUpdate, RevisionAdd}
+	if len(history) != len(want) {
+		t.Fatalf("got %d revisions, want %d", len(history), len(want))
+	}
+	for i, action := range want {
+		if history[i].Action != action {
+			t.Errorf("revision %d action = %s, want %s", i, history[i].Action, action)
+		}
+	}
+	if history[1].PreviousText != "User likes tea" || history[1].NewText != "User likes coffee" {
+		t.Errorf("unexpected update revision: %+v", history[1])
+	}
+
+	// Restoring the ADD revision brings back the deleted memory with its original text.
+	item, err := svc.RestoreRevision(ctx, RestoreRequest{
+		BotID:      revisionTestBotID,
+		MemoryID:   added.ID,
+		RevisionID: history[2].ID,
+	})
+	if err != nil {
+		t.Fatalf("RestoreRevision failed: %v", err)
+	}
+	if item.ID != added.ID || item.Memory != "User likes tea" {
+		t.Fatalf("unexpected restored item: %+v", item)
+	}
+	if point := store.points[added.ID]; point.Payload["scopeId"] != revisionTestBotID || len(point.SparseIndices) == 0 {
+		t.Errorf("restored point missing scope or sparse vector: %+v", point)
+	}
+	last := revisions.items[len(revisions.items)-1]
+	if last.Action != RevisionRestore || last.RestoredFrom != history[2].ID {
+		t.Errorf("unexpected restore revision: %+v", last)
+	}
+
+	_, err = svc.RestoreRevision(ctx, RestoreRequest{
+		BotID:      revisionTestBotID,
+		MemoryID:   "22222222-2222-2222-2222-222222222222",
+		RevisionID: history[2].ID,
+	})
+	if !errors.Is(err, ErrRevisionNotFound) {
+		t.Fatalf("restore with mismatched memory error = %v, want ErrRevisionNotFound", err)
+	}
+}
+
+func TestService_Revisions_AddRecordsSourceAndModel(t *testing.T) {
+	t.Parallel()
+	revisions := &memRevisions{}
+	svc := newArchiveTestService(newMemStore(false), nil, "")
+	svc.llm = &namedMockLLM{
+		MockLLM: MockLLM{
+			ExtractFunc: func(context.Context, ExtractRequest) (ExtractResponse, error) {
+				return ExtractResponse{Facts: []string{"User is vegetarian"}}, nil
+			},
+			DecideFunc: func(context.Context, DecideRequest) (DecideResponse, error) {
+				return DecideResponse{Actions: []DecisionAction{{Event: "ADD", Text: "User is vegetarian"}}}, nil
+			},
+			DetectLanguageFunc: func(context.Context, string) (string, error) { return "en", nil },
+		},
+		model: "memory-model",
+	}
+	svc.SetRevisionStore(revisions)
+
+	if _, err := svc.Add(context.Background(), AddRequest{
+		Message: "I don't eat meat",
+		BotID:   revisionTestBotID,
+	}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if len(revisions.items) != 1 {
+		t.Fatalf("got %d revisions, want 1", len(revisions.items))
+	}
+	rev := revisions.items[0]
+	if rev.Action != RevisionAdd || rev.Model != "memory-model" || rev.BotID != revisionTestBotID {
+		t.Errorf("unexpected revision: %+v", rev)
+	}
+	if len(rev.SourceMessages) != 1 || rev.SourceMessages[0].Content != "I don't eat meat" {
+		t.Errorf("source messages not recorded: %+v", rev.SourceMessages)
+	}
+}
+
+func TestService_Revisions_DeleteAllRecordsEveryMemory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := newMemStore(false)
+	revisions := &memRevisions{}
+	svc := newArchiveTestService(store, nil, "")
+	svc.SetRevisionStore(revisions)
+
+	const total = 300
+	points := make([]vectorPoint, 0, total)
+	for i := 0; i < total; i++ {
+		points = append(points, vectorPoint{
+			ID:      fmt.Sprintf("00000000-0000-0000-0000-%012d", i),
+			Payload: map[string]any{"bot_id": revisionTestBotID, "data": fmt.Sprintf("fact %d", i)},
+		})
+	}
+	if err := store.Upsert(ctx, points); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.DeleteAll(ctx, DeleteAllRequest{BotID: revisionTestBotID}); err != nil {
+		t.Fatalf("DeleteAll failed: %v", err)
+	}
+	if len(store.points) != 0 {
+		t.Fatalf("got %d points left, want 0", len(store.points))
+	}
+	if len(revisions.items) != total {
+		t.Fatalf("got %d revisions, want %d", len(revisions.items), total)
+	}
+}
+
+type namedMockLLM struct {
+	MockLLM
+	model string
+}
+
+func (m *namedMockLLM) ModelName(context.Context) string { return m.model }
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// ScopePolicy controls which of a bot's memories are visible to a conversation.
+type ScopePolicy string
+
+const (
+	// ScopeShared makes every memory of the bot visible in every conversation.
+	ScopeShared ScopePolicy = "shared"
+	// ScopePerUser limits memories to the channel identity they were learned from.
+	ScopePerUser ScopePolicy = "per_user"
+	// ScopePerConversation limits memories to the conversation (route) they were
+	// learned in, falling back to the channel identity when no route is known.
+	ScopePerConversation ScopePolicy = "per_conversation"
+)
+
+// Payload keys tagging a memory with the subject it originates from.
+const (
+	SubjectChannelIdentityKey = "channel_identity_id"
+	SubjectUserKey            = "user_id"
+	SubjectConversationKey    = "conversation_id"
+)
+
+// NormalizeScopePolicy maps raw settings values to a known policy, defaulting
+// to ScopeShared.
+func NormalizeScopePolicy(raw string) ScopePolicy {
+	switch policy := ScopePolicy(strings.ToLower(strings.TrimSpace(raw))); policy {
+	case ScopePerUser, ScopePerConversation:
+		return policy
+	default:
+		return ScopeShared
+	}
+}
+
+// Subject identifies who a memory is about and where it was learned. A nil
+// subject on AddRequest or SearchRequest marks an administrative call that is
+// neither tagged nor restricted by the bot's scope policy.
+type Subject struct {
+	ChannelIdentityID string `json:"channel_identity_id,omitempty"`
+	UserID            string `json:"user_id,omitempty"`
+	ConversationID    string `json:"conversation_id,omitempty"`
+}
+
+// payload returns the non-empty subject fields as payload tags.
+func (s *Subject) payload() map[string]any {
+	if s == nil {
+		return nil
+	}
+	tags := map[string]any{}
+	for key, value := range map[string]string{
+		SubjectChannelIdentityKey: s.ChannelIdentityID,
+		SubjectUserKey:            s.UserID,
+		SubjectConversationKey:    s.ConversationID,
+	} {
+		if value = strings.TrimSpace(value); value != "" {
+			tags[key] = value
+		}
+	}
+	return tags
+}
+
+// filters returns the payload filter restricting memories to what the subject
+// may see under policy. A restrictive policy without a matching subject field
+// yields a filter on an empty value, which matches no tagged memory.
+func (p ScopePolicy) filters(subject *Subject) map[string]any {
+	if subject == nil {
+		return nil
+	}
+	switch p {
+	case ScopePerConversation:
+		if conversationID := strings.TrimSpace(subject.ConversationID); conversationID != "" {
+			return map[string]any{SubjectConversationKey: conversationID}
+		}
+		fallthrough
+	case ScopePerUser:
+		return map[string]any{SubjectChannelIdentityKey: strings.TrimSpace(subject.ChannelIdentityID)}
+	default:
+		return nil
+	}
+}
+
+// ScopePolicyResolver returns the memory scope policy of the bot carried in ctx.
+type ScopePolicyResolver interface {
+	ResolveScopePolicy(ctx context.Context) (ScopePolicy, error)
+}
+
+// SetScopePolicyResolver enables per-bot memory scoping for Add and Search.
+func (s *Service) SetScopePolicyResolver(resolver ScopePolicyResolver) {
+	s.scopes = resolver
+}
+
+// scopeFilters returns the filters that restrict subject to the memories it
+// may see, or nil when the bot shares memories across conversations.
+func (s *Service) scopeFilters(ctx context.Context, subject *Subject) map[string]any {
+	if subject == nil || s.scopes == nil {
+		return nil
+	}
+	policy, err := s.scopes.ResolveScopePolicy(ctx)
+	if err != nil {
+		s.logger.Warn("resolve memory scope policy failed", slog.Any("error", err))
+		// Fail closed: a restricted view is safer than leaking another user's memories.
+		policy = ScopePerUser
+	}
+	return policy.filters(subject)
+}
+
+// mergeFilters returns base with extra applied on top, leaving both untouched.
+func mergeFilters(base, extra map[string]any) map[string]any {
+	if len(extra) == 0 {
+		return base
+	}
+	out := make(map[string]any, len(base)+len(extra))
+	for key, value := range base {
+		out[key] = value
+	}
+	for key, value := range extra {
+		out[key] = value
+	}
+	return out
+}
@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestScopePolicy_Filters(t *testing.T) {
+	t.Parallel()
+	subject := &Subject{ChannelIdentityID: "ci-1", UserID: "user-1", ConversationID: "route-1"}
+	tests := []struct {
+		name    string
+		policy  ScopePolicy
+		subject *Subject
+		want    map[string]any
+	}{
+		{name: "shared", policy: ScopeShared, subject: subject},
+		{name: "no subject", policy: ScopePerUser},
+		{name: "per user", policy: ScopePerUser, subject: subject, want: map[string]any{SubjectChannelIdentityKey: "ci-1"}},
+		{name: "per conversation", policy: ScopePerConversation, subject: subject, want: map[string]any{SubjectConversationKey: "route-1"}},
+		{name: "per conversation without route", policy: ScopePerConversation, subject: &Subject{ChannelIdentityID: "ci-1"}, want: map[string]any{SubjectChannelIdentityKey: "ci-1"}},
+		{name: "per user without identity", policy: ScopePerUser, subject: &Subject{UserID: "user-1"}, want: map[string]any{SubjectChannelIdentityKey: ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.policy.filters(tt.subject); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeScopePolicy(t *testing.T) {
+	t.Parallel()
+	for raw, want := range map[string]ScopePolicy{
+		"":                   ScopeShared,
+		"unknown":            ScopeShared,
+		"per_user":           ScopePerUser,
+		" PER_CONVERSATION ": ScopePerConversation,
+	} {
+		if got := NormalizeScopePolicy(raw); got != want {
+			t.Errorf("NormalizeScopePolicy(%q) = %s, want %s", raw, got, want)
+		}
+	}
+}
+
+type stubScopeResolver struct {
+	policy ScopePolicy
+	err    error
+}
+
+func (r *stubScopeResolver) ResolveScopePolicy(context.Context) (ScopePolicy, error) {
+	return r.policy, r.err
+}
+
+// filterCaptureStore records the filters of the last sparse search.
+type filterCaptureStore struct {
+	*memStore
+	lastFilters map[string]any
+}
+
+func (s *filterCaptureStore) SearchSparse(_ context.Context, _ []uint32, _ []float32, _ int, filters map[string]any, _ bool) ([]vectorPoint, []float64, error) {
+	s.lastFilters = filters
+	return nil, nil, nil
+}
+
+func TestService_Search_AppliesScopePolicy(t *testing.T) {
+	t.Parallel()
+	subject := &Subject{ChannelIdentityID: "ci-1", ConversationID: "route-1"}
+	tests := []struct {
+		name     string
+		resolver *stubScopeResolver
+		subject  *Subject
+		want     map[string]any
+	}{
+		{name: "shared", resolver: &stubScopeResolver{policy: ScopeShared}, subject: subject, want: map[string]any{}},
+		{name: "per user", resolver: &stubScopeResolver{policy: ScopePerUser}, subject: subject, want: map[string]any{SubjectChannelIdentityKey: "ci-1"}},
+		{name: "per conversation", resolver: &stubScopeResolver{policy: ScopePerConversation}, subject: subject, want: map[string]any{SubjectConversationKey: "route-1"}},
+		{name: "admin search without subject", resolver: &stubScopeResolver{policy: ScopePerUser}, want: map[string]any{}},
+		{name: "resolver failure fails closed", resolver: &stubScopeResolver{err: errors.New("boom")}, subject: subject, want: map[string]any{SubjectChannelIdentityKey: "ci-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			store := &filterCaptureStore{memStore: newMemStore(false)}
+			svc := newArchiveTestService(store, nil, "")
+			svc.SetScopePolicyResolver(tt.resolver)
+			if _, err := svc.Search(context.Background(), SearchRequest{
+				Query:   "coffee",
+				BotID:   "bot-a",
+				Filters: map[string]any{"scopeId": "bot-a"},
+				Subject: tt.subject,
+				NoStats: true,
+			}); err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			want := map[string]any{"scopeId": "bot-a", "bot_id": "bot-a"}
+			for key, value := range tt.want {
+				want[key] = value
+			}
+			if _, ok := store.lastFilters[expiresAtKey].(expiryFilter); !ok {
+				t.Errorf("search filters = %v, missing the expiry filter", store.lastFilters)
+			}
+			delete(store.lastFilters, expiresAtKey)
+			if !reflect.DeepEqual(store.lastFilters, want) {
+				t.Errorf("search filters = %v, want %v", store.lastFilters, want)
+			}
+		})
+	}
+}
+
+func TestService_Add_TagsSubject(t *testing.T) {
+	t.Parallel()
+	store := newMemStore(false)
+	svc := newArchiveTestService(store, nil, "")
+	svc.SetScopePolicyResolver(&stubScopeResolver{policy: ScopePerUser})
+	infer := false
+	resp, err := svc.Add(context.Background(), AddRequest{
+		Message: "I am allergic to peanuts",
+		BotID:   "bot-a",
+		Infer:   &infer,
+		Subject: &Subject{ChannelIdentityID: "ci-1", UserID: "user-1", ConversationID: "route-1"},
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if len(resp.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(resp.Results))
+	}
+	item := resp.Results[0]
+	if item.ChannelIdentityID != "ci-1" || item.UserID != "user-1" || item.ConversationID != "route-1" {
+		t.Errorf("memory not tagged with subject: %+v", item)
+	}
+	if payload := store.points[item.ID].Payload; payload[SubjectChannelIdentityKey] != "ci-1" {
+		t.Errorf("payload missing channel identity: %v", payload)
+	}
+}
@@ -12,23 +12,34 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/qdrant/go-client/qdrant"
 
 	"github.com/memohai/memoh/internal/embeddings"
 )
 
+// scrollAllBatchSize is the page size used when reading a whole scope.
+const scrollAllBatchSize = 256
+
+// defaultSearchLimit is the result count used when a search sets no limit; it
+// matches the vector stores' own default.
+const defaultSearchLimit = 10
+
 type Service struct {
 	llm                      LLM
 	embedder                 embeddings.Embedder
-	store                    *QdrantStore
+	queryEmbedder            embeddings.Embedder
+	store                    VectorStore
 	resolver                 *embeddings.Resolver
 	bm25                     *BM25Indexer
+	revisions                RevisionStore
+	rerankers                RerankResolver
+	scopes                   ScopePolicyResolver
+	memoryFS                 *MemoryFS
 	logger                   *slog.Logger
 	defaultTextModelID       string
 	defaultMultimodalModelID string
 }
 
-func NewService(log *slog.Logger, llm LLM, embedder embeddings.Embedder, store *QdrantStore, resolver *embeddings.Resolver, bm25 *BM25Indexer, defaultTextModelID, defaultMultimodalModelID string) *Service {
+func NewService(log *slog.Logger, llm LLM, embedder embeddings.Embedder, store VectorStore, resolver *embeddings.Resolver, bm25 *BM25Indexer, defaultTextModelID, defaultMultimodalModelID string) *Service {
 	return &Service{
 		llm:                      llm,
 		embedder:                 embedder,
@@ -41,6 +52,13 @@ func NewService(log *slog.Logger, llm LLM, embedder embeddings.Embedder, store *
 	}
 }
 
+// SetQueryEmbedder sets the embedder used for search queries. Memory text is
+// embedded with the embedder given to NewService, which may cache vectors;
+// queries are one-off, so caching them would only grow the cache.
+func (s *Service) SetQueryEmbedder(embedder embeddings.Embedder) {
+	s.queryEmbedder = embedder
+}
+
 func (s *Service) Add(ctx context.Context, req AddRequest) (SearchResponse, error) {
 	if req.Message == "" && len(req.Messages) == 0 {
 		return SearchResponse{}, fmt.Errorf("message or messages is required")
@@ -52,10 +70,22 @@ func (s *Service) Add(ctx context.Context, req AddRequest) (SearchResponse, erro
 	messages := normalizeMessages(req)
 	filters := buildFilters(req)
 	ctx = WithBotID(ctx, resolveBotID(req.BotID, filters))
+	// Candidates for UPDATE/DELETE are limited to what the subject may see;
+	// new and updated memories are tagged with the full subject.
+	candidateFilters := mergeFilters(filters, s.scopeFilters(ctx, req.Subject))
+	filters = mergeFilters(filters, req.Subject.payload())
+	expiresAt := ""
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return SearchResponse{}, fmt.Errorf("expires_at must be in the future")
+		}
+		expiresAt = formatExpiry(*req.ExpiresAt)
+	}
 
 	embeddingEnabled := req.EmbeddingEnabled != nil && *req.EmbeddingEnabled
 	if req.Infer != nil && !*req.Infer {
-		return s.addRawMessages(ctx, messages, filters, req.Metadata, embeddingEnabled)
+		ctx = withRevisionSource(ctx, revisionSource{messages: messages})
+		return s.addRawMessages(ctx, messages, withExpiry(filters, expiresAt), req.Metadata, embeddingEnabled)
 	}
 
 	extractResp, err := s.llm.Extract(ctx, ExtractRequest{
@@ -70,7 +100,7 @@ func (s *Service) Add(ctx context.Context, req AddRequest) (SearchResponse, erro
 		return SearchResponse{Results: []MemoryItem{}}, nil
 	}
 
-	candidates, err := s.collectCandidates(ctx, extractResp.Facts, filters)
+	candidates, err := s.collectCandidates(ctx, extractResp.Facts, candidateFilters)
 	if err != nil {
 		return SearchResponse{}, err
 	}
@@ -78,13 +108,17 @@ func (s *Service) Add(ctx context.Context, req AddRequest) (SearchResponse, erro
 	decideResp, err := s.llm.Decide(ctx, DecideRequest{
 		Facts:      extractResp.Facts,
 		Candidates: candidates,
-		Filters:    filters,
+		Filters:    candidateFilters,
 		Metadata:   req.Metadata,
 	})
 	if err != nil {
 		return SearchResponse{}, err
 	}
 
+	if s.revisions != nil {
+		ctx = withRevisionSource(ctx, revisionSource{messages: messages, model: s.modelName(ctx)})
+	}
+
 	actions := decideResp.Actions
 	if len(actions) == 0 && len(extractResp.Facts) > 0 {
 		actions = make([]DecisionAction, 0, len(extractResp.Facts))
@@ -100,7 +134,8 @@ func (s *Service) Add(ctx context.Context, req AddRequest) (SearchResponse, erro
 	for _, action := range actions {
 		switch strings.ToUpper(action.Event) {
 		case "ADD":
-			item, err := s.applyAdd(ctx, action.Text, filters, req.Metadata, embeddingEnabled)
+			actionFilters := withExpiry(filters, factExpiry(expiresAt, extractResp.Expirations, action.Text))
+			item, err := s.applyAdd(ctx, action.Text, actionFilters, req.Metadata, embeddingEnabled)
 			if err != nil {
 				return SearchResponse{}, err
 			}
@@ -109,7 +144,8 @@ func (s *Service) Add(ctx context.Context, req AddRequest) (SearchResponse, erro
 			})
 			results = append(results, item)
 		case "UPDATE":
-			item, err := s.applyUpdate(ctx, action.ID, action.Text, filters, req.Metadata, embeddingEnabled)
+			actionFilters := withExpiry(filters, factExpiry(expiresAt, extractResp.Expirations, action.Text))
+			item, err := s.applyUpdate(ctx, action.ID, action.Text, actionFilters, req.Metadata, embeddingEnabled)
 			if err != nil {
 				return SearchResponse{}, err
 			}
@@ -135,15 +171,48 @@ func (s *Service) Add(ctx context.Context, req AddRequest) (SearchResponse, erro
 	return SearchResponse{Results: results}, nil
 }
 
+// Search retrieves memories by dense, sparse or fused similarity. When the bot
+// has a rerank model configured, max(limit, top_n) candidates are retrieved,
+// reordered by the reranker and then truncated to the requested limit, or to
+// defaultSearchLimit when none is set.
+// Requests carrying a Subject only see memories allowed by the bot's scope policy.
 func (s *Service) Search(ctx context.Context, req SearchRequest) (SearchResponse, error) {
+	ctx = WithBotID(ctx, resolveBotID(req.BotID, buildSearchFilters(req)))
+	req.Filters = mergeFilters(req.Filters, s.scopeFilters(ctx, req.Subject))
+	reranker, topN := s.resolveReranker(ctx)
+	if reranker == nil {
+		return s.retrieve(ctx, req)
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	req.Limit = max(limit, topN)
+	resp, err := s.retrieve(ctx, req)
+	if err != nil {
+		return SearchResponse{}, err
+	}
+	if len(resp.Results) > req.Limit {
+		resp.Results = resp.Results[:req.Limit]
+	}
+	resp.Results = s.rerankItems(ctx, reranker, req.Query, resp.Results)
+	if len(resp.Results) > limit {
+		resp.Results = resp.Results[:limit]
+	}
+	return resp, nil
+}
+
+func (s *Service) retrieve(ctx context.Context, req SearchRequest) (SearchResponse, error) {
 	if strings.TrimSpace(req.Query) == "" {
 		return SearchResponse{}, fmt.Errorf("query is required")
 	}
 	if s.store == nil {
-		return SearchResponse{}, fmt.Errorf("qdrant store not configured")
+		return SearchResponse{}, fmt.Errorf("vector store not configured")
 	}
 	filters := buildSearchFilters(req)
 	ctx = WithBotID(ctx, resolveBotID(req.BotID, filters))
+	// Expired memories stay hidden until the sweeper removes them.
+	filters[expiresAtKey] = unexpiredAt(time.Now())
 	modality := ""
 	if raw, ok := filters["modality"].(string); ok {
 		modality = strings.ToLower(strings.TrimSpace(raw))
@@ -190,10 +259,14 @@ func (s *Service) Search(ctx context.Context, req SearchRequest) (SearchResponse
 	}
 
 	if embeddingEnabled {
-		if s.embedder == nil {
+		embedder := s.queryEmbedder
+		if embedder == nil {
+			embedder = s.embedder
+		}
+		if embedder == nil {
 			return SearchResponse{}, fmt.Errorf("embedder not configured")
 		}
-		vector, err := s.embedder.Embed(ctx, req.Query)
+		vector, err := embedder.Embed(ctx, req.Query)
 		if err != nil {
 			return SearchResponse{}, err
 		}
@@ -257,9 +330,9 @@ func (s *Service) Search(ctx context.Context, req SearchRequest) (SearchResponse
 		return SearchResponse{}, err
 	}
 	// Build sparse vector lookup before fusion (fusion discards raw points).
-	var sparseByID map[string]qdrantPoint
+	var sparseByID map[string]vectorPoint
 	if wantStats {
-		sparseByID = make(map[string]qdrantPoint)
+		sparseByID = make(map[string]vectorPoint)
 		for _, pts := range pointsBySource {
 			for _, p := range pts {
 				if len(p.SparseIndices) > 0 {
@@ -308,11 +381,11 @@ func (s *Service) EmbedUpsert(ctx context.Context, req EmbedUpsertRequest) (Embe
 	}
 
 	if s.store == nil {
-		return EmbedUpsertResponse{}, fmt.Errorf("qdrant store not configured")
+		return EmbedUpsertResponse{}, fmt.Errorf("vector store not configured")
 	}
 
 	vectorName := ""
-	if s.store.usesNamedVectors {
+	if s.store.UsesNamedVectors() {
 		vectorName = result.Model
 	}
 
@@ -322,7 +395,7 @@ func (s *Service) EmbedUpsert(ctx context.Context, req EmbedUpsertRequest) (Embe
 	if metadata, ok := payload["metadata"].(map[string]any); ok && result.Model != "" {
 		metadata["model_id"] = result.Model
 	}
-	if err := s.store.Upsert(ctx, []qdrantPoint{{
+	if err := s.store.Upsert(ctx, []vectorPoint{{
 		ID:         id,
 		Vector:     result.Embedding,
 		VectorName: vectorName,
@@ -348,7 +421,7 @@ func (s *Service) Update(ctx context.Context, req UpdateRequest) (MemoryItem, er
 		return MemoryItem{}, fmt.Errorf("memory is required")
 	}
 	if s.store == nil {
-		return MemoryItem{}, fmt.Errorf("qdrant store not configured")
+		return MemoryItem{}, fmt.Errorf("vector store not configured")
 	}
 	if s.bm25 == nil {
 		return MemoryItem{}, fmt.Errorf("bm25 indexer not configured")
@@ -398,11 +471,11 @@ func (s *Service) Update(ctx context.Context, req UpdateRequest) (MemoryItem, er
 	payload["lang"] = newLang
 
 	embeddingEnabled := req.EmbeddingEnabled != nil && *req.EmbeddingEnabled
-	point := qdrantPoint{
+	point := vectorPoint{
 		ID:               req.MemoryID,
 		SparseIndices:    sparseIndices,
 		SparseValues:     sparseValues,
-		SparseVectorName: s.store.sparseVectorName,
+		SparseVectorName: s.store.SparseVectorName(),
 		Payload:          payload,
 	}
 	if embeddingEnabled {
@@ -416,9 +489,16 @@ func (s *Service) Update(ctx context.Context, req UpdateRequest) (MemoryItem, er
 		point.Vector = vector
 		point.VectorName = s.vectorNameForText()
 	}
-	if err := s.store.Upsert(ctx, []qdrantPoint{point}); err != nil {
+	if err := s.store.Upsert(ctx, []vectorPoint{point}); err != nil {
 		return MemoryItem{}, err
 	}
+	s.recordRevision(ctx, Revision{
+		MemoryID:     req.MemoryID,
+		Action:       RevisionUpdate,
+		PreviousText: oldText,
+		NewText:      req.Memory,
+		Payload:      payload,
+	})
 	return payloadToMemoryItem(req.MemoryID, payload), nil
 }
 
@@ -453,6 +533,7 @@ func (s *Service) GetAll(ctx context.Context, req GetAllRequest) (SearchResponse
 	if len(filters) == 0 {
 		return SearchResponse{}, fmt.Errorf("bot_id, agent_id or run_id is required")
 	}
+	filters[expiresAtKey] = unexpiredAt(time.Now())
 
 	wantStats := !req.NoStats
 	points, err := s.store.List(ctx, req.Limit, filters, wantStats)
@@ -470,10 +551,36 @@ func (s *Service) GetAll(ctx context.Context, req GetAllRequest) (SearchResponse
 	return SearchResponse{Results: results}, nil
 }
 
+// ListAll returns every memory matching filters without sparse vector stats.
+// Unlike GetAll it is not capped by a limit.
+func (s *Service) ListAll(ctx context.Context, filters map[string]any) ([]MemoryItem, error) {
+	if len(filters) == 0 {
+		return nil, fmt.Errorf("filters are required")
+	}
+	points, err := s.scrollAll(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]MemoryItem, 0, len(points))
+	for _, point := range points {
+		items = append(items, payloadToMemoryItem(point.ID, point.Payload))
+	}
+	return items, nil
+}
+
 func (s *Service) Delete(ctx context.Context, memoryID string) (DeleteResponse, error) {
 	if strings.TrimSpace(memoryID) == "" {
 		return DeleteResponse{}, fmt.Errorf("memory_id is required")
 	}
+	if s.revisions != nil {
+		existing, err := s.store.Get(ctx, memoryID)
+		if err != nil {
+			return DeleteResponse{}, err
+		}
+		if existing != nil {
+			s.recordDeletions(ctx, []vectorPoint{*existing})
+		}
+	}
 	if err := s.store.Delete(ctx, memoryID); err != nil {
 		return DeleteResponse{}, err
 	}
@@ -494,6 +601,17 @@ func (s *Service) DeleteBatch(ctx context.Context, memoryIDs []string) (DeleteRe
 	if len(cleaned) == 0 {
 		return DeleteResponse{}, fmt.Errorf("memory_ids is required")
 	}
+	if s.revisions != nil {
+		for _, id := range cleaned {
+			existing, err := s.store.Get(ctx, id)
+			if err != nil {
+				return DeleteResponse{}, err
+			}
+			if existing != nil {
+				s.recordDeletions(ctx, []vectorPoint{*existing})
+			}
+		}
+	}
 	if err := s.store.DeleteBatch(ctx, cleaned); err != nil {
 		return DeleteResponse{}, err
 	}
@@ -517,18 +635,61 @@ func (s *Service) DeleteAll(ctx context.Context, req DeleteAllRequest) (DeleteRe
 	if len(filters) == 0 {
 		return DeleteResponse{}, fmt.Errorf("bot_id, agent_id or run_id is required")
 	}
+	if s.revisions != nil {
+		points, err := s.scrollAll(ctx, filters)
+		if err != nil {
+			return DeleteResponse{}, err
+		}
+		s.recordDeletions(ctx, points)
+	}
 	if err := s.store.DeleteAll(ctx, filters); err != nil {
 		return DeleteResponse{}, err
 	}
 	return DeleteResponse{Message: "Memories deleted successfully!"}, nil
 }
 
+// scrollAll returns every point matching filters. Unlike List it is not
+// capped, so callers that act on a whole scope see all of it.
+func (s *Service) scrollAll(ctx context.Context, filters map[string]any) ([]vectorPoint, error) {
+	var (
+		all    []vectorPoint
+		offset string
+	)
+	for {
+		points, next, err := s.store.Scroll(ctx, scrollAllBatchSize, filters, offset)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, points...)
+		if next == "" || len(points) == 0 {
+			return all, nil
+		}
+		offset = next
+	}
+}
+
 func (s *Service) Compact(ctx context.Context, filters map[string]any, ratio float64, decayDays int) (CompactResult, error) {
+	return s.compact(ctx, filters, ratio, decayDays, false)
+}
+
+// PreviewCompact asks the LLM how the memories would be consolidated without
+// changing anything. The result lists each merged fact with its source memories.
+func (s *Service) PreviewCompact(ctx context.Context, filters map[string]any, ratio float64, decayDays int) (CompactResult, error) {
+	return s.compact(ctx, filters, ratio, decayDays, true)
+}
+
+// compact consolidates memories separately for each subject (identity, user
+// and conversation tags), so that per-user and per-conversation scope
+// policies still hold afterwards. Each merged fact keeps its group's subject
+// tags and the latest expiry of its source memories; a fact merged from any
+// permanent memory stays permanent. All LLM calls finish before the store is
+// changed, so a failed call leaves every memory untouched.
+func (s *Service) compact(ctx context.Context, filters map[string]any, ratio float64, decayDays int, dryRun bool) (CompactResult, error) {
 	if s.llm == nil {
 		return CompactResult{}, fmt.Errorf("llm not configured")
 	}
 	if s.store == nil {
-		return CompactResult{}, fmt.Errorf("qdrant store not configured")
+		return CompactResult{}, fmt.Errorf("vector store not configured")
 	}
 	if ratio <= 0 || ratio > 1 {
 		ratio = 0.5
@@ -536,96 +697,196 @@ func (s *Service) Compact(ctx context.Context, filters map[string]any, ratio flo
 	ctx = WithBotID(ctx, resolveBotID("", filters))
 
 	// Fetch all existing memories.
-	points, err := s.store.List(ctx, 0, filters, false)
+	points, err := s.scrollAll(ctx, filters)
 	if err != nil {
 		return CompactResult{}, err
 	}
 	beforeCount := len(points)
-	if beforeCount <= 1 {
-		// Nothing to compact.
-		items := make([]MemoryItem, 0, len(points))
-		for _, p := range points {
-			items = append(items, payloadToMemoryItem(p.ID, p.Payload))
-		}
-		return CompactResult{
-			BeforeCount: beforeCount,
-			AfterCount:  beforeCount,
-			Ratio:       1.0,
-			Results:     items,
-		}, nil
-	}
 
-	// Build candidate list and compute target.
-	candidates := make([]CandidateMemory, 0, beforeCount)
+	type compactGroup struct {
+		subject *Subject
+		points  []vectorPoint
+		merges  []CompactMerge
+	}
+	groupsByKey := map[string]*compactGroup{}
+	var keys []string
 	for _, p := range points {
-		candidates = append(candidates, CandidateMemory{
-			ID:        p.ID,
-			Memory:    fmt.Sprint(p.Payload["data"]),
-			CreatedAt: fmt.Sprint(p.Payload["created_at"]),
+		subject := subjectFromPayload(p.Payload)
+		key := subject.ChannelIdentityID + "\x00" + subject.UserID + "\x00" + subject.ConversationID
+		group, ok := groupsByKey[key]
+		if !ok {
+			group = &compactGroup{subject: subject}
+			groupsByKey[key] = group
+			keys = append(keys, key)
+		}
+		group.points = append(group.points, p)
+	}
+	sort.Strings(keys)
+
+	// Ask the LLM to consolidate each group with more than one memory.
+	kept := make([]MemoryItem, 0)
+	var merges []CompactMerge
+	var compacted []*compactGroup
+	for _, key := range keys {
+		group := groupsByKey[key]
+		if len(group.points) <= 1 {
+			for _, p := range group.points {
+				kept = append(kept, payloadToMemoryItem(p.ID, p.Payload))
+			}
+			continue
+		}
+		candidates := make([]CandidateMemory, 0, len(group.points))
+		for _, p := range group.points {
+			candidates = append(candidates, CandidateMemory{
+				ID:        p.ID,
+				Memory:    fmt.Sprint(p.Payload["data"]),
+				CreatedAt: fmt.Sprint(p.Payload["created_at"]),
+			})
+		}
+		targetCount := int(math.Round(float64(len(candidates)) * ratio))
+		if targetCount < 1 {
+			targetCount = 1
+		}
+		compactResp, err := s.llm.Compact(ctx, CompactRequest{
+			Memories:    candidates,
+			TargetCount: targetCount,
+			DecayDays:   decayDays,
 		})
-	}
-	targetCount := int(math.Round(float64(beforeCount) * ratio))
-	if targetCount < 1 {
-		targetCount = 1
+		if err != nil {
+			return CompactResult{}, fmt.Errorf("compact llm call failed: %w", err)
+		}
+		if len(compactResp.Facts) == 0 {
+			return CompactResult{}, fmt.Errorf("compact returned no facts")
+		}
+		group.merges = buildCompactMerges(compactResp, candidates)
+		merges = append(merges, group.merges...)
+		compacted = append(compacted, group)
 	}
 
-	// Ask LLM to consolidate.
-	compactResp, err := s.llm.Compact(ctx, CompactRequest{
-		Memories:    candidates,
-		TargetCount: targetCount,
-		DecayDays:   decayDays,
-	})
-	if err != nil {
-		return CompactResult{}, fmt.Errorf("compact llm call failed: %w", err)
-	}
-	if len(compactResp.Facts) == 0 {
-		return CompactResult{}, fmt.Errorf("compact returned no facts")
+	afterCount := len(kept) + len(merges)
+	if dryRun || len(compacted) == 0 {
+		result := CompactResult{
+			BeforeCount: beforeCount,
+			AfterCount:  afterCount,
+			Ratio:       1.0,
+			Results:     kept,
+			Merges:      merges,
+			DryRun:      dryRun,
+		}
+		if beforeCount > 0 {
+			result.Ratio = math.Round(float64(afterCount)/float64(beforeCount)*100) / 100
+		}
+		if dryRun {
+			result.Results = []MemoryItem{}
+		}
+		return result, nil
 	}
 
-	// Delete old memories.
-	if err := s.store.DeleteAll(ctx, filters); err != nil {
-		return CompactResult{}, fmt.Errorf("compact delete old failed: %w", err)
+	// Every change below is recorded as a COMPACT revision.
+	if s.revisions != nil {
+		ctx = withRevisionSource(ctx, revisionSource{action: RevisionCompact, model: s.modelName(ctx)})
 	}
 
-	// Reset BM25 stats for deleted documents.
-	if s.bm25 != nil {
-		for _, p := range points {
-			text := fmt.Sprint(p.Payload["data"])
-			lang := fmt.Sprint(p.Payload["lang"])
-			if strings.TrimSpace(text) == "" || strings.TrimSpace(lang) == "" {
-				continue
-			}
-			freq, docLen, err := s.bm25.TermFrequencies(lang, text)
-			if err != nil {
-				continue
-			}
-			s.bm25.RemoveDocument(lang, freq, docLen)
+	results := kept
+	for _, group := range compacted {
+		s.recordDeletions(ctx, group.points)
+		ids := make([]string, 0, len(group.points))
+		for _, p := range group.points {
+			ids = append(ids, p.ID)
+		}
+		// Delete old memories.
+		if err := s.store.DeleteBatch(ctx, ids); err != nil {
+			return CompactResult{}, fmt.Errorf("compact delete old failed: %w", err)
+		}
+		// Reset BM25 stats for deleted documents.
+		for _, p := range group.points {
+			s.removeFromIndex(ctx, p.Payload)
 		}
-	}
 
-	// Add compacted facts.
-	results := make([]MemoryItem, 0, len(compactResp.Facts))
-	for _, fact := range compactResp.Facts {
-		if strings.TrimSpace(fact) == "" {
-			continue
+		// Add compacted facts with the group's subject and their sources' expiry.
+		expiries := make(map[string]string, len(group.points))
+		for _, p := range group.points {
+			expiries[p.ID], _ = p.Payload[expiresAtKey].(string)
 		}
-		item, err := s.applyAdd(ctx, fact, filters, nil, false)
-		if err != nil {
-			return CompactResult{}, fmt.Errorf("compact add failed: %w", err)
+		groupFilters := mergeFilters(filters, group.subject.payload())
+		for _, merge := range group.merges {
+			sourceIDs := make([]string, 0, len(merge.Sources))
+			for _, source := range merge.Sources {
+				sourceIDs = append(sourceIDs, source.ID)
+			}
+			if len(sourceIDs) == 0 {
+				sourceIDs = ids
+			}
+			item, err := s.applyAdd(ctx, merge.Memory, withExpiry(groupFilters, latestExpiry(expiries, sourceIDs)), nil, false)
+			if err != nil {
+				return CompactResult{}, fmt.Errorf("compact add failed: %w", err)
+			}
+			results = append(results, item)
 		}
-		results = append(results, item)
 	}
 
-	afterCount := len(results)
+	afterCount = len(results)
 	actualRatio := float64(afterCount) / float64(beforeCount)
 	return CompactResult{
 		BeforeCount: beforeCount,
 		AfterCount:  afterCount,
 		Ratio:       math.Round(actualRatio*100) / 100,
 		Results:     results,
+		Merges:      merges,
 	}, nil
 }
 
+// subjectFromPayload returns the subject tags a memory was written with.
+func subjectFromPayload(payload map[string]any) *Subject {
+	subject := &Subject{}
+	subject.ChannelIdentityID, _ = payload[SubjectChannelIdentityKey].(string)
+	subject.UserID, _ = payload[SubjectUserKey].(string)
+	subject.ConversationID, _ = payload[SubjectConversationKey].(string)
+	return subject
+}
+
+// latestExpiry returns the latest expiry among ids, or "" when any of them is
+// permanent. expiries maps memory IDs to their formatted expires_at.
+func latestExpiry(expiries map[string]string, ids []string) string {
+	var latest time.Time
+	for _, id := range ids {
+		t, ok := parseExpiry(expiries[id])
+		if !ok {
+			return ""
+		}
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	if latest.IsZero() {
+		return ""
+	}
+	return formatExpiry(latest)
+}
+
+func buildCompactMerges(resp CompactResponse, candidates []CandidateMemory) []CompactMerge {
+	byID := make(map[string]CandidateMemory, len(candidates))
+	for _, candidate := range candidates {
+		byID[candidate.ID] = candidate
+	}
+	merges := make([]CompactMerge, 0, len(resp.Facts))
+	for i, fact := range resp.Facts {
+		if strings.TrimSpace(fact) == "" {
+			continue
+		}
+		merge := CompactMerge{Memory: fact, Sources: []CandidateMemory{}}
+		if i < len(resp.Sources) {
+			for _, id := range resp.Sources[i] {
+				if candidate, ok := byID[strings.TrimSpace(id)]; ok {
+					merge.Sources = append(merge.Sources, candidate)
+				}
+			}
+		}
+		merges = append(merges, merge)
+	}
+	return merges
+}
+
 const (
 	// Estimated sparse vector overhead per point: ~200 dims * 8 bytes (4 index + 4 value).
 	sparseVectorOverheadBytes = 1600
@@ -635,9 +896,9 @@ const (
 
 func (s *Service) Usage(ctx context.Context, filters map[string]any) (UsageResponse, error) {
 	if s.store == nil {
-		return UsageResponse{}, fmt.Errorf("qdrant store not configured")
+		return UsageResponse{}, fmt.Errorf("vector store not configured")
 	}
-	points, err := s.store.List(ctx, 0, filters, false)
+	points, err := s.scrollAll(ctx, filters)
 	if err != nil {
 		return UsageResponse{}, err
 	}
@@ -660,11 +921,23 @@ func (s *Service) Usage(ctx context.Context, filters map[string]any) (UsageRespo
 	}, nil
 }
 
+// Count returns the number of memories matching filters.
+func (s *Service) Count(ctx context.Context, filters map[string]any) (int, error) {
+	if s.store == nil {
+		return 0, fmt.Errorf("vector store not configured")
+	}
+	count, err := s.store.Count(ctx, filters)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
 func (s *Service) WarmupBM25(ctx context.Context, batchSize int) error {
 	if s.bm25 == nil || s.store == nil {
 		return nil
 	}
-	var offset *qdrant.PointId
+	var offset string
 	for {
 		points, next, err := s.store.Scroll(ctx, batchSize, nil, offset)
 		if err != nil {
@@ -689,7 +962,7 @@ func (s *Service) WarmupBM25(ctx context.Context, batchSize int) error {
 			}
 			s.bm25.AddDocument(lang, termFreq, docLen)
 		}
-		if next == nil {
+		if next == "" {
 			break
 		}
 		offset = next
@@ -713,6 +986,9 @@ func (s *Service) addRawMessages(ctx context.Context, messages []Message, filter
 }
 
 func (s *Service) collectCandidates(ctx context.Context, facts []string, filters map[string]any) ([]CandidateMemory, error) {
+	if s.store == nil {
+		return []CandidateMemory{}, nil
+	}
 	unique := map[string]CandidateMemory{}
 	for _, fact := range facts {
 		if s.bm25 == nil {
@@ -750,7 +1026,7 @@ func (s *Service) collectCandidates(ctx context.Context, facts []string, filters
 
 func (s *Service) applyAdd(ctx context.Context, text string, filters map[string]any, metadata map[string]any, embeddingEnabled bool) (MemoryItem, error) {
 	if s.store == nil {
-		return MemoryItem{}, fmt.Errorf("qdrant store not configured")
+		return MemoryItem{}, fmt.Errorf("vector store not configured")
 	}
 	if s.bm25 == nil {
 		return MemoryItem{}, fmt.Errorf("bm25 indexer not configured")
@@ -767,11 +1043,11 @@ func (s *Service) applyAdd(ctx context.Context, text string, filters map[string]
 	id := uuid.NewString()
 	payload := buildPayload(text, filters, metadata, "")
 	payload["lang"] = lang
-	point := qdrantPoint{
+	point := vectorPoint{
 		ID:               id,
 		SparseIndices:    sparseIndices,
 		SparseValues:     sparseValues,
-		SparseVectorName: s.store.sparseVectorName,
+		SparseVectorName: s.store.SparseVectorName(),
 		Payload:          payload,
 	}
 	if embeddingEnabled {
@@ -785,9 +1061,15 @@ func (s *Service) applyAdd(ctx context.Context, text string, filters map[string]
 		point.Vector = vector
 		point.VectorName = s.vectorNameForText()
 	}
-	if err := s.store.Upsert(ctx, []qdrantPoint{point}); err != nil {
+	if err := s.store.Upsert(ctx, []vectorPoint{point}); err != nil {
 		return MemoryItem{}, err
 	}
+	s.recordRevision(ctx, Revision{
+		MemoryID: id,
+		Action:   RevisionAdd,
+		NewText:  text,
+		Payload:  payload,
+	})
 	return payloadToMemoryItem(id, payload), nil
 }
 
@@ -795,7 +1077,7 @@ func (s *Service) applyAdd(ctx context.Context, text string, filters map[string]
 // Like applyAdd but preserves the given ID instead of generating a new UUID.
 func (s *Service) RebuildAdd(ctx context.Context, id, text string, filters map[string]any) (MemoryItem, error) {
 	if s.store == nil {
-		return MemoryItem{}, fmt.Errorf("qdrant store not configured")
+		return MemoryItem{}, fmt.Errorf("vector store not configured")
 	}
 	if s.bm25 == nil {
 		return MemoryItem{}, fmt.Errorf("bm25 indexer not configured")
@@ -814,14 +1096,14 @@ func (s *Service) RebuildAdd(ctx context.Context, id, text string, filters map[s
 	sparseIndices, sparseValues := s.bm25.AddDocument(lang, termFreq, docLen)
 	payload := buildPayload(text, filters, nil, "")
 	payload["lang"] = lang
-	point := qdrantPoint{
+	point := vectorPoint{
 		ID:               id,
 		SparseIndices:    sparseIndices,
 		SparseValues:     sparseValues,
-		SparseVectorName: s.store.sparseVectorName,
+		SparseVectorName: s.store.SparseVectorName(),
 		Payload:          payload,
 	}
-	if err := s.store.Upsert(ctx, []qdrantPoint{point}); err != nil {
+	if err := s.store.Upsert(ctx, []vectorPoint{point}); err != nil {
 		return MemoryItem{}, err
 	}
 	return payloadToMemoryItem(id, payload), nil
@@ -876,11 +1158,11 @@ func (s *Service) applyUpdate(ctx context.Context, id, text string, filters map[
 	if filters != nil {
 		applyFiltersToPayload(payload, filters)
 	}
-	point := qdrantPoint{
+	point := vectorPoint{
 		ID:               id,
 		SparseIndices:    sparseIndices,
 		SparseValues:     sparseValues,
-		SparseVectorName: s.store.sparseVectorName,
+		SparseVectorName: s.store.SparseVectorName(),
 		Payload:          payload,
 	}
 	if embeddingEnabled {
@@ -894,9 +1176,16 @@ func (s *Service) applyUpdate(ctx context.Context, id, text string, filters map[
 		point.Vector = vector
 		point.VectorName = s.vectorNameForText()
 	}
-	if err := s.store.Upsert(ctx, []qdrantPoint{point}); err != nil {
+	if err := s.store.Upsert(ctx, []vectorPoint{point}); err != nil {
 		return MemoryItem{}, err
 	}
+	s.recordRevision(ctx, Revision{
+		MemoryID:     id,
+		Action:       RevisionUpdate,
+		PreviousText: oldText,
+		NewText:      text,
+		Payload:      payload,
+	})
 	return payloadToMemoryItem(id, payload), nil
 }
 
@@ -934,6 +1223,7 @@ func (s *Service) applyDelete(ctx context.Context, id string) (MemoryItem, error
 	if err := s.store.Delete(ctx, id); err != nil {
 		return MemoryItem{}, err
 	}
+	s.recordDeletions(ctx, []vectorPoint{*existing})
 	return item, nil
 }
 
@@ -1095,14 +1385,14 @@ func buildEmbeddingPayload(req EmbedUpsertRequest, filters map[string]any) map[s
 }
 
 func (s *Service) vectorNameForText() string {
-	if s.store == nil || !s.store.usesNamedVectors {
+	if s.store == nil || !s.store.UsesNamedVectors() {
 		return ""
 	}
 	return strings.TrimSpace(s.defaultTextModelID)
 }
 
 func (s *Service) vectorNameForMultimodal() string {
-	if s.store == nil || !s.store.usesNamedVectors {
+	if s.store == nil || !s.store.UsesNamedVectors() {
 		return ""
 	}
 	return strings.TrimSpace(s.defaultMultimodalModelID)
@@ -1153,6 +1443,18 @@ func payloadToMemoryItem(id string, payload map[string]any) MemoryItem {
 	if v, ok := payload["run_id"].(string); ok {
 		item.RunID = v
 	}
+	if v, ok := payload[SubjectChannelIdentityKey].(string); ok {
+		item.ChannelIdentityID = v
+	}
+	if v, ok := payload[SubjectUserKey].(string); ok {
+		item.UserID = v
+	}
+	if v, ok := payload[SubjectConversationKey].(string); ok {
+		item.ConversationID = v
+	}
+	if v, ok := payload[expiresAtKey].(string); ok {
+		item.ExpiresAt = v
+	}
 	if meta, ok := payload["metadata"].(map[string]any); ok {
 		item.Metadata = meta
 	} else if payload["metadata"] == nil {
@@ -1248,7 +1550,7 @@ const (
 	rrfK = 60.0
 )
 
-func fuseByRankFusion(pointsBySource map[string][]qdrantPoint, _ map[string][]float64) []MemoryItem {
+func fuseByRankFusion(pointsBySource map[string][]vectorPoint, _ map[string][]float64) []MemoryItem {
 	candidates := map[string]*rerankCandidate{}
 	rrfScores := map[string]float64{}
 
@@ -98,11 +98,11 @@ func reflectContains(s, substr string) bool {
 }
 
 func TestRankFusion_Logic(t *testing.T) {
-	p1 := qdrantPoint{ID: "1", Payload: map[string]any{"data": "result 1"}}
-	p2 := qdrantPoint{ID: "2", Payload: map[string]any{"data": "result 2"}}
+	p1 := vectorPoint{ID: "1", Payload: map[string]any{"data": "result 1"}}
+	p2 := vectorPoint{ID: "2", Payload: map[string]any{"data": "result 2"}}
 
 	// Source A: 1 first, 2 second; Source B: 2 first, 1 second.
-	pointsBySource := map[string][]qdrantPoint{
+	pointsBySource := map[string][]vectorPoint{
 		"source_a": {p1, p2},
 		"source_b": {p2, p1},
 	}
@@ -121,3 +121,52 @@ func TestRankFusion_Logic(t *testing.T) {
 		// Symmetric case: both get same RRF score (e.g. 1/(k+1)+1/(k+2) for k=60).
 	}
 }
+
+func TestService_ListAllIsNotCapped(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := newMemStore(false)
+	svc := newArchiveTestService(store, nil, "")
+	const total = 260
+	points := make([]vectorPoint, 0, total+1)
+	for i := 0; i < total; i++ {
+		points = append(points, vectorPoint{
+			ID:      fmt.Sprintf("00000000-0000-0000-0000-%012d", i),
+			Payload: map[string]any{"bot_id": "bot-a", "subject_user_id": "user-1", "data": fmt.Sprintf("fact %d", i)},
+		})
+	}
+	points = append(points, vectorPoint{
+		ID:      "ffffffff-0000-0000-0000-000000000000",
+		Payload: map[string]any{"bot_id": "bot-a", "subject_user_id": "user-2", "data": "other"},
+	})
+	if err := store.Upsert(ctx, points); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := svc.ListAll(ctx, map[string]any{"bot_id": "bot-a", "subject_user_id": "user-1"})
+	if err != nil {
+		t.Fatalf("ListAll failed: %v", err)
+	}
+	if len(items) != total {
+		t.Fatalf("got %d items, want %d", len(items), total)
+	}
+	if _, err := svc.ListAll(ctx, nil); err == nil {
+		t.Fatal("expected error for empty filters")
+	}
+}
+
+func TestService_Search_UsesQueryEmbedder(t *testing.T) {
+	t.Parallel()
+	documents := &stubEmbedder{}
+	queries := &stubEmbedder{}
+	svc := newArchiveTestService(newMemStore(false), documents, "")
+	svc.SetQueryEmbedder(queries)
+
+	enabled := true
+	if _, err := svc.Search(context.Background(), SearchRequest{Query: "tea", BotID: "bot-a", EmbeddingEnabled: &enabled}); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if queries.calls != 1 || documents.calls != 0 {
+		t.Fatalf("query embeds = %d, document embeds = %d; want the query embedder only", queries.calls, documents.calls)
+	}
+}
@@ -1,6 +1,9 @@
 package memory
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // LLM is the interface for LLM operations needed by memory service
 type LLM interface {
@@ -25,6 +28,8 @@ type AddRequest struct {
 	Filters          map[string]any `json:"filters,omitempty"`
 	Infer            *bool          `json:"infer,omitempty"`
 	EmbeddingEnabled *bool          `json:"embedding_enabled,omitempty"`
+	Subject          *Subject       `json:"subject,omitempty"`
+	ExpiresAt        *time.Time     `json:"expires_at,omitempty"`
 }
 
 type SearchRequest struct {
@@ -37,6 +42,7 @@ type SearchRequest struct {
 	Sources          []string       `json:"sources,omitempty"`
 	EmbeddingEnabled *bool          `json:"embedding_enabled,omitempty"`
 	NoStats          bool           `json:"no_stats,omitempty"`
+	Subject          *Subject       `json:"subject,omitempty"`
 }
 
 type UpdateRequest struct {
@@ -88,18 +94,24 @@ type EmbedUpsertResponse struct {
 }
 
 type MemoryItem struct {
-	ID          string         `json:"id"`
-	Memory      string         `json:"memory"`
-	Hash        string         `json:"hash,omitempty"`
-	CreatedAt   string         `json:"created_at,omitempty"`
-	UpdatedAt   string         `json:"updated_at,omitempty"`
-	Score       float64        `json:"score,omitempty"`
-	Metadata    map[string]any `json:"metadata,omitempty"`
-	BotID       string         `json:"bot_id,omitempty"`
-	AgentID     string         `json:"agent_id,omitempty"`
-	RunID       string         `json:"run_id,omitempty"`
-	TopKBuckets []TopKBucket   `json:"top_k_buckets,omitempty"`
-	CDFCurve    []CDFPoint     `json:"cdf_curve,omitempty"`
+	ID                string         `json:"id"`
+	Memory            string         `json:"memory"`
+	Hash              string         `json:"hash,omitempty"`
+	CreatedAt         string         `json:"created_at,omitempty"`
+	UpdatedAt         string         `json:"updated_at,omitempty"`
+	Score             float64        `json:"score,omitempty"`
+	RetrievalScore    float64        `json:"retrieval_score,omitempty"`
+	RerankScore       *float64       `json:"rerank_score,omitempty"`
+	Metadata          map[string]any `json:"metadata,omitempty"`
+	BotID             string         `json:"bot_id,omitempty"`
+	AgentID           string         `json:"agent_id,omitempty"`
+	RunID             string         `json:"run_id,omitempty"`
+	ChannelIdentityID string         `json:"channel_identity_id,omitempty"`
+	UserID            string         `json:"user_id,omitempty"`
+	ConversationID    string         `json:"conversation_id,omitempty"`
+	ExpiresAt         string         `json:"expires_at,omitempty"`
+	TopKBuckets       []TopKBucket   `json:"top_k_buckets,omitempty"`
+	CDFCurve          []CDFPoint     `json:"cdf_curve,omitempty"`
 }
 
 // TopKBucket represents one bar in the Top-K sparse dimension bar chart.
@@ -131,6 +143,8 @@ type ExtractRequest struct {
 
 type ExtractResponse struct {
 	Facts []string `json:"facts"`
+	// Expirations maps time-bound facts to the date or time they stop holding.
+	Expirations map[string]string `json:"expirations,omitempty"`
 }
 
 type CandidateMemory struct {
@@ -166,13 +180,23 @@ type CompactRequest struct {
 
 type CompactResponse struct {
 	Facts []string `json:"facts"`
+	// Sources lists, for each fact, the IDs of the input memories merged into it.
+	Sources [][]string `json:"sources,omitempty"`
+}
+
+// CompactMerge is one consolidated fact and the memories folded into it.
+type CompactMerge struct {
+	Memory  string            `json:"memory"`
+	Sources []CandidateMemory `json:"sources"`
 }
 
 type CompactResult struct {
-	BeforeCount int          `json:"before_count"`
-	AfterCount  int          `json:"after_count"`
-	Ratio       float64      `json:"ratio"`
-	Results     []MemoryItem `json:"results"`
+	BeforeCount int            `json:"before_count"`
+	AfterCount  int            `json:"after_count"`
+	Ratio       float64        `json:"ratio"`
+	Results     []MemoryItem   `json:"results"`
+	Merges      []CompactMerge `json:"merges,omitempty"`
+	DryRun      bool           `json:"dry_run,omitempty"`
 }
 
 type UsageResponse struct {
@@ -0,0 +1,37 @@
+package memory
+
+import "context"
+
+// VectorStore persists memory points (payload, dense vectors and the BM25
+// sparse vector) and serves filtered similarity queries. Filters are payload
+// key/value matches as built by buildFilters; nested keys use dot notation.
+type VectorStore interface {
+	Upsert(ctx context.Context, points []vectorPoint) error
+	Search(ctx context.Context, vector []float32, limit int, filters map[string]any, vectorName string) ([]vectorPoint, []float64, error)
+	SearchSparse(ctx context.Context, indices []uint32, values []float32, limit int, filters map[string]any, withSparseVectors bool) ([]vectorPoint, []float64, error)
+	SearchBySources(ctx context.Context, vector []float32, limit int, filters map[string]any, sources []string, vectorName string) (map[string][]vectorPoint, map[string][]float64, error)
+	SearchSparseBySources(ctx context.Context, indices []uint32, values []float32, limit int, filters map[string]any, sources []string, withSparseVectors bool) (map[string][]vectorPoint, map[string][]float64, error)
+	// Get returns nil without error when the point does not exist.
+	Get(ctx context.Context, id string) (*vectorPoint, error)
+	Delete(ctx context.Context, id string) error
+	DeleteBatch(ctx context.Context, ids []string) error
+	// DeleteAll removes every point matching filters; empty filters are rejected.
+	DeleteAll(ctx context.Context, filters map[string]any) error
+	List(ctx context.Context, limit int, filters map[string]any, withSparseVectors bool) ([]vectorPoint, error)
+	// Scroll pages through points in ID order. An empty offset starts from the
+	// beginning; an empty next offset means there are no more points.
+	Scroll(ctx context.Context, limit int, filters map[string]any, offset string) ([]vectorPoint, string, error)
+	// ScrollVectors is Scroll that also returns each point's dense vector and
+	// its name (empty for unnamed vectors).
+	ScrollVectors(ctx context.Context, limit int, filters map[string]any, offset string) ([]vectorPoint, string, error)
+	Count(ctx context.Context, filters map[string]any) (uint64, error)
+	// UsesNamedVectors reports whether dense vectors are keyed by embedding model.
+	UsesNamedVectors() bool
+	// SparseVectorName returns the name of the BM25 sparse vector.
+	SparseVectorName() string
+}
+
+var (
+	_ VectorStore = (*QdrantStore)(nil)
+	_ VectorStore = (*PgVectorStore)(nil)
+)
@@ -184,6 +184,15 @@ func TestModel_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid rerank model",
+			model: models.Model{
+				ModelID:       "rerank-v3.5",
+				LlmProviderID: "11111111-1111-1111-1111-111111111111",
+				Type:          models.ModelTypeRerank,
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalid model type",
 			model: models.Model{
@@ -283,6 +292,7 @@ func TestModelTypes(t *testing.T) {
 	t.Run("ModelType constants", func(t *testing.T) {
 		assert.Equal(t, models.ModelType("chat"), models.ModelTypeChat)
 		assert.Equal(t, models.ModelType("embedding"), models.ModelTypeEmbedding)
+		assert.Equal(t, models.ModelType("rerank"), models.ModelTypeRerank)
 	})
 
 	t.Run("ClientType constants", func(t *testing.T) {
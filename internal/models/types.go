@@ -12,6 +12,7 @@ type ModelType string
 const (
 	ModelTypeChat      ModelType = "chat"
 	ModelTypeEmbedding ModelType = "embedding"
+	ModelTypeRerank    ModelType = "rerank"
 )
 
 const (
@@ -58,7 +59,7 @@ func (m *Model) Validate() error {
 	if _, err := uuid.Parse(m.LlmProviderID); err != nil {
 		return errors.New("llm provider ID must be a valid UUID")
 	}
-	if m.Type != ModelTypeChat && m.Type != ModelTypeEmbedding {
+	if !isValidModelType(m.Type) {
 		return errors.New("invalid model type")
 	}
 	if m.Type == ModelTypeChat {
@@ -0,0 +1,294 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/memory"
+)
+
+// Compaction run triggers and statuses.
+const (
+	CompactionTriggerSchedule = "schedule"
+	CompactionTriggerManual   = "manual"
+
+	CompactionStatusSuccess = "success"
+	CompactionStatusSkipped = "skipped"
+	CompactionStatusFailed  = "failed"
+)
+
+const (
+	compactionMemoryNamespace = "bot"
+	compactionJobPrefix       = "compaction:"
+	defaultCompactionRunLimit = 20
+)
+
+// MemoryCompactor consolidates memories; implemented by memory.Service.
+type MemoryCompactor interface {
+	Compact(ctx context.Context, filters map[string]any, ratio float64, decayDays int) (memory.CompactResult, error)
+	PreviewCompact(ctx context.Context, filters map[string]any, ratio float64, decayDays int) (memory.CompactResult, error)
+	Count(ctx context.Context, filters map[string]any) (int, error)
+}
+
+// CompactionFS mirrors compacted memories into the bot filesystem; implemented
+// by memory.MemoryFS.
+type CompactionFS interface {
+	RebuildFiles(ctx context.Context, botID string, items []memory.MemoryItem, filters map[string]any) error
+}
+
+// CompactionRequest overrides the bot's compaction settings for a single run.
+type CompactionRequest struct {
+	Ratio     *float64 `json:"ratio,omitempty"`
+	DecayDays *int     `json:"decay_days,omitempty"`
+	DryRun    bool     `json:"dry_run,omitempty"`
+}
+
+// CompactionRun is one recorded memory compaction of a bot.
+type CompactionRun struct {
+	ID          string                `json:"id"`
+	BotID       string                `json:"bot_id"`
+	Trigger     string                `json:"trigger"`
+	DryRun      bool                  `json:"dry_run"`
+	Status      string                `json:"status"`
+	Ratio       float64               `json:"ratio"`
+	DecayDays   int                   `json:"decay_days"`
+	BeforeCount int                   `json:"before_count"`
+	AfterCount  int                   `json:"after_count"`
+	Merges      []memory.CompactMerge `json:"merges"`
+	Error       string                `json:"error,omitempty"`
+	StartedAt   time.Time             `json:"started_at"`
+	FinishedAt  time.Time             `json:"finished_at"`
+}
+
+type CompactionRunListResponse struct {
+	Items []CompactionRun `json:"items"`
+}
+
+// SetMemoryCompactor enables scheduled memory compaction. fs may be nil.
+func (s *Service) SetMemoryCompactor(compactor MemoryCompactor, fs CompactionFS) {
+	s.compactor = compactor
+	s.compactionFS = fs
+}
+
+// SyncCompaction reschedules the automatic compaction of a bot from its
+// current settings, removing the job when the cron pattern is cleared.
+func (s *Service) SyncCompaction(ctx context.Context, botID string) error {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return err
+	}
+	s.removeJob(compactionJobPrefix + botID)
+	row, err := s.queries.GetBotCompactionSettings(ctx, pgBotID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	return s.scheduleCompaction(botID, row.CompactionCron)
+}
+
+func (s *Service) bootstrapCompaction(ctx context.Context) error {
+	rows, err := s.queries.ListBotCompactionSchedules(ctx)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		botID := row.ID.String()
+		if err := s.scheduleCompaction(botID, row.CompactionCron); err != nil {
+			// A bad pattern on one bot must not block startup.
+			s.logger.Warn("schedule memory compaction failed", slog.String("bot_id", botID), slog.Any("error", err))
+		}
+	}
+	return nil
+}
+
+func (s *Service) scheduleCompaction(botID, pattern string) error {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || s.compactor == nil {
+		return nil
+	}
+	job := func() {
+		if _, _, err := s.RunCompaction(context.Background(), botID, CompactionTriggerSchedule, CompactionRequest{}); err != nil {
+			s.logger.Error("scheduled memory compaction failed", slog.String("bot_id", botID), slog.Any("error", err))
+		}
+	}
+	entryID, err := s.cron.AddFunc(pattern, job)
+	if err != nil {
+		return fmt.Errorf("invalid compaction cron: %w", err)
+	}
+	s.mu.Lock()
+	s.jobs[compactionJobPrefix+botID] = entryID
+	s.mu.Unlock()
+	return nil
+}
+
+// RunCompaction compacts the bot-shared memories of a bot and records the run.
+// Unset request fields fall back to the bot's compaction settings. Scheduled
+// runs are skipped while the bot holds no more than its configured maximum
+// number of memories. Dry runs only preview which memories would merge.
+func (s *Service) RunCompaction(ctx context.Context, botID, trigger string, req CompactionRequest) (CompactionRun, memory.CompactResult, error) {
+	if s.compactor == nil {
+		return CompactionRun{}, memory.CompactResult{}, fmt.Errorf("memory compactor not configured")
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return CompactionRun{}, memory.CompactResult{}, err
+	}
+	settings, err := s.queries.GetBotCompactionSettings(ctx, pgBotID)
+	if err != nil {
+		return CompactionRun{}, memory.CompactResult{}, fmt.Errorf("get compaction settings: %w", err)
+	}
+	run := CompactionRun{
+		BotID:     botID,
+		Trigger:   trigger,
+		DryRun:    req.DryRun,
+		Ratio:     settings.CompactionRatio,
+		DecayDays: int(settings.CompactionDecayDays),
+		Merges:    []memory.CompactMerge{},
+		StartedAt: time.Now().UTC(),
+	}
+	if req.Ratio != nil {
+		run.Ratio = *req.Ratio
+	}
+	if req.DecayDays != nil {
+		run.DecayDays = *req.DecayDays
+	}
+	filters := map[string]any{
+		"namespace": compactionMemoryNamespace,
+		"scopeId":   botID,
+	}
+
+	result, runErr := s.compactMemories(ctx, botID, filters, settings.CompactionMaxMemories, &run)
+	if runErr != nil {
+		run.Status = CompactionStatusFailed
+		run.Error = runErr.Error()
+	}
+	run.FinishedAt = time.Now().UTC()
+	recorded, err := s.recordCompactionRun(ctx, pgBotID, run)
+	if err != nil {
+		s.logger.Warn("record memory compaction run failed", slog.String("bot_id", botID), slog.Any("error", err))
+	} else {
+		run = recorded
+	}
+	return run, result, runErr
+}
+
+func (s *Service) compactMemories(ctx context.Context, botID string, filters map[string]any, maxMemories int32, run *CompactionRun) (memory.CompactResult, error) {
+	if run.Trigger == CompactionTriggerSchedule && maxMemories > 0 {
+		count, err := s.compactor.Count(ctx, filters)
+		if err != nil {
+			return memory.CompactResult{}, err
+		}
+		if count <= int(maxMemories) {
+			run.Status = CompactionStatusSkipped
+			run.BeforeCount = count
+			run.AfterCount = count
+			return memory.CompactResult{BeforeCount: count, AfterCount: count, Ratio: 1, Results: []memory.MemoryItem{}}, nil
+		}
+	}
+
+	var (
+		result memory.CompactResult
+		err    error
+	)
+	if run.DryRun {
+		result, err = s.compactor.PreviewCompact(ctx, filters, run.Ratio, run.DecayDays)
+	} else {
+		result, err = s.compactor.Compact(ctx, filters, run.Ratio, run.DecayDays)
+	}
+	if err != nil {
+		return memory.CompactResult{}, err
+	}
+	run.Status = CompactionStatusSuccess
+	run.BeforeCount = result.BeforeCount
+	run.AfterCount = result.AfterCount
+	if result.Merges != nil {
+		run.Merges = result.Merges
+	}
+	if !run.DryRun && s.compactionFS != nil {
+		if err := s.compactionFS.RebuildFiles(ctx, botID, result.Results, filters); err != nil {
+			s.logger.Warn("compact memory fs rebuild failed", slog.String("bot_id", botID), slog.Any("error", err))
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) recordCompactionRun(ctx context.Context, pgBotID pgtype.UUID, run CompactionRun) (CompactionRun, error) {
+	merges, err := json.Marshal(run.Merges)
+	if err != nil {
+		return CompactionRun{}, err
+	}
+	row, err := s.queries.CreateMemoryCompactionRun(ctx, sqlc.CreateMemoryCompactionRunParams{
+		BotID:       pgBotID,
+		Trigger:     run.Trigger,
+		DryRun:      run.DryRun,
+		Status:      run.Status,
+		Ratio:       run.Ratio,
+		DecayDays:   int32(run.DecayDays),
+		BeforeCount: int32(run.BeforeCount),
+		AfterCount:  int32(run.AfterCount),
+		Merges:      merges,
+		Error:       run.Error,
+		StartedAt:   pgtype.Timestamptz{Time: run.StartedAt, Valid: true},
+	})
+	if err != nil {
+		return CompactionRun{}, err
+	}
+	return toCompactionRun(row), nil
+}
+
+// ListCompactionRuns returns the most recent compaction runs of a bot.
+func (s *Service) ListCompactionRuns(ctx context.Context, botID string, limit int) ([]CompactionRun, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultCompactionRunLimit
+	}
+	rows, err := s.queries.ListMemoryCompactionRuns(ctx, sqlc.ListMemoryCompactionRunsParams{
+		BotID:    pgBotID,
+		MaxCount: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	items := make([]CompactionRun, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, toCompactionRun(row))
+	}
+	return items, nil
+}
+
+func toCompactionRun(row sqlc.MemoryCompactionRun) CompactionRun {
+	run := CompactionRun{
+		ID:          row.ID.String(),
+		BotID:       row.BotID.String(),
+		Trigger:     row.Trigger,
+		DryRun:      row.DryRun,
+		Status:      row.Status,
+		Ratio:       row.Ratio,
+		DecayDays:   int(row.DecayDays),
+		BeforeCount: int(row.BeforeCount),
+		AfterCount:  int(row.AfterCount),
+		Merges:      []memory.CompactMerge{},
+		Error:       row.Error,
+		StartedAt:   db.TimeFromPg(row.StartedAt),
+		FinishedAt:  db.TimeFromPg(row.FinishedAt),
+	}
+	if len(row.Merges) > 0 {
+		_ = json.Unmarshal(row.Merges, &run.Merges)
+	}
+	return run
+}
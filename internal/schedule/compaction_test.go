@@ -0,0 +1,80 @@
+package schedule
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/memohai/memoh/internal/memory"
+)
+
+type fakeCompactor struct {
+	count    int
+	compacts int
+	previews int
+}
+
+func (f *fakeCompactor) Compact(context.Context, map[string]any, float64, int) (memory.CompactResult, error) {
+	f.compacts++
+	return memory.CompactResult{BeforeCount: f.count, AfterCount: 1, Merges: []memory.CompactMerge{{Memory: "merged"}}}, nil
+}
+
+func (f *fakeCompactor) PreviewCompact(context.Context, map[string]any, float64, int) (memory.CompactResult, error) {
+	f.previews++
+	return memory.CompactResult{BeforeCount: f.count, AfterCount: 1, DryRun: true}, nil
+}
+
+func (f *fakeCompactor) Count(context.Context, map[string]any) (int, error) {
+	return f.count, nil
+}
+
+type fakeCompactionFS struct {
+	rebuilds int
+}
+
+func (f *fakeCompactionFS) RebuildFiles(context.Context, string, []memory.MemoryItem, map[string]any) error {
+	f.rebuilds++
+	return nil
+}
+
+func TestCompactMemories(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		count        int
+		maxMemories  int32
+		run          CompactionRun
+		wantStatus   string
+		wantCompacts int
+		wantPreviews int
+		wantRebuilds int
+	}{
+		{name: "scheduled below max is skipped", count: 10, maxMemories: 50, run: CompactionRun{Trigger: CompactionTriggerSchedule}, wantStatus: CompactionStatusSkipped},
+		{name: "scheduled above max compacts", count: 60, maxMemories: 50, run: CompactionRun{Trigger: CompactionTriggerSchedule}, wantStatus: CompactionStatusSuccess, wantCompacts: 1, wantRebuilds: 1},
+		{name: "manual ignores max", count: 10, maxMemories: 50, run: CompactionRun{Trigger: CompactionTriggerManual}, wantStatus: CompactionStatusSuccess, wantCompacts: 1, wantRebuilds: 1},
+		{name: "dry run previews only", count: 10, run: CompactionRun{Trigger: CompactionTriggerManual, DryRun: true}, wantStatus: CompactionStatusSuccess, wantPreviews: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			compactor := &fakeCompactor{count: tt.count}
+			fs := &fakeCompactionFS{}
+			svc := &Service{logger: slog.Default()}
+			svc.SetMemoryCompactor(compactor, fs)
+			run := tt.run
+			run.Merges = []memory.CompactMerge{}
+			if _, err := svc.compactMemories(context.Background(), "bot-a", map[string]any{}, tt.maxMemories, &run); err != nil {
+				t.Fatalf("compactMemories failed: %v", err)
+			}
+			if run.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", run.Status, tt.wantStatus)
+			}
+			if compactor.compacts != tt.wantCompacts || compactor.previews != tt.wantPreviews || fs.rebuilds != tt.wantRebuilds {
+				t.Errorf("compacts=%d previews=%d rebuilds=%d", compactor.compacts, compactor.previews, fs.rebuilds)
+			}
+			if run.BeforeCount != tt.count {
+				t.Errorf("before count = %d, want %d", run.BeforeCount, tt.count)
+			}
+		})
+	}
+}
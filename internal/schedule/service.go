@@ -28,6 +28,10 @@ type Service struct {
 	logger    *slog.Logger
 	mu        sync.Mutex
 	jobs      map[string]cron.EntryID
+
+	compactor    MemoryCompactor
+	compactionFS CompactionFS
+	snapshotter  ContainerSnapshotter
 }
 
 func NewService(log *slog.Logger, queries *sqlc.Queries, triggerer Triggerer, runtimeConfig *boot.RuntimeConfig) *Service {
@@ -59,6 +63,14 @@ func (s *Service) Bootstrap(ctx context.Context) error {
 			return err
 		}
 	}
+	if s.compactor != nil {
+		if err := s.bootstrapCompaction(ctx); err != nil {
+			return err
+		}
+	}
+	if s.snapshotter != nil {
+		return s.bootstrapSnapshots(ctx)
+	}
 	return nil
 }
 
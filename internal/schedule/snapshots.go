@@ -0,0 +1,79 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/memohai/memoh/internal/db"
+)
+
+const snapshotJobPrefix = "snapshot:"
+
+// ContainerSnapshotter takes a scheduled container snapshot and applies the
+// retention policy; implemented by mcp.Manager.
+type ContainerSnapshotter interface {
+	RunScheduledSnapshot(ctx context.Context, botID string) error
+}
+
+// SetContainerSnapshotter enables scheduled container snapshots.
+func (s *Service) SetContainerSnapshotter(snapshotter ContainerSnapshotter) {
+	s.snapshotter = snapshotter
+}
+
+// SyncSnapshots reschedules the automatic container snapshots of a bot from
+// its current settings, removing the job when the cron pattern is cleared.
+func (s *Service) SyncSnapshots(ctx context.Context, botID string) error {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return err
+	}
+	s.removeJob(snapshotJobPrefix + botID)
+	row, err := s.queries.GetBotSnapshotPolicy(ctx, pgBotID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	return s.scheduleSnapshot(botID, row.SnapshotCron)
+}
+
+func (s *Service) bootstrapSnapshots(ctx context.Context) error {
+	rows, err := s.queries.ListBotSnapshotSchedules(ctx)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		botID := row.ID.String()
+		if err := s.scheduleSnapshot(botID, row.SnapshotCron); err != nil {
+			// A bad pattern on one bot must not block startup.
+			s.logger.Warn("schedule container snapshot failed", slog.String("bot_id", botID), slog.Any("error", err))
+		}
+	}
+	return nil
+}
+
+func (s *Service) scheduleSnapshot(botID, pattern string) error {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || s.snapshotter == nil {
+		return nil
+	}
+	job := func() {
+		if err := s.snapshotter.RunScheduledSnapshot(context.Background(), botID); err != nil {
+			s.logger.Error("scheduled container snapshot failed", slog.String("bot_id", botID), slog.Any("error", err))
+		}
+	}
+	entryID, err := s.cron.AddFunc(pattern, job)
+	if err != nil {
+		return fmt.Errorf("invalid snapshot cron: %w", err)
+	}
+	s.mu.Lock()
+	s.jobs[snapshotJobPrefix+botID] = entryID
+	s.mu.Unlock()
+	return nil
+}
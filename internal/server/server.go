@@ -81,5 +81,8 @@ func shouldSkipJWT(path string) bool {
 	if strings.HasPrefix(path, "/channels/feishu/webhook/") {
 		return true
 	}
+	if strings.HasPrefix(path, "/channels/webhook/") {
+		return true
+	}
 	return false
 }
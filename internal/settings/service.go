@@ -5,24 +5,49 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/netip"
+	"regexp"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/robfig/cron/v3"
 
 	"github.com/memohai/memoh/internal/db"
 	"github.com/memohai/memoh/internal/db/sqlc"
 )
 
 type Service struct {
-	queries *sqlc.Queries
-	logger  *slog.Logger
+	queries    *sqlc.Queries
+	compaction CompactionScheduler
+	snapshots  SnapshotScheduler
+	logger     *slog.Logger
+}
+
+// CompactionScheduler reschedules a bot's automatic memory compaction after
+// its settings change.
+type CompactionScheduler interface {
+	SyncCompaction(ctx context.Context, botID string) error
+}
+
+// SnapshotScheduler reschedules a bot's automatic container snapshots after
+// its settings change.
+type SnapshotScheduler interface {
+	SyncSnapshots(ctx context.Context, botID string) error
 }
 
 var ErrPersonalBotGuestAccessUnsupported = errors.New("personal bots do not support guest access")
 var ErrModelIDAmbiguous = errors.New("model_id is ambiguous across providers")
 var ErrInvalidModelRef = errors.New("invalid model reference")
+var ErrInvalidCompaction = errors.New("invalid compaction settings")
+var ErrInvalidResourceLimits = errors.New("invalid container resource limits")
+
+var ErrInvalidNetworkPolicy = errors.New("invalid container network policy")
+var ErrInvalidSnapshotPolicy = errors.New("invalid container snapshot policy")
+
+// cronParser matches the parser used by the schedule service.
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
 
 func NewService(log *slog.Logger, queries *sqlc.Queries) *Service {
 	return &Service{
@@ -31,6 +56,16 @@ func NewService(log *slog.Logger, queries *sqlc.Queries) *Service {
 	}
 }
 
+// SetCompactionScheduler keeps scheduled memory compaction in sync with settings.
+func (s *Service) SetCompactionScheduler(scheduler CompactionScheduler) {
+	s.compaction = scheduler
+}
+
+// SetSnapshotScheduler keeps scheduled container snapshots in sync with settings.
+func (s *Service) SetSnapshotScheduler(scheduler SnapshotScheduler) {
+	s.snapshots = scheduler
+}
+
 func (s *Service) GetBot(ctx context.Context, botID string) (Settings, error) {
 	pgID, err := db.ParseUUID(botID)
 	if err != nil {
@@ -109,6 +144,42 @@ func (s *Service) UpsertBot(ctx context.Context, botID string, req UpsertRequest
 		}
 		embeddingModelUUID = modelID
 	}
+	rerankModelUUID := pgtype.UUID{}
+	if value := strings.TrimSpace(req.RerankModelID); value != "" {
+		modelID, err := s.resolveModelUUID(ctx, value)
+		if err != nil {
+			return Settings{}, err
+		}
+		rerankModelUUID = modelID
+	}
+	rerankEnabled := pgtype.Bool{}
+	if req.RerankEnabled != nil {
+		rerankEnabled = pgtype.Bool{Bool: *req.RerankEnabled, Valid: true}
+	}
+	rerankTopN := pgtype.Int4{}
+	if req.RerankTopN != nil && *req.RerankTopN > 0 {
+		rerankTopN = pgtype.Int4{Int32: int32(*req.RerankTopN), Valid: true}
+	}
+	memoryScope := pgtype.Text{}
+	if req.MemoryScope != nil && isValidMemoryScope(*req.MemoryScope) {
+		memoryScope = pgtype.Text{String: *req.MemoryScope, Valid: true}
+	}
+	compaction, err := buildCompactionParams(req)
+	if err != nil {
+		return Settings{}, err
+	}
+	resources, err := buildResourceParams(req)
+	if err != nil {
+		return Settings{}, err
+	}
+	network, err := buildNetworkParams(req)
+	if err != nil {
+		return Settings{}, err
+	}
+	snapshot, err := buildSnapshotParams(req)
+	if err != nil {
+		return Settings{}, err
+	}
 	searchProviderUUID := pgtype.UUID{}
 	if value := strings.TrimSpace(req.SearchProviderID); value != "" {
 		providerID, err := db.ParseUUID(value)
@@ -119,22 +190,43 @@ func (s *Service) UpsertBot(ctx context.Context, botID string, req UpsertRequest
 	}
 
 	updated, err := s.queries.UpsertBotSettings(ctx, sqlc.UpsertBotSettingsParams{
-		ID:                 pgID,
-		MaxContextLoadTime: int32(current.MaxContextLoadTime),
-		MaxContextTokens:   int32(current.MaxContextTokens),
-		MaxInboxItems:      int32(current.MaxInboxItems),
-		Language:           current.Language,
-		AllowGuest:         current.AllowGuest,
-		ReasoningEnabled:   current.ReasoningEnabled,
-		ReasoningEffort:    current.ReasoningEffort,
-		ChatModelID:        chatModelUUID,
-		MemoryModelID:      memoryModelUUID,
-		EmbeddingModelID:   embeddingModelUUID,
-		SearchProviderID:   searchProviderUUID,
+		ID:                    pgID,
+		MaxContextLoadTime:    int32(current.MaxContextLoadTime),
+		MaxContextTokens:      int32(current.MaxContextTokens),
+		MaxInboxItems:         int32(current.MaxInboxItems),
+		Language:              current.Language,
+		AllowGuest:            current.AllowGuest,
+		ReasoningEnabled:      current.ReasoningEnabled,
+		ReasoningEffort:       current.ReasoningEffort,
+		ChatModelID:           chatModelUUID,
+		MemoryModelID:         memoryModelUUID,
+		EmbeddingModelID:      embeddingModelUUID,
+		SearchProviderID:      searchProviderUUID,
+		RerankEnabled:         rerankEnabled,
+		RerankModelID:         rerankModelUUID,
+		RerankTopN:            rerankTopN,
+		MemoryScope:           memoryScope,
+		CompactionCron:        compaction.cron,
+		CompactionRatio:       compaction.ratio,
+		CompactionDecayDays:   compaction.decayDays,
+		CompactionMaxMemories: compaction.maxMemories,
+		ContainerCpuShares:    resources.cpuShares,
+		ContainerCpuLimit:     resources.cpuLimit,
+		ContainerMemoryMb:     resources.memoryMB,
+		ContainerPidsLimit:    resources.pidsLimit,
+		ContainerDiskQuotaMb:  resources.diskQuotaMB,
+		NetworkMode:           network.mode,
+		NetworkAllowlist:      network.allowlist,
+		SnapshotCron:          snapshot.cron,
+		SnapshotBeforeRisky:   snapshot.beforeRisky,
+		SnapshotKeepLast:      snapshot.keepLast,
+		SnapshotKeepDaily:     snapshot.keepDaily,
 	})
 	if err != nil {
 		return Settings{}, err
 	}
+	s.syncCompaction(ctx, botID)
+	s.syncSnapshots(ctx, botID)
 	return normalizeBotSettingsWriteRow(updated), nil
 }
 
@@ -146,7 +238,106 @@ func (s *Service) Delete(ctx context.Context, botID string) error {
 	if err != nil {
 		return err
 	}
-	return s.queries.DeleteSettingsByBotID(ctx, pgID)
+	if err := s.queries.DeleteSettingsByBotID(ctx, pgID); err != nil {
+		return err
+	}
+	s.syncCompaction(ctx, botID)
+	s.syncSnapshots(ctx, botID)
+	return nil
+}
+
+func (s *Service) syncCompaction(ctx context.Context, botID string) {
+	if s.compaction == nil {
+		return
+	}
+	if err := s.compaction.SyncCompaction(ctx, botID); err != nil {
+		s.logger.Warn("sync memory compaction schedule failed", slog.String("bot_id", botID), slog.Any("error", err))
+	}
+}
+
+func (s *Service) syncSnapshots(ctx context.Context, botID string) {
+	if s.snapshots == nil {
+		return
+	}
+	if err := s.snapshots.SyncSnapshots(ctx, botID); err != nil {
+		s.logger.Warn("sync container snapshot schedule failed", slog.String("bot_id", botID), slog.Any("error", err))
+	}
+}
+
+type compactionParams struct {
+	cron        pgtype.Text
+	ratio       pgtype.Float8
+	decayDays   pgtype.Int4
+	maxMemories pgtype.Int4
+}
+
+func buildCompactionParams(req UpsertRequest) (compactionParams, error) {
+	params := compactionParams{}
+	if req.CompactionCron != nil {
+		pattern := strings.TrimSpace(*req.CompactionCron)
+		if pattern != "" {
+			if _, err := cronParser.Parse(pattern); err != nil {
+				return compactionParams{}, fmt.Errorf("%w: cron: %v", ErrInvalidCompaction, err)
+			}
+		}
+		params.cron = pgtype.Text{String: pattern, Valid: true}
+	}
+	if req.CompactionRatio != nil {
+		if *req.CompactionRatio <= 0 || *req.CompactionRatio > 1 {
+			return compactionParams{}, fmt.Errorf("%w: ratio must be in range (0, 1]", ErrInvalidCompaction)
+		}
+		params.ratio = pgtype.Float8{Float64: *req.CompactionRatio, Valid: true}
+	}
+	if req.CompactionDecayDays != nil {
+		if *req.CompactionDecayDays < 0 {
+			return compactionParams{}, fmt.Errorf("%w: decay days must not be negative", ErrInvalidCompaction)
+		}
+		params.decayDays = pgtype.Int4{Int32: int32(*req.CompactionDecayDays), Valid: true}
+	}
+	if req.CompactionMaxMemories != nil {
+		if *req.CompactionMaxMemories < 0 {
+			return compactionParams{}, fmt.Errorf("%w: max memories must not be negative", ErrInvalidCompaction)
+		}
+		params.maxMemories = pgtype.Int4{Int32: int32(*req.CompactionMaxMemories), Valid: true}
+	}
+	return params, nil
+}
+
+type resourceParams struct {
+	cpuShares   pgtype.Int4
+	cpuLimit    pgtype.Float8
+	memoryMB    pgtype.Int4
+	pidsLimit   pgtype.Int4
+	diskQuotaMB pgtype.Int4
+}
+
+func buildResourceParams(req UpsertRequest) (resourceParams, error) {
+	params := resourceParams{}
+	for _, field := range []struct {
+		name  string
+		value *int
+		dst   *pgtype.Int4
+	}{
+		{"cpu shares", req.ContainerCPUShares, &params.cpuShares},
+		{"memory", req.ContainerMemoryMB, &params.memoryMB},
+		{"pids limit", req.ContainerPidsLimit, &params.pidsLimit},
+		{"disk quota", req.ContainerDiskQuotaMB, &params.diskQuotaMB},
+	} {
+		if field.value == nil {
+			continue
+		}
+		if *field.value < 0 {
+			return resourceParams{}, fmt.Errorf("%w: %s must not be negative", ErrInvalidResourceLimits, field.name)
+		}
+		*field.dst = pgtype.Int4{Int32: int32(*field.value), Valid: true}
+	}
+	if req.ContainerCPULimit != nil {
+		if *req.ContainerCPULimit < 0 {
+			return resourceParams{}, fmt.Errorf("%w: cpu limit must not be negative", ErrInvalidResourceLimits)
+		}
+		params.cpuLimit = pgtype.Float8{Float64: *req.ContainerCPULimit, Valid: true}
+	}
+	return params, nil
 }
 
 func normalizeBotSetting(maxContextLoadTime int32, maxContextTokens int32, maxInboxItems int32, language string, allowGuest bool, reasoningEnabled bool, reasoningEffort string) Settings {
@@ -186,8 +377,17 @@ func isValidReasoningEffort(effort string) bool {
 	}
 }
 
+func isValidMemoryScope(scope string) bool {
+	switch scope {
+	case "shared", "per_user", "per_conversation":
+		return true
+	default:
+		return false
+	}
+}
+
 func normalizeBotSettingsReadRow(row sqlc.GetSettingsByBotIDRow) Settings {
-	return normalizeBotSettingsFields(
+	settings := normalizeBotSettingsFields(
 		row.MaxContextLoadTime,
 		row.MaxContextTokens,
 		row.MaxInboxItems,
@@ -200,10 +400,16 @@ func normalizeBotSettingsReadRow(row sqlc.GetSettingsByBotIDRow) Settings {
 		row.EmbeddingModelID,
 		row.SearchProviderID,
 	)
+	settings = withRerankSettings(settings, row.RerankEnabled, row.RerankModelID, row.RerankTopN)
+	settings = withCompactionSettings(settings, row.CompactionCron, row.CompactionRatio, row.CompactionDecayDays, row.CompactionMaxMemories)
+	settings = withResourceSettings(settings, row.ContainerCpuShares, row.ContainerCpuLimit, row.ContainerMemoryMb, row.ContainerPidsLimit, row.ContainerDiskQuotaMb)
+	settings = withNetworkSettings(settings, row.NetworkMode, row.NetworkAllowlist)
+	settings = withSnapshotSettings(settings, row.SnapshotCron, row.SnapshotBeforeRisky, row.SnapshotKeepLast, row.SnapshotKeepDaily)
+	return withMemoryScope(settings, row.MemoryScope)
 }
 
 func normalizeBotSettingsWriteRow(row sqlc.UpsertBotSettingsRow) Settings {
-	return normalizeBotSettingsFields(
+	settings := normalizeBotSettingsFields(
 		row.MaxContextLoadTime,
 		row.MaxContextTokens,
 		row.MaxInboxItems,
@@ -216,6 +422,43 @@ func normalizeBotSettingsWriteRow(row sqlc.UpsertBotSettingsRow) Settings {
 		row.EmbeddingModelID,
 		row.SearchProviderID,
 	)
+	settings = withRerankSettings(settings, row.RerankEnabled, row.RerankModelID, row.RerankTopN)
+	settings = withCompactionSettings(settings, row.CompactionCron, row.CompactionRatio, row.CompactionDecayDays, row.CompactionMaxMemories)
+	settings = withResourceSettings(settings, row.ContainerCpuShares, row.ContainerCpuLimit, row.ContainerMemoryMb, row.ContainerPidsLimit, row.ContainerDiskQuotaMb)
+	settings = withNetworkSettings(settings, row.NetworkMode, row.NetworkAllowlist)
+	settings = withSnapshotSettings(settings, row.SnapshotCron, row.SnapshotBeforeRisky, row.SnapshotKeepLast, row.SnapshotKeepDaily)
+	return withMemoryScope(settings, row.MemoryScope)
+}
+
+func withRerankSettings(settings Settings, enabled bool, modelID pgtype.UUID, topN int32) Settings {
+	settings.RerankEnabled = enabled
+	if modelID.Valid {
+		settings.RerankModelID = uuid.UUID(modelID.Bytes).String()
+	}
+	settings.RerankTopN = int(topN)
+	if settings.RerankTopN <= 0 {
+		settings.RerankTopN = DefaultRerankTopN
+	}
+	return settings
+}
+
+func withCompactionSettings(settings Settings, cronPattern string, ratio float64, decayDays, maxMemories int32) Settings {
+	settings.CompactionCron = strings.TrimSpace(cronPattern)
+	settings.CompactionRatio = ratio
+	if settings.CompactionRatio <= 0 || settings.CompactionRatio > 1 {
+		settings.CompactionRatio = DefaultCompactionRatio
+	}
+	settings.CompactionDecayDays = int(decayDays)
+	settings.CompactionMaxMemories = int(maxMemories)
+	return settings
+}
+
+func withMemoryScope(settings Settings, scope string) Settings {
+	settings.MemoryScope = strings.TrimSpace(scope)
+	if !isValidMemoryScope(settings.MemoryScope) {
+		settings.MemoryScope = DefaultMemoryScope
+	}
+	return settings
 }
 
 func normalizeBotSettingsFields(
@@ -274,3 +517,124 @@ func (s *Service) resolveModelUUID(ctx context.Context, modelID string) (pgtype.
 	}
 	return rows[0].ID, nil
 }
+
+type networkParams struct {
+	mode      pgtype.Text
+	allowlist []string
+}
+
+func buildNetworkParams(req UpsertRequest) (networkParams, error) {
+	params := networkParams{}
+	if req.NetworkMode != nil {
+		mode := strings.TrimSpace(*req.NetworkMode)
+		if !isValidNetworkMode(mode) {
+			return networkParams{}, fmt.Errorf("%w: unknown network mode %q", ErrInvalidNetworkPolicy, mode)
+		}
+		params.mode = pgtype.Text{String: mode, Valid: true}
+	}
+	if req.NetworkAllowlist != nil {
+		params.allowlist = make([]string, 0, len(req.NetworkAllowlist))
+		seen := make(map[string]struct{}, len(req.NetworkAllowlist))
+		for _, entry := range req.NetworkAllowlist {
+			entry = strings.ToLower(strings.TrimSpace(entry))
+			if entry == "" {
+				continue
+			}
+			if !isValidAllowlistEntry(entry) {
+				return networkParams{}, fmt.Errorf("%w: %q is not a domain, IP or CIDR", ErrInvalidNetworkPolicy, entry)
+			}
+			if _, ok := seen[entry]; ok {
+				continue
+			}
+			seen[entry] = struct{}{}
+			params.allowlist = append(params.allowlist, entry)
+		}
+	}
+	return params, nil
+}
+
+func isValidNetworkMode(mode string) bool {
+	switch mode {
+	case NetworkModeFull, NetworkModeNone, NetworkModeAllowlist:
+		return true
+	default:
+		return false
+	}
+}
+
+var allowlistDomainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)*[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+func isValidAllowlistEntry(entry string) bool {
+	if _, err := netip.ParsePrefix(entry); err == nil {
+		return true
+	}
+	if _, err := netip.ParseAddr(entry); err == nil {
+		return true
+	}
+	return len(entry) <= 253 && allowlistDomainPattern.MatchString(entry)
+}
+
+func withResourceSettings(settings Settings, cpuShares int32, cpuLimit float64, memoryMB, pidsLimit, diskQuotaMB int32) Settings {
+	settings.ContainerCPUShares = int(max(cpuShares, 0))
+	settings.ContainerCPULimit = max(cpuLimit, 0)
+	settings.ContainerMemoryMB = int(max(memoryMB, 0))
+	settings.ContainerPidsLimit = int(max(pidsLimit, 0))
+	settings.ContainerDiskQuotaMB = int(max(diskQuotaMB, 0))
+	return settings
+}
+
+func withNetworkSettings(settings Settings, mode string, allowlist []string) Settings {
+	settings.NetworkMode = strings.TrimSpace(mode)
+	if !isValidNetworkMode(settings.NetworkMode) {
+		settings.NetworkMode = DefaultNetworkMode
+	}
+	settings.NetworkAllowlist = allowlist
+	if settings.NetworkAllowlist == nil {
+		settings.NetworkAllowlist = []string{}
+	}
+	return settings
+}
+
+type snapshotParams struct {
+	cron        pgtype.Text
+	beforeRisky pgtype.Bool
+	keepLast    pgtype.Int4
+	keepDaily   pgtype.Int4
+}
+
+func buildSnapshotParams(req UpsertRequest) (snapshotParams, error) {
+	params := snapshotParams{}
+	if req.SnapshotCron != nil {
+		pattern := strings.TrimSpace(*req.SnapshotCron)
+		if pattern != "" {
+			if _, err := cronParser.Parse(pattern); err != nil {
+				return snapshotParams{}, fmt.Errorf("%w: cron: %v", ErrInvalidSnapshotPolicy, err)
+			}
+		}
+		params.cron = pgtype.Text{String: pattern, Valid: true}
+	}
+	if req.SnapshotBeforeRisky != nil {
+		params.beforeRisky = pgtype.Bool{Bool: *req.SnapshotBeforeRisky, Valid: true}
+	}
+	if req.SnapshotKeepLast != nil {
+		if *req.SnapshotKeepLast < 0 {
+			return snapshotParams{}, fmt.Errorf("%w: keep last must not be negative", ErrInvalidSnapshotPolicy)
+		}
+		params.keepLast = pgtype.Int4{Int32: int32(*req.SnapshotKeepLast), Valid: true}
+	}
+	if req.SnapshotKeepDaily != nil {
+		if *req.SnapshotKeepDaily < 0 {
+			return snapshotParams{}, fmt.Errorf("%w: keep daily must not be negative", ErrInvalidSnapshotPolicy)
+		}
+		params.keepDaily = pgtype.Int4{Int32: int32(*req.SnapshotKeepDaily), Valid: true}
+	}
+	return params, nil
+}
+
+func withSnapshotSettings(settings Settings, cronPattern string, beforeRisky bool, keepLast, keepDaily int32) Settings {
+	settings.SnapshotCron = strings.TrimSpace(cronPattern)
+	settings.SnapshotBeforeRisky = beforeRisky
+	settings.SnapshotKeepLast = int(max(keepLast, 0))
+	settings.SnapshotKeepDaily = int(max(keepDaily, 0))
+	return settings
+}
@@ -5,6 +5,17 @@ const (
 	DefaultMaxInboxItems      = 50
 	DefaultLanguage           = "auto"
 	DefaultReasoningEffort    = "medium"
+	DefaultRerankTopN         = 20
+	DefaultMemoryScope        = "shared"
+	DefaultCompactionRatio    = 0.5
+	DefaultNetworkMode        = NetworkModeFull
+)
+
+// Container network modes.
+const (
+	NetworkModeFull      = "full"
+	NetworkModeNone      = "none"
+	NetworkModeAllowlist = "allowlist"
 )
 
 type Settings struct {
@@ -19,6 +30,37 @@ type Settings struct {
 	AllowGuest         bool   `json:"allow_guest"`
 	ReasoningEnabled   bool   `json:"reasoning_enabled"`
 	ReasoningEffort    string `json:"reasoning_effort"`
+	RerankEnabled      bool   `json:"rerank_enabled"`
+	RerankModelID      string `json:"rerank_model_id"`
+	RerankTopN         int    `json:"rerank_top_n"`
+	MemoryScope        string `json:"memory_scope"`
+	// CompactionCron schedules automatic memory compaction; empty disables it.
+	CompactionCron        string  `json:"compaction_cron"`
+	CompactionRatio       float64 `json:"compaction_ratio"`
+	CompactionDecayDays   int     `json:"compaction_decay_days"`
+	CompactionMaxMemories int     `json:"compaction_max_memories"`
+	// Container resource limits take effect when the bot container restarts;
+	// zero means unlimited. ContainerCPULimit is in cores. ContainerDiskQuotaMB
+	// applies immediately to file manager uploads and the container file tools.
+	ContainerCPUShares   int     `json:"container_cpu_shares"`
+	ContainerCPULimit    float64 `json:"container_cpu_limit"`
+	ContainerMemoryMB    int     `json:"container_memory_mb"`
+	ContainerPidsLimit   int     `json:"container_pids_limit"`
+	ContainerDiskQuotaMB int     `json:"container_disk_quota_mb"`
+	// NetworkMode is full, none or allowlist. In allowlist mode the container
+	// may only reach the domains, IPs and CIDRs in NetworkAllowlist. Domains
+	// are resolved when the container starts.
+	NetworkMode      string   `json:"network_mode"`
+	NetworkAllowlist []string `json:"network_allowlist"`
+	// SnapshotCron schedules automatic container snapshots; empty disables it.
+	// SnapshotBeforeRisky also snapshots before rollbacks, image changes and
+	// terminal sessions.
+	// Retention keeps the newest SnapshotKeepLast snapshots plus the newest one
+	// of each of the last SnapshotKeepDaily days; both zero keeps everything.
+	SnapshotCron        string `json:"snapshot_cron"`
+	SnapshotBeforeRisky bool   `json:"snapshot_before_risky"`
+	SnapshotKeepLast    int    `json:"snapshot_keep_last"`
+	SnapshotKeepDaily   int    `json:"snapshot_keep_daily"`
 }
 
 type UpsertRequest struct {
@@ -33,4 +75,27 @@ type UpsertRequest struct {
 	AllowGuest         *bool   `json:"allow_guest,omitempty"`
 	ReasoningEnabled   *bool   `json:"reasoning_enabled,omitempty"`
 	ReasoningEffort    *string `json:"reasoning_effort,omitempty"`
+	RerankEnabled      *bool   `json:"rerank_enabled,omitempty"`
+	RerankModelID      string  `json:"rerank_model_id,omitempty"`
+	RerankTopN         *int    `json:"rerank_top_n,omitempty"`
+	MemoryScope        *string `json:"memory_scope,omitempty"`
+	// CompactionCron accepts a cron pattern, or an empty string to disable scheduled compaction.
+	CompactionCron        *string  `json:"compaction_cron,omitempty"`
+	CompactionRatio       *float64 `json:"compaction_ratio,omitempty"`
+	CompactionDecayDays   *int     `json:"compaction_decay_days,omitempty"`
+	CompactionMaxMemories *int     `json:"compaction_max_memories,omitempty"`
+	// Container resource limits; 0 removes a limit.
+	ContainerCPUShares   *int     `json:"container_cpu_shares,omitempty"`
+	ContainerCPULimit    *float64 `json:"container_cpu_limit,omitempty"`
+	ContainerMemoryMB    *int     `json:"container_memory_mb,omitempty"`
+	ContainerPidsLimit   *int     `json:"container_pids_limit,omitempty"`
+	ContainerDiskQuotaMB *int     `json:"container_disk_quota_mb,omitempty"`
+	NetworkMode          *string  `json:"network_mode,omitempty"`
+	// NetworkAllowlist replaces the allowlist when present; an empty list clears it.
+	NetworkAllowlist []string `json:"network_allowlist,omitempty"`
+	// SnapshotCron accepts a cron pattern, or an empty string to disable scheduled snapshots.
+	SnapshotCron        *string `json:"snapshot_cron,omitempty"`
+	SnapshotBeforeRisky *bool   `json:"snapshot_before_risky,omitempty"`
+	SnapshotKeepLast    *int    `json:"snapshot_keep_last,omitempty"`
+	SnapshotKeepDaily   *int    `json:"snapshot_keep_daily,omitempty"`
 }
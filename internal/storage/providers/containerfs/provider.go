@@ -75,6 +75,11 @@ func (p *Provider) Delete(_ context.Context, key string) error {
 	return nil
 }
 
+// Location identifies the host data root the provider writes under.
+func (p *Provider) Location() string {
+	return "localfs:" + p.dataRoot
+}
+
 // AccessPath returns the container-internal path for a storage key.
 // Routing key format: "<bot_id>/<storage_key>" → "/data/media/<storage_key>".
 func (p *Provider) AccessPath(key string) string {
@@ -0,0 +1,377 @@
+// Package s3 implements storage.Provider for S3-compatible object stores
+// (AWS S3, MinIO, Cloudflare R2, ...). Routing keys "<bot_id>/<storage_key>"
+// map to objects "<prefix>/<bot_id>/<storage_key>" in the configured bucket,
+// and AccessPath returns presigned GET URLs.
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRegion        = "us-east-1"
+	defaultPresignExpiry = time.Hour
+	maxPresignExpiry     = 7 * 24 * time.Hour
+	defaultTimeout       = 60 * time.Second
+)
+
+// ErrObjectNotFound indicates the requested object does not exist in the bucket.
+var ErrObjectNotFound = errors.New("s3 object not found")
+
+// Config holds connection settings for an S3-compatible endpoint.
+// JSON tags match the storage_providers.config column layout.
+type Config struct {
+	Endpoint             string `json:"endpoint"`
+	Region               string `json:"region,omitempty"`
+	Bucket               string `json:"bucket"`
+	AccessKeyID          string `json:"access_key_id"`
+	SecretAccessKey      string `json:"secret_access_key"`
+	SessionToken         string `json:"session_token,omitempty"`
+	Prefix               string `json:"prefix,omitempty"`
+	ForcePathStyle       bool   `json:"force_path_style,omitempty"`
+	PresignExpirySeconds int    `json:"presign_expiry_seconds,omitempty"`
+	TimeoutSeconds       int    `json:"timeout_seconds,omitempty"`
+}
+
+// Provider stores media assets as objects in an S3-compatible bucket.
+type Provider struct {
+	endpoint      *url.URL
+	bucket        string
+	prefix        string
+	pathStyle     bool
+	presignExpiry time.Duration
+	signer        signer
+	client        *http.Client
+	now           func() time.Time
+}
+
+// New creates an S3-compatible storage provider from cfg.
+func New(cfg Config) (*Provider, error) {
+	endpoint := strings.TrimSpace(cfg.Endpoint)
+	if endpoint == "" {
+		return nil, fmt.Errorf("s3 endpoint is required")
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint: %s", cfg.Endpoint)
+	}
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawQuery = ""
+
+	bucket := strings.TrimSpace(cfg.Bucket)
+	if bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+	if strings.TrimSpace(cfg.AccessKeyID) == "" || strings.TrimSpace(cfg.SecretAccessKey) == "" {
+		return nil, fmt.Errorf("s3 access_key_id and secret_access_key are required")
+	}
+	region := strings.TrimSpace(cfg.Region)
+	if region == "" {
+		region = defaultRegion
+	}
+	expiry := time.Duration(cfg.PresignExpirySeconds) * time.Second
+	if expiry <= 0 {
+		expiry = defaultPresignExpiry
+	}
+	if expiry > maxPresignExpiry {
+		expiry = maxPresignExpiry
+	}
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &Provider{
+		endpoint:      u,
+		bucket:        bucket,
+		prefix:        strings.Trim(path.Clean("/"+strings.TrimSpace(cfg.Prefix)), "/"),
+		pathStyle:     cfg.ForcePathStyle,
+		presignExpiry: expiry,
+		signer: signer{
+			accessKeyID:     strings.TrimSpace(cfg.AccessKeyID),
+			secretAccessKey: strings.TrimSpace(cfg.SecretAccessKey),
+			sessionToken:    strings.TrimSpace(cfg.SessionToken),
+			region:          region,
+		},
+		client: &http.Client{Timeout: timeout},
+		now:    time.Now,
+	}, nil
+}
+
+// Location identifies the endpoint, bucket and prefix objects are stored under.
+func (p *Provider) Location() string {
+	return "s3:" + strings.ToLower(p.endpoint.Host) + p.endpoint.Path + "/" + p.bucket + "/" + p.prefix
+}
+
+// Put uploads the reader content to the object for key.
+// S3 requires a Content-Length, so non-seekable readers are spooled to a temp file first.
+func (p *Provider) Put(ctx context.Context, key string, reader io.Reader) error {
+	objectKey, err := p.objectKey(key)
+	if err != nil {
+		return err
+	}
+	body, size, cleanup, err := sizedBody(reader)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// NopCloser keeps the transport from closing the caller's reader.
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, p.objectURL(objectKey).String(), io.NopCloser(body))
+	if err != nil {
+		return fmt.Errorf("build put request: %w", err)
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	if contentType := mime.TypeByExtension(path.Ext(objectKey)); contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := p.do(req, unsignedPayload)
+	if err != nil {
+		return fmt.Errorf("put object: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("put object: %w", responseError(resp))
+	}
+	return nil
+}
+
+// Open returns a reader for the object at key. The caller must close it.
+func (p *Provider) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	objectKey, err := p.objectKey(key)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.objectURL(objectKey).String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("build get request: %w", err)
+	}
+	resp, err := p.do(req, emptyPayloadSHA)
+	if err != nil {
+		return nil, fmt.Errorf("get object: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("open %s: %w", key, ErrObjectNotFound)
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("get object: %w", responseError(resp))
+	}
+	return resp.Body, nil
+}
+
+// Delete removes the object at key. Missing objects are not an error.
+func (p *Provider) Delete(ctx context.Context, key string) error {
+	objectKey, err := p.objectKey(key)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, p.objectURL(objectKey).String(), nil)
+	if err != nil {
+		return fmt.Errorf("build delete request: %w", err)
+	}
+	resp, err := p.do(req, emptyPayloadSHA)
+	if err != nil {
+		return fmt.Errorf("delete object: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode/100 == 2 {
+		return nil
+	}
+	return fmt.Errorf("delete object: %w", responseError(resp))
+}
+
+// AccessPath returns a presigned GET URL for the object at key.
+// Invalid keys yield an empty string.
+func (p *Provider) AccessPath(key string) string {
+	objectKey, err := p.objectKey(key)
+	if err != nil {
+		return ""
+	}
+	return p.signer.presign(http.MethodGet, p.objectURL(objectKey), p.presignExpiry, p.now()).String()
+}
+
+// ListPrefix returns all routing keys that start with prefix.
+func (p *Provider) ListPrefix(ctx context.Context, prefix string) ([]string, error) {
+	objectPrefix, err := p.objectKey(prefix)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		keys  []string
+		token string
+	)
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", objectPrefix)
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		u := p.bucketURL()
+		u.RawQuery = canonicalQuery(query)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("build list request: %w", err)
+		}
+		resp, err := p.do(req, emptyPayloadSHA)
+		if err != nil {
+			return nil, fmt.Errorf("list objects: %w", err)
+		}
+		page, err := decodeListResult(resp)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			if routingKey := p.routingKey(obj.Key); routingKey != "" {
+				keys = append(keys, routingKey)
+			}
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+func (p *Provider) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	p.signer.sign(req, payloadHash, p.now())
+	return p.client.Do(req)
+}
+
+// objectKey validates a routing key and prepends the configured prefix.
+func (p *Provider) objectKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", fmt.Errorf("invalid storage key: %q", key)
+	}
+	if strings.HasPrefix(key, "/") {
+		return "", fmt.Errorf("absolute key is forbidden: %s", key)
+	}
+	clean := path.Clean(key)
+	if clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("path traversal is forbidden: %s", key)
+	}
+	if p.prefix == "" {
+		return clean, nil
+	}
+	return p.prefix + "/" + clean, nil
+}
+
+// routingKey strips the configured prefix from an object key.
+func (p *Provider) routingKey(objectKey string) string {
+	if p.prefix == "" {
+		return objectKey
+	}
+	rest, ok := strings.CutPrefix(objectKey, p.prefix+"/")
+	if !ok {
+		return ""
+	}
+	return rest
+}
+
+func (p *Provider) bucketURL() *url.URL {
+	u := *p.endpoint
+	if p.pathStyle {
+		u.Path = u.Path + "/" + p.bucket
+	} else {
+		u.Host = p.bucket + "." + u.Host
+	}
+	return &u
+}
+
+func (p *Provider) objectURL(objectKey string) *url.URL {
+	u := p.bucketURL()
+	u.Path = u.Path + "/" + objectKey
+	return u
+}
+
+// sizedBody returns a reader with a known length. Seekable readers are used
+// as-is from their current offset; others are spooled to a temp file.
+func sizedBody(reader io.Reader) (io.Reader, int64, func(), error) {
+	noop := func() {}
+	if reader == nil {
+		return nil, 0, noop, fmt.Errorf("reader is required")
+	}
+	if seeker, ok := reader.(io.Seeker); ok {
+		cur, err := seeker.Seek(0, io.SeekCurrent)
+		if err == nil {
+			end, endErr := seeker.Seek(0, io.SeekEnd)
+			if endErr == nil {
+				if _, err := seeker.Seek(cur, io.SeekStart); err == nil {
+					return reader, end - cur, noop, nil
+				}
+			}
+		}
+	}
+	tmp, err := os.CreateTemp("", "memoh-s3-*")
+	if err != nil {
+		return nil, 0, noop, fmt.Errorf("create temp file: %w", err)
+	}
+	cleanup := func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}
+	size, err := io.Copy(tmp, reader)
+	if err != nil {
+		cleanup()
+		return nil, 0, noop, fmt.Errorf("spool upload: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, 0, noop, fmt.Errorf("rewind upload: %w", err)
+	}
+	return tmp, size, cleanup, nil
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func decodeListResult(resp *http.Response) (listBucketResult, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return listBucketResult{}, fmt.Errorf("list objects: %w", responseError(resp))
+	}
+	var result listBucketResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return listBucketResult{}, fmt.Errorf("decode list result: %w", err)
+	}
+	return result, nil
+}
+
+type errorResponse struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var parsed errorResponse
+	if err := xml.Unmarshal(body, &parsed); err == nil && parsed.Code != "" {
+		return fmt.Errorf("%s: %s (status %d)", parsed.Code, parsed.Message, resp.StatusCode)
+	}
+	return fmt.Errorf("unexpected status %d", resp.StatusCode)
+}
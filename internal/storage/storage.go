@@ -30,3 +30,10 @@ type ContainerFileOpener interface {
 type PrefixLister interface {
 	ListPrefix(ctx context.Context, prefix string) ([]string, error)
 }
+
+// Locator is an optional interface for providers that can identify where
+// they keep objects. Two providers with the same location read and write the
+// same objects for the same keys.
+type Locator interface {
+	Location() string
+}
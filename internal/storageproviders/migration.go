@@ -0,0 +1,264 @@
+package storageproviders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/media"
+	"github.com/memohai/memoh/internal/storage"
+)
+
+// maxMigrationErrors caps the error messages kept on a migration status.
+const maxMigrationErrors = 20
+
+// AssetCopier copies a content-addressed asset between providers.
+// It is implemented by media.Service.
+type AssetCopier interface {
+	CopyAsset(ctx context.Context, botID, contentHash string, from, to storage.Provider) (media.Asset, bool, error)
+}
+
+// SetAssetCopier wires the media service used by storage migrations.
+func (s *Service) SetAssetCopier(copier AssetCopier) {
+	s.copier = copier
+}
+
+// GetMigration returns the latest migration status for a bot.
+func (s *Service) GetMigration(botID string) (MigrationStatus, bool) {
+	s.migrationsMu.Lock()
+	defer s.migrationsMu.Unlock()
+	status, ok := s.migrations[strings.TrimSpace(botID)]
+	if !ok {
+		return MigrationStatus{}, false
+	}
+	return cloneStatus(status), true
+}
+
+// StartMigration copies every asset referenced by the bot's message history
+// from its current backend to the target, then switches the binding. It runs
+// in the background; poll GetMigration for progress.
+func (s *Service) StartMigration(ctx context.Context, botID string, req MigrateRequest) (MigrationStatus, error) {
+	if s.copier == nil {
+		return MigrationStatus{}, fmt.Errorf("asset copier not configured")
+	}
+	botID = strings.TrimSpace(botID)
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+
+	current, err := s.GetBinding(ctx, botID)
+	if err != nil && !errors.Is(err, ErrBindingNotFound) {
+		return MigrationStatus{}, err
+	}
+	targetID := strings.TrimSpace(req.StorageProviderID)
+	basePath := normalizeBasePath(req.BasePath)
+	if current.StorageProviderID == targetID && current.BasePath == basePath {
+		return MigrationStatus{}, ErrSameTarget
+	}
+
+	source, err := s.ResolveProvider(ctx, botID)
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+	if source == nil {
+		source = s.defaultProvider
+	}
+	if targetID == "" && basePath != "" {
+		return MigrationStatus{}, ErrBasePathUnsupported
+	}
+	target := s.defaultProvider
+	if targetID != "" {
+		pgProviderID, err := db.ParseUUID(targetID)
+		if err != nil {
+			return MigrationStatus{}, err
+		}
+		row, err := s.queries.GetStorageProviderByID(ctx, pgProviderID)
+		if err != nil {
+			return MigrationStatus{}, fmt.Errorf("get storage provider: %w", err)
+		}
+		target, err = s.build(row.Provider, row.Config, basePath)
+		if err != nil {
+			return MigrationStatus{}, err
+		}
+	}
+	// A different provider row can still resolve to the same place, e.g. a
+	// localfs provider without data_root; deleting the source would then
+	// delete the only copy.
+	if sameLocation(source, target) {
+		return MigrationStatus{}, ErrSameTarget
+	}
+
+	s.migrationsMu.Lock()
+	if existing, ok := s.migrations[botID]; ok && existing.State == MigrationRunning {
+		s.migrationsMu.Unlock()
+		return MigrationStatus{}, ErrMigrationRunning
+	}
+	status := &MigrationStatus{
+		BotID:             botID,
+		State:             MigrationRunning,
+		StorageProviderID: targetID,
+		BasePath:          basePath,
+		DeleteSource:      req.DeleteSource,
+		StartedAt:         time.Now().UTC(),
+	}
+	s.migrations[botID] = status
+	snapshot := cloneStatus(status)
+	s.migrationsMu.Unlock()
+
+	hashes, err := s.queries.ListBotAssetContentHashes(ctx, pgBotID)
+	if err != nil {
+		s.finishMigration(botID, fmt.Errorf("list bot assets: %w", err))
+		return s.mustGetMigration(botID), nil
+	}
+	go s.runMigration(context.WithoutCancel(ctx), botID, req, source, target, hashes)
+	return snapshot, nil
+}
+
+func (s *Service) runMigration(ctx context.Context, botID string, req MigrateRequest, source, target storage.Provider, hashes []string) {
+	s.updateMigration(botID, func(st *MigrationStatus) { st.Total = len(hashes) })
+	processed := make(map[string]struct{}, len(hashes))
+	copiedKeys := s.copyAssets(ctx, botID, hashes, source, target, processed)
+
+	// Switching now would make the assets that failed to copy unreachable.
+	if failed := s.mustGetMigration(botID).Failed; failed > 0 {
+		s.finishMigration(botID, fmt.Errorf("%d assets failed to copy; binding left unchanged", failed))
+		return
+	}
+	if err := s.switchBinding(ctx, botID, req); err != nil {
+		s.finishMigration(botID, err)
+		return
+	}
+
+	// Assets ingested while the first pass ran were written to the source.
+	if pgBotID, err := db.ParseUUID(botID); err == nil {
+		if latest, err := s.queries.ListBotAssetContentHashes(ctx, pgBotID); err == nil {
+			var remaining []string
+			for _, hash := range latest {
+				if _, ok := processed[hash]; !ok {
+					remaining = append(remaining, hash)
+				}
+			}
+			s.updateMigration(botID, func(st *MigrationStatus) { st.Total += len(remaining) })
+			copiedKeys = append(copiedKeys, s.copyAssets(ctx, botID, remaining, source, target, processed)...)
+		}
+	}
+
+	// Assets that failed in the second pass still live only in the source.
+	if failed := s.mustGetMigration(botID).Failed; failed > 0 {
+		s.finishMigration(botID, fmt.Errorf("%d assets failed to copy after switching; source kept", failed))
+		return
+	}
+	// Only objects this run wrote to the target are removed; skipped assets
+	// already existed there and may be the very objects the source holds.
+	if req.DeleteSource {
+		for _, storageKey := range copiedKeys {
+			if err := source.Delete(ctx, path.Join(botID, storageKey)); err != nil {
+				s.logger.Warn("delete migrated source asset failed", slog.String("bot_id", botID), slog.String("storage_key", storageKey), slog.Any("error", err))
+			}
+		}
+	}
+	s.finishMigration(botID, nil)
+}
+
+// copyAssets copies hashes from source to target, marks them in processed and
+// returns the storage keys of the assets it actually copied.
+func (s *Service) copyAssets(ctx context.Context, botID string, hashes []string, source, target storage.Provider, processed map[string]struct{}) []string {
+	var copiedKeys []string
+	for _, hash := range hashes {
+		asset, copied, err := s.copier.CopyAsset(ctx, botID, hash, source, target)
+		switch {
+		case errors.Is(err, media.ErrAssetNotFound):
+			processed[hash] = struct{}{}
+			s.updateMigration(botID, func(st *MigrationStatus) { st.Missing++ })
+		case err != nil:
+			s.logger.Warn("migrate asset failed", slog.String("bot_id", botID), slog.String("content_hash", hash), slog.Any("error", err))
+			s.updateMigration(botID, func(st *MigrationStatus) {
+				st.Failed++
+				if len(st.Errors) < maxMigrationErrors {
+					st.Errors = append(st.Errors, hash+": "+err.Error())
+				}
+			})
+		default:
+			processed[hash] = struct{}{}
+			if copied && asset.StorageKey != "" {
+				copiedKeys = append(copiedKeys, asset.StorageKey)
+			}
+			s.updateMigration(botID, func(st *MigrationStatus) {
+				if copied {
+					st.Copied++
+				} else {
+					st.Skipped++
+				}
+			})
+		}
+	}
+	return copiedKeys
+}
+
+// sameLocation reports whether two providers store objects in the same place.
+func sameLocation(a, b storage.Provider) bool {
+	if a == b {
+		return true
+	}
+	la, ok := a.(storage.Locator)
+	if !ok {
+		return false
+	}
+	lb, ok := b.(storage.Locator)
+	return ok && la.Location() == lb.Location()
+}
+
+func (s *Service) switchBinding(ctx context.Context, botID string, req MigrateRequest) error {
+	if strings.TrimSpace(req.StorageProviderID) == "" {
+		return s.DeleteBinding(ctx, botID)
+	}
+	_, err := s.UpsertBinding(ctx, botID, BindingRequest{
+		StorageProviderID: req.StorageProviderID,
+		BasePath:          req.BasePath,
+	})
+	return err
+}
+
+func (s *Service) updateMigration(botID string, fn func(*MigrationStatus)) {
+	s.migrationsMu.Lock()
+	defer s.migrationsMu.Unlock()
+	if st, ok := s.migrations[botID]; ok {
+		fn(st)
+	}
+}
+
+func (s *Service) finishMigration(botID string, err error) {
+	s.updateMigration(botID, func(st *MigrationStatus) {
+		now := time.Now().UTC()
+		st.FinishedAt = &now
+		st.State = MigrationCompleted
+		if err != nil {
+			st.State = MigrationFailed
+			st.Errors = append(st.Errors, err.Error())
+		}
+	})
+	if err != nil {
+		s.logger.Error("storage migration failed", slog.String("bot_id", botID), slog.Any("error", err))
+	}
+}
+
+func (s *Service) mustGetMigration(botID string) MigrationStatus {
+	status, _ := s.GetMigration(botID)
+	return status
+}
+
+func cloneStatus(status *MigrationStatus) MigrationStatus {
+	out := *status
+	out.Errors = append([]string(nil), status.Errors...)
+	if status.FinishedAt != nil {
+		finished := *status.FinishedAt
+		out.FinishedAt = &finished
+	}
+	return out
+}
@@ -0,0 +1,502 @@
+// Package storageproviders manages object storage backends and per-bot
+// storage bindings, and resolves the storage.Provider each bot should use.
+package storageproviders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/storage"
+	"github.com/memohai/memoh/internal/storage/providers/containerfs"
+	"github.com/memohai/memoh/internal/storage/providers/s3"
+)
+
+type Service struct {
+	queries         *sqlc.Queries
+	logger          *slog.Logger
+	defaultProvider storage.Provider
+	dataRoot        string
+
+	mu    sync.RWMutex
+	cache map[string]storage.Provider // bot_id -> bound provider (nil = default)
+
+	copier       AssetCopier
+	migrationsMu sync.Mutex
+	migrations   map[string]*MigrationStatus
+}
+
+// NewService creates the storage provider service. dataRoot is the host data
+// directory used by the default container-backed provider.
+func NewService(log *slog.Logger, queries *sqlc.Queries, dataRoot string) (*Service, error) {
+	defaultProvider, err := containerfs.New(dataRoot)
+	if err != nil {
+		return nil, fmt.Errorf("init default storage provider: %w", err)
+	}
+	return &Service{
+		queries:         queries,
+		logger:          log.With(slog.String("service", "storage_providers")),
+		defaultProvider: defaultProvider,
+		dataRoot:        dataRoot,
+		cache:           make(map[string]storage.Provider),
+		migrations:      make(map[string]*MigrationStatus),
+	}, nil
+}
+
+// DefaultProvider returns the provider used by bots without a binding.
+func (s *Service) DefaultProvider() storage.Provider {
+	return s.defaultProvider
+}
+
+func (s *Service) ListMeta(_ context.Context) []ProviderMeta {
+	return []ProviderMeta{
+		{
+			Provider:    string(ProviderLocalFS),
+			DisplayName: "Local filesystem",
+			ConfigSchema: ProviderConfigSchema{
+				Fields: map[string]ProviderFieldSchema{
+					"data_root": {
+						Type:        "string",
+						Title:       "Data root",
+						Description: "Host data directory; defaults to mcp.data_root",
+						Required:    false,
+						Example:     "data",
+					},
+				},
+			},
+		},
+		{
+			Provider:    string(ProviderS3),
+			DisplayName: "S3 compatible",
+			ConfigSchema: ProviderConfigSchema{
+				Fields: map[string]ProviderFieldSchema{
+					"endpoint": {
+						Type:        "string",
+						Title:       "Endpoint",
+						Description: "S3 API endpoint URL",
+						Required:    true,
+						Example:     "https://s3.us-east-1.amazonaws.com",
+					},
+					"region": {
+						Type:        "string",
+						Title:       "Region",
+						Description: "Signing region",
+						Required:    false,
+						Example:     "us-east-1",
+					},
+					"bucket": {
+						Type:        "string",
+						Title:       "Bucket",
+						Description: "Bucket that stores media objects",
+						Required:    true,
+					},
+					"access_key_id": {
+						Type:     "secret",
+						Title:    "Access Key ID",
+						Required: true,
+					},
+					"secret_access_key": {
+						Type:     "secret",
+						Title:    "Secret Access Key",
+						Required: true,
+					},
+					"prefix": {
+						Type:        "string",
+						Title:       "Key prefix",
+						Description: "Prefix for all object keys",
+						Required:    false,
+						Example:     "memoh",
+					},
+					"force_path_style": {
+						Type:        "boolean",
+						Title:       "Path-style addressing",
+						Description: "Use endpoint/bucket/key URLs (required by most MinIO setups)",
+						Required:    false,
+					},
+					"presign_expiry_seconds": {
+						Type:        "number",
+						Title:       "Presigned URL expiry (seconds)",
+						Description: "Lifetime of generated access URLs",
+						Required:    false,
+						Example:     3600,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (s *Service) Create(ctx context.Context, req CreateRequest) (GetResponse, error) {
+	if !isValidProviderName(req.Provider) {
+		return GetResponse{}, fmt.Errorf("%w: %s", ErrUnsupportedProvider, req.Provider)
+	}
+	configJSON, err := json.Marshal(req.Config)
+	if err != nil {
+		return GetResponse{}, fmt.Errorf("marshal config: %w", err)
+	}
+	if _, err := s.build(string(req.Provider), configJSON, ""); err != nil {
+		return GetResponse{}, err
+	}
+	row, err := s.queries.CreateStorageProvider(ctx, sqlc.CreateStorageProviderParams{
+		Name:     strings.TrimSpace(req.Name),
+		Provider: string(req.Provider),
+		Config:   configJSON,
+	})
+	if err != nil {
+		return GetResponse{}, fmt.Errorf("create storage provider: %w", err)
+	}
+	return s.toGetResponse(row), nil
+}
+
+func (s *Service) Get(ctx context.Context, id string) (GetResponse, error) {
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return GetResponse{}, err
+	}
+	row, err := s.queries.GetStorageProviderByID(ctx, pgID)
+	if err != nil {
+		return GetResponse{}, fmt.Errorf("get storage provider: %w", err)
+	}
+	return s.toGetResponse(row), nil
+}
+
+func (s *Service) List(ctx context.Context) ([]GetResponse, error) {
+	rows, err := s.queries.ListStorageProviders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list storage providers: %w", err)
+	}
+	items := make([]GetResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, s.toGetResponse(row))
+	}
+	return items, nil
+}
+
+func (s *Service) Update(ctx context.Context, id string, req UpdateRequest) (GetResponse, error) {
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return GetResponse{}, err
+	}
+	current, err := s.queries.GetStorageProviderByID(ctx, pgID)
+	if err != nil {
+		return GetResponse{}, fmt.Errorf("get storage provider: %w", err)
+	}
+	name := current.Name
+	if req.Name != nil {
+		name = strings.TrimSpace(*req.Name)
+	}
+	provider := current.Provider
+	if req.Provider != nil {
+		if !isValidProviderName(*req.Provider) {
+			return GetResponse{}, fmt.Errorf("%w: %s", ErrUnsupportedProvider, *req.Provider)
+		}
+		provider = string(*req.Provider)
+	}
+	config := current.Config
+	if req.Config != nil {
+		keepMaskedSecrets(provider, current.Config, req.Config)
+		configJSON, marshalErr := json.Marshal(req.Config)
+		if marshalErr != nil {
+			return GetResponse{}, fmt.Errorf("marshal config: %w", marshalErr)
+		}
+		config = configJSON
+	}
+	if _, err := s.build(provider, config, ""); err != nil {
+		return GetResponse{}, err
+	}
+	updated, err := s.queries.UpdateStorageProvider(ctx, sqlc.UpdateStorageProviderParams{
+		ID:       pgID,
+		Name:     name,
+		Provider: provider,
+		Config:   config,
+	})
+	if err != nil {
+		return GetResponse{}, fmt.Errorf("update storage provider: %w", err)
+	}
+	s.invalidateAll()
+	return s.toGetResponse(updated), nil
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return err
+	}
+	// Deleting would cascade to the bindings, silently moving bots back to
+	// the default provider and orphaning their assets.
+	bound, err := s.queries.CountBotStorageBindingsByProvider(ctx, pgID)
+	if err != nil {
+		return fmt.Errorf("count storage bindings: %w", err)
+	}
+	if bound > 0 {
+		return fmt.Errorf("%w: %d bots; migrate them first", ErrProviderInUse, bound)
+	}
+	if err := s.queries.DeleteStorageProvider(ctx, pgID); err != nil {
+		return fmt.Errorf("delete storage provider: %w", err)
+	}
+	s.invalidateAll()
+	return nil
+}
+
+// GetBinding returns the bot's storage binding, or ErrBindingNotFound.
+func (s *Service) GetBinding(ctx context.Context, botID string) (BindingResponse, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return BindingResponse{}, err
+	}
+	binding, err := s.queries.GetBotStorageBinding(ctx, pgBotID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return BindingResponse{}, ErrBindingNotFound
+		}
+		return BindingResponse{}, fmt.Errorf("get storage binding: %w", err)
+	}
+	row, err := s.queries.GetStorageProviderByID(ctx, binding.StorageProviderID)
+	if err != nil {
+		return BindingResponse{}, fmt.Errorf("get storage provider: %w", err)
+	}
+	return toBindingResponse(binding, row), nil
+}
+
+// UpsertBinding points the bot at another storage provider. Existing assets
+// stay where they are; use StartMigration to move them.
+func (s *Service) UpsertBinding(ctx context.Context, botID string, req BindingRequest) (BindingResponse, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return BindingResponse{}, err
+	}
+	pgProviderID, err := db.ParseUUID(req.StorageProviderID)
+	if err != nil {
+		return BindingResponse{}, err
+	}
+	row, err := s.queries.GetStorageProviderByID(ctx, pgProviderID)
+	if err != nil {
+		return BindingResponse{}, fmt.Errorf("get storage provider: %w", err)
+	}
+	basePath := normalizeBasePath(req.BasePath)
+	if _, err := s.build(row.Provider, row.Config, basePath); err != nil {
+		return BindingResponse{}, err
+	}
+	binding, err := s.queries.UpsertBotStorageBinding(ctx, sqlc.UpsertBotStorageBindingParams{
+		BotID:             pgBotID,
+		StorageProviderID: pgProviderID,
+		BasePath:          basePath,
+	})
+	if err != nil {
+		return BindingResponse{}, fmt.Errorf("upsert storage binding: %w", err)
+	}
+	s.invalidate(botID)
+	return toBindingResponse(binding, row), nil
+}
+
+// DeleteBinding reverts the bot to the default provider.
+func (s *Service) DeleteBinding(ctx context.Context, botID string) error {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return err
+	}
+	if err := s.queries.DeleteBotStorageBinding(ctx, pgBotID); err != nil {
+		return fmt.Errorf("delete storage binding: %w", err)
+	}
+	s.invalidate(botID)
+	return nil
+}
+
+// ResolveProvider implements media.ProviderResolver. It returns nil when the
+// bot has no binding so the caller falls back to its default provider.
+func (s *Service) ResolveProvider(ctx context.Context, botID string) (storage.Provider, error) {
+	botID = strings.TrimSpace(botID)
+	s.mu.RLock()
+	provider, ok := s.cache[botID]
+	s.mu.RUnlock()
+	if ok {
+		return provider, nil
+	}
+	provider, err := s.lookup(ctx, botID)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.cache[botID] = provider
+	s.mu.Unlock()
+	return provider, nil
+}
+
+func (s *Service) lookup(ctx context.Context, botID string) (storage.Provider, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return nil, err
+	}
+	binding, err := s.queries.GetBotStorageBinding(ctx, pgBotID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get storage binding: %w", err)
+	}
+	row, err := s.queries.GetStorageProviderByID(ctx, binding.StorageProviderID)
+	if err != nil {
+		return nil, fmt.Errorf("get storage provider: %w", err)
+	}
+	return s.build(row.Provider, row.Config, binding.BasePath)
+}
+
+// build instantiates a backend from its stored configuration.
+func (s *Service) build(provider string, configJSON []byte, basePath string) (storage.Provider, error) {
+	switch ProviderName(provider) {
+	case ProviderLocalFS:
+		// The container mounts media at a fixed path, so there is no prefix
+		// a binding could move it under.
+		if basePath != "" {
+			return nil, ErrBasePathUnsupported
+		}
+		var cfg struct {
+			DataRoot string `json:"data_root"`
+		}
+		if err := unmarshalConfig(configJSON, &cfg); err != nil {
+			return nil, err
+		}
+		dataRoot := strings.TrimSpace(cfg.DataRoot)
+		if dataRoot == "" {
+			dataRoot = s.dataRoot
+		}
+		p, err := containerfs.New(dataRoot)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	case ProviderS3:
+		var cfg s3.Config
+		if err := unmarshalConfig(configJSON, &cfg); err != nil {
+			return nil, err
+		}
+		if basePath != "" {
+			cfg.Prefix = path.Join(cfg.Prefix, basePath)
+		}
+		p, err := s3.New(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedProvider, provider)
+	}
+}
+
+func (s *Service) invalidate(botID string) {
+	s.mu.Lock()
+	delete(s.cache, strings.TrimSpace(botID))
+	s.mu.Unlock()
+}
+
+func (s *Service) invalidateAll() {
+	s.mu.Lock()
+	s.cache = make(map[string]storage.Provider)
+	s.mu.Unlock()
+}
+
+func (s *Service) toGetResponse(row sqlc.StorageProvider) GetResponse {
+	var cfg map[string]any
+	if len(row.Config) > 0 {
+		if err := json.Unmarshal(row.Config, &cfg); err != nil {
+			s.logger.Warn("storage provider config unmarshal failed", slog.String("id", row.ID.String()), slog.Any("error", err))
+		}
+	}
+	for _, key := range secretConfigFields[ProviderName(row.Provider)] {
+		if value, ok := cfg[key].(string); ok {
+			cfg[key] = maskSecret(value)
+		}
+	}
+	return GetResponse{
+		ID:        row.ID.String(),
+		Name:      row.Name,
+		Provider:  row.Provider,
+		Config:    cfg,
+		CreatedAt: row.CreatedAt.Time,
+		UpdatedAt: row.UpdatedAt.Time,
+	}
+}
+
+// secretConfigFields lists the config keys of each provider that are only
+// returned masked; they match the "secret" fields in ListMeta.
+var secretConfigFields = map[ProviderName][]string{
+	ProviderS3: {"access_key_id", "secret_access_key"},
+}
+
+// maskSecret masks a credential for responses.
+func maskSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	if len(value) <= 8 {
+		return strings.Repeat("*", len(value))
+	}
+	return value[:8] + strings.Repeat("*", len(value)-8)
+}
+
+// keepMaskedSecrets restores stored credentials in updated when the request
+// sends back the masked value, so a round-tripped GET response does not
+// overwrite the real secret.
+func keepMaskedSecrets(provider string, currentJSON []byte, updated map[string]any) {
+	var current map[string]any
+	if err := unmarshalConfig(currentJSON, &current); err != nil || current == nil {
+		return
+	}
+	for _, key := range secretConfigFields[ProviderName(provider)] {
+		existing, ok := current[key].(string)
+		if !ok || existing == "" {
+			continue
+		}
+		if value, ok := updated[key].(string); ok && value == maskSecret(existing) {
+			updated[key] = existing
+		}
+	}
+}
+
+func toBindingResponse(binding sqlc.BotStorageBinding, row sqlc.StorageProvider) BindingResponse {
+	return BindingResponse{
+		BotID:             binding.BotID.String(),
+		StorageProviderID: binding.StorageProviderID.String(),
+		Provider:          row.Provider,
+		ProviderName:      row.Name,
+		BasePath:          binding.BasePath,
+		CreatedAt:         binding.CreatedAt.Time,
+		UpdatedAt:         binding.UpdatedAt.Time,
+	}
+}
+
+func unmarshalConfig(raw []byte, out any) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("invalid storage provider config: %w", err)
+	}
+	return nil
+}
+
+func normalizeBasePath(basePath string) string {
+	basePath = strings.TrimSpace(basePath)
+	if basePath == "" {
+		return ""
+	}
+	return strings.Trim(path.Clean("/"+basePath), "/")
+}
+
+func isValidProviderName(name ProviderName) bool {
+	switch name {
+	case ProviderLocalFS, ProviderS3:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,332 @@
+package storageproviders
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/media"
+	"github.com/memohai/memoh/internal/storage"
+	"github.com/memohai/memoh/internal/storage/providers/s3"
+)
+
+type fakeRow struct {
+	scanFunc func(dest ...any) error
+}
+
+func (r *fakeRow) Scan(dest ...any) error {
+	return r.scanFunc(dest...)
+}
+
+// fakeDBTX implements sqlc.DBTX and counts QueryRow calls.
+type fakeDBTX struct {
+	queryRowFunc func(ctx context.Context, sql string, args ...any) pgx.Row
+	queryRows    int
+	execs        int
+}
+
+func (d *fakeDBTX) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	d.execs++
+	return pgconn.CommandTag{}, nil
+}
+
+func (d *fakeDBTX) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (d *fakeDBTX) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	d.queryRows++
+	if d.queryRowFunc != nil {
+		return d.queryRowFunc(ctx, sql, args...)
+	}
+	return &fakeRow{scanFunc: func(dest ...any) error { return pgx.ErrNoRows }}
+}
+
+func mustUUID(t *testing.T, s string) pgtype.UUID {
+	t.Helper()
+	var id pgtype.UUID
+	if err := id.Scan(s); err != nil {
+		t.Fatalf("invalid uuid %q: %v", s, err)
+	}
+	return id
+}
+
+func newTestService(t *testing.T, dbtx sqlc.DBTX) *Service {
+	t.Helper()
+	svc, err := NewService(slog.Default(), sqlc.New(dbtx), t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+	return svc
+}
+
+func TestService_ResolveProvider_NoBinding(t *testing.T) {
+	t.Parallel()
+	dbtx := &fakeDBTX{}
+	svc := newTestService(t, dbtx)
+
+	botID := "11111111-1111-1111-1111-111111111111"
+	for range 2 {
+		provider, err := svc.ResolveProvider(context.Background(), botID)
+		if err != nil {
+			t.Fatalf("ResolveProvider failed: %v", err)
+		}
+		if provider != nil {
+			t.Fatalf("ResolveProvider = %T, want nil for unbound bot", provider)
+		}
+	}
+	if dbtx.queryRows != 1 {
+		t.Errorf("binding lookups = %d, want 1 (cached)", dbtx.queryRows)
+	}
+}
+
+func TestService_ResolveProvider_S3Binding(t *testing.T) {
+	t.Parallel()
+	botID := mustUUID(t, "11111111-1111-1111-1111-111111111111")
+	providerID := mustUUID(t, "22222222-2222-2222-2222-222222222222")
+	dbtx := &fakeDBTX{
+		queryRowFunc: func(_ context.Context, sql string, _ ...any) pgx.Row {
+			return &fakeRow{scanFunc: func(dest ...any) error {
+				switch {
+				case strings.Contains(sql, "FROM bot_storage_bindings"):
+					*dest[1].(*pgtype.UUID) = botID
+					*dest[2].(*pgtype.UUID) = providerID
+					*dest[3].(*string) = "tenant-a"
+				case strings.Contains(sql, "FROM storage_providers"):
+					*dest[0].(*pgtype.UUID) = providerID
+					*dest[1].(*string) = "minio"
+					*dest[2].(*string) = string(ProviderS3)
+					*dest[3].(*[]byte) = []byte(`{"endpoint":"http://minio:9000","bucket":"media","access_key_id":"k","secret_access_key":"s","prefix":"memoh","force_path_style":true}`)
+				default:
+					return pgx.ErrNoRows
+				}
+				return nil
+			}}
+		},
+	}
+	svc := newTestService(t, dbtx)
+
+	provider, err := svc.ResolveProvider(context.Background(), botID.String())
+	if err != nil {
+		t.Fatalf("ResolveProvider failed: %v", err)
+	}
+	p, ok := provider.(*s3.Provider)
+	if !ok {
+		t.Fatalf("ResolveProvider = %T, want *s3.Provider", provider)
+	}
+	got := p.AccessPath("bot/ab/abcd.png")
+	if !strings.HasPrefix(got, "http://minio:9000/media/memoh/tenant-a/bot/ab/abcd.png?") {
+		t.Errorf("AccessPath = %q, want object under memoh/tenant-a", got)
+	}
+}
+
+func TestService_Build(t *testing.T) {
+	t.Parallel()
+	svc := newTestService(t, &fakeDBTX{})
+
+	tests := []struct {
+		name     string
+		provider string
+		config   string
+		basePath string
+		wantErr  bool
+	}{
+		{name: "localfs default root", provider: "localfs", config: `{}`},
+		{name: "localfs null config", provider: "localfs", config: `null`},
+		{name: "localfs base path", provider: "localfs", config: `{}`, basePath: "tenant-a", wantErr: true},
+		{name: "s3 missing bucket", provider: "s3", config: `{"endpoint":"http://minio:9000"}`, wantErr: true},
+		{name: "gcs unsupported", provider: "gcs", config: `{}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		provider, err := svc.build(tt.provider, []byte(tt.config), tt.basePath)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", tt.name)
+			}
+			if provider != nil {
+				t.Errorf("%s: provider should be nil on error, got %T", tt.name, provider)
+			}
+			continue
+		}
+		if err != nil || provider == nil {
+			t.Errorf("%s: build() = %v, %v", tt.name, provider, err)
+		}
+	}
+}
+
+func TestNormalizeBasePath(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"":               "",
+		"  ":             "",
+		"/tenant-a/":     "tenant-a",
+		"tenant-a//bots": "tenant-a/bots",
+		"../escape":      "escape",
+	}
+	for in, want := range tests {
+		if got := normalizeBasePath(in); got != want {
+			t.Errorf("normalizeBasePath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+type failingCopier struct{}
+
+func (failingCopier) CopyAsset(context.Context, string, string, storage.Provider, storage.Provider) (media.Asset, bool, error) {
+	return media.Asset{}, false, errors.New("copy failed")
+}
+
+func TestService_RunMigration_FailedCopyKeepsBinding(t *testing.T) {
+	dbtx := &fakeDBTX{}
+	svc := newTestService(t, dbtx)
+	svc.SetAssetCopier(failingCopier{})
+	botID := "00000000-0000-0000-0000-000000000001"
+	svc.migrations[botID] = &MigrationStatus{BotID: botID, State: MigrationRunning}
+
+	svc.runMigration(context.Background(), botID, MigrateRequest{DeleteSource: true}, nil, nil, []string{"hash-a"})
+
+	status, ok := svc.GetMigration(botID)
+	if !ok {
+		t.Fatal("migration status missing")
+	}
+	if status.State != MigrationFailed || status.Failed != 1 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	if dbtx.execs != 0 || dbtx.queryRows != 0 {
+		t.Fatalf("binding must not change, got %d execs and %d queries", dbtx.execs, dbtx.queryRows)
+	}
+}
+
+func TestService_Delete_ProviderInUse(t *testing.T) {
+	t.Parallel()
+	dbtx := &fakeDBTX{
+		queryRowFunc: func(_ context.Context, sql string, _ ...any) pgx.Row {
+			return &fakeRow{scanFunc: func(dest ...any) error {
+				if strings.Contains(sql, "COUNT(*) FROM bot_storage_bindings") {
+					*dest[0].(*int64) = 2
+					return nil
+				}
+				return pgx.ErrNoRows
+			}}
+		},
+	}
+	svc := newTestService(t, dbtx)
+
+	err := svc.Delete(context.Background(), "22222222-2222-2222-2222-222222222222")
+	if !errors.Is(err, ErrProviderInUse) {
+		t.Fatalf("Delete error = %v, want ErrProviderInUse", err)
+	}
+	if dbtx.execs != 0 {
+		t.Fatalf("provider must not be deleted, got %d execs", dbtx.execs)
+	}
+}
+
+func TestService_ToGetResponse_MasksSecrets(t *testing.T) {
+	t.Parallel()
+	svc := newTestService(t, &fakeDBTX{})
+	resp := svc.toGetResponse(sqlc.StorageProvider{
+		Provider: string(ProviderS3),
+		Config:   []byte(`{"bucket":"media","access_key_id":"AKIAEXAMPLE123","secret_access_key":"supersecretvalue"}`),
+	})
+	if resp.Config["bucket"] != "media" {
+		t.Errorf("bucket = %v, want media", resp.Config["bucket"])
+	}
+	if resp.Config["secret_access_key"] != "supersec********" {
+		t.Errorf("secret_access_key = %v, want masked", resp.Config["secret_access_key"])
+	}
+	if resp.Config["access_key_id"] != "AKIAEXAM******" {
+		t.Errorf("access_key_id = %v, want masked", resp.Config["access_key_id"])
+	}
+}
+
+func TestKeepMaskedSecrets(t *testing.T) {
+	t.Parallel()
+	current := []byte(`{"access_key_id":"AKIAEXAMPLE123","secret_access_key":"supersecretvalue"}`)
+	updated := map[string]any{
+		"access_key_id":     "AKIANEWKEY",
+		"secret_access_key": maskSecret("supersecretvalue"),
+	}
+	keepMaskedSecrets(string(ProviderS3), current, updated)
+	if updated["secret_access_key"] != "supersecretvalue" {
+		t.Errorf("secret_access_key = %v, want stored secret", updated["secret_access_key"])
+	}
+	if updated["access_key_id"] != "AKIANEWKEY" {
+		t.Errorf("access_key_id = %v, want new value", updated["access_key_id"])
+	}
+}
+
+func TestService_StartMigration_SameDirectory(t *testing.T) {
+	t.Parallel()
+	providerID := mustUUID(t, "22222222-2222-2222-2222-222222222222")
+	dbtx := &fakeDBTX{
+		queryRowFunc: func(_ context.Context, sql string, _ ...any) pgx.Row {
+			return &fakeRow{scanFunc: func(dest ...any) error {
+				if !strings.Contains(sql, "FROM storage_providers") {
+					return pgx.ErrNoRows
+				}
+				*dest[0].(*pgtype.UUID) = providerID
+				*dest[1].(*string) = "local"
+				*dest[2].(*string) = string(ProviderLocalFS)
+				*dest[3].(*[]byte) = []byte(`{}`)
+				return nil
+			}}
+		},
+	}
+	svc := newTestService(t, dbtx)
+	svc.SetAssetCopier(failingCopier{})
+
+	// A localfs provider without data_root resolves to the default directory.
+	_, err := svc.StartMigration(context.Background(), "00000000-0000-0000-0000-000000000001", MigrateRequest{
+		StorageProviderID: providerID.String(),
+		DeleteSource:      true,
+	})
+	if !errors.Is(err, ErrSameTarget) {
+		t.Fatalf("StartMigration error = %v, want ErrSameTarget", err)
+	}
+}
+
+// skippingCopier reports the assets in existing as already present at the target.
+type skippingCopier struct {
+	existing map[string]bool
+}
+
+func (c skippingCopier) CopyAsset(_ context.Context, _ string, hash string, _, _ storage.Provider) (media.Asset, bool, error) {
+	return media.Asset{StorageKey: "media/" + hash}, !c.existing[hash], nil
+}
+
+// recordingProvider records deleted keys.
+type recordingProvider struct {
+	storage.Provider
+	deleted []string
+}
+
+func (p *recordingProvider) Delete(_ context.Context, key string) error {
+	p.deleted = append(p.deleted, key)
+	return nil
+}
+
+func TestService_RunMigration_DeletesOnlyCopiedAssets(t *testing.T) {
+	dbtx := &fakeDBTX{}
+	svc := newTestService(t, dbtx)
+	svc.SetAssetCopier(skippingCopier{existing: map[string]bool{"hash-b": true}})
+	botID := "00000000-0000-0000-0000-000000000001"
+	svc.migrations[botID] = &MigrationStatus{BotID: botID, State: MigrationRunning}
+	source := &recordingProvider{}
+
+	svc.runMigration(context.Background(), botID, MigrateRequest{DeleteSource: true}, source, &recordingProvider{}, []string{"hash-a", "hash-b"})
+
+	status, _ := svc.GetMigration(botID)
+	if status.State != MigrationCompleted || status.Copied != 1 || status.Skipped != 1 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	if len(source.deleted) != 1 || source.deleted[0] != botID+"/media/hash-a" {
+		t.Fatalf("deleted source keys = %v, want only the copied asset", source.deleted)
+	}
+}
@@ -0,0 +1,118 @@
+package storageproviders
+
+import (
+	"errors"
+	"time"
+)
+
+type ProviderName string
+
+const (
+	ProviderLocalFS ProviderName = "localfs"
+	ProviderS3      ProviderName = "s3"
+)
+
+var (
+	// ErrUnsupportedProvider indicates the provider type has no backend implementation.
+	ErrUnsupportedProvider = errors.New("unsupported storage provider")
+	// ErrBindingNotFound indicates the bot has no storage binding and uses the default provider.
+	ErrBindingNotFound = errors.New("storage binding not found")
+	// ErrMigrationRunning indicates a migration is already in progress for the bot.
+	ErrMigrationRunning = errors.New("storage migration already running")
+	// ErrSameTarget indicates the migration target equals the current binding.
+	ErrSameTarget = errors.New("bot already uses the target storage")
+	// ErrBasePathUnsupported indicates a base path was given for a backend without key prefixes.
+	ErrBasePathUnsupported = errors.New("base_path is not supported by the localfs storage provider")
+	// ErrProviderInUse indicates bots are still bound to the storage provider.
+	ErrProviderInUse = errors.New("storage provider is bound to bots")
+)
+
+type ProviderConfigSchema struct {
+	Fields map[string]ProviderFieldSchema `json:"fields"`
+}
+
+type ProviderFieldSchema struct {
+	Type        string   `json:"type"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Example     any      `json:"example,omitempty"`
+}
+
+type ProviderMeta struct {
+	Provider     string               `json:"provider"`
+	DisplayName  string               `json:"display_name"`
+	ConfigSchema ProviderConfigSchema `json:"config_schema"`
+}
+
+type CreateRequest struct {
+	Name     string         `json:"name"`
+	Provider ProviderName   `json:"provider"`
+	Config   map[string]any `json:"config,omitempty"`
+}
+
+type UpdateRequest struct {
+	Name     *string        `json:"name,omitempty"`
+	Provider *ProviderName  `json:"provider,omitempty"`
+	Config   map[string]any `json:"config,omitempty"`
+}
+
+type GetResponse struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Provider  string         `json:"provider"`
+	Config    map[string]any `json:"config,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// BindingRequest selects the storage provider for a bot.
+// BasePath is an object key prefix for object-store backends; localfs rejects it.
+type BindingRequest struct {
+	StorageProviderID string `json:"storage_provider_id"`
+	BasePath          string `json:"base_path,omitempty"`
+}
+
+type BindingResponse struct {
+	BotID             string    `json:"bot_id"`
+	StorageProviderID string    `json:"storage_provider_id"`
+	Provider          string    `json:"provider"`
+	ProviderName      string    `json:"provider_name"`
+	BasePath          string    `json:"base_path"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// MigrateRequest moves a bot's assets to another backend. An empty
+// StorageProviderID targets the default provider and removes the binding; it
+// takes no BasePath.
+type MigrateRequest struct {
+	StorageProviderID string `json:"storage_provider_id,omitempty"`
+	BasePath          string `json:"base_path,omitempty"`
+	DeleteSource      bool   `json:"delete_source,omitempty"`
+}
+
+type MigrationState string
+
+const (
+	MigrationRunning   MigrationState = "running"
+	MigrationCompleted MigrationState = "completed"
+	MigrationFailed    MigrationState = "failed"
+)
+
+type MigrationStatus struct {
+	BotID             string         `json:"bot_id"`
+	State             MigrationState `json:"state"`
+	StorageProviderID string         `json:"storage_provider_id,omitempty"`
+	BasePath          string         `json:"base_path,omitempty"`
+	DeleteSource      bool           `json:"delete_source"`
+	Total             int            `json:"total"`
+	Copied            int            `json:"copied"`
+	Skipped           int            `json:"skipped"`
+	Missing           int            `json:"missing"`
+	Failed            int            `json:"failed"`
+	Errors            []string       `json:"errors,omitempty"`
+	StartedAt         time.Time      `json:"started_at"`
+	FinishedAt        *time.Time     `json:"finished_at,omitempty"`
+}
@@ -10,6 +10,7 @@ export interface IdentityContext {
   displayName: string
   currentPlatform?: string
   conversationType?: string
+  conversationId?: string
   sessionToken?: string
 }
 
@@ -13,5 +13,8 @@ export const buildIdentityHeaders = (identity: IdentityContext, auth: AgentAuthC
   if (identity.currentPlatform) {
     headers['X-Memoh-Current-Platform'] = identity.currentPlatform
   }
+  if (identity.conversationId) {
+    headers['X-Memoh-Conversation-Id'] = identity.conversationId
+  }
   return headers
 }
\ No newline at end of file
@@ -61,6 +61,218 @@ const docTemplate = `{
                 }
             }
         },
+        "/bot-templates": {
+            "get": {
+                "description": "List the templates bots can be created from",
+                "tags": [
+                    "bot-templates"
+                ],
+                "summary": "List bot templates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ListBotTemplatesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a bot template with identity files, skills, settings, MCP connections, schedules and subagents (admin only)",
+                "tags": [
+                    "bot-templates"
+                ],
+                "summary": "Create bot template",
+                "parameters": [
+                    {
+                        "description": "Template payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bundle.TemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/bundle.Template"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bot-templates/{id}": {
+            "get": {
+                "tags": [
+                    "bot-templates"
+                ],
+                "summary": "Get bot template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/bundle.Template"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace a bot template (admin only). Bots created from it are not changed.",
+                "tags": [
+                    "bot-templates"
+                ],
+                "summary": "Update bot template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bundle.TemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/bundle.Template"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a bot template (admin only). Bots created from it are kept.",
+                "tags": [
+                    "bot-templates"
+                ],
+                "summary": "Delete bot template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/bots": {
             "get": {
                 "description": "List bots accessible to current user (admin can specify owner_id)",
@@ -104,7 +316,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a bot user owned by current user (or admin-specified owner)",
+                "description": "Create a bot user owned by current user (or admin-specified owner). With template_id, the bot template's files, skills, settings, MCP connections, schedules and subagents are applied; the bot is removed again if that fails.",
                 "tags": [
                     "bots"
                 ],
@@ -148,6 +360,62 @@ const docTemplate = `{
                 }
             }
         },
+        "/bots/import": {
+            "post": {
+                "description": "Create a new bot owned by the current user from a bundle produced by the export endpoint. Models and the search provider are re-bound by name; anything that cannot be applied is listed in warnings.",
+                "consumes": [
+                    "application/gzip",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bots"
+                ],
+                "summary": "Import bot bundle",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Display name of the new bot (defaults to the exported one)",
+                        "name": "display_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Re-embed memories whose vectors are missing or from another model",
+                        "name": "embedding_enabled",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Bot bundle (multipart upload); otherwise send the bundle as the request body",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/bundle.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/bots/{bot_id}/cli/messages": {
             "post": {
                 "description": "Post a user message (with optional attachments) through the local channel pipeline.",
@@ -811,6 +1079,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "507": {
+                        "description": "Data directory quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
             }
@@ -864,16 +1138,23 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "507": {
+                        "description": "Data directory quota exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/bots/{bot_id}/container/skills": {
-            "get": {
+        "/bots/{bot_id}/container/image": {
+            "put": {
+                "description": "Recreate the bot container from another image. The data directory is kept and mounted into the new container. Images that are not present locally can only be pulled by admins.",
                 "tags": [
                     "containerd"
                 ],
-                "summary": "List skills from data directory",
+                "summary": "Switch the bot container image",
                 "parameters": [
                     {
                         "type": "string",
@@ -881,13 +1162,22 @@ const docTemplate = `{
                         "name": "bot_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Container image payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetContainerImageRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SkillsResponse"
+                            "$ref": "#/definitions/handlers.SetContainerImageResponse"
                         }
                     },
                     "400": {
@@ -896,8 +1186,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -907,10 +1197,59 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/bots/{bot_id}/container/skills": {
+            "get": {
+                "tags": [
+                    "containerd"
+                ],
+                "summary": "List skills from data directory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SkillsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
                 "tags": [
                     "containerd"
                 ],
@@ -1098,6 +1437,50 @@ const docTemplate = `{
                 }
             }
         },
+        "/bots/{bot_id}/container/snapshots/prune": {
+            "post": {
+                "description": "Deletes versions outside the keep-last/keep-daily policy and removes unreferenced container snapshots.",
+                "tags": [
+                    "containerd"
+                ],
+                "summary": "Apply the snapshot retention policy of a bot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PruneSnapshotsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Snapshots currently not supported on this backend",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/bots/{bot_id}/container/start": {
             "post": {
                 "tags": [
@@ -1172,13 +1555,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{bot_id}/inbox": {
+        "/bots/{bot_id}/container/terminal": {
             "get": {
-                "description": "List inbox items for a bot with optional filters",
+                "description": "Upgrades to a WebSocket attached to a login shell (PTY) in the bot container. Browsers pass the access token as the token query parameter. Output is sent as binary frames; input is accepted as binary frames or {\"type\":\"input\",\"data\":...} text frames, and {\"type\":\"resize\",\"cols\":...,\"rows\":...} resizes the terminal.",
                 "tags": [
-                    "inbox"
+                    "containerd"
                 ],
-                "summary": "List inbox items",
+                "summary": "Open an interactive terminal",
                 "parameters": [
                     {
                         "type": "string",
@@ -1187,42 +1570,22 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
-                    {
-                        "type": "string",
-                        "description": "Filter by read status (true/false)",
-                        "name": "is_read",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by source",
-                        "name": "source",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
-                        "default": 50,
-                        "description": "Max items to return",
-                        "name": "limit",
+                        "description": "Initial terminal width",
+                        "name": "cols",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "default": 0,
-                        "description": "Offset for pagination",
-                        "name": "offset",
+                        "description": "Initial terminal height",
+                        "name": "rows",
                         "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/inbox.Item"
-                            }
-                        }
+                    "101": {
+                        "description": "Switching Protocols"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1230,20 +1593,40 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new inbox item (for external integrations)",
+            }
+        },
+        "/bots/{bot_id}/container/versions/diff": {
+            "get": {
+                "description": "Compares the root filesystems of two versions. The bot data directory is not versioned and never shows up.",
                 "tags": [
-                    "inbox"
+                    "containerd"
                 ],
-                "summary": "Create inbox item",
+                "summary": "List files changed between two container versions",
                 "parameters": [
                     {
                         "type": "string",
@@ -1253,20 +1636,25 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Inbox item payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/inbox.CreateRequest"
-                        }
+                        "type": "integer",
+                        "description": "Base version",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Target version",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/inbox.Item"
+                            "$ref": "#/definitions/handlers.VersionDiffResponse"
                         }
                     },
                     "400": {
@@ -1275,22 +1663,33 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "501": {
+                        "description": "Snapshots currently not supported on this backend",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/bots/{bot_id}/inbox/count": {
+        "/bots/{bot_id}/file-watches": {
             "get": {
-                "description": "Count unread and total inbox items",
                 "tags": [
-                    "inbox"
+                    "file-watches"
                 ],
-                "summary": "Count inbox items",
+                "summary": "List file watches",
                 "parameters": [
                     {
                         "type": "string",
@@ -1304,7 +1703,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/inbox.CountResult"
+                            "$ref": "#/definitions/filewatch.ListResponse"
                         }
                     },
                     "400": {
@@ -1313,6 +1712,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1320,15 +1725,13 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/bots/{bot_id}/inbox/mark-read": {
+            },
             "post": {
-                "description": "Batch mark inbox items as read",
+                "description": "Watch files in the bot data directory matching a glob; changes are delivered to the bot inbox or trigger a chat turn",
                 "tags": [
-                    "inbox"
+                    "file-watches"
                 ],
-                "summary": "Mark inbox items as read",
+                "summary": "Create file watch",
                 "parameters": [
                     {
                         "type": "string",
@@ -1338,18 +1741,21 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Item IDs to mark as read",
+                        "description": "File watch payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.markReadRequest"
+                            "$ref": "#/definitions/filewatch.CreateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/filewatch.Watch"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1357,6 +1763,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1366,13 +1778,12 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{bot_id}/inbox/{id}": {
+        "/bots/{bot_id}/file-watches/{id}": {
             "get": {
-                "description": "Get a single inbox item by ID",
                 "tags": [
-                    "inbox"
+                    "file-watches"
                 ],
-                "summary": "Get inbox item",
+                "summary": "Get file watch",
                 "parameters": [
                     {
                         "type": "string",
@@ -1383,7 +1794,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Inbox item ID",
+                        "description": "File watch ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1393,11 +1804,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/inbox.Item"
+                            "$ref": "#/definitions/filewatch.Watch"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1416,12 +1827,11 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Delete a single inbox item",
+            "put": {
                 "tags": [
-                    "inbox"
+                    "file-watches"
                 ],
-                "summary": "Delete inbox item",
+                "summary": "Update file watch",
                 "parameters": [
                     {
                         "type": "string",
@@ -1432,43 +1842,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Inbox item ID",
+                        "description": "File watch ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    {
+                        "description": "File watch payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/filewatch.UpdateRequest"
                         }
                     }
-                }
-            }
-        },
-        "/bots/{bot_id}/mcp": {
-            "get": {
-                "description": "List MCP connections for a bot",
-                "tags": [
-                    "mcp"
                 ],
-                "summary": "List MCP connections",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/mcp.ListResponse"
+                            "$ref": "#/definitions/filewatch.Watch"
                         }
                     },
                     "400": {
@@ -1497,87 +1890,39 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Create a MCP connection for a bot",
+            "delete": {
                 "tags": [
-                    "mcp"
+                    "file-watches"
                 ],
-                "summary": "Create MCP connection",
+                "summary": "Delete file watch",
                 "parameters": [
                     {
-                        "description": "MCP payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/mcp.UpsertRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_memohai_memoh_internal_mcp.Connection"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/bots/{bot_id}/mcp-ops/batch-delete": {
-            "post": {
-                "description": "Delete multiple MCP connections by IDs.",
-                "tags": [
-                    "mcp"
-                ],
-                "summary": "Batch delete MCP connections",
-                "parameters": [
                     {
-                        "description": "IDs to delete",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.BatchDeleteRequest"
-                        }
+                        "type": "string",
+                        "description": "File watch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "204": {
                         "description": "No Content"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -1591,13 +1936,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{bot_id}/mcp-stdio": {
-            "post": {
-                "description": "Start a stdio MCP process in the bot container and expose it as MCP HTTP endpoint.",
+        "/bots/{bot_id}/inbox": {
+            "get": {
+                "description": "List inbox items for a bot with optional filters",
                 "tags": [
-                    "containerd"
+                    "inbox"
                 ],
-                "summary": "Create MCP stdio proxy",
+                "summary": "List inbox items",
                 "parameters": [
                     {
                         "type": "string",
@@ -1607,20 +1952,40 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Stdio MCP payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.MCPStdioRequest"
-                        }
+                        "type": "string",
+                        "description": "Filter by read status (true/false)",
+                        "name": "is_read",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by source",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Max items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.MCPStdioResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/inbox.Item"
+                            }
                         }
                     },
                     "400": {
@@ -1629,12 +1994,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1642,15 +2001,13 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/bots/{bot_id}/mcp-stdio/{connection_id}": {
+            },
             "post": {
-                "description": "Proxies MCP JSON-RPC requests to a stdio MCP process in the container.",
+                "description": "Create a new inbox item (for external integrations)",
                 "tags": [
-                    "containerd"
+                    "inbox"
                 ],
-                "summary": "MCP stdio proxy (JSON-RPC)",
+                "summary": "Create inbox item",
                 "parameters": [
                     {
                         "type": "string",
@@ -1660,27 +2017,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Connection ID",
-                        "name": "connection_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "JSON-RPC request",
+                        "description": "Inbox item payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/inbox.CreateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "JSON-RPC response: {jsonrpc,id,result|error}",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/inbox.Item"
                         }
                     },
                     "400": {
@@ -1689,12 +2039,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1704,18 +2048,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{bot_id}/mcp/export": {
+        "/bots/{bot_id}/inbox/count": {
             "get": {
-                "description": "Export all MCP connections for a bot in standard mcpServers format.",
+                "description": "Count unread and total inbox items",
                 "tags": [
-                    "mcp"
+                    "inbox"
+                ],
+                "summary": "Count inbox items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Export MCP connections",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/mcp.ExportResponse"
+                            "$ref": "#/definitions/inbox.CountResult"
                         }
                     },
                     "400": {
@@ -1724,12 +2077,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1739,30 +2086,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{bot_id}/mcp/import": {
-            "put": {
-                "description": "Batch import MCP connections from standard mcpServers format. Existing connections (matched by name) get config updated with is_active preserved. New connections are created as active.",
+        "/bots/{bot_id}/inbox/mark-read": {
+            "post": {
+                "description": "Batch mark inbox items as read",
                 "tags": [
-                    "mcp"
+                    "inbox"
                 ],
-                "summary": "Import MCP connections",
+                "summary": "Mark inbox items as read",
                 "parameters": [
                     {
-                        "description": "mcpServers dict",
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Item IDs to mark as read",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/mcp.ImportRequest"
+                            "$ref": "#/definitions/handlers.markReadRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/mcp.ListResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1770,12 +2121,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1785,17 +2130,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{bot_id}/mcp/{id}": {
+        "/bots/{bot_id}/inbox/{id}": {
             "get": {
-                "description": "Get a MCP connection by ID",
+                "description": "Get a single inbox item by ID",
                 "tags": [
-                    "mcp"
+                    "inbox"
                 ],
-                "summary": "Get MCP connection",
+                "summary": "Get inbox item",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "MCP ID",
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Inbox item ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1805,7 +2157,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_memohai_memoh_internal_mcp.Connection"
+                            "$ref": "#/definitions/inbox.Item"
                         }
                     },
                     "400": {
@@ -1814,12 +2166,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1834,35 +2180,59 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update a MCP connection by ID",
+            "delete": {
+                "description": "Delete a single inbox item",
                 "tags": [
-                    "mcp"
+                    "inbox"
                 ],
-                "summary": "Update MCP connection",
+                "summary": "Delete inbox item",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "MCP ID",
-                        "name": "id",
+                        "description": "Bot ID",
+                        "name": "bot_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "MCP payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Inbox item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/mcp.UpsertRequest"
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/bots/{bot_id}/mcp": {
+            "get": {
+                "description": "List MCP connections for a bot",
+                "tags": [
+                    "mcp"
                 ],
+                "summary": "List MCP connections",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_memohai_memoh_internal_mcp.Connection"
+                            "$ref": "#/definitions/mcp.ListResponse"
                         }
                     },
                     "400": {
@@ -1891,24 +2261,29 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Delete a MCP connection by ID",
+            "post": {
+                "description": "Create a MCP connection for a bot",
                 "tags": [
                     "mcp"
                 ],
-                "summary": "Delete MCP connection",
+                "summary": "Create MCP connection",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "MCP ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "MCP payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/mcp.UpsertRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_memohai_memoh_internal_mcp.Connection"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1937,37 +2312,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{bot_id}/memory": {
-            "get": {
-                "description": "List all memories in the bot-shared namespace",
-                "produces": [
-                    "application/json"
-                ],
+        "/bots/{bot_id}/mcp-ops/batch-delete": {
+            "post": {
+                "description": "Delete multiple MCP connections by IDs.",
                 "tags": [
-                    "memory"
+                    "mcp"
                 ],
-                "summary": "Get all memories",
+                "summary": "Batch delete MCP connections",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Bot ID",
-                        "name": "bot_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Skip sparse vector stats (top_k_buckets, cdf_curve) to reduce overhead",
-                        "name": "no_stats",
-                        "in": "query"
+                        "description": "IDs to delete",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BatchDeleteRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/memory.SearchResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1986,27 +2351,17 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    },
-                    "503": {
-                        "description": "Service Unavailable",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
                     }
                 }
-            },
+            }
+        },
+        "/bots/{bot_id}/mcp-stdio": {
             "post": {
-                "description": "Add memory into the bot-shared namespace",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Start a stdio MCP process in the bot container and expose it as MCP HTTP endpoint.",
                 "tags": [
-                    "memory"
+                    "containerd"
                 ],
-                "summary": "Add memory",
+                "summary": "Create MCP stdio proxy",
                 "parameters": [
                     {
                         "type": "string",
@@ -2016,12 +2371,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Memory add payload",
+                        "description": "Stdio MCP payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.memoryAddPayload"
+                            "$ref": "#/definitions/handlers.MCPStdioRequest"
                         }
                     }
                 ],
@@ -2029,7 +2384,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/memory.SearchResponse"
+                            "$ref": "#/definitions/handlers.MCPStdioResponse"
                         }
                     },
                     "400": {
@@ -2038,8 +2393,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2049,27 +2404,17 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    },
-                    "503": {
-                        "description": "Service Unavailable",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete specific memories by IDs, or delete all memories if no IDs are provided",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+            }
+        },
+        "/bots/{bot_id}/mcp-stdio/{connection_id}": {
+            "post": {
+                "description": "Proxies MCP JSON-RPC requests to a stdio MCP process in the container.",
                 "tags": [
-                    "memory"
+                    "containerd"
                 ],
-                "summary": "Delete memories",
+                "summary": "MCP stdio proxy (JSON-RPC)",
                 "parameters": [
                     {
                         "type": "string",
@@ -2079,19 +2424,27 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Optional: specify memory_ids to delete; if omitted, deletes all",
+                        "type": "string",
+                        "description": "Connection ID",
+                        "name": "connection_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON-RPC request",
                         "name": "payload",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.memoryDeletePayload"
+                            "type": "object"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "JSON-RPC response: {jsonrpc,id,result|error}",
                         "schema": {
-                            "$ref": "#/definitions/memory.DeleteResponse"
+                            "type": "object"
                         }
                     },
                     "400": {
@@ -2100,8 +2453,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2111,52 +2464,22 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    },
-                    "503": {
-                        "description": "Service Unavailable",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
                     }
                 }
             }
         },
-        "/bots/{bot_id}/memory/compact": {
-            "post": {
-                "description": "Consolidate memories by merging similar/redundant entries using LLM.\n\n**ratio** (required, range (0,1]):\n- 0.8 = light compression, mostly dedup, keep ~80% of entries\n- 0.5 = moderate compression, merge similar facts, keep ~50%\n- 0.3 = aggressive compression, heavily consolidate, keep ~30%\n\n**decay_days** (optional): enable time decay — memories older than N days are treated as low priority and more likely to be merged/dropped.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+        "/bots/{bot_id}/mcp/export": {
+            "get": {
+                "description": "Export all MCP connections for a bot in standard mcpServers format.",
                 "tags": [
-                    "memory"
-                ],
-                "summary": "Compact memories",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Bot ID",
-                        "name": "bot_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "ratio (0,1] required; decay_days optional",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.memoryCompactPayload"
-                        }
-                    }
+                    "mcp"
                 ],
+                "summary": "Export MCP connections",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/memory.CompactResult"
+                            "$ref": "#/definitions/mcp.ExportResponse"
                         }
                     },
                     "400": {
@@ -2176,40 +2499,33 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    },
-                    "503": {
-                        "description": "Service Unavailable",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
                     }
                 }
             }
         },
-        "/bots/{bot_id}/memory/rebuild": {
-            "post": {
-                "description": "Read memory files from the container filesystem (source of truth) and restore missing entries to Qdrant",
-                "produces": [
-                    "application/json"
-                ],
+        "/bots/{bot_id}/mcp/import": {
+            "put": {
+                "description": "Batch import MCP connections from standard mcpServers format. Existing connections (matched by name) get config updated with is_active preserved. New connections are created as active.",
                 "tags": [
-                    "memory"
+                    "mcp"
                 ],
-                "summary": "Rebuild memories from filesystem",
+                "summary": "Import MCP connections",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Bot ID",
-                        "name": "bot_id",
-                        "in": "path",
-                        "required": true
+                        "description": "mcpServers dict",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/mcp.ImportRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/memory.RebuildResult"
+                            "$ref": "#/definitions/mcp.ListResponse"
                         }
                     },
                     "400": {
@@ -2229,44 +2545,80 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    },
-                    "503": {
-                        "description": "Service Unavailable",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
                     }
                 }
             }
         },
-        "/bots/{bot_id}/memory/search": {
-            "post": {
-                "description": "Search memory in the bot-shared namespace",
-                "consumes": [
-                    "application/json"
+        "/bots/{bot_id}/mcp/{id}": {
+            "get": {
+                "description": "Get a MCP connection by ID",
+                "tags": [
+                    "mcp"
                 ],
-                "produces": [
-                    "application/json"
+                "summary": "Get MCP connection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "MCP ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_memohai_memoh_internal_mcp.Connection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a MCP connection by ID",
                 "tags": [
-                    "memory"
+                    "mcp"
                 ],
-                "summary": "Search memory",
+                "summary": "Update MCP connection",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bot ID",
-                        "name": "bot_id",
+                        "description": "MCP ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Memory search payload",
+                        "description": "MCP payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.memorySearchPayload"
+                            "$ref": "#/definitions/mcp.UpsertRequest"
                         }
                     }
                 ],
@@ -2274,7 +2626,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/memory.SearchResponse"
+                            "$ref": "#/definitions/github_com_memohai_memoh_internal_mcp.Connection"
                         }
                     },
                     "400": {
@@ -2300,9 +2652,48 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a MCP connection by ID",
+                "tags": [
+                    "mcp"
+                ],
+                "summary": "Delete MCP connection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "MCP ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2310,16 +2701,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{bot_id}/memory/usage": {
+        "/bots/{bot_id}/memory": {
             "get": {
-                "description": "Query the estimated storage usage of current memories",
+                "description": "List all memories in the bot-shared namespace",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "memory"
                 ],
-                "summary": "Get memory usage",
+                "summary": "Get all memories",
                 "parameters": [
                     {
                         "type": "string",
@@ -2327,13 +2718,19 @@ const docTemplate = `{
                         "name": "bot_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip sparse vector stats (top_k_buckets, cdf_curve) to reduce overhead",
+                        "name": "no_stats",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/memory.UsageResponse"
+                            "$ref": "#/definitions/memory.SearchResponse"
                         }
                     },
                     "400": {
@@ -2361,18 +2758,19 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/bots/{bot_id}/memory/{id}": {
-            "delete": {
-                "description": "Delete a single memory by its ID",
+            },
+            "post": {
+                "description": "Add memory into the bot-shared namespace. Memories with expires_at are removed automatically once it passes.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "memory"
                 ],
-                "summary": "Delete a single memory",
+                "summary": "Add memory",
                 "parameters": [
                     {
                         "type": "string",
@@ -2382,18 +2780,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Memory ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Memory add payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.memoryAddPayload"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/memory.DeleteResponse"
+                            "$ref": "#/definitions/memory.SearchResponse"
                         }
                     },
                     "400": {
@@ -2421,18 +2821,19 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/bots/{bot_id}/messages": {
-            "get": {
-                "description": "List messages for a bot history with optional pagination",
+            },
+            "delete": {
+                "description": "Delete specific memories by IDs, or delete all memories if no IDs are provided",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "messages"
+                    "memory"
                 ],
-                "summary": "List bot history messages",
+                "summary": "Delete memories",
                 "parameters": [
                     {
                         "type": "string",
@@ -2442,29 +2843,19 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Before",
-                        "name": "before",
-                        "in": "query"
+                        "description": "Optional: specify memory_ids to delete; if omitted, deletes all",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.memoryDeletePayload"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/message.Message"
-                                }
-                            }
+                            "$ref": "#/definitions/memory.DeleteResponse"
                         }
                     },
                     "400": {
@@ -2484,18 +2875,29 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "delete": {
-                "description": "Clear all persisted bot-level history messages",
+            }
+        },
+        "/bots/{bot_id}/memory/compact": {
+            "post": {
+                "description": "Consolidate memories by merging similar/redundant entries using LLM.\n\n**ratio** (required, range (0,1]):\n- 0.8 = light compression, mostly dedup, keep ~80% of entries\n- 0.5 = moderate compression, merge similar facts, keep ~50%\n- 0.3 = aggressive compression, heavily consolidate, keep ~30%\n\n**decay_days** (optional): enable time decay — memories older than N days are treated as low priority and more likely to be merged/dropped.\n\n**dry_run** (optional): only preview which memories would merge; nothing is changed.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "messages"
+                    "memory"
                 ],
-                "summary": "Delete all bot history messages",
+                "summary": "Compact memories",
                 "parameters": [
                     {
                         "type": "string",
@@ -2503,11 +2905,23 @@ const docTemplate = `{
                         "name": "bot_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "ratio (0,1] required; decay_days and dry_run optional",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.memoryCompactPayload"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/memory.CompactResult"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -2526,22 +2940,46 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/bots/{bot_id}/schedule": {
+        "/bots/{bot_id}/memory/compaction/runs": {
             "get": {
-                "description": "List schedules for current user",
+                "description": "List the scheduled and manual memory compactions of a bot, newest first, including the merges each run made or previewed",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "schedule"
+                    "memory"
+                ],
+                "summary": "List memory compaction runs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of runs (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List schedules",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schedule.ListResponse"
+                            "$ref": "#/definitions/schedule.CompactionRunListResponse"
                         }
                     },
                     "400": {
@@ -2550,46 +2988,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            },
-            "post": {
-                "description": "Create a schedule for current user",
-                "tags": [
-                    "schedule"
-                ],
-                "summary": "Create schedule",
-                "parameters": [
-                    {
-                        "description": "Schedule payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/schedule.CreateRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/schedule.Schedule"
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2597,27 +3009,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{bot_id}/schedule/{id}": {
+        "/bots/{bot_id}/memory/export": {
             "get": {
-                "description": "Get a schedule by ID",
+                "description": "Export bot-shared memories as a versioned archive (gzipped tar with manifest.json and memories.jsonl)",
+                "produces": [
+                    "application/gzip"
+                ],
                 "tags": [
-                    "schedule"
+                    "memory"
                 ],
-                "summary": "Get schedule",
+                "summary": "Export memories",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Schedule ID",
-                        "name": "id",
+                        "description": "Bot ID",
+                        "name": "bot_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include dense vectors and the model that produced them",
+                        "name": "include_vectors",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schedule.Schedule"
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -2626,8 +3047,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2637,38 +3058,56 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "put": {
-                "description": "Update a schedule by ID",
+            }
+        },
+        "/bots/{bot_id}/memory/import": {
+            "post": {
+                "description": "Import a memory archive produced by the export endpoint into the bot-shared namespace. When an embedding model is configured, vectors that are missing or from a different model are re-embedded unless embedding_enabled=false.",
+                "consumes": [
+                    "application/gzip",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "schedule"
+                    "memory"
                 ],
-                "summary": "Update schedule",
+                "summary": "Import memories",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Schedule ID",
-                        "name": "id",
+                        "description": "Bot ID",
+                        "name": "bot_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Schedule payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/schedule.UpdateRequest"
-                        }
+                        "type": "boolean",
+                        "description": "Re-embed memories whose vectors are missing or from another model (default true; false keeps archived vectors only)",
+                        "name": "embedding_enabled",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Memory archive (multipart upload); otherwise send the archive as the request body",
+                        "name": "file",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schedule.Schedule"
+                            "$ref": "#/definitions/memory.ImportResult"
                         }
                     },
                     "400": {
@@ -2677,32 +3116,52 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a schedule by ID",
+            }
+        },
+        "/bots/{bot_id}/memory/me": {
+            "get": {
+                "description": "List the memories the bot has learned from the caller, matched by user ID and every linked channel identity",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "schedule"
+                    "memory"
                 ],
-                "summary": "Delete schedule",
+                "summary": "List my memories",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Schedule ID",
-                        "name": "id",
+                        "description": "Bot ID",
+                        "name": "bot_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/memory.SearchResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -2715,22 +3174,38 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/bots/{bot_id}/settings": {
-            "get": {
-                "description": "Get agent settings for current user",
+            },
+            "delete": {
+                "description": "Delete every memory the bot has learned from the caller",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "settings"
+                    "memory"
+                ],
+                "summary": "Delete my memories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get user settings",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/settings.Settings"
+                            "$ref": "#/definitions/memory.DeleteResponse"
                         }
                     },
                     "400": {
@@ -2744,31 +3219,47 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "put": {
-                "description": "Update or create agent settings for current user",
+            }
+        },
+        "/bots/{bot_id}/memory/me/{memory_id}": {
+            "delete": {
+                "description": "Delete a single memory the bot has learned from the caller",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "settings"
+                    "memory"
                 ],
-                "summary": "Update user settings",
+                "summary": "Delete one of my memories",
                 "parameters": [
                     {
-                        "description": "Settings payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/settings.UpsertRequest"
-                        }
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Memory ID",
+                        "name": "memory_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/settings.Settings"
+                            "$ref": "#/definitions/memory.DeleteResponse"
                         }
                     },
                     "400": {
@@ -2777,36 +3268,51 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/bots/{bot_id}/memory/rebuild": {
             "post": {
-                "description": "Update or create agent settings for current user",
+                "description": "Read memory files from the container filesystem (source of truth) and restore missing entries to Qdrant",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "settings"
+                    "memory"
                 ],
-                "summary": "Update user settings",
+                "summary": "Rebuild memories from filesystem",
                 "parameters": [
                     {
-                        "description": "Settings payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/settings.UpsertRequest"
-                        }
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/settings.Settings"
+                            "$ref": "#/definitions/memory.RebuildResult"
                         }
                     },
                     "400": {
@@ -2815,32 +3321,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            },
-            "delete": {
-                "description": "Remove agent settings for current user",
-                "tags": [
-                    "settings"
-                ],
-                "summary": "Delete user settings",
-                "responses": {
-                    "204": {
-                        "description": "No Content"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2848,56 +3342,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{bot_id}/subagents": {
-            "get": {
-                "description": "List subagents for current user",
-                "tags": [
-                    "subagent"
-                ],
-                "summary": "List subagents",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/subagent.ListResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    }
-                }
-            },
+        "/bots/{bot_id}/memory/search": {
             "post": {
-                "description": "Create a subagent for current user",
+                "description": "Search memory in the bot-shared namespace",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "subagent"
+                    "memory"
                 ],
-                "summary": "Create subagent",
+                "summary": "Search memory",
                 "parameters": [
                     {
-                        "description": "Subagent payload",
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Memory search payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/subagent.CreateRequest"
+                            "$ref": "#/definitions/handlers.memorySearchPayload"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/subagent.Subagent"
+                            "$ref": "#/definitions/memory.SearchResponse"
                         }
                     },
                     "400": {
@@ -2906,27 +3386,48 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/bots/{bot_id}/subagents/{id}": {
+        "/bots/{bot_id}/memory/usage": {
             "get": {
-                "description": "Get a subagent by ID",
+                "description": "Query the estimated storage usage of current memories",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "subagent"
+                    "memory"
                 ],
-                "summary": "Get subagent",
+                "summary": "Get memory usage",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subagent ID",
-                        "name": "id",
+                        "description": "Bot ID",
+                        "name": "bot_id",
                         "in": "path",
                         "required": true
                     }
@@ -2935,7 +3436,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/subagent.Subagent"
+                            "$ref": "#/definitions/memory.UsageResponse"
                         }
                     },
                     "400": {
@@ -2944,8 +3445,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -2955,38 +3456,47 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "put": {
-                "description": "Update a subagent by ID",
+            }
+        },
+        "/bots/{bot_id}/memory/{id}": {
+            "delete": {
+                "description": "Delete a single memory by its ID",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "subagent"
+                    "memory"
                 ],
-                "summary": "Update subagent",
+                "summary": "Delete a single memory",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subagent ID",
-                        "name": "id",
+                        "description": "Bot ID",
+                        "name": "bot_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Subagent payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/subagent.UpdateRequest"
-                        }
+                        "type": "string",
+                        "description": "Memory ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/subagent.Subagent"
+                            "$ref": "#/definitions/memory.DeleteResponse"
                         }
                     },
                     "400": {
@@ -2995,8 +3505,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3006,27 +3516,54 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a subagent by ID",
+            }
+        },
+        "/bots/{bot_id}/memory/{memory_id}/revisions": {
+            "get": {
+                "description": "List the audit history of a memory (ADD/UPDATE/DELETE/COMPACT/RESTORE), newest first",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "subagent"
+                    "memory"
                 ],
-                "summary": "Delete subagent",
+                "summary": "List memory revisions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subagent ID",
-                        "name": "id",
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Memory ID",
+                        "name": "memory_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of revisions (default 50)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/memory.RevisionListResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -3034,8 +3571,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3045,31 +3582,60 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/bots/{bot_id}/subagents/{id}/context": {
-            "get": {
-                "description": "Get a subagent's message context",
+        "/bots/{bot_id}/memory/{memory_id}/revisions/{revision_id}/restore": {
+            "post": {
+                "description": "Restore a memory to the text and payload captured by a revision. Deleted memories are re-created under the same ID.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "subagent"
+                    "memory"
                 ],
-                "summary": "Get subagent context",
+                "summary": "Restore memory revision",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subagent ID",
-                        "name": "id",
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Memory ID",
+                        "name": "memory_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Revision ID",
+                        "name": "revision_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Re-embed the restored memory",
+                        "name": "embedding_enabled",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/subagent.ContextResponse"
+                            "$ref": "#/definitions/memory.MemoryItem"
                         }
                     },
                     "400": {
@@ -3078,6 +3644,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -3089,38 +3661,58 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "put": {
-                "description": "Update a subagent's message context",
+            }
+        },
+        "/bots/{bot_id}/messages": {
+            "get": {
+                "description": "List messages for a bot history with optional pagination",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "subagent"
+                    "messages"
                 ],
-                "summary": "Update subagent context",
+                "summary": "List bot history messages",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subagent ID",
-                        "name": "id",
+                        "description": "Bot ID",
+                        "name": "bot_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Context payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/subagent.UpdateContextRequest"
-                        }
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Before",
+                        "name": "before",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/subagent.ContextResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/message.Message"
+                                }
+                            }
                         }
                     },
                     "400": {
@@ -3129,8 +3721,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3142,30 +3734,28 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/bots/{bot_id}/subagents/{id}/skills": {
-            "get": {
-                "description": "Get a subagent's skills",
+            },
+            "delete": {
+                "description": "Clear all persisted bot-level history messages",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "subagent"
+                    "messages"
                 ],
-                "summary": "Get subagent skills",
+                "summary": "Delete all bot history messages",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Subagent ID",
-                        "name": "id",
+                        "description": "Bot ID",
+                        "name": "bot_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/subagent.SkillsResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -3173,8 +3763,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3186,36 +3776,20 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Replace a subagent's skills",
+            }
+        },
+        "/bots/{bot_id}/schedule": {
+            "get": {
+                "description": "List schedules for current user",
                 "tags": [
-                    "subagent"
-                ],
-                "summary": "Update subagent skills",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Subagent ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Skills payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/subagent.UpdateSkillsRequest"
-                        }
-                    }
+                    "schedule"
                 ],
+                "summary": "List schedules",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/subagent.SkillsResponse"
+                            "$ref": "#/definitions/schedule.ListResponse"
                         }
                     },
                     "400": {
@@ -3224,12 +3798,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3239,34 +3807,27 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Add skills to a subagent",
+                "description": "Create a schedule for current user",
                 "tags": [
-                    "subagent"
+                    "schedule"
                 ],
-                "summary": "Add subagent skills",
+                "summary": "Create schedule",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Subagent ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Skills payload",
+                        "description": "Schedule payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/subagent.AddSkillsRequest"
+                            "$ref": "#/definitions/schedule.CreateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/subagent.SkillsResponse"
+                            "$ref": "#/definitions/schedule.Schedule"
                         }
                     },
                     "400": {
@@ -3275,12 +3836,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3290,36 +3845,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{bot_id}/tools": {
-            "post": {
-                "description": "MCP endpoint for tool discovery and invocation.",
+        "/bots/{bot_id}/schedule/{id}": {
+            "get": {
+                "description": "Get a schedule by ID",
                 "tags": [
-                    "containerd"
+                    "schedule"
                 ],
-                "summary": "Unified MCP tools gateway",
+                "summary": "Get schedule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bot ID",
-                        "name": "bot_id",
+                        "description": "Schedule ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "JSON-RPC request",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "JSON-RPC response: {jsonrpc,id,result|error}",
+                        "description": "OK",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/schedule.Schedule"
                         }
                     },
                     "400": {
@@ -3341,36 +3887,28 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/bots/{bot_id}/web/messages": {
-            "post": {
-                "description": "Post a user message (with optional attachments) through the local channel pipeline.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "put": {
+                "description": "Update a schedule by ID",
                 "tags": [
-                    "local-channel"
+                    "schedule"
                 ],
-                "summary": "Send a message to a local channel",
+                "summary": "Update schedule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bot ID",
-                        "name": "bot_id",
+                        "description": "Schedule ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Message payload",
+                        "description": "Schedule payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.LocalChannelMessageRequest"
+                            "$ref": "#/definitions/schedule.UpdateRequest"
                         }
                     }
                 ],
@@ -3378,10 +3916,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/schedule.Schedule"
                         }
                     },
                     "400": {
@@ -3390,12 +3925,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3403,33 +3932,25 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/bots/{bot_id}/web/stream": {
-            "get": {
-                "description": "Open a persistent SSE connection to receive real-time stream events for the given bot.",
-                "produces": [
-                    "text/event-stream"
-                ],
+            },
+            "delete": {
+                "description": "Delete a schedule by ID",
                 "tags": [
-                    "local-channel"
+                    "schedule"
                 ],
-                "summary": "Subscribe to local channel events via SSE",
+                "summary": "Delete schedule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bot ID",
-                        "name": "bot_id",
+                        "description": "Schedule ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "SSE stream",
-                        "schema": {
-                            "type": "string"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -3437,12 +3958,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3452,27 +3967,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{id}": {
+        "/bots/{bot_id}/settings": {
             "get": {
-                "description": "Get a bot by ID (owner/admin only)",
+                "description": "Get agent settings for current user",
                 "tags": [
-                    "bots"
-                ],
-                "summary": "Get bot details",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Bot ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "settings"
                 ],
+                "summary": "Get user settings",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/bots.Bot"
+                            "$ref": "#/definitions/settings.Settings"
                         }
                     },
                     "400": {
@@ -3481,18 +3987,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3502,26 +3996,19 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update bot profile (owner/admin only)",
+                "description": "Update or create agent settings for current user",
                 "tags": [
-                    "bots"
+                    "settings"
                 ],
-                "summary": "Update bot details",
+                "summary": "Update user settings",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Bot ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Bot update payload",
+                        "description": "Settings payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/bots.UpdateBotRequest"
+                            "$ref": "#/definitions/settings.UpsertRequest"
                         }
                     }
                 ],
@@ -3529,7 +4016,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/bots.Bot"
+                            "$ref": "#/definitions/settings.Settings"
                         }
                     },
                     "400": {
@@ -3538,18 +4025,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3558,29 +4033,28 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Delete a bot user (owner/admin only)",
+            "post": {
+                "description": "Update or create agent settings for current user",
                 "tags": [
-                    "bots"
+                    "settings"
                 ],
-                "summary": "Delete bot",
+                "summary": "Update user settings",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Bot ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Settings payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/settings.UpsertRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/settings.Settings"
                         }
                     },
                     "400": {
@@ -3589,14 +4063,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove agent settings for current user",
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Delete user settings",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3610,25 +4096,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{id}/channel/{platform}": {
+        "/bots/{bot_id}/storage": {
             "get": {
-                "description": "Get bot channel configuration",
+                "description": "Get the storage provider bound to a bot",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "bots"
+                    "storage-providers"
                 ],
-                "summary": "Get bot channel config",
+                "summary": "Get bot storage binding",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Bot ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Channel platform",
-                        "name": "platform",
+                        "name": "bot_id",
                         "in": "path",
                         "required": true
                     }
@@ -3637,13 +4119,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/channel.ChannelConfig"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/storageproviders.BindingResponse"
                         }
                     },
                     "403": {
@@ -3657,43 +4133,36 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
                     }
                 }
             },
             "put": {
-                "description": "Update bot channel configuration",
+                "description": "Bind a bot to a storage provider. Existing assets are not moved; use the migrate endpoint for that.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "bots"
+                    "storage-providers"
                 ],
-                "summary": "Update bot channel config",
+                "summary": "Bind bot storage",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Bot ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Channel platform",
-                        "name": "platform",
+                        "name": "bot_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Channel config payload",
-                        "name": "payload",
+                        "description": "Binding",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/channel.UpsertConfigRequest"
+                            "$ref": "#/definitions/storageproviders.BindingRequest"
                         }
                     }
                 ],
@@ -3701,7 +4170,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/channel.ChannelConfig"
+                            "$ref": "#/definitions/storageproviders.BindingResponse"
                         }
                     },
                     "400": {
@@ -3715,39 +4184,20 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
                     }
                 }
             },
             "delete": {
-                "description": "Remove bot channel configuration",
+                "description": "Remove the bot storage binding so the bot uses the default provider",
                 "tags": [
-                    "bots"
+                    "storage-providers"
                 ],
-                "summary": "Delete bot channel config",
+                "summary": "Remove bot storage binding",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Bot ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Channel platform",
-                        "name": "platform",
+                        "name": "bot_id",
                         "in": "path",
                         "required": true
                     }
@@ -3756,12 +4206,6 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
@@ -3777,52 +4221,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{id}/channel/{platform}/send": {
-            "post": {
-                "description": "Send a message using bot channel configuration",
+        "/bots/{bot_id}/storage/migrate": {
+            "get": {
+                "description": "Get progress of the latest storage migration for a bot",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "bots"
+                    "storage-providers"
                 ],
-                "summary": "Send message via bot channel",
+                "summary": "Get bot storage migration status",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Bot ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Channel platform",
-                        "name": "platform",
+                        "name": "bot_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Send payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/channel.SendRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/storageproviders.MigrationStatus"
                         }
                     },
                     "403": {
@@ -3836,56 +4258,44 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
                     }
                 }
-            }
-        },
-        "/bots/{id}/channel/{platform}/send_chat": {
+            },
             "post": {
-                "description": "Send a message using a session-scoped token (reply only)",
+                "description": "Copy a bot's media assets to another storage provider in the background, then switch the binding",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "bots"
+                    "storage-providers"
                 ],
-                "summary": "Send message via bot channel session token",
+                "summary": "Migrate bot storage",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Bot ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Channel platform",
-                        "name": "platform",
+                        "name": "bot_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Send payload",
-                        "name": "payload",
+                        "description": "Migration target",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/channel.SendRequest"
+                            "$ref": "#/definitions/storageproviders.MigrateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/storageproviders.MigrationStatus"
                         }
                     },
                     "400": {
@@ -3894,20 +4304,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3915,43 +4319,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{id}/channel/{platform}/status": {
-            "patch": {
-                "description": "Update bot channel enabled/disabled status",
+        "/bots/{bot_id}/subagents": {
+            "get": {
+                "description": "List subagents for current user",
                 "tags": [
-                    "bots"
-                ],
-                "summary": "Update bot channel status",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Bot ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Channel platform",
-                        "name": "platform",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Channel status payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/channel.UpdateChannelStatusRequest"
-                        }
-                    }
+                    "subagent"
                 ],
+                "summary": "List subagents",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/channel.ChannelConfig"
+                            "$ref": "#/definitions/subagent.ListResponse"
                         }
                     },
                     "400": {
@@ -3960,14 +4339,40 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a subagent for current user",
+                "tags": [
+                    "subagent"
+                ],
+                "summary": "Create subagent",
+                "parameters": [
+                    {
+                        "description": "Subagent payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subagent.CreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/subagent.Subagent"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -3981,17 +4386,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{id}/checks": {
+        "/bots/{bot_id}/subagents/{id}": {
             "get": {
-                "description": "Evaluate bot attached resource checks in runtime",
+                "description": "Get a subagent by ID",
                 "tags": [
-                    "bots"
+                    "subagent"
                 ],
-                "summary": "List bot runtime checks",
+                "summary": "Get subagent",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bot ID",
+                        "description": "Subagent ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -4001,7 +4406,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/bots.ListChecksResponse"
+                            "$ref": "#/definitions/subagent.Subagent"
                         }
                     },
                     "400": {
@@ -4010,12 +4415,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -4029,29 +4428,36 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/bots/{id}/members": {
-            "get": {
-                "description": "List members for a bot",
+            },
+            "put": {
+                "description": "Update a subagent by ID",
                 "tags": [
-                    "bots"
+                    "subagent"
                 ],
-                "summary": "List bot members",
+                "summary": "Update subagent",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bot ID",
+                        "description": "Subagent ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Subagent payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subagent.UpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/bots.ListMembersResponse"
+                            "$ref": "#/definitions/subagent.Subagent"
                         }
                     },
                     "400": {
@@ -4060,12 +4466,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -4080,36 +4480,24 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Add or update bot member role",
+            "delete": {
+                "description": "Delete a subagent by ID",
                 "tags": [
-                    "bots"
+                    "subagent"
                 ],
-                "summary": "Upsert bot member",
+                "summary": "Delete subagent",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bot ID",
+                        "description": "Subagent ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Member payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/bots.UpsertMemberRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/bots.BotMember"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -4117,12 +4505,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -4138,41 +4520,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/bots/{id}/members/{user_id}": {
-            "delete": {
-                "description": "Remove a member from a bot",
+        "/bots/{bot_id}/subagents/{id}/context": {
+            "get": {
+                "description": "Get a subagent's message context",
                 "tags": [
-                    "bots"
+                    "subagent"
                 ],
-                "summary": "Delete bot member",
+                "summary": "Get subagent context",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bot ID",
+                        "description": "Subagent ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/subagent.ContextResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -4190,30 +4562,28 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/bots/{id}/owner": {
+            },
             "put": {
-                "description": "Transfer bot ownership to another human user",
+                "description": "Update a subagent's message context",
                 "tags": [
-                    "bots"
+                    "subagent"
                 ],
-                "summary": "Transfer bot owner (admin only)",
+                "summary": "Update subagent context",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bot ID",
+                        "description": "Subagent ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Transfer payload",
+                        "description": "Context payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/bots.TransferBotRequest"
+                            "$ref": "#/definitions/subagent.UpdateContextRequest"
                         }
                     }
                 ],
@@ -4221,7 +4591,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/bots.Bot"
+                            "$ref": "#/definitions/subagent.ContextResponse"
                         }
                     },
                     "400": {
@@ -4230,12 +4600,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -4251,21 +4615,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/channels": {
+        "/bots/{bot_id}/subagents/{id}/skills": {
             "get": {
-                "description": "List channel meta information including capabilities and schemas",
+                "description": "Get a subagent's skills",
                 "tags": [
-                    "channel"
+                    "subagent"
+                ],
+                "summary": "Get subagent skills",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subagent ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "List channel capabilities and schemas",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handlers.ChannelMeta"
-                            }
+                            "$ref": "#/definitions/subagent.SkillsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -4275,29 +4657,36 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/channels/{platform}": {
-            "get": {
-                "description": "Get channel meta information including capabilities and schemas",
+            },
+            "put": {
+                "description": "Replace a subagent's skills",
                 "tags": [
-                    "channel"
+                    "subagent"
                 ],
-                "summary": "Get channel capabilities and schemas",
+                "summary": "Update subagent skills",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel platform",
-                        "name": "platform",
+                        "description": "Subagent ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Skills payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subagent.UpdateSkillsRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ChannelMeta"
+                            "$ref": "#/definitions/subagent.SkillsResponse"
                         }
                     },
                     "400": {
@@ -4311,25 +4700,36 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
                 }
-            }
-        },
-        "/embeddings": {
+            },
             "post": {
-                "description": "Create text or multimodal embeddings",
+                "description": "Add skills to a subagent",
                 "tags": [
-                    "embeddings"
+                    "subagent"
                 ],
-                "summary": "Create embeddings",
+                "summary": "Add subagent skills",
                 "parameters": [
                     {
-                        "description": "Embeddings request",
+                        "type": "string",
+                        "description": "Subagent ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Skills payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.EmbeddingsRequest"
+                            "$ref": "#/definitions/subagent.AddSkillsRequest"
                         }
                     }
                 ],
@@ -4337,7 +4737,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.EmbeddingsResponse"
+                            "$ref": "#/definitions/subagent.SkillsResponse"
                         }
                     },
                     "400": {
@@ -4346,50 +4746,51 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "501": {
-                        "description": "Not Implemented",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.EmbeddingsResponse"
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/models": {
-            "get": {
-                "description": "Get a list of all configured models, optionally filtered by type or client type",
+        "/bots/{bot_id}/tools": {
+            "post": {
+                "description": "MCP endpoint for tool discovery and invocation.",
                 "tags": [
-                    "models"
+                    "containerd"
                 ],
-                "summary": "List all models",
+                "summary": "Unified MCP tools gateway",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Model type (chat, embedding)",
-                        "name": "type",
-                        "in": "query"
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Client type (openai-responses, openai-completions, anthropic-messages, google-generative-ai)",
-                        "name": "client_type",
-                        "in": "query"
+                        "description": "JSON-RPC request",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "JSON-RPC response: {jsonrpc,id,result|error}",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.GetResponse"
-                            }
+                            "type": "object"
                         }
                     },
                     "400": {
@@ -4398,6 +4799,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4405,29 +4812,47 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/bots/{bot_id}/web/messages": {
             "post": {
-                "description": "Create a new model configuration",
+                "description": "Post a user message (with optional attachments) through the local channel pipeline.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "models"
+                    "local-channel"
                 ],
-                "summary": "Create a new model",
+                "summary": "Send a message to a local channel",
                 "parameters": [
                     {
-                        "description": "Model configuration",
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.AddRequest"
+                            "$ref": "#/definitions/handlers.LocalChannelMessageRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.AddResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -4436,6 +4861,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4445,26 +4876,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/models/count": {
+        "/bots/{bot_id}/web/stream": {
             "get": {
-                "description": "Get the total count of models, optionally filtered by type",
+                "description": "Open a persistent SSE connection to receive real-time stream events for the given bot.",
+                "produces": [
+                    "text/event-stream"
+                ],
                 "tags": [
-                    "models"
+                    "local-channel"
                 ],
-                "summary": "Get model count",
+                "summary": "Subscribe to local channel events via SSE",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Model type (chat, embedding)",
-                        "name": "type",
-                        "in": "query"
+                        "description": "Bot ID",
+                        "name": "bot_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "SSE stream",
                         "schema": {
-                            "$ref": "#/definitions/models.CountResponse"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -4473,6 +4908,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4482,18 +4923,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/models/model/{modelId}": {
+        "/bots/{id}": {
             "get": {
-                "description": "Get a model configuration by its model_id field (e.g., gpt-4)",
+                "description": "Get a bot by ID (owner/admin only)",
                 "tags": [
-                    "models"
+                    "bots"
                 ],
-                "summary": "Get model by model ID",
+                "summary": "Get bot details",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Model ID (e.g., gpt-4)",
-                        "name": "modelId",
+                        "description": "Bot ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -4502,7 +4943,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.GetResponse"
+                            "$ref": "#/definitions/bots.Bot"
                         }
                     },
                     "400": {
@@ -4511,6 +4952,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -4526,26 +4973,26 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update a model configuration by its model_id field (e.g., gpt-4)",
+                "description": "Update bot profile (owner/admin only)",
                 "tags": [
-                    "models"
+                    "bots"
                 ],
-                "summary": "Update model by model ID",
+                "summary": "Update bot details",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Model ID (e.g., gpt-4)",
-                        "name": "modelId",
+                        "description": "Bot ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated model configuration",
+                        "description": "Bot update payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateRequest"
+                            "$ref": "#/definitions/bots.UpdateBotRequest"
                         }
                     }
                 ],
@@ -4553,7 +5000,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.GetResponse"
+                            "$ref": "#/definitions/bots.Bot"
                         }
                     },
                     "400": {
@@ -4562,6 +5009,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -4577,23 +5030,29 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a model configuration by its model_id field (e.g., gpt-4)",
+                "description": "Delete a bot user (owner/admin only)",
                 "tags": [
-                    "models"
+                    "bots"
                 ],
-                "summary": "Delete model by model ID",
+                "summary": "Delete bot",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Model ID (e.g., gpt-4)",
-                        "name": "modelId",
+                        "description": "Bot ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -4601,6 +5060,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -4616,27 +5081,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/models/{id}": {
+        "/bots/{id}/channel/{platform}": {
             "get": {
-                "description": "Get a model configuration by its internal UUID",
+                "description": "Get bot channel configuration",
                 "tags": [
-                    "models"
+                    "bots"
                 ],
-                "summary": "Get model by internal ID",
+                "summary": "Get bot channel config",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Model internal ID (UUID)",
+                        "description": "Bot ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel platform",
+                        "name": "platform",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Config name; empty selects the default config",
+                        "name": "name",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.GetResponse"
+                            "$ref": "#/definitions/channel.ChannelConfig"
                         }
                     },
                     "400": {
@@ -4645,6 +5123,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -4660,26 +5144,39 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update a model configuration by its internal UUID",
+                "description": "Update bot channel configuration",
                 "tags": [
-                    "models"
+                    "bots"
                 ],
-                "summary": "Update model by internal ID",
+                "summary": "Update bot channel config",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Model internal ID (UUID)",
+                        "description": "Bot ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated model configuration",
+                        "type": "string",
+                        "description": "Channel platform",
+                        "name": "platform",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Config name; empty selects the default config",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Channel config payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateRequest"
+                            "$ref": "#/definitions/channel.UpsertConfigRequest"
                         }
                     }
                 ],
@@ -4687,7 +5184,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.GetResponse"
+                            "$ref": "#/definitions/channel.ChannelConfig"
                         }
                     },
                     "400": {
@@ -4696,6 +5193,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -4711,18 +5214,31 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a model configuration by its internal UUID",
+                "description": "Remove bot channel configuration",
                 "tags": [
-                    "models"
+                    "bots"
                 ],
-                "summary": "Delete model by internal ID",
+                "summary": "Delete bot channel config",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Model internal ID (UUID)",
+                        "description": "Bot ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel platform",
+                        "name": "platform",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Config name; empty selects the default config",
+                        "name": "name",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -4735,8 +5251,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -4750,45 +5266,66 @@ const docTemplate = `{
                 }
             }
         },
-        "/ping": {
-            "get": {
+        "/bots/{id}/channel/{platform}/send": {
+            "post": {
+                "description": "Send a message using bot channel configuration",
                 "tags": [
-                    "system"
+                    "bots"
                 ],
-                "summary": "Health check with server capabilities",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                "summary": "Send message via bot channel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel platform",
+                        "name": "platform",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Send payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.PingResponse"
+                            "$ref": "#/definitions/channel.SendRequest"
                         }
                     }
-                }
-            }
-        },
-        "/providers": {
-            "get": {
-                "description": "Get a list of all configured LLM providers",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
                 ],
-                "tags": [
-                    "providers"
-                ],
-                "summary": "List all LLM providers",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/providers.GetResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4796,35 +5333,48 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/bots/{id}/channel/{platform}/send_chat": {
             "post": {
-                "description": "Create a new LLM provider configuration",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Send a message using a session-scoped token (reply only)",
                 "tags": [
-                    "providers"
+                    "bots"
                 ],
-                "summary": "Create a new LLM provider",
+                "summary": "Send message via bot channel session token",
                 "parameters": [
                     {
-                        "description": "Provider configuration",
-                        "name": "request",
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel platform",
+                        "name": "platform",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Send payload",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/providers.CreateRequest"
+                            "$ref": "#/definitions/channel.SendRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/providers.GetResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -4833,33 +5383,16 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/providers/count": {
-            "get": {
-                "description": "Get the total count of providers",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "providers"
-                ],
-                "summary": "Count providers",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/providers.CountResponse"
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -4871,33 +5404,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/providers/name/{name}": {
-            "get": {
-                "description": "Get a provider configuration by its name",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+        "/bots/{id}/channel/{platform}/status": {
+            "patch": {
+                "description": "Update bot channel enabled/disabled status",
                 "tags": [
-                    "providers"
+                    "bots"
                 ],
-                "summary": "Get provider by name",
+                "summary": "Update bot channel status",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Provider name",
-                        "name": "name",
+                        "description": "Bot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel platform",
+                        "name": "platform",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Config name; empty selects the default config",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Channel status payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/channel.UpdateChannelStatusRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/providers.GetResponse"
+                            "$ref": "#/definitions/channel.ChannelConfig"
                         }
                     },
                     "400": {
@@ -4906,6 +5455,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -4921,23 +5476,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/providers/{id}": {
+        "/bots/{id}/channels": {
             "get": {
-                "description": "Get a provider configuration by its ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "List all channel configurations of a bot, including named configs of the same platform",
                 "tags": [
-                    "providers"
+                    "bots"
                 ],
-                "summary": "Get provider by ID",
+                "summary": "List bot channel configs",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Provider ID (UUID)",
+                        "description": "Bot ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -4947,7 +5496,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/providers.GetResponse"
+                            "$ref": "#/definitions/handlers.ListBotChannelConfigsResponse"
                         }
                     },
                     "400": {
@@ -4956,8 +5505,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -4969,42 +5518,29 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing provider configuration",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+            }
+        },
+        "/bots/{id}/checks": {
+            "get": {
+                "description": "Evaluate bot attached resource checks in runtime",
                 "tags": [
-                    "providers"
+                    "bots"
                 ],
-                "summary": "Update provider",
+                "summary": "List bot runtime checks",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Provider ID (UUID)",
+                        "description": "Bot ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated provider configuration",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/providers.UpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/providers.GetResponse"
+                            "$ref": "#/definitions/bots.ListChecksResponse"
                         }
                     },
                     "400": {
@@ -5013,6 +5549,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -5026,31 +5568,38 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a provider configuration",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+            }
+        },
+        "/bots/{id}/clone": {
+            "post": {
+                "description": "Create a new bot owned by the current user with the settings, data files, MCP connections, schedules and subagents of an existing bot. Conversation history, memories and channel configs are not copied.",
                 "tags": [
-                    "providers"
+                    "bots"
                 ],
-                "summary": "Delete provider",
+                "summary": "Clone bot",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Provider ID (UUID)",
+                        "description": "Bot ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Clone payload",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CloneBotRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/bots.Bot"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -5058,6 +5607,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -5073,25 +5628,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/providers/{id}/models": {
-            "get": {
-                "description": "Get models for a provider by id, optionally filtered by type",
+        "/bots/{id}/export": {
+            "post": {
+                "description": "Export a bot as a gzipped tar with manifest.json, bot.json (bot, settings, channel configs, MCP connections, schedules and subagents), memories.tar.gz and the data/ tree. Models are referenced by name.",
+                "produces": [
+                    "application/gzip"
+                ],
                 "tags": [
-                    "providers"
+                    "bots"
                 ],
-                "summary": "List provider models",
+                "summary": "Export bot bundle",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Provider ID (UUID)",
+                        "description": "Bot ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Model type (chat, embedding)",
-                        "name": "type",
+                        "type": "boolean",
+                        "description": "Leave out channel credentials and MCP env/header values",
+                        "name": "redact_secrets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include memory vectors and the model that produced them",
+                        "name": "include_vectors",
                         "in": "query"
                     }
                 ],
@@ -5099,10 +5663,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.GetResponse"
-                            }
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -5111,6 +5672,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -5126,23 +5693,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/providers/{id}/test": {
-            "post": {
-                "description": "Probe a provider's base URL to check reachability, supported client types, and embedding support",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+        "/bots/{id}/members": {
+            "get": {
+                "description": "List members for a bot",
                 "tags": [
-                    "providers"
+                    "bots"
                 ],
-                "summary": "Test provider connectivity",
+                "summary": "List bot members",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Provider ID (UUID)",
+                        "description": "Bot ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -5152,7 +5713,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/providers.TestResponse"
+                            "$ref": "#/definitions/bots.ListMembersResponse"
                         }
                     },
                     "400": {
@@ -5161,6 +5722,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -5174,37 +5741,54 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/search-providers": {
-            "get": {
-                "description": "List configured search providers",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "put": {
+                "description": "Add or update bot member role",
                 "tags": [
-                    "search-providers"
+                    "bots"
                 ],
-                "summary": "List search providers",
+                "summary": "Upsert bot member",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Provider filter (brave)",
-                        "name": "provider",
-                        "in": "query"
+                        "description": "Bot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Member payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bots.UpsertMemberRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/searchproviders.GetResponse"
-                            }
+                            "$ref": "#/definitions/bots.BotMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -5214,36 +5798,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a search provider configuration",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+            }
+        },
+        "/bots/{id}/members/{user_id}": {
+            "delete": {
+                "description": "Remove a member from a bot",
                 "tags": [
-                    "search-providers"
+                    "bots"
                 ],
-                "summary": "Create a search provider",
+                "summary": "Delete bot member",
                 "parameters": [
                     {
-                        "description": "Search provider configuration",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/searchproviders.CreateRequest"
-                        }
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/searchproviders.GetResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -5251,6 +5833,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5260,44 +5854,103 @@ const docTemplate = `{
                 }
             }
         },
-        "/search-providers/meta": {
-            "get": {
-                "description": "List available search provider types and config schemas",
+        "/bots/{id}/owner": {
+            "put": {
+                "description": "Transfer bot ownership to another human user",
                 "tags": [
-                    "search-providers"
+                    "bots"
                 ],
-                "summary": "List search provider metadata",
-                "responses": {
+                "summary": "Transfer bot owner (admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transfer payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bots.TransferBotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/bots.Bot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels": {
+            "get": {
+                "description": "List channel meta information including capabilities and schemas",
+                "tags": [
+                    "channel"
+                ],
+                "summary": "List channel capabilities and schemas",
+                "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/searchproviders.ProviderMeta"
+                                "$ref": "#/definitions/handlers.ChannelMeta"
                             }
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/search-providers/{id}": {
+        "/channels/{platform}": {
             "get": {
-                "description": "Get search provider by ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Get channel meta information including capabilities and schemas",
                 "tags": [
-                    "search-providers"
+                    "channel"
                 ],
-                "summary": "Get a search provider",
+                "summary": "Get channel capabilities and schemas",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Provider ID",
-                        "name": "id",
+                        "description": "Channel platform",
+                        "name": "platform",
                         "in": "path",
                         "required": true
                     }
@@ -5306,7 +5959,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/searchproviders.GetResponse"
+                            "$ref": "#/definitions/handlers.ChannelMeta"
                         }
                     },
                     "400": {
@@ -5322,34 +5975,23 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update search provider by ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+            }
+        },
+        "/embeddings": {
+            "post": {
+                "description": "Create text or multimodal embeddings",
                 "tags": [
-                    "search-providers"
+                    "embeddings"
                 ],
-                "summary": "Update a search provider",
+                "summary": "Create embeddings",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Provider ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated configuration",
-                        "name": "request",
+                        "description": "Embeddings request",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/searchproviders.UpdateRequest"
+                            "$ref": "#/definitions/handlers.EmbeddingsRequest"
                         }
                     }
                 ],
@@ -5357,7 +5999,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/searchproviders.GetResponse"
+                            "$ref": "#/definitions/handlers.EmbeddingsResponse"
                         }
                     },
                     "400": {
@@ -5366,6 +6008,41 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.EmbeddingsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/images": {
+            "get": {
+                "description": "List the images available to bot containers (admin only)",
+                "tags": [
+                    "images"
+                ],
+                "summary": "List container images",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ListImagesResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5375,23 +6052,17 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete search provider by ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Delete an image that no bot container uses (admin only)",
                 "tags": [
-                    "search-providers"
+                    "images"
                 ],
-                "summary": "Delete a search provider",
+                "summary": "Delete a container image",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Provider ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Image reference",
+                        "name": "ref",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -5405,37 +6076,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/users": {
-            "get": {
-                "description": "List users",
-                "tags": [
-                    "users"
-                ],
-                "summary": "List users (admin only)",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/accounts.ListAccountsResponse"
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -5447,29 +6101,31 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/images/pull": {
             "post": {
-                "description": "Create a new human user account",
+                "description": "Pull an image with retries (admin only). When the registry is unreachable but the image is already present locally, the local copy is returned. With stream=true, progress is sent as server-sent events.",
                 "tags": [
-                    "users"
+                    "images"
                 ],
-                "summary": "Create human user (admin only)",
+                "summary": "Pull a container image",
                 "parameters": [
                     {
-                        "description": "User payload",
+                        "description": "Pull image payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/accounts.CreateAccountRequest"
+                            "$ref": "#/definitions/handlers.PullImageRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/accounts.Account"
+                            "$ref": "#/definitions/handlers.ImageInfo"
                         }
                     },
                     "400": {
@@ -5484,8 +6140,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -5493,18 +6149,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/me": {
+        "/models": {
             "get": {
-                "description": "Get current user profile",
+                "description": "Get a list of all configured models, optionally filtered by type or client type",
                 "tags": [
-                    "users"
+                    "models"
+                ],
+                "summary": "List all models",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Model type (chat, embedding, rerank)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client type (openai-responses, openai-completions, anthropic-messages, google-generative-ai)",
+                        "name": "client_type",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get current user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/accounts.Account"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.GetResponse"
+                            }
                         }
                     },
                     "400": {
@@ -5521,28 +6194,28 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update current user display name or avatar",
+            "post": {
+                "description": "Create a new model configuration",
                 "tags": [
-                    "users"
+                    "models"
                 ],
-                "summary": "Update current user profile",
+                "summary": "Create a new model",
                 "parameters": [
                     {
-                        "description": "Profile payload",
+                        "description": "Model configuration",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/accounts.UpdateProfileRequest"
+                            "$ref": "#/definitions/models.AddRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/accounts.Account"
+                            "$ref": "#/definitions/models.AddResponse"
                         }
                     },
                     "400": {
@@ -5560,27 +6233,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/me/channels/{platform}": {
+        "/models/count": {
             "get": {
-                "description": "Get channel binding configuration for current user",
+                "description": "Get the total count of models, optionally filtered by type",
                 "tags": [
-                    "channel"
+                    "models"
                 ],
-                "summary": "Get channel user config",
+                "summary": "Get model count",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel platform",
-                        "name": "platform",
-                        "in": "path",
-                        "required": true
+                        "description": "Model type (chat, embedding, rerank)",
+                        "name": "type",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/channel.ChannelIdentityBinding"
+                            "$ref": "#/definitions/models.CountResponse"
                         }
                     },
                     "400": {
@@ -5589,12 +6261,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5602,36 +6268,29 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update channel binding configuration for current user",
+            }
+        },
+        "/models/model/{modelId}": {
+            "get": {
+                "description": "Get a model configuration by its model_id field (e.g., gpt-4)",
                 "tags": [
-                    "channel"
+                    "models"
                 ],
-                "summary": "Update channel user config",
+                "summary": "Get model by model ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel platform",
-                        "name": "platform",
+                        "description": "Model ID (e.g., gpt-4)",
+                        "name": "modelId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Channel user config payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/channel.UpsertChannelIdentityConfigRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/channel.ChannelIdentityBinding"
+                            "$ref": "#/definitions/models.GetResponse"
                         }
                     },
                     "400": {
@@ -5640,6 +6299,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5647,20 +6312,36 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/users/me/identities": {
-            "get": {
-                "description": "List all channel identities linked to current user",
+            },
+            "put": {
+                "description": "Update a model configuration by its model_id field (e.g., gpt-4)",
                 "tags": [
-                    "users"
+                    "models"
+                ],
+                "summary": "Update model by model ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Model ID (e.g., gpt-4)",
+                        "name": "modelId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated model configuration",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateRequest"
+                        }
+                    }
                 ],
-                "summary": "List current user's channel identities",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.listMyIdentitiesResponse"
+                            "$ref": "#/definitions/models.GetResponse"
                         }
                     },
                     "400": {
@@ -5682,24 +6363,20 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/users/me/password": {
-            "put": {
-                "description": "Update current user password with current password check",
+            },
+            "delete": {
+                "description": "Delete a model configuration by its model_id field (e.g., gpt-4)",
                 "tags": [
-                    "users"
+                    "models"
                 ],
-                "summary": "Update current user password",
+                "summary": "Delete model by model ID",
                 "parameters": [
                     {
-                        "description": "Password payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/accounts.UpdatePasswordRequest"
-                        }
+                        "type": "string",
+                        "description": "Model ID (e.g., gpt-4)",
+                        "name": "modelId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -5712,6 +6389,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5721,17 +6404,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/models/{id}": {
             "get": {
-                "description": "Get user details (self or admin only)",
+                "description": "Get a model configuration by its internal UUID",
                 "tags": [
-                    "users"
+                    "models"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Get model by internal ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Model internal ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -5741,7 +6424,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/accounts.Account"
+                            "$ref": "#/definitions/models.GetResponse"
                         }
                     },
                     "400": {
@@ -5750,12 +6433,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -5771,26 +6448,26 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update user profile and status",
+                "description": "Update a model configuration by its internal UUID",
                 "tags": [
-                    "users"
+                    "models"
                 ],
-                "summary": "Update user (admin only)",
+                "summary": "Update model by internal ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Model internal ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "User update payload",
+                        "description": "Updated model configuration",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/accounts.UpdateAccountRequest"
+                            "$ref": "#/definitions/models.UpdateRequest"
                         }
                     }
                 ],
@@ -5798,7 +6475,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/accounts.Account"
+                            "$ref": "#/definitions/models.GetResponse"
                         }
                     },
                     "400": {
@@ -5807,12 +6484,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -5826,31 +6497,20 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/users/{id}/password": {
-            "put": {
-                "description": "Reset a user password",
+            },
+            "delete": {
+                "description": "Delete a model configuration by its internal UUID",
                 "tags": [
-                    "users"
+                    "models"
                 ],
-                "summary": "Reset user password (admin only)",
+                "summary": "Delete model by internal ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Model internal ID (UUID)",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Password payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/accounts.ResetPasswordRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -5863,12 +6523,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -5883,196 +6537,2640 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "accounts.Account": {
-            "type": "object",
-            "properties": {
-                "avatar_url": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "display_name": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "is_active": {
-                    "type": "boolean"
-                },
-                "last_login_at": {
-                    "type": "string"
-                },
-                "role": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "username": {
-                    "type": "string"
-                }
-            }
         },
-        "accounts.CreateAccountRequest": {
-            "type": "object",
-            "properties": {
-                "avatar_url": {
-                    "type": "string"
-                },
-                "display_name": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "is_active": {
-                    "type": "boolean"
-                },
-                "password": {
-                    "type": "string"
-                },
-                "role": {
-                    "type": "string"
-                },
-                "username": {
-                    "type": "string"
+        "/ping": {
+            "get": {
+                "tags": [
+                    "system"
+                ],
+                "summary": "Health check with server capabilities",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PingResponse"
+                        }
+                    }
                 }
             }
         },
-        "accounts.ListAccountsResponse": {
-            "type": "object",
-            "properties": {
-                "items": {
+        "/providers": {
+            "get": {
+                "description": "Get a list of all configured LLM providers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "List all LLM providers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/providers.GetResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new LLM provider configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Create a new LLM provider",
+                "parameters": [
+                    {
+                        "description": "Provider configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/providers.CreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/providers.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/providers/count": {
+            "get": {
+                "description": "Get the total count of providers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Count providers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/providers.CountResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/providers/name/{name}": {
+            "get": {
+                "description": "Get a provider configuration by its name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Get provider by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/providers.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/providers/{id}": {
+            "get": {
+                "description": "Get a provider configuration by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Get provider by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/providers.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing provider configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Update provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated provider configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/providers.UpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/providers.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a provider configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Delete provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/providers/{id}/models": {
+            "get": {
+                "description": "Get models for a provider by id, optionally filtered by type",
+                "tags": [
+                    "providers"
+                ],
+                "summary": "List provider models",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Model type (chat, embedding, rerank)",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.GetResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/providers/{id}/test": {
+            "post": {
+                "description": "Probe a provider's base URL to check reachability, supported client types, and embedding support",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "providers"
+                ],
+                "summary": "Test provider connectivity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/providers.TestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/search-providers": {
+            "get": {
+                "description": "List configured search providers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search-providers"
+                ],
+                "summary": "List search providers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider filter (brave)",
+                        "name": "provider",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/searchproviders.GetResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a search provider configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search-providers"
+                ],
+                "summary": "Create a search provider",
+                "parameters": [
+                    {
+                        "description": "Search provider configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/searchproviders.CreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/searchproviders.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/search-providers/meta": {
+            "get": {
+                "description": "List available search provider types and config schemas",
+                "tags": [
+                    "search-providers"
+                ],
+                "summary": "List search provider metadata",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/searchproviders.ProviderMeta"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/search-providers/{id}": {
+            "get": {
+                "description": "Get search provider by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search-providers"
+                ],
+                "summary": "Get a search provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/searchproviders.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update search provider by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search-providers"
+                ],
+                "summary": "Update a search provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/searchproviders.UpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/searchproviders.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete search provider by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search-providers"
+                ],
+                "summary": "Delete a search provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage-providers": {
+            "get": {
+                "description": "List configured storage providers (admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage-providers"
+                ],
+                "summary": "List storage providers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/storageproviders.GetResponse"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a storage provider configuration (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage-providers"
+                ],
+                "summary": "Create a storage provider",
+                "parameters": [
+                    {
+                        "description": "Storage provider configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storageproviders.CreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/storageproviders.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage-providers/meta": {
+            "get": {
+                "description": "List available storage provider types and config schemas",
+                "tags": [
+                    "storage-providers"
+                ],
+                "summary": "List storage provider metadata",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/storageproviders.ProviderMeta"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage-providers/{id}": {
+            "get": {
+                "description": "Get storage provider by ID (admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage-providers"
+                ],
+                "summary": "Get a storage provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/storageproviders.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update storage provider by ID (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "storage-providers"
+                ],
+                "summary": "Update a storage provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/storageproviders.UpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/storageproviders.GetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete storage provider by ID (admin only)",
+                "tags": [
+                    "storage-providers"
+                ],
+                "summary": "Delete a storage provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "List users",
+                "tags": [
+                    "users"
+                ],
+                "summary": "List users (admin only)",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.ListAccountsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new human user account",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create human user (admin only)",
+                "parameters": [
+                    {
+                        "description": "User payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/accounts.CreateAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.Account"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "description": "Get current user profile",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.Account"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update current user display name or avatar",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update current user profile",
+                "parameters": [
+                    {
+                        "description": "Profile payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/accounts.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.Account"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/channels/{platform}": {
+            "get": {
+                "description": "Get channel binding configuration for current user",
+                "tags": [
+                    "channel"
+                ],
+                "summary": "Get channel user config",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel platform",
+                        "name": "platform",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/channel.ChannelIdentityBinding"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update channel binding configuration for current user",
+                "tags": [
+                    "channel"
+                ],
+                "summary": "Update channel user config",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel platform",
+                        "name": "platform",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Channel user config payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/channel.UpsertChannelIdentityConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/channel.ChannelIdentityBinding"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/identities": {
+            "get": {
+                "description": "List all channel identities linked to current user",
+                "tags": [
+                    "users"
+                ],
+                "summary": "List current user's channel identities",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.listMyIdentitiesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/password": {
+            "put": {
+                "description": "Update current user password with current password check",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update current user password",
+                "parameters": [
+                    {
+                        "description": "Password payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/accounts.UpdatePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Get user details (self or admin only)",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.Account"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update user profile and status",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user (admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User update payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/accounts.UpdateAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.Account"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/password": {
+            "put": {
+                "description": "Reset a user password",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Reset user password (admin only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Password payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/accounts.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "accounts.Account": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "last_login_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.CreateAccountRequest": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.ListAccountsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/accounts.Account"
+                    }
+                }
+            }
+        },
+        "accounts.ResetPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.UpdateAccountRequest": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.UpdatePasswordRequest": {
+            "type": "object",
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.UpdateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "bots.Bot": {
+            "type": "object",
+            "properties": {
+                "allow_guest": {
+                    "type": "boolean"
+                },
+                "avatar_url": {
+                    "type": "string"
+                },
+                "check_issue_count": {
+                    "type": "integer"
+                },
+                "check_state": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "owner_user_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "bots.BotCheck": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subtitle": {
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "title_key": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "bots.BotMember": {
+            "type": "object",
+            "properties": {
+                "bot_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "bots.CreateBotRequest": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "template_id": {
+                    "description": "TemplateID applies an admin-managed bot template after creation.",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "bots.ListBotsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bots.Bot"
+                    }
+                }
+            }
+        },
+        "bots.ListChecksResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bots.BotCheck"
+                    }
+                }
+            }
+        },
+        "bots.ListMembersResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bots.BotMember"
+                    }
+                }
+            }
+        },
+        "bots.TransferBotRequest": {
+            "type": "object",
+            "properties": {
+                "owner_user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "bots.UpdateBotRequest": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "bots.UpsertMemberRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "bundle.ImportResult": {
+            "type": "object",
+            "properties": {
+                "bot_id": {
+                    "type": "string"
+                },
+                "channels": {
+                    "type": "integer"
+                },
+                "data_files": {
+                    "type": "integer"
+                },
+                "manifest": {
+                    "$ref": "#/definitions/bundle.Manifest"
+                },
+                "mcp_servers": {
+                    "type": "integer"
+                },
+                "memories": {
+                    "$ref": "#/definitions/memory.ImportResult"
+                },
+                "schedules": {
+                    "type": "integer"
+                },
+                "subagents": {
+                    "type": "integer"
+                },
+                "warnings": {
+                    "description": "Warnings lists parts of the bundle that could not be applied, such as\nmodels missing on this host or channels whose credentials were redacted.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "bundle.Manifest": {
+            "type": "object",
+            "properties": {
+                "bot_id": {
+                    "type": "string"
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "includes_vectors": {
+                    "type": "boolean"
+                },
+                "memory_count": {
+                    "type": "integer"
+                },
+                "secrets_redacted": {
+                    "description": "SecretsRedacted is set when channel credentials and MCP env/header\nvalues were left out of the bundle.",
+                    "type": "boolean"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "bundle.Schedule": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "max_calls": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                }
+            }
+        },
+        "bundle.Subagent": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": {}
+                    }
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "name": {
+                    "type": "string"
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "bundle.Template": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "spec": {
+                    "$ref": "#/definitions/bundle.TemplateSpec"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "bundle.TemplateRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "spec": {
+                    "$ref": "#/definitions/bundle.TemplateSpec"
+                }
+            }
+        },
+        "bundle.TemplateSkill": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "bundle.TemplateSpec": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "description": "Files maps paths relative to the data directory, such as IDENTITY.md or\nSOUL.md, to their content. They replace the defaults from the MCP\ntemplate.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "mcp_servers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/mcp.MCPServerEntry"
+                    }
+                },
+                "schedules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bundle.Schedule"
+                    }
+                },
+                "settings": {
+                    "description": "Settings is applied as-is; model fields accept a UUID or a model_id.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/settings.UpsertRequest"
+                        }
+                    ]
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bundle.TemplateSkill"
+                    }
+                },
+                "subagents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bundle.Subagent"
+                    }
+                }
+            }
+        },
+        "channel.Action": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "channel.Attachment": {
+            "type": "object",
+            "properties": {
+                "base64": {
+                    "description": "data URL for agent delivery",
+                    "type": "string"
+                },
+                "caption": {
+                    "type": "string"
+                },
+                "content_hash": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "mime": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "platform_key": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "source_platform": {
+                    "type": "string"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/channel.AttachmentType"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "channel.AttachmentType": {
+            "type": "string",
+            "enum": [
+                "image",
+                "audio",
+                "video",
+                "voice",
+                "file",
+                "gif"
+            ],
+            "x-enum-varnames": [
+                "AttachmentImage",
+                "AttachmentAudio",
+                "AttachmentVideo",
+                "AttachmentVoice",
+                "AttachmentFile",
+                "AttachmentGIF"
+            ]
+        },
+        "channel.ChannelCapabilities": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "type": "boolean"
+                },
+                "block_streaming": {
+                    "type": "boolean"
+                },
+                "buttons": {
+                    "type": "boolean"
+                },
+                "chat_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "edit": {
+                    "type": "boolean"
+                },
+                "markdown": {
+                    "type": "boolean"
+                },
+                "media": {
+                    "type": "boolean"
+                },
+                "native_commands": {
+                    "type": "boolean"
+                },
+                "polls": {
+                    "type": "boolean"
+                },
+                "reactions": {
+                    "type": "boolean"
+                },
+                "reply": {
+                    "type": "boolean"
+                },
+                "rich_text": {
+                    "type": "boolean"
+                },
+                "streaming": {
+                    "type": "boolean"
+                },
+                "text": {
+                    "type": "boolean"
+                },
+                "threads": {
+                    "type": "boolean"
+                },
+                "unsend": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "channel.ChannelConfig": {
+            "type": "object",
+            "properties": {
+                "bot_id": {
+                    "type": "string"
+                },
+                "channel_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credentials": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "disabled": {
+                    "type": "boolean"
+                },
+                "external_identity": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "routing": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "self_identity": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "verified_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "channel.ChannelIdentityBinding": {
+            "type": "object",
+            "properties": {
+                "channel_identity_id": {
+                    "type": "string"
+                },
+                "channel_type": {
+                    "type": "string"
+                },
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "channel.ConfigSchema": {
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/channel.FieldSchema"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "channel.FieldSchema": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "enum": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "example": {},
+                "required": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/channel.FieldType"
+                }
+            }
+        },
+        "channel.FieldType": {
+            "type": "string",
+            "enum": [
+                "string",
+                "secret",
+                "bool",
+                "number",
+                "enum"
+            ],
+            "x-enum-varnames": [
+                "FieldString",
+                "FieldSecret",
+                "FieldBool",
+                "FieldNumber",
+                "FieldEnum"
+            ]
+        },
+        "channel.Message": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/channel.Action"
+                    }
+                },
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/channel.Attachment"
+                    }
+                },
+                "format": {
+                    "$ref": "#/definitions/channel.MessageFormat"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "parts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/channel.MessagePart"
+                    }
+                },
+                "reply": {
+                    "$ref": "#/definitions/channel.ReplyRef"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "thread": {
+                    "$ref": "#/definitions/channel.ThreadRef"
+                }
+            }
+        },
+        "channel.MessageFormat": {
+            "type": "string",
+            "enum": [
+                "plain",
+                "markdown",
+                "rich"
+            ],
+            "x-enum-varnames": [
+                "MessageFormatPlain",
+                "MessageFormatMarkdown",
+                "MessageFormatRich"
+            ]
+        },
+        "channel.MessagePart": {
+            "type": "object",
+            "properties": {
+                "channel_identity_id": {
+                    "type": "string"
+                },
+                "emoji": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "styles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/channel.MessageTextStyle"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/channel.MessagePartType"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "channel.MessagePartType": {
+            "type": "string",
+            "enum": [
+                "text",
+                "link",
+                "code_block",
+                "mention",
+                "emoji"
+            ],
+            "x-enum-varnames": [
+                "MessagePartText",
+                "MessagePartLink",
+                "MessagePartCodeBlock",
+                "MessagePartMention",
+                "MessagePartEmoji"
+            ]
+        },
+        "channel.MessageTextStyle": {
+            "type": "string",
+            "enum": [
+                "bold",
+                "italic",
+                "strikethrough",
+                "code"
+            ],
+            "x-enum-varnames": [
+                "MessageStyleBold",
+                "MessageStyleItalic",
+                "MessageStyleStrikethrough",
+                "MessageStyleCode"
+            ]
+        },
+        "channel.ReplyRef": {
+            "type": "object",
+            "properties": {
+                "message_id": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
+        "channel.SendRequest": {
+            "type": "object",
+            "properties": {
+                "channel_identity_id": {
+                    "type": "string"
+                },
+                "config_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "$ref": "#/definitions/channel.Message"
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
+        "channel.TargetHint": {
+            "type": "object",
+            "properties": {
+                "example": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                }
+            }
+        },
+        "channel.TargetSpec": {
+            "type": "object",
+            "properties": {
+                "format": {
+                    "type": "string"
+                },
+                "hints": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/channel.TargetHint"
+                    }
+                }
+            }
+        },
+        "channel.ThreadRef": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "channel.UpdateChannelStatusRequest": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "channel.UpsertChannelIdentityConfigRequest": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "channel.UpsertConfigRequest": {
+            "type": "object",
+            "properties": {
+                "credentials": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "disabled": {
+                    "type": "boolean"
+                },
+                "external_identity": {
+                    "type": "string"
+                },
+                "routing": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "self_identity": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "verified_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "filewatch.CreateRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "command": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/accounts.Account"
+                        "type": "string"
                     }
+                },
+                "pattern": {
+                    "type": "string"
                 }
             }
         },
-        "accounts.ResetPasswordRequest": {
+        "filewatch.ListResponse": {
             "type": "object",
             "properties": {
-                "new_password": {
-                    "type": "string"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/filewatch.Watch"
+                    }
                 }
             }
         },
-        "accounts.UpdateAccountRequest": {
+        "filewatch.UpdateRequest": {
             "type": "object",
             "properties": {
-                "avatar_url": {
+                "action": {
                     "type": "string"
                 },
-                "display_name": {
+                "command": {
                     "type": "string"
                 },
-                "is_active": {
+                "enabled": {
                     "type": "boolean"
                 },
-                "role": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pattern": {
                     "type": "string"
                 }
             }
         },
-        "accounts.UpdatePasswordRequest": {
+        "filewatch.Watch": {
             "type": "object",
             "properties": {
-                "current_password": {
+                "action": {
                     "type": "string"
                 },
-                "new_password": {
+                "bot_id": {
+                    "type": "string"
+                },
+                "command": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "description": "Pattern is a glob relative to the data directory; \"**\" matches any\nnumber of directories, e.g. \"uploads/**/*.csv\".",
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "accounts.UpdateProfileRequest": {
+        "github_com_memohai_memoh_internal_mcp.Connection": {
             "type": "object",
             "properties": {
-                "avatar_url": {
+                "bot_id": {
                     "type": "string"
                 },
-                "display_name": {
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "bots.Bot": {
+        "handlers.BatchDeleteRequest": {
             "type": "object",
             "properties": {
-                "allow_guest": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.ChannelMeta": {
+            "type": "object",
+            "properties": {
+                "capabilities": {
+                    "$ref": "#/definitions/channel.ChannelCapabilities"
+                },
+                "config_schema": {
+                    "$ref": "#/definitions/channel.ConfigSchema"
+                },
+                "configless": {
                     "type": "boolean"
                 },
-                "avatar_url": {
+                "display_name": {
                     "type": "string"
                 },
-                "check_issue_count": {
-                    "type": "integer"
+                "target_spec": {
+                    "$ref": "#/definitions/channel.TargetSpec"
                 },
-                "check_state": {
+                "type": {
                     "type": "string"
                 },
-                "created_at": {
+                "user_config_schema": {
+                    "$ref": "#/definitions/channel.ConfigSchema"
+                }
+            }
+        },
+        "handlers.CloneBotRequest": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "description": "DisplayName defaults to the source bot's name with a \" (copy)\" suffix.",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateContainerRequest": {
+            "type": "object",
+            "properties": {
+                "snapshotter": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateContainerResponse": {
+            "type": "object",
+            "properties": {
+                "container_id": {
                     "type": "string"
                 },
-                "display_name": {
+                "image": {
                     "type": "string"
                 },
-                "id": {
+                "snapshotter": {
                     "type": "string"
                 },
-                "is_active": {
+                "started": {
                     "type": "boolean"
-                },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "owner_user_id": {
+                }
+            }
+        },
+        "handlers.CreateSnapshotRequest": {
+            "type": "object",
+            "properties": {
+                "snapshot_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateSnapshotResponse": {
+            "type": "object",
+            "properties": {
+                "container_id": {
                     "type": "string"
                 },
-                "status": {
+                "snapshot_name": {
                     "type": "string"
                 },
-                "type": {
+                "snapshotter": {
                     "type": "string"
                 },
-                "updated_at": {
+                "source": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "bots.BotCheck": {
+        "handlers.EmbeddingsInput": {
             "type": "object",
             "properties": {
-                "detail": {
+                "image_url": {
                     "type": "string"
                 },
-                "id": {
+                "text": {
                     "type": "string"
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "status": {
+                "video_url": {
                     "type": "string"
+                }
+            }
+        },
+        "handlers.EmbeddingsRequest": {
+            "type": "object",
+            "properties": {
+                "dimensions": {
+                    "type": "integer"
                 },
-                "subtitle": {
-                    "type": "string"
+                "input": {
+                    "$ref": "#/definitions/handlers.EmbeddingsInput"
                 },
-                "summary": {
+                "model": {
                     "type": "string"
                 },
-                "title_key": {
+                "provider": {
                     "type": "string"
                 },
                 "type": {
@@ -6080,633 +9178,441 @@ const docTemplate = `{
                 }
             }
         },
-        "bots.BotMember": {
+        "handlers.EmbeddingsResponse": {
             "type": "object",
             "properties": {
-                "bot_id": {
+                "dimensions": {
+                    "type": "integer"
+                },
+                "embedding": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "message": {
                     "type": "string"
                 },
-                "created_at": {
+                "model": {
                     "type": "string"
                 },
-                "role": {
+                "provider": {
                     "type": "string"
                 },
-                "user_id": {
+                "type": {
                     "type": "string"
+                },
+                "usage": {
+                    "$ref": "#/definitions/handlers.EmbeddingsUsage"
                 }
             }
         },
-        "bots.CreateBotRequest": {
+        "handlers.EmbeddingsUsage": {
             "type": "object",
             "properties": {
-                "avatar_url": {
-                    "type": "string"
-                },
-                "display_name": {
-                    "type": "string"
-                },
-                "is_active": {
-                    "type": "boolean"
+                "duration": {
+                    "type": "integer"
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "image_tokens": {
+                    "type": "integer"
                 },
-                "type": {
+                "input_tokens": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
                     "type": "string"
                 }
             }
         },
-        "bots.ListBotsResponse": {
+        "handlers.FSDeleteRequest": {
             "type": "object",
             "properties": {
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/bots.Bot"
-                    }
+                "path": {
+                    "type": "string"
+                },
+                "recursive": {
+                    "type": "boolean"
                 }
             }
         },
-        "bots.ListChecksResponse": {
+        "handlers.FSFileInfo": {
             "type": "object",
             "properties": {
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/bots.BotCheck"
-                    }
+                "isDir": {
+                    "type": "boolean"
+                },
+                "modTime": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
                 }
             }
         },
-        "bots.ListMembersResponse": {
+        "handlers.FSListResponse": {
             "type": "object",
             "properties": {
-                "items": {
+                "entries": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/bots.BotMember"
+                        "$ref": "#/definitions/handlers.FSFileInfo"
                     }
+                },
+                "path": {
+                    "type": "string"
                 }
             }
         },
-        "bots.TransferBotRequest": {
+        "handlers.FSMkdirRequest": {
             "type": "object",
             "properties": {
-                "owner_user_id": {
+                "path": {
                     "type": "string"
                 }
             }
         },
-        "bots.UpdateBotRequest": {
+        "handlers.FSReadResponse": {
             "type": "object",
             "properties": {
-                "avatar_url": {
+                "content": {
                     "type": "string"
                 },
-                "display_name": {
+                "path": {
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
-                },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "size": {
+                    "type": "integer"
                 }
             }
         },
-        "bots.UpsertMemberRequest": {
+        "handlers.FSRenameRequest": {
             "type": "object",
             "properties": {
-                "role": {
+                "newPath": {
                     "type": "string"
                 },
-                "user_id": {
+                "oldPath": {
                     "type": "string"
                 }
             }
         },
-        "channel.Action": {
+        "handlers.FSUploadResponse": {
             "type": "object",
             "properties": {
-                "label": {
-                    "type": "string"
-                },
-                "type": {
+                "path": {
                     "type": "string"
                 },
-                "url": {
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.FSWriteRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
                     "type": "string"
                 },
-                "value": {
+                "path": {
                     "type": "string"
                 }
             }
         },
-        "channel.Attachment": {
+        "handlers.GetContainerResponse": {
             "type": "object",
             "properties": {
-                "base64": {
-                    "description": "data URL for agent delivery",
-                    "type": "string"
-                },
-                "caption": {
+                "container_id": {
                     "type": "string"
                 },
-                "content_hash": {
+                "container_path": {
                     "type": "string"
                 },
-                "duration_ms": {
-                    "type": "integer"
-                },
-                "height": {
-                    "type": "integer"
-                },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "mime": {
+                "created_at": {
                     "type": "string"
                 },
-                "name": {
+                "host_path": {
                     "type": "string"
                 },
-                "platform_key": {
+                "image": {
                     "type": "string"
                 },
-                "size": {
-                    "type": "integer"
-                },
-                "source_platform": {
+                "namespace": {
                     "type": "string"
                 },
-                "thumbnail_url": {
+                "status": {
                     "type": "string"
                 },
-                "type": {
-                    "$ref": "#/definitions/channel.AttachmentType"
+                "task_running": {
+                    "type": "boolean"
                 },
-                "url": {
+                "updated_at": {
                     "type": "string"
-                },
-                "width": {
-                    "type": "integer"
                 }
             }
         },
-        "channel.AttachmentType": {
-            "type": "string",
-            "enum": [
-                "image",
-                "audio",
-                "video",
-                "voice",
-                "file",
-                "gif"
-            ],
-            "x-enum-varnames": [
-                "AttachmentImage",
-                "AttachmentAudio",
-                "AttachmentVideo",
-                "AttachmentVoice",
-                "AttachmentFile",
-                "AttachmentGIF"
-            ]
-        },
-        "channel.ChannelCapabilities": {
+        "handlers.ImageInfo": {
             "type": "object",
             "properties": {
-                "attachments": {
+                "default": {
                     "type": "boolean"
                 },
-                "block_streaming": {
-                    "type": "boolean"
+                "id": {
+                    "type": "string"
                 },
-                "buttons": {
-                    "type": "boolean"
+                "name": {
+                    "type": "string"
                 },
-                "chat_types": {
+                "tags": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
-                },
-                "edit": {
-                    "type": "boolean"
-                },
-                "markdown": {
-                    "type": "boolean"
-                },
-                "media": {
-                    "type": "boolean"
-                },
-                "native_commands": {
-                    "type": "boolean"
-                },
-                "polls": {
-                    "type": "boolean"
-                },
-                "reactions": {
-                    "type": "boolean"
-                },
-                "reply": {
-                    "type": "boolean"
-                },
-                "rich_text": {
-                    "type": "boolean"
-                },
-                "streaming": {
-                    "type": "boolean"
-                },
-                "text": {
-                    "type": "boolean"
-                },
-                "threads": {
-                    "type": "boolean"
-                },
-                "unsend": {
-                    "type": "boolean"
                 }
             }
         },
-        "channel.ChannelConfig": {
+        "handlers.ListBotChannelConfigsResponse": {
             "type": "object",
             "properties": {
-                "bot_id": {
-                    "type": "string"
-                },
-                "channel_type": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "credentials": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "disabled": {
-                    "type": "boolean"
-                },
-                "external_identity": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "routing": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "self_identity": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "verified_at": {
-                    "type": "string"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/channel.ChannelConfig"
+                    }
                 }
             }
         },
-        "channel.ChannelIdentityBinding": {
+        "handlers.ListBotTemplatesResponse": {
             "type": "object",
             "properties": {
-                "channel_identity_id": {
-                    "type": "string"
-                },
-                "channel_type": {
-                    "type": "string"
-                },
-                "config": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bundle.Template"
+                    }
                 }
             }
         },
-        "channel.ConfigSchema": {
+        "handlers.ListImagesResponse": {
             "type": "object",
             "properties": {
-                "fields": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/channel.FieldSchema"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ImageInfo"
                     }
-                },
-                "version": {
-                    "type": "integer"
                 }
             }
         },
-        "channel.FieldSchema": {
+        "handlers.ListSnapshotsResponse": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "enum": {
+                "snapshots": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/handlers.SnapshotInfo"
                     }
                 },
-                "example": {},
-                "required": {
-                    "type": "boolean"
-                },
-                "title": {
+                "snapshotter": {
                     "type": "string"
-                },
-                "type": {
-                    "$ref": "#/definitions/channel.FieldType"
                 }
             }
         },
-        "channel.FieldType": {
-            "type": "string",
-            "enum": [
-                "string",
-                "secret",
-                "bool",
-                "number",
-                "enum"
-            ],
-            "x-enum-varnames": [
-                "FieldString",
-                "FieldSecret",
-                "FieldBool",
-                "FieldNumber",
-                "FieldEnum"
-            ]
+        "handlers.LocalChannelMessageRequest": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "$ref": "#/definitions/channel.Message"
+                }
+            }
         },
-        "channel.Message": {
+        "handlers.LoginRequest": {
             "type": "object",
             "properties": {
-                "actions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/channel.Action"
-                    }
-                },
-                "attachments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/channel.Attachment"
-                    }
-                },
-                "format": {
-                    "$ref": "#/definitions/channel.MessageFormat"
-                },
-                "id": {
+                "password": {
                     "type": "string"
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "parts": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/channel.MessagePart"
-                    }
-                },
-                "reply": {
-                    "$ref": "#/definitions/channel.ReplyRef"
-                },
-                "text": {
+                "username": {
                     "type": "string"
-                },
-                "thread": {
-                    "$ref": "#/definitions/channel.ThreadRef"
                 }
             }
         },
-        "channel.MessageFormat": {
-            "type": "string",
-            "enum": [
-                "plain",
-                "markdown",
-                "rich"
-            ],
-            "x-enum-varnames": [
-                "MessageFormatPlain",
-                "MessageFormatMarkdown",
-                "MessageFormatRich"
-            ]
-        },
-        "channel.MessagePart": {
+        "handlers.LoginResponse": {
             "type": "object",
             "properties": {
-                "channel_identity_id": {
+                "access_token": {
                     "type": "string"
                 },
-                "emoji": {
+                "display_name": {
                     "type": "string"
                 },
-                "language": {
+                "expires_at": {
                     "type": "string"
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "styles": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/channel.MessageTextStyle"
-                    }
+                "role": {
+                    "type": "string"
                 },
-                "text": {
+                "token_type": {
                     "type": "string"
                 },
-                "type": {
-                    "$ref": "#/definitions/channel.MessagePartType"
+                "user_id": {
+                    "type": "string"
                 },
-                "url": {
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "channel.MessagePartType": {
-            "type": "string",
-            "enum": [
-                "text",
-                "link",
-                "code_block",
-                "mention",
-                "emoji"
-            ],
-            "x-enum-varnames": [
-                "MessagePartText",
-                "MessagePartLink",
-                "MessagePartCodeBlock",
-                "MessagePartMention",
-                "MessagePartEmoji"
-            ]
-        },
-        "channel.MessageTextStyle": {
-            "type": "string",
-            "enum": [
-                "bold",
-                "italic",
-                "strikethrough",
-                "code"
-            ],
-            "x-enum-varnames": [
-                "MessageStyleBold",
-                "MessageStyleItalic",
-                "MessageStyleStrikethrough",
-                "MessageStyleCode"
-            ]
-        },
-        "channel.ReplyRef": {
+        "handlers.MCPStdioRequest": {
             "type": "object",
             "properties": {
-                "message_id": {
+                "args": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "command": {
                     "type": "string"
                 },
-                "target": {
+                "cwd": {
+                    "type": "string"
+                },
+                "env": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "channel.SendRequest": {
+        "handlers.MCPStdioResponse": {
             "type": "object",
             "properties": {
-                "channel_identity_id": {
+                "connection_id": {
                     "type": "string"
                 },
-                "message": {
-                    "$ref": "#/definitions/channel.Message"
+                "tools": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "target": {
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "channel.TargetHint": {
+        "handlers.PingResponse": {
             "type": "object",
             "properties": {
-                "example": {
+                "container_backend": {
                     "type": "string"
                 },
-                "label": {
+                "snapshot_supported": {
+                    "type": "boolean"
+                },
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "channel.TargetSpec": {
+        "handlers.PruneSnapshotsResponse": {
             "type": "object",
             "properties": {
-                "format": {
+                "container_id": {
                     "type": "string"
                 },
-                "hints": {
+                "removed": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/channel.TargetHint"
+                        "type": "string"
+                    }
+                },
+                "retained": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
                     }
                 }
             }
         },
-        "channel.ThreadRef": {
+        "handlers.PullImageRequest": {
             "type": "object",
             "properties": {
-                "id": {
+                "ref": {
                     "type": "string"
-                }
-            }
-        },
-        "channel.UpdateChannelStatusRequest": {
-            "type": "object",
-            "properties": {
-                "disabled": {
+                },
+                "stream": {
+                    "description": "Stream reports pull progress as server-sent events instead of a single\nJSON response.",
                     "type": "boolean"
                 }
             }
         },
-        "channel.UpsertChannelIdentityConfigRequest": {
+        "handlers.SetContainerImageRequest": {
             "type": "object",
             "properties": {
-                "config": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "image": {
+                    "description": "Image is the image reference; empty restores the default MCP image.",
+                    "type": "string"
                 }
             }
         },
-        "channel.UpsertConfigRequest": {
+        "handlers.SetContainerImageResponse": {
             "type": "object",
             "properties": {
-                "credentials": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "disabled": {
-                    "type": "boolean"
-                },
-                "external_identity": {
-                    "type": "string"
-                },
-                "routing": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "self_identity": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "container_id": {
+                    "type": "string"
                 },
-                "verified_at": {
+                "image": {
                     "type": "string"
                 }
             }
         },
-        "github_com_memohai_memoh_internal_mcp.Connection": {
+        "handlers.SkillItem": {
             "type": "object",
             "properties": {
-                "bot_id": {
-                    "type": "string"
-                },
-                "config": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "created_at": {
+                "content": {
                     "type": "string"
                 },
-                "id": {
+                "description": {
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
                 },
                 "name": {
                     "type": "string"
-                },
-                "type": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
                 }
             }
         },
-        "handlers.BatchDeleteRequest": {
+        "handlers.SkillsDeleteRequest": {
             "type": "object",
             "properties": {
-                "ids": {
+                "names": {
                     "type": "array",
                     "items": {
                         "type": "string"
@@ -6714,72 +9620,50 @@ const docTemplate = `{
                 }
             }
         },
-        "handlers.ChannelMeta": {
+        "handlers.SkillsResponse": {
             "type": "object",
             "properties": {
-                "capabilities": {
-                    "$ref": "#/definitions/channel.ChannelCapabilities"
-                },
-                "config_schema": {
-                    "$ref": "#/definitions/channel.ConfigSchema"
-                },
-                "configless": {
-                    "type": "boolean"
-                },
-                "display_name": {
-                    "type": "string"
-                },
-                "target_spec": {
-                    "$ref": "#/definitions/channel.TargetSpec"
-                },
-                "type": {
-                    "type": "string"
-                },
-                "user_config_schema": {
-                    "$ref": "#/definitions/channel.ConfigSchema"
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SkillItem"
+                    }
                 }
             }
         },
-        "handlers.CreateContainerRequest": {
+        "handlers.SkillsUpsertRequest": {
             "type": "object",
             "properties": {
-                "snapshotter": {
-                    "type": "string"
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SkillItem"
+                    }
                 }
             }
         },
-        "handlers.CreateContainerResponse": {
+        "handlers.SnapshotInfo": {
             "type": "object",
             "properties": {
-                "container_id": {
+                "created_at": {
                     "type": "string"
                 },
-                "image": {
+                "kind": {
                     "type": "string"
                 },
-                "snapshotter": {
-                    "type": "string"
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "started": {
+                "managed": {
                     "type": "boolean"
-                }
-            }
-        },
-        "handlers.CreateSnapshotRequest": {
-            "type": "object",
-            "properties": {
-                "snapshot_name": {
-                    "type": "string"
-                }
-            }
-        },
-        "handlers.CreateSnapshotResponse": {
-            "type": "object",
-            "properties": {
-                "container_id": {
+                },
+                "name": {
                     "type": "string"
                 },
-                "snapshot_name": {
+                "parent": {
                     "type": "string"
                 },
                 "snapshotter": {
@@ -6788,291 +9672,336 @@ const docTemplate = `{
                 "source": {
                     "type": "string"
                 },
+                "updated_at": {
+                    "type": "string"
+                },
                 "version": {
                     "type": "integer"
                 }
             }
         },
-        "handlers.EmbeddingsInput": {
+        "handlers.VersionDiffResponse": {
             "type": "object",
             "properties": {
-                "image_url": {
-                    "type": "string"
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.VersionFileChange"
+                    }
                 },
-                "text": {
+                "container_id": {
                     "type": "string"
                 },
-                "video_url": {
-                    "type": "string"
+                "from": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
+                },
+                "truncated": {
+                    "type": "boolean"
                 }
             }
         },
-        "handlers.EmbeddingsRequest": {
+        "handlers.VersionFileChange": {
             "type": "object",
             "properties": {
-                "dimensions": {
-                    "type": "integer"
-                },
-                "input": {
-                    "$ref": "#/definitions/handlers.EmbeddingsInput"
-                },
-                "model": {
+                "kind": {
                     "type": "string"
                 },
-                "provider": {
+                "path": {
                     "type": "string"
+                }
+            }
+        },
+        "handlers.fsOpResponse": {
+            "type": "object",
+            "properties": {
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.listMyIdentitiesResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/identities.ChannelIdentity"
+                    }
                 },
-                "type": {
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "handlers.EmbeddingsResponse": {
+        "handlers.markReadRequest": {
             "type": "object",
             "properties": {
-                "dimensions": {
-                    "type": "integer"
-                },
-                "embedding": {
+                "ids": {
                     "type": "array",
                     "items": {
-                        "type": "number"
+                        "type": "string"
                     }
+                }
+            }
+        },
+        "handlers.memoryAddPayload": {
+            "type": "object",
+            "properties": {
+                "embedding_enabled": {
+                    "type": "boolean"
                 },
-                "message": {
+                "expires_at": {
                     "type": "string"
                 },
-                "model": {
-                    "type": "string"
+                "filters": {
+                    "type": "object",
+                    "additionalProperties": {}
                 },
-                "provider": {
+                "infer": {
+                    "type": "boolean"
+                },
+                "message": {
                     "type": "string"
                 },
-                "type": {
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/memory.Message"
+                    }
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "namespace": {
                     "type": "string"
                 },
-                "usage": {
-                    "$ref": "#/definitions/handlers.EmbeddingsUsage"
+                "run_id": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.EmbeddingsUsage": {
+        "handlers.memoryCompactPayload": {
             "type": "object",
             "properties": {
-                "duration": {
+                "decay_days": {
                     "type": "integer"
                 },
-                "image_tokens": {
-                    "type": "integer"
+                "dry_run": {
+                    "type": "boolean"
                 },
-                "input_tokens": {
-                    "type": "integer"
-                }
-            }
-        },
-        "handlers.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string"
+                "ratio": {
+                    "type": "number"
                 }
             }
         },
-        "handlers.FSDeleteRequest": {
+        "handlers.memoryDeletePayload": {
             "type": "object",
             "properties": {
-                "path": {
-                    "type": "string"
-                },
-                "recursive": {
-                    "type": "boolean"
+                "memory_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "handlers.FSFileInfo": {
+        "handlers.memorySearchPayload": {
             "type": "object",
             "properties": {
-                "isDir": {
+                "embedding_enabled": {
                     "type": "boolean"
                 },
-                "modTime": {
-                    "type": "string"
-                },
-                "mode": {
-                    "type": "string"
+                "filters": {
+                    "type": "object",
+                    "additionalProperties": {}
                 },
-                "name": {
-                    "type": "string"
+                "limit": {
+                    "type": "integer"
                 },
-                "path": {
-                    "type": "string"
+                "no_stats": {
+                    "type": "boolean"
                 },
-                "size": {
-                    "type": "integer"
-                }
-            }
-        },
-        "handlers.FSListResponse": {
-            "type": "object",
-            "properties": {
-                "entries": {
+                "query": {
+                    "type": "string"
+                },
+                "run_id": {
+                    "type": "string"
+                },
+                "sources": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handlers.FSFileInfo"
+                        "type": "string"
                     }
-                },
-                "path": {
-                    "type": "string"
                 }
             }
         },
-        "handlers.FSMkdirRequest": {
+        "handlers.skillsOpResponse": {
             "type": "object",
             "properties": {
-                "path": {
-                    "type": "string"
+                "ok": {
+                    "type": "boolean"
                 }
             }
         },
-        "handlers.FSReadResponse": {
+        "identities.ChannelIdentity": {
             "type": "object",
             "properties": {
-                "content": {
+                "avatar_url": {
                     "type": "string"
                 },
-                "path": {
+                "channel": {
                     "type": "string"
                 },
-                "size": {
-                    "type": "integer"
-                }
-            }
-        },
-        "handlers.FSRenameRequest": {
-            "type": "object",
-            "properties": {
-                "newPath": {
+                "channel_subject_id": {
                     "type": "string"
                 },
-                "oldPath": {
+                "created_at": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "handlers.FSUploadResponse": {
+        "inbox.CountResult": {
             "type": "object",
             "properties": {
-                "path": {
-                    "type": "string"
+                "total": {
+                    "type": "integer"
                 },
-                "size": {
+                "unread": {
                     "type": "integer"
                 }
             }
         },
-        "handlers.FSWriteRequest": {
+        "inbox.CreateRequest": {
             "type": "object",
             "properties": {
-                "content": {
+                "bot_id": {
                     "type": "string"
                 },
-                "path": {
+                "content": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "source": {
                     "type": "string"
                 }
             }
         },
-        "handlers.GetContainerResponse": {
+        "inbox.Item": {
             "type": "object",
             "properties": {
-                "container_id": {
+                "bot_id": {
                     "type": "string"
                 },
-                "container_path": {
-                    "type": "string"
+                "content": {
+                    "type": "object",
+                    "additionalProperties": {}
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "host_path": {
-                    "type": "string"
-                },
-                "image": {
+                "id": {
                     "type": "string"
                 },
-                "namespace": {
-                    "type": "string"
+                "is_read": {
+                    "type": "boolean"
                 },
-                "status": {
+                "read_at": {
                     "type": "string"
                 },
-                "task_running": {
-                    "type": "boolean"
-                },
-                "updated_at": {
+                "source": {
                     "type": "string"
                 }
             }
         },
-        "handlers.ListSnapshotsResponse": {
+        "mcp.ExportResponse": {
             "type": "object",
             "properties": {
-                "snapshots": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handlers.SnapshotInfo"
+                "mcpServers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/mcp.MCPServerEntry"
                     }
-                },
-                "snapshotter": {
-                    "type": "string"
                 }
             }
         },
-        "handlers.LocalChannelMessageRequest": {
+        "mcp.ImportRequest": {
             "type": "object",
             "properties": {
-                "message": {
-                    "$ref": "#/definitions/channel.Message"
+                "mcpServers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/mcp.MCPServerEntry"
+                    }
                 }
             }
         },
-        "handlers.LoginRequest": {
+        "mcp.ListResponse": {
             "type": "object",
             "properties": {
-                "password": {
-                    "type": "string"
-                },
-                "username": {
-                    "type": "string"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_memohai_memoh_internal_mcp.Connection"
+                    }
                 }
             }
         },
-        "handlers.LoginResponse": {
+        "mcp.MCPServerEntry": {
             "type": "object",
             "properties": {
-                "access_token": {
-                    "type": "string"
+                "args": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "display_name": {
+                "command": {
                     "type": "string"
                 },
-                "expires_at": {
+                "cwd": {
                     "type": "string"
                 },
-                "role": {
-                    "type": "string"
+                "env": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "token_type": {
-                    "type": "string"
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "user_id": {
+                "transport": {
                     "type": "string"
                 },
-                "username": {
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "handlers.MCPStdioRequest": {
+        "mcp.UpsertRequest": {
             "type": "object",
             "properties": {
                 "args": {
@@ -7093,274 +10022,400 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string"
+                },
+                "transport": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.MCPStdioResponse": {
+        "memory.ArchiveManifest": {
             "type": "object",
             "properties": {
-                "connection_id": {
+                "bot_id": {
                     "type": "string"
                 },
-                "tools": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+                "count": {
+                    "type": "integer"
+                },
+                "embedding_models": {
+                    "description": "EmbeddingModels lists the model IDs that produced the exported vectors,\nwith their dimensions.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
                     }
                 },
-                "url": {
+                "exported_at": {
+                    "type": "string"
+                },
+                "format": {
                     "type": "string"
+                },
+                "includes_vectors": {
+                    "type": "boolean"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers.PingResponse": {
+        "memory.CDFPoint": {
             "type": "object",
             "properties": {
-                "container_backend": {
-                    "type": "string"
-                },
-                "snapshot_supported": {
-                    "type": "boolean"
+                "cumulative": {
+                    "description": "cumulative weight fraction [0.0, 1.0]",
+                    "type": "number"
                 },
-                "status": {
-                    "type": "string"
+                "k": {
+                    "description": "rank position (1-based, sorted by value desc)",
+                    "type": "integer"
                 }
             }
         },
-        "handlers.SkillItem": {
+        "memory.CandidateMemory": {
             "type": "object",
             "properties": {
-                "content": {
+                "created_at": {
                     "type": "string"
                 },
-                "description": {
+                "id": {
+                    "type": "string"
+                },
+                "memory": {
                     "type": "string"
                 },
                 "metadata": {
                     "type": "object",
                     "additionalProperties": {}
-                },
-                "name": {
+                }
+            }
+        },
+        "memory.CompactMerge": {
+            "type": "object",
+            "properties": {
+                "memory": {
                     "type": "string"
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/memory.CandidateMemory"
+                    }
                 }
             }
         },
-        "handlers.SkillsDeleteRequest": {
+        "memory.CompactResult": {
             "type": "object",
             "properties": {
-                "names": {
+                "after_count": {
+                    "type": "integer"
+                },
+                "before_count": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "merges": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/memory.CompactMerge"
+                    }
+                },
+                "ratio": {
+                    "type": "number"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/memory.MemoryItem"
                     }
                 }
             }
         },
-        "handlers.SkillsResponse": {
+        "memory.DeleteResponse": {
             "type": "object",
             "properties": {
-                "skills": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handlers.SkillItem"
-                    }
+                "message": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.SkillsUpsertRequest": {
+        "memory.ImportResult": {
             "type": "object",
             "properties": {
-                "skills": {
+                "errors": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handlers.SkillItem"
+                        "type": "string"
                     }
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "manifest": {
+                    "$ref": "#/definitions/memory.ArchiveManifest"
+                },
+                "reembedded": {
+                    "type": "integer"
+                },
+                "reused_vectors": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers.SnapshotInfo": {
+        "memory.MemoryItem": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "agent_id": {
                     "type": "string"
                 },
-                "kind": {
+                "bot_id": {
                     "type": "string"
                 },
-                "labels": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
+                "cdf_curve": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/memory.CDFPoint"
                     }
                 },
-                "managed": {
-                    "type": "boolean"
+                "channel_identity_id": {
+                    "type": "string"
                 },
-                "name": {
+                "conversation_id": {
                     "type": "string"
                 },
-                "parent": {
+                "created_at": {
                     "type": "string"
                 },
-                "snapshotter": {
+                "expires_at": {
                     "type": "string"
                 },
-                "source": {
+                "hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "memory": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "rerank_score": {
+                    "type": "number"
+                },
+                "retrieval_score": {
+                    "type": "number"
+                },
+                "run_id": {
                     "type": "string"
                 },
+                "score": {
+                    "type": "number"
+                },
+                "top_k_buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/memory.TopKBucket"
+                    }
+                },
                 "updated_at": {
                     "type": "string"
                 },
-                "version": {
-                    "type": "integer"
-                }
-            }
-        },
-        "handlers.fsOpResponse": {
-            "type": "object",
-            "properties": {
-                "ok": {
-                    "type": "boolean"
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.listMyIdentitiesResponse": {
+        "memory.Message": {
             "type": "object",
             "properties": {
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/identities.ChannelIdentity"
-                    }
+                "content": {
+                    "type": "string"
                 },
-                "user_id": {
+                "role": {
                     "type": "string"
                 }
             }
         },
-        "handlers.markReadRequest": {
+        "memory.RebuildResult": {
             "type": "object",
             "properties": {
-                "ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "fs_count": {
+                    "type": "integer"
+                },
+                "missing_count": {
+                    "type": "integer"
+                },
+                "qdrant_count": {
+                    "type": "integer"
+                },
+                "restored_count": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers.memoryAddPayload": {
+        "memory.Revision": {
             "type": "object",
             "properties": {
-                "embedding_enabled": {
-                    "type": "boolean"
+                "action": {
+                    "$ref": "#/definitions/memory.RevisionAction"
                 },
-                "filters": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "bot_id": {
+                    "type": "string"
                 },
-                "infer": {
-                    "type": "boolean"
+                "created_at": {
+                    "type": "string"
                 },
-                "message": {
+                "id": {
                     "type": "string"
                 },
-                "messages": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/memory.Message"
-                    }
+                "memory_id": {
+                    "type": "string"
                 },
-                "metadata": {
+                "model": {
+                    "type": "string"
+                },
+                "new_text": {
+                    "type": "string"
+                },
+                "payload": {
                     "type": "object",
                     "additionalProperties": {}
                 },
-                "namespace": {
+                "previous_text": {
                     "type": "string"
                 },
-                "run_id": {
+                "restored_from": {
                     "type": "string"
+                },
+                "source_messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/memory.Message"
+                    }
                 }
             }
         },
-        "handlers.memoryCompactPayload": {
+        "memory.RevisionAction": {
+            "type": "string",
+            "enum": [
+                "ADD",
+                "UPDATE",
+                "DELETE",
+                "COMPACT",
+                "RESTORE",
+                "EXPIRE"
+            ],
+            "x-enum-varnames": [
+                "RevisionAdd",
+                "RevisionUpdate",
+                "RevisionDelete",
+                "RevisionCompact",
+                "RevisionRestore",
+                "RevisionExpire"
+            ]
+        },
+        "memory.RevisionListResponse": {
             "type": "object",
             "properties": {
-                "decay_days": {
-                    "type": "integer"
-                },
-                "ratio": {
-                    "type": "number"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/memory.Revision"
+                    }
                 }
             }
         },
-        "handlers.memoryDeletePayload": {
+        "memory.SearchResponse": {
             "type": "object",
             "properties": {
-                "memory_ids": {
+                "relations": {
+                    "type": "array",
+                    "items": {}
+                },
+                "results": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/memory.MemoryItem"
                     }
                 }
             }
         },
-        "handlers.memorySearchPayload": {
+        "memory.TopKBucket": {
             "type": "object",
             "properties": {
-                "embedding_enabled": {
-                    "type": "boolean"
-                },
-                "filters": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "limit": {
+                "index": {
+                    "description": "sparse dimension index (term hash)",
                     "type": "integer"
                 },
-                "no_stats": {
-                    "type": "boolean"
-                },
-                "query": {
-                    "type": "string"
-                },
-                "run_id": {
-                    "type": "string"
-                },
-                "sources": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "value": {
+                    "description": "weight (term frequency)",
+                    "type": "number"
                 }
             }
         },
-        "handlers.skillsOpResponse": {
+        "memory.UsageResponse": {
             "type": "object",
             "properties": {
-                "ok": {
-                    "type": "boolean"
+                "avg_text_bytes": {
+                    "type": "integer"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "estimated_storage_bytes": {
+                    "type": "integer"
+                },
+                "total_text_bytes": {
+                    "type": "integer"
                 }
             }
         },
-        "identities.ChannelIdentity": {
+        "message.Message": {
             "type": "object",
             "properties": {
-                "avatar_url": {
-                    "type": "string"
+                "assets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/message.MessageAsset"
+                    }
                 },
-                "channel": {
+                "bot_id": {
                     "type": "string"
                 },
-                "channel_subject_id": {
-                    "type": "string"
+                "content": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "display_name": {
+                "external_message_id": {
                     "type": "string"
                 },
                 "id": {
@@ -7370,803 +10425,857 @@ const docTemplate = `{
                     "type": "object",
                     "additionalProperties": {}
                 },
-                "updated_at": {
+                "platform": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "route_id": {
+                    "type": "string"
+                },
+                "sender_avatar_url": {
+                    "type": "string"
+                },
+                "sender_channel_identity_id": {
+                    "type": "string"
+                },
+                "sender_display_name": {
+                    "type": "string"
+                },
+                "sender_user_id": {
                     "type": "string"
                 },
-                "user_id": {
+                "source_reply_to_message_id": {
                     "type": "string"
+                },
+                "usage": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "inbox.CountResult": {
+        "message.MessageAsset": {
             "type": "object",
             "properties": {
-                "total": {
-                    "type": "integer"
+                "content_hash": {
+                    "type": "string"
                 },
-                "unread": {
+                "mime": {
+                    "type": "string"
+                },
+                "ordinal": {
                     "type": "integer"
-                }
-            }
-        },
-        "inbox.CreateRequest": {
-            "type": "object",
-            "properties": {
-                "bot_id": {
+                },
+                "role": {
                     "type": "string"
                 },
-                "content": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "size_bytes": {
+                    "type": "integer"
                 },
-                "source": {
+                "storage_key": {
                     "type": "string"
                 }
             }
         },
-        "inbox.Item": {
+        "models.AddRequest": {
             "type": "object",
             "properties": {
-                "bot_id": {
-                    "type": "string"
+                "client_type": {
+                    "$ref": "#/definitions/models.ClientType"
                 },
-                "content": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "dimensions": {
+                    "type": "integer"
                 },
-                "created_at": {
-                    "type": "string"
+                "input_modalities": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "id": {
+                "llm_provider_id": {
                     "type": "string"
                 },
-                "is_read": {
-                    "type": "boolean"
-                },
-                "read_at": {
+                "model_id": {
                     "type": "string"
                 },
-                "source": {
+                "name": {
                     "type": "string"
+                },
+                "supports_reasoning": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.ModelType"
                 }
             }
         },
-        "mcp.ExportResponse": {
+        "models.AddResponse": {
             "type": "object",
             "properties": {
-                "mcpServers": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/mcp.MCPServerEntry"
-                    }
+                "id": {
+                    "type": "string"
+                },
+                "model_id": {
+                    "type": "string"
                 }
             }
         },
-        "mcp.ImportRequest": {
-            "type": "object",
-            "properties": {
-                "mcpServers": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/mcp.MCPServerEntry"
-                    }
-                }
-            }
+        "models.ClientType": {
+            "type": "string",
+            "enum": [
+                "openai-responses",
+                "openai-completions",
+                "anthropic-messages",
+                "google-generative-ai"
+            ],
+            "x-enum-varnames": [
+                "ClientTypeOpenAIResponses",
+                "ClientTypeOpenAICompletions",
+                "ClientTypeAnthropicMessages",
+                "ClientTypeGoogleGenerativeAI"
+            ]
         },
-        "mcp.ListResponse": {
+        "models.CountResponse": {
             "type": "object",
             "properties": {
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/github_com_memohai_memoh_internal_mcp.Connection"
-                    }
+                "count": {
+                    "type": "integer"
                 }
             }
         },
-        "mcp.MCPServerEntry": {
+        "models.GetResponse": {
             "type": "object",
             "properties": {
-                "args": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "client_type": {
+                    "$ref": "#/definitions/models.ClientType"
                 },
-                "command": {
-                    "type": "string"
+                "dimensions": {
+                    "type": "integer"
                 },
-                "cwd": {
+                "id": {
                     "type": "string"
                 },
-                "env": {
-                    "type": "object",
-                    "additionalProperties": {
+                "input_modalities": {
+                    "type": "array",
+                    "items": {
                         "type": "string"
                     }
                 },
-                "headers": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
+                "llm_provider_id": {
+                    "type": "string"
                 },
-                "transport": {
+                "model_id": {
                     "type": "string"
                 },
-                "url": {
+                "name": {
                     "type": "string"
+                },
+                "supports_reasoning": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.ModelType"
                 }
             }
         },
-        "mcp.UpsertRequest": {
+        "models.ModelType": {
+            "type": "string",
+            "enum": [
+                "chat",
+                "embedding",
+                "rerank"
+            ],
+            "x-enum-varnames": [
+                "ModelTypeChat",
+                "ModelTypeEmbedding",
+                "ModelTypeRerank"
+            ]
+        },
+        "models.UpdateRequest": {
             "type": "object",
             "properties": {
-                "args": {
+                "client_type": {
+                    "$ref": "#/definitions/models.ClientType"
+                },
+                "dimensions": {
+                    "type": "integer"
+                },
+                "input_modalities": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "command": {
+                "llm_provider_id": {
                     "type": "string"
                 },
-                "cwd": {
+                "model_id": {
                     "type": "string"
                 },
-                "env": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
-                },
-                "headers": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
-                },
-                "is_active": {
-                    "type": "boolean"
-                },
                 "name": {
                     "type": "string"
                 },
-                "transport": {
-                    "type": "string"
+                "supports_reasoning": {
+                    "type": "boolean"
                 },
-                "url": {
-                    "type": "string"
+                "type": {
+                    "$ref": "#/definitions/models.ModelType"
                 }
             }
         },
-        "memory.CDFPoint": {
+        "providers.CheckResult": {
             "type": "object",
             "properties": {
-                "cumulative": {
-                    "description": "cumulative weight fraction [0.0, 1.0]",
-                    "type": "number"
+                "latency_ms": {
+                    "type": "integer"
                 },
-                "k": {
-                    "description": "rank position (1-based, sorted by value desc)",
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/providers.CheckStatus"
+                },
+                "status_code": {
                     "type": "integer"
                 }
             }
         },
-        "memory.CompactResult": {
+        "providers.CheckStatus": {
+            "type": "string",
+            "enum": [
+                "supported",
+                "auth_error",
+                "unsupported",
+                "error"
+            ],
+            "x-enum-varnames": [
+                "CheckStatusSupported",
+                "CheckStatusAuthError",
+                "CheckStatusUnsupported",
+                "CheckStatusError"
+            ]
+        },
+        "providers.CountResponse": {
             "type": "object",
             "properties": {
-                "after_count": {
-                    "type": "integer"
-                },
-                "before_count": {
+                "count": {
                     "type": "integer"
-                },
-                "ratio": {
-                    "type": "number"
-                },
-                "results": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/memory.MemoryItem"
-                    }
                 }
             }
         },
-        "memory.DeleteResponse": {
+        "providers.CreateRequest": {
             "type": "object",
+            "required": [
+                "base_url",
+                "name"
+            ],
             "properties": {
-                "message": {
+                "api_key": {
+                    "type": "string"
+                },
+                "base_url": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "memory.MemoryItem": {
+        "providers.GetResponse": {
             "type": "object",
             "properties": {
-                "agent_id": {
+                "api_key": {
+                    "description": "masked in response",
                     "type": "string"
                 },
-                "bot_id": {
+                "base_url": {
                     "type": "string"
                 },
-                "cdf_curve": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/memory.CDFPoint"
-                    }
-                },
                 "created_at": {
                     "type": "string"
                 },
-                "hash": {
-                    "type": "string"
-                },
                 "id": {
                     "type": "string"
                 },
-                "memory": {
-                    "type": "string"
-                },
                 "metadata": {
                     "type": "object",
                     "additionalProperties": {}
                 },
-                "run_id": {
+                "name": {
                     "type": "string"
                 },
-                "score": {
-                    "type": "number"
-                },
-                "top_k_buckets": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/memory.TopKBucket"
-                    }
-                },
                 "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "memory.Message": {
-            "type": "object",
-            "properties": {
-                "content": {
-                    "type": "string"
-                },
-                "role": {
-                    "type": "string"
-                }
-            }
-        },
-        "memory.RebuildResult": {
+        "providers.TestResponse": {
             "type": "object",
             "properties": {
-                "fs_count": {
-                    "type": "integer"
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/providers.CheckResult"
+                    }
                 },
-                "missing_count": {
+                "latency_ms": {
                     "type": "integer"
                 },
-                "qdrant_count": {
-                    "type": "integer"
+                "message": {
+                    "type": "string"
                 },
-                "restored_count": {
-                    "type": "integer"
+                "reachable": {
+                    "type": "boolean"
                 }
             }
         },
-        "memory.SearchResponse": {
+        "providers.UpdateRequest": {
             "type": "object",
             "properties": {
-                "relations": {
-                    "type": "array",
-                    "items": {}
+                "api_key": {
+                    "type": "string"
                 },
-                "results": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/memory.MemoryItem"
-                    }
-                }
-            }
-        },
-        "memory.TopKBucket": {
-            "type": "object",
-            "properties": {
-                "index": {
-                    "description": "sparse dimension index (term hash)",
-                    "type": "integer"
+                "base_url": {
+                    "type": "string"
                 },
-                "value": {
-                    "description": "weight (term frequency)",
-                    "type": "number"
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "name": {
+                    "type": "string"
                 }
             }
         },
-        "memory.UsageResponse": {
+        "schedule.CompactionRun": {
             "type": "object",
             "properties": {
-                "avg_text_bytes": {
-                    "type": "integer"
-                },
-                "count": {
-                    "type": "integer"
-                },
-                "estimated_storage_bytes": {
+                "after_count": {
                     "type": "integer"
                 },
-                "total_text_bytes": {
+                "before_count": {
                     "type": "integer"
-                }
-            }
-        },
-        "message.Message": {
-            "type": "object",
-            "properties": {
-                "assets": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/message.MessageAsset"
-                    }
                 },
                 "bot_id": {
                     "type": "string"
                 },
-                "content": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "decay_days": {
+                    "type": "integer"
                 },
-                "created_at": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "error": {
                     "type": "string"
                 },
-                "external_message_id": {
+                "finished_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "merges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/memory.CompactMerge"
+                    }
                 },
-                "platform": {
-                    "type": "string"
+                "ratio": {
+                    "type": "number"
                 },
-                "role": {
+                "started_at": {
                     "type": "string"
                 },
-                "route_id": {
+                "status": {
                     "type": "string"
                 },
-                "sender_avatar_url": {
+                "trigger": {
                     "type": "string"
-                },
-                "sender_channel_identity_id": {
+                }
+            }
+        },
+        "schedule.CompactionRunListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schedule.CompactionRun"
+                    }
+                }
+            }
+        },
+        "schedule.CreateRequest": {
+            "type": "object",
+            "properties": {
+                "command": {
                     "type": "string"
                 },
-                "sender_display_name": {
+                "description": {
                     "type": "string"
                 },
-                "sender_user_id": {
-                    "type": "string"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "source_reply_to_message_id": {
+                "max_calls": {
+                    "$ref": "#/definitions/schedule.NullableInt"
+                },
+                "name": {
                     "type": "string"
                 },
-                "usage": {
+                "pattern": {
+                    "type": "string"
+                }
+            }
+        },
+        "schedule.ListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/schedule.Schedule"
                     }
                 }
             }
         },
-        "message.MessageAsset": {
+        "schedule.NullableInt": {
             "type": "object",
             "properties": {
-                "content_hash": {
+                "set": {
+                    "type": "boolean"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "schedule.Schedule": {
+            "type": "object",
+            "properties": {
+                "bot_id": {
                     "type": "string"
                 },
-                "mime": {
+                "command": {
                     "type": "string"
                 },
-                "ordinal": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current_calls": {
                     "type": "integer"
                 },
-                "role": {
+                "description": {
                     "type": "string"
                 },
-                "size_bytes": {
-                    "type": "integer"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "storage_key": {
+                "id": {
                     "type": "string"
-                }
-            }
-        },
-        "models.AddRequest": {
-            "type": "object",
-            "properties": {
-                "client_type": {
-                    "$ref": "#/definitions/models.ClientType"
                 },
-                "dimensions": {
+                "max_calls": {
                     "type": "integer"
                 },
-                "input_modalities": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "name": {
+                    "type": "string"
                 },
-                "llm_provider_id": {
+                "pattern": {
                     "type": "string"
                 },
-                "model_id": {
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "schedule.UpdateRequest": {
+            "type": "object",
+            "properties": {
+                "command": {
                     "type": "string"
                 },
-                "name": {
+                "description": {
                     "type": "string"
                 },
-                "supports_reasoning": {
+                "enabled": {
                     "type": "boolean"
                 },
-                "type": {
-                    "$ref": "#/definitions/models.ModelType"
-                }
-            }
-        },
-        "models.AddResponse": {
-            "type": "object",
-            "properties": {
-                "id": {
+                "max_calls": {
+                    "$ref": "#/definitions/schedule.NullableInt"
+                },
+                "name": {
                     "type": "string"
                 },
-                "model_id": {
+                "pattern": {
                     "type": "string"
                 }
             }
         },
-        "models.ClientType": {
-            "type": "string",
-            "enum": [
-                "openai-responses",
-                "openai-completions",
-                "anthropic-messages",
-                "google-generative-ai"
-            ],
-            "x-enum-varnames": [
-                "ClientTypeOpenAIResponses",
-                "ClientTypeOpenAICompletions",
-                "ClientTypeAnthropicMessages",
-                "ClientTypeGoogleGenerativeAI"
-            ]
-        },
-        "models.CountResponse": {
+        "searchproviders.CreateRequest": {
             "type": "object",
             "properties": {
-                "count": {
-                    "type": "integer"
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "name": {
+                    "type": "string"
+                },
+                "provider": {
+                    "$ref": "#/definitions/searchproviders.ProviderName"
                 }
             }
         },
-        "models.GetResponse": {
+        "searchproviders.GetResponse": {
             "type": "object",
             "properties": {
-                "client_type": {
-                    "$ref": "#/definitions/models.ClientType"
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {}
                 },
-                "dimensions": {
-                    "type": "integer"
+                "created_at": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "input_modalities": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "llm_provider_id": {
+                "name": {
                     "type": "string"
                 },
-                "model_id": {
+                "provider": {
                     "type": "string"
                 },
-                "name": {
+                "updated_at": {
                     "type": "string"
-                },
-                "supports_reasoning": {
-                    "type": "boolean"
-                },
-                "type": {
-                    "$ref": "#/definitions/models.ModelType"
                 }
             }
         },
-        "models.ModelType": {
-            "type": "string",
-            "enum": [
-                "chat",
-                "embedding"
-            ],
-            "x-enum-varnames": [
-                "ModelTypeChat",
-                "ModelTypeEmbedding"
-            ]
+        "searchproviders.ProviderConfigSchema": {
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/searchproviders.ProviderFieldSchema"
+                    }
+                }
+            }
         },
-        "models.UpdateRequest": {
+        "searchproviders.ProviderFieldSchema": {
             "type": "object",
             "properties": {
-                "client_type": {
-                    "$ref": "#/definitions/models.ClientType"
-                },
-                "dimensions": {
-                    "type": "integer"
+                "description": {
+                    "type": "string"
                 },
-                "input_modalities": {
+                "enum": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "llm_provider_id": {
-                    "type": "string"
-                },
-                "model_id": {
-                    "type": "string"
+                "example": {},
+                "required": {
+                    "type": "boolean"
                 },
-                "name": {
+                "title": {
                     "type": "string"
                 },
-                "supports_reasoning": {
-                    "type": "boolean"
-                },
                 "type": {
-                    "$ref": "#/definitions/models.ModelType"
+                    "type": "string"
                 }
             }
         },
-        "providers.CheckResult": {
+        "searchproviders.ProviderMeta": {
             "type": "object",
             "properties": {
-                "latency_ms": {
-                    "type": "integer"
+                "config_schema": {
+                    "$ref": "#/definitions/searchproviders.ProviderConfigSchema"
                 },
-                "message": {
+                "display_name": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/providers.CheckStatus"
-                },
-                "status_code": {
-                    "type": "integer"
+                "provider": {
+                    "type": "string"
                 }
             }
         },
-        "providers.CheckStatus": {
+        "searchproviders.ProviderName": {
             "type": "string",
             "enum": [
-                "supported",
-                "auth_error",
-                "unsupported",
-                "error"
+                "brave",
+                "bing",
+                "google"
             ],
             "x-enum-varnames": [
-                "CheckStatusSupported",
-                "CheckStatusAuthError",
-                "CheckStatusUnsupported",
-                "CheckStatusError"
+                "ProviderBrave",
+                "ProviderBing",
+                "ProviderGoogle"
             ]
         },
-        "providers.CountResponse": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                }
-            }
-        },
-        "providers.CreateRequest": {
+        "searchproviders.UpdateRequest": {
             "type": "object",
-            "required": [
-                "base_url",
-                "name"
-            ],
             "properties": {
-                "api_key": {
-                    "type": "string"
-                },
-                "base_url": {
-                    "type": "string"
-                },
-                "metadata": {
+                "config": {
                     "type": "object",
                     "additionalProperties": {}
                 },
                 "name": {
                     "type": "string"
+                },
+                "provider": {
+                    "$ref": "#/definitions/searchproviders.ProviderName"
                 }
             }
         },
-        "providers.GetResponse": {
+        "settings.Settings": {
             "type": "object",
             "properties": {
-                "api_key": {
-                    "description": "masked in response",
+                "allow_guest": {
+                    "type": "boolean"
+                },
+                "chat_model_id": {
                     "type": "string"
                 },
-                "base_url": {
+                "compaction_cron": {
+                    "description": "CompactionCron schedules automatic memory compaction; empty disables it.",
                     "type": "string"
                 },
-                "created_at": {
+                "compaction_decay_days": {
+                    "type": "integer"
+                },
+                "compaction_max_memories": {
+                    "type": "integer"
+                },
+                "compaction_ratio": {
+                    "type": "number"
+                },
+                "container_cpu_limit": {
+                    "type": "number"
+                },
+                "container_cpu_shares": {
+                    "description": "Container resource limits take effect when the bot container restarts;\nzero means unlimited. ContainerCPULimit is in cores. ContainerDiskQuotaMB\napplies immediately to file manager uploads and the container file tools.",
+                    "type": "integer"
+                },
+                "container_disk_quota_mb": {
+                    "type": "integer"
+                },
+                "container_memory_mb": {
+                    "type": "integer"
+                },
+                "container_pids_limit": {
+                    "type": "integer"
+                },
+                "embedding_model_id": {
                     "type": "string"
                 },
-                "id": {
+                "language": {
                     "type": "string"
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "max_context_load_time": {
+                    "type": "integer"
                 },
-                "name": {
+                "max_context_tokens": {
+                    "type": "integer"
+                },
+                "max_inbox_items": {
+                    "type": "integer"
+                },
+                "memory_model_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "memory_scope": {
                     "type": "string"
-                }
-            }
-        },
-        "providers.TestResponse": {
-            "type": "object",
-            "properties": {
-                "checks": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/providers.CheckResult"
+                },
+                "network_allowlist": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 },
-                "latency_ms": {
-                    "type": "integer"
+                "network_mode": {
+                    "description": "NetworkMode is full, none or allowlist. In allowlist mode the container\nmay only reach the domains, IPs and CIDRs in NetworkAllowlist. Domains\nare resolved when the container starts.",
+                    "type": "string"
                 },
-                "message": {
+                "reasoning_effort": {
                     "type": "string"
                 },
-                "reachable": {
+                "reasoning_enabled": {
                     "type": "boolean"
-                }
-            }
-        },
-        "providers.UpdateRequest": {
-            "type": "object",
-            "properties": {
-                "api_key": {
+                },
+                "rerank_enabled": {
+                    "type": "boolean"
+                },
+                "rerank_model_id": {
                     "type": "string"
                 },
-                "base_url": {
+                "rerank_top_n": {
+                    "type": "integer"
+                },
+                "search_provider_id": {
                     "type": "string"
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "snapshot_before_risky": {
+                    "type": "boolean"
                 },
-                "name": {
+                "snapshot_cron": {
+                    "description": "SnapshotCron schedules automatic container snapshots; empty disables it.\nSnapshotBeforeRisky also snapshots before rollbacks, image changes and\nterminal sessions.\nRetention keeps the newest SnapshotKeepLast snapshots plus the newest one\nof each of the last SnapshotKeepDaily days; both zero keeps everything.",
                     "type": "string"
+                },
+                "snapshot_keep_daily": {
+                    "type": "integer"
+                },
+                "snapshot_keep_last": {
+                    "type": "integer"
                 }
             }
         },
-        "schedule.CreateRequest": {
+        "settings.UpsertRequest": {
             "type": "object",
             "properties": {
-                "command": {
+                "allow_guest": {
+                    "type": "boolean"
+                },
+                "chat_model_id": {
                     "type": "string"
                 },
-                "description": {
+                "compaction_cron": {
+                    "description": "CompactionCron accepts a cron pattern, or an empty string to disable scheduled compaction.",
                     "type": "string"
                 },
-                "enabled": {
-                    "type": "boolean"
+                "compaction_decay_days": {
+                    "type": "integer"
                 },
-                "max_calls": {
-                    "$ref": "#/definitions/schedule.NullableInt"
+                "compaction_max_memories": {
+                    "type": "integer"
                 },
-                "name": {
+                "compaction_ratio": {
+                    "type": "number"
+                },
+                "container_cpu_limit": {
+                    "type": "number"
+                },
+                "container_cpu_shares": {
+                    "description": "Container resource limits; 0 removes a limit.",
+                    "type": "integer"
+                },
+                "container_disk_quota_mb": {
+                    "type": "integer"
+                },
+                "container_memory_mb": {
+                    "type": "integer"
+                },
+                "container_pids_limit": {
+                    "type": "integer"
+                },
+                "embedding_model_id": {
                     "type": "string"
                 },
-                "pattern": {
+                "language": {
                     "type": "string"
-                }
-            }
-        },
-        "schedule.ListResponse": {
-            "type": "object",
-            "properties": {
-                "items": {
+                },
+                "max_context_load_time": {
+                    "type": "integer"
+                },
+                "max_context_tokens": {
+                    "type": "integer"
+                },
+                "max_inbox_items": {
+                    "type": "integer"
+                },
+                "memory_model_id": {
+                    "type": "string"
+                },
+                "memory_scope": {
+                    "type": "string"
+                },
+                "network_allowlist": {
+                    "description": "NetworkAllowlist replaces the allowlist when present; an empty list clears it.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/schedule.Schedule"
+                        "type": "string"
                     }
-                }
-            }
-        },
-        "schedule.NullableInt": {
-            "type": "object",
-            "properties": {
-                "set": {
-                    "type": "boolean"
                 },
-                "value": {
-                    "type": "integer"
-                }
-            }
-        },
-        "schedule.Schedule": {
-            "type": "object",
-            "properties": {
-                "bot_id": {
+                "network_mode": {
                     "type": "string"
                 },
-                "command": {
+                "reasoning_effort": {
                     "type": "string"
                 },
-                "created_at": {
+                "reasoning_enabled": {
+                    "type": "boolean"
+                },
+                "rerank_enabled": {
+                    "type": "boolean"
+                },
+                "rerank_model_id": {
                     "type": "string"
                 },
-                "current_calls": {
+                "rerank_top_n": {
                     "type": "integer"
                 },
-                "description": {
+                "search_provider_id": {
                     "type": "string"
                 },
-                "enabled": {
+                "snapshot_before_risky": {
                     "type": "boolean"
                 },
-                "id": {
+                "snapshot_cron": {
+                    "description": "SnapshotCron accepts a cron pattern, or an empty string to disable scheduled snapshots.",
                     "type": "string"
                 },
-                "max_calls": {
+                "snapshot_keep_daily": {
                     "type": "integer"
                 },
-                "name": {
-                    "type": "string"
-                },
-                "pattern": {
+                "snapshot_keep_last": {
+                    "type": "integer"
+                }
+            }
+        },
+        "storageproviders.BindingRequest": {
+            "type": "object",
+            "properties": {
+                "base_path": {
                     "type": "string"
                 },
-                "updated_at": {
+                "storage_provider_id": {
                     "type": "string"
                 }
             }
         },
-        "schedule.UpdateRequest": {
+        "storageproviders.BindingResponse": {
             "type": "object",
             "properties": {
-                "command": {
+                "base_path": {
                     "type": "string"
                 },
-                "description": {
+                "bot_id": {
                     "type": "string"
                 },
-                "enabled": {
-                    "type": "boolean"
+                "created_at": {
+                    "type": "string"
                 },
-                "max_calls": {
-                    "$ref": "#/definitions/schedule.NullableInt"
+                "provider": {
+                    "type": "string"
                 },
-                "name": {
+                "provider_name": {
                     "type": "string"
                 },
-                "pattern": {
+                "storage_provider_id": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "searchproviders.CreateRequest": {
+        "storageproviders.CreateRequest": {
             "type": "object",
             "properties": {
                 "config": {
@@ -8177,11 +11286,11 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "provider": {
-                    "$ref": "#/definitions/searchproviders.ProviderName"
+                    "$ref": "#/definitions/storageproviders.ProviderName"
                 }
             }
         },
-        "searchproviders.GetResponse": {
+        "storageproviders.GetResponse": {
             "type": "object",
             "properties": {
                 "config": {
@@ -8205,18 +11314,92 @@ const docTemplate = `{
                 }
             }
         },
-        "searchproviders.ProviderConfigSchema": {
+        "storageproviders.MigrateRequest": {
+            "type": "object",
+            "properties": {
+                "base_path": {
+                    "type": "string"
+                },
+                "delete_source": {
+                    "type": "boolean"
+                },
+                "storage_provider_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "storageproviders.MigrationState": {
+            "type": "string",
+            "enum": [
+                "running",
+                "completed",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "MigrationRunning",
+                "MigrationCompleted",
+                "MigrationFailed"
+            ]
+        },
+        "storageproviders.MigrationStatus": {
+            "type": "object",
+            "properties": {
+                "base_path": {
+                    "type": "string"
+                },
+                "bot_id": {
+                    "type": "string"
+                },
+                "copied": {
+                    "type": "integer"
+                },
+                "delete_source": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "missing": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "$ref": "#/definitions/storageproviders.MigrationState"
+                },
+                "storage_provider_id": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "storageproviders.ProviderConfigSchema": {
             "type": "object",
             "properties": {
                 "fields": {
                     "type": "object",
                     "additionalProperties": {
-                        "$ref": "#/definitions/searchproviders.ProviderFieldSchema"
+                        "$ref": "#/definitions/storageproviders.ProviderFieldSchema"
                     }
                 }
             }
         },
-        "searchproviders.ProviderFieldSchema": {
+        "storageproviders.ProviderFieldSchema": {
             "type": "object",
             "properties": {
                 "description": {
@@ -8240,11 +11423,11 @@ const docTemplate = `{
                 }
             }
         },
-        "searchproviders.ProviderMeta": {
+        "storageproviders.ProviderMeta": {
             "type": "object",
             "properties": {
                 "config_schema": {
-                    "$ref": "#/definitions/searchproviders.ProviderConfigSchema"
+                    "$ref": "#/definitions/storageproviders.ProviderConfigSchema"
                 },
                 "display_name": {
                     "type": "string"
@@ -8254,20 +11437,18 @@ const docTemplate = `{
                 }
             }
         },
-        "searchproviders.ProviderName": {
+        "storageproviders.ProviderName": {
             "type": "string",
             "enum": [
-                "brave",
-                "bing",
-                "google"
+                "localfs",
+                "s3"
             ],
             "x-enum-varnames": [
-                "ProviderBrave",
-                "ProviderBing",
-                "ProviderGoogle"
+                "ProviderLocalFS",
+                "ProviderS3"
             ]
         },
-        "searchproviders.UpdateRequest": {
+        "storageproviders.UpdateRequest": {
             "type": "object",
             "properties": {
                 "config": {
@@ -8278,83 +11459,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "provider": {
-                    "$ref": "#/definitions/searchproviders.ProviderName"
-                }
-            }
-        },
-        "settings.Settings": {
-            "type": "object",
-            "properties": {
-                "allow_guest": {
-                    "type": "boolean"
-                },
-                "chat_model_id": {
-                    "type": "string"
-                },
-                "embedding_model_id": {
-                    "type": "string"
-                },
-                "language": {
-                    "type": "string"
-                },
-                "max_context_load_time": {
-                    "type": "integer"
-                },
-                "max_context_tokens": {
-                    "type": "integer"
-                },
-                "max_inbox_items": {
-                    "type": "integer"
-                },
-                "memory_model_id": {
-                    "type": "string"
-                },
-                "reasoning_effort": {
-                    "type": "string"
-                },
-                "reasoning_enabled": {
-                    "type": "boolean"
-                },
-                "search_provider_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "settings.UpsertRequest": {
-            "type": "object",
-            "properties": {
-                "allow_guest": {
-                    "type": "boolean"
-                },
-                "chat_model_id": {
-                    "type": "string"
-                },
-                "embedding_model_id": {
-                    "type": "string"
-                },
-                "language": {
-                    "type": "string"
-                },
-                "max_context_load_time": {
-                    "type": "integer"
-                },
-                "max_context_tokens": {
-                    "type": "integer"
-                },
-                "max_inbox_items": {
-                    "type": "integer"
-                },
-                "memory_model_id": {
-                    "type": "string"
-                },
-                "reasoning_effort": {
-                    "type": "string"
-                },
-                "reasoning_enabled": {
-                    "type": "boolean"
-                },
-                "search_provider_id": {
-                    "type": "string"
+                    "$ref": "#/definitions/storageproviders.ProviderName"
                 }
             }
         },